@@ -0,0 +1,280 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+const (
+	// apiPath is the path of the REST API on a Bitbucket Server instance.
+	apiPath = "/rest/api/1.0"
+)
+
+// ClientOption is the interface to implement for passing options to NewClient.
+// The clientOptions struct is private to force usage of the With... functions.
+type ClientOption interface {
+	// ApplyToBitbucketClientOptions applies set fields of this object into target.
+	ApplyToBitbucketClientOptions(target *clientOptions) error
+}
+
+// clientOptions is the struct that tracks data about what options have been set.
+type clientOptions struct {
+	// clientOptions shares all the common options
+	gitprovider.CommonClientOptions
+
+	// AuthTransport is a ChainableRoundTripperFunc adding authentication credentials to the transport chain.
+	AuthTransport gitprovider.ChainableRoundTripperFunc
+}
+
+// ApplyToBitbucketClientOptions implements ClientOption, and applies the set fields of opts
+// into target. If both opts and target has the same specific field set, ErrInvalidClientOptions is returned.
+func (opts *clientOptions) ApplyToBitbucketClientOptions(target *clientOptions) error {
+	// Apply common values, if any
+	if err := opts.CommonClientOptions.ApplyToCommonClientOptions(&target.CommonClientOptions); err != nil {
+		return err
+	}
+
+	if opts.AuthTransport != nil {
+		// Make sure the user didn't specify the AuthTransport twice
+		if target.AuthTransport != nil {
+			return fmt.Errorf("option AuthTransport already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.AuthTransport = opts.AuthTransport
+	}
+	return nil
+}
+
+// getTransportChain builds the full chain of transports (from left to right,
+// as per gitprovider.BuildClientFromTransportChain) of the form described in NewClient.
+func (opts *clientOptions) getTransportChain() (chain []gitprovider.ChainableRoundTripperFunc) {
+	if opts.PostChainTransportHook != nil {
+		chain = append(chain, opts.PostChainTransportHook)
+	}
+	if userAgentTransport := opts.UserAgentTransport(); userAgentTransport != nil {
+		chain = append(chain, userAgentTransport)
+	}
+	if requestTimeoutTransport := opts.RequestTimeoutTransport(); requestTimeoutTransport != nil {
+		chain = append(chain, requestTimeoutTransport)
+	}
+	if opts.AuthTransport != nil {
+		chain = append(chain, opts.AuthTransport)
+	}
+	if opts.PreChainTransportHook != nil {
+		chain = append(chain, opts.PreChainTransportHook)
+	}
+	return
+}
+
+// buildCommonOption is a helper for returning a ClientOption out of a common option field.
+func buildCommonOption(opt gitprovider.CommonClientOptions) *clientOptions {
+	return &clientOptions{CommonClientOptions: opt}
+}
+
+// errorOption implements ClientOption, and just wraps an error which is immediately returned.
+// This struct can be used through the optionError function, in order to make makeOptions fail
+// if there are invalid options given to the With... functions.
+type errorOption struct {
+	err error
+}
+
+// ApplyToBitbucketClientOptions implements ClientOption, but just returns the internal error.
+func (e *errorOption) ApplyToBitbucketClientOptions(*clientOptions) error { return e.err }
+
+// optionError is a constructor for errorOption.
+func optionError(err error) ClientOption {
+	return &errorOption{err}
+}
+
+//
+// Common options
+//
+
+// WithDomain initializes a Client for the Bitbucket Server instance of the given domain.
+// domain consists of the host, an optional port, and the context path the instance is installed
+// under, if any, e.g. "bitbucket.example.com:7990" or "bitbucket.example.com/stash". The base URL of
+// the API is accepted too. domain must not be an empty string. See gitprovider.NormalizeBaseURL.
+func WithDomain(domain string) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{Domain: &domain})
+}
+
+// WithDefaultOrganization makes repository operations given a ref without an organization, i.e.
+// only a repository name, use the given organization. The domain of org must match the client's.
+func WithDefaultOrganization(org gitprovider.OrganizationRef) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{DefaultOrganization: &org})
+}
+
+// WithDestructiveAPICalls tells the client whether it's allowed to do dangerous and possibly destructive
+// actions, like e.g. deleting a repository.
+func WithDestructiveAPICalls(destructiveActions bool) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{EnableDestructiveAPICalls: &destructiveActions})
+}
+
+// WithPreChainTransportHook registers a ChainableRoundTripperFunc "before" the authentication
+// transport in the chain. For more information, see NewClient, and gitprovider.CommonClientOptions.PreChainTransportHook.
+func WithPreChainTransportHook(preRoundTripperFunc gitprovider.ChainableRoundTripperFunc) ClientOption {
+	// Don't allow an empty value
+	if preRoundTripperFunc == nil {
+		return optionError(fmt.Errorf("preRoundTripperFunc cannot be nil: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return buildCommonOption(gitprovider.CommonClientOptions{PreChainTransportHook: preRoundTripperFunc})
+}
+
+// WithPostChainTransportHook registers a ChainableRoundTripperFunc "after" the authentication
+// transport in the chain. For more information, see NewClient, and gitprovider.CommonClientOptions.WithPostChainTransportHook.
+func WithPostChainTransportHook(postRoundTripperFunc gitprovider.ChainableRoundTripperFunc) ClientOption {
+	// Don't allow an empty value
+	if postRoundTripperFunc == nil {
+		return optionError(fmt.Errorf("postRoundTripperFunc cannot be nil: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return buildCommonOption(gitprovider.CommonClientOptions{PostChainTransportHook: postRoundTripperFunc})
+}
+
+// WithRequestTimeout limits every single request to Bitbucket Server to the given duration, including reading
+// the response body, e.g. so that a hung connection can't block a reconcile forever. A shorter
+// deadline of the caller's context is kept. d must be positive. See gitprovider.RequestTimeoutTransport.
+func WithRequestTimeout(d time.Duration) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{RequestTimeout: &d})
+}
+
+// WithUserAgent sets the User-Agent header of every request to Bitbucket Server to userAgent, e.g. so that
+// the administrators of a self-hosted instance can identify the client's traffic in their logs.
+// userAgent must not be an empty string. See gitprovider.UserAgentTransport.
+func WithUserAgent(userAgent string) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{UserAgent: &userAgent})
+}
+
+//
+// Bitbucket-specific options
+//
+
+// WithPersonalAccessToken initializes a Client which authenticates with Bitbucket Server through a
+// personal (or HTTP) access token, sent as a bearer token. token must not be an empty string.
+func WithPersonalAccessToken(token string) ClientOption {
+	// Don't allow an empty value
+	if len(token) == 0 {
+		return optionError(fmt.Errorf("token cannot be empty: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return &clientOptions{AuthTransport: bearerTokenTransport(token)}
+}
+
+func bearerTokenTransport(token string) gitprovider.ChainableRoundTripperFunc {
+	return func(in http.RoundTripper) http.RoundTripper {
+		if in == nil {
+			in = http.DefaultTransport
+		}
+		return &bearerTokenRoundTripper{transport: in, token: token}
+	}
+}
+
+// bearerTokenRoundTripper sets the given token in the Authorization header of all requests.
+type bearerTokenRoundTripper struct {
+	transport http.RoundTripper
+	token     string
+}
+
+// RoundTrip sets the Authorization header on a clone of the request, as RoundTrippers must
+// not modify the given request.
+func (t *bearerTokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.transport.RoundTrip(req)
+}
+
+// makeOptions assembles a clientOptions struct from ClientOption mutator functions.
+func makeOptions(opts ...ClientOption) (*clientOptions, error) {
+	o := &clientOptions{}
+	for _, opt := range opts {
+		if err := opt.ApplyToBitbucketClientOptions(o); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// NewClient creates a new gitprovider.Client instance for Bitbucket Server API endpoints.
+//
+// Bitbucket Server is always self-hosted, hence the domain of the instance must be given using
+// WithDomain. The REST API is expected to be served over HTTPS at "https://{domain}/rest/api/1.0/".
+// The domain is normalized, e.g. "https://bitbucket.example.com/stash/rest/api/1.0" becomes
+// "bitbucket.example.com/stash", which is then the SupportedDomain of the client.
+//
+// Using WithPersonalAccessToken you can specify authentication
+// credentials, passing no such ClientOption will allow anonymous access only.
+//
+// You can customize low-level HTTP Transport functionality by using the With{Pre,Post}ChainTransportHook options.
+//
+// The chain of transports looks like this:
+// Bitbucket Server API <-> "Post Chain" <-> User Agent <-> Request Timeout <-> Authentication <-> "Pre Chain" <-> *http.Client.
+func NewClient(optFns ...ClientOption) (gitprovider.Client, error) {
+	// Complete the options struct
+	opts, err := makeOptions(optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	// There is no default domain, as Bitbucket Server is always self-hosted
+	if opts.Domain == nil {
+		return nil, fmt.Errorf("option Domain is required for Bitbucket Server: %w", gitprovider.ErrInvalidClientOptions)
+	}
+	domain, baseURL, err := baseURLForDomain(*opts.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a *http.Client using the transport chain
+	httpClient, err := gitprovider.BuildClientFromTransportChain(opts.getTransportChain())
+	if err != nil {
+		return nil, err
+	}
+
+	// By default, turn destructive actions off. But allow overrides.
+	destructiveActions := false
+	if opts.EnableDestructiveAPICalls != nil {
+		destructiveActions = *opts.EnableDestructiveAPICalls
+	}
+
+	// Make sure the default organization, if set, is in the domain of the client
+	if opts.DefaultOrganization != nil && opts.DefaultOrganization.Domain != domain {
+		return nil, fmt.Errorf("default organization domain %q doesn't match the client domain %q: %w",
+			opts.DefaultOrganization.Domain, domain, gitprovider.ErrInvalidClientOptions)
+	}
+
+	return newClient(httpClient, baseURL, domain, destructiveActions, opts.DefaultOrganization), nil
+}
+
+// baseURLForDomain returns the normalized domain and the REST API base URL of the Bitbucket Server
+// instance at domain, see gitprovider.NormalizeBaseURL.
+func baseURLForDomain(domain string) (string, *url.URL, error) {
+	baseURL, err := gitprovider.NormalizeBaseURL(domain, apiPath)
+	if err != nil {
+		return "", nil, err
+	}
+	apiURL, err := url.Parse(baseURL.URL(apiPath + "/"))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid domain %q: %v: %w", domain, err, gitprovider.ErrInvalidClientOptions)
+	}
+	return baseURL.Domain(), apiURL, nil
+}
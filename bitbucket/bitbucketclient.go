@@ -0,0 +1,332 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// bitbucketClientImpl is a wrapper around the Bitbucket Server REST API, which implements
+// higher-level methods, operating on the Project and Repository structs. Pagination is implemented
+// for all List* methods, all returned objects are validated, and HTTP errors are handled/wrapped
+// using handleHTTPError.
+// This interface is also fakeable, in order to unit-test the client.
+type bitbucketClient interface {
+	// Client returns the underlying *http.Client
+	Client() *http.Client
+
+	// GetProject is a wrapper for "GET /projects/{projectKey}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetProject(ctx context.Context, projectKey string) (*Project, error)
+	// ListProjects is a wrapper for "GET /projects".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListProjects(ctx context.Context) ([]*Project, error)
+
+	// GetRepo is a wrapper for "GET /projects/{projectKey}/repos/{repositorySlug}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetRepo(ctx context.Context, projectKey, repoSlug string) (*Repository, error)
+	// ListRepos is a wrapper for "GET /projects/{projectKey}/repos".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListRepos(ctx context.Context, projectKey string) ([]*Repository, error)
+	// ListReposPage is a wrapper for one page of "GET /projects/{projectKey}/repos".
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListReposPage(ctx context.Context, projectKey string, opts gitprovider.PageOptions) ([]*Repository, gitprovider.PageInfo, error)
+	// CreateRepo is a wrapper for "POST /projects/{projectKey}/repos".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateRepo(ctx context.Context, projectKey string, req *Repository) (*Repository, error)
+	// UpdateRepo is a wrapper for "PUT /projects/{projectKey}/repos/{repositorySlug}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateRepo(ctx context.Context, projectKey, repoSlug string, req *Repository) (*Repository, error)
+	// DeleteRepo is a wrapper for "DELETE /projects/{projectKey}/repos/{repositorySlug}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteRepo(ctx context.Context, projectKey, repoSlug string) error
+
+	// GetDefaultBranch is a wrapper for "GET /projects/{projectKey}/repos/{repositorySlug}/default-branch".
+	// The display name of the branch, e.g. "main", is returned.
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetDefaultBranch(ctx context.Context, projectKey, repoSlug string) (string, error)
+	// SetDefaultBranch is a wrapper for "PUT /projects/{projectKey}/repos/{repositorySlug}/default-branch".
+	// This function handles HTTP error wrapping.
+	SetDefaultBranch(ctx context.Context, projectKey, repoSlug, branch string) error
+}
+
+// bitbucketClientImpl is a wrapper around the Bitbucket Server REST API at baseURL.
+type bitbucketClientImpl struct {
+	c                  *http.Client
+	baseURL            *url.URL
+	destructiveActions bool
+}
+
+// bitbucketClientImpl implements bitbucketClient.
+var _ bitbucketClient = &bitbucketClientImpl{}
+
+func (c *bitbucketClientImpl) Client() *http.Client {
+	return c.c
+}
+
+func (c *bitbucketClientImpl) GetProject(ctx context.Context, projectKey string) (*Project, error) {
+	// GET /projects/{projectKey}
+	apiObj := &Project{}
+	if err := c.do(ctx, http.MethodGet, projectPath(projectKey), nil, nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateProjectAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *bitbucketClientImpl) ListProjects(ctx context.Context) ([]*Project, error) {
+	apiObjs := []*Project{}
+	opts := &listOptions{}
+	err := allPages(opts, func() (*pagedResponse, error) {
+		// GET /projects
+		page := &projectPage{}
+		if err := c.do(ctx, http.MethodGet, "projects", opts.query(), nil, page); err != nil {
+			return nil, err
+		}
+		apiObjs = append(apiObjs, page.Values...)
+		return &page.pagedResponse, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validateProjectAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *bitbucketClientImpl) GetRepo(ctx context.Context, projectKey, repoSlug string) (*Repository, error) {
+	// GET /projects/{projectKey}/repos/{repositorySlug}
+	apiObj := &Repository{}
+	if err := c.do(ctx, http.MethodGet, repoPath(projectKey, repoSlug), nil, nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateRepositoryAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *bitbucketClientImpl) ListRepos(ctx context.Context, projectKey string) ([]*Repository, error) {
+	apiObjs := []*Repository{}
+	opts := &listOptions{}
+	err := allPages(opts, func() (*pagedResponse, error) {
+		// GET /projects/{projectKey}/repos
+		page := &repositoryPage{}
+		if err := c.do(ctx, http.MethodGet, path.Join(projectPath(projectKey), "repos"), opts.query(), nil, page); err != nil {
+			return nil, err
+		}
+		apiObjs = append(apiObjs, page.Values...)
+		return &page.pagedResponse, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validateRepositoryAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *bitbucketClientImpl) ListReposPage(ctx context.Context, projectKey string, opts gitprovider.PageOptions) ([]*Repository, gitprovider.PageInfo, error) {
+	listOpts := pageListOptions(opts)
+	// GET /projects/{projectKey}/repos
+	page := &repositoryPage{}
+	if err := c.do(ctx, http.MethodGet, path.Join(projectPath(projectKey), "repos"), listOpts.query(), nil, page); err != nil {
+		return nil, gitprovider.PageInfo{}, handleHTTPError(err)
+	}
+
+	// Validate the API objects
+	for _, apiObj := range page.Values {
+		if err := validateRepositoryAPI(apiObj); err != nil {
+			return nil, gitprovider.PageInfo{}, err
+		}
+	}
+	return page.Values, pageInfoFromResponse(opts.Page, &page.pagedResponse), nil
+}
+
+func (c *bitbucketClientImpl) CreateRepo(ctx context.Context, projectKey string, req *Repository) (*Repository, error) {
+	// POST /projects/{projectKey}/repos
+	apiObj := &Repository{}
+	if err := c.do(ctx, http.MethodPost, path.Join(projectPath(projectKey), "repos"), nil, req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateRepositoryAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *bitbucketClientImpl) UpdateRepo(ctx context.Context, projectKey, repoSlug string, req *Repository) (*Repository, error) {
+	// PUT /projects/{projectKey}/repos/{repositorySlug}
+	apiObj := &Repository{}
+	if err := c.do(ctx, http.MethodPut, repoPath(projectKey, repoSlug), nil, req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateRepositoryAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *bitbucketClientImpl) DeleteRepo(ctx context.Context, projectKey, repoSlug string) error {
+	// Don't allow deleting repositories if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete repository: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /projects/{projectKey}/repos/{repositorySlug}
+	return handleHTTPError(c.do(ctx, http.MethodDelete, repoPath(projectKey, repoSlug), nil, nil, nil))
+}
+
+func (c *bitbucketClientImpl) GetDefaultBranch(ctx context.Context, projectKey, repoSlug string) (string, error) {
+	// GET /projects/{projectKey}/repos/{repositorySlug}/default-branch
+	apiObj := &branchRef{}
+	if err := c.do(ctx, http.MethodGet, path.Join(repoPath(projectKey, repoSlug), "default-branch"), nil, nil, apiObj); err != nil {
+		return "", handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateBranchRefAPI(apiObj); err != nil {
+		return "", err
+	}
+	return apiObj.DisplayID, nil
+}
+
+func (c *bitbucketClientImpl) SetDefaultBranch(ctx context.Context, projectKey, repoSlug, branch string) error {
+	// PUT /projects/{projectKey}/repos/{repositorySlug}/default-branch
+	req := &branchRef{ID: branchRefPrefix + branch}
+	return handleHTTPError(c.do(ctx, http.MethodPut, path.Join(repoPath(projectKey, repoSlug), "default-branch"), nil, req, nil))
+}
+
+// do sends a request with the JSON-encoded body (if non-nil) to the API path relative to the
+// base URL, and decodes the JSON response into v (if non-nil). If the server responds with an
+// unsuccessful status code, an *errorResponse is returned, which should be given to handleHTTPError.
+func (c *bitbucketClientImpl) do(ctx context.Context, method, apiPath string, query url.Values, body, v interface{}) error {
+	u := c.baseURL.ResolveReference(&url.URL{Path: apiPath, RawQuery: query.Encode()})
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		errResp := &errorResponse{Response: resp}
+		// The error details are best-effort, the status code is enough to handle the error
+		if data, err := ioutil.ReadAll(resp.Body); err == nil {
+			_ = json.Unmarshal(data, errResp)
+		}
+		return errResp
+	}
+	if v == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// projectPath returns the API path of the project with the given key.
+func projectPath(projectKey string) string {
+	return path.Join("projects", projectKey)
+}
+
+// repoPath returns the API path of the repository with the given slug in the given project.
+func repoPath(projectKey, repoSlug string) string {
+	return path.Join(projectPath(projectKey), "repos", repoSlug)
+}
+
+// listOptions specifies the page to request from paged Bitbucket Server APIs.
+type listOptions struct {
+	// Start is the index of the first item to return.
+	Start int
+	// Limit is the maximum amount of items to return, or 0 for the server default.
+	Limit int
+}
+
+// query returns the query parameters for the options.
+func (opts *listOptions) query() url.Values {
+	query := url.Values{}
+	if opts.Start != 0 {
+		query.Set("start", strconv.Itoa(opts.Start))
+	}
+	if opts.Limit != 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	return query
+}
+
+// pagedResponse is the pagination metadata of responses from paged Bitbucket Server APIs.
+type pagedResponse struct {
+	Size          int  `json:"size"`
+	Limit         int  `json:"limit"`
+	Start         int  `json:"start"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+// projectPage is a page of projects.
+type projectPage struct {
+	pagedResponse
+	Values []*Project `json:"values"`
+}
+
+// repositoryPage is a page of repositories.
+type repositoryPage struct {
+	pagedResponse
+	Values []*Repository `json:"values"`
+}
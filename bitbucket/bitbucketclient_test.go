@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// newTestClient returns a bitbucketClientImpl talking to a test server serving handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc, destructiveActions bool) *bitbucketClientImpl {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	baseURL, err := url.Parse(srv.URL + apiPath + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &bitbucketClientImpl{srv.Client(), baseURL, destructiveActions}
+}
+
+func Test_bitbucketClientImpl_ListRepos(t *testing.T) {
+	var starts []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/~alice/repos" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		start := r.URL.Query().Get("start")
+		starts = append(starts, start)
+		if start == "" {
+			fmt.Fprint(w, `{"isLastPage":false,"nextPageStart":1,"values":[{"slug":"foo","name":"Foo"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"isLastPage":true,"values":[{"slug":"bar","name":"Bar"}]}`)
+	}, false)
+
+	repos, err := c.ListRepos(context.Background(), "~alice")
+	if err != nil {
+		t.Fatalf("ListRepos() error = %v", err)
+	}
+	if len(repos) != 2 || repos[0].Slug != "foo" || repos[1].Slug != "bar" {
+		t.Errorf("ListRepos() = %v, want repositories foo and bar", repos)
+	}
+	if want := []string{"", "1"}; !reflect.DeepEqual(starts, want) {
+		t.Errorf("ListRepos() requested starts %v, want %v", starts, want)
+	}
+}
+
+func Test_bitbucketClientImpl_errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		call         func(c *bitbucketClientImpl) error
+		expectedErrs []error
+	}{
+		{
+			name:   "not found",
+			status: http.StatusNotFound,
+			body:   `{"errors":[{"message":"Repository PRJ/foo does not exist."}]}`,
+			call: func(c *bitbucketClientImpl) error {
+				_, err := c.GetRepo(context.Background(), "PRJ", "foo")
+				return err
+			},
+			expectedErrs: []error{gitprovider.ErrNotFound},
+		},
+		{
+			name:   "already exists",
+			status: http.StatusConflict,
+			body:   `{"errors":[{"exceptionName":"com.atlassian.bitbucket.repository.DuplicateRepositoryNameException"}]}`,
+			call: func(c *bitbucketClientImpl) error {
+				_, err := c.CreateRepo(context.Background(), "PRJ", &Repository{Name: "foo"})
+				return err
+			},
+			expectedErrs: []error{gitprovider.ErrAlreadyExists},
+		},
+		{
+			name:   "invalid credentials",
+			status: http.StatusUnauthorized,
+			call: func(c *bitbucketClientImpl) error {
+				_, err := c.ListProjects(context.Background())
+				return err
+			},
+			expectedErrs: []error{&gitprovider.InvalidCredentialsError{}},
+		},
+		{
+			name:   "invalid server data",
+			status: http.StatusOK,
+			body:   `{"name":"Foo"}`,
+			call: func(c *bitbucketClientImpl) error {
+				_, err := c.GetRepo(context.Background(), "PRJ", "foo")
+				return err
+			},
+			expectedErrs: []error{gitprovider.ErrInvalidServerData},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}, false)
+			validation.TestExpectErrors(t, tt.name, tt.call(c), tt.expectedErrs...)
+		})
+	}
+}
+
+func Test_bitbucketClientImpl_DeleteRepo(t *testing.T) {
+	deleted := false
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete && r.URL.Path == "/rest/api/1.0/projects/PRJ/repos/foo" {
+			deleted = true
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+
+	err := newTestClient(t, handler, false).DeleteRepo(context.Background(), "PRJ", "foo")
+	validation.TestExpectErrors(t, "DeleteRepo", err, gitprovider.ErrDestructiveCallDisallowed)
+	if deleted {
+		t.Error("DeleteRepo() deleted the repository without destructive actions enabled")
+	}
+
+	if err := newTestClient(t, handler, true).DeleteRepo(context.Background(), "PRJ", "foo"); err != nil {
+		t.Fatalf("DeleteRepo() error = %v", err)
+	}
+	if !deleted {
+		t.Error("DeleteRepo() didn't delete the repository")
+	}
+}
+
+func Test_pageInfoFromResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		page int
+		resp pagedResponse
+		want gitprovider.PageInfo
+	}{
+		{
+			name: "first page",
+			resp: pagedResponse{NextPageStart: 25},
+			want: gitprovider.PageInfo{NextPage: 2, TotalCount: gitprovider.UnknownCount, TotalPages: gitprovider.UnknownCount},
+		},
+		{
+			name: "last page",
+			page: 3,
+			resp: pagedResponse{IsLastPage: true},
+			want: gitprovider.PageInfo{TotalCount: gitprovider.UnknownCount, TotalPages: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pageInfoFromResponse(tt.page, &tt.resp); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pageInfoFromResponse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
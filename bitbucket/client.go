@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// ProviderID is the provider ID for Bitbucket Server.
+const ProviderID = gitprovider.ProviderID("bitbucket-server")
+
+func newClient(c *http.Client, baseURL *url.URL, domain string, destructiveActions bool, defaultOrg *gitprovider.OrganizationRef) *Client {
+	bbClient := &bitbucketClientImpl{c, baseURL, destructiveActions}
+	ctx := &clientContext{bbClient, domain, destructiveActions, defaultOrg}
+	return &Client{
+		clientContext: ctx,
+		orgs: &OrganizationsClient{
+			clientContext: ctx,
+		},
+		orgRepos: &OrgRepositoriesClient{
+			clientContext: ctx,
+		},
+		userRepos: &UserRepositoriesClient{
+			clientContext: ctx,
+		},
+	}
+}
+
+type clientContext struct {
+	c                  bitbucketClient
+	domain             string
+	destructiveActions bool
+	// defaultOrg is used for repository operations given a ref without an organization, if set
+	defaultOrg *gitprovider.OrganizationRef
+}
+
+// resolveOrgRepositoryRef fills in the default organization if ref doesn't specify one.
+func (c *clientContext) resolveOrgRepositoryRef(ref gitprovider.OrgRepositoryRef) (gitprovider.OrgRepositoryRef, error) {
+	orgRef, err := gitprovider.ResolveOrganizationRef(ref.OrganizationRef, c.defaultOrg)
+	ref.OrganizationRef = orgRef
+	return ref, err
+}
+
+// Client implements the gitprovider.Client interface.
+var _ gitprovider.Client = &Client{}
+
+// Client is an interface that allows talking to a Git provider.
+type Client struct {
+	*clientContext
+
+	orgs      *OrganizationsClient
+	orgRepos  *OrgRepositoriesClient
+	userRepos *UserRepositoriesClient
+}
+
+// SupportedDomain returns the domain endpoint for this client, e.g. "bitbucket.example.com" or
+// "my-custom-git-server.com:7990". This allows a higher-level user to know what Client to use for
+// what endpoints.
+// This field is set at client creation time, and can't be changed.
+func (c *Client) SupportedDomain() string {
+	return c.domain
+}
+
+// ProviderID returns the provider ID "bitbucket-server".
+// This field is set at client creation time, and can't be changed.
+func (c *Client) ProviderID() gitprovider.ProviderID {
+	return ProviderID
+}
+
+// Raw returns the *http.Client used under the hood for accessing Bitbucket Server. Requests made
+// with it go through the same transport chain, including authentication, as the ones of this client.
+func (c *Client) Raw() interface{} {
+	return c.c.Client()
+}
+
+// Organizations returns the OrganizationsClient handling sets of organizations.
+func (c *Client) Organizations() gitprovider.OrganizationsClient {
+	return c.orgs
+}
+
+// OrgRepositories returns the OrgRepositoriesClient handling sets of repositories in an organization.
+func (c *Client) OrgRepositories() gitprovider.OrgRepositoriesClient {
+	return c.orgRepos
+}
+
+// UserRepositories returns the UserRepositoriesClient handling sets of repositories for a user.
+func (c *Client) UserRepositories() gitprovider.UserRepositoriesClient {
+	return c.userRepos
+}
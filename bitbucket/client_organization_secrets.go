@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// OrgActionsSecretsClient implements the gitprovider.OrgActionsSecretsClient interface.
+var _ gitprovider.OrgActionsSecretsClient = &OrgActionsSecretsClient{}
+
+// OrgActionsSecretsClient operates on the organization-wide CI secrets of a specific project.
+//
+// This is not supported in Bitbucket Server, which has no built-in CI. All methods return
+// gitprovider.ErrNoProviderSupport.
+type OrgActionsSecretsClient struct {
+	*clientContext
+	ref gitprovider.OrganizationRef
+}
+
+// List lists all secrets in the organization.
+func (c *OrgActionsSecretsClient) List(_ context.Context) ([]gitprovider.ActionsSecretInfo, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Set creates or updates the given secret.
+func (c *OrgActionsSecretsClient) Set(_ context.Context, _ gitprovider.ActionsSecretInfo) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// Delete deletes the secret with the given name.
+func (c *OrgActionsSecretsClient) Delete(_ context.Context, _ string) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// ListSelectedRepositories lists the names of the repositories that can access a secret.
+func (c *OrgActionsSecretsClient) ListSelectedRepositories(_ context.Context, _ string) ([]string, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// SetSelectedRepositories replaces the list of repositories that can access a secret.
+func (c *OrgActionsSecretsClient) SetSelectedRepositories(_ context.Context, _ string, _ []string) error {
+	return gitprovider.ErrNoProviderSupport
+}
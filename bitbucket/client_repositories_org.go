@@ -0,0 +1,450 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// OrgRepositoriesClient implements the gitprovider.OrgRepositoriesClient interface.
+var _ gitprovider.OrgRepositoriesClient = &OrgRepositoriesClient{}
+
+// OrgRepositoriesClient operates on repositories the user has access to.
+type OrgRepositoriesClient struct {
+	*clientContext
+}
+
+// Get returns the repository at the given path.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *OrgRepositoriesClient) Get(ctx context.Context, ref gitprovider.OrgRepositoryRef) (gitprovider.OrgRepository, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := c.resolveOrgRepositoryRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the OrgRepositoryRef is valid
+	if err := validateOrgRepositoryRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+	apiObj, err := getRepository(ctx, c.c, projectKey(ref), ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+	return newOrgRepository(c.clientContext, apiObj, ref), nil
+}
+
+// List all repositories in the given organization.
+//
+// List returns all available repositories, using multiple paginated requests if needed.
+func (c *OrgRepositoriesClient) List(ctx context.Context, ref gitprovider.OrganizationRef) ([]gitprovider.OrgRepository, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the OrganizationRef is valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+
+	// GET /projects/{projectKey}/repos
+	apiObjs, err := c.c.ListRepos(ctx, projectKey(ref))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.orgRepositoriesFromAPI(ref, apiObjs), nil
+}
+
+// ListPage lists one page of repositories, along with the pagination metadata supplied by the provider.
+func (c *OrgRepositoriesClient) ListPage(ctx context.Context, ref gitprovider.OrganizationRef, opts gitprovider.PageOptions) ([]gitprovider.OrgRepository, gitprovider.PageInfo, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	// Make sure the OrganizationRef and options are valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+
+	// GET /projects/{projectKey}/repos
+	apiObjs, pageInfo, err := c.c.ListReposPage(ctx, projectKey(ref), opts)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	return c.orgRepositoriesFromAPI(ref, apiObjs), pageInfo, nil
+}
+
+// ListSorted lists one page of repositories, sorted server-side according to sortOpts.
+//
+// This is not supported in Bitbucket Server, which always sorts repositories by name, hence an
+// error wrapping ErrNoProviderSupport is returned for all sort keys.
+func (c *OrgRepositoriesClient) ListSorted(_ context.Context, _ gitprovider.OrganizationRef, sortOpts gitprovider.RepositorySortOptions, _ gitprovider.PageOptions) ([]gitprovider.OrgRepository, gitprovider.PageInfo, error) {
+	if err := sortOpts.ValidateOptions(); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	return nil, gitprovider.PageInfo{}, fmt.Errorf("sorting repositories by %q: %w", sortOpts.Sort, gitprovider.ErrNoProviderSupport)
+}
+
+// ListRepositoryRefs lists references to the repositories in the given organization that
+// match the filter, without returning the full repository resources.
+//
+// All filters are applied client-side. The Language filter is not supported, as Bitbucket
+// Server doesn't detect languages.
+//
+// ListRepositoryRefs returns all matching references, using multiple paginated requests if needed.
+func (c *OrgRepositoriesClient) ListRepositoryRefs(ctx context.Context, ref gitprovider.OrganizationRef, filter gitprovider.RefListFilter) ([]gitprovider.RepositoryRef, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the OrganizationRef and filter are valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+	if err := filter.ValidateOptions(); err != nil {
+		return nil, err
+	}
+	if len(filter.Language) != 0 {
+		return nil, fmt.Errorf("filtering repositories by language: %w", gitprovider.ErrNoProviderSupport)
+	}
+
+	// GET /projects/{projectKey}/repos
+	apiObjs, err := c.c.ListRepos(ctx, projectKey(ref))
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]gitprovider.RepositoryRef, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListRepos
+		if !repositoryMatchesFilter(apiObj, filter) {
+			continue
+		}
+		refs = append(refs, gitprovider.OrgRepositoryRef{
+			OrganizationRef: ref,
+			RepositoryName:  apiObj.Slug,
+		})
+	}
+	return refs, nil
+}
+
+// ListRepositories lists the repositories in the given organization that match opts.
+//
+// The Visibility filter is applied client-side. Filtering by topic isn't supported (yet) in
+// Bitbucket Server.
+//
+// ListRepositories returns all matching repositories, using multiple paginated requests if needed.
+func (c *OrgRepositoriesClient) ListRepositories(ctx context.Context, ref gitprovider.OrganizationRef, opts gitprovider.RepositoryListOptions) ([]gitprovider.OrgRepository, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the OrganizationRef and options are valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, err
+	}
+	if len(opts.Topic) != 0 {
+		return nil, fmt.Errorf("filtering repositories by topic: %w", gitprovider.ErrNoProviderSupport)
+	}
+
+	// GET /projects/{projectKey}/repos
+	apiObjs, err := c.c.ListRepos(ctx, projectKey(ref))
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]*Repository, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListRepos
+		if repositoryMatchesFilter(apiObj, gitprovider.RefListFilter{Visibility: opts.Visibility}) {
+			matching = append(matching, apiObj)
+		}
+	}
+	return c.orgRepositoriesFromAPI(ref, matching), nil
+}
+
+// orgRepositoriesFromAPI traverses the list, and returns a list of OrgRepository objects.
+func (c *OrgRepositoriesClient) orgRepositoriesFromAPI(ref gitprovider.OrganizationRef, apiObjs []*Repository) []gitprovider.OrgRepository {
+	repos := make([]gitprovider.OrgRepository, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListRepos or ListReposPage
+		repos = append(repos, newOrgRepository(c.clientContext, apiObj, gitprovider.OrgRepositoryRef{
+			OrganizationRef: ref,
+			RepositoryName:  apiObj.Slug,
+		}))
+	}
+	return repos
+}
+
+// Create creates a repository for the given organization, with the data and options.
+//
+// ErrAlreadyExists will be returned if the resource already exists.
+func (c *OrgRepositoriesClient) Create(ctx context.Context, ref gitprovider.OrgRepositoryRef, req gitprovider.RepositoryInfo, opts ...gitprovider.RepositoryCreateOption) (gitprovider.OrgRepository, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := c.resolveOrgRepositoryRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the RepositoryRef is valid
+	if err := validateOrgRepositoryRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+
+	apiObj, err := createRepository(ctx, c.c, ref, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newOrgRepository(c.clientContext, apiObj, ref), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *OrgRepositoriesClient) Reconcile(ctx context.Context, ref gitprovider.OrgRepositoryRef, req gitprovider.RepositoryInfo, opts ...gitprovider.RepositoryReconcileOption) (gitprovider.OrgRepository, bool, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := c.resolveOrgRepositoryRef(ref)
+	if err != nil {
+		return nil, false, err
+	}
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	actual, err := c.Get(ctx, ref)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			resp, err := c.Create(ctx, ref, req, toCreateOpts(opts...)...)
+			return resp, true, err
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+	// Run generic reconciliation
+	actionTaken, err := reconcileRepository(ctx, actual, req)
+	return actual, actionTaken, err
+}
+
+func createRepository(ctx context.Context, c bitbucketClient, ref gitprovider.RepositoryRef, req gitprovider.RepositoryInfo, opts ...gitprovider.RepositoryCreateOption) (*Repository, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, err
+	}
+	if err := validateVisibility(req.Visibility); err != nil {
+		return nil, err
+	}
+
+	// Assemble the options struct based on the given options
+	o, err := gitprovider.MakeRepositoryCreateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	// Bitbucket Server can't initialize repositories with a commit
+	if o.AutoInit != nil && *o.AutoInit {
+		return nil, fmt.Errorf("auto-initializing repositories: %w", gitprovider.ErrNoProviderSupport)
+	}
+	if o.TemplateRepositoryRef != nil {
+		return nil, fmt.Errorf("creating repositories from a template: %w", gitprovider.ErrNoProviderSupport)
+	}
+
+	// Convert to the API object
+	data := repositoryToAPI(&req, ref)
+
+	// POST /projects/{projectKey}/repos
+	apiObj, err := c.CreateRepo(ctx, projectKey(ref), &data)
+	if err != nil {
+		return nil, err
+	}
+	// The default branch is set at creation time, but not returned
+	apiObj.DefaultBranch = data.DefaultBranch
+	return apiObj, nil
+}
+
+// ReconcileList makes sure the repositories of the given organization are the desired ones, matched
+// by name. Missing repositories are created, and drifted ones updated, see Reconcile. If opts.Prune is
+// set, the repositories that aren't desired, and whose name starts with opts.NamePrefix, are deleted.
+// Deleting repositories requires destructive actions to be enabled in the client, otherwise
+// ErrDestructiveCallDisallowed is returned before any change. If opts.DryRun is set, nothing is changed,
+// hence the deletions are reported even if destructive actions are disabled.
+//
+// The action taken (or, in a dry run, that would be taken) for each repository is returned, also when
+// an error occurred midway.
+func (c *OrgRepositoriesClient) ReconcileList(ctx context.Context, ref gitprovider.OrganizationRef, desired []gitprovider.RepositoryReconcileRequest, opts gitprovider.RepositoryReconcileListOptions) ([]gitprovider.RepositoryReconcileResult, error) {
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, err
+	}
+	// First thing, validate and default the requests to ensure valid and fully-populated objects
+	reqs, err := gitprovider.ValidateAndDefaultRepositoryReconcileRequests(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := c.List(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	// Repository names are case-insensitive in Bitbucket Server
+	actualByName := make(map[string]gitprovider.OrgRepository, len(actual))
+	for _, repo := range actual {
+		actualByName[strings.ToLower(repo.Repository().GetRepository())] = repo
+	}
+	desiredNames := make(map[string]struct{}, len(reqs))
+	for _, req := range reqs {
+		desiredNames[strings.ToLower(req.Name)] = struct{}{}
+	}
+
+	// Find the repositories that aren't desired, and make sure they may be deleted before changing anything
+	toRemove := make([]gitprovider.OrgRepository, 0, len(actual))
+	for _, repo := range actual {
+		name := repo.Repository().GetRepository()
+		if _, ok := desiredNames[strings.ToLower(name)]; !ok && opts.Prunable(name) {
+			toRemove = append(toRemove, repo)
+		}
+	}
+	if len(toRemove) != 0 && !opts.DryRun && !c.destructiveActions {
+		return nil, fmt.Errorf("cannot delete %d repositories: %w", len(toRemove), gitprovider.ErrDestructiveCallDisallowed)
+	}
+
+	results := make([]gitprovider.RepositoryReconcileResult, 0, len(reqs)+len(toRemove))
+	for _, req := range reqs {
+		action, err := c.reconcileListItem(ctx, ref, req, actualByName, opts.DryRun)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, gitprovider.RepositoryReconcileResult{Name: req.Name, Action: action})
+	}
+	for _, repo := range toRemove {
+		if !opts.DryRun {
+			if err := repo.Delete(ctx); err != nil {
+				return results, err
+			}
+		}
+		results = append(results, gitprovider.RepositoryReconcileResult{
+			Name:   repo.Repository().GetRepository(),
+			Action: gitprovider.RepositoryReconcileActionDelete,
+		})
+	}
+	return results, nil
+}
+
+// ForkRepository forks the upstream repository into the organization given in opts.
+//
+// This is not supported (yet) in Bitbucket Server.
+func (c *OrgRepositoriesClient) ForkRepository(_ context.Context, _ gitprovider.RepositoryRef, _ gitprovider.ForkOptions) (gitprovider.OrgRepository, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// TransferRepository transfers the repository to newOwner, a user or organization.
+//
+// This is not supported (yet) in Bitbucket Server.
+func (c *OrgRepositoriesClient) TransferRepository(_ context.Context, _ gitprovider.RepositoryRef, _ gitprovider.IdentityRef, _ gitprovider.TransferOptions) (gitprovider.UserRepository, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// reconcileListItem reconciles a repository desired by ReconcileList, and returns the action taken. The
+// repository is only fetched again if it exists, as the listed objects may lack fields.
+func (c *OrgRepositoriesClient) reconcileListItem(ctx context.Context, ref gitprovider.OrganizationRef, req gitprovider.RepositoryReconcileRequest, actualByName map[string]gitprovider.OrgRepository, dryRun bool) (gitprovider.RepositoryReconcileAction, error) {
+	repoRef := gitprovider.OrgRepositoryRef{OrganizationRef: ref, RepositoryName: req.Name}
+	listed, ok := actualByName[strings.ToLower(req.Name)]
+	if !ok {
+		if dryRun {
+			return gitprovider.RepositoryReconcileActionCreate, nil
+		}
+		_, err := c.Create(ctx, repoRef, req.Info)
+		return gitprovider.RepositoryReconcileActionCreate, err
+	}
+
+	// Use the actual name, as the desired one may differ in case
+	repoRef.RepositoryName = listed.Repository().GetRepository()
+	actual, err := c.Get(ctx, repoRef)
+	if err != nil {
+		return gitprovider.RepositoryReconcileActionNone, err
+	}
+	var drifted bool
+	if dryRun {
+		_, drifted = repositoryDrift(actual, req.Info)
+	} else if drifted, err = reconcileRepository(ctx, actual, req.Info); err != nil {
+		return gitprovider.RepositoryReconcileActionUpdate, err
+	}
+	if !drifted {
+		return gitprovider.RepositoryReconcileActionNone, nil
+	}
+	return gitprovider.RepositoryReconcileActionUpdate, nil
+}
+
+func reconcileRepository(ctx context.Context, actual gitprovider.UserRepository, req gitprovider.RepositoryInfo) (bool, error) {
+	req, drifted := repositoryDrift(actual, req)
+	// If the desired matches the actual state, just return the actual state
+	if !drifted {
+		return false, nil
+	}
+	// Populate the desired state to the current-actual object
+	if err := actual.Set(req); err != nil {
+		return false, err
+	}
+	// Apply the desired state by running Update
+	return true, actual.Update(ctx)
+}
+
+// repositoryDrift returns the desired state to Set on actual, and whether it differs from the actual state.
+func repositoryDrift(actual gitprovider.UserRepository, req gitprovider.RepositoryInfo) (gitprovider.RepositoryInfo, bool) {
+	// Archived isn't supported (yet) in Bitbucket Server, hence don't detect drift for it
+	req.Archived = nil
+	// Topics aren't supported (yet) in Bitbucket Server, hence don't detect drift for them
+	req.Topics = nil
+	// HasIssues, HasWiki and HasProjects have no Bitbucket Server equivalent, hence don't detect drift for them
+	req.HasIssues = nil
+	req.HasWiki = nil
+	req.HasProjects = nil
+	actualInfo := actual.Get()
+	// AllowForking has no default, leave it as-is if it isn't desired
+	if req.AllowForking == nil {
+		req.AllowForking = actualInfo.AllowForking
+	}
+	return req, !req.Equals(actualInfo)
+}
+
+func toCreateOpts(opts ...gitprovider.RepositoryReconcileOption) []gitprovider.RepositoryCreateOption {
+	// Convert RepositoryReconcileOption => RepositoryCreateOption
+	createOpts := make([]gitprovider.RepositoryCreateOption, 0, len(opts))
+	for _, opt := range opts {
+		createOpts = append(createOpts, opt)
+	}
+	return createOpts
+}
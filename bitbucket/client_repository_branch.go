@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// BranchClient implements the gitprovider.BranchClient interface.
+var _ gitprovider.BranchClient = &BranchClient{}
+
+// BranchClient operates on the branches of a specific repository.
+//
+// This is not supported (yet) in Bitbucket Server.
+// All methods return gitprovider.ErrNoProviderSupport.
+type BranchClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get a branch by its name.
+func (c *BranchClient) Get(_ context.Context, _ string) (gitprovider.Branch, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List lists all branches of the repository.
+func (c *BranchClient) List(_ context.Context) ([]gitprovider.Branch, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create creates a branch with the given name, pointing at the commit fromSHA.
+func (c *BranchClient) Create(_ context.Context, _, _ string) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// IsBranchMerged returns true if all commits of the given branch are part of the default branch.
+func (c *BranchClient) IsBranchMerged(_ context.Context, _ string) (bool, error) {
+	return false, gitprovider.ErrNoProviderSupport
+}
+
+// DeleteMergedBranches deletes the merged branches whose last commit is older than opts.MinAge.
+func (c *BranchClient) DeleteMergedBranches(_ context.Context, _ gitprovider.DeleteMergedBranchesOptions) ([]string, map[string]gitprovider.BranchSkipReason, error) {
+	return nil, nil, gitprovider.ErrNoProviderSupport
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// DeployKeyClient implements the gitprovider.DeployKeyClient interface.
+var _ gitprovider.DeployKeyClient = &DeployKeyClient{}
+
+// DeployKeyClient operates on the access deploy key list for a specific repository.
+//
+// This is not supported (yet) in Bitbucket Server, where deploy keys are called access keys.
+// All methods return gitprovider.ErrNoProviderSupport.
+type DeployKeyClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get a DeployKey by its name.
+func (c *DeployKeyClient) Get(_ context.Context, _ string) (gitprovider.DeployKey, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List lists all repository deploy keys.
+func (c *DeployKeyClient) List(_ context.Context) ([]gitprovider.DeployKey, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// ListReadWriteKeys lists the repository deploy keys that can write to the repository.
+func (c *DeployKeyClient) ListReadWriteKeys(_ context.Context) ([]gitprovider.DeployKey, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create creates a deploy key with the given specifications.
+func (c *DeployKeyClient) Create(_ context.Context, _ gitprovider.DeployKeyInfo) (gitprovider.DeployKey, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+func (c *DeployKeyClient) Reconcile(_ context.Context, _ gitprovider.DeployKeyInfo) (gitprovider.DeployKey, bool, error) {
+	return nil, false, gitprovider.ErrNoProviderSupport
+}
+
+// ReconcileList makes sure the deploy keys of the repository are exactly the desired ones.
+func (c *DeployKeyClient) ReconcileList(_ context.Context, _ []gitprovider.DeployKeyInfo) ([]string, []string, error) {
+	return nil, nil, gitprovider.ErrNoProviderSupport
+}
+
+// ReconcileAll makes sure the deploy keys of the repository are exactly the desired ones.
+func (c *DeployKeyClient) ReconcileAll(_ context.Context, _ []gitprovider.DeployKeyInfo) (bool, error) {
+	return false, gitprovider.ErrNoProviderSupport
+}
+
+// RotateAll replaces each deploy key of the repository with a new key pair from generate.
+func (c *DeployKeyClient) RotateAll(_ context.Context, _ func() ([]byte, []byte, error)) (map[string][]byte, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// EnableDeployKeyForProject enables an existing deploy key of this repository for another repository.
+func (c *DeployKeyClient) EnableDeployKeyForProject(_ context.Context, _ int, _ gitprovider.RepositoryRef) error {
+	return gitprovider.ErrNoProviderSupport
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// ReleaseClient implements the gitprovider.ReleaseClient interface.
+var _ gitprovider.ReleaseClient = &ReleaseClient{}
+
+// ReleaseClient operates on the releases of a specific repository.
+//
+// This is not supported in Bitbucket Server, which has no releases, only tags.
+// All methods return gitprovider.ErrNoProviderSupport.
+type ReleaseClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// LatestRelease returns the most recently released release of the repository.
+func (c *ReleaseClient) LatestRelease(_ context.Context) (gitprovider.ReleaseInfo, error) {
+	return gitprovider.ReleaseInfo{}, gitprovider.ErrNoProviderSupport
+}
+
+// Get returns the release made from the given tag.
+func (c *ReleaseClient) Get(_ context.Context, _ string) (gitprovider.Release, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List lists all releases of the repository, newest first.
+func (c *ReleaseClient) List(_ context.Context) ([]gitprovider.Release, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create creates a release with the given specifications.
+func (c *ReleaseClient) Create(_ context.Context, _ gitprovider.ReleaseInfo) (gitprovider.Release, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// TeamAccessClient implements the gitprovider.TeamAccessClient interface.
+var _ gitprovider.TeamAccessClient = &TeamAccessClient{}
+
+// TeamAccessClient operates on the teams list for a specific repository.
+//
+// This is not supported (yet) in Bitbucket Server, where the closest equivalent are group
+// permissions of the repository. All methods return gitprovider.ErrNoProviderSupport.
+type TeamAccessClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get a team's permission level of this given repository.
+func (c *TeamAccessClient) Get(_ context.Context, _ string) (gitprovider.TeamAccess, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List the team access control list for this repository.
+func (c *TeamAccessClient) List(_ context.Context) ([]gitprovider.TeamAccess, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create adds a given team to the repository's team access control list.
+func (c *TeamAccessClient) Create(_ context.Context, _ gitprovider.TeamAccessInfo) (gitprovider.TeamAccess, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+func (c *TeamAccessClient) Reconcile(_ context.Context, _ gitprovider.TeamAccessInfo) (gitprovider.TeamAccess, bool, error) {
+	return nil, false, gitprovider.ErrNoProviderSupport
+}
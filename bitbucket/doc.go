@@ -14,9 +14,10 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Package bitbucket implements the gitprovider interfaces for Bitbucket Server (formerly Stash),
+// talking to its REST API (/rest/api/1.0) directly. Bitbucket Cloud is not supported.
+//
+// Projects are modelled as organizations, where the project key is the organization name.
+// Repositories of a user live in the personal project "~{username}", and the repository
+// name is the repository slug.
 package bitbucket
-
-import (
-	// TODO: Dummy import until we have the implementation ready.
-	_ "github.com/ktrysmt/go-bitbucket"
-)
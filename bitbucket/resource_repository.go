@@ -0,0 +1,595 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// Repository is a Bitbucket Server repository, as returned by the REST API.
+type Repository struct {
+	ID          int      `json:"id,omitempty"`
+	Slug        string   `json:"slug,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	ScmID       string   `json:"scmId,omitempty"`
+	State       string   `json:"state,omitempty"`
+	Forkable    *bool    `json:"forkable,omitempty"`
+	Public      bool     `json:"public"`
+	Archived    bool     `json:"archived,omitempty"`
+	Project     *Project `json:"project,omitempty"`
+	// DefaultBranch is the display name of the default branch, e.g. "main". It is only sent when
+	// creating a repository, as the server has a separate endpoint for it, see GetDefaultBranch.
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+}
+
+// branchRef is a reference to a branch, as used by the default branch endpoint.
+type branchRef struct {
+	// ID is the fully-qualified name of the branch, e.g. "refs/heads/main".
+	ID string `json:"id"`
+	// DisplayID is the short name of the branch, e.g. "main".
+	DisplayID string `json:"displayId,omitempty"`
+}
+
+func newUserRepository(ctx *clientContext, apiObj *Repository, ref gitprovider.RepositoryRef) *userRepository {
+	return &userRepository{
+		clientContext: ctx,
+		r:             *apiObj,
+		ref:           ref,
+		deployKeys: &DeployKeyClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		releases: &ReleaseClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		commits: &CommitClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branches: &BranchClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		tags: &TagClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		issues: &IssueClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		hooks: &RepositoryHookClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		rulesets: &RulesetClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branchProtection: &BranchProtectionClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+	}
+}
+
+var _ gitprovider.UserRepository = &userRepository{}
+
+type userRepository struct {
+	*clientContext
+
+	r   Repository
+	ref gitprovider.RepositoryRef
+
+	deployKeys *DeployKeyClient
+	releases   *ReleaseClient
+	commits    *CommitClient
+	branches   *BranchClient
+	tags       *TagClient
+	issues     *IssueClient
+	hooks      *RepositoryHookClient
+	rulesets   *RulesetClient
+
+	branchProtection *BranchProtectionClient
+}
+
+func (r *userRepository) Get() gitprovider.RepositoryInfo {
+	return repositoryFromAPI(&r.r)
+}
+
+func (r *userRepository) Set(info gitprovider.RepositoryInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	if err := validateVisibility(info.Visibility); err != nil {
+		return err
+	}
+	repositoryInfoToAPIObj(&info, &r.r)
+	return nil
+}
+
+func (r *userRepository) APIObject() interface{} {
+	return &r.r
+}
+
+func (r *userRepository) Repository() gitprovider.RepositoryRef {
+	return r.ref
+}
+
+func (r *userRepository) DeployKeys() gitprovider.DeployKeyClient {
+	return r.deployKeys
+}
+
+func (r *userRepository) Releases() gitprovider.ReleaseClient {
+	return r.releases
+}
+
+func (r *userRepository) Commits() gitprovider.CommitClient {
+	return r.commits
+}
+
+func (r *userRepository) Branches() gitprovider.BranchClient {
+	return r.branches
+}
+
+func (r *userRepository) Tags() gitprovider.TagClient {
+	return r.tags
+}
+
+func (r *userRepository) Issues() gitprovider.IssueClient {
+	return r.issues
+}
+
+func (r *userRepository) Hooks() gitprovider.RepositoryHookClient {
+	return r.hooks
+}
+
+func (r *userRepository) Rulesets() gitprovider.RulesetClient {
+	return r.rulesets
+}
+
+func (r *userRepository) BranchProtection() gitprovider.BranchProtectionClient {
+	return r.branchProtection
+}
+
+// Update will apply the desired state in this object to the server.
+// Only set fields will be respected (i.e. PATCH behaviour).
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (r *userRepository) Update(ctx context.Context) error {
+	// PUT /projects/{projectKey}/repos/{repositorySlug}
+	apiObj, err := r.c.UpdateRepo(ctx, projectKey(r.ref), r.ref.GetRepository(), repositoryUpdateRequest(&r.r))
+	if err != nil {
+		return err
+	}
+	// The default branch isn't part of the repository, hence apply it separately
+	if len(r.r.DefaultBranch) != 0 {
+		// PUT /projects/{projectKey}/repos/{repositorySlug}/default-branch
+		if err := r.c.SetDefaultBranch(ctx, projectKey(r.ref), r.ref.GetRepository(), r.r.DefaultBranch); err != nil {
+			return err
+		}
+		apiObj.DefaultBranch = r.r.DefaultBranch
+	}
+	r.r = *apiObj
+	return nil
+}
+
+// Refresh fetches the current state of this repository from the server, without
+// mutating anything. Any local changes that weren't applied are discarded.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (r *userRepository) Refresh(ctx context.Context) error {
+	apiObj, err := getRepository(ctx, r.c, projectKey(r.ref), r.ref.GetRepository())
+	if err != nil {
+		return err
+	}
+	r.r = *apiObj
+	return nil
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (r *userRepository) Reconcile(ctx context.Context) (bool, error) {
+	apiObj, err := getRepository(ctx, r.c, projectKey(r.ref), r.ref.GetRepository())
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			// POST /projects/{projectKey}/repos
+			repo, err := r.c.CreateRepo(ctx, projectKey(r.ref), repositoryCreateRequest(&r.r))
+			if err != nil {
+				return true, err
+			}
+			repo.DefaultBranch = r.r.DefaultBranch
+			r.r = *repo
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	// Use wrappers here to extract the "spec" part of the object for comparison
+	desiredSpec := newBitbucketRepositorySpec(&r.r)
+	actualSpec := newBitbucketRepositorySpec(apiObj)
+	// Forkable and the default branch are only reconciled if they are desired
+	if r.r.Forkable == nil {
+		actualSpec.Forkable = nil
+	}
+	if len(r.r.DefaultBranch) == 0 {
+		actualSpec.DefaultBranch = ""
+	}
+
+	// If desired state already is the actual state, do nothing
+	if desiredSpec.Equals(actualSpec) {
+		return false, nil
+	}
+	// Otherwise, make the desired state the actual state
+	return true, r.Update(ctx)
+}
+
+// Delete deletes the current resource irreversibly.
+//
+// ErrNotFound is returned if the resource doesn't exist anymore.
+func (r *userRepository) Delete(ctx context.Context) error {
+	// DELETE /projects/{projectKey}/repos/{repositorySlug}
+	return r.c.DeleteRepo(ctx, projectKey(r.ref), r.ref.GetRepository())
+}
+
+// ListSecurityAdvisories lists the security advisories filed for this repository.
+//
+// This is not supported in Bitbucket Server.
+func (r *userRepository) ListSecurityAdvisories(_ context.Context, _ gitprovider.SecurityAdvisoryListOptions) ([]gitprovider.SecurityAdvisoryInfo, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// SetPipelineRequirements configures what CI results are required before changes can be merged.
+//
+// This is not supported (yet) in Bitbucket Server, where builds are required through merge checks.
+func (r *userRepository) SetPipelineRequirements(_ context.Context, _ gitprovider.PipelineRequirements) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// SetIssueCloseSettings configures how issues are closed automatically when referenced.
+//
+// This is not supported in Bitbucket Server, which has no issue tracker.
+func (r *userRepository) SetIssueCloseSettings(_ context.Context, _ gitprovider.IssueCloseSettings) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// ListInstalledApps lists the apps that have been granted access to this repository.
+//
+// This is not supported in Bitbucket Server.
+func (r *userRepository) ListInstalledApps(_ context.Context) ([]gitprovider.InstalledAppInfo, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// EffectivePermissions returns the permissions the credentials of the client have on this repository.
+//
+// This is not supported in Bitbucket Server, which doesn't expose granular permissions.
+func (r *userRepository) EffectivePermissions(_ context.Context) (map[string]string, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// PrimaryLanguage returns the dominant programming language of this repository.
+//
+// This is not supported in Bitbucket Server, which doesn't detect languages.
+func (r *userRepository) PrimaryLanguage(_ context.Context) (string, error) {
+	return "", gitprovider.ErrNoProviderSupport
+}
+
+// IsEmpty returns true if the repository has no commits (and hence no branches) yet.
+//
+// This is not supported (yet) in Bitbucket Server.
+func (r *userRepository) IsEmpty(_ context.Context) (bool, error) {
+	return false, gitprovider.ErrNoProviderSupport
+}
+
+// GetMergeQueue returns the merge queue settings of the given branch.
+//
+// This is not supported in Bitbucket Server.
+func (r *userRepository) GetMergeQueue(_ context.Context, _ string) (gitprovider.MergeQueueInfo, error) {
+	return gitprovider.MergeQueueInfo{}, gitprovider.ErrNoProviderSupport
+}
+
+// SetMergeQueue configures the merge queue of the given branch.
+//
+// This is not supported in Bitbucket Server.
+func (r *userRepository) SetMergeQueue(_ context.Context, _ string, _ gitprovider.MergeQueueInfo) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// TriggerMirrorSync starts syncing this mirror repository with its upstream repository.
+//
+// This is not supported in Bitbucket Server.
+func (r *userRepository) TriggerMirrorSync(_ context.Context) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// GetMirrorStatus returns the state of the last sync of this mirror repository.
+//
+// This is not supported in Bitbucket Server.
+func (r *userRepository) GetMirrorStatus(_ context.Context) (gitprovider.MirrorStatusInfo, error) {
+	return gitprovider.MirrorStatusInfo{}, gitprovider.ErrNoProviderSupport
+}
+
+// SetMergeTrain enables or disables merge trains.
+//
+// This is not supported in Bitbucket Server.
+func (r *userRepository) SetMergeTrain(_ context.Context, _ bool) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// SetWebCommitSigning configures whether unsigned commits are rejected for all branches.
+//
+// This is not supported (yet) in Bitbucket Server, where this requires a hook.
+func (r *userRepository) SetWebCommitSigning(_ context.Context, _ bool) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// CountOpenIssues returns the amount of open issues in this repository. As Bitbucket Server
+// has no issue tracker, this is always 0.
+func (r *userRepository) CountOpenIssues(_ context.Context) (int64, error) {
+	return 0, nil
+}
+
+// CountOpenPullRequests returns the amount of open pull requests in this repository.
+//
+// This is not supported (yet) in Bitbucket Server.
+func (r *userRepository) CountOpenPullRequests(_ context.Context) (int64, error) {
+	return 0, gitprovider.ErrNoProviderSupport
+}
+
+// ListProtectedEnvironments lists the protected environments of this repository.
+//
+// This is not supported in Bitbucket Server, which has no deployment environments.
+func (r *userRepository) ListProtectedEnvironments(_ context.Context) ([]gitprovider.ProtectedEnvironmentInfo, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// ProtectEnvironment makes sure only the roles in req can deploy to the environment.
+//
+// This is not supported in Bitbucket Server, which has no deployment environments.
+func (r *userRepository) ProtectEnvironment(_ context.Context, _ gitprovider.ProtectedEnvironmentInfo) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// UnprotectEnvironment removes the protection of the given environment.
+//
+// This is not supported in Bitbucket Server, which has no deployment environments.
+func (r *userRepository) UnprotectEnvironment(_ context.Context, _ string) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// SetSubscription configures what notifications the authenticated user gets about this repository.
+//
+// This is not supported (yet) in Bitbucket Server.
+func (r *userRepository) SetSubscription(_ context.Context, _ gitprovider.SubscriptionInfo) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// GetCommunityHealthFile returns the content of the given community health file on the default branch.
+//
+// This is not supported (yet) in Bitbucket Server.
+func (r *userRepository) GetCommunityHealthFile(_ context.Context, _ gitprovider.CommunityHealthFile) (string, error) {
+	return "", gitprovider.ErrNoProviderSupport
+}
+
+// SetCommunityHealthFile commits content as the given community health file to the default branch.
+//
+// This is not supported (yet) in Bitbucket Server.
+func (r *userRepository) SetCommunityHealthFile(_ context.Context, _ gitprovider.CommunityHealthFile, _ string) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// CommunityHealthScore returns the community health score of this repository.
+//
+// This is not supported (yet) in Bitbucket Server.
+func (r *userRepository) CommunityHealthScore(_ context.Context) (gitprovider.CommunityHealthInfo, error) {
+	return gitprovider.CommunityHealthInfo{}, gitprovider.ErrNoProviderSupport
+}
+
+func newOrgRepository(ctx *clientContext, apiObj *Repository, ref gitprovider.RepositoryRef) *orgRepository {
+	return &orgRepository{
+		userRepository: *newUserRepository(ctx, apiObj, ref),
+		teamAccess: &TeamAccessClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+	}
+}
+
+var _ gitprovider.OrgRepository = &orgRepository{}
+
+type orgRepository struct {
+	userRepository
+
+	teamAccess *TeamAccessClient
+}
+
+func (r *orgRepository) TeamAccess() gitprovider.TeamAccessClient {
+	return r.teamAccess
+}
+
+// getRepository fetches the repository along with its default branch, as the default branch
+// isn't part of the repository resource in Bitbucket Server.
+func getRepository(ctx context.Context, c bitbucketClient, projectKey, repoSlug string) (*Repository, error) {
+	// GET /projects/{projectKey}/repos/{repositorySlug}
+	apiObj, err := c.GetRepo(ctx, projectKey, repoSlug)
+	if err != nil {
+		return nil, err
+	}
+	// GET /projects/{projectKey}/repos/{repositorySlug}/default-branch
+	defaultBranch, err := c.GetDefaultBranch(ctx, projectKey, repoSlug)
+	// Repositories without a configured default branch respond with 404 Not Found
+	if err != nil && !errors.Is(err, gitprovider.ErrNotFound) {
+		return nil, err
+	}
+	apiObj.DefaultBranch = defaultBranch
+	return apiObj, nil
+}
+
+// validateVisibility makes sure the visibility, if set, exists in Bitbucket Server.
+func validateVisibility(visibility *gitprovider.RepositoryVisibility) error {
+	if visibility != nil && *visibility == gitprovider.RepositoryVisibilityInternal {
+		return fmt.Errorf("bitbucket server doesn't support internal repositories: %w", gitprovider.ErrNoProviderSupport)
+	}
+	return nil
+}
+
+// validateRepositoryAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateRepositoryAPI(apiObj *Repository) error {
+	return validateAPIObject("Bitbucket.Repository", func(validator validation.Validator) {
+		// Make sure name and slug are set
+		if len(apiObj.Name) == 0 {
+			validator.Required("Name")
+		}
+		if len(apiObj.Slug) == 0 {
+			validator.Required("Slug")
+		}
+	})
+}
+
+// validateBranchRefAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateBranchRefAPI(apiObj *branchRef) error {
+	return validateAPIObject("Bitbucket.BranchRef", func(validator validation.Validator) {
+		if len(apiObj.DisplayID) == 0 {
+			validator.Required("DisplayID")
+		}
+	})
+}
+
+func repositoryFromAPI(apiObj *Repository) gitprovider.RepositoryInfo {
+	repo := gitprovider.RepositoryInfo{
+		Description:  &apiObj.Description,
+		AllowForking: apiObj.Forkable,
+	}
+	// The default branch is unknown for listed repositories
+	if len(apiObj.DefaultBranch) != 0 {
+		repo.DefaultBranch = &apiObj.DefaultBranch
+	}
+	if apiObj.Public {
+		repo.Visibility = gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic)
+	} else {
+		repo.Visibility = gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPrivate)
+	}
+	return repo
+}
+
+func repositoryToAPI(repo *gitprovider.RepositoryInfo, ref gitprovider.RepositoryRef) Repository {
+	apiObj := Repository{
+		Name:  ref.GetRepository(),
+		ScmID: "git",
+	}
+	repositoryInfoToAPIObj(repo, &apiObj)
+	return apiObj
+}
+
+func repositoryInfoToAPIObj(repo *gitprovider.RepositoryInfo, apiObj *Repository) {
+	if repo.Description != nil {
+		apiObj.Description = *repo.Description
+	}
+	if repo.DefaultBranch != nil {
+		apiObj.DefaultBranch = *repo.DefaultBranch
+	}
+	if repo.Visibility != nil {
+		apiObj.Public = *repo.Visibility == gitprovider.RepositoryVisibilityPublic
+	}
+	if repo.AllowForking != nil {
+		apiObj.Forkable = repo.AllowForking
+	}
+	// HasIssues, HasWiki and HasProjects have no Bitbucket Server equivalent, and are ignored
+}
+
+// repositoryCreateRequest returns the fields of apiObj that can be set when creating a repository.
+func repositoryCreateRequest(apiObj *Repository) *Repository {
+	req := repositoryUpdateRequest(apiObj)
+	req.ScmID = "git"
+	req.DefaultBranch = apiObj.DefaultBranch
+	return req
+}
+
+// repositoryUpdateRequest returns the fields of apiObj that can be updated. In particular, the
+// project is left out, as setting it moves the repository.
+func repositoryUpdateRequest(apiObj *Repository) *Repository {
+	return &Repository{
+		Name:        apiObj.Name,
+		Description: apiObj.Description,
+		Forkable:    apiObj.Forkable,
+		Public:      apiObj.Public,
+	}
+}
+
+// This function copies over the fields that are part of create/update requests of a repository
+// i.e. the desired spec of the repository. This allows us to separate "spec" from "status" fields.
+// Fields that Bitbucket Server canonicalizes server-side are normalized, see RepositoryInfo.Normalized().
+func newBitbucketRepositorySpec(repo *Repository) *bitbucketRepositorySpec {
+	return &bitbucketRepositorySpec{
+		&Repository{
+			// Generic
+			Name:        repo.Name,
+			Description: gitprovider.NormalizeDescription(repo.Description),
+			Forkable:    repo.Forkable,
+			Public:      repo.Public,
+
+			// Update-specific parameters
+			DefaultBranch: gitprovider.NormalizeBranchName(repo.DefaultBranch),
+		},
+	}
+}
+
+type bitbucketRepositorySpec struct {
+	*Repository
+}
+
+func (s *bitbucketRepositorySpec) Equals(other *bitbucketRepositorySpec) bool {
+	return reflect.DeepEqual(s, other)
+}
+
+// repositoryMatchesFilter applies the filters of RefListFilter, which Bitbucket Server can't
+// apply server-side. Languages aren't detected by Bitbucket Server, hence that filter must not be set.
+func repositoryMatchesFilter(apiObj *Repository, filter gitprovider.RefListFilter) bool {
+	if filter.Visibility != nil && *repositoryFromAPI(apiObj).Visibility != *filter.Visibility {
+		return false
+	}
+	if filter.Archived != nil && apiObj.Archived != *filter.Archived {
+		return false
+	}
+	return strings.HasPrefix(apiObj.Slug, filter.NamePrefix)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+const (
+	alreadyExistsMagicString = "DuplicateRepositoryNameException"
+	// userProjectPrefix is the prefix of the keys of personal projects, followed by the user slug.
+	userProjectPrefix = "~"
+	// branchRefPrefix is the prefix of the fully-qualified names of branches.
+	branchRefPrefix = "refs/heads/"
+	// defaultPageLimit is the amount of items the server returns per page, if no limit is requested.
+	defaultPageLimit = 25
+)
+
+// projectKey returns the key of the project the repositories of ref live in, which is the
+// organization name for organizations, and the personal project of the user for users.
+func projectKey(ref gitprovider.IdentityRef) string {
+	if ref.GetType() == gitprovider.IdentityTypeUser {
+		return userProjectPrefix + ref.GetIdentity()
+	}
+	return ref.GetIdentity()
+}
+
+// validateUserRepositoryRef makes sure the UserRepositoryRef is valid for Bitbucket Server's usage.
+func validateUserRepositoryRef(ref gitprovider.UserRepositoryRef, expectedDomain string) error {
+	// Make sure the RepositoryRef fields are valid
+	if err := validation.ValidateTargets("UserRepositoryRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
+// validateOrgRepositoryRef makes sure the OrgRepositoryRef is valid for Bitbucket Server's usage.
+func validateOrgRepositoryRef(ref gitprovider.OrgRepositoryRef, expectedDomain string) error {
+	// Make sure the RepositoryRef fields are valid
+	if err := validation.ValidateTargets("OrgRepositoryRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
+// validateOrganizationRef makes sure the OrganizationRef is valid for Bitbucket Server's usage.
+func validateOrganizationRef(ref gitprovider.OrganizationRef, expectedDomain string) error {
+	// Make sure the OrganizationRef fields are valid
+	if err := validation.ValidateTargets("OrganizationRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
+// validateUserRef makes sure the UserRef is valid for Bitbucket Server's usage.
+func validateUserRef(ref gitprovider.UserRef, expectedDomain string) error {
+	// Make sure the UserRef fields are valid
+	if err := validation.ValidateTargets("UserRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
+// validateIdentityFields makes sure the type of the IdentityRef is supported, and the domain is as expected.
+func validateIdentityFields(ref gitprovider.IdentityRef, expectedDomain string) error {
+	// Make sure the expected domain is used
+	if ref.GetDomain() != expectedDomain {
+		return fmt.Errorf("domain %q not supported by this client: %w", ref.GetDomain(), gitprovider.ErrDomainUnsupported)
+	}
+	// Make sure the right type of identityref is used
+	switch ref.GetType() {
+	case gitprovider.IdentityTypeOrganization, gitprovider.IdentityTypeUser:
+		return nil
+	case gitprovider.IdentityTypeSuborganization:
+		return fmt.Errorf("bitbucket server doesn't support sub-organizations: %w", gitprovider.ErrNoProviderSupport)
+	}
+	return fmt.Errorf("invalid identity type: %v: %w", ref.GetType(), gitprovider.ErrInvalidArgument)
+}
+
+// errorResponse is returned from requests the server responded to with an unsuccessful status code.
+type errorResponse struct {
+	// Response is the HTTP response, whose body has already been read.
+	Response *http.Response `json:"-"`
+	// Errors are the errors the server returned in the body, if any.
+	Errors []errorDetail `json:"errors"`
+}
+
+// errorDetail is an error returned by the server.
+type errorDetail struct {
+	Context       string `json:"context"`
+	Message       string `json:"message"`
+	ExceptionName string `json:"exceptionName"`
+}
+
+func (r *errorResponse) Error() string {
+	return fmt.Sprintf("%v %v: %d %v",
+		r.Response.Request.Method, r.Response.Request.URL, r.Response.StatusCode, r.message())
+}
+
+// message joins the messages of all errors returned by the server.
+func (r *errorResponse) message() string {
+	messages := make([]string, 0, len(r.Errors))
+	for _, detail := range r.Errors {
+		messages = append(messages, detail.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// handleHTTPError checks the type of err, and returns typed variants of it
+// However, it _always_ keeps the original error too, and just wraps it in a MultiError
+// The consumer must use errors.Is and errors.As to check for equality and get data out of it.
+func handleHTTPError(err error) error {
+	// Short-circuit quickly if possible, allow always piping through this function
+	if err == nil {
+		return nil
+	}
+	bbErrorResponse := &errorResponse{}
+	if errors.As(err, &bbErrorResponse) {
+		httpErr := gitprovider.HTTPError{
+			Response:     bbErrorResponse.Response,
+			ErrorMessage: bbErrorResponse.Error(),
+			Message:      bbErrorResponse.message(),
+		}
+		// Check for invalid credentials, and return a typed error in that case
+		if bbErrorResponse.Response.StatusCode == http.StatusForbidden ||
+			bbErrorResponse.Response.StatusCode == http.StatusUnauthorized {
+			return validation.NewMultiError(err,
+				&gitprovider.InvalidCredentialsError{HTTPError: httpErr},
+			)
+		}
+		// Check for 404 Not Found
+		if bbErrorResponse.Response.StatusCode == http.StatusNotFound {
+			return validation.NewMultiError(err, gitprovider.ErrNotFound)
+		}
+		// Check for already exists errors
+		for _, detail := range bbErrorResponse.Errors {
+			if strings.HasSuffix(detail.ExceptionName, alreadyExistsMagicString) {
+				return validation.NewMultiError(err, gitprovider.ErrAlreadyExists)
+			}
+		}
+		// Otherwise, return a generic *HTTPError
+		return validation.NewMultiError(err, &httpErr)
+	}
+	// Do nothing, just pipe through the unknown err
+	return err
+}
+
+// allPages runs fn for each page, expecting a HTTP request to be made and returned during that call.
+// allPages expects that the data is saved in fn to an outer variable.
+// allPages calls fn as many times as needed to get all pages, and modifies opts for each call.
+// There is no need to wrap the resulting error in handleHTTPError(err), as that's already done.
+func allPages(opts *listOptions, fn func() (*pagedResponse, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return handleHTTPError(err)
+		}
+		if resp.IsLastPage {
+			return nil
+		}
+		opts.Start = resp.NextPageStart
+	}
+}
+
+// pageListOptions converts the given PageOptions to listOptions. Bitbucket Server pages by item
+// index rather than page index, hence the limit is always set, in order to know where pages start.
+func pageListOptions(opts gitprovider.PageOptions) listOptions {
+	page, limit := opts.Page, opts.PerPage
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+	return listOptions{Start: (page - 1) * limit, Limit: limit}
+}
+
+// pageInfoFromResponse normalizes the pagination metadata of a paged Bitbucket Server API response.
+// Bitbucket Server doesn't supply the total amount of items, so that is always unknown. The total
+// amount of pages is only known from the last page.
+func pageInfoFromResponse(page int, resp *pagedResponse) gitprovider.PageInfo {
+	if page == 0 {
+		page = 1
+	}
+	if resp.IsLastPage {
+		return gitprovider.PageInfo{
+			TotalCount: gitprovider.UnknownCount,
+			TotalPages: page,
+		}
+	}
+	return gitprovider.PageInfo{
+		NextPage:   page + 1,
+		TotalCount: gitprovider.UnknownCount,
+		TotalPages: gitprovider.UnknownCount,
+	}
+}
+
+// validateAPIObject creates a Validatior with the specified name, gives it to fn, and
+// depending on if any error was registered with it; either returns nil, or a MultiError
+// with both the validation error and ErrInvalidServerData, to mark that the server data
+// was invalid.
+func validateAPIObject(name string, fn func(validation.Validator)) error {
+	v := validation.New(name)
+	fn(v)
+	// If there was a validation error, also mark it specifically as invalid server data
+	if err := v.Error(); err != nil {
+		return validation.NewMultiError(err, gitprovider.ErrInvalidServerData)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+func dummyRoundTripper1(http.RoundTripper) http.RoundTripper { return nil }
+func dummyRoundTripper2(http.RoundTripper) http.RoundTripper { return nil }
+
+func roundTrippersEqual(a, b gitprovider.ChainableRoundTripperFunc) bool {
+	if a == nil && b == nil {
+		return true
+	} else if (a != nil && b == nil) || (a == nil && b != nil) {
+		return false
+	}
+	// Note that this comparison relies on "undefined behavior" in the Go language spec, see:
+	// https://stackoverflow.com/questions/9643205/how-do-i-compare-two-functions-for-pointer-equality-in-the-latest-go-weekly
+	return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+}
+
+func Test_makeOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []ClientOption
+		want         *clientOptions
+		expectedErrs []error
+	}{
+		{
+			name: "no options",
+			want: &clientOptions{},
+		},
+		{
+			name: "WithDomain",
+			opts: []ClientOption{WithDomain("git.example.com:3000")},
+			want: buildCommonOption(gitprovider.CommonClientOptions{Domain: gitprovider.StringVar("git.example.com:3000")}),
+		},
+		{
+			name:         "WithDomain, empty",
+			opts:         []ClientOption{WithDomain("")},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name: "WithDestructiveAPICalls",
+			opts: []ClientOption{WithDestructiveAPICalls(true)},
+			want: buildCommonOption(gitprovider.CommonClientOptions{EnableDestructiveAPICalls: gitprovider.BoolVar(true)}),
+		},
+		{
+			name: "WithPreChainTransportHook",
+			opts: []ClientOption{WithPreChainTransportHook(dummyRoundTripper1)},
+			want: buildCommonOption(gitprovider.CommonClientOptions{PreChainTransportHook: dummyRoundTripper1}),
+		},
+		{
+			name:         "WithPostChainTransportHook, nil",
+			opts:         []ClientOption{WithPostChainTransportHook(nil)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithPersonalAccessToken, empty",
+			opts:         []ClientOption{WithPersonalAccessToken("")},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithPersonalAccessToken, exclusive",
+			opts:         []ClientOption{WithPersonalAccessToken("foo"), WithPersonalAccessToken("bar")},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := makeOptions(tt.opts...)
+			validation.TestExpectErrors(t, "makeOptions", err, tt.expectedErrs...)
+			if tt.want == nil {
+				return
+			}
+			if !roundTrippersEqual(got.AuthTransport, tt.want.AuthTransport) ||
+				!roundTrippersEqual(got.PostChainTransportHook, tt.want.PostChainTransportHook) ||
+				!roundTrippersEqual(got.PreChainTransportHook, tt.want.PreChainTransportHook) {
+				t.Errorf("makeOptions() = %v, want %v", got, tt.want)
+			}
+			got.PostChainTransportHook = nil
+			got.PreChainTransportHook = nil
+			tt.want.PostChainTransportHook = nil
+			tt.want.PreChainTransportHook = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("makeOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClient_domain(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []ClientOption
+		wantDomain   string
+		expectedErrs []error
+	}{
+		{
+			name:       "default",
+			wantDomain: DefaultDomain,
+		},
+		{
+			name:       "host and port",
+			opts:       []ClientOption{WithDomain("git.example.com:3000")},
+			wantDomain: "git.example.com:3000",
+		},
+		{
+			name:       "API base URL with path prefix",
+			opts:       []ClientOption{WithDomain("https://git.example.com/gitea/api/v1/")},
+			wantDomain: "git.example.com/gitea",
+		},
+		{
+			name:         "unsupported scheme",
+			opts:         []ClientOption{WithDomain("ftp://git.example.com")},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(tt.opts...)
+			validation.TestExpectErrors(t, "NewClient", err, tt.expectedErrs...)
+			if err != nil {
+				return
+			}
+			if got := c.SupportedDomain(); got != tt.wantDomain {
+				t.Errorf("NewClient() domain = %v, want %v", got, tt.wantDomain)
+			}
+		})
+	}
+}
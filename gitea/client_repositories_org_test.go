@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"code.gitea.io/sdk/gitea"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// fakeOrgReposClient is a giteaClient listing the repositories of an organization.
+// Calling any other method than the overridden ones panics.
+type fakeOrgReposClient struct {
+	giteaClient
+
+	repos []*gitea.Repository
+}
+
+func (c *fakeOrgReposClient) ListOrgRepos(_ context.Context, _ string) ([]*gitea.Repository, error) {
+	return c.repos, nil
+}
+
+func TestOrgRepositoriesClient_ListRepositories(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      gitprovider.RepositoryListOptions
+		wantNames []string
+		wantErr   error
+	}{
+		{
+			name:      "no filters",
+			wantNames: []string{"api", "docs", "web"},
+		},
+		{
+			name:      "visibility",
+			opts:      gitprovider.RepositoryListOptions{Visibility: gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic)},
+			wantNames: []string{"docs", "web"},
+		},
+		{
+			name:    "topics aren't supported",
+			opts:    gitprovider.RepositoryListOptions{Topic: "go"},
+			wantErr: gitprovider.ErrNoProviderSupport,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeOrgReposClient{repos: []*gitea.Repository{
+				{Name: "api", Private: true},
+				{Name: "docs"},
+				{Name: "web"},
+			}}
+			c := &OrgRepositoriesClient{
+				clientContext: &clientContext{c: fake, domain: DefaultDomain},
+			}
+			ref := gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"}
+
+			repos, err := c.ListRepositories(context.Background(), ref, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ListRepositories() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			names := make([]string, 0, len(repos))
+			for _, repo := range repos {
+				names = append(names, repo.Repository().GetRepository())
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("ListRepositories() = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
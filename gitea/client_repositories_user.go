@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/sdk/gitea"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// UserRepositoriesClient implements the gitprovider.UserRepositoriesClient interface.
+var _ gitprovider.UserRepositoriesClient = &UserRepositoriesClient{}
+
+// UserRepositoriesClient operates on repositories the user has access to.
+type UserRepositoriesClient struct {
+	*clientContext
+}
+
+// Get returns the repository at the given path.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *UserRepositoriesClient) Get(ctx context.Context, ref gitprovider.UserRepositoryRef) (gitprovider.UserRepository, error) {
+	// Make sure the UserRepositoryRef is valid
+	if err := validateUserRepositoryRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+	// GET /repos/{owner}/{repo}
+	apiObj, err := c.c.GetRepo(ctx, ref.GetIdentity(), ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+	return newUserRepository(c.clientContext, apiObj, ref), nil
+}
+
+// List all repositories for the given user.
+//
+// List returns all available repositories, using multiple paginated requests if needed.
+func (c *UserRepositoriesClient) List(ctx context.Context, ref gitprovider.UserRef) ([]gitprovider.UserRepository, error) {
+	// Make sure the UserRef is valid
+	if err := validateUserRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+
+	// GET /users/{username}/repos
+	apiObjs, err := c.c.ListUserRepos(ctx, ref.UserLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.userRepositoriesFromAPI(ref, apiObjs), nil
+}
+
+// ListPage lists one page of repositories, along with the pagination metadata supplied by the provider.
+func (c *UserRepositoriesClient) ListPage(ctx context.Context, ref gitprovider.UserRef, opts gitprovider.PageOptions) ([]gitprovider.UserRepository, gitprovider.PageInfo, error) {
+	// Make sure the UserRef and options are valid
+	if err := validateUserRef(ref, c.domain); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+
+	// GET /users/{username}/repos
+	apiObjs, pageInfo, err := c.c.ListUserReposPage(ctx, ref.UserLogin, opts)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	return c.userRepositoriesFromAPI(ref, apiObjs), pageInfo, nil
+}
+
+// userRepositoriesFromAPI traverses the list, and returns a list of UserRepository objects.
+func (c *UserRepositoriesClient) userRepositoriesFromAPI(ref gitprovider.UserRef, apiObjs []*gitea.Repository) []gitprovider.UserRepository {
+	repos := make([]gitprovider.UserRepository, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListUserRepos or ListUserReposPage
+		repos = append(repos, newUserRepository(c.clientContext, apiObj, gitprovider.UserRepositoryRef{
+			UserRef:        ref,
+			RepositoryName: apiObj.Name,
+		}))
+	}
+	return repos
+}
+
+// Create creates a repository for the given user, with the data and options.
+//
+// ErrAlreadyExists will be returned if the resource already exists.
+func (c *UserRepositoriesClient) Create(ctx context.Context,
+	ref gitprovider.UserRepositoryRef,
+	req gitprovider.RepositoryInfo,
+	opts ...gitprovider.RepositoryCreateOption,
+) (gitprovider.UserRepository, error) {
+	// Make sure the RepositoryRef is valid
+	if err := validateUserRepositoryRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+
+	apiObj, err := createRepository(ctx, c.c, ref, "", req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newUserRepository(c.clientContext, apiObj, ref), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *UserRepositoriesClient) Reconcile(ctx context.Context, ref gitprovider.UserRepositoryRef, req gitprovider.RepositoryInfo, opts ...gitprovider.RepositoryReconcileOption) (gitprovider.UserRepository, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	actual, err := c.Get(ctx, ref)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			resp, err := c.Create(ctx, ref, req, toCreateOpts(opts...)...)
+			return resp, true, err
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+
+	// Run generic reconciliation
+	actionTaken, err := reconcileRepository(ctx, actual, req)
+	return actual, actionTaken, err
+}
+
+// GetAuthenticatedUser returns the login and domain of the user the client is authenticated as.
+//
+// An *InvalidCredentialsError is returned if the client isn't authenticated, or the token is invalid.
+func (c *UserRepositoriesClient) GetAuthenticatedUser(ctx context.Context) (gitprovider.UserInfo, error) {
+	// GET /user
+	apiObj, err := c.c.GetMyUserInfo(ctx)
+	if err != nil {
+		return gitprovider.UserInfo{}, fmt.Errorf("failed to get the authenticated user: %w", err)
+	}
+	return gitprovider.UserInfo{Domain: c.domain, Login: apiObj.UserName}, nil
+}
+
+func validateUserAPI(apiObj *gitea.User) error {
+	return validateAPIObject("Gitea.User", func(validator validation.Validator) {
+		if len(apiObj.UserName) == 0 {
+			validator.Required("UserName")
+		}
+	})
+}
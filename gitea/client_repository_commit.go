@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// CommitClient implements the gitprovider.CommitClient interface.
+var _ gitprovider.CommitClient = &CommitClient{}
+
+// CommitClient operates on the commits of a specific repository.
+//
+// This is not supported (yet) in Gitea.
+// All methods return gitprovider.ErrNoProviderSupport.
+type CommitClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// ListPage lists the commits of the given branch, newest first, in pages of perPage commits.
+func (c *CommitClient) ListPage(_ context.Context, _ string, _, _ int, _ gitprovider.CommitListOptions) ([]gitprovider.Commit, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create creates a commit on top of the given branch, changing the given files.
+func (c *CommitClient) Create(_ context.Context, _ string, _ string, _ []gitprovider.CommitFile) (gitprovider.Commit, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// CommitCount returns the amount of commits reachable from the given branch.
+func (c *CommitClient) CommitCount(_ context.Context, _ string) (int64, error) {
+	return 0, gitprovider.ErrNoProviderSupport
+}
@@ -0,0 +1,365 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/sdk/gitea"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// DeployKeyClient implements the gitprovider.DeployKeyClient interface.
+var _ gitprovider.DeployKeyClient = &DeployKeyClient{}
+
+// DeployKeyClient operates on the access deploy key list for a specific repository.
+type DeployKeyClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the deploy key with the given name (title).
+//
+// As Gitea can't get deploy keys by their title, the keys are listed until the key is found.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *DeployKeyClient) Get(ctx context.Context, name string) (gitprovider.DeployKey, error) {
+	return c.get(ctx, name)
+}
+
+func (c *DeployKeyClient) get(ctx context.Context, name string) (*deployKey, error) {
+	deployKeys, err := c.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Loop through deploy keys once we find one with the right name
+	for _, dk := range deployKeys {
+		if dk.k.Title == name {
+			return dk, nil
+		}
+	}
+	return nil, gitprovider.ErrNotFound
+}
+
+// List lists all repository deploy keys of the given deploy key type.
+//
+// List returns all available repository deploy keys for the given type,
+// using multiple paginated requests if needed.
+func (c *DeployKeyClient) List(ctx context.Context) ([]gitprovider.DeployKey, error) {
+	dks, err := c.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Cast to the generic []gitprovider.DeployKey
+	keys := make([]gitprovider.DeployKey, 0, len(dks))
+	for _, dk := range dks {
+		keys = append(keys, dk)
+	}
+	return keys, nil
+}
+
+// ListReadWriteKeys lists the repository deploy keys that can write to the repository.
+//
+// This is a client-side filter over all deploy keys, as they can't be filtered server-side.
+func (c *DeployKeyClient) ListReadWriteKeys(ctx context.Context) ([]gitprovider.DeployKey, error) {
+	dks, err := c.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]gitprovider.DeployKey, 0, len(dks))
+	for _, dk := range dks {
+		if readOnly := dk.Get().ReadOnly; readOnly != nil && !*readOnly {
+			keys = append(keys, dk)
+		}
+	}
+	return keys, nil
+}
+
+func (c *DeployKeyClient) list(ctx context.Context) ([]*deployKey, error) {
+	// GET /repos/{owner}/{repo}/keys
+	apiObjs, err := c.c.ListKeys(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+
+	// Map the api object to our DeployKey type
+	keys := make([]*deployKey, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListKeys
+		keys = append(keys, newDeployKey(c, apiObj))
+	}
+
+	return keys, nil
+}
+
+// Create creates a deploy key with the given specifications.
+//
+// ErrAlreadyExists will be returned if the resource already exists.
+func (c *DeployKeyClient) Create(ctx context.Context, req gitprovider.DeployKeyInfo) (gitprovider.DeployKey, error) {
+	apiObj, err := createDeployKey(ctx, c.c, c.ref, req)
+	if err != nil {
+		return nil, err
+	}
+	return newDeployKey(c, apiObj), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be deleted and recreated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *DeployKeyClient) Reconcile(ctx context.Context, req gitprovider.DeployKeyInfo) (gitprovider.DeployKey, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	// Get the key with the desired name
+	actual, err := c.Get(ctx, req.Name)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			resp, err := c.Create(ctx, req)
+			return resp, true, err
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Populate the desired state to the current-actual object
+	if err := actual.Set(req); err != nil {
+		return actual, false, err
+	}
+	// Apply the desired state by running Update
+	return actual, true, actual.Update(ctx)
+}
+
+// ReconcileList makes sure the deploy keys of the repository are exactly the desired ones, matched
+// by their public key material. Missing keys are created, matching keys are left as-is, and the
+// other keys are deleted, which requires destructive actions to be enabled in the client.
+//
+// The names of the added and removed keys are returned, also when an error occurred midway.
+func (c *DeployKeyClient) ReconcileList(ctx context.Context, desired []gitprovider.DeployKeyInfo) ([]string, []string, error) {
+	// First thing, validate and default the requests to ensure valid and fully-populated objects
+	reqs := make([]gitprovider.DeployKeyInfo, len(desired))
+	for i := range desired {
+		reqs[i] = desired[i]
+		if err := gitprovider.ValidateAndDefaultInfo(&reqs[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	actual, err := c.list(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Find the keys that aren't desired, and make sure they may be deleted before changing anything
+	toRemove := make([]*deployKey, 0, len(actual))
+	for _, dk := range actual {
+		if !containsDeployKey(reqs, dk.Get()) {
+			toRemove = append(toRemove, dk)
+		}
+	}
+	if len(toRemove) != 0 && !c.destructiveActions {
+		return nil, nil, fmt.Errorf("cannot delete %d deploy keys: %w", len(toRemove), gitprovider.ErrDestructiveCallDisallowed)
+	}
+
+	// Create the missing keys first, so access isn't interrupted when replacing keys
+	actualInfos := actualDeployKeyInfos(actual)
+	added, removed := []string{}, []string{}
+	for _, req := range reqs {
+		if containsDeployKey(actualInfos, req) {
+			continue
+		}
+		if _, err := c.Create(ctx, req); err != nil {
+			return added, removed, err
+		}
+		added = append(added, req.Name)
+	}
+	for _, dk := range toRemove {
+		if err := dk.Delete(ctx); err != nil {
+			return added, removed, err
+		}
+		removed = append(removed, dk.Get().Name)
+	}
+	return added, removed, nil
+}
+
+// ReconcileAll makes sure the deploy keys of the repository are exactly the desired ones, matched
+// by name. Missing keys are created, and keys whose public key material or access level changed are
+// rotated, i.e. deleted and recreated, as Gitea deploy keys are immutable. The other keys are deleted.
+// Rotating and deleting keys requires destructive actions to be enabled in the client.
+//
+// Whether any key was created, rotated or deleted is returned, also when an error occurred midway.
+func (c *DeployKeyClient) ReconcileAll(ctx context.Context, desired []gitprovider.DeployKeyInfo) (bool, error) {
+	// First thing, validate and default the requests to ensure valid and fully-populated objects
+	reqs, err := gitprovider.ValidateAndDefaultDeployKeyInfos(desired)
+	if err != nil {
+		return false, err
+	}
+
+	actual, err := c.list(ctx)
+	if err != nil {
+		return false, err
+	}
+	actualByName := make(map[string]*deployKey, len(actual))
+	for _, dk := range actual {
+		actualByName[dk.Get().Name] = dk
+	}
+
+	// Sort the desired keys into the ones to create and to rotate, and find the keys that aren't desired
+	toCreate := make([]gitprovider.DeployKeyInfo, 0, len(reqs))
+	toRotate := make([]gitprovider.DeployKeyInfo, 0, len(reqs))
+	desiredNames := make(map[string]struct{}, len(reqs))
+	for _, req := range reqs {
+		desiredNames[req.Name] = struct{}{}
+		dk, ok := actualByName[req.Name]
+		if !ok {
+			toCreate = append(toCreate, req)
+		} else if !req.Equals(dk.Get()) {
+			toRotate = append(toRotate, req)
+		}
+	}
+	toRemove := make([]*deployKey, 0, len(actual))
+	for _, dk := range actual {
+		if _, ok := desiredNames[dk.Get().Name]; !ok {
+			toRemove = append(toRemove, dk)
+		}
+	}
+
+	// Make sure the keys may be rotated and deleted before changing anything
+	if len(toRotate)+len(toRemove) != 0 && !c.destructiveActions {
+		return false, fmt.Errorf("cannot rotate %d and delete %d deploy keys: %w",
+			len(toRotate), len(toRemove), gitprovider.ErrDestructiveCallDisallowed)
+	}
+
+	changed := false
+	for _, req := range toCreate {
+		if _, err := c.Create(ctx, req); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	for _, req := range toRotate {
+		dk := actualByName[req.Name]
+		if err := dk.Set(req); err != nil {
+			return changed, err
+		}
+		if err := dk.Update(ctx); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	for _, dk := range toRemove {
+		if err := dk.Delete(ctx); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// RotateAll replaces each deploy key of the repository with a new key pair from generate, keeping its
+// name and access level. The new key is created before the old one is deleted, which requires destructive
+// actions to be enabled in the client.
+//
+// The private keys of the new key pairs are returned by key name. If some keys failed to rotate,
+// a *validation.MultiError with an error per key is returned too.
+func (c *DeployKeyClient) RotateAll(ctx context.Context, generate func() (pub, priv []byte, err error)) (map[string][]byte, error) {
+	if !c.destructiveActions {
+		return nil, fmt.Errorf("cannot rotate deploy keys: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+
+	actual, err := c.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKeys := make(map[string][]byte, len(actual))
+	errs := []error{}
+	for _, dk := range actual {
+		req := dk.Get()
+		pub, priv, err := generate()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to generate key pair for deploy key %q: %w", req.Name, err))
+			continue
+		}
+		req.Key = pub
+		// Create the new key first, so the old key still works if that fails
+		if _, err := c.Create(ctx, req); err != nil {
+			errs = append(errs, fmt.Errorf("failed to create new deploy key %q, kept the old key: %w", req.Name, err))
+			continue
+		}
+		// The new key works from now on, so hand it out even if deleting the old key fails
+		privateKeys[req.Name] = priv
+		if err := dk.Delete(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("created new deploy key %q, but failed to delete the old key: %w", req.Name, err))
+		}
+	}
+	if len(errs) != 0 {
+		return privateKeys, validation.NewMultiError(errs...)
+	}
+	return privateKeys, nil
+}
+
+// actualDeployKeyInfos returns the DeployKeyInfo of each of the keys.
+func actualDeployKeyInfos(keys []*deployKey) []gitprovider.DeployKeyInfo {
+	infos := make([]gitprovider.DeployKeyInfo, 0, len(keys))
+	for _, dk := range keys {
+		infos = append(infos, dk.Get())
+	}
+	return infos
+}
+
+// containsDeployKey returns true if any of infos has the same public key material as info.
+func containsDeployKey(infos []gitprovider.DeployKeyInfo, info gitprovider.DeployKeyInfo) bool {
+	for _, other := range infos {
+		if other.HasSameKey(info) {
+			return true
+		}
+	}
+	return false
+}
+
+// EnableDeployKeyForProject enables an existing deploy key for another repository.
+//
+// This is not supported in Gitea, deploy keys are always bound to exactly one repository.
+func (c *DeployKeyClient) EnableDeployKeyForProject(_ context.Context, _ int, _ gitprovider.RepositoryRef) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+func createDeployKey(ctx context.Context, c giteaClient, ref gitprovider.RepositoryRef, req gitprovider.DeployKeyInfo) (*gitea.DeployKey, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, err
+	}
+	// POST /repos/{owner}/{repo}/keys
+	return c.CreateKey(ctx, ref.GetIdentity(), ref.GetRepository(), deployKeyToAPI(&req))
+}
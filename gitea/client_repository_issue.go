@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// IssueClient implements the gitprovider.IssueClient interface.
+var _ gitprovider.IssueClient = &IssueClient{}
+
+// IssueClient operates on the issues of a specific repository.
+//
+// This is not supported (yet) in Gitea.
+// All methods return gitprovider.ErrNoProviderSupport.
+type IssueClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Create creates an open issue with the given title, body and labels.
+func (c *IssueClient) Create(_ context.Context, _, _ string, _ []string) (gitprovider.Issue, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List lists the issues of the repository, optionally filtered by state and label.
+func (c *IssueClient) List(_ context.Context, _ gitprovider.IssueListOptions) ([]gitprovider.Issue, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
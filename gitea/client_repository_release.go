@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// ReleaseClient implements the gitprovider.ReleaseClient interface.
+var _ gitprovider.ReleaseClient = &ReleaseClient{}
+
+// ReleaseClient operates on the releases of a specific repository.
+type ReleaseClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// LatestRelease returns the most recently published release of the repository, as determined by
+// Gitea. Drafts and prereleases are never considered the latest release.
+//
+// ErrNotFound is returned if the repository has no (published) releases.
+func (c *ReleaseClient) LatestRelease(ctx context.Context) (gitprovider.ReleaseInfo, error) {
+	// GET /repos/{owner}/{repo}/releases/latest
+	apiObj, err := c.c.GetLatestRelease(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return gitprovider.ReleaseInfo{}, err
+	}
+	return releaseFromAPI(apiObj), nil
+}
+
+// Get returns the release made from the given tag.
+//
+// This is not supported (yet) in Gitea, gitprovider.ErrNoProviderSupport is returned.
+func (c *ReleaseClient) Get(_ context.Context, _ string) (gitprovider.Release, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List lists all releases of the repository, newest first.
+//
+// This is not supported (yet) in Gitea, gitprovider.ErrNoProviderSupport is returned.
+func (c *ReleaseClient) List(_ context.Context) ([]gitprovider.Release, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create creates a release with the given specifications.
+//
+// This is not supported (yet) in Gitea, gitprovider.ErrNoProviderSupport is returned.
+func (c *ReleaseClient) Create(_ context.Context, _ gitprovider.ReleaseInfo) (gitprovider.Release, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
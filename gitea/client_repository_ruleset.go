@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// RulesetClient implements the gitprovider.RulesetClient interface.
+var _ gitprovider.RulesetClient = &RulesetClient{}
+
+// RulesetClient operates on the rulesets of a specific repository.
+//
+// This is not supported in Gitea, which has no rulesets.
+// All methods return gitprovider.ErrNoProviderSupport.
+type RulesetClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the ruleset with the given name.
+func (c *RulesetClient) Get(_ context.Context, _ string) (gitprovider.Ruleset, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+func (c *RulesetClient) Reconcile(_ context.Context, _ gitprovider.RulesetInfo) (gitprovider.Ruleset, bool, error) {
+	return nil, false, gitprovider.ErrNoProviderSupport
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// TagClient implements the gitprovider.TagClient interface.
+var _ gitprovider.TagClient = &TagClient{}
+
+// TagClient operates on the tags of a specific repository.
+//
+// This is not supported (yet) in Gitea.
+// All methods return gitprovider.ErrNoProviderSupport.
+type TagClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// List lists all tags of the repository, along with the commits they point to.
+func (c *TagClient) List(_ context.Context) ([]gitprovider.Tag, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create creates a lightweight tag with the given name, pointing at the commit sha.
+func (c *TagClient) Create(_ context.Context, _, _ string) error {
+	return gitprovider.ErrNoProviderSupport
+}
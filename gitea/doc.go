@@ -0,0 +1,21 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gitea implements the gitprovider interfaces for Gitea, using code.gitea.io/sdk/gitea.
+//
+// Organizations map to Gitea organizations, and the repositories of users to the repositories
+// owned by their user account. Sub-organizations aren't supported.
+package gitea
@@ -0,0 +1,356 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/sdk/gitea"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// giteaClient is a wrapper around *gitea.Client, which implements higher-level methods,
+// operating on the Gitea SDK structs. Pagination is implemented for all List* methods, all returned
+// objects are validated, and HTTP errors are handled/wrapped using handleHTTPError.
+// This interface is also fakeable, in order to unit-test the client.
+type giteaClient interface {
+	// Client returns the underlying *gitea.Client
+	Client() *gitea.Client
+
+	// GetOrg is a wrapper for "GET /orgs/{org}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetOrg(ctx context.Context, orgName string) (*gitea.Organization, error)
+	// ListOrgs is a wrapper for "GET /user/orgs".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListOrgs(ctx context.Context) ([]*gitea.Organization, error)
+
+	// GetMyUserInfo is a wrapper for "GET /user".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetMyUserInfo(ctx context.Context) (*gitea.User, error)
+
+	// GetRepo is a wrapper for "GET /repos/{owner}/{repo}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetRepo(ctx context.Context, owner, repo string) (*gitea.Repository, error)
+	// ListOrgRepos is a wrapper for "GET /orgs/{org}/repos".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListOrgRepos(ctx context.Context, org string) ([]*gitea.Repository, error)
+	// ListOrgReposPage is a wrapper for one page of "GET /orgs/{org}/repos".
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListOrgReposPage(ctx context.Context, org string, opts gitprovider.PageOptions) ([]*gitea.Repository, gitprovider.PageInfo, error)
+	// ListUserRepos is a wrapper for "GET /users/{username}/repos".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListUserRepos(ctx context.Context, username string) ([]*gitea.Repository, error)
+	// ListUserReposPage is a wrapper for one page of "GET /users/{username}/repos".
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListUserReposPage(ctx context.Context, username string, opts gitprovider.PageOptions) ([]*gitea.Repository, gitprovider.PageInfo, error)
+	// CreateRepo is a wrapper for "POST /user/repos" (if orgName == "")
+	// or "POST /orgs/{org}/repos" (if orgName != "").
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateRepo(ctx context.Context, orgName string, req *gitea.CreateRepoOption) (*gitea.Repository, error)
+	// EditRepo is a wrapper for "PATCH /repos/{owner}/{repo}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	EditRepo(ctx context.Context, owner, repo string, req *gitea.EditRepoOption) (*gitea.Repository, error)
+	// DeleteRepo is a wrapper for "DELETE /repos/{owner}/{repo}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteRepo(ctx context.Context, owner, repo string) error
+
+	// ListKeys is a wrapper for "GET /repos/{owner}/{repo}/keys".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListKeys(ctx context.Context, owner, repo string) ([]*gitea.DeployKey, error)
+	// CreateKey is a wrapper for "POST /repos/{owner}/{repo}/keys".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateKey(ctx context.Context, owner, repo string, req *gitea.CreateKeyOption) (*gitea.DeployKey, error)
+	// DeleteKey is a wrapper for "DELETE /repos/{owner}/{repo}/keys/{id}".
+	// This function handles HTTP error wrapping.
+	DeleteKey(ctx context.Context, owner, repo string, id int64) error
+
+	// GetLatestRelease is a wrapper for "GET /repos/{owner}/{repo}/releases/latest".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetLatestRelease(ctx context.Context, owner, repo string) (*gitea.Release, error)
+}
+
+// giteaClientImpl is a wrapper around *gitea.Client, which implements higher-level methods,
+// operating on the Gitea SDK structs. See the giteaClient interface for method documentation.
+type giteaClientImpl struct {
+	c                  *gitea.Client
+	destructiveActions bool
+
+	// baseURL and httpClient are the settings of c, used to create an SDK client per method call,
+	// see withContext.
+	baseURL    string
+	httpClient *http.Client
+}
+
+// giteaClientImpl implements giteaClient.
+var _ giteaClient = &giteaClientImpl{}
+
+// newGiteaClientImpl creates a giteaClientImpl for the Gitea server at baseURL, sending the
+// requests through httpClient.
+func newGiteaClientImpl(baseURL string, httpClient *http.Client, destructiveActions bool) (*giteaClientImpl, error) {
+	// Don't let the SDK request the server version, as that would make creating a client fail if
+	// the server isn't reachable.
+	c, err := gitea.NewClient(baseURL, gitea.SetHTTPClient(httpClient), gitea.SetGiteaVersion(""))
+	if err != nil {
+		return nil, err
+	}
+	return &giteaClientImpl{c: c, destructiveActions: destructiveActions, baseURL: baseURL, httpClient: httpClient}, nil
+}
+
+func (c *giteaClientImpl) Client() *gitea.Client {
+	return c.c
+}
+
+// withContext returns an SDK client sending its requests with ctx. The SDK takes the context per
+// client, not per request, hence every method call uses its own client, sharing the underlying
+// *http.Client (and with it, the connections). This way, concurrent calls can't send their requests
+// with each other's context, nor wait for each other.
+func (c *giteaClientImpl) withContext(ctx context.Context) *gitea.Client {
+	// NewClient only fails checking the server version, which SetGiteaVersion("") turns off
+	gt, _ := gitea.NewClient(c.baseURL, gitea.SetHTTPClient(c.httpClient), gitea.SetGiteaVersion(""), gitea.SetContext(ctx))
+	return gt
+}
+
+func (c *giteaClientImpl) GetOrg(ctx context.Context, orgName string) (*gitea.Organization, error) {
+	gt := c.withContext(ctx)
+	// GET /orgs/{org}
+	apiObj, resp, err := gt.GetOrg(orgName)
+	if err != nil {
+		return nil, handleHTTPError(resp, err)
+	}
+	// Validate the API object
+	if err := validateOrganizationAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) ListOrgs(ctx context.Context) ([]*gitea.Organization, error) {
+	gt := c.withContext(ctx)
+	apiObjs := []*gitea.Organization{}
+	opts := gitea.ListOrgsOptions{}
+	err := allPages(&opts.ListOptions, func() (*gitea.Response, error) {
+		// GET /user/orgs
+		pageObjs, resp, listErr := gt.ListMyOrgs(opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validateOrganizationAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *giteaClientImpl) GetMyUserInfo(ctx context.Context) (*gitea.User, error) {
+	gt := c.withContext(ctx)
+	// GET /user
+	apiObj, resp, err := gt.GetMyUserInfo()
+	if err != nil {
+		return nil, handleHTTPError(resp, err)
+	}
+	// Validate the API object
+	if err := validateUserAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) GetRepo(ctx context.Context, owner, repo string) (*gitea.Repository, error) {
+	gt := c.withContext(ctx)
+	// GET /repos/{owner}/{repo}
+	apiObj, resp, err := gt.GetRepo(owner, repo)
+	return validateRepositoryAPIResp(apiObj, resp, err)
+}
+
+func (c *giteaClientImpl) ListOrgRepos(ctx context.Context, org string) ([]*gitea.Repository, error) {
+	gt := c.withContext(ctx)
+	var apiObjs []*gitea.Repository
+	opts := gitea.ListOrgReposOptions{}
+	err := allPages(&opts.ListOptions, func() (*gitea.Response, error) {
+		// GET /orgs/{org}/repos
+		pageObjs, resp, listErr := gt.ListOrgRepos(org, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return validateRepositoryObjects(apiObjs)
+}
+
+func (c *giteaClientImpl) ListOrgReposPage(ctx context.Context, org string, opts gitprovider.PageOptions) ([]*gitea.Repository, gitprovider.PageInfo, error) {
+	gt := c.withContext(ctx)
+	listOpts := gitea.ListOrgReposOptions{ListOptions: pageListOptions(opts)}
+	// GET /orgs/{org}/repos
+	apiObjs, resp, err := gt.ListOrgRepos(org, listOpts)
+	return validateRepositoryPage(listOpts.Page, apiObjs, resp, err)
+}
+
+func (c *giteaClientImpl) ListUserRepos(ctx context.Context, username string) ([]*gitea.Repository, error) {
+	gt := c.withContext(ctx)
+	var apiObjs []*gitea.Repository
+	opts := gitea.ListReposOptions{}
+	err := allPages(&opts.ListOptions, func() (*gitea.Response, error) {
+		// GET /users/{username}/repos
+		pageObjs, resp, listErr := gt.ListUserRepos(username, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return validateRepositoryObjects(apiObjs)
+}
+
+func (c *giteaClientImpl) ListUserReposPage(ctx context.Context, username string, opts gitprovider.PageOptions) ([]*gitea.Repository, gitprovider.PageInfo, error) {
+	gt := c.withContext(ctx)
+	listOpts := gitea.ListReposOptions{ListOptions: pageListOptions(opts)}
+	// GET /users/{username}/repos
+	apiObjs, resp, err := gt.ListUserRepos(username, listOpts)
+	return validateRepositoryPage(listOpts.Page, apiObjs, resp, err)
+}
+
+func (c *giteaClientImpl) CreateRepo(ctx context.Context, orgName string, req *gitea.CreateRepoOption) (*gitea.Repository, error) {
+	gt := c.withContext(ctx)
+	if len(orgName) == 0 {
+		// POST /user/repos
+		apiObj, resp, err := gt.CreateRepo(*req)
+		return validateRepositoryAPIResp(apiObj, resp, err)
+	}
+	// POST /orgs/{org}/repos
+	apiObj, resp, err := gt.CreateOrgRepo(orgName, *req)
+	return validateRepositoryAPIResp(apiObj, resp, err)
+}
+
+func (c *giteaClientImpl) EditRepo(ctx context.Context, owner, repo string, req *gitea.EditRepoOption) (*gitea.Repository, error) {
+	gt := c.withContext(ctx)
+	// PATCH /repos/{owner}/{repo}
+	apiObj, resp, err := gt.EditRepo(owner, repo, *req)
+	return validateRepositoryAPIResp(apiObj, resp, err)
+}
+
+func (c *giteaClientImpl) DeleteRepo(ctx context.Context, owner, repo string) error {
+	// Don't allow deleting repositories if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete repository: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	gt := c.withContext(ctx)
+	// DELETE /repos/{owner}/{repo}
+	resp, err := gt.DeleteRepo(owner, repo)
+	return handleHTTPError(resp, err)
+}
+
+func (c *giteaClientImpl) ListKeys(ctx context.Context, owner, repo string) ([]*gitea.DeployKey, error) {
+	gt := c.withContext(ctx)
+	apiObjs := []*gitea.DeployKey{}
+	opts := gitea.ListDeployKeysOptions{}
+	err := allPages(&opts.ListOptions, func() (*gitea.Response, error) {
+		// GET /repos/{owner}/{repo}/keys
+		pageObjs, resp, listErr := gt.ListDeployKeys(owner, repo, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validateDeployKeyAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *giteaClientImpl) CreateKey(ctx context.Context, owner, repo string, req *gitea.CreateKeyOption) (*gitea.DeployKey, error) {
+	gt := c.withContext(ctx)
+	// POST /repos/{owner}/{repo}/keys
+	apiObj, resp, err := gt.CreateDeployKey(owner, repo, *req)
+	if err != nil {
+		return nil, handleHTTPError(resp, err)
+	}
+	// Validate the API object
+	if err := validateDeployKeyAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) DeleteKey(ctx context.Context, owner, repo string, id int64) error {
+	gt := c.withContext(ctx)
+	// DELETE /repos/{owner}/{repo}/keys/{id}
+	resp, err := gt.DeleteDeployKey(owner, repo, id)
+	return handleHTTPError(resp, err)
+}
+
+func (c *giteaClientImpl) GetLatestRelease(ctx context.Context, owner, repo string) (*gitea.Release, error) {
+	gt := c.withContext(ctx)
+	// GET /repos/{owner}/{repo}/releases/latest
+	apiObj, resp, err := gt.GetLatestRelease(owner, repo)
+	if err != nil {
+		return nil, handleHTTPError(resp, err)
+	}
+	if err := validateReleaseAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func validateRepositoryAPIResp(apiObj *gitea.Repository, resp *gitea.Response, err error) (*gitea.Repository, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, handleHTTPError(resp, err)
+	}
+	// Make sure apiObj is valid
+	if err := validateRepositoryAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func validateRepositoryPage(page int, apiObjs []*gitea.Repository, resp *gitea.Response, err error) ([]*gitea.Repository, gitprovider.PageInfo, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, handleHTTPError(resp, err)
+	}
+	apiObjs, err = validateRepositoryObjects(apiObjs)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	return apiObjs, pageInfoFromResponse(page, resp), nil
+}
+
+func validateRepositoryObjects(apiObjs []*gitea.Repository) ([]*gitea.Repository, error) {
+	for _, apiObj := range apiObjs {
+		// Make sure apiObj is valid
+		if err := validateRepositoryAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"code.gitea.io/sdk/gitea"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// newTestClient returns a giteaClientImpl talking to a test server serving handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc, destructiveActions bool) *giteaClientImpl {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c, err := newGiteaClientImpl(srv.URL, srv.Client(), destructiveActions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func Test_giteaClientImpl_ListOrgRepos(t *testing.T) {
+	var pages []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/orgs/my-org/repos" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		if page == "1" {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v1/orgs/my-org/repos?page=2>; rel="next"`, r.Host))
+			fmt.Fprint(w, `[{"name":"foo"}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"name":"bar"}]`)
+	}, false)
+
+	repos, err := c.ListOrgRepos(context.Background(), "my-org")
+	if err != nil {
+		t.Fatalf("ListOrgRepos() error = %v", err)
+	}
+	if len(repos) != 2 || repos[0].Name != "foo" || repos[1].Name != "bar" {
+		t.Errorf("ListOrgRepos() = %v, want repositories foo and bar", repos)
+	}
+	if want := []string{"1", "2"}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("ListOrgRepos() requested pages %v, want %v", pages, want)
+	}
+}
+
+func Test_giteaClientImpl_errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		call         func(c *giteaClientImpl) error
+		expectedErrs []error
+	}{
+		{
+			name:   "not found",
+			status: http.StatusNotFound,
+			body:   `{"message":"The target couldn't be found."}`,
+			call: func(c *giteaClientImpl) error {
+				_, err := c.GetRepo(context.Background(), "my-org", "foo")
+				return err
+			},
+			expectedErrs: []error{gitprovider.ErrNotFound},
+		},
+		{
+			name:   "already exists",
+			status: http.StatusConflict,
+			body:   `{"message":"The repository with the same name already exists."}`,
+			call: func(c *giteaClientImpl) error {
+				_, err := c.CreateRepo(context.Background(), "my-org", &gitea.CreateRepoOption{Name: "foo"})
+				return err
+			},
+			expectedErrs: []error{gitprovider.ErrAlreadyExists},
+		},
+		{
+			name:   "invalid credentials",
+			status: http.StatusUnauthorized,
+			body:   `{"message":"token is required"}`,
+			call: func(c *giteaClientImpl) error {
+				_, err := c.ListOrgs(context.Background())
+				return err
+			},
+			expectedErrs: []error{&gitprovider.InvalidCredentialsError{}},
+		},
+		{
+			name:   "invalid server data",
+			status: http.StatusOK,
+			body:   `{"title":"foo","key":"ssh-ed25519 AAAA"}`,
+			call: func(c *giteaClientImpl) error {
+				_, err := c.CreateKey(context.Background(), "my-org", "foo", &gitea.CreateKeyOption{Title: "foo"})
+				return err
+			},
+			expectedErrs: []error{gitprovider.ErrInvalidServerData},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}, false)
+			validation.TestExpectErrors(t, tt.name, tt.call(c), tt.expectedErrs...)
+		})
+	}
+}
+
+func Test_giteaClientImpl_DeleteRepo(t *testing.T) {
+	deleted := false
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete && r.URL.Path == "/api/v1/repos/my-org/foo" {
+			deleted = true
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	err := newTestClient(t, handler, false).DeleteRepo(context.Background(), "my-org", "foo")
+	validation.TestExpectErrors(t, "DeleteRepo", err, gitprovider.ErrDestructiveCallDisallowed)
+	if deleted {
+		t.Error("DeleteRepo() deleted the repository without destructive actions enabled")
+	}
+
+	if err := newTestClient(t, handler, true).DeleteRepo(context.Background(), "my-org", "foo"); err != nil {
+		t.Fatalf("DeleteRepo() error = %v", err)
+	}
+	if !deleted {
+		t.Error("DeleteRepo() didn't delete the repository")
+	}
+}
+
+func Test_giteaClientImpl_concurrentContexts(t *testing.T) {
+	firstPage, proceed := make(chan struct{}), make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path != "/api/v1/orgs/my-org/repos":
+			fmt.Fprint(w, `{"name":"baz"}`)
+		case r.URL.Query().Get("page") == "1":
+			// Hold the first page, until the other calls are done
+			close(firstPage)
+			<-proceed
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v1/orgs/my-org/repos?page=2>; rel="next"`, r.Host))
+			fmt.Fprint(w, `[{"name":"foo"}]`)
+		default:
+			fmt.Fprint(w, `[{"name":"bar"}]`)
+		}
+	}, false)
+
+	// List the repositories in two requests, with a context that is never canceled
+	listErr := make(chan error)
+	go func() {
+		_, err := c.ListOrgRepos(context.Background(), "my-org")
+		listErr <- err
+	}()
+	<-firstPage
+
+	// Meanwhile, other calls neither wait for the list, nor use its context
+	if _, err := c.GetRepo(context.Background(), "my-org", "baz"); err != nil {
+		t.Errorf("GetRepo() error = %v, want nil", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.GetRepo(ctx, "my-org", "baz"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetRepo() with a canceled context error = %v, want %v", err, context.Canceled)
+	}
+	close(proceed)
+
+	if err := <-listErr; err != nil {
+		t.Errorf("ListOrgRepos() error = %v, want nil", err)
+	}
+}
+
+func Test_pageInfoFromResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		page   int
+		resp   gitea.Response
+		header http.Header
+		want   gitprovider.PageInfo
+	}{
+		{
+			name:   "first page",
+			page:   1,
+			resp:   gitea.Response{NextPage: 2, LastPage: 4},
+			header: http.Header{"X-Total-Count": []string{"100"}},
+			want:   gitprovider.PageInfo{NextPage: 2, TotalCount: 100, TotalPages: 4},
+		},
+		{
+			name: "last page, no total count",
+			page: 3,
+			want: gitprovider.PageInfo{TotalCount: gitprovider.UnknownCount, TotalPages: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.resp.Response = &http.Response{Header: tt.header}
+			if got := pageInfoFromResponse(tt.page, &tt.resp); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pageInfoFromResponse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+
+	"code.gitea.io/sdk/gitea"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+func newOrganization(ctx *clientContext, apiObj *gitea.Organization, ref gitprovider.OrganizationRef) *organization {
+	return &organization{
+		clientContext: ctx,
+		o:             *apiObj,
+		ref:           ref,
+		teams: &TeamsClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		actionsSecrets: &OrgActionsSecretsClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+	}
+}
+
+var _ gitprovider.Organization = &organization{}
+
+type organization struct {
+	*clientContext
+
+	o   gitea.Organization
+	ref gitprovider.OrganizationRef
+
+	teams          *TeamsClient
+	actionsSecrets *OrgActionsSecretsClient
+}
+
+func (o *organization) Get() gitprovider.OrganizationInfo {
+	return organizationFromAPI(&o.o)
+}
+
+// GetMetadata fetches the profile of the organization. Gitea doesn't supply the member count
+// of organizations, hence it is nil.
+//
+// The internal API object will be overridden with the received server data.
+func (o *organization) GetMetadata(ctx context.Context) (gitprovider.OrganizationMetadata, error) {
+	// GET /orgs/{org}
+	apiObj, err := o.c.GetOrg(ctx, o.ref.Organization)
+	if err != nil {
+		return gitprovider.OrganizationMetadata{}, err
+	}
+	o.o = *apiObj
+	return organizationMetadataFromAPI(apiObj), nil
+}
+
+// SetMetadata updates the profile of the organization.
+//
+// This is not supported in Gitea.
+func (o *organization) SetMetadata(_ context.Context, _ gitprovider.OrganizationMetadata) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+func (o *organization) APIObject() interface{} {
+	return &o.o
+}
+
+func (o *organization) Organization() gitprovider.OrganizationRef {
+	return o.ref
+}
+
+func (o *organization) Teams() gitprovider.TeamsClient {
+	return o.teams
+}
+
+func (o *organization) ActionsSecrets() gitprovider.OrgActionsSecretsClient {
+	return o.actionsSecrets
+}
+
+// StreamAuditLog calls fn for each event in the audit log of this organization.
+//
+// This is not supported in Gitea, which has no audit log.
+func (o *organization) StreamAuditLog(_ context.Context, _ gitprovider.AuditLogOptions, _ func(gitprovider.AuditEvent) error) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// GetDefaultBranchName returns the name of the initial branch of new repositories in this organization.
+//
+// This is not supported in Gitea, where the setting is instance-wide.
+func (o *organization) GetDefaultBranchName(_ context.Context) (string, error) {
+	return "", gitprovider.ErrNoProviderSupport
+}
+
+// SetDefaultBranchName sets the name of the initial branch of new repositories in this organization.
+//
+// This is not supported in Gitea, where the setting is instance-wide.
+func (o *organization) SetDefaultBranchName(_ context.Context, _ string) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// SetRepositoryCreationLevel sets who can create repositories in this organization.
+//
+// This is not supported in Gitea, where this is a permission of each team.
+func (o *organization) SetRepositoryCreationLevel(_ context.Context, _ gitprovider.RepositoryCreationLevel) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// ListPATRequests lists the pending requests to access this organization with a fine-grained
+// personal access token.
+//
+// This is not supported in Gitea, which has no approval flow for access tokens.
+func (o *organization) ListPATRequests(_ context.Context) ([]gitprovider.PATRequest, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// ApprovePATRequest approves the pending fine-grained personal access token request with the given ID.
+//
+// This is not supported in Gitea, which has no approval flow for access tokens.
+func (o *organization) ApprovePATRequest(_ context.Context, _ int64) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// DenyPATRequest denies the pending fine-grained personal access token request with the given ID.
+//
+// This is not supported in Gitea, which has no approval flow for access tokens.
+func (o *organization) DenyPATRequest(_ context.Context, _ int64) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+func organizationFromAPI(apiObj *gitea.Organization) gitprovider.OrganizationInfo {
+	return gitprovider.OrganizationInfo{
+		Name:        &apiObj.FullName,
+		Description: &apiObj.Description,
+	}
+}
+
+func organizationMetadataFromAPI(apiObj *gitea.Organization) gitprovider.OrganizationMetadata {
+	return gitprovider.OrganizationMetadata{
+		DisplayName: &apiObj.FullName,
+		Description: &apiObj.Description,
+		AvatarURL:   &apiObj.AvatarURL,
+		Website:     &apiObj.Website,
+	}
+}
+
+// validateOrganizationAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateOrganizationAPI(apiObj *gitea.Organization) error {
+	return validateAPIObject("Gitea.Organization", func(validator validation.Validator) {
+		if len(apiObj.UserName) == 0 {
+			validator.Required("UserName")
+		}
+	})
+}
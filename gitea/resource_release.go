@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"code.gitea.io/sdk/gitea"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+func releaseFromAPI(apiObj *gitea.Release) gitprovider.ReleaseInfo {
+	return gitprovider.ReleaseInfo{
+		TagName:         apiObj.TagName,
+		TargetCommitish: gitprovider.StringVar(apiObj.Target),
+		Name:            gitprovider.StringVar(apiObj.Title),
+		Body:            gitprovider.StringVar(apiObj.Note),
+		Draft:           gitprovider.BoolVar(apiObj.IsDraft),
+		Prerelease:      gitprovider.BoolVar(apiObj.IsPrerelease),
+	}
+}
+
+// validateReleaseAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateReleaseAPI(apiObj *gitea.Release) error {
+	return validateAPIObject("Gitea.Release", func(validator validation.Validator) {
+		if len(apiObj.TagName) == 0 {
+			validator.Required("TagName")
+		}
+	})
+}
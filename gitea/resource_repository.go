@@ -0,0 +1,586 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// licenseNames maps the license templates to the names of the licenses bundled with Gitea.
+var licenseNames = map[gitprovider.LicenseTemplate]string{
+	gitprovider.LicenseTemplateApache2: "Apache-2.0",
+	gitprovider.LicenseTemplateMIT:     "MIT",
+	gitprovider.LicenseTemplateGPL3:    "GPL-3.0",
+}
+
+func newUserRepository(ctx *clientContext, apiObj *gitea.Repository, ref gitprovider.RepositoryRef) *userRepository {
+	return &userRepository{
+		clientContext: ctx,
+		r:             *apiObj,
+		ref:           ref,
+		deployKeys: &DeployKeyClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		releases: &ReleaseClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		commits: &CommitClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branches: &BranchClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		tags: &TagClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		issues: &IssueClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		hooks: &RepositoryHookClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		rulesets: &RulesetClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branchProtection: &BranchProtectionClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+	}
+}
+
+var _ gitprovider.UserRepository = &userRepository{}
+
+type userRepository struct {
+	*clientContext
+
+	r   gitea.Repository
+	ref gitprovider.RepositoryRef
+
+	deployKeys *DeployKeyClient
+	releases   *ReleaseClient
+	commits    *CommitClient
+	branches   *BranchClient
+	tags       *TagClient
+	issues     *IssueClient
+	hooks      *RepositoryHookClient
+	rulesets   *RulesetClient
+
+	branchProtection *BranchProtectionClient
+}
+
+func (r *userRepository) Get() gitprovider.RepositoryInfo {
+	return repositoryFromAPI(&r.r)
+}
+
+func (r *userRepository) Set(info gitprovider.RepositoryInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	if err := validateVisibility(info.Visibility); err != nil {
+		return err
+	}
+	repositoryInfoToAPIObj(&info, &r.r)
+	return nil
+}
+
+func (r *userRepository) APIObject() interface{} {
+	return &r.r
+}
+
+func (r *userRepository) Repository() gitprovider.RepositoryRef {
+	return r.ref
+}
+
+func (r *userRepository) DeployKeys() gitprovider.DeployKeyClient {
+	return r.deployKeys
+}
+
+func (r *userRepository) Releases() gitprovider.ReleaseClient {
+	return r.releases
+}
+
+func (r *userRepository) Commits() gitprovider.CommitClient {
+	return r.commits
+}
+
+func (r *userRepository) Branches() gitprovider.BranchClient {
+	return r.branches
+}
+
+func (r *userRepository) Tags() gitprovider.TagClient {
+	return r.tags
+}
+
+func (r *userRepository) Issues() gitprovider.IssueClient {
+	return r.issues
+}
+
+func (r *userRepository) Hooks() gitprovider.RepositoryHookClient {
+	return r.hooks
+}
+
+func (r *userRepository) Rulesets() gitprovider.RulesetClient {
+	return r.rulesets
+}
+
+func (r *userRepository) BranchProtection() gitprovider.BranchProtectionClient {
+	return r.branchProtection
+}
+
+// Update will apply the desired state in this object to the server.
+// Only set fields will be respected (i.e. PATCH behaviour).
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (r *userRepository) Update(ctx context.Context) error {
+	// PATCH /repos/{owner}/{repo}
+	apiObj, err := r.c.EditRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), repositoryEditOption(&r.r))
+	if err != nil {
+		return err
+	}
+	r.r = *apiObj
+	return nil
+}
+
+// Refresh fetches the current state of this repository from the server, without
+// mutating anything. Any local changes that weren't applied are discarded.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (r *userRepository) Refresh(ctx context.Context) error {
+	// GET /repos/{owner}/{repo}
+	apiObj, err := r.c.GetRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return err
+	}
+	r.r = *apiObj
+	return nil
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (r *userRepository) Reconcile(ctx context.Context) (bool, error) {
+	// GET /repos/{owner}/{repo}
+	apiObj, err := r.c.GetRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			orgName := ""
+			if r.ref.GetType() == gitprovider.IdentityTypeOrganization {
+				orgName = r.ref.GetIdentity()
+			}
+			// POST /user/repos or POST /orgs/{org}/repos
+			repo, err := r.c.CreateRepo(ctx, orgName, repositoryCreateOption(&r.r))
+			if err != nil {
+				return true, err
+			}
+			r.r = *repo
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	// Use wrappers here to extract the "spec" part of the object for comparison
+	desiredSpec := newGiteaRepositorySpec(&r.r)
+	actualSpec := newGiteaRepositorySpec(apiObj)
+
+	// If desired state already is the actual state, do nothing
+	if desiredSpec.Equals(actualSpec) {
+		return false, nil
+	}
+	// Otherwise, make the desired state the actual state
+	return true, r.Update(ctx)
+}
+
+// Delete deletes the current resource irreversibly.
+//
+// ErrNotFound is returned if the resource doesn't exist anymore.
+func (r *userRepository) Delete(ctx context.Context) error {
+	// DELETE /repos/{owner}/{repo}
+	return r.c.DeleteRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+}
+
+// ListSecurityAdvisories lists the security advisories filed for this repository.
+//
+// This is not supported in Gitea.
+func (r *userRepository) ListSecurityAdvisories(_ context.Context, _ gitprovider.SecurityAdvisoryListOptions) ([]gitprovider.SecurityAdvisoryInfo, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// SetPipelineRequirements configures what CI results are required before changes can be merged.
+//
+// This is not supported (yet) in Gitea, where status checks are required through branch protection.
+func (r *userRepository) SetPipelineRequirements(_ context.Context, _ gitprovider.PipelineRequirements) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// SetIssueCloseSettings configures how issues are closed automatically when referenced.
+//
+// This is not supported in Gitea, where referenced issues are always closed on the default branch.
+func (r *userRepository) SetIssueCloseSettings(_ context.Context, _ gitprovider.IssueCloseSettings) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// ListInstalledApps lists the apps that have been granted access to this repository.
+//
+// This is not supported in Gitea.
+func (r *userRepository) ListInstalledApps(_ context.Context) ([]gitprovider.InstalledAppInfo, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// EffectivePermissions returns the permissions the credentials of the client have on this repository.
+//
+// This is not supported in Gitea, which doesn't expose granular permissions.
+func (r *userRepository) EffectivePermissions(_ context.Context) (map[string]string, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// PrimaryLanguage returns the dominant programming language of this repository.
+//
+// This is not supported (yet) in Gitea.
+func (r *userRepository) PrimaryLanguage(_ context.Context) (string, error) {
+	return "", gitprovider.ErrNoProviderSupport
+}
+
+// IsEmpty returns true if the repository has no commits (and hence no branches) yet.
+//
+// ErrNotFound is returned if the repository does not exist.
+func (r *userRepository) IsEmpty(ctx context.Context) (bool, error) {
+	// GET /repos/{owner}/{repo}
+	apiObj, err := r.c.GetRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return false, err
+	}
+	return apiObj.Empty, nil
+}
+
+// GetMergeQueue returns the merge queue settings of the given branch.
+//
+// This is not supported in Gitea.
+func (r *userRepository) GetMergeQueue(_ context.Context, _ string) (gitprovider.MergeQueueInfo, error) {
+	return gitprovider.MergeQueueInfo{}, gitprovider.ErrNoProviderSupport
+}
+
+// SetMergeQueue configures the merge queue of the given branch.
+//
+// This is not supported in Gitea.
+func (r *userRepository) SetMergeQueue(_ context.Context, _ string, _ gitprovider.MergeQueueInfo) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// TriggerMirrorSync starts syncing this mirror repository with its upstream repository.
+//
+// This is not supported in Gitea.
+func (r *userRepository) TriggerMirrorSync(_ context.Context) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// GetMirrorStatus returns the state of the last sync of this mirror repository.
+//
+// This is not supported in Gitea.
+func (r *userRepository) GetMirrorStatus(_ context.Context) (gitprovider.MirrorStatusInfo, error) {
+	return gitprovider.MirrorStatusInfo{}, gitprovider.ErrNoProviderSupport
+}
+
+// SetMergeTrain enables or disables merge trains.
+//
+// This is not supported in Gitea.
+func (r *userRepository) SetMergeTrain(_ context.Context, _ bool) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// SetWebCommitSigning configures whether unsigned commits are rejected for all branches.
+//
+// This is not supported (yet) in Gitea, where signed commits are required per branch, through
+// its branch protection rules.
+func (r *userRepository) SetWebCommitSigning(_ context.Context, _ bool) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// CountOpenIssues returns the amount of open issues in this repository, or 0 if the issue
+// tracker is disabled.
+func (r *userRepository) CountOpenIssues(ctx context.Context) (int64, error) {
+	// GET /repos/{owner}/{repo}
+	apiObj, err := r.c.GetRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return 0, err
+	}
+	if !apiObj.HasIssues {
+		return 0, nil
+	}
+	return int64(apiObj.OpenIssues), nil
+}
+
+// CountOpenPullRequests returns the amount of open pull requests in this repository, or 0 if
+// pull requests are disabled.
+func (r *userRepository) CountOpenPullRequests(ctx context.Context) (int64, error) {
+	// GET /repos/{owner}/{repo}
+	apiObj, err := r.c.GetRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return 0, err
+	}
+	if !apiObj.HasPullRequests {
+		return 0, nil
+	}
+	return int64(apiObj.OpenPulls), nil
+}
+
+// ListProtectedEnvironments lists the protected environments of this repository.
+//
+// This is not supported in Gitea, which has no deployment environments.
+func (r *userRepository) ListProtectedEnvironments(_ context.Context) ([]gitprovider.ProtectedEnvironmentInfo, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// ProtectEnvironment makes sure only the roles in req can deploy to the environment.
+//
+// This is not supported in Gitea, which has no deployment environments.
+func (r *userRepository) ProtectEnvironment(_ context.Context, _ gitprovider.ProtectedEnvironmentInfo) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// UnprotectEnvironment removes the protection of the given environment.
+//
+// This is not supported in Gitea, which has no deployment environments.
+func (r *userRepository) UnprotectEnvironment(_ context.Context, _ string) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// SetSubscription configures what notifications the authenticated user gets about this repository.
+//
+// This is not supported (yet) in Gitea.
+func (r *userRepository) SetSubscription(_ context.Context, _ gitprovider.SubscriptionInfo) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// GetCommunityHealthFile returns the content of the given community health file on the default branch.
+//
+// This is not supported (yet) in Gitea.
+func (r *userRepository) GetCommunityHealthFile(_ context.Context, _ gitprovider.CommunityHealthFile) (string, error) {
+	return "", gitprovider.ErrNoProviderSupport
+}
+
+// SetCommunityHealthFile commits content as the given community health file to the default branch.
+//
+// This is not supported (yet) in Gitea.
+func (r *userRepository) SetCommunityHealthFile(_ context.Context, _ gitprovider.CommunityHealthFile, _ string) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// CommunityHealthScore returns the community health score of this repository.
+//
+// This is not supported (yet) in Gitea.
+func (r *userRepository) CommunityHealthScore(_ context.Context) (gitprovider.CommunityHealthInfo, error) {
+	return gitprovider.CommunityHealthInfo{}, gitprovider.ErrNoProviderSupport
+}
+
+func newOrgRepository(ctx *clientContext, apiObj *gitea.Repository, ref gitprovider.RepositoryRef) *orgRepository {
+	return &orgRepository{
+		userRepository: *newUserRepository(ctx, apiObj, ref),
+		teamAccess: &TeamAccessClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+	}
+}
+
+var _ gitprovider.OrgRepository = &orgRepository{}
+
+type orgRepository struct {
+	userRepository
+
+	teamAccess *TeamAccessClient
+}
+
+func (r *orgRepository) TeamAccess() gitprovider.TeamAccessClient {
+	return r.teamAccess
+}
+
+// validateVisibility makes sure the visibility, if set, exists in Gitea.
+func validateVisibility(visibility *gitprovider.RepositoryVisibility) error {
+	if visibility != nil && *visibility == gitprovider.RepositoryVisibilityInternal {
+		return fmt.Errorf("gitea doesn't support internal repositories: %w", gitprovider.ErrNoProviderSupport)
+	}
+	return nil
+}
+
+// validateRepositoryAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateRepositoryAPI(apiObj *gitea.Repository) error {
+	return validateAPIObject("Gitea.Repository", func(validator validation.Validator) {
+		// Make sure the name is set
+		if len(apiObj.Name) == 0 {
+			validator.Required("Name")
+		}
+	})
+}
+
+func repositoryFromAPI(apiObj *gitea.Repository) gitprovider.RepositoryInfo {
+	repo := gitprovider.RepositoryInfo{
+		Description: &apiObj.Description,
+		HasIssues:   &apiObj.HasIssues,
+		HasWiki:     &apiObj.HasWiki,
+		HasProjects: &apiObj.HasProjects,
+	}
+	// Empty repositories may have no default branch yet
+	if len(apiObj.DefaultBranch) != 0 {
+		repo.DefaultBranch = &apiObj.DefaultBranch
+	}
+	if apiObj.Private {
+		repo.Visibility = gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPrivate)
+	} else {
+		repo.Visibility = gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic)
+	}
+	return repo
+}
+
+func repositoryToAPI(repo *gitprovider.RepositoryInfo, ref gitprovider.RepositoryRef) gitea.Repository {
+	apiObj := gitea.Repository{
+		Name: ref.GetRepository(),
+	}
+	repositoryInfoToAPIObj(repo, &apiObj)
+	return apiObj
+}
+
+func repositoryInfoToAPIObj(repo *gitprovider.RepositoryInfo, apiObj *gitea.Repository) {
+	if repo.Description != nil {
+		apiObj.Description = *repo.Description
+	}
+	if repo.DefaultBranch != nil {
+		apiObj.DefaultBranch = *repo.DefaultBranch
+	}
+	if repo.Visibility != nil {
+		apiObj.Private = *repo.Visibility == gitprovider.RepositoryVisibilityPrivate
+	}
+	if repo.HasIssues != nil {
+		apiObj.HasIssues = *repo.HasIssues
+	}
+	if repo.HasWiki != nil {
+		apiObj.HasWiki = *repo.HasWiki
+	}
+	if repo.HasProjects != nil {
+		apiObj.HasProjects = *repo.HasProjects
+	}
+	// AllowForking has no Gitea equivalent, and is ignored
+}
+
+// repositoryCreateOption returns the fields of apiObj that can be set when creating a repository.
+func repositoryCreateOption(apiObj *gitea.Repository) *gitea.CreateRepoOption {
+	return &gitea.CreateRepoOption{
+		Name:          apiObj.Name,
+		Description:   apiObj.Description,
+		Private:       apiObj.Private,
+		DefaultBranch: apiObj.DefaultBranch,
+	}
+}
+
+// applyRepoCreateOptions applies the create options to the request.
+func applyRepoCreateOptions(req *gitea.CreateRepoOption, opts gitprovider.RepositoryCreateOptions) {
+	if opts.AutoInit != nil {
+		req.AutoInit = *opts.AutoInit
+	}
+	if opts.LicenseTemplate != nil {
+		req.License = licenseNames[*opts.LicenseTemplate]
+	}
+}
+
+// repositoryEditOption returns the fields of apiObj that can be updated. The default branch
+// is left out if it is unknown, which is the case for empty repositories.
+func repositoryEditOption(apiObj *gitea.Repository) *gitea.EditRepoOption {
+	req := &gitea.EditRepoOption{
+		Name:        gitprovider.StringVar(apiObj.Name),
+		Description: gitprovider.StringVar(apiObj.Description),
+		Private:     gitprovider.BoolVar(apiObj.Private),
+		HasIssues:   gitprovider.BoolVar(apiObj.HasIssues),
+		HasWiki:     gitprovider.BoolVar(apiObj.HasWiki),
+		HasProjects: gitprovider.BoolVar(apiObj.HasProjects),
+	}
+	if len(apiObj.DefaultBranch) != 0 {
+		req.DefaultBranch = gitprovider.StringVar(apiObj.DefaultBranch)
+	}
+	return req
+}
+
+// This function copies over the fields that are part of create/update requests of a repository
+// i.e. the desired spec of the repository. This allows us to separate "spec" from "status" fields.
+// Fields that Gitea canonicalizes server-side are normalized, see RepositoryInfo.Normalized().
+func newGiteaRepositorySpec(repo *gitea.Repository) *giteaRepositorySpec {
+	return &giteaRepositorySpec{
+		&gitea.Repository{
+			// Generic
+			Name:        repo.Name,
+			Description: gitprovider.NormalizeDescription(repo.Description),
+			Private:     repo.Private,
+			HasIssues:   repo.HasIssues,
+			HasWiki:     repo.HasWiki,
+			HasProjects: repo.HasProjects,
+
+			// Update-specific parameters
+			DefaultBranch: gitprovider.NormalizeBranchName(repo.DefaultBranch),
+		},
+	}
+}
+
+type giteaRepositorySpec struct {
+	*gitea.Repository
+}
+
+func (s *giteaRepositorySpec) Equals(other *giteaRepositorySpec) bool {
+	return reflect.DeepEqual(s, other)
+}
+
+// repositoryMatchesFilter applies the filters of RefListFilter, which Gitea can't apply
+// server-side. Languages aren't returned by the Gitea SDK, hence that filter must not be set.
+func repositoryMatchesFilter(apiObj *gitea.Repository, filter gitprovider.RefListFilter) bool {
+	if filter.Visibility != nil && *repositoryFromAPI(apiObj).Visibility != *filter.Visibility {
+		return false
+	}
+	if filter.Archived != nil && apiObj.Archived != *filter.Archived {
+		return false
+	}
+	return strings.HasPrefix(apiObj.Name, filter.NamePrefix)
+}
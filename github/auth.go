@@ -19,6 +19,7 @@ package github
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/go-github/v32/github"
 	"golang.org/x/oauth2"
@@ -30,8 +31,22 @@ import (
 const (
 	// DefaultDomain specifies the default domain used as the backend.
 	DefaultDomain = "github.com"
+
+	// enterpriseAPIPath is the path of the REST API on a GitHub Enterprise instance.
+	enterpriseAPIPath = "/api/v3"
+	// enterpriseUploadPath is the path of the upload API on a GitHub Enterprise instance.
+	enterpriseUploadPath = "/api/uploads"
 )
 
+// rateLimitHeaders are the headers GitHub reports the rate limit of the client in.
+// See: https://docs.github.com/en/rest/overview/resources-in-the-rest-api#rate-limiting
+//nolint:gochecknoglobals
+var rateLimitHeaders = gitprovider.RateLimitHeaders{
+	Limit:     "X-RateLimit-Limit",
+	Remaining: "X-RateLimit-Remaining",
+	Reset:     "X-RateLimit-Reset",
+}
+
 // ClientOption is the interface to implement for passing options to NewClient.
 // The clientOptions struct is private to force usage of the With... functions.
 type ClientOption interface {
@@ -52,6 +67,19 @@ type clientOptions struct {
 	// See: https://developer.github.com/v3/#conditional-requests for more info.
 	// Default: false
 	EnableConditionalRequests *bool
+
+	// Retry holds the settings for retrying failed requests, see WithRetry and gitprovider.RetryTransport.
+	// Default: nil (which means requests aren't retried)
+	Retry *retryOptions
+}
+
+// retryOptions are the settings given to WithRetry or WithCustomRetry.
+type retryOptions struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	// ShouldRetry is the predicate given to WithCustomRetry. If nil, gitprovider.DefaultRetryPredicate
+	// is used, or gitprovider.RetryOnStatusCodes if RetryableStatusCodes is set.
+	ShouldRetry gitprovider.RetryPredicate
 }
 
 // ApplyToGithubClientOptions implements ClientOption, and applies the set fields of opts
@@ -77,6 +105,14 @@ func (opts *clientOptions) ApplyToGithubClientOptions(target *clientOptions) err
 		}
 		target.EnableConditionalRequests = opts.EnableConditionalRequests
 	}
+
+	if opts.Retry != nil {
+		// Make sure the user didn't specify the Retry twice
+		if target.Retry != nil {
+			return fmt.Errorf("option Retry already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.Retry = opts.Retry
+	}
 	return nil
 }
 
@@ -86,6 +122,18 @@ func (opts *clientOptions) getTransportChain() (chain []gitprovider.ChainableRou
 	if opts.PostChainTransportHook != nil {
 		chain = append(chain, opts.PostChainTransportHook)
 	}
+	if userAgentTransport := opts.UserAgentTransport(); userAgentTransport != nil {
+		chain = append(chain, userAgentTransport)
+	}
+	if requestTimeoutTransport := opts.RequestTimeoutTransport(); requestTimeoutTransport != nil {
+		chain = append(chain, requestTimeoutTransport)
+	}
+	if rateLimitTransport := opts.RateLimitTransport(rateLimitHeaders); rateLimitTransport != nil {
+		chain = append(chain, rateLimitTransport)
+	}
+	if opts.Retry != nil {
+		chain = append(chain, opts.retryTransport())
+	}
 	if opts.AuthTransport != nil {
 		chain = append(chain, opts.AuthTransport)
 	}
@@ -100,6 +148,16 @@ func (opts *clientOptions) getTransportChain() (chain []gitprovider.ChainableRou
 	return
 }
 
+// retryTransport builds the gitprovider.RetryTransport configured by opts.Retry, retrying
+// the RetryableStatusCodes if set, and limited by the RetryBudget if set.
+func (opts *clientOptions) retryTransport() gitprovider.ChainableRoundTripperFunc {
+	shouldRetry := opts.Retry.ShouldRetry
+	if shouldRetry == nil && opts.RetryableStatusCodes != nil {
+		shouldRetry = gitprovider.RetryOnStatusCodes(opts.RetryableStatusCodes)
+	}
+	return gitprovider.RetryTransport(opts.Retry.MaxRetries, opts.Retry.BaseDelay, shouldRetry, opts.RetryBudget)
+}
+
 // buildCommonOption is a helper for returning a ClientOption out of a common option field.
 func buildCommonOption(opt gitprovider.CommonClientOptions) *clientOptions {
 	return &clientOptions{CommonClientOptions: opt}
@@ -125,11 +183,19 @@ func optionError(err error) ClientOption {
 //
 
 // WithDomain initializes a Client for a custom GitHub Enterprise instance of the given domain.
-// Only host and port information should be present in domain. domain must not be an empty string.
+// domain consists of the host, an optional port, and the path the instance is installed under, if
+// any, e.g. "ghe.example.com/github". The base URL of the API, e.g. "https://ghe.example.com/api/v3",
+// is accepted too. domain must not be an empty string. See gitprovider.NormalizeBaseURL.
 func WithDomain(domain string) ClientOption {
 	return buildCommonOption(gitprovider.CommonClientOptions{Domain: &domain})
 }
 
+// WithDefaultOrganization makes repository operations given a ref without an organization, i.e.
+// only a repository name, use the given organization. The domain of org must match the client's.
+func WithDefaultOrganization(org gitprovider.OrganizationRef) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{DefaultOrganization: &org})
+}
+
 // WithDestructiveAPICalls tells the client whether it's allowed to do dangerous and possibly destructive
 // actions, like e.g. deleting a repository.
 func WithDestructiveAPICalls(destructiveActions bool) ClientOption {
@@ -158,6 +224,63 @@ func WithPostChainTransportHook(postRoundTripperFunc gitprovider.ChainableRoundT
 	return buildCommonOption(gitprovider.CommonClientOptions{PostChainTransportHook: postRoundTripperFunc})
 }
 
+// WithRequestTimeout limits every single request to GitHub to the given duration, including reading
+// the response body, e.g. so that a hung connection can't block a reconcile forever. A shorter
+// deadline of the caller's context is kept. d must be positive. See gitprovider.RequestTimeoutTransport.
+func WithRequestTimeout(d time.Duration) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{RequestTimeout: &d})
+}
+
+// WithUserAgent sets the User-Agent header of every request to GitHub to userAgent, e.g. so that
+// the administrators of a self-hosted instance can identify the client's traffic in their logs.
+// userAgent must not be an empty string. See gitprovider.UserAgentTransport.
+func WithUserAgent(userAgent string) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{UserAgent: &userAgent})
+}
+
+// WithRateLimitHandler calls handler with the rate limit GitHub reports in the X-RateLimit-* headers
+// of every response, e.g. to throttle requests before the limit is exhausted. handler must not be nil.
+// See gitprovider.RateLimitTransport for more information.
+func WithRateLimitHandler(handler func(gitprovider.RateLimitInfo)) ClientOption {
+	// Don't allow an empty value
+	if handler == nil {
+		return optionError(fmt.Errorf("handler cannot be nil: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return buildCommonOption(gitprovider.CommonClientOptions{RateLimitHandler: handler})
+}
+
+// WithRateLimitBlocking makes requests wait until the rate limit resets, once GitHub reported
+// that no requests remain, instead of failing with a gitprovider.RateLimitError.
+func WithRateLimitBlocking(block bool) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{BlockOnRateLimit: &block})
+}
+
+// WithRetryableStatusCodes makes WithRetry retry responses with the given status codes, instead of
+// 429 Too Many Requests, 5xx, and 403 Forbidden with a Retry-After header. This is useful e.g. behind a
+// proxy signaling throttling with a non-standard status code. The codes must be valid HTTP status codes.
+// It can't be combined with WithCustomRetry, whose predicate decides what to retry on its own.
+func WithRetryableStatusCodes(codes ...int) ClientOption {
+	// Don't allow invalid values
+	if err := gitprovider.ValidateRetryableStatusCodes(codes); err != nil {
+		return optionError(fmt.Errorf("%v: %w", err, gitprovider.ErrInvalidClientOptions))
+	}
+
+	return buildCommonOption(gitprovider.CommonClientOptions{RetryableStatusCodes: codes})
+}
+
+// WithRetryBudget limits the amount of retries made by WithRetry or WithCustomRetry across all
+// requests of the client. The budget allows size retries in a burst, and is refilled with
+// refillPerSecond retries per second. When the budget is exhausted, the last response of a failed
+// request is returned immediately. size must be positive, and refillPerSecond must not be negative.
+func WithRetryBudget(size int, refillPerSecond float64) ClientOption {
+	budget, err := gitprovider.NewRetryBudget(size, refillPerSecond)
+	if err != nil {
+		return optionError(fmt.Errorf("%v: %w", err, gitprovider.ErrInvalidClientOptions))
+	}
+	return buildCommonOption(gitprovider.CommonClientOptions{RetryBudget: budget})
+}
+
 //
 // GitHub-specific options
 //
@@ -192,6 +315,39 @@ func WithConditionalRequests(conditionalRequests bool) ClientOption {
 	return &clientOptions{EnableConditionalRequests: &conditionalRequests}
 }
 
+// WithRetry retries idempotent requests (e.g. GET) up to maxRetries times when they fail transiently,
+// e.g. with 502 Bad Gateway, or because of a secondary rate limit. The delay before the first retry is
+// baseDelay, and it doubles for each further retry, unless GitHub specifies a delay through the Retry-After
+// header. The retried status codes can be customized using WithRetryableStatusCodes.
+// See gitprovider.RetryTransport and gitprovider.DefaultRetryPredicate for more information.
+func WithRetry(maxRetries int, baseDelay time.Duration) ClientOption {
+	return retryOption(maxRetries, baseDelay, nil)
+}
+
+// WithCustomRetry works like WithRetry, but retries the requests shouldRetry allows, e.g. to also
+// retry POST requests to endpoints that are known to be idempotent. shouldRetry must not be nil.
+func WithCustomRetry(maxRetries int, baseDelay time.Duration, shouldRetry gitprovider.RetryPredicate) ClientOption {
+	// Don't allow an empty value
+	if shouldRetry == nil {
+		return optionError(fmt.Errorf("shouldRetry cannot be nil: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return retryOption(maxRetries, baseDelay, shouldRetry)
+}
+
+// retryOption validates the settings shared by WithRetry and WithCustomRetry.
+func retryOption(maxRetries int, baseDelay time.Duration, shouldRetry gitprovider.RetryPredicate) ClientOption {
+	// Don't allow invalid values
+	if maxRetries < 0 {
+		return optionError(fmt.Errorf("maxRetries cannot be negative, got %d: %w", maxRetries, gitprovider.ErrInvalidClientOptions))
+	}
+	if baseDelay < 0 {
+		return optionError(fmt.Errorf("baseDelay cannot be negative, got %v: %w", baseDelay, gitprovider.ErrInvalidClientOptions))
+	}
+
+	return &clientOptions{Retry: &retryOptions{MaxRetries: maxRetries, BaseDelay: baseDelay, ShouldRetry: shouldRetry}}
+}
+
 // makeOptions assembles a clientOptions struct from ClientOption mutator functions.
 func makeOptions(opts ...ClientOption) (*clientOptions, error) {
 	o := &clientOptions{}
@@ -200,6 +356,16 @@ func makeOptions(opts ...ClientOption) (*clientOptions, error) {
 			return nil, err
 		}
 	}
+
+	// The retryable status codes are only used by the retry predicate of WithRetry
+	if o.RetryableStatusCodes != nil && (o.Retry == nil || o.Retry.ShouldRetry != nil) {
+		return nil, fmt.Errorf("option RetryableStatusCodes requires WithRetry, and can't be combined with WithCustomRetry: %w",
+			gitprovider.ErrInvalidClientOptions)
+	}
+	// The retry budget only limits the retries of WithRetry or WithCustomRetry
+	if o.RetryBudget != nil && o.Retry == nil {
+		return nil, fmt.Errorf("option RetryBudget requires WithRetry or WithCustomRetry: %w", gitprovider.ErrInvalidClientOptions)
+	}
 	return o, nil
 }
 
@@ -211,13 +377,17 @@ func makeOptions(opts ...ClientOption) (*clientOptions, error) {
 // Password-based authentication is not supported because it is deprecated by GitHub, see
 // https://developer.github.com/changes/2020-02-14-deprecating-password-auth/
 //
-// GitHub Enterprise can be used if you specify the domain using WithDomain.
+// GitHub Enterprise can be used if you specify the domain using WithDomain. The domain is normalized,
+// e.g. "https://ghe.example.com/github/api/v3/" becomes "ghe.example.com/github", which is then
+// the SupportedDomain of the client.
 //
 // You can customize low-level HTTP Transport functionality by using the With{Pre,Post}ChainTransportHook options.
-// You can also use conditional requests (and an in-memory cache) using WithConditionalRequests.
+// You can also use conditional requests (and an in-memory cache) using WithConditionalRequests,
+// and retry transiently failing requests using WithRetry. The rate limit can be observed using
+// WithRateLimitHandler, and waited for using WithRateLimitBlocking.
 //
 // The chain of transports looks like this:
-// github.com API <-> "Post Chain" <-> Authentication <-> Cache <-> "Pre Chain" <-> *github.Client.
+// github.com API <-> "Post Chain" <-> User Agent <-> Request Timeout <-> Rate Limit <-> Retry <-> Authentication <-> Cache <-> "Pre Chain" <-> *github.Client.
 func NewClient(optFns ...ClientOption) (gitprovider.Client, error) {
 	// Complete the options struct
 	opts, err := makeOptions(optFns...)
@@ -237,17 +407,24 @@ func NewClient(optFns ...ClientOption) (gitprovider.Client, error) {
 	var gh *github.Client
 	var domain string
 
-	if opts.Domain == nil || *opts.Domain == DefaultDomain {
+	var baseURL gitprovider.BaseURL
+	if opts.Domain != nil {
+		if baseURL, err = gitprovider.NormalizeBaseURL(*opts.Domain, enterpriseAPIPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Domain == nil || baseURL.Domain() == DefaultDomain {
 		// No domain or the default github.com used
 		domain = DefaultDomain
 		gh = github.NewClient(httpClient)
 	} else {
-		// GitHub Enterprise is used
-		domain = *opts.Domain
-		baseURL := fmt.Sprintf("https://%s/api/v3/", domain)
-		uploadURL := fmt.Sprintf("https://%s/api/uploads/", domain)
+		// GitHub Enterprise is used, possibly installed under a path
+		domain = baseURL.Domain()
+		apiURL := baseURL.URL(enterpriseAPIPath + "/")
+		uploadURL := baseURL.URL(enterpriseUploadPath + "/")
 
-		if gh, err = github.NewEnterpriseClient(baseURL, uploadURL, httpClient); err != nil {
+		if gh, err = github.NewEnterpriseClient(apiURL, uploadURL, httpClient); err != nil {
 			return nil, err
 		}
 	}
@@ -257,5 +434,11 @@ func NewClient(optFns ...ClientOption) (gitprovider.Client, error) {
 		destructiveActions = *opts.EnableDestructiveAPICalls
 	}
 
-	return newClient(gh, domain, destructiveActions), nil
+	// Make sure the default organization, if set, is in the domain of the client
+	if opts.DefaultOrganization != nil && opts.DefaultOrganization.Domain != domain {
+		return nil, fmt.Errorf("default organization domain %q doesn't match the client domain %q: %w",
+			opts.DefaultOrganization.Domain, domain, gitprovider.ErrInvalidClientOptions)
+	}
+
+	return newClient(gh, domain, destructiveActions, opts.DefaultOrganization), nil
 }
@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
 	"github.com/dinosk/go-git-providers/gitprovider/cache"
@@ -30,6 +31,8 @@ func dummyRoundTripper1(http.RoundTripper) http.RoundTripper { return nil }
 func dummyRoundTripper2(http.RoundTripper) http.RoundTripper { return nil }
 func dummyRoundTripper3(http.RoundTripper) http.RoundTripper { return nil }
 
+func durationVar(d time.Duration) *time.Duration { return &d }
+
 func roundTrippersEqual(a, b gitprovider.ChainableRoundTripperFunc) bool {
 	if a == nil && b == nil {
 		return true
@@ -47,6 +50,9 @@ func Test_clientOptions_getTransportChain(t *testing.T) {
 		preChain  gitprovider.ChainableRoundTripperFunc
 		postChain gitprovider.ChainableRoundTripperFunc
 		auth      gitprovider.ChainableRoundTripperFunc
+		retry     *retryOptions
+		timeout   *time.Duration
+		userAgent *string
 		cache     bool
 		wantChain []gitprovider.ChainableRoundTripperFunc
 	}{
@@ -64,6 +70,40 @@ func Test_clientOptions_getTransportChain(t *testing.T) {
 				dummyRoundTripper1,
 			},
 		},
+		{
+			name:      "retry between post chain and auth",
+			postChain: dummyRoundTripper1,
+			retry:     &retryOptions{MaxRetries: 3, BaseDelay: time.Second},
+			auth:      dummyRoundTripper2,
+			// expect: "post chain" <-> "retry" <-> "auth"
+			wantChain: []gitprovider.ChainableRoundTripperFunc{
+				dummyRoundTripper1,
+				gitprovider.RetryTransport(3, time.Second, nil, nil),
+				dummyRoundTripper2,
+			},
+		},
+		{
+			name:      "request timeout between post chain and retry",
+			postChain: dummyRoundTripper1,
+			timeout:   durationVar(time.Minute),
+			retry:     &retryOptions{MaxRetries: 3, BaseDelay: time.Second},
+			// expect: "post chain" <-> "request timeout" <-> "retry"
+			wantChain: []gitprovider.ChainableRoundTripperFunc{
+				dummyRoundTripper1,
+				gitprovider.RequestTimeoutTransport(time.Minute),
+				gitprovider.RetryTransport(3, time.Second, nil, nil),
+			},
+		},
+		{
+			name:      "user agent before request timeout",
+			userAgent: gitprovider.StringVar("flux/1.0"),
+			timeout:   durationVar(time.Minute),
+			// expect: "user agent" <-> "request timeout"
+			wantChain: []gitprovider.ChainableRoundTripperFunc{
+				gitprovider.UserAgentTransport("flux/1.0"),
+				gitprovider.RequestTimeoutTransport(time.Minute),
+			},
+		},
 		{
 			name:     "only pre + auth",
 			preChain: dummyRoundTripper1,
@@ -91,8 +131,11 @@ func Test_clientOptions_getTransportChain(t *testing.T) {
 				CommonClientOptions: gitprovider.CommonClientOptions{
 					PreChainTransportHook:  tt.preChain,
 					PostChainTransportHook: tt.postChain,
+					RequestTimeout:         tt.timeout,
+					UserAgent:              tt.userAgent,
 				},
 				AuthTransport:             tt.auth,
+				Retry:                     tt.retry,
 				EnableConditionalRequests: &tt.cache,
 			}
 			gotChain := opts.getTransportChain()
@@ -172,6 +215,120 @@ func Test_makeOptions(t *testing.T) {
 			opts:         []ClientOption{WithConditionalRequests(true), WithConditionalRequests(false)},
 			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
 		},
+		{
+			name: "WithRetry",
+			opts: []ClientOption{WithRetry(3, time.Second)},
+			want: &clientOptions{Retry: &retryOptions{MaxRetries: 3, BaseDelay: time.Second}},
+		},
+		{
+			name:         "WithRetry, negative",
+			opts:         []ClientOption{WithRetry(-1, time.Second)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithCustomRetry, nil predicate",
+			opts:         []ClientOption{WithCustomRetry(3, time.Second, nil)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name: "WithRetryableStatusCodes",
+			opts: []ClientOption{WithRetry(3, time.Second), WithRetryableStatusCodes(420, 503)},
+			want: &clientOptions{
+				CommonClientOptions: gitprovider.CommonClientOptions{RetryableStatusCodes: []int{420, 503}},
+				Retry:               &retryOptions{MaxRetries: 3, BaseDelay: time.Second},
+			},
+		},
+		{
+			name:         "WithRetryableStatusCodes, invalid code",
+			opts:         []ClientOption{WithRetry(3, time.Second), WithRetryableStatusCodes(420, 600)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRetryableStatusCodes, empty",
+			opts:         []ClientOption{WithRetry(3, time.Second), WithRetryableStatusCodes()},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRetryableStatusCodes, without WithRetry",
+			opts:         []ClientOption{WithRetryableStatusCodes(420)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name: "WithRetryableStatusCodes, with WithCustomRetry",
+			opts: []ClientOption{
+				WithCustomRetry(3, time.Second, gitprovider.DefaultRetryPredicate),
+				WithRetryableStatusCodes(420),
+			},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRetryBudget, invalid size",
+			opts:         []ClientOption{WithRetry(3, time.Second), WithRetryBudget(0, 1)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRetryBudget, without WithRetry",
+			opts:         []ClientOption{WithRetryBudget(10, 1)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRetryBudget, exclusive",
+			opts:         []ClientOption{WithRetry(3, time.Second), WithRetryBudget(10, 1), WithRetryBudget(5, 1)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRetry, exclusive",
+			opts:         []ClientOption{WithRetry(3, time.Second), WithRetry(5, time.Second)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name: "WithRequestTimeout",
+			opts: []ClientOption{WithRequestTimeout(time.Minute)},
+			want: buildCommonOption(gitprovider.CommonClientOptions{RequestTimeout: durationVar(time.Minute)}),
+		},
+		{
+			name:         "WithRequestTimeout, not positive",
+			opts:         []ClientOption{WithRequestTimeout(-time.Second)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name: "WithUserAgent",
+			opts: []ClientOption{WithUserAgent("flux/1.0")},
+			want: buildCommonOption(gitprovider.CommonClientOptions{UserAgent: gitprovider.StringVar("flux/1.0")}),
+		},
+		{
+			name:         "WithUserAgent, empty",
+			opts:         []ClientOption{WithUserAgent("")},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithUserAgent, exclusive",
+			opts:         []ClientOption{WithUserAgent("flux/1.0"), WithUserAgent("flux/2.0")},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name: "WithRateLimitBlocking",
+			opts: []ClientOption{WithRateLimitBlocking(true)},
+			want: buildCommonOption(gitprovider.CommonClientOptions{BlockOnRateLimit: gitprovider.BoolVar(true)}),
+		},
+		{
+			name:         "WithRateLimitBlocking, exclusive",
+			opts:         []ClientOption{WithRateLimitBlocking(true), WithRateLimitBlocking(false)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRateLimitHandler, nil",
+			opts:         []ClientOption{WithRateLimitHandler(nil)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name: "WithRateLimitHandler, exclusive",
+			opts: []ClientOption{
+				WithRateLimitHandler(func(gitprovider.RateLimitInfo) {}),
+				WithRateLimitHandler(func(gitprovider.RateLimitInfo) {}),
+			},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -197,3 +354,131 @@ func Test_makeOptions(t *testing.T) {
 		})
 	}
 }
+
+// statusRoundTripper returns the scripted status codes in order, counting the requests.
+type statusRoundTripper struct {
+	statuses []int
+	requests int
+}
+
+func (rt *statusRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	status := rt.statuses[rt.requests]
+	rt.requests++
+	return &http.Response{StatusCode: status, Header: http.Header{}, Body: http.NoBody}, nil
+}
+
+func TestWithRetryableStatusCodes(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []ClientOption
+		wantStatus   int
+		wantRequests int
+	}{
+		{
+			name:         "custom status code is retried",
+			opts:         []ClientOption{WithRetry(1, 0), WithRetryableStatusCodes(420)},
+			wantStatus:   http.StatusOK,
+			wantRequests: 2,
+		},
+		{
+			name:         "custom status code isn't retried by default",
+			opts:         []ClientOption{WithRetry(1, 0)},
+			wantStatus:   420,
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &statusRoundTripper{statuses: []int{420, http.StatusOK}}
+			postChain := func(http.RoundTripper) http.RoundTripper { return fake }
+			opts, err := makeOptions(append(tt.opts, WithPostChainTransportHook(postChain))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			httpClient, err := gitprovider.BuildClientFromTransportChain(opts.getTransportChain())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := httpClient.Get("https://api.github.com/repos/foo/bar")
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if fake.requests != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", fake.requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestWithRetryBudget(t *testing.T) {
+	fake := &statusRoundTripper{statuses: []int{
+		http.StatusBadGateway, http.StatusBadGateway,
+		http.StatusBadGateway,
+	}}
+	postChain := func(http.RoundTripper) http.RoundTripper { return fake }
+	opts, err := makeOptions(WithRetry(3, 0), WithRetryBudget(1, 0), WithPostChainTransportHook(postChain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpClient, err := gitprovider.BuildClientFromTransportChain(opts.getTransportChain())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first request is retried once, taking the whole budget, and the second one isn't retried
+	for _, wantRequests := range []int{2, 3} {
+		resp, err := httpClient.Get("https://api.github.com/repos/foo/bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadGateway {
+			t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusBadGateway)
+		}
+		if fake.requests != wantRequests {
+			t.Errorf("got %d requests, want %d", fake.requests, wantRequests)
+		}
+	}
+}
+
+type userAgentRecorder struct {
+	userAgent string
+}
+
+func (rt *userAgentRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.userAgent = req.Header.Get("User-Agent")
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+}
+
+func TestWithUserAgent(t *testing.T) {
+	fake := &userAgentRecorder{}
+	postChain := func(http.RoundTripper) http.RoundTripper { return fake }
+	opts, err := makeOptions(WithUserAgent("flux/1.0"), WithPostChainTransportHook(postChain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpClient, err := gitprovider.BuildClientFromTransportChain(opts.getTransportChain())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// go-github sets its own user agent, which must be replaced
+	req, err := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("User-Agent", "go-github")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if fake.userAgent != "flux/1.0" {
+		t.Errorf("got User-Agent %q, want %q", fake.userAgent, "flux/1.0")
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package github
 
 import (
+	"strings"
+	"sync"
+
 	"github.com/google/go-github/v32/github"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
@@ -25,9 +28,9 @@ import (
 // ProviderID is the provider ID for GitHub.
 const ProviderID = gitprovider.ProviderID("github")
 
-func newClient(c *github.Client, domain string, destructiveActions bool) *Client {
+func newClient(c *github.Client, domain string, destructiveActions bool, defaultOrg *gitprovider.OrganizationRef) *Client {
 	ghClient := &githubClientImpl{c, destructiveActions}
-	ctx := &clientContext{ghClient, domain, destructiveActions}
+	ctx := &clientContext{c: ghClient, domain: domain, destructiveActions: destructiveActions, defaultOrg: defaultOrg}
 	return &Client{
 		clientContext: ctx,
 		orgs: &OrganizationsClient{
@@ -46,6 +49,52 @@ type clientContext struct {
 	c                  githubClient
 	domain             string
 	destructiveActions bool
+	// defaultOrg is used for repository operations given a ref without an organization, if set
+	defaultOrg *gitprovider.OrganizationRef
+	// permissions caches the effective permissions per repository, see userRepository.EffectivePermissions
+	permissions permissionsCache
+}
+
+// permissionsCache caches the effective permissions of the client per repository.
+// The zero value is ready to use, and it is safe for concurrent use.
+type permissionsCache struct {
+	mu          sync.Mutex
+	permissions map[string]map[string]string
+}
+
+// get returns a copy of the cached permissions for ref, if any.
+func (c *permissionsCache) get(ref gitprovider.RepositoryRef) (map[string]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.permissions[permissionsCacheKey(ref)]
+	if !ok {
+		return nil, false
+	}
+	return copyPermissions(cached), true
+}
+
+// set caches a copy of permissions for ref.
+func (c *permissionsCache) set(ref gitprovider.RepositoryRef, permissions map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.permissions == nil {
+		c.permissions = map[string]map[string]string{}
+	}
+	c.permissions[permissionsCacheKey(ref)] = copyPermissions(permissions)
+}
+
+// permissionsCacheKey returns the key of ref in the cache. GitHub names are case-insensitive.
+func permissionsCacheKey(ref gitprovider.RepositoryRef) string {
+	return strings.ToLower(ref.GetIdentity() + "/" + ref.GetRepository())
+}
+
+// resolveOrgRepositoryRef fills in the default organization if ref doesn't specify one.
+func (c *clientContext) resolveOrgRepositoryRef(ref gitprovider.OrgRepositoryRef) (gitprovider.OrgRepositoryRef, error) {
+	orgRef, err := gitprovider.ResolveOrganizationRef(ref.OrganizationRef, c.defaultOrg)
+	ref.OrganizationRef = orgRef
+	return ref, err
 }
 
 // Client implements the gitprovider.Client interface.
@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// OrgActionsSecretsClient implements the gitprovider.OrgActionsSecretsClient interface.
+var _ gitprovider.OrgActionsSecretsClient = &OrgActionsSecretsClient{}
+
+// OrgActionsSecretsClient operates on the GitHub Actions secrets of a specific organization.
+type OrgActionsSecretsClient struct {
+	*clientContext
+	ref gitprovider.OrganizationRef
+}
+
+// List lists all secrets in the organization. The secret values are never returned.
+//
+// List returns all available secrets, using multiple paginated requests if needed.
+func (c *OrgActionsSecretsClient) List(ctx context.Context) ([]gitprovider.ActionsSecretInfo, error) {
+	// GET /orgs/{org}/actions/secrets
+	apiObjs, err := c.c.ListOrgSecrets(ctx, c.ref.Organization)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]gitprovider.ActionsSecretInfo, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListOrgSecrets
+		secrets = append(secrets, actionsSecretFromAPI(apiObj))
+	}
+	return secrets, nil
+}
+
+// Set creates or updates the given secret. The value is sealed with the organization's
+// public key before it is sent to the server.
+func (c *OrgActionsSecretsClient) Set(ctx context.Context, req gitprovider.ActionsSecretInfo) error {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return err
+	}
+
+	req2 := &github.EncryptedSecret{
+		Name:       req.Name,
+		Visibility: string(*req.Visibility),
+	}
+	if *req.Visibility == gitprovider.ActionsSecretVisibilitySelected {
+		repoIDs, err := c.getRepoIDs(ctx, req.SelectedRepositories)
+		if err != nil {
+			return err
+		}
+		req2.SelectedRepositoryIDs = repoIDs
+	}
+
+	// GET /orgs/{org}/actions/secrets/public-key
+	publicKey, err := c.c.GetOrgPublicKey(ctx, c.ref.Organization)
+	if err != nil {
+		return err
+	}
+	req2.KeyID = *publicKey.KeyID
+	req2.EncryptedValue, err = sealSecret(req.Value, publicKey)
+	if err != nil {
+		return err
+	}
+
+	// PUT /orgs/{org}/actions/secrets/{secret_name}
+	return c.c.CreateOrUpdateOrgSecret(ctx, c.ref.Organization, req2)
+}
+
+// Delete deletes the secret with the given name.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *OrgActionsSecretsClient) Delete(ctx context.Context, name string) error {
+	// DELETE /orgs/{org}/actions/secrets/{secret_name}
+	return c.c.DeleteOrgSecret(ctx, c.ref.Organization, name)
+}
+
+// ListSelectedRepositories lists the names of the repositories that can access a secret
+// with the "selected" visibility.
+func (c *OrgActionsSecretsClient) ListSelectedRepositories(ctx context.Context, name string) ([]string, error) {
+	// GET /orgs/{org}/actions/secrets/{secret_name}/repositories
+	apiObjs, err := c.c.ListSelectedReposForOrgSecret(ctx, c.ref.Organization, name)
+	if err != nil {
+		return nil, err
+	}
+
+	repoNames := make([]string, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListSelectedReposForOrgSecret
+		repoNames = append(repoNames, *apiObj.Name)
+	}
+	return repoNames, nil
+}
+
+// SetSelectedRepositories replaces the list of repositories that can access a secret
+// with the "selected" visibility.
+func (c *OrgActionsSecretsClient) SetSelectedRepositories(ctx context.Context, name string, repoNames []string) error {
+	repoIDs, err := c.getRepoIDs(ctx, repoNames)
+	if err != nil {
+		return err
+	}
+	// PUT /orgs/{org}/actions/secrets/{secret_name}/repositories
+	return c.c.SetSelectedReposForOrgSecret(ctx, c.ref.Organization, name, repoIDs)
+}
+
+// getRepoIDs resolves the given repository names in the organization to their IDs.
+func (c *OrgActionsSecretsClient) getRepoIDs(ctx context.Context, repoNames []string) ([]int64, error) {
+	repoIDs := make([]int64, 0, len(repoNames))
+	for _, repoName := range repoNames {
+		// GET /repos/{owner}/{repo}
+		apiObj, err := c.c.GetRepo(ctx, c.ref.Organization, repoName)
+		if err != nil {
+			return nil, err
+		}
+		if apiObj.ID == nil {
+			return nil, fmt.Errorf("repository %q has no ID: %w", repoName, gitprovider.ErrInvalidServerData)
+		}
+		repoIDs = append(repoIDs, *apiObj.ID)
+	}
+	return repoIDs, nil
+}
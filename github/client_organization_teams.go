@@ -18,10 +18,12 @@ package github
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/go-github/v32/github"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
 )
 
 // TeamsClient implements the gitprovider.TeamsClient interface.
@@ -35,50 +37,40 @@ type TeamsClient struct {
 
 // Get a team within the specific organization.
 //
-// teamName may include slashes, to point to e.g. subgroups in GitLab.
-// teamName must not be an empty string.
+// teamName is the slug of the team, and must not be an empty string.
 //
 // ErrNotFound is returned if the resource does not exist.
 func (c *TeamsClient) Get(ctx context.Context, teamName string) (gitprovider.Team, error) {
-	// GET /orgs/{org}/teams/{team_slug}/members
-	apiObjs, err := c.c.ListOrgTeamMembers(ctx, c.ref.Organization, teamName)
-	if err != nil {
+	if err := validateOrganizationRef(c.ref, c.domain); err != nil {
 		return nil, err
 	}
 
-	// Collect a list of the members' names. Login is validated to be non-nil in ListOrgTeamMembers.
-	logins := make([]string, 0, len(apiObjs))
-	for _, apiObj := range apiObjs {
-		// Login is validated to be non-nil in ListOrgTeamMembers
-		logins = append(logins, *apiObj.Login)
+	// GET /orgs/{org}/teams/{team_slug}
+	apiObj, err := c.c.GetOrgTeam(ctx, c.ref.Organization, teamName)
+	if err != nil {
+		return nil, err
 	}
-
-	return &team{
-		users: apiObjs,
-		info: gitprovider.TeamInfo{
-			Name:    teamName,
-			Members: logins,
-		},
-		ref: c.ref,
-	}, nil
+	return c.newTeam(ctx, apiObj)
 }
 
 // List all teams (recursively, in terms of subgroups) within the specific organization.
 //
 // List returns all available organizations, using multiple paginated requests if needed.
 func (c *TeamsClient) List(ctx context.Context) ([]gitprovider.Team, error) {
+	if err := validateOrganizationRef(c.ref, c.domain); err != nil {
+		return nil, err
+	}
+
 	// GET /orgs/{org}/teams
 	apiObjs, err := c.c.ListOrgTeams(ctx, c.ref.Organization)
 	if err != nil {
 		return nil, err
 	}
 
-	// Use .Get() to get detailed information about each member
 	teams := make([]gitprovider.Team, 0, len(apiObjs))
 	for _, apiObj := range apiObjs {
-		// Get detailed information about individual teams (including members).
-		// Slug is validated to be non-nil in ListOrgTeams.
-		team, err := c.Get(ctx, *apiObj.Slug)
+		// The listed teams don't contain information about their members, fetch them separately
+		team, err := c.newTeam(ctx, apiObj)
 		if err != nil {
 			return nil, err
 		}
@@ -89,6 +81,44 @@ func (c *TeamsClient) List(ctx context.Context) ([]gitprovider.Team, error) {
 	return teams, nil
 }
 
+// newTeam lists the members of the team described by apiObj, and returns the resulting team.
+func (c *TeamsClient) newTeam(ctx context.Context, apiObj *github.Team) (*team, error) {
+	// GET /orgs/{org}/teams/{team_slug}/members
+	users, err := c.c.ListOrgTeamMembers(ctx, c.ref.Organization, *apiObj.Slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members of team %q: %w", *apiObj.Slug, err)
+	}
+
+	// Collect a list of the members' names. Login is validated to be non-nil in ListOrgTeamMembers.
+	logins := make([]string, 0, len(users))
+	for _, user := range users {
+		logins = append(logins, *user.Login)
+	}
+
+	return &team{
+		users: users,
+		info: gitprovider.TeamInfo{
+			Name:    *apiObj.Name,
+			Slug:    *apiObj.Slug,
+			Members: logins,
+		},
+		ref: c.ref,
+	}, nil
+}
+
+// validateTeamAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateTeamAPI(apiObj *github.Team) error {
+	return validateAPIObject("GitHub.Team", func(validator validation.Validator) {
+		if apiObj.Name == nil {
+			validator.Required("Name")
+		}
+		if apiObj.Slug == nil {
+			validator.Required("Slug")
+		}
+	})
+}
+
 var _ gitprovider.Team = &team{}
 
 type team struct {
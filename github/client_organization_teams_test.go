@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// fakeTeamsClient is a githubClient that serves the teams of a single organization from memory.
+// Calling any other method than the overridden ones panics.
+type fakeTeamsClient struct {
+	githubClient
+
+	teams   []*github.Team
+	members map[string][]string
+}
+
+func (c *fakeTeamsClient) GetOrgTeam(_ context.Context, _, teamSlug string) (*github.Team, error) {
+	for _, team := range c.teams {
+		if *team.Slug == teamSlug {
+			return team, nil
+		}
+	}
+	return nil, gitprovider.ErrNotFound
+}
+
+func (c *fakeTeamsClient) ListOrgTeams(_ context.Context, _ string) ([]*github.Team, error) {
+	return c.teams, nil
+}
+
+func (c *fakeTeamsClient) ListOrgTeamMembers(_ context.Context, _, teamSlug string) ([]*github.User, error) {
+	logins, ok := c.members[teamSlug]
+	if !ok {
+		return nil, gitprovider.ErrNotFound
+	}
+	users := make([]*github.User, 0, len(logins))
+	for _, login := range logins {
+		users = append(users, &github.User{Login: github.String(login)})
+	}
+	return users, nil
+}
+
+func newFakeTeamsClient(fake *fakeTeamsClient) *TeamsClient {
+	return &TeamsClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain},
+		ref:           gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"},
+	}
+}
+
+func TestTeamsClient(t *testing.T) {
+	fake := &fakeTeamsClient{
+		teams: []*github.Team{
+			{Name: github.String("Core Maintainers"), Slug: github.String("core-maintainers")},
+			{Name: github.String("Docs"), Slug: github.String("docs")},
+		},
+		members: map[string][]string{
+			"core-maintainers": {"alice", "bob"},
+			"docs":             {},
+		},
+	}
+	c := newFakeTeamsClient(fake)
+	ctx := context.Background()
+
+	team, err := c.Get(ctx, "core-maintainers")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	want := gitprovider.TeamInfo{Name: "Core Maintainers", Slug: "core-maintainers", Members: []string{"alice", "bob"}}
+	if got := team.Get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() = %#v, want %#v", got, want)
+	}
+
+	if _, err := c.Get(ctx, "unknown"); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+
+	teams, err := c.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	got := make([]gitprovider.TeamInfo, 0, len(teams))
+	for _, team := range teams {
+		got = append(got, team.Get())
+	}
+	wantList := []gitprovider.TeamInfo{
+		want,
+		{Name: "Docs", Slug: "docs", Members: []string{}},
+	}
+	if !reflect.DeepEqual(got, wantList) {
+		t.Errorf("List() = %#v, want %#v", got, wantList)
+	}
+}
+
+func TestTeamsClient_invalidRef(t *testing.T) {
+	c := newFakeTeamsClient(&fakeTeamsClient{})
+	c.ref.Domain = "gitlab.com"
+
+	if _, err := c.Get(context.Background(), "docs"); !errors.Is(err, gitprovider.ErrDomainUnsupported) {
+		t.Errorf("Get() error = %v, want %v", err, gitprovider.ErrDomainUnsupported)
+	}
+	if _, err := c.List(context.Background()); !errors.Is(err, gitprovider.ErrDomainUnsupported) {
+		t.Errorf("List() error = %v, want %v", err, gitprovider.ErrDomainUnsupported)
+	}
+}
@@ -19,10 +19,14 @@ package github
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/go-github/v32/github"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
 )
 
 // OrgRepositoriesClient implements the gitprovider.OrgRepositoriesClient interface.
@@ -37,6 +41,11 @@ type OrgRepositoriesClient struct {
 //
 // ErrNotFound is returned if the resource does not exist.
 func (c *OrgRepositoriesClient) Get(ctx context.Context, ref gitprovider.OrgRepositoryRef) (gitprovider.OrgRepository, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := c.resolveOrgRepositoryRef(ref)
+	if err != nil {
+		return nil, err
+	}
 	// Make sure the OrgRepositoryRef is valid
 	if err := validateOrgRepositoryRef(ref, c.domain); err != nil {
 		return nil, err
@@ -46,13 +55,23 @@ func (c *OrgRepositoriesClient) Get(ctx context.Context, ref gitprovider.OrgRepo
 	if err != nil {
 		return nil, err
 	}
-	return newOrgRepository(c.clientContext, apiObj, ref), nil
+	repo := newOrgRepository(c.clientContext, apiObj, ref)
+	// go-github doesn't provide a field for AllowForking (yet), hence request it separately
+	if err := repo.getAllowForking(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
 }
 
 // List all repositories in the given organization.
 //
 // List returns all available repositories, using multiple paginated requests if needed.
 func (c *OrgRepositoriesClient) List(ctx context.Context, ref gitprovider.OrganizationRef) ([]gitprovider.OrgRepository, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, err
+	}
 	// Make sure the OrganizationRef is valid
 	if err := validateOrganizationRef(ref, c.domain); err != nil {
 		return nil, err
@@ -64,22 +83,191 @@ func (c *OrgRepositoriesClient) List(ctx context.Context, ref gitprovider.Organi
 		return nil, err
 	}
 
-	// Traverse the list, and return a list of OrgRepository objects
+	return c.orgRepositoriesFromAPI(ref, apiObjs), nil
+}
+
+// ListPage lists one page of repositories, along with the pagination metadata supplied by the provider.
+func (c *OrgRepositoriesClient) ListPage(ctx context.Context, ref gitprovider.OrganizationRef, opts gitprovider.PageOptions) ([]gitprovider.OrgRepository, gitprovider.PageInfo, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	// Make sure the OrganizationRef and options are valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+
+	// GET /orgs/{org}/repos
+	apiObjs, pageInfo, err := c.c.ListOrgReposPage(ctx, ref.Organization, opts, "", "")
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	return c.orgRepositoriesFromAPI(ref, apiObjs), pageInfo, nil
+}
+
+// ListSorted lists one page of repositories, sorted server-side according to sortOpts.
+func (c *OrgRepositoriesClient) ListSorted(ctx context.Context, ref gitprovider.OrganizationRef, sortOpts gitprovider.RepositorySortOptions, opts gitprovider.PageOptions) ([]gitprovider.OrgRepository, gitprovider.PageInfo, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	// Make sure the OrganizationRef and options are valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	if err := sortOpts.ValidateOptions(); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	sortKey, ok := githubRepositorySortKeys[sortOpts.Sort]
+	if !ok {
+		return nil, gitprovider.PageInfo{}, fmt.Errorf("sorting repositories by %q: %w", sortOpts.Sort, gitprovider.ErrNoProviderSupport)
+	}
+
+	// GET /orgs/{org}/repos
+	apiObjs, pageInfo, err := c.c.ListOrgReposPage(ctx, ref.Organization, opts, sortKey, string(sortOpts.GetDirection()))
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	return c.orgRepositoriesFromAPI(ref, apiObjs), pageInfo, nil
+}
+
+// githubRepositorySortKeys maps the known sort keys to the values of the "sort" parameter of
+// "GET /orgs/{org}/repos".
+//nolint:gochecknoglobals
+var githubRepositorySortKeys = map[gitprovider.RepositorySortKey]string{
+	gitprovider.RepositorySortKeyCreated: "created",
+	gitprovider.RepositorySortKeyUpdated: "updated",
+	gitprovider.RepositorySortKeyPushed:  "pushed",
+	gitprovider.RepositorySortKeyName:    "full_name",
+}
+
+// ListRepositoryRefs lists references to the repositories in the given organization that
+// match the filter, without returning the full repository resources.
+//
+// The Visibility filter is applied server-side, the Archived, Language and NamePrefix filters
+// client-side.
+//
+// ListRepositoryRefs returns all matching references, using multiple paginated requests if needed.
+func (c *OrgRepositoriesClient) ListRepositoryRefs(ctx context.Context, ref gitprovider.OrganizationRef, filter gitprovider.RefListFilter) ([]gitprovider.RepositoryRef, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the OrganizationRef and filter are valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+	if err := filter.ValidateOptions(); err != nil {
+		return nil, err
+	}
+
+	// The visibilities map 1:1 to the repository types GitHub can filter by
+	repoType := ""
+	if filter.Visibility != nil {
+		repoType = string(*filter.Visibility)
+	}
+	// GET /orgs/{org}/repos
+	apiObjs, err := c.c.ListOrgReposOfType(ctx, ref.Organization, repoType)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]gitprovider.RepositoryRef, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListOrgReposOfType
+		if !repositoryMatchesFilter(apiObj, filter) {
+			continue
+		}
+		refs = append(refs, gitprovider.OrgRepositoryRef{
+			OrganizationRef: ref,
+			RepositoryName:  *apiObj.Name,
+		})
+	}
+	return refs, nil
+}
+
+// repositoryMatchesFilter applies the filters of RefListFilter that GitHub can't apply server-side.
+func repositoryMatchesFilter(apiObj *github.Repository, filter gitprovider.RefListFilter) bool {
+	if filter.Archived != nil && apiObj.GetArchived() != *filter.Archived {
+		return false
+	}
+	if filter.Language != "" && !strings.EqualFold(apiObj.GetLanguage(), filter.Language) {
+		return false
+	}
+	return strings.HasPrefix(*apiObj.Name, filter.NamePrefix)
+}
+
+// ListRepositories lists the repositories in the given organization that match opts.
+//
+// The Visibility filter is applied server-side, the Topic filter client-side. GitHub lists the
+// topics along with the repositories, hence filtering by topic doesn't need any extra requests.
+//
+// ListRepositories returns all matching repositories, using multiple paginated requests if needed.
+func (c *OrgRepositoriesClient) ListRepositories(ctx context.Context, ref gitprovider.OrganizationRef, opts gitprovider.RepositoryListOptions) ([]gitprovider.OrgRepository, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the OrganizationRef and options are valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, err
+	}
+
+	// The visibilities map 1:1 to the repository types GitHub can filter by
+	repoType := ""
+	if opts.Visibility != nil {
+		repoType = string(*opts.Visibility)
+	}
+	// GET /orgs/{org}/repos
+	apiObjs, err := c.c.ListOrgReposOfType(ctx, ref.Organization, repoType)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]*github.Repository, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		if opts.MatchesTopics(apiObj.Topics) {
+			matching = append(matching, apiObj)
+		}
+	}
+	return c.orgRepositoriesFromAPI(ref, matching), nil
+}
+
+// orgRepositoriesFromAPI traverses the list, and returns a list of OrgRepository objects.
+func (c *OrgRepositoriesClient) orgRepositoriesFromAPI(ref gitprovider.OrganizationRef, apiObjs []*github.Repository) []gitprovider.OrgRepository {
 	repos := make([]gitprovider.OrgRepository, 0, len(apiObjs))
 	for _, apiObj := range apiObjs {
-		// apiObj is already validated at ListOrgRepos
+		// apiObj is already validated at ListOrgRepos or ListOrgReposPage
 		repos = append(repos, newOrgRepository(c.clientContext, apiObj, gitprovider.OrgRepositoryRef{
 			OrganizationRef: ref,
 			RepositoryName:  *apiObj.Name,
 		}))
 	}
-	return repos, nil
+	return repos
 }
 
 // Create creates a repository for the given organization, with the data and options.
 //
 // ErrAlreadyExists will be returned if the resource already exists.
 func (c *OrgRepositoriesClient) Create(ctx context.Context, ref gitprovider.OrgRepositoryRef, req gitprovider.RepositoryInfo, opts ...gitprovider.RepositoryCreateOption) (gitprovider.OrgRepository, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := c.resolveOrgRepositoryRef(ref)
+	if err != nil {
+		return nil, err
+	}
 	// Make sure the RepositoryRef is valid
 	if err := validateOrgRepositoryRef(ref, c.domain); err != nil {
 		return nil, err
@@ -89,7 +277,15 @@ func (c *OrgRepositoriesClient) Create(ctx context.Context, ref gitprovider.OrgR
 	if err != nil {
 		return nil, err
 	}
-	return newOrgRepository(c.clientContext, apiObj, ref), nil
+	repo := newOrgRepository(c.clientContext, apiObj, ref)
+	// AllowForking can't be set at creation time, hence apply it separately
+	if req.AllowForking != nil {
+		repo.allowForking = req.AllowForking
+		if err := repo.updateAllowForking(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return repo, nil
 }
 
 // Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
@@ -98,6 +294,11 @@ func (c *OrgRepositoriesClient) Create(ctx context.Context, ref gitprovider.OrgR
 // If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
 // If req is already the actual state, this is a no-op (actionTaken == false).
 func (c *OrgRepositoriesClient) Reconcile(ctx context.Context, ref gitprovider.OrgRepositoryRef, req gitprovider.RepositoryInfo, opts ...gitprovider.RepositoryReconcileOption) (gitprovider.OrgRepository, bool, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := c.resolveOrgRepositoryRef(ref)
+	if err != nil {
+		return nil, false, err
+	}
 	// First thing, validate and default the request to ensure a valid and fully-populated object
 	// (to minimize any possible diffs between desired and actual state)
 	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
@@ -120,12 +321,256 @@ func (c *OrgRepositoriesClient) Reconcile(ctx context.Context, ref gitprovider.O
 	return actual, actionTaken, err
 }
 
+// ReconcileList makes sure the repositories of the given organization are the desired ones, matched
+// by name. Missing repositories are created, and drifted ones updated, see Reconcile. If opts.Prune is
+// set, the repositories that aren't desired, and whose name starts with opts.NamePrefix, are deleted.
+// Deleting repositories requires destructive actions to be enabled in the client, otherwise
+// ErrDestructiveCallDisallowed is returned before any change. If opts.DryRun is set, nothing is changed,
+// hence the deletions are reported even if destructive actions are disabled.
+//
+// The action taken (or, in a dry run, that would be taken) for each repository is returned, also when
+// an error occurred midway.
+func (c *OrgRepositoriesClient) ReconcileList(ctx context.Context, ref gitprovider.OrganizationRef, desired []gitprovider.RepositoryReconcileRequest, opts gitprovider.RepositoryReconcileListOptions) ([]gitprovider.RepositoryReconcileResult, error) {
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, err
+	}
+	// First thing, validate and default the requests to ensure valid and fully-populated objects
+	reqs, err := gitprovider.ValidateAndDefaultRepositoryReconcileRequests(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := c.List(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	// Repository names are case-insensitive in GitHub
+	actualByName := make(map[string]gitprovider.OrgRepository, len(actual))
+	for _, repo := range actual {
+		actualByName[strings.ToLower(repo.Repository().GetRepository())] = repo
+	}
+	desiredNames := make(map[string]struct{}, len(reqs))
+	for _, req := range reqs {
+		desiredNames[strings.ToLower(req.Name)] = struct{}{}
+	}
+
+	// Find the repositories that aren't desired, and make sure they may be deleted before changing anything
+	toRemove := make([]gitprovider.OrgRepository, 0, len(actual))
+	for _, repo := range actual {
+		name := repo.Repository().GetRepository()
+		if _, ok := desiredNames[strings.ToLower(name)]; !ok && opts.Prunable(name) {
+			toRemove = append(toRemove, repo)
+		}
+	}
+	if len(toRemove) != 0 && !opts.DryRun && !c.destructiveActions {
+		return nil, fmt.Errorf("cannot delete %d repositories: %w", len(toRemove), gitprovider.ErrDestructiveCallDisallowed)
+	}
+
+	results := make([]gitprovider.RepositoryReconcileResult, 0, len(reqs)+len(toRemove))
+	for _, req := range reqs {
+		action, err := c.reconcileListItem(ctx, ref, req, actualByName, opts.DryRun)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, gitprovider.RepositoryReconcileResult{Name: req.Name, Action: action})
+	}
+	for _, repo := range toRemove {
+		if !opts.DryRun {
+			if err := repo.Delete(ctx); err != nil {
+				return results, err
+			}
+		}
+		results = append(results, gitprovider.RepositoryReconcileResult{
+			Name:   repo.Repository().GetRepository(),
+			Action: gitprovider.RepositoryReconcileActionDelete,
+		})
+	}
+	return results, nil
+}
+
+// reconcileListItem reconciles a repository desired by ReconcileList, and returns the action taken. The
+// repository is only fetched again if it exists, as the listed objects lack e.g. AllowForking.
+func (c *OrgRepositoriesClient) reconcileListItem(ctx context.Context, ref gitprovider.OrganizationRef, req gitprovider.RepositoryReconcileRequest, actualByName map[string]gitprovider.OrgRepository, dryRun bool) (gitprovider.RepositoryReconcileAction, error) {
+	repoRef := gitprovider.OrgRepositoryRef{OrganizationRef: ref, RepositoryName: req.Name}
+	listed, ok := actualByName[strings.ToLower(req.Name)]
+	if !ok {
+		if dryRun {
+			return gitprovider.RepositoryReconcileActionCreate, nil
+		}
+		_, err := c.Create(ctx, repoRef, req.Info)
+		return gitprovider.RepositoryReconcileActionCreate, err
+	}
+
+	// Use the actual name, as the desired one may differ in case
+	repoRef.RepositoryName = listed.Repository().GetRepository()
+	actual, err := c.Get(ctx, repoRef)
+	if err != nil {
+		return gitprovider.RepositoryReconcileActionNone, err
+	}
+	var drifted bool
+	if dryRun {
+		_, drifted = repositoryDrift(actual, req.Info)
+	} else if drifted, err = reconcileRepository(ctx, actual, req.Info); err != nil {
+		return gitprovider.RepositoryReconcileActionUpdate, err
+	}
+	if !drifted {
+		return gitprovider.RepositoryReconcileActionNone, nil
+	}
+	return gitprovider.RepositoryReconcileActionUpdate, nil
+}
+
+// ForkRepository forks the upstream repository into the organization given in opts, and waits until
+// the fork is created, at most opts.Timeout. GitHub creates forks asynchronously, hence the fork is
+// polled for every opts.PollInterval. A custom opts.Name is applied by renaming the created fork.
+//
+// ErrAlreadyExists is returned if a repository with the name of the fork already exists.
+func (c *OrgRepositoriesClient) ForkRepository(ctx context.Context, upstream gitprovider.RepositoryRef, opts gitprovider.ForkOptions) (gitprovider.OrgRepository, error) {
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, err
+	}
+	// Make sure the upstream RepositoryRef is valid
+	if err := validateRepositoryRef(upstream, c.domain); err != nil {
+		return nil, err
+	}
+	// Fill in the default organization if opts doesn't specify one
+	org, err := gitprovider.ResolveOrganizationRef(opts.Organization, c.defaultOrg)
+	if err != nil {
+		return nil, err
+	}
+	name := upstream.GetRepository()
+	if opts.Name != nil {
+		name = *opts.Name
+	}
+	ref := gitprovider.OrgRepositoryRef{OrganizationRef: org, RepositoryName: name}
+	// Make sure the OrgRepositoryRef of the fork is valid
+	if err := validateOrgRepositoryRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+
+	// GitHub returns an existing fork as-is, or picks another name, hence check the name is free first
+	// GET /repos/{owner}/{repo}
+	if _, err := c.c.GetRepo(ctx, ref.GetIdentity(), ref.GetRepository()); err == nil {
+		return nil, fmt.Errorf("repository %s: %w", ref, gitprovider.ErrAlreadyExists)
+	} else if !errors.Is(err, gitprovider.ErrNotFound) {
+		return nil, err
+	}
+
+	// POST /repos/{owner}/{repo}/forks
+	apiObj, err := c.c.CreateFork(ctx, upstream.GetIdentity(), upstream.GetRepository(), org.Organization)
+	if err != nil {
+		return nil, err
+	}
+	forkRef := gitprovider.OrgRepositoryRef{OrganizationRef: org, RepositoryName: *apiObj.Name}
+	if err := c.waitForFork(ctx, forkRef, opts); err != nil {
+		return nil, err
+	}
+	if forkRef.RepositoryName != name {
+		// PATCH /repos/{owner}/{repo}
+		if _, err := c.c.UpdateRepo(ctx, forkRef.GetIdentity(), forkRef.GetRepository(), &github.Repository{
+			Name: github.String(name),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return c.Get(ctx, ref)
+}
+
+// waitForFork polls for the fork at ref every opts.PollInterval, until it exists or opts.Timeout elapsed.
+func (c *OrgRepositoriesClient) waitForFork(ctx context.Context, ref gitprovider.OrgRepositoryRef, opts gitprovider.ForkOptions) error {
+	_, err := c.waitForRepository(ctx, ref, opts.GetTimeout(), opts.GetPollInterval())
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("fork %s wasn't created within %s: %w", ref, opts.GetTimeout(), err)
+	}
+	return err
+}
+
+// TransferRepository transfers the repository to newOwner, a user or organization, and waits until
+// it's available there, at most opts.Timeout. GitHub transfers repositories asynchronously, hence the
+// repository is polled under newOwner for every opts.PollInterval. This requires destructive actions
+// to be enabled in the client.
+//
+// ErrNotFound is returned if the repository does not exist.
+func (c *OrgRepositoriesClient) TransferRepository(ctx context.Context, repo gitprovider.RepositoryRef, newOwner gitprovider.IdentityRef, opts gitprovider.TransferOptions) (gitprovider.UserRepository, error) {
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, err
+	}
+	// Make sure the RepositoryRef and the new owner are valid
+	if err := validateRepositoryRef(repo, c.domain); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateTargets("IdentityRef", newOwner); err != nil {
+		return nil, err
+	}
+	if err := validateIdentityFields(newOwner, c.domain); err != nil {
+		return nil, err
+	}
+	// Transferring moves the repository out of reach of its current owner, refuse it up front
+	if !c.destructiveActions {
+		return nil, fmt.Errorf("cannot transfer repository %s: %w", repo, gitprovider.ErrDestructiveCallDisallowed)
+	}
+
+	// POST /repos/{owner}/{repo}/transfer
+	if err := c.c.TransferRepo(ctx, repo.GetIdentity(), repo.GetRepository(), newOwner.GetIdentity()); err != nil {
+		return nil, err
+	}
+	ref := transferredRepositoryRef(repo, newOwner)
+	apiObj, err := c.waitForRepository(ctx, ref, opts.GetTimeout(), opts.GetPollInterval())
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("repository %s wasn't transferred to %s within %s: %w", repo, newOwner.GetIdentity(), opts.GetTimeout(), err)
+	} else if err != nil {
+		return nil, err
+	}
+	if orgRef, ok := ref.(gitprovider.OrgRepositoryRef); ok {
+		return newOrgRepository(c.clientContext, apiObj, orgRef), nil
+	}
+	return newUserRepository(c.clientContext, apiObj, ref), nil
+}
+
+// transferredRepositoryRef returns the reference of repo after it's transferred to newOwner.
+func transferredRepositoryRef(repo gitprovider.RepositoryRef, newOwner gitprovider.IdentityRef) gitprovider.RepositoryRef {
+	if newOwner.GetType() == gitprovider.IdentityTypeUser {
+		return gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: newOwner.GetDomain(), UserLogin: newOwner.GetIdentity()},
+			RepositoryName: repo.GetRepository(),
+		}
+	}
+	return gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: newOwner.GetDomain(), Organization: newOwner.GetIdentity()},
+		RepositoryName:  repo.GetRepository(),
+	}
+}
+
+// waitForRepository polls for the repository at ref every pollInterval, until it exists or timeout
+// elapsed, in which case an error wrapping context.DeadlineExceeded is returned.
+func (c *OrgRepositoriesClient) waitForRepository(ctx context.Context, ref gitprovider.RepositoryRef, timeout, pollInterval time.Duration) (*github.Repository, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		// GET /repos/{owner}/{repo}
+		apiObj, err := c.c.GetRepo(ctx, ref.GetIdentity(), ref.GetRepository())
+		if !errors.Is(err, gitprovider.ErrNotFound) {
+			return apiObj, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
 func createRepository(ctx context.Context, c githubClient, ref gitprovider.RepositoryRef, orgName string, req gitprovider.RepositoryInfo, opts ...gitprovider.RepositoryCreateOption) (*github.Repository, error) {
 	// First thing, validate and default the request to ensure a valid and fully-populated object
 	// (to minimize any possible diffs between desired and actual state)
 	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
 		return nil, err
 	}
+	if err := validateAllowForking(req.AllowForking, req.Visibility); err != nil {
+		return nil, err
+	}
+	if err := validateVisibility(req.Visibility, ref.GetDomain()); err != nil {
+		return nil, err
+	}
 
 	// Assemble the options struct based on the given options
 	o, err := gitprovider.MakeRepositoryCreateOptions(opts...)
@@ -137,12 +582,70 @@ func createRepository(ctx context.Context, c githubClient, ref gitprovider.Repos
 	data := repositoryToAPI(&req, ref)
 	applyRepoCreateOptions(&data, o)
 
-	return c.CreateRepo(ctx, orgName, &data)
+	var apiObj *github.Repository
+	if o.TemplateRepositoryRef != nil {
+		apiObj, err = createRepositoryFromTemplate(ctx, c, ref, o.TemplateRepositoryRef, data)
+	} else {
+		apiObj, err = c.CreateRepo(ctx, orgName, &data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Add the topics of the template to the desired ones if they should be inherited
+	if o.InheritTopics {
+		// GET /repos/{owner}/{repo}
+		template, err := c.GetRepo(ctx, o.TemplateRepositoryRef.GetIdentity(), o.TemplateRepositoryRef.GetRepository())
+		if err != nil {
+			return nil, err
+		}
+		data.Topics = gitprovider.NormalizeTopics(append(data.Topics, template.Topics...))
+	}
+	// Topics can't be set at creation time, hence apply them separately
+	if len(data.Topics) != 0 {
+		// PUT /repos/{owner}/{repo}/topics
+		topics, err := c.ReplaceRepoTopics(ctx, ref.GetIdentity(), ref.GetRepository(), data.Topics)
+		if err != nil {
+			return nil, err
+		}
+		apiObj.Topics = topics
+	}
+	// Neither can the repository be archived, hence archive it last
+	if !data.GetArchived() {
+		return apiObj, nil
+	}
+	// PATCH /repos/{owner}/{repo}
+	return c.UpdateRepo(ctx, ref.GetIdentity(), ref.GetRepository(), &github.Repository{
+		Archived: github.Bool(true),
+	})
+}
+
+// createRepositoryFromTemplate creates the repository described by data from the template repository.
+// Only the name, owner, description and privacy can be given when generating a repository, hence the
+// rest of data is applied with a separate update.
+func createRepositoryFromTemplate(ctx context.Context, c githubClient, ref, template gitprovider.RepositoryRef, data github.Repository) (*github.Repository, error) {
+	req := &github.TemplateRepoRequest{
+		Name:        data.Name,
+		Owner:       gitprovider.StringVar(ref.GetIdentity()),
+		Description: data.Description,
+	}
+	if data.Visibility != nil {
+		req.Private = gitprovider.BoolVar(*data.Visibility != string(gitprovider.RepositoryVisibilityPublic))
+	}
+	// POST /repos/{template_owner}/{template_repo}/generate
+	if _, err := c.CreateRepoFromTemplate(ctx, template.GetIdentity(), template.GetRepository(), req); err != nil {
+		return nil, err
+	}
+
+	// The default branch is the one of the template, as the other branches aren't copied
+	data.DefaultBranch = nil
+	// PATCH /repos/{owner}/{repo}
+	return c.UpdateRepo(ctx, ref.GetIdentity(), ref.GetRepository(), &data)
 }
 
 func reconcileRepository(ctx context.Context, actual gitprovider.UserRepository, req gitprovider.RepositoryInfo) (bool, error) {
+	req, drifted := repositoryDrift(actual, req)
 	// If the desired matches the actual state, just return the actual state
-	if req.Equals(actual.Get()) {
+	if !drifted {
 		return false, nil
 	}
 	// Populate the desired state to the current-actual object
@@ -153,6 +656,24 @@ func reconcileRepository(ctx context.Context, actual gitprovider.UserRepository,
 	return true, actual.Update(ctx)
 }
 
+// repositoryDrift returns the desired state to Set on actual, and whether it differs from the actual state.
+func repositoryDrift(actual gitprovider.UserRepository, req gitprovider.RepositoryInfo) (gitprovider.RepositoryInfo, bool) {
+	actualInfo := actual.Get()
+	// AllowForking has no default, leave it as-is if it isn't desired
+	if req.AllowForking == nil {
+		req.AllowForking = actualInfo.AllowForking
+	}
+	// Topics have no default, leave them as-is if they aren't desired
+	if req.Topics == nil {
+		req.Topics = actualInfo.Topics
+	}
+	// Neither has Archived, leave it as-is if it isn't desired
+	if req.Archived == nil {
+		req.Archived = actualInfo.Archived
+	}
+	return req, !req.Equals(actualInfo)
+}
+
 func toCreateOpts(opts ...gitprovider.RepositoryReconcileOption) []gitprovider.RepositoryCreateOption {
 	// Convert RepositoryReconcileOption => RepositoryCreateOption
 	createOpts := make([]gitprovider.RepositoryCreateOption, 0, len(opts))
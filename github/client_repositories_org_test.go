@@ -0,0 +1,527 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// fakeOrgReposClient is a githubClient that keeps the repositories of an organization in memory,
+// counting the calls that change them. Calling any other method than the overridden ones panics.
+type fakeOrgReposClient struct {
+	githubClient
+
+	repos   map[string]*github.Repository
+	changes int
+	// forks are the forks that are being created, which GetRepo returns ErrNotFound for forkPolls times
+	forks     map[string]*github.Repository
+	forkPolls int
+}
+
+func (c *fakeOrgReposClient) ListOrgRepos(_ context.Context, _ string) ([]*github.Repository, error) {
+	names := make([]string, 0, len(c.repos))
+	for name := range c.repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	apiObjs := make([]*github.Repository, 0, len(names))
+	for _, name := range names {
+		apiObj := *c.repos[name]
+		apiObjs = append(apiObjs, &apiObj)
+	}
+	return apiObjs, nil
+}
+
+func (c *fakeOrgReposClient) ListOrgReposOfType(ctx context.Context, org, repoType string) ([]*github.Repository, error) {
+	all, err := c.ListOrgRepos(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	// Like the real server, the visibility types are filtered by
+	apiObjs := make([]*github.Repository, 0, len(all))
+	for _, apiObj := range all {
+		if repoType == "" || apiObj.GetVisibility() == repoType {
+			apiObjs = append(apiObjs, apiObj)
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *fakeOrgReposClient) GetRepo(_ context.Context, _, repo string) (*github.Repository, error) {
+	if fork, ok := c.forks[repo]; ok {
+		if c.forkPolls > 0 {
+			c.forkPolls--
+			return nil, gitprovider.ErrNotFound
+		}
+		c.repos[repo] = fork
+		delete(c.forks, repo)
+	}
+	apiObj, ok := c.repos[repo]
+	if !ok {
+		return nil, gitprovider.ErrNotFound
+	}
+	copied := *apiObj
+	return &copied, nil
+}
+
+func (c *fakeOrgReposClient) GetRepoForkingSettings(_ context.Context, _, _ string) (*repositoryForkingSettings, error) {
+	return &repositoryForkingSettings{}, nil
+}
+
+func (c *fakeOrgReposClient) CreateRepo(_ context.Context, _ string, req *github.Repository) (*github.Repository, error) {
+	apiObj := *req
+	c.repos[apiObj.GetName()] = &apiObj
+	c.changes++
+	return c.GetRepo(context.Background(), "", apiObj.GetName())
+}
+
+// UpdateRepo applies the set fields of req, and renames the repository if req.Name differs.
+func (c *fakeOrgReposClient) UpdateRepo(_ context.Context, _, repo string, req *github.Repository) (*github.Repository, error) {
+	apiObj, ok := c.repos[repo]
+	if !ok {
+		return nil, gitprovider.ErrNotFound
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, apiObj); err != nil {
+		return nil, err
+	}
+	delete(c.repos, repo)
+	c.repos[apiObj.GetName()] = apiObj
+	c.changes++
+	return c.GetRepo(context.Background(), "", apiObj.GetName())
+}
+
+func (c *fakeOrgReposClient) CreateFork(_ context.Context, _, repo, _ string) (*github.Repository, error) {
+	if c.forks == nil {
+		c.forks = map[string]*github.Repository{}
+	}
+	c.forks[repo] = &github.Repository{Name: github.String(repo), Fork: github.Bool(true)}
+	c.changes++
+	return &github.Repository{Name: github.String(repo)}, nil
+}
+
+func (c *fakeOrgReposClient) DeleteRepo(_ context.Context, _, repo string) error {
+	delete(c.repos, repo)
+	c.changes++
+	return nil
+}
+
+func newFakeOrgReposClient(destructiveActions bool, descriptions map[string]string) (*OrgRepositoriesClient, *fakeOrgReposClient) {
+	fake := &fakeOrgReposClient{repos: map[string]*github.Repository{}}
+	for name, description := range descriptions {
+		info := gitprovider.RepositoryInfo{Description: gitprovider.StringVar(description)}
+		info.Default()
+		apiObj := repositoryToAPI(&info, gitprovider.OrgRepositoryRef{RepositoryName: name})
+		fake.repos[name] = &apiObj
+	}
+	return &OrgRepositoriesClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain, destructiveActions: destructiveActions},
+	}, fake
+}
+
+func TestOrgRepositoriesClient_ReconcileList(t *testing.T) {
+	org := gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"}
+	desired := []gitprovider.RepositoryReconcileRequest{
+		{Name: "team-a", Info: gitprovider.RepositoryInfo{Description: gitprovider.StringVar("A")}},
+		{Name: "team-b", Info: gitprovider.RepositoryInfo{Description: gitprovider.StringVar("B")}},
+		{Name: "team-c", Info: gitprovider.RepositoryInfo{Description: gitprovider.StringVar("C")}},
+	}
+	actual := map[string]string{"team-a": "A", "team-b": "old", "team-d": "D", "other": "other"}
+	pruneOpts := gitprovider.RepositoryReconcileListOptions{Prune: true, NamePrefix: "team-"}
+	wantResults := []gitprovider.RepositoryReconcileResult{
+		{Name: "team-a", Action: gitprovider.RepositoryReconcileActionNone},
+		{Name: "team-b", Action: gitprovider.RepositoryReconcileActionUpdate},
+		{Name: "team-c", Action: gitprovider.RepositoryReconcileActionCreate},
+	}
+	wantPrunedResults := append(wantResults[:3:3], gitprovider.RepositoryReconcileResult{
+		Name: "team-d", Action: gitprovider.RepositoryReconcileActionDelete,
+	})
+
+	tests := []struct {
+		name               string
+		opts               gitprovider.RepositoryReconcileListOptions
+		destructiveActions bool
+		want               []gitprovider.RepositoryReconcileResult
+		wantRepos          []string
+		expectedErr        error
+	}{
+		{
+			name:      "without pruning",
+			want:      wantResults,
+			wantRepos: []string{"other", "team-a", "team-b", "team-c", "team-d"},
+		},
+		{
+			name:               "pruning within the prefix",
+			opts:               pruneOpts,
+			destructiveActions: true,
+			want:               wantPrunedResults,
+			wantRepos:          []string{"other", "team-a", "team-b", "team-c"},
+		},
+		{
+			name:               "dry run",
+			opts:               gitprovider.RepositoryReconcileListOptions{Prune: true, NamePrefix: "team-", DryRun: true},
+			destructiveActions: true,
+			want:               wantPrunedResults,
+			wantRepos:          []string{"other", "team-a", "team-b", "team-d"},
+		},
+		{
+			name:      "dry run without destructive actions",
+			opts:      gitprovider.RepositoryReconcileListOptions{Prune: true, NamePrefix: "team-", DryRun: true},
+			want:      wantPrunedResults,
+			wantRepos: []string{"other", "team-a", "team-b", "team-d"},
+		},
+		{
+			name:        "pruning without destructive actions",
+			opts:        pruneOpts,
+			wantRepos:   []string{"other", "team-a", "team-b", "team-d"},
+			expectedErr: gitprovider.ErrDestructiveCallDisallowed,
+		},
+		{
+			name:               "pruning without a prefix",
+			opts:               gitprovider.RepositoryReconcileListOptions{Prune: true},
+			destructiveActions: true,
+			wantRepos:          []string{"other", "team-a", "team-b", "team-d"},
+			expectedErr:        validation.ErrFieldRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, fake := newFakeOrgReposClient(tt.destructiveActions, actual)
+			got, err := c.ReconcileList(context.Background(), org, desired, tt.opts)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ReconcileList() error = %v, want %v", err, tt.expectedErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReconcileList() = %v, want %v", got, tt.want)
+			}
+			gotRepos, _ := fake.ListOrgRepos(context.Background(), "")
+			names := make([]string, 0, len(gotRepos))
+			for _, apiObj := range gotRepos {
+				names = append(names, apiObj.GetName())
+			}
+			if !reflect.DeepEqual(names, tt.wantRepos) {
+				t.Errorf("repositories = %v, want %v", names, tt.wantRepos)
+			}
+			if err != nil || tt.opts.DryRun {
+				if fake.changes != 0 {
+					t.Errorf("server got %d changes, want none", fake.changes)
+				}
+				return
+			}
+			// Reconciling again is a no-op
+			changes := fake.changes
+			got, err = c.ReconcileList(context.Background(), org, desired, tt.opts)
+			if err != nil || fake.changes != changes {
+				t.Errorf("ReconcileList() second pass = %d changes, %v, want none", fake.changes-changes, err)
+			}
+			for _, result := range got {
+				if result.Action != gitprovider.RepositoryReconcileActionNone {
+					t.Errorf("ReconcileList() second pass action for %s = %s, want none", result.Name, result.Action)
+				}
+			}
+		})
+	}
+}
+
+func TestOrgRepositoriesClient_ReconcileList_invalid(t *testing.T) {
+	c, _ := newFakeOrgReposClient(true, nil)
+	org := gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"}
+	desired := []gitprovider.RepositoryReconcileRequest{{Name: "foo"}, {Name: "FOO"}}
+	if _, err := c.ReconcileList(context.Background(), org, desired, gitprovider.RepositoryReconcileListOptions{}); !errors.Is(err, validation.ErrFieldInvalid) {
+		t.Errorf("ReconcileList() error = %v, want %v", err, validation.ErrFieldInvalid)
+	}
+}
+
+func TestOrgRepositoriesClient_ForkRepository(t *testing.T) {
+	upstream := gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "upstream"},
+		RepositoryName:  "repo",
+	}
+	org := gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"}
+	tests := []struct {
+		name        string
+		existing    map[string]string
+		forkPolls   int
+		opts        gitprovider.ForkOptions
+		wantName    string
+		expectedErr error
+	}{
+		{
+			name:      "fork created after polling",
+			forkPolls: 3,
+			opts:      gitprovider.ForkOptions{Organization: org, PollInterval: time.Millisecond},
+			wantName:  "repo",
+		},
+		{
+			name:      "fork renamed",
+			forkPolls: 1,
+			opts:      gitprovider.ForkOptions{Organization: org, Name: gitprovider.StringVar("renamed"), PollInterval: time.Millisecond},
+			wantName:  "renamed",
+		},
+		{
+			name:        "fork already exists",
+			existing:    map[string]string{"repo": "fork"},
+			opts:        gitprovider.ForkOptions{Organization: org},
+			expectedErr: gitprovider.ErrAlreadyExists,
+		},
+		{
+			name:        "fork not created in time",
+			forkPolls:   1000,
+			opts:        gitprovider.ForkOptions{Organization: org, Timeout: 10 * time.Millisecond, PollInterval: time.Millisecond},
+			expectedErr: context.DeadlineExceeded,
+		},
+		{
+			name:        "no organization",
+			expectedErr: validation.ErrFieldRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, fake := newFakeOrgReposClient(false, tt.existing)
+			fake.forkPolls = tt.forkPolls
+			repo, err := c.ForkRepository(context.Background(), upstream, tt.opts)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ForkRepository() error = %v, want %v", err, tt.expectedErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := repo.Repository().GetRepository(); got != tt.wantName {
+				t.Errorf("ForkRepository() name = %q, want %q", got, tt.wantName)
+			}
+			if apiObj, ok := fake.repos[tt.wantName]; !ok || !apiObj.GetFork() {
+				t.Errorf("server has no fork named %q", tt.wantName)
+			}
+			if fake.forkPolls != 0 {
+				t.Errorf("fork was polled %d times less than needed", fake.forkPolls)
+			}
+		})
+	}
+}
+
+// fakeTransferClient is a githubClient that keeps repositories by their full name, and transfers them
+// asynchronously: GetRepo returns ErrNotFound for a transferred repository transferPolls times.
+// Calling any other method than the overridden ones panics.
+type fakeTransferClient struct {
+	githubClient
+
+	repos         map[string]*github.Repository
+	transferred   map[string]*github.Repository
+	transferPolls int
+	polls         int
+}
+
+func (c *fakeTransferClient) TransferRepo(_ context.Context, owner, repo, newOwner string) error {
+	apiObj, ok := c.repos[owner+"/"+repo]
+	if !ok {
+		return gitprovider.ErrNotFound
+	}
+	delete(c.repos, owner+"/"+repo)
+	c.transferred[newOwner+"/"+repo] = apiObj
+	return nil
+}
+
+func (c *fakeTransferClient) GetRepo(_ context.Context, owner, repo string) (*github.Repository, error) {
+	c.polls++
+	fullName := owner + "/" + repo
+	if apiObj, ok := c.transferred[fullName]; ok {
+		if c.transferPolls > 0 {
+			c.transferPolls--
+			return nil, gitprovider.ErrNotFound
+		}
+		c.repos[fullName] = apiObj
+		delete(c.transferred, fullName)
+	}
+	apiObj, ok := c.repos[fullName]
+	if !ok {
+		return nil, gitprovider.ErrNotFound
+	}
+	return apiObj, nil
+}
+
+func TestOrgRepositoriesClient_TransferRepository(t *testing.T) {
+	repo := gitprovider.UserRepositoryRef{
+		UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "alice"},
+		RepositoryName: "repo",
+	}
+	org := gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"}
+	tests := []struct {
+		name               string
+		repo               gitprovider.RepositoryRef
+		newOwner           gitprovider.IdentityRef
+		destructiveActions bool
+		transferPolls      int
+		opts               gitprovider.TransferOptions
+		wantRef            string
+		wantPolls          int
+		expectedErr        error
+	}{
+		{
+			name:               "transferred after polling",
+			repo:               repo,
+			newOwner:           org,
+			destructiveActions: true,
+			transferPolls:      3,
+			opts:               gitprovider.TransferOptions{PollInterval: time.Millisecond},
+			wantRef:            "https://github.com/foo/repo",
+			wantPolls:          4,
+		},
+		{
+			name:               "to a user",
+			repo:               repo,
+			newOwner:           gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "bob"},
+			destructiveActions: true,
+			wantRef:            "https://github.com/bob/repo",
+			wantPolls:          1,
+		},
+		{
+			name:               "not transferred in time",
+			repo:               repo,
+			newOwner:           org,
+			destructiveActions: true,
+			transferPolls:      1000,
+			opts:               gitprovider.TransferOptions{Timeout: 10 * time.Millisecond, PollInterval: time.Millisecond},
+			expectedErr:        context.DeadlineExceeded,
+		},
+		{
+			name: "unknown repository",
+			repo: gitprovider.UserRepositoryRef{
+				UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "alice"},
+				RepositoryName: "other",
+			},
+			newOwner:           org,
+			destructiveActions: true,
+			expectedErr:        gitprovider.ErrNotFound,
+		},
+		{
+			name:        "without destructive actions",
+			repo:        repo,
+			newOwner:    org,
+			expectedErr: gitprovider.ErrDestructiveCallDisallowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeTransferClient{
+				repos:         map[string]*github.Repository{"alice/repo": {Name: github.String("repo")}},
+				transferred:   map[string]*github.Repository{},
+				transferPolls: tt.transferPolls,
+			}
+			c := &OrgRepositoriesClient{
+				clientContext: &clientContext{c: fake, domain: DefaultDomain, destructiveActions: tt.destructiveActions},
+			}
+			got, err := c.TransferRepository(context.Background(), tt.repo, tt.newOwner, tt.opts)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("TransferRepository() error = %v, want %v", err, tt.expectedErr)
+			}
+			if errors.Is(err, gitprovider.ErrDestructiveCallDisallowed) {
+				if _, ok := fake.repos["alice/repo"]; !ok || len(fake.transferred) != 0 || fake.polls != 0 {
+					t.Errorf("TransferRepository() made requests without destructive actions")
+				}
+			}
+			if err != nil {
+				return
+			}
+			if ref := got.Repository().String(); ref != tt.wantRef {
+				t.Errorf("TransferRepository() ref = %q, want %q", ref, tt.wantRef)
+			}
+			if _, isOrgRepo := got.(gitprovider.OrgRepository); isOrgRepo != (tt.newOwner.GetType() == gitprovider.IdentityTypeOrganization) {
+				t.Errorf("TransferRepository() returned an OrgRepository = %t for owner %s", isOrgRepo, tt.newOwner)
+			}
+			if fake.polls != tt.wantPolls {
+				t.Errorf("TransferRepository() polled %d times, want %d", fake.polls, tt.wantPolls)
+			}
+		})
+	}
+}
+
+func TestOrgRepositoriesClient_ListRepositories(t *testing.T) {
+	org := gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"}
+	tests := []struct {
+		name      string
+		opts      gitprovider.RepositoryListOptions
+		wantNames []string
+		wantErr   error
+	}{
+		{
+			name:      "no filters",
+			wantNames: []string{"api", "docs", "web"},
+		},
+		{
+			name:      "visibility",
+			opts:      gitprovider.RepositoryListOptions{Visibility: gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic)},
+			wantNames: []string{"docs", "web"},
+		},
+		{
+			name:      "topic, compared case-insensitively",
+			opts:      gitprovider.RepositoryListOptions{Topic: "Frontend"},
+			wantNames: []string{"docs", "web"},
+		},
+		{
+			name: "visibility and topic",
+			opts: gitprovider.RepositoryListOptions{
+				Visibility: gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPrivate),
+				Topic:      "go",
+			},
+			wantNames: []string{"api"},
+		},
+		{
+			name:    "invalid topic",
+			opts:    gitprovider.RepositoryListOptions{Topic: "front end"},
+			wantErr: validation.ErrFieldInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, fake := newFakeOrgReposClient(false, nil)
+			fake.repos = map[string]*github.Repository{
+				"api":  {Name: github.String("api"), Visibility: github.String("private"), Topics: []string{"go"}},
+				"docs": {Name: github.String("docs"), Visibility: github.String("public"), Topics: []string{"frontend"}},
+				"web":  {Name: github.String("web"), Visibility: github.String("public"), Topics: []string{"frontend", "go"}},
+			}
+
+			repos, err := c.ListRepositories(context.Background(), org, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ListRepositories() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			names := make([]string, 0, len(repos))
+			for _, repo := range repos {
+				names = append(names, repo.Repository().GetRepository())
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("ListRepositories() = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// fakeUserClient is a githubClient returning a canned authenticated user, or err if set.
+// Calling any other method than the overridden ones panics.
+type fakeUserClient struct {
+	githubClient
+
+	user *github.User
+	err  error
+}
+
+func (c *fakeUserClient) GetAuthenticatedUser(_ context.Context) (*github.User, error) {
+	return c.user, c.err
+}
+
+func TestUserRepositoriesClient_GetAuthenticatedUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		fake    *fakeUserClient
+		want    gitprovider.UserInfo
+		wantErr bool
+	}{
+		{
+			name: "authenticated",
+			fake: &fakeUserClient{user: &github.User{Login: github.String("fluxcd-bot")}},
+			want: gitprovider.UserInfo{Domain: DefaultDomain, Login: "fluxcd-bot"},
+		},
+		{
+			name:    "unauthenticated",
+			fake:    &fakeUserClient{err: &gitprovider.InvalidCredentialsError{}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &UserRepositoriesClient{
+				clientContext: &clientContext{c: tt.fake, domain: DefaultDomain},
+			}
+			got, err := c.GetAuthenticatedUser(context.Background())
+			if tt.wantErr {
+				var credsErr *gitprovider.InvalidCredentialsError
+				if !errors.As(err, &credsErr) {
+					t.Fatalf("GetAuthenticatedUser() error = %v, want %T", err, credsErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetAuthenticatedUser() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetAuthenticatedUser() = %v, want %v", got, tt.want)
+			}
+			if ref := got.UserRef(); ref.UserLogin != "fluxcd-bot" || ref.Domain != DefaultDomain {
+				t.Errorf("UserRef() = %v", ref)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// BranchClient implements the gitprovider.BranchClient interface.
+var _ gitprovider.BranchClient = &BranchClient{}
+
+// BranchClient operates on the branches of a specific repository.
+type BranchClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get a branch by its name.
+//
+// ErrNotFound is returned if the branch does not exist.
+func (c *BranchClient) Get(ctx context.Context, name string) (gitprovider.Branch, error) {
+	// GET /repos/{owner}/{repo}/branches/{branch}
+	apiObj, err := c.c.GetBranch(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), name)
+	if err != nil {
+		return nil, err
+	}
+	return newBranch(c.clientContext, apiObj, c.ref), nil
+}
+
+// List lists all branches of the repository, along with whether they are protected.
+//
+// List returns all available branches, using multiple paginated requests if needed.
+func (c *BranchClient) List(ctx context.Context) ([]gitprovider.Branch, error) {
+	if err := validateRepositoryRef(c.ref, c.domain); err != nil {
+		return nil, err
+	}
+	// GET /repos/{owner}/{repo}/branches
+	apiObjs, err := c.c.ListBranches(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+	// Map the api object to our Branch type
+	branches := make([]gitprovider.Branch, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListBranches
+		branches = append(branches, newBranch(c.clientContext, apiObj, c.ref))
+	}
+	return branches, nil
+}
+
+// Create creates a branch with the given name, pointing at the commit fromSHA. If fromSHA is
+// empty, the branch points at the head of the default branch of the repository.
+//
+// ErrAlreadyExists is returned if the branch already exists.
+// ErrNotFound is returned if fromSHA isn't a commit of the repository.
+func (c *BranchClient) Create(ctx context.Context, branchName, fromSHA string) error {
+	if err := gitprovider.ValidateBranchName(branchName); err != nil {
+		return validation.NewMultiError(err, gitprovider.ErrInvalidArgument)
+	}
+	owner, repo := c.ref.GetIdentity(), c.ref.GetRepository()
+
+	// GET /repos/{owner}/{repo}/branches/{branch}
+	_, err := c.c.GetBranch(ctx, owner, repo, branchName)
+	if err == nil {
+		return gitprovider.ErrAlreadyExists
+	} else if !errors.Is(err, gitprovider.ErrNotFound) {
+		return err
+	}
+
+	sha, err := c.resolveSHA(ctx, fromSHA)
+	if err != nil {
+		return err
+	}
+	// POST /repos/{owner}/{repo}/git/refs
+	_, err = c.c.CreateRef(ctx, owner, repo, &github.Reference{
+		Ref:    github.String("refs/heads/" + branchName),
+		Object: &github.GitObject{SHA: github.String(sha)},
+	})
+	return err
+}
+
+// IsBranchMerged returns true if all commits of the given branch are part of the default branch
+// of the repository, i.e. if the branch isn't ahead of the default branch.
+//
+// ErrNotFound is returned if the branch does not exist.
+func (c *BranchClient) IsBranchMerged(ctx context.Context, branch string) (bool, error) {
+	owner, repo := c.ref.GetIdentity(), c.ref.GetRepository()
+	// GET /repos/{owner}/{repo}
+	repoObj, err := c.c.GetRepo(ctx, owner, repo)
+	if err != nil {
+		return false, err
+	}
+	// GET /repos/{owner}/{repo}/compare/{base}...{head}
+	apiObj, err := c.c.CompareCommits(ctx, owner, repo, repoObj.GetDefaultBranch(), branch)
+	if err != nil {
+		return false, err
+	}
+	return *apiObj.AheadBy == 0, nil
+}
+
+// DeleteMergedBranches deletes the merged branches whose last commit is older than opts.MinAge.
+// The default branch and protected branches are never deleted. This requires destructive actions
+// to be enabled in the client.
+//
+// The names of the deleted branches are returned, along with the reason why each other branch was
+// skipped. If some branches failed to be checked or deleted, a *validation.MultiError with an error
+// per branch is returned too.
+func (c *BranchClient) DeleteMergedBranches(ctx context.Context, opts gitprovider.DeleteMergedBranchesOptions) ([]string, map[string]gitprovider.BranchSkipReason, error) {
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, nil, err
+	}
+	if !c.destructiveActions {
+		return nil, nil, fmt.Errorf("cannot delete merged branches: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	owner, repo := c.ref.GetIdentity(), c.ref.GetRepository()
+
+	// GET /repos/{owner}/{repo}
+	repoObj, err := c.c.GetRepo(ctx, owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	// GET /repos/{owner}/{repo}/branches
+	apiObjs, err := c.c.ListBranches(ctx, owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The default and protected branches can be skipped without further requests
+	skipped := map[string]gitprovider.BranchSkipReason{}
+	candidates := make([]*github.Branch, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		switch {
+		case *apiObj.Name == repoObj.GetDefaultBranch():
+			skipped[*apiObj.Name] = gitprovider.BranchSkipReasonDefault
+		case apiObj.GetProtected():
+			skipped[*apiObj.Name] = gitprovider.BranchSkipReasonProtected
+		default:
+			candidates = append(candidates, apiObj)
+		}
+	}
+
+	reasons := make([]gitprovider.BranchSkipReason, len(candidates))
+	errs := make([]error, len(candidates))
+	forEachBounded(len(candidates), opts.GetConcurrency(), func(i int) {
+		reasons[i], errs[i] = c.deleteIfMerged(ctx, repoObj.GetDefaultBranch(), candidates[i], opts.MinAge)
+	})
+
+	deleted := []string{}
+	failed := []error{}
+	for i, apiObj := range candidates {
+		switch {
+		case errs[i] != nil:
+			failed = append(failed, errs[i])
+		case reasons[i] != "":
+			skipped[*apiObj.Name] = reasons[i]
+		default:
+			deleted = append(deleted, *apiObj.Name)
+		}
+	}
+	if len(failed) != 0 {
+		return deleted, skipped, validation.NewMultiError(failed...)
+	}
+	return deleted, skipped, nil
+}
+
+// deleteIfMerged deletes the (unprotected, non-default) branch if it is merged into defaultBranch, and
+// its last commit is at least minAge old. Otherwise, the reason for not deleting it is returned.
+func (c *BranchClient) deleteIfMerged(ctx context.Context, defaultBranch string, apiObj *github.Branch, minAge time.Duration) (gitprovider.BranchSkipReason, error) {
+	owner, repo, name := c.ref.GetIdentity(), c.ref.GetRepository(), *apiObj.Name
+	// GET /repos/{owner}/{repo}/compare/{base}...{head}
+	comparison, err := c.c.CompareCommits(ctx, owner, repo, defaultBranch, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to check whether branch %q is merged: %w", name, err)
+	}
+	if *comparison.AheadBy != 0 {
+		return gitprovider.BranchSkipReasonNotMerged, nil
+	}
+	if minAge != 0 {
+		// GET /repos/{owner}/{repo}/git/commits/{commit_sha}
+		commit, err := c.c.GetCommit(ctx, owner, repo, *apiObj.Commit.SHA)
+		if err != nil {
+			return "", fmt.Errorf("failed to get the last commit of branch %q: %w", name, err)
+		}
+		if time.Since(commit.GetCommitter().GetDate()) < minAge {
+			return gitprovider.BranchSkipReasonTooRecent, nil
+		}
+	}
+	// DELETE /repos/{owner}/{repo}/git/refs/{ref}
+	if err := c.c.DeleteRef(ctx, owner, repo, "heads/"+name); err != nil {
+		return "", fmt.Errorf("failed to delete branch %q: %w", name, err)
+	}
+	return "", nil
+}
+
+// resolveSHA returns the full SHA of the commit fromSHA, or of the head of the default branch if
+// fromSHA is empty.
+func (c *BranchClient) resolveSHA(ctx context.Context, fromSHA string) (string, error) {
+	owner, repo := c.ref.GetIdentity(), c.ref.GetRepository()
+	if fromSHA != "" {
+		// GET /repos/{owner}/{repo}/git/commits/{commit_sha}
+		apiObj, err := c.c.GetCommit(ctx, owner, repo, fromSHA)
+		if err != nil {
+			return "", err
+		}
+		return *apiObj.SHA, nil
+	}
+
+	// GET /repos/{owner}/{repo}
+	repoObj, err := c.c.GetRepo(ctx, owner, repo)
+	if err != nil {
+		return "", err
+	}
+	// GET /repos/{owner}/{repo}/branches/{branch}
+	apiObj, err := c.c.GetBranch(ctx, owner, repo, repoObj.GetDefaultBranch())
+	if err != nil {
+		return "", err
+	}
+	return *apiObj.Commit.SHA, nil
+}
@@ -0,0 +1,334 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// fakeBranchClient is a githubClient that keeps the branches and commits of a single repository
+// in memory. It is safe for concurrent use. Calling any other method than the overridden ones panics.
+type fakeBranchClient struct {
+	githubClient
+	mu sync.Mutex
+
+	defaultBranch string
+	// branches maps branch names to the SHAs they point at
+	branches map[string]string
+	// commits holds the SHAs of the existing commits
+	commits map[string]bool
+	// aheadBy maps branch names to the amount of commits they are ahead of the default branch
+	aheadBy map[string]int
+	// protected holds the names of the protected branches
+	protected map[string]bool
+	// commitDates maps commit SHAs to their commit date, which is the zero time if unset
+	commitDates map[string]time.Time
+	// failDeletes holds the names of the branches that can't be deleted
+	failDeletes map[string]bool
+}
+
+func (c *fakeBranchClient) GetRepo(_ context.Context, _, repo string) (*github.Repository, error) {
+	return &github.Repository{Name: github.String(repo), DefaultBranch: github.String(c.defaultBranch)}, nil
+}
+
+func (c *fakeBranchClient) ListBranches(ctx context.Context, owner, repo string) ([]*github.Branch, error) {
+	names := []string{}
+	for name := range c.branches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	apiObjs := []*github.Branch{}
+	for _, name := range names {
+		apiObj, _ := c.GetBranch(ctx, owner, repo, name)
+		apiObj.Protected = github.Bool(c.protected[name])
+		apiObjs = append(apiObjs, apiObj)
+	}
+	return apiObjs, nil
+}
+
+func (c *fakeBranchClient) GetBranch(_ context.Context, _, _, branch string) (*github.Branch, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sha, ok := c.branches[branch]
+	if !ok {
+		return nil, gitprovider.ErrNotFound
+	}
+	return &github.Branch{Name: github.String(branch), Commit: &github.RepositoryCommit{SHA: github.String(sha)}}, nil
+}
+
+func (c *fakeBranchClient) GetCommit(_ context.Context, _, _, sha string) (*github.Commit, error) {
+	if !c.commits[sha] {
+		return nil, gitprovider.ErrNotFound
+	}
+	date := c.commitDates[sha]
+	return &github.Commit{SHA: github.String(sha), Committer: &github.CommitAuthor{Date: &date}}, nil
+}
+
+func (c *fakeBranchClient) CompareCommits(_ context.Context, _, _, base, head string) (*github.CommitsComparison, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.branches[head]; !ok || base != c.defaultBranch {
+		return nil, gitprovider.ErrNotFound
+	}
+	return &github.CommitsComparison{AheadBy: github.Int(c.aheadBy[head])}, nil
+}
+
+func (c *fakeBranchClient) CreateRef(_ context.Context, _, _ string, req *github.Reference) (*github.Reference, error) {
+	c.branches[strings.TrimPrefix(req.GetRef(), "refs/heads/")] = req.GetObject().GetSHA()
+	return req, nil
+}
+
+func (c *fakeBranchClient) DeleteRef(_ context.Context, _, _, ref string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	name := strings.TrimPrefix(ref, "heads/")
+	if c.failDeletes[name] {
+		return errors.New("server error")
+	}
+	delete(c.branches, name)
+	return nil
+}
+
+// pagesRoundTripper serves list requests, e.g. "GET /repos/{owner}/{repo}/branches", in pages of
+// the given raw JSON arrays, linking each page to the next one like GitHub does.
+type pagesRoundTripper struct {
+	pages    []string
+	requests []string
+}
+
+func (rt *pagesRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.requests = append(rt.requests, req.URL.RequestURI())
+	page := 1
+	if p := req.URL.Query().Get("page"); p != "" {
+		_, _ = fmt.Sscanf(p, "%d", &page)
+	}
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	if page < len(rt.pages) {
+		header.Set("Link", fmt.Sprintf(`<https://api.github.com%s?page=%d>; rel="next"`, req.URL.Path, page+1))
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader(rt.pages[page-1])),
+		Request:    req,
+	}, nil
+}
+
+func TestBranchClient_List(t *testing.T) {
+	rt := &pagesRoundTripper{pages: []string{
+		`[{"name":"main","commit":{"sha":"aaa"},"protected":true},{"name":"feature","commit":{"sha":"bbb"},"protected":false}]`,
+		`[{"name":"release","commit":{"sha":"ccc"},"protected":true}]`,
+	}}
+	c := &BranchClient{
+		clientContext: &clientContext{
+			c:      &githubClientImpl{c: github.NewClient(&http.Client{Transport: rt})},
+			domain: DefaultDomain,
+		},
+		ref: gitprovider.OrgRepositoryRef{
+			OrganizationRef: gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"},
+			RepositoryName:  "bar",
+		},
+	}
+
+	branches, err := c.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	got := []gitprovider.BranchInfo{}
+	for _, branch := range branches {
+		got = append(got, branch.Get())
+	}
+	want := []gitprovider.BranchInfo{
+		{Name: "main", Sha: "aaa", Protected: true},
+		{Name: "feature", Sha: "bbb"},
+		{Name: "release", Sha: "ccc", Protected: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %+v, want %+v", got, want)
+	}
+	wantRequests := []string{"/repos/foo/bar/branches", "/repos/foo/bar/branches?page=2"}
+	if !reflect.DeepEqual(rt.requests, wantRequests) {
+		t.Errorf("List() requests = %v, want %v", rt.requests, wantRequests)
+	}
+
+	// An empty ref is rejected before calling the server
+	c.ref = gitprovider.OrgRepositoryRef{OrganizationRef: c.ref.(gitprovider.OrgRepositoryRef).OrganizationRef}
+	if _, err := c.List(context.Background()); !errors.Is(err, validation.ErrFieldRequired) {
+		t.Errorf("List() error = %v, want %v", err, validation.ErrFieldRequired)
+	}
+	if len(rt.requests) != 2 {
+		t.Errorf("List() made %d requests, want 2", len(rt.requests))
+	}
+}
+
+func TestBranchClient_Create(t *testing.T) {
+	tests := []struct {
+		name        string
+		branchName  string
+		fromSHA     string
+		wantSHA     string
+		expectedErr error
+	}{
+		{
+			name:       "defaults to the head of the default branch",
+			branchName: "feature",
+			wantSHA:    "head",
+		},
+		{
+			name:       "from a commit",
+			branchName: "feature",
+			fromSHA:    "old",
+			wantSHA:    "old",
+		},
+		{
+			name:        "unknown commit",
+			branchName:  "feature",
+			fromSHA:     "unknown",
+			expectedErr: gitprovider.ErrNotFound,
+		},
+		{
+			name:        "existing branch",
+			branchName:  "main",
+			expectedErr: gitprovider.ErrAlreadyExists,
+		},
+		{
+			name:        "invalid name",
+			branchName:  "",
+			expectedErr: gitprovider.ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeBranchClient{
+				defaultBranch: "main",
+				branches:      map[string]string{"main": "head"},
+				commits:       map[string]bool{"head": true, "old": true},
+			}
+			c := &BranchClient{
+				clientContext: &clientContext{c: fake, domain: DefaultDomain},
+				ref: gitprovider.UserRepositoryRef{
+					UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+					RepositoryName: "bar",
+				},
+			}
+			err := c.Create(context.Background(), tt.branchName, tt.fromSHA)
+			if !errors.Is(err, tt.expectedErr) || (err != nil && tt.expectedErr == nil) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+			branch, err := c.Get(context.Background(), tt.branchName)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got := branch.Get(); got.Name != tt.branchName || got.Sha != tt.wantSHA {
+				t.Errorf("Get() = %+v, want sha %q", got, tt.wantSHA)
+			}
+		})
+	}
+}
+
+func TestBranchClient_IsBranchMerged(t *testing.T) {
+	fake := &fakeBranchClient{
+		defaultBranch: "main",
+		branches:      map[string]string{"main": "head", "merged": "old", "feature": "new"},
+		aheadBy:       map[string]int{"feature": 2},
+	}
+	c := &BranchClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain},
+		ref: gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+			RepositoryName: "bar",
+		},
+	}
+	for branch, want := range map[string]bool{"merged": true, "feature": false} {
+		got, err := c.IsBranchMerged(context.Background(), branch)
+		if err != nil || got != want {
+			t.Errorf("IsBranchMerged(%q) = %v, %v, want %v, nil", branch, got, err, want)
+		}
+	}
+	if _, err := c.IsBranchMerged(context.Background(), "unknown"); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("IsBranchMerged() error = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+}
+
+func TestBranchClient_DeleteMergedBranches(t *testing.T) {
+	now := time.Now()
+	fake := &fakeBranchClient{
+		defaultBranch: "main",
+		branches: map[string]string{
+			"main": "head", "release": "head", "feature": "new", "recent": "recent",
+			"old": "old", "older": "old", "failing": "old",
+		},
+		commits:     map[string]bool{"head": true, "new": true, "recent": true, "old": true},
+		aheadBy:     map[string]int{"feature": 1},
+		protected:   map[string]bool{"release": true},
+		commitDates: map[string]time.Time{"recent": now.Add(-time.Hour), "old": now.Add(-30 * 24 * time.Hour)},
+		failDeletes: map[string]bool{"failing": true},
+	}
+	c := &BranchClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain},
+		ref: gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+			RepositoryName: "bar",
+		},
+	}
+	opts := gitprovider.DeleteMergedBranchesOptions{MinAge: 7 * 24 * time.Hour, Concurrency: 2}
+
+	// Nothing is deleted without destructive actions
+	if _, _, err := c.DeleteMergedBranches(context.Background(), opts); !errors.Is(err, gitprovider.ErrDestructiveCallDisallowed) {
+		t.Fatalf("DeleteMergedBranches() error = %v, want %v", err, gitprovider.ErrDestructiveCallDisallowed)
+	}
+
+	c.destructiveActions = true
+	deleted, skipped, err := c.DeleteMergedBranches(context.Background(), opts)
+	multiErr := &validation.MultiError{}
+	if !errors.As(err, &multiErr) || len(multiErr.Errors) != 1 || !strings.Contains(err.Error(), `"failing"`) {
+		t.Errorf("DeleteMergedBranches() error = %v, want an error for the failing branch", err)
+	}
+	if want := []string{"old", "older"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("DeleteMergedBranches() deleted = %v, want %v", deleted, want)
+	}
+	wantSkipped := map[string]gitprovider.BranchSkipReason{
+		"main":    gitprovider.BranchSkipReasonDefault,
+		"release": gitprovider.BranchSkipReasonProtected,
+		"feature": gitprovider.BranchSkipReasonNotMerged,
+		"recent":  gitprovider.BranchSkipReasonTooRecent,
+	}
+	if !reflect.DeepEqual(skipped, wantSkipped) {
+		t.Errorf("DeleteMergedBranches() skipped = %v, want %v", skipped, wantSkipped)
+	}
+	if _, ok := fake.branches["old"]; ok {
+		t.Error("DeleteMergedBranches() didn't delete the branch on the server")
+	}
+}
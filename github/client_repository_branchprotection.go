@@ -0,0 +1,190 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// BranchProtectionClient implements the gitprovider.BranchProtectionClient interface.
+var _ gitprovider.BranchProtectionClient = &BranchProtectionClient{}
+
+// BranchProtectionClient operates on the branch protection rules of a specific repository.
+type BranchProtectionClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the protection rules of the given branch.
+//
+// ErrNotFound is returned if the branch isn't protected, or doesn't exist.
+func (c *BranchProtectionClient) Get(ctx context.Context, branch string) (gitprovider.BranchProtection, error) {
+	return c.get(ctx, branch)
+}
+
+func (c *BranchProtectionClient) get(ctx context.Context, branch string) (*branchProtection, error) {
+	// GET /repos/{owner}/{repo}/branches/{branch}/protection
+	apiObj, err := c.c.GetBranchProtection(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch)
+	if err != nil {
+		return nil, err
+	}
+	return newBranchProtection(c, branch, apiObj), nil
+}
+
+// Create protects a branch with the given rules.
+//
+// ErrAlreadyExists will be returned if the branch already is protected.
+func (c *BranchProtectionClient) Create(ctx context.Context, req gitprovider.BranchProtectionInfo) (gitprovider.BranchProtection, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, err
+	}
+	// Protecting a branch replaces any existing protection, hence check for it first
+	if _, err := c.get(ctx, req.Branch); err == nil {
+		return nil, gitprovider.ErrAlreadyExists
+	} else if !errors.Is(err, gitprovider.ErrNotFound) {
+		return nil, err
+	}
+	return c.create(ctx, req)
+}
+
+func (c *BranchProtectionClient) create(ctx context.Context, req gitprovider.BranchProtectionInfo) (*branchProtection, error) {
+	if err := validateBranchProtectionInfo(req); err != nil {
+		return nil, err
+	}
+	// PUT /repos/{owner}/{repo}/branches/{branch}/protection
+	apiObj, err := c.c.UpdateBranchProtection(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), req.Branch, branchProtectionToRequest(branchProtectionToAPI(&req)))
+	if err != nil {
+		return nil, err
+	}
+	return newBranchProtection(c, req.Branch, apiObj), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+// The protection is looked up by the name of its branch, and the rules are compared field by field.
+// The settings of an existing protection that aren't modelled in gitprovider.BranchProtectionInfo are kept.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *BranchProtectionClient) Reconcile(ctx context.Context, req gitprovider.BranchProtectionInfo) (gitprovider.BranchProtection, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	// Get the protection of the desired branch
+	actual, err := c.get(ctx, req.Branch)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			resp, err := c.create(ctx, req)
+			if err != nil {
+				return nil, false, err
+			}
+			return resp, true, nil
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Populate the desired state to the current-actual object
+	if err := actual.Set(req); err != nil {
+		return actual, false, err
+	}
+	// Apply the desired state by running Update
+	return actual, true, actual.Update(ctx)
+}
+
+// ApplyProtectionTemplate protects the default branch of each of the given repositories with the
+// protection of the template, like BranchProtectionClient.Reconcile does. At most
+// template.Concurrency repositories are processed at once.
+//
+// The result has an entry per repository, which is nil if its default branch is protected as desired.
+// A failing repository doesn't abort the others. Repositories whose default branch doesn't exist, e.g.
+// empty ones, are skipped with gitprovider.ErrDefaultBranchNotFound. The returned error is only set if
+// the template or the refs are invalid, in which case no repository is changed.
+//
+// The refs are the keys of the result, hence their types must be comparable. Pass organization
+// repositories as *gitprovider.OrgRepositoryRef, as gitprovider.OrgRepositoryRef isn't comparable.
+func (c *Client) ApplyProtectionTemplate(ctx context.Context, repos []gitprovider.RepositoryRef, template gitprovider.BranchProtectionTemplate) (map[gitprovider.RepositoryRef]error, error) {
+	if err := template.ValidateTemplate(); err != nil {
+		return nil, err
+	}
+	seen := make(map[gitprovider.RepositoryRef]struct{}, len(repos))
+	for _, ref := range repos {
+		if ref == nil || !reflect.TypeOf(ref).Comparable() {
+			return nil, fmt.Errorf("repository %v can't be a key of the result, pass a pointer: %w", ref, gitprovider.ErrInvalidArgument)
+		}
+		if err := validateRepositoryRef(ref, c.domain); err != nil {
+			return nil, err
+		}
+		if _, ok := seen[ref]; ok {
+			return nil, fmt.Errorf("repository %s given twice: %w", ref, gitprovider.ErrInvalidArgument)
+		}
+		seen[ref] = struct{}{}
+	}
+
+	errs := make([]error, len(repos))
+	forEachBounded(len(repos), template.GetConcurrency(), func(i int) {
+		errs[i] = c.applyProtectionTemplate(ctx, repos[i], template.Protection)
+	})
+	result := make(map[gitprovider.RepositoryRef]error, len(repos))
+	for i, ref := range repos {
+		result[ref] = errs[i]
+	}
+	return result, nil
+}
+
+// applyProtectionTemplate protects the default branch of the repository with protection.
+func (c *Client) applyProtectionTemplate(ctx context.Context, ref gitprovider.RepositoryRef, protection gitprovider.BranchProtectionInfo) error {
+	// GET /repos/{owner}/{repo}
+	apiObj, err := c.c.GetRepo(ctx, ref.GetIdentity(), ref.GetRepository())
+	if err != nil {
+		return err
+	}
+	// Skip the repository if it has no default branch, or if the branch doesn't exist as the
+	// repository is empty
+	branch := apiObj.GetDefaultBranch()
+	if branch == "" {
+		return gitprovider.ErrDefaultBranchNotFound
+	}
+	// GET /repos/{owner}/{repo}/branches/{branch}
+	if _, err := c.c.GetBranch(ctx, ref.GetIdentity(), ref.GetRepository(), branch); errors.Is(err, gitprovider.ErrNotFound) {
+		return fmt.Errorf("%w: %q", gitprovider.ErrDefaultBranchNotFound, branch)
+	} else if err != nil {
+		return err
+	}
+
+	protection.Branch = branch
+	bpc := &BranchProtectionClient{clientContext: c.clientContext, ref: ref}
+	_, _, err = bpc.Reconcile(ctx, protection)
+	return err
+}
@@ -0,0 +1,343 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// fakeBranchProtectionClient is a githubClient that keeps the branch protections of a single
+// repository in memory. Calling any other method than the overridden ones panics.
+type fakeBranchProtectionClient struct {
+	githubClient
+
+	protections map[string]*github.Protection
+	requests    []*github.ProtectionRequest
+}
+
+func (c *fakeBranchProtectionClient) GetBranchProtection(_ context.Context, _, _, branch string) (*github.Protection, error) {
+	apiObj, ok := c.protections[branch]
+	if !ok {
+		return nil, gitprovider.ErrNotFound
+	}
+	return apiObj, nil
+}
+
+func (c *fakeBranchProtectionClient) UpdateBranchProtection(_ context.Context, _, _, branch string, req *github.ProtectionRequest) (*github.Protection, error) {
+	c.requests = append(c.requests, req)
+	// Like the real server, respond with the protection as given in the request
+	apiObj := &github.Protection{
+		RequiredStatusChecks: req.RequiredStatusChecks,
+		EnforceAdmins:        &github.AdminEnforcement{Enabled: req.EnforceAdmins},
+	}
+	if reviews := req.RequiredPullRequestReviews; reviews != nil {
+		apiObj.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcement{
+			DismissStaleReviews:          reviews.DismissStaleReviews,
+			RequireCodeOwnerReviews:      reviews.RequireCodeOwnerReviews,
+			RequiredApprovingReviewCount: reviews.RequiredApprovingReviewCount,
+		}
+	}
+	if req.RequireLinearHistory != nil {
+		apiObj.RequireLinearHistory = &github.RequireLinearHistory{Enabled: *req.RequireLinearHistory}
+	}
+	if restrictions := req.Restrictions; restrictions != nil {
+		apiObj.Restrictions = &github.BranchRestrictions{}
+		for _, login := range restrictions.Users {
+			apiObj.Restrictions.Users = append(apiObj.Restrictions.Users, &github.User{Login: github.String(login)})
+		}
+		for _, slug := range restrictions.Teams {
+			apiObj.Restrictions.Teams = append(apiObj.Restrictions.Teams, &github.Team{Slug: github.String(slug)})
+		}
+	}
+	c.protections[branch] = apiObj
+	return apiObj, nil
+}
+
+func TestBranchProtectionClient_Reconcile(t *testing.T) {
+	fake := &fakeBranchProtectionClient{protections: map[string]*github.Protection{}}
+	c := &BranchProtectionClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain},
+		ref: gitprovider.OrgRepositoryRef{
+			OrganizationRef: gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"},
+			RepositoryName:  "bar",
+		},
+	}
+	ctx := context.Background()
+
+	// An unprotected branch isn't found
+	if _, err := c.Get(ctx, "main"); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Fatalf("Get() error = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+
+	// The first pass protects the branch
+	req := gitprovider.BranchProtectionInfo{
+		Branch:               "main",
+		RequiredReviewCount:  2,
+		RequiredStatusChecks: []string{"test", "lint", "test"},
+		EnforceAdmins:        true,
+		RestrictPushes:       true,
+		PushTeams:            []string{"maintainers"},
+	}
+	bp, actionTaken, err := c.Reconcile(ctx, req)
+	if err != nil || !actionTaken {
+		t.Fatalf("Reconcile() = %v, %v, want true, nil", actionTaken, err)
+	}
+	want := gitprovider.BranchProtectionInfo{
+		Branch:               "main",
+		RequiredReviewCount:  2,
+		RequiredStatusChecks: []string{"lint", "test"},
+		EnforceAdmins:        true,
+		RestrictPushes:       true,
+		PushUsers:            []string{},
+		PushTeams:            []string{"maintainers"},
+		RequireLinearHistory: gitprovider.BoolVar(false),
+	}
+	if got := bp.Get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Reconcile() = %+v, want %+v", got, want)
+	}
+	if restrictions := fake.requests[0].Restrictions; restrictions == nil || restrictions.Users == nil {
+		t.Errorf("Reconcile() restrictions = %+v, want non-nil users", restrictions)
+	}
+
+	// Reconciling the same state, in another order, is a no-op
+	req.RequiredStatusChecks = []string{"lint", "test"}
+	if _, actionTaken, err := c.Reconcile(ctx, req); err != nil || actionTaken {
+		t.Fatalf("Reconcile() = %v, %v, want false, nil", actionTaken, err)
+	}
+	if len(fake.requests) != 1 {
+		t.Errorf("server got %d writes, want 1", len(fake.requests))
+	}
+
+	// A changed field is updated, keeping the settings that aren't modelled
+	fake.protections["main"].RequiredStatusChecks.Strict = true
+	fake.protections["main"].RequiredPullRequestReviews.DismissStaleReviews = true
+	req.RequiredReviewCount = 1
+	req.RestrictPushes, req.PushTeams = false, nil
+	if _, actionTaken, err := c.Reconcile(ctx, req); err != nil || !actionTaken {
+		t.Fatalf("Reconcile() = %v, %v, want true, nil", actionTaken, err)
+	}
+	update := fake.requests[1]
+	if !update.RequiredStatusChecks.Strict || !update.RequiredPullRequestReviews.DismissStaleReviews {
+		t.Errorf("Reconcile() didn't keep the strict status checks or dismissing stale reviews")
+	}
+	if update.RequiredPullRequestReviews.RequiredApprovingReviewCount != 1 || update.Restrictions != nil {
+		t.Errorf("Reconcile() reviews, restrictions = %+v, %+v", update.RequiredPullRequestReviews, update.Restrictions)
+	}
+
+	// Requiring linear history is detected as drift, and applied
+	req.RequireLinearHistory = gitprovider.BoolVar(true)
+	if _, actionTaken, err := c.Reconcile(ctx, req); err != nil || !actionTaken {
+		t.Fatalf("Reconcile() = %v, %v, want true, nil", actionTaken, err)
+	}
+	if update := fake.requests[2]; update.RequireLinearHistory == nil || !*update.RequireLinearHistory {
+		t.Errorf("Reconcile() RequireLinearHistory = %v, want true", update.RequireLinearHistory)
+	}
+	// Leaving it unset keeps it as-is
+	req.RequireLinearHistory = nil
+	if _, actionTaken, err := c.Reconcile(ctx, req); err != nil || actionTaken {
+		t.Fatalf("Reconcile() = %v, %v, want false, nil", actionTaken, err)
+	}
+
+	// GitHub can't require more than 6 reviews
+	req.RequiredReviewCount = 7
+	if _, _, err := c.Reconcile(ctx, req); err == nil {
+		t.Error("Reconcile() error = nil for 7 required reviews")
+	}
+}
+
+// fakeProtectionTemplateClient is a githubClient that keeps the default branches and their
+// protections of several repositories in memory, and records how many repositories were
+// processed at once. Calling any other method than the overridden ones panics.
+type fakeProtectionTemplateClient struct {
+	githubClient
+
+	mu sync.Mutex
+	// defaultBranches maps the names of the repositories to their default branches
+	defaultBranches map[string]string
+	// emptyRepos are the repositories whose default branch doesn't exist
+	emptyRepos map[string]bool
+	// protections maps the names of the repositories to the protections of their branches
+	protections map[string]map[string]*github.Protection
+	inflight    int
+	maxInflight int
+}
+
+func (c *fakeProtectionTemplateClient) GetRepo(_ context.Context, _, repo string) (*github.Repository, error) {
+	c.mu.Lock()
+	c.inflight++
+	if c.inflight > c.maxInflight {
+		c.maxInflight = c.inflight
+	}
+	branch, ok := c.defaultBranches[repo]
+	c.mu.Unlock()
+	// Give the other repositories the chance to be processed at the same time
+	time.Sleep(10 * time.Millisecond)
+	c.mu.Lock()
+	c.inflight--
+	c.mu.Unlock()
+	if !ok {
+		return nil, gitprovider.ErrNotFound
+	}
+	return &github.Repository{Name: github.String(repo), DefaultBranch: github.String(branch)}, nil
+}
+
+func (c *fakeProtectionTemplateClient) GetBranch(_ context.Context, _, repo, branch string) (*github.Branch, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.emptyRepos[repo] || c.defaultBranches[repo] != branch {
+		return nil, gitprovider.ErrNotFound
+	}
+	return &github.Branch{Name: github.String(branch)}, nil
+}
+
+func (c *fakeProtectionTemplateClient) GetBranchProtection(_ context.Context, _, repo, branch string) (*github.Protection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	apiObj, ok := c.protections[repo][branch]
+	if !ok {
+		return nil, gitprovider.ErrNotFound
+	}
+	return apiObj, nil
+}
+
+func (c *fakeProtectionTemplateClient) UpdateBranchProtection(_ context.Context, _, repo, branch string, req *github.ProtectionRequest) (*github.Protection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	apiObj := &github.Protection{
+		RequiredStatusChecks: req.RequiredStatusChecks,
+		EnforceAdmins:        &github.AdminEnforcement{Enabled: req.EnforceAdmins},
+	}
+	if c.protections[repo] == nil {
+		c.protections[repo] = map[string]*github.Protection{}
+	}
+	c.protections[repo][branch] = apiObj
+	return apiObj, nil
+}
+
+func TestClient_ApplyProtectionTemplate(t *testing.T) {
+	fake := &fakeProtectionTemplateClient{
+		defaultBranches: map[string]string{"a": "main", "b": "master", "c": "main", "d": "trunk", "empty": "main"},
+		emptyRepos:      map[string]bool{"empty": true},
+		protections:     map[string]map[string]*github.Protection{},
+	}
+	c := &Client{clientContext: &clientContext{c: fake, domain: DefaultDomain}}
+	ref := func(name string) gitprovider.RepositoryRef {
+		return &gitprovider.OrgRepositoryRef{
+			OrganizationRef: gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"},
+			RepositoryName:  name,
+		}
+	}
+	repos := []gitprovider.RepositoryRef{ref("a"), ref("b"), ref("c"), ref("d"), ref("empty"), ref("missing")}
+	template := gitprovider.BranchProtectionTemplate{
+		Protection: gitprovider.BranchProtectionInfo{
+			RequiredStatusChecks: []string{"test"},
+			EnforceAdmins:        true,
+		},
+		Concurrency: 2,
+	}
+
+	result, err := c.ApplyProtectionTemplate(context.Background(), repos, template)
+	if err != nil {
+		t.Fatalf("ApplyProtectionTemplate() error = %v", err)
+	}
+	if len(result) != len(repos) {
+		t.Fatalf("ApplyProtectionTemplate() = %d results, want %d", len(result), len(repos))
+	}
+	// Each repository gets its own result, without aborting the others
+	for _, repo := range repos[:4] {
+		if err := result[repo]; err != nil {
+			t.Errorf("ApplyProtectionTemplate() %s error = %v", repo, err)
+		}
+		branch := fake.defaultBranches[repo.GetRepository()]
+		if _, ok := fake.protections[repo.GetRepository()][branch]; !ok {
+			t.Errorf("ApplyProtectionTemplate() didn't protect %s of %s", branch, repo)
+		}
+	}
+	if err := result[repos[4]]; !errors.Is(err, gitprovider.ErrDefaultBranchNotFound) {
+		t.Errorf("ApplyProtectionTemplate() empty repository error = %v, want %v", err, gitprovider.ErrDefaultBranchNotFound)
+	}
+	if err := result[repos[5]]; !errors.Is(err, gitprovider.ErrNotFound) || errors.Is(err, gitprovider.ErrDefaultBranchNotFound) {
+		t.Errorf("ApplyProtectionTemplate() missing repository error = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+	if fake.maxInflight > template.Concurrency {
+		t.Errorf("ApplyProtectionTemplate() processed %d repositories at once, want at most %d", fake.maxInflight, template.Concurrency)
+	}
+
+	// Applying the template again is a no-op
+	protections := fake.protections["a"]["main"]
+	if result, err := c.ApplyProtectionTemplate(context.Background(), repos[:1], template); err != nil || result[repos[0]] != nil {
+		t.Fatalf("ApplyProtectionTemplate() = %v, %v", result, err)
+	}
+	if fake.protections["a"]["main"] != protections {
+		t.Error("ApplyProtectionTemplate() updated a protection that was already as desired")
+	}
+}
+
+func TestClient_ApplyProtectionTemplate_invalid(t *testing.T) {
+	c := &Client{clientContext: &clientContext{c: &fakeProtectionTemplateClient{}, domain: DefaultDomain}}
+	orgRef := gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"},
+		RepositoryName:  "bar",
+	}
+	tests := []struct {
+		name        string
+		repos       []gitprovider.RepositoryRef
+		template    gitprovider.BranchProtectionTemplate
+		expectedErr error
+	}{
+		{
+			name:        "template naming a branch",
+			repos:       []gitprovider.RepositoryRef{&orgRef},
+			template:    gitprovider.BranchProtectionTemplate{Protection: gitprovider.BranchProtectionInfo{Branch: "main"}},
+			expectedErr: validation.ErrFieldInvalid,
+		},
+		{
+			name:        "invalid protection",
+			repos:       []gitprovider.RepositoryRef{&orgRef},
+			template:    gitprovider.BranchProtectionTemplate{Protection: gitprovider.BranchProtectionInfo{RequiredReviewCount: -1}},
+			expectedErr: validation.ErrFieldInvalid,
+		},
+		{
+			name:        "uncomparable ref",
+			repos:       []gitprovider.RepositoryRef{orgRef},
+			expectedErr: gitprovider.ErrInvalidArgument,
+		},
+		{
+			name:        "duplicate ref",
+			repos:       []gitprovider.RepositoryRef{&orgRef, &orgRef},
+			expectedErr: gitprovider.ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := c.ApplyProtectionTemplate(context.Background(), tt.repos, tt.template)
+			if !errors.Is(err, tt.expectedErr) || result != nil {
+				t.Errorf("ApplyProtectionTemplate() = %v, %v, want nil, %v", result, err, tt.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// CommitClient implements the gitprovider.CommitClient interface.
+var _ gitprovider.CommitClient = &CommitClient{}
+
+// CommitClient operates on the commits of a specific repository.
+type CommitClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// ListPage lists the commits of the given branch, newest first, in pages of perPage commits.
+// GitHub returns the verification status along with the commits, hence it's always populated,
+// regardless of opts.
+//
+// ErrNotFound is returned if the branch does not exist.
+func (c *CommitClient) ListPage(ctx context.Context, branch string, perPage, page int, _ gitprovider.CommitListOptions) ([]gitprovider.Commit, error) {
+	// GET /repos/{owner}/{repo}/commits
+	apiObjs, _, err := c.c.ListCommitsPage(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch, perPage, page)
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]gitprovider.Commit, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListCommitsPage
+		commits = append(commits, newCommit(c.clientContext, commitFromRepositoryCommit(apiObj), c.ref))
+	}
+	return commits, nil
+}
+
+// Create creates a commit on top of the given branch, changing the given files, and moves the
+// branch to it, using the Git Data API. Files with nil content are deleted.
+//
+// ErrNotFound is returned if the branch does not exist.
+func (c *CommitClient) Create(ctx context.Context, branch string, message string, files []gitprovider.CommitFile) (gitprovider.Commit, error) {
+	if err := gitprovider.ValidateCommitRequest(branch, message, files); err != nil {
+		return nil, err
+	}
+	owner, repo := c.ref.GetIdentity(), c.ref.GetRepository()
+
+	// GET /repos/{owner}/{repo}/git/ref/{ref}
+	ref, err := c.c.GetRef(ctx, owner, repo, "heads/"+branch)
+	if err != nil {
+		return nil, err
+	}
+	// GET /repos/{owner}/{repo}/git/commits/{commit_sha}
+	parent, err := c.c.GetCommit(ctx, owner, repo, *ref.Object.SHA)
+	if err != nil {
+		return nil, err
+	}
+
+	blobSHAs := make(map[string]string, len(files))
+	for _, file := range files {
+		if file.Content == nil {
+			continue
+		}
+		// POST /repos/{owner}/{repo}/git/blobs
+		blob, err := c.c.CreateBlob(ctx, owner, repo, &github.Blob{
+			Content:  file.Content,
+			Encoding: github.String(blobEncodingUTF8),
+		})
+		if err != nil {
+			return nil, err
+		}
+		blobSHAs[file.Path] = *blob.SHA
+	}
+	// POST /repos/{owner}/{repo}/git/trees
+	tree, err := c.c.CreateTree(ctx, owner, repo, parent.GetTree().GetSHA(), commitTreeEntries(files, blobSHAs))
+	if err != nil {
+		return nil, err
+	}
+	// POST /repos/{owner}/{repo}/git/commits
+	apiObj, err := c.c.CreateCommit(ctx, owner, repo, &github.Commit{
+		Message: github.String(message),
+		Tree:    &github.Tree{SHA: tree.SHA},
+		Parents: []*github.Commit{{SHA: parent.SHA}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Move the branch to the new commit. This isn't forced, hence fails if the branch moved meanwhile.
+	ref.Object.SHA = apiObj.SHA
+	// PATCH /repos/{owner}/{repo}/git/refs/{ref}
+	if _, err := c.c.UpdateRef(ctx, owner, repo, ref); err != nil {
+		return nil, err
+	}
+	return newCommit(c.clientContext, apiObj, c.ref), nil
+}
+
+// CommitCount returns the amount of commits reachable from the given branch, or the default
+// branch if branch is empty. GitHub doesn't supply the count, hence it's approximated by listing
+// one commit per page, and reading the index of the last page from the Link header.
+//
+// UnknownCount (-1) is returned if GitHub doesn't link to the last page.
+func (c *CommitClient) CommitCount(ctx context.Context, branch string) (int64, error) {
+	// GET /repos/{owner}/{repo}/commits?per_page=1
+	apiObjs, pageInfo, err := c.c.ListCommitsPage(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch, 1, 1)
+	if err != nil {
+		return 0, err
+	}
+	if pageInfo.IsLastPage() {
+		return int64(len(apiObjs)), nil
+	}
+	return int64(pageInfo.TotalPages), nil
+}
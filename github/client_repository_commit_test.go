@@ -0,0 +1,238 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// fakeGitDataClient is a githubClient that records the Git Data API calls of CommitClient.Create,
+// for a branch pointing at the "parent" commit. Calling any other method than the overridden ones panics.
+type fakeGitDataClient struct {
+	githubClient
+
+	blobs   []string
+	entries []*github.TreeEntry
+	commit  *github.Commit
+	ref     *github.Reference
+}
+
+func (c *fakeGitDataClient) GetRef(_ context.Context, _, _, ref string) (*github.Reference, error) {
+	if ref != "heads/main" {
+		return nil, gitprovider.ErrNotFound
+	}
+	return &github.Reference{Ref: github.String("refs/" + ref), Object: &github.GitObject{SHA: github.String("parent")}}, nil
+}
+
+func (c *fakeGitDataClient) GetCommit(_ context.Context, _, _, sha string) (*github.Commit, error) {
+	return &github.Commit{SHA: github.String(sha), Tree: &github.Tree{SHA: github.String("base-tree")}}, nil
+}
+
+func (c *fakeGitDataClient) CreateBlob(_ context.Context, _, _ string, req *github.Blob) (*github.Blob, error) {
+	c.blobs = append(c.blobs, req.GetContent())
+	return &github.Blob{SHA: github.String("blob-" + req.GetContent())}, nil
+}
+
+func (c *fakeGitDataClient) CreateTree(_ context.Context, _, _, baseTree string, entries []*github.TreeEntry) (*github.Tree, error) {
+	if baseTree != "base-tree" {
+		return nil, errors.New("unexpected base tree")
+	}
+	c.entries = entries
+	return &github.Tree{SHA: github.String("tree")}, nil
+}
+
+func (c *fakeGitDataClient) CreateCommit(_ context.Context, _, _ string, req *github.Commit) (*github.Commit, error) {
+	c.commit = req
+	apiObj := *req
+	apiObj.SHA = github.String("commit")
+	return &apiObj, nil
+}
+
+func (c *fakeGitDataClient) UpdateRef(_ context.Context, _, _ string, req *github.Reference) (*github.Reference, error) {
+	c.ref = req
+	return req, nil
+}
+
+func TestCommitClient_Create(t *testing.T) {
+	fake := &fakeGitDataClient{}
+	c := &CommitClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain},
+		ref: gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+			RepositoryName: "bar",
+		},
+	}
+	files := []gitprovider.CommitFile{
+		{Path: "clusters/prod/kustomization.yaml", Content: gitprovider.StringVar("kind: Kustomization")},
+		{Path: "README.md"},
+	}
+
+	commit, err := c.Create(context.Background(), "main", "Bootstrap", files)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if got := commit.Get().Sha; got != "commit" {
+		t.Errorf("Create() sha = %q, want %q", got, "commit")
+	}
+	// Only the changed file needs a blob
+	if len(fake.blobs) != 1 || fake.blobs[0] != "kind: Kustomization" {
+		t.Errorf("Create() blobs = %v", fake.blobs)
+	}
+	// The deleted file has neither SHA nor content, which go-github sends as a null SHA
+	if len(fake.entries) != 2 || fake.entries[0].GetSHA() != "blob-kind: Kustomization" ||
+		fake.entries[1].SHA != nil || fake.entries[1].Content != nil {
+		t.Errorf("Create() tree entries = %v", fake.entries)
+	}
+	if fake.commit.GetMessage() != "Bootstrap" || fake.commit.GetTree().GetSHA() != "tree" ||
+		len(fake.commit.Parents) != 1 || fake.commit.Parents[0].GetSHA() != "parent" {
+		t.Errorf("Create() commit = %v", fake.commit)
+	}
+	if fake.ref.GetRef() != "refs/heads/main" || fake.ref.GetObject().GetSHA() != "commit" {
+		t.Errorf("Create() ref = %v", fake.ref)
+	}
+
+	// A missing branch is reported as such
+	if _, err := c.Create(context.Background(), "other", "Bootstrap", files); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Create() error = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+	// Invalid requests are rejected before any request is made
+	if _, err := c.Create(context.Background(), "main", "", files); !errors.Is(err, validation.ErrFieldRequired) {
+		t.Errorf("Create() error = %v, want %v", err, validation.ErrFieldRequired)
+	}
+}
+
+// fakeCommitListClient is a githubClient that lists a single page of commits with the given
+// pagination metadata. Calling any other method than the overridden ones panics.
+type fakeCommitListClient struct {
+	githubClient
+
+	commits  []*github.RepositoryCommit
+	pageInfo gitprovider.PageInfo
+}
+
+func (c *fakeCommitListClient) ListCommitsPage(_ context.Context, _, _, _ string, _, _ int) ([]*github.RepositoryCommit, gitprovider.PageInfo, error) {
+	return c.commits, c.pageInfo, nil
+}
+
+func TestCommitClient_CommitCount(t *testing.T) {
+	commit := &github.RepositoryCommit{SHA: github.String("head")}
+	tests := []struct {
+		name     string
+		commits  []*github.RepositoryCommit
+		pageInfo gitprovider.PageInfo
+		want     int64
+	}{
+		{
+			name:     "count from the last page",
+			commits:  []*github.RepositoryCommit{commit},
+			pageInfo: gitprovider.PageInfo{NextPage: 2, TotalCount: gitprovider.UnknownCount, TotalPages: 1337},
+			want:     1337,
+		},
+		{
+			name:     "single commit",
+			commits:  []*github.RepositoryCommit{commit},
+			pageInfo: gitprovider.PageInfo{TotalCount: gitprovider.UnknownCount, TotalPages: 1},
+			want:     1,
+		},
+		{
+			name:     "no link to the last page",
+			commits:  []*github.RepositoryCommit{commit},
+			pageInfo: gitprovider.PageInfo{NextPage: 2, TotalCount: gitprovider.UnknownCount, TotalPages: gitprovider.UnknownCount},
+			want:     gitprovider.UnknownCount,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &CommitClient{
+				clientContext: &clientContext{c: &fakeCommitListClient{commits: tt.commits, pageInfo: tt.pageInfo}, domain: DefaultDomain},
+				ref: gitprovider.UserRepositoryRef{
+					UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+					RepositoryName: "bar",
+				},
+			}
+			got, err := c.CommitCount(context.Background(), "")
+			if err != nil {
+				t.Fatalf("CommitCount() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CommitCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeCommitsClient is a githubClient listing a fixed page of commits. Calling any other method
+// than the overridden ones panics.
+type fakeCommitsClient struct {
+	githubClient
+
+	commits []*github.RepositoryCommit
+}
+
+func (c *fakeCommitsClient) ListCommitsPage(_ context.Context, _, _, _ string, _, _ int) ([]*github.RepositoryCommit, gitprovider.PageInfo, error) {
+	return c.commits, gitprovider.PageInfo{}, nil
+}
+
+func TestCommitClient_ListPage_verification(t *testing.T) {
+	fake := &fakeCommitsClient{commits: []*github.RepositoryCommit{
+		{SHA: github.String("signed"), Commit: &github.Commit{
+			Verification: &github.SignatureVerification{Verified: github.Bool(true), Reason: github.String("valid")},
+		}},
+		{SHA: github.String("unsigned"), Commit: &github.Commit{
+			Verification: &github.SignatureVerification{Verified: github.Bool(false), Reason: github.String("unsigned")},
+		}},
+		{SHA: github.String("unknown"), Commit: &github.Commit{}},
+	}}
+	c := &CommitClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain},
+		ref: gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+			RepositoryName: "bar",
+		},
+	}
+
+	// GitHub returns the verification with the commits, hence it's populated without opting in
+	commits, err := c.ListPage(context.Background(), "main", 10, 1, gitprovider.CommitListOptions{})
+	if err != nil {
+		t.Fatalf("ListPage() error = %v", err)
+	}
+	want := []struct {
+		verified *bool
+		reason   string
+	}{
+		{gitprovider.BoolVar(true), "valid"},
+		{gitprovider.BoolVar(false), "unsigned"},
+		{nil, ""},
+	}
+	if len(commits) != len(want) {
+		t.Fatalf("ListPage() returned %d commits, want %d", len(commits), len(want))
+	}
+	for i, commit := range commits {
+		info := commit.Get()
+		if !reflect.DeepEqual(info.Verified, want[i].verified) || info.VerificationReason != want[i].reason {
+			t.Errorf("commit %s verification = %v, %q, want %v, %q", info.Sha, info.Verified, info.VerificationReason, want[i].verified, want[i].reason)
+		}
+	}
+}
@@ -0,0 +1,521 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// fakeDeployKeyClient is a githubClient that keeps the deploy keys of a single repository in memory.
+// Calling any other method than the overridden ones panics.
+type fakeDeployKeyClient struct {
+	githubClient
+
+	keys   []*github.Key
+	nextID int64
+}
+
+func (c *fakeDeployKeyClient) ListKeys(_ context.Context, _, _ string) ([]*github.Key, error) {
+	return c.keys, nil
+}
+
+func (c *fakeDeployKeyClient) GetKeyByTitle(_ context.Context, _, _, title string) (*github.Key, error) {
+	for _, key := range c.keys {
+		if *key.Title == title {
+			return key, nil
+		}
+	}
+	return nil, gitprovider.ErrNotFound
+}
+
+func (c *fakeDeployKeyClient) CreateKey(_ context.Context, _, _ string, req *github.Key) (*github.Key, error) {
+	c.nextID++
+	apiObj := *req
+	apiObj.ID = github.Int64(c.nextID)
+	c.keys = append(c.keys, &apiObj)
+	return &apiObj, nil
+}
+
+func (c *fakeDeployKeyClient) DeleteKey(_ context.Context, _, _ string, id int64) error {
+	for i, key := range c.keys {
+		if *key.ID == id {
+			c.keys = append(c.keys[:i], c.keys[i+1:]...)
+			return nil
+		}
+	}
+	return gitprovider.ErrNotFound
+}
+
+func TestDeployKeyClient_ReconcileList(t *testing.T) {
+	existing := []*github.Key{
+		{ID: github.Int64(1), Title: github.String("flux"), Key: github.String("ssh-ed25519 AAAAflux"), ReadOnly: github.Bool(true)},
+		{ID: github.Int64(2), Title: github.String("old"), Key: github.String("ssh-ed25519 AAAAold"), ReadOnly: github.Bool(true)},
+	}
+	tests := []struct {
+		name               string
+		desired            []gitprovider.DeployKeyInfo
+		destructiveActions bool
+		wantAdded          []string
+		wantRemoved        []string
+		wantKeys           []string
+		expectedErr        error
+	}{
+		{
+			name: "no-op when desired equals actual",
+			desired: []gitprovider.DeployKeyInfo{
+				// The comment isn't stored by the server, and mustn't cause drift
+				{Name: "flux", Key: []byte("ssh-ed25519 AAAAflux flux@cluster\n")},
+				{Name: "old", Key: []byte("ssh-ed25519 AAAAold")},
+			},
+			wantAdded:   []string{},
+			wantRemoved: []string{},
+			wantKeys:    []string{"flux", "old"},
+		},
+		{
+			name: "add missing and remove undesired keys",
+			desired: []gitprovider.DeployKeyInfo{
+				{Name: "flux", Key: []byte("ssh-ed25519 AAAAflux")},
+				{Name: "new", Key: []byte("ssh-ed25519 AAAAnew")},
+			},
+			destructiveActions: true,
+			wantAdded:          []string{"new"},
+			wantRemoved:        []string{"old"},
+			wantKeys:           []string{"flux", "new"},
+		},
+		{
+			name: "removing keys requires destructive actions",
+			desired: []gitprovider.DeployKeyInfo{
+				{Name: "new", Key: []byte("ssh-ed25519 AAAAnew")},
+			},
+			wantKeys:    []string{"flux", "old"},
+			expectedErr: gitprovider.ErrDestructiveCallDisallowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDeployKeyClient{keys: append([]*github.Key{}, existing...), nextID: 2}
+			c := &DeployKeyClient{
+				clientContext: &clientContext{c: fake, domain: DefaultDomain, destructiveActions: tt.destructiveActions},
+				ref: gitprovider.UserRepositoryRef{
+					UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+					RepositoryName: "bar",
+				},
+			}
+			added, removed, err := c.ReconcileList(context.Background(), tt.desired)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ReconcileList() error = %v, want %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr == nil && (!reflect.DeepEqual(added, tt.wantAdded) || !reflect.DeepEqual(removed, tt.wantRemoved)) {
+				t.Errorf("ReconcileList() = %v, %v, want %v, %v", added, removed, tt.wantAdded, tt.wantRemoved)
+			}
+			keys := []string{}
+			for _, key := range fake.keys {
+				keys = append(keys, *key.Title)
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("server keys = %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestDeployKeyClient_ReconcileAll(t *testing.T) {
+	existing := []*github.Key{
+		{ID: github.Int64(1), Title: github.String("flux"), Key: github.String("ssh-ed25519 AAAAflux"), ReadOnly: github.Bool(true)},
+		{ID: github.Int64(2), Title: github.String("old"), Key: github.String("ssh-ed25519 AAAAold"), ReadOnly: github.Bool(true)},
+	}
+	tests := []struct {
+		name               string
+		desired            []gitprovider.DeployKeyInfo
+		destructiveActions bool
+		wantChanged        bool
+		wantKeys           map[string]string
+		expectedErr        error
+	}{
+		{
+			name: "no-op when desired equals actual",
+			desired: []gitprovider.DeployKeyInfo{
+				{Name: "flux", Key: []byte("ssh-ed25519 AAAAflux flux@cluster\n")},
+				{Name: "old", Key: []byte("ssh-ed25519 AAAAold")},
+			},
+			wantKeys: map[string]string{"flux": "ssh-ed25519 AAAAflux", "old": "ssh-ed25519 AAAAold"},
+		},
+		{
+			name: "add only",
+			desired: []gitprovider.DeployKeyInfo{
+				{Name: "flux", Key: []byte("ssh-ed25519 AAAAflux")},
+				{Name: "old", Key: []byte("ssh-ed25519 AAAAold")},
+				{Name: "new", Key: []byte("ssh-ed25519 AAAAnew")},
+			},
+			wantChanged: true,
+			wantKeys:    map[string]string{"flux": "ssh-ed25519 AAAAflux", "old": "ssh-ed25519 AAAAold", "new": "ssh-ed25519 AAAAnew"},
+		},
+		{
+			name: "delete extra",
+			desired: []gitprovider.DeployKeyInfo{
+				{Name: "flux", Key: []byte("ssh-ed25519 AAAAflux")},
+			},
+			destructiveActions: true,
+			wantChanged:        true,
+			wantKeys:           map[string]string{"flux": "ssh-ed25519 AAAAflux"},
+		},
+		{
+			name: "rotate changed key",
+			desired: []gitprovider.DeployKeyInfo{
+				{Name: "flux", Key: []byte("ssh-ed25519 AAAArotated")},
+				{Name: "old", Key: []byte("ssh-ed25519 AAAAold")},
+			},
+			destructiveActions: true,
+			wantChanged:        true,
+			wantKeys:           map[string]string{"flux": "ssh-ed25519 AAAArotated", "old": "ssh-ed25519 AAAAold"},
+		},
+		{
+			name: "delete and rotate disallowed before any change",
+			desired: []gitprovider.DeployKeyInfo{
+				{Name: "flux", Key: []byte("ssh-ed25519 AAAArotated")},
+				{Name: "new", Key: []byte("ssh-ed25519 AAAAnew")},
+			},
+			wantKeys:    map[string]string{"flux": "ssh-ed25519 AAAAflux", "old": "ssh-ed25519 AAAAold"},
+			expectedErr: gitprovider.ErrDestructiveCallDisallowed,
+		},
+		{
+			name: "duplicate names",
+			desired: []gitprovider.DeployKeyInfo{
+				{Name: "flux", Key: []byte("ssh-ed25519 AAAAflux")},
+				{Name: "flux", Key: []byte("ssh-ed25519 AAAAother")},
+			},
+			destructiveActions: true,
+			wantKeys:           map[string]string{"flux": "ssh-ed25519 AAAAflux", "old": "ssh-ed25519 AAAAold"},
+			expectedErr:        validation.ErrFieldInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := make([]*github.Key, 0, len(existing))
+			for _, key := range existing {
+				k := *key
+				keys = append(keys, &k)
+			}
+			fake := &fakeDeployKeyClient{keys: keys, nextID: 2}
+			c := &DeployKeyClient{
+				clientContext: &clientContext{c: fake, domain: DefaultDomain, destructiveActions: tt.destructiveActions},
+				ref: gitprovider.UserRepositoryRef{
+					UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+					RepositoryName: "bar",
+				},
+			}
+			changed, err := c.ReconcileAll(context.Background(), tt.desired)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ReconcileAll() error = %v, want %v", err, tt.expectedErr)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("ReconcileAll() = %v, want %v", changed, tt.wantChanged)
+			}
+			gotKeys := map[string]string{}
+			for _, key := range fake.keys {
+				gotKeys[*key.Title] = *key.Key
+			}
+			if !reflect.DeepEqual(gotKeys, tt.wantKeys) {
+				t.Errorf("server keys = %v, want %v", gotKeys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestDeployKeyClient_Reconcile(t *testing.T) {
+	tests := []struct {
+		name            string
+		req             gitprovider.DeployKeyInfo
+		wantActionTaken bool
+		wantKey         string
+		wantReadOnly    bool
+		wantID          int64
+	}{
+		{
+			name: "create when missing",
+			req:  gitprovider.DeployKeyInfo{Name: "ci", Key: []byte("ssh-ed25519 AAAAci")},
+			// The existing key is kept, and the new one gets the next ID
+			wantActionTaken: true,
+			wantKey:         "ssh-ed25519 AAAAci",
+			wantReadOnly:    true,
+			wantID:          2,
+		},
+		{
+			name: "no-op when the name, key and read-only flag match",
+			// The comment isn't stored by the server, and mustn't cause a rotation
+			req:          gitprovider.DeployKeyInfo{Name: "flux", Key: []byte("ssh-ed25519 AAAAflux flux@cluster\n")},
+			wantKey:      "ssh-ed25519 AAAAflux",
+			wantReadOnly: true,
+			wantID:       1,
+		},
+		{
+			name:            "rotate when the key differs",
+			req:             gitprovider.DeployKeyInfo{Name: "flux", Key: []byte("ssh-ed25519 AAAAnew")},
+			wantActionTaken: true,
+			wantKey:         "ssh-ed25519 AAAAnew",
+			wantReadOnly:    true,
+			wantID:          2,
+		},
+		{
+			name:            "recreate when only the read-only flag differs",
+			req:             gitprovider.DeployKeyInfo{Name: "flux", Key: []byte("ssh-ed25519 AAAAflux"), ReadOnly: gitprovider.BoolVar(false)},
+			wantActionTaken: true,
+			wantKey:         "ssh-ed25519 AAAAflux",
+			wantReadOnly:    false,
+			wantID:          2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDeployKeyClient{
+				keys: []*github.Key{
+					{ID: github.Int64(1), Title: github.String("flux"), Key: github.String("ssh-ed25519 AAAAflux"), ReadOnly: github.Bool(true)},
+				},
+				nextID: 1,
+			}
+			c := &DeployKeyClient{
+				clientContext: &clientContext{c: fake, domain: DefaultDomain},
+				ref: gitprovider.UserRepositoryRef{
+					UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+					RepositoryName: "bar",
+				},
+			}
+
+			_, actionTaken, err := c.Reconcile(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if actionTaken != tt.wantActionTaken {
+				t.Errorf("Reconcile() actionTaken = %v, want %v", actionTaken, tt.wantActionTaken)
+			}
+			var got *github.Key
+			for _, key := range fake.keys {
+				if *key.Title == tt.req.Name {
+					if got != nil {
+						t.Fatalf("server has multiple keys named %q", tt.req.Name)
+					}
+					got = key
+				}
+			}
+			if got == nil {
+				t.Fatalf("server has no key named %q", tt.req.Name)
+			}
+			if *got.Key != tt.wantKey || *got.ReadOnly != tt.wantReadOnly || *got.ID != tt.wantID {
+				t.Errorf("server key = {ID: %d, Key: %q, ReadOnly: %v}, want {ID: %d, Key: %q, ReadOnly: %v}",
+					*got.ID, *got.Key, *got.ReadOnly, tt.wantID, tt.wantKey, tt.wantReadOnly)
+			}
+		})
+	}
+}
+
+func TestDeployKeyClient_RotateAll(t *testing.T) {
+	fake := &fakeDeployKeyClient{
+		keys: []*github.Key{
+			{ID: github.Int64(1), Title: github.String("flux"), Key: github.String("ssh-ed25519 AAAAflux"), ReadOnly: github.Bool(false)},
+			{ID: github.Int64(2), Title: github.String("ci"), Key: github.String("ssh-ed25519 AAAAci"), ReadOnly: github.Bool(true)},
+		},
+		nextID: 2,
+	}
+	c := &DeployKeyClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain, destructiveActions: true},
+		ref: gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+			RepositoryName: "bar",
+		},
+	}
+	// Fail generating the second key pair
+	generated := 0
+	errGenerate := errors.New("generate failed")
+	generate := func() ([]byte, []byte, error) {
+		generated++
+		if generated == 2 {
+			return nil, nil, errGenerate
+		}
+		return []byte("ssh-ed25519 AAAAnew"), []byte("private"), nil
+	}
+
+	privateKeys, err := c.RotateAll(context.Background(), generate)
+	if !errors.Is(err, errGenerate) {
+		t.Fatalf("RotateAll() error = %v, want %v", err, errGenerate)
+	}
+	if !reflect.DeepEqual(privateKeys, map[string][]byte{"flux": []byte("private")}) {
+		t.Errorf("RotateAll() = %v, want only the flux key", privateKeys)
+	}
+	// The failed key must be kept, and the rotated one must keep its access level
+	got := map[string]string{}
+	for _, key := range fake.keys {
+		got[*key.Title] = *key.Key
+		if *key.Title == "flux" && *key.ReadOnly {
+			t.Error("rotated key is read-only, want read-write as the old key")
+		}
+	}
+	want := map[string]string{"flux": "ssh-ed25519 AAAAnew", "ci": "ssh-ed25519 AAAAci"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("server keys = %v, want %v", got, want)
+	}
+}
+
+func TestDeployKeyClient_ListReadWriteKeys(t *testing.T) {
+	fake := &fakeDeployKeyClient{
+		keys: []*github.Key{
+			{ID: github.Int64(1), Title: github.String("flux"), Key: github.String("ssh-ed25519 AAAAflux"), ReadOnly: github.Bool(false)},
+			{ID: github.Int64(2), Title: github.String("ci"), Key: github.String("ssh-ed25519 AAAAci"), ReadOnly: github.Bool(true)},
+			{ID: github.Int64(3), Title: github.String("bot"), Key: github.String("ssh-ed25519 AAAAbot"), ReadOnly: github.Bool(false)},
+		},
+	}
+	c := &DeployKeyClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain},
+		ref: gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+			RepositoryName: "bar",
+		},
+	}
+
+	keys, err := c.ListReadWriteKeys(context.Background())
+	if err != nil {
+		t.Fatalf("ListReadWriteKeys() error = %v", err)
+	}
+	got := make([]string, 0, len(keys))
+	for _, key := range keys {
+		got = append(got, key.Get().Name)
+	}
+	if want := []string{"flux", "bot"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListReadWriteKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestDeployKeyClient_Get(t *testing.T) {
+	fake := &fakeDeployKeyClient{
+		keys: []*github.Key{
+			{ID: github.Int64(1), Title: github.String("flux"), Key: github.String("ssh-ed25519 AAAAflux"), ReadOnly: github.Bool(false)},
+			{ID: github.Int64(2), Title: github.String("ci"), Key: github.String("ssh-ed25519 AAAAci"), ReadOnly: github.Bool(true)},
+		},
+	}
+	c := &DeployKeyClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain},
+		ref: gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+			RepositoryName: "bar",
+		},
+	}
+
+	key, err := c.Get(context.Background(), "ci")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	want := gitprovider.DeployKeyInfo{Name: "ci", Key: []byte("ssh-ed25519 AAAAci"), ReadOnly: gitprovider.BoolVar(true)}
+	if got := key.Get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+
+	if _, err := c.Get(context.Background(), "unknown"); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+}
+
+func TestDeployKeyClient_Create_readOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		readOnly *bool
+		want     bool
+	}{
+		{name: "read-write", readOnly: gitprovider.BoolVar(false), want: false},
+		{name: "read-only", readOnly: gitprovider.BoolVar(true), want: true},
+		{name: "unset defaults to read-only", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &DeployKeyClient{
+				clientContext: &clientContext{c: &fakeDeployKeyClient{}, domain: DefaultDomain},
+				ref: gitprovider.UserRepositoryRef{
+					UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+					RepositoryName: "bar",
+				},
+			}
+			ctx := context.Background()
+			if _, err := c.Create(ctx, gitprovider.DeployKeyInfo{
+				Name:     "flux",
+				Key:      []byte("ssh-ed25519 AAAAflux"),
+				ReadOnly: tt.readOnly,
+			}); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			key, err := c.Get(ctx, "flux")
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got := key.Get().ReadOnly; got == nil || *got != tt.want {
+				t.Errorf("Get().ReadOnly = %v, want %v", got, tt.want)
+			}
+			keys, err := c.List(ctx)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(keys) != 1 {
+				t.Fatalf("List() returned %d keys, want 1", len(keys))
+			}
+			if got := keys[0].Get().ReadOnly; got == nil || *got != tt.want {
+				t.Errorf("List()[0].ReadOnly = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_githubClientImpl_GetKeyByTitle(t *testing.T) {
+	pages := []string{
+		`[{"id":1,"title":"flux","key":"ssh-ed25519 AAAAflux","read_only":true}]`,
+		`[{"id":2,"title":"ci","key":"ssh-ed25519 AAAAci","read_only":false}]`,
+		`[{"id":3,"title":"bot","key":"ssh-ed25519 AAAAbot","read_only":true}]`,
+	}
+	tests := []struct {
+		name         string
+		title        string
+		wantID       int64
+		wantErr      error
+		wantRequests int
+	}{
+		{name: "found on the second page", title: "ci", wantID: 2, wantRequests: 2},
+		{name: "not found", title: "unknown", wantErr: gitprovider.ErrNotFound, wantRequests: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &pagesRoundTripper{pages: pages}
+			c := &githubClientImpl{c: github.NewClient(&http.Client{Transport: rt})}
+
+			key, err := c.GetKeyByTitle(context.Background(), "foo", "bar", tt.title)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetKeyByTitle() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && key.GetID() != tt.wantID {
+				t.Errorf("GetKeyByTitle() ID = %d, want %d", key.GetID(), tt.wantID)
+			}
+			if len(rt.requests) != tt.wantRequests {
+				t.Errorf("requests = %v, want %d requests", rt.requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// RepositoryHookClient implements the gitprovider.RepositoryHookClient interface.
+var _ gitprovider.RepositoryHookClient = &RepositoryHookClient{}
+
+// RepositoryHookClient operates on the webhooks of a specific repository.
+type RepositoryHookClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the webhook delivering to the given URL.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *RepositoryHookClient) Get(ctx context.Context, url string) (gitprovider.RepositoryHook, error) {
+	return c.get(ctx, url)
+}
+
+func (c *RepositoryHookClient) get(ctx context.Context, url string) (*repositoryHook, error) {
+	hooks, err := c.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Loop through the webhooks until we find one with the right URL
+	for _, hook := range hooks {
+		if hook.Get().URL == url {
+			return hook, nil
+		}
+	}
+	return nil, gitprovider.ErrNotFound
+}
+
+// List lists all webhooks of the repository.
+//
+// List returns all available webhooks, using multiple paginated requests if needed.
+func (c *RepositoryHookClient) List(ctx context.Context) ([]gitprovider.RepositoryHook, error) {
+	rhs, err := c.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Cast to the generic []gitprovider.RepositoryHook
+	hooks := make([]gitprovider.RepositoryHook, 0, len(rhs))
+	for _, rh := range rhs {
+		hooks = append(hooks, rh)
+	}
+	return hooks, nil
+}
+
+func (c *RepositoryHookClient) list(ctx context.Context) ([]*repositoryHook, error) {
+	// GET /repos/{owner}/{repo}/hooks
+	apiObjs, err := c.c.ListHooks(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+
+	// Map the api object to our RepositoryHook type
+	hooks := make([]*repositoryHook, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListHooks
+		hooks = append(hooks, newRepositoryHook(c, apiObj))
+	}
+
+	return hooks, nil
+}
+
+// Create creates a webhook with the given specifications. If opts.VerifyURL is set, the webhook is
+// only created if its URL is reachable, see gitprovider.VerifyHookURL.
+//
+// ErrAlreadyExists will be returned if the resource already exists.
+func (c *RepositoryHookClient) Create(ctx context.Context, req gitprovider.RepositoryHookInfo, opts gitprovider.RepositoryHookCreateOptions) (gitprovider.RepositoryHook, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, err
+	}
+	// GitHub doesn't allow several webhooks with the same URL, but its error isn't recognizable
+	if _, err := c.get(ctx, req.URL); err == nil {
+		return nil, gitprovider.ErrAlreadyExists
+	} else if !errors.Is(err, gitprovider.ErrNotFound) {
+		return nil, err
+	}
+
+	// GitHub accepts any URL, so check that the payloads can be delivered if asked to
+	if opts.VerifyURL {
+		if err := gitprovider.VerifyHookURL(ctx, req.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	// POST /repos/{owner}/{repo}/hooks
+	apiObj, err := c.c.CreateHook(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), repositoryHookToAPI(&req))
+	if err != nil {
+		return nil, err
+	}
+	return newRepositoryHook(c, apiObj), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+// The secret isn't compared, as it can't be read; it is only set when the webhook is created or updated.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *RepositoryHookClient) Reconcile(ctx context.Context, req gitprovider.RepositoryHookInfo) (gitprovider.RepositoryHook, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	// Get the webhook with the desired URL
+	actual, err := c.get(ctx, req.URL)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			resp, err := c.Create(ctx, req, gitprovider.RepositoryHookCreateOptions{})
+			return resp, true, err
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Populate the desired state to the current-actual object
+	if err := actual.Set(req); err != nil {
+		return actual, false, err
+	}
+	// Apply the desired state by running Update
+	return actual, true, actual.Update(ctx)
+}
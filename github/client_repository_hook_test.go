@@ -0,0 +1,213 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// fakeHookClient is a githubClient that keeps the webhooks of a single repository in memory,
+// masking the secrets like GitHub does. Calling any other method than the overridden ones panics.
+type fakeHookClient struct {
+	githubClient
+
+	hooks   []*github.Hook
+	secrets map[int64]string
+	nextID  int64
+}
+
+func (c *fakeHookClient) ListHooks(_ context.Context, _, _ string) ([]*github.Hook, error) {
+	return c.hooks, nil
+}
+
+func (c *fakeHookClient) CreateHook(_ context.Context, _, _ string, req *github.Hook) (*github.Hook, error) {
+	c.nextID++
+	apiObj := &github.Hook{ID: github.Int64(c.nextID)}
+	c.hooks = append(c.hooks, apiObj)
+	return c.EditHook(context.Background(), "", "", c.nextID, req)
+}
+
+func (c *fakeHookClient) EditHook(_ context.Context, _, _ string, id int64, req *github.Hook) (*github.Hook, error) {
+	for _, apiObj := range c.hooks {
+		if *apiObj.ID != id {
+			continue
+		}
+		config := map[string]interface{}{}
+		for k, v := range req.Config {
+			config[k] = v
+		}
+		delete(c.secrets, id)
+		if secret, ok := config[hookConfigSecret].(string); ok {
+			c.secrets[id] = secret
+			config[hookConfigSecret] = "********"
+		}
+		apiObj.Config, apiObj.Events, apiObj.Active = config, req.Events, req.Active
+		return apiObj, nil
+	}
+	return nil, gitprovider.ErrNotFound
+}
+
+func TestRepositoryHookClient_Reconcile(t *testing.T) {
+	existing := func() []*github.Hook {
+		return []*github.Hook{{
+			ID: github.Int64(1),
+			Config: map[string]interface{}{
+				hookConfigURL:         "https://ci.example.com/hook",
+				hookConfigContentType: "json",
+				hookConfigSecret:      "********",
+			},
+			Events: []string{"pull_request", "deployment", "push"},
+			Active: github.Bool(true),
+		}}
+	}
+	tests := []struct {
+		name            string
+		req             gitprovider.RepositoryHookInfo
+		wantActionTaken bool
+		wantEvents      []string
+		wantSecret      string
+	}{
+		{
+			name: "no-op when only the secret differs",
+			req: gitprovider.RepositoryHookInfo{
+				URL:    "https://ci.example.com/hook",
+				Secret: gitprovider.StringVar("rotated"),
+				Events: []gitprovider.RepositoryHookEvent{gitprovider.RepositoryHookEventPush, gitprovider.RepositoryHookEventPullRequest},
+			},
+			wantEvents: []string{"pull_request", "deployment", "push"},
+			wantSecret: "old",
+		},
+		{
+			name: "update events, keeping events without a provider-neutral equivalent",
+			req: gitprovider.RepositoryHookInfo{
+				URL:    "https://ci.example.com/hook",
+				Secret: gitprovider.StringVar("s3cr3t"),
+				Events: []gitprovider.RepositoryHookEvent{gitprovider.RepositoryHookEventRelease},
+			},
+			wantActionTaken: true,
+			wantEvents:      []string{"release", "deployment"},
+			wantSecret:      "s3cr3t",
+		},
+		{
+			name: "create with defaults",
+			req: gitprovider.RepositoryHookInfo{
+				URL:    "https://flux.example.com/hook",
+				Secret: gitprovider.StringVar("s3cr3t"),
+			},
+			wantActionTaken: true,
+			wantEvents:      []string{"push"},
+			wantSecret:      "s3cr3t",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeHookClient{hooks: existing(), secrets: map[int64]string{1: "old"}, nextID: 1}
+			c := &RepositoryHookClient{
+				clientContext: &clientContext{c: fake, domain: DefaultDomain},
+				ref: gitprovider.UserRepositoryRef{
+					UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+					RepositoryName: "bar",
+				},
+			}
+			hook, actionTaken, err := c.Reconcile(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if actionTaken != tt.wantActionTaken {
+				t.Errorf("Reconcile() actionTaken = %v, want %v", actionTaken, tt.wantActionTaken)
+			}
+			apiObj := hook.APIObject().(*github.Hook)
+			if !reflect.DeepEqual(apiObj.Events, tt.wantEvents) {
+				t.Errorf("server events = %v, want %v", apiObj.Events, tt.wantEvents)
+			}
+			if got := fake.secrets[*apiObj.ID]; got != tt.wantSecret {
+				t.Errorf("server secret = %q, want %q", got, tt.wantSecret)
+			}
+			// The secret must never be populated when read
+			if info := hook.Get(); info.Secret != nil {
+				t.Errorf("Get().Secret = %q, want nil", *info.Secret)
+			}
+		})
+	}
+}
+
+func TestRepositoryHookClient_Create_verifyURL(t *testing.T) {
+	reachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer reachable.Close()
+	// unreachable is an address nothing listens on anymore
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unreachable := "http://" + l.Addr().String() + "/hook"
+	l.Close()
+
+	tests := []struct {
+		name        string
+		url         string
+		opts        gitprovider.RepositoryHookCreateOptions
+		expectedErr error
+		wantHooks   int
+	}{
+		{
+			name:      "unreachable URL isn't verified by default",
+			url:       unreachable,
+			wantHooks: 1,
+		},
+		{
+			name:        "unreachable URL",
+			url:         unreachable,
+			opts:        gitprovider.RepositoryHookCreateOptions{VerifyURL: true},
+			expectedErr: gitprovider.ErrHookURLUnreachable,
+		},
+		{
+			name:      "reachable URL",
+			url:       reachable.URL,
+			opts:      gitprovider.RepositoryHookCreateOptions{VerifyURL: true},
+			wantHooks: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeHookClient{secrets: map[int64]string{}}
+			c := &RepositoryHookClient{
+				clientContext: &clientContext{c: fake, domain: DefaultDomain},
+				ref: gitprovider.UserRepositoryRef{
+					UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+					RepositoryName: "bar",
+				},
+			}
+			_, err := c.Create(context.Background(), gitprovider.RepositoryHookInfo{URL: tt.url}, tt.opts)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.expectedErr)
+			}
+			if len(fake.hooks) != tt.wantHooks {
+				t.Errorf("server got %d webhooks, want %d", len(fake.hooks), tt.wantHooks)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// IssueClient implements the gitprovider.IssueClient interface.
+var _ gitprovider.IssueClient = &IssueClient{}
+
+// IssueClient operates on the issues of a specific repository.
+type IssueClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Create creates an open issue with the given title, body and labels.
+// The title must not be empty.
+func (c *IssueClient) Create(ctx context.Context, title, body string, labels []string) (gitprovider.Issue, error) {
+	if err := gitprovider.ValidateIssueRequest(title, labels); err != nil {
+		return nil, err
+	}
+	req := &github.IssueRequest{
+		Title: &title,
+		Body:  &body,
+	}
+	if len(labels) != 0 {
+		req.Labels = &labels
+	}
+	// POST /repos/{owner}/{repo}/issues
+	apiObj, err := c.c.CreateIssue(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue %q: %w", title, err)
+	}
+	return newIssue(c.clientContext, apiObj, c.ref), nil
+}
+
+// List lists the issues of the repository, newest first, optionally filtered by state and label.
+// Pull requests, which GitHub lists as issues too, are never included.
+//
+// List returns all available issues, using multiple paginated requests if needed.
+func (c *IssueClient) List(ctx context.Context, opts gitprovider.IssueListOptions) ([]gitprovider.Issue, error) {
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, err
+	}
+	// GET /repos/{owner}/{repo}/issues
+	apiObjs, err := c.c.ListIssues(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), issueListOptions(opts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	issues := make([]gitprovider.Issue, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListIssues
+		if apiObj.IsPullRequest() {
+			continue
+		}
+		issues = append(issues, newIssue(c.clientContext, apiObj, c.ref))
+	}
+	return issues, nil
+}
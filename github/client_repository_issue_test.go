@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// fakeIssuesClient is a githubClient serving a fixed set of issues, filtered by state and label like
+// GitHub does. Calling any other method than the overridden ones panics.
+type fakeIssuesClient struct {
+	githubClient
+
+	issues  []*github.Issue
+	created *github.IssueRequest
+}
+
+func (c *fakeIssuesClient) CreateIssue(_ context.Context, _, _ string, req *github.IssueRequest) (*github.Issue, error) {
+	c.created = req
+	return &github.Issue{
+		Number:  github.Int(len(c.issues) + 1),
+		Title:   req.Title,
+		State:   github.String("open"),
+		HTMLURL: github.String("https://github.com/foo/bar/issues/3"),
+	}, nil
+}
+
+func (c *fakeIssuesClient) ListIssues(_ context.Context, _, _ string, opts *github.IssueListByRepoOptions) ([]*github.Issue, error) {
+	apiObjs := []*github.Issue{}
+	for _, apiObj := range c.issues {
+		if opts.State != "all" && apiObj.GetState() != opts.State {
+			continue
+		}
+		if len(opts.Labels) != 0 && !issueHasLabel(apiObj, opts.Labels[0]) {
+			continue
+		}
+		apiObjs = append(apiObjs, apiObj)
+	}
+	return apiObjs, nil
+}
+
+func issueHasLabel(apiObj *github.Issue, label string) bool {
+	for _, l := range apiObj.Labels {
+		if l.GetName() == label {
+			return true
+		}
+	}
+	return false
+}
+
+func newIssueClient(fake githubClient) *IssueClient {
+	return &IssueClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain},
+		ref: gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+			RepositoryName: "bar",
+		},
+	}
+}
+
+func TestIssueClient_List(t *testing.T) {
+	bug := []*github.Label{{Name: github.String("bug")}}
+	c := newIssueClient(&fakeIssuesClient{issues: []*github.Issue{
+		{Number: github.Int(1), Title: github.String("Crash"), State: github.String("open"), Labels: bug},
+		{Number: github.Int(2), Title: github.String("Typo"), State: github.String("closed")},
+		{Number: github.Int(3), Title: github.String("Old crash"), State: github.String("closed"), Labels: bug},
+		// Pull requests are listed as issues too
+		{Number: github.Int(4), Title: github.String("Fix crash"), State: github.String("open"),
+			PullRequestLinks: &github.PullRequestLinks{}},
+	}})
+
+	tests := []struct {
+		name string
+		opts gitprovider.IssueListOptions
+		want []int
+	}{
+		{
+			name: "all states",
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "open",
+			opts: gitprovider.IssueListOptions{State: gitprovider.IssueStateVar(gitprovider.IssueStateOpen)},
+			want: []int{1},
+		},
+		{
+			name: "closed",
+			opts: gitprovider.IssueListOptions{State: gitprovider.IssueStateVar(gitprovider.IssueStateClosed)},
+			want: []int{2, 3},
+		},
+		{
+			name: "closed with label",
+			opts: gitprovider.IssueListOptions{State: gitprovider.IssueStateVar(gitprovider.IssueStateClosed), Label: "bug"},
+			want: []int{3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := c.List(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			got := []int{}
+			for _, issue := range issues {
+				got = append(got, issue.Get().Number)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Unknown states are rejected before any request is made
+	opts := gitprovider.IssueListOptions{State: gitprovider.IssueStateVar("merged")}
+	if _, err := c.List(context.Background(), opts); !errors.Is(err, validation.ErrFieldEnumInvalid) {
+		t.Errorf("List() error = %v, want %v", err, validation.ErrFieldEnumInvalid)
+	}
+}
+
+func TestIssueClient_Create(t *testing.T) {
+	fake := &fakeIssuesClient{issues: []*github.Issue{{Number: github.Int(1)}, {Number: github.Int(2)}}}
+	c := newIssueClient(fake)
+
+	issue, err := c.Create(context.Background(), "Crash on start", "Stack trace", []string{"bug"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	want := gitprovider.IssueInfo{
+		Number: 3,
+		Title:  "Crash on start",
+		State:  gitprovider.IssueStateOpen,
+		URL:    "https://github.com/foo/bar/issues/3",
+	}
+	if got := issue.Get(); got != want {
+		t.Errorf("Create() = %v, want %v", got, want)
+	}
+	if fake.created.GetBody() != "Stack trace" || !reflect.DeepEqual(fake.created.GetLabels(), []string{"bug"}) {
+		t.Errorf("Create() request = %v", fake.created)
+	}
+
+	// An empty title is rejected before any request is made
+	fake.created = nil
+	if _, err := c.Create(context.Background(), " ", "Stack trace", nil); !errors.Is(err, validation.ErrFieldRequired) {
+		t.Errorf("Create() error = %v, want %v", err, validation.ErrFieldRequired)
+	}
+	if fake.created != nil {
+		t.Errorf("Create() sent a request for an empty title")
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// ReleaseClient implements the gitprovider.ReleaseClient interface.
+var _ gitprovider.ReleaseClient = &ReleaseClient{}
+
+// ReleaseClient operates on the releases of a specific repository.
+type ReleaseClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// LatestRelease returns the most recently released release of the repository, as determined by
+// GitHub. Drafts and prereleases are never considered the latest release.
+//
+// ErrNotFound is returned if the repository has no (published) releases.
+func (c *ReleaseClient) LatestRelease(ctx context.Context) (gitprovider.ReleaseInfo, error) {
+	// GET /repos/{owner}/{repo}/releases/latest
+	apiObj, err := c.c.GetLatestRelease(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return gitprovider.ReleaseInfo{}, err
+	}
+	return releaseFromAPI(apiObj), nil
+}
+
+// Get returns the release made from the given tag.
+//
+// ErrNotFound is returned if the tag has no release, or doesn't exist.
+func (c *ReleaseClient) Get(ctx context.Context, tag string) (gitprovider.Release, error) {
+	// GET /repos/{owner}/{repo}/releases/tags/{tag}
+	apiObj, err := c.c.GetReleaseByTag(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), tag)
+	if err != nil {
+		return nil, err
+	}
+	return newRelease(c.clientContext, apiObj, c.ref), nil
+}
+
+// List lists all releases of the repository, newest first. Drafts are only included if the
+// client has push access to the repository.
+//
+// List returns all available releases, using multiple paginated requests if needed.
+func (c *ReleaseClient) List(ctx context.Context) ([]gitprovider.Release, error) {
+	// GET /repos/{owner}/{repo}/releases
+	apiObjs, err := c.c.ListReleases(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+	releases := make([]gitprovider.Release, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListReleases
+		releases = append(releases, newRelease(c.clientContext, apiObj, c.ref))
+	}
+	return releases, nil
+}
+
+// Create creates a release with the given specifications. The tag is created from
+// req.TargetCommitish if it doesn't exist yet.
+//
+// ErrAlreadyExists will be returned if the tag already has a release.
+func (c *ReleaseClient) Create(ctx context.Context, req gitprovider.ReleaseInfo) (gitprovider.Release, error) {
+	if err := req.ValidateInfo(); err != nil {
+		return nil, err
+	}
+	// GitHub's error for a duplicate release isn't recognizable, hence check for it first
+	if _, err := c.Get(ctx, req.TagName); err == nil {
+		return nil, fmt.Errorf("release for tag %q: %w", req.TagName, gitprovider.ErrAlreadyExists)
+	} else if !errors.Is(err, gitprovider.ErrNotFound) {
+		return nil, err
+	}
+
+	// POST /repos/{owner}/{repo}/releases
+	apiObj, err := c.c.CreateRelease(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), releaseToAPI(&req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release for tag %q: %w", req.TagName, err)
+	}
+	return newRelease(c.clientContext, apiObj, c.ref), nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// fakeReleasesClient is a githubClient serving the releases of a repository by tag, and recording
+// the created ones. Calling any other method than the overridden ones panics.
+type fakeReleasesClient struct {
+	githubClient
+
+	releases map[string]*github.RepositoryRelease
+	created  []*github.RepositoryRelease
+}
+
+func (c *fakeReleasesClient) GetReleaseByTag(_ context.Context, _, _, tag string) (*github.RepositoryRelease, error) {
+	apiObj, ok := c.releases[tag]
+	if !ok {
+		return nil, gitprovider.ErrNotFound
+	}
+	return apiObj, nil
+}
+
+func (c *fakeReleasesClient) CreateRelease(_ context.Context, _, _ string, req *github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	c.created = append(c.created, req)
+	return req, nil
+}
+
+func newReleaseClient(fake githubClient) *ReleaseClient {
+	return &ReleaseClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain},
+		ref: gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+			RepositoryName: "bar",
+		},
+	}
+}
+
+func TestReleaseClient_Get(t *testing.T) {
+	c := newReleaseClient(&fakeReleasesClient{releases: map[string]*github.RepositoryRelease{
+		"v1.0.0": {TagName: github.String("v1.0.0"), Name: github.String("First")},
+	}})
+
+	release, err := c.Get(context.Background(), "v1.0.0")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := release.Get(); got.TagName != "v1.0.0" || got.Name == nil || *got.Name != "First" {
+		t.Errorf("Get() = %v", got)
+	}
+	// A tag without release is reported as not found
+	if _, err := c.Get(context.Background(), "v2.0.0"); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+}
+
+func TestReleaseClient_Create(t *testing.T) {
+	fake := &fakeReleasesClient{releases: map[string]*github.RepositoryRelease{
+		"v1.0.0": {TagName: github.String("v1.0.0")},
+	}}
+	c := newReleaseClient(fake)
+
+	req := gitprovider.ReleaseInfo{
+		TagName:         "v1.1.0",
+		TargetCommitish: gitprovider.StringVar("main"),
+		Body:            gitprovider.StringVar("Changelog"),
+		Prerelease:      gitprovider.BoolVar(true),
+	}
+	release, err := c.Create(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if got := release.Get(); !got.Equals(req) {
+		t.Errorf("Create() = %v, want %v", got, req)
+	}
+
+	// A tag that already has a release isn't sent to GitHub
+	if _, err := c.Create(context.Background(), gitprovider.ReleaseInfo{TagName: "v1.0.0"}); !errors.Is(err, gitprovider.ErrAlreadyExists) {
+		t.Errorf("Create() error = %v, want %v", err, gitprovider.ErrAlreadyExists)
+	}
+	if len(fake.created) != 1 {
+		t.Errorf("Create() sent %d requests, want 1", len(fake.created))
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// RulesetClient implements the gitprovider.RulesetClient interface.
+var _ gitprovider.RulesetClient = &RulesetClient{}
+
+// RulesetClient operates on the rulesets of a specific repository.
+type RulesetClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the ruleset with the given name.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *RulesetClient) Get(ctx context.Context, name string) (gitprovider.Ruleset, error) {
+	return c.get(ctx, name)
+}
+
+func (c *RulesetClient) get(ctx context.Context, name string) (*repositoryRuleset, error) {
+	// GET /repos/{owner}/{repo}/rulesets
+	apiObj, err := c.c.GetRepoRulesetByName(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), name)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := c.listTeams(ctx, apiObj, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newRepositoryRuleset(c, apiObj, teams), nil
+}
+
+// validateEnvironments returns ErrNotFound, listing the missing environments, if any of the given
+// environments doesn't exist in the repository. No request is sent if there are no environments.
+func (c *RulesetClient) validateEnvironments(ctx context.Context, environments []string) error {
+	if len(environments) == 0 {
+		return nil
+	}
+	// GET /repos/{owner}/{repo}/environments
+	names, err := c.c.ListRepoEnvironments(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return err
+	}
+	// The names of environments aren't case sensitive
+	existing := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		existing[strings.ToLower(name)] = struct{}{}
+	}
+	missing := []string{}
+	for _, env := range environments {
+		if _, ok := existing[strings.ToLower(env)]; !ok {
+			missing = append(missing, env)
+		}
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("%w: environments %s", gitprovider.ErrNotFound, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// listTeams returns the teams of the organization owning the repository, if the ruleset (apiObj,
+// which may be nil) or the desired merge teams refer to any teams. Otherwise, nil is returned
+// without sending any request.
+func (c *RulesetClient) listTeams(ctx context.Context, apiObj *ruleset, mergeTeams []string) (rulesetTeams, error) {
+	if len(mergeTeams) == 0 && !hasTeamBypassActors(apiObj) {
+		return nil, nil
+	}
+	// GET /orgs/{org}/teams
+	apiObjs, err := c.c.ListOrgTeams(ctx, c.ref.GetIdentity())
+	if err != nil {
+		return nil, err
+	}
+	teams := make(rulesetTeams, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		teams[apiObj.GetSlug()] = apiObj.GetID()
+	}
+	return teams, nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+// The ruleset is looked up by its name. Rules of the ruleset that aren't modelled in gitprovider.RulesetInfo,
+// e.g. the merge queue, are kept. The merge teams are the teams that can bypass the ruleset; bypass
+// actors that aren't teams are kept. ErrNotFound is returned, listing the missing teams, if any of the
+// merge teams doesn't exist in the organization, or any of the required deployment environments
+// doesn't exist in the repository.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *RulesetClient) Reconcile(ctx context.Context, req gitprovider.RulesetInfo) (gitprovider.Ruleset, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	// Get the ruleset with the desired name
+	actual, err := c.get(ctx, req.Name)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			if err := c.validateEnvironments(ctx, req.RequiredDeploymentEnvironments); err != nil {
+				return nil, false, err
+			}
+			teams, err := c.listTeams(ctx, nil, req.MergeTeams)
+			if err != nil {
+				return nil, false, err
+			}
+			apiObj, err := rulesetToAPI(&req, teams)
+			if err != nil {
+				return nil, false, err
+			}
+			// POST /repos/{owner}/{repo}/rulesets
+			apiObj, err = c.c.CreateRepoRuleset(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), apiObj)
+			if err != nil {
+				return nil, false, err
+			}
+			return newRepositoryRuleset(c, apiObj, teams), true, nil
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+	// The teams are only listed if the actual ruleset refers to any, hence list them for the desired ones
+	if actual.teams == nil {
+		if actual.teams, err = c.listTeams(ctx, nil, req.MergeTeams); err != nil {
+			return nil, false, err
+		}
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Populate the desired state to the current-actual object
+	if err := c.validateEnvironments(ctx, req.RequiredDeploymentEnvironments); err != nil {
+		return actual, false, err
+	}
+	if err := actual.Set(req); err != nil {
+		return actual, false, err
+	}
+	// Apply the desired state by running Update
+	return actual, true, actual.Update(ctx)
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// fakeRulesetClient is a githubClient that keeps the rulesets of a single repository in memory.
+// Calling any other method than the overridden ones panics.
+type fakeRulesetClient struct {
+	githubClient
+
+	rulesets []*ruleset
+	updates  int
+	// teams are the teams of the organization owning the repository
+	teams []*github.Team
+	// environments are the names of the environments of the repository
+	environments []string
+}
+
+func (c *fakeRulesetClient) ListRepoEnvironments(_ context.Context, _, _ string) ([]string, error) {
+	return c.environments, nil
+}
+
+func (c *fakeRulesetClient) ListOrgTeams(_ context.Context, _ string) ([]*github.Team, error) {
+	return c.teams, nil
+}
+
+func (c *fakeRulesetClient) GetRepoRulesetByName(_ context.Context, _, _, name string) (*ruleset, error) {
+	for _, rs := range c.rulesets {
+		if *rs.Name == name {
+			return roundTripRuleset(rs), nil
+		}
+	}
+	return nil, gitprovider.ErrNotFound
+}
+
+func (c *fakeRulesetClient) CreateRepoRuleset(_ context.Context, _, _ string, req *ruleset) (*ruleset, error) {
+	apiObj := roundTripRuleset(req)
+	id := int64(len(c.rulesets) + 1)
+	apiObj.ID = &id
+	c.rulesets = append(c.rulesets, apiObj)
+	return roundTripRuleset(apiObj), nil
+}
+
+func (c *fakeRulesetClient) UpdateRepoRuleset(_ context.Context, _, _ string, req *ruleset) (*ruleset, error) {
+	for i, rs := range c.rulesets {
+		if *rs.ID == *req.ID {
+			c.updates++
+			c.rulesets[i] = roundTripRuleset(req)
+			return roundTripRuleset(req), nil
+		}
+	}
+	return nil, gitprovider.ErrNotFound
+}
+
+// roundTripRuleset returns a deep copy of rs, as if it was sent to or received from the server.
+func roundTripRuleset(rs *ruleset) *ruleset {
+	data, _ := json.Marshal(rs)
+	out := &ruleset{}
+	_ = json.Unmarshal(data, out)
+	return out
+}
+
+func TestRulesetClient_Reconcile(t *testing.T) {
+	mergeQueue := &rulesetRule{Type: rulesetRuleTypeMergeQueue, Parameters: json.RawMessage(`{"max_entries_to_merge":5}`)}
+	id := int64(1)
+	fake := &fakeRulesetClient{rulesets: []*ruleset{{
+		ID:          &id,
+		Name:        gitprovider.StringVar("main"),
+		Target:      gitprovider.StringVar(rulesetTargetBranch),
+		Enforcement: gitprovider.StringVar(rulesetEnforcementActive),
+		Conditions: &rulesetConditions{
+			RefName: &rulesetRefNameCondition{Include: []string{"refs/heads/main"}, Exclude: []string{}},
+		},
+		Rules: []*rulesetRule{mergeQueue},
+	}}}
+	c := &RulesetClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain},
+		ref: gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+			RepositoryName: "bar",
+		},
+	}
+	ctx := context.Background()
+	reconcile := func(info gitprovider.RulesetInfo, wantActionTaken bool) gitprovider.RulesetInfo {
+		t.Helper()
+		rs, actionTaken, err := c.Reconcile(ctx, info)
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if actionTaken != wantActionTaken {
+			t.Errorf("Reconcile() actionTaken = %v, want %v", actionTaken, wantActionTaken)
+		}
+		return rs.Get()
+	}
+
+	// Requiring signatures on the existing ruleset keeps its merge queue rule
+	actual := reconcile(gitprovider.RulesetInfo{Name: "main", Include: []string{"refs/heads/main"}, RequiredSignatures: true}, true)
+	if !actual.RequiredSignatures {
+		t.Error("Reconcile() RequiredSignatures = false, want true")
+	}
+	if rule := rulesetRuleOfType(fake.rulesets[0], rulesetRuleTypeMergeQueue); rule == nil || string(rule.Parameters) != string(mergeQueue.Parameters) {
+		t.Errorf("Reconcile() merge queue rule = %+v, want %+v", rule, mergeQueue)
+	}
+
+	// Reconciling the same state again is a no-op
+	reconcile(gitprovider.RulesetInfo{Name: "main", Include: []string{"refs/heads/main"}, RequiredSignatures: true}, false)
+	if fake.updates != 1 {
+		t.Errorf("UpdateRepoRuleset() called %d times, want 1", fake.updates)
+	}
+
+	// Not requiring signatures removes the rule again
+	reconcile(gitprovider.RulesetInfo{Name: "main", Include: []string{"refs/heads/main"}}, true)
+	if rulesetRuleOfType(fake.rulesets[0], rulesetRuleTypeRequiredSignatures) != nil || len(fake.rulesets[0].Rules) != 1 {
+		t.Errorf("Reconcile() rules = %+v, want only the merge queue", fake.rulesets[0].Rules)
+	}
+
+	// A ruleset that doesn't exist is created
+	actual = reconcile(gitprovider.RulesetInfo{Name: "releases", Include: []string{"refs/heads/release/*"}, RequiredSignatures: true}, true)
+	if len(fake.rulesets) != 2 || !actual.RequiredSignatures || *actual.Target != gitprovider.RulesetTargetBranch {
+		t.Errorf("Reconcile() created %+v, rulesets = %d", actual, len(fake.rulesets))
+	}
+}
+
+func TestRulesetClient_Reconcile_mergeTeams(t *testing.T) {
+	admins := &rulesetBypassActor{ActorID: github.Int64(1), ActorType: "OrganizationAdmin", BypassMode: rulesetBypassModeAlways}
+	id := int64(1)
+	fake := &fakeRulesetClient{
+		rulesets: []*ruleset{{
+			ID:          &id,
+			Name:        gitprovider.StringVar("main"),
+			Target:      gitprovider.StringVar(rulesetTargetBranch),
+			Enforcement: gitprovider.StringVar(rulesetEnforcementActive),
+			Conditions: &rulesetConditions{
+				RefName: &rulesetRefNameCondition{Include: []string{"refs/heads/main"}, Exclude: []string{}},
+			},
+			Rules:        []*rulesetRule{},
+			BypassActors: &[]*rulesetBypassActor{admins},
+		}},
+		teams: []*github.Team{
+			{ID: github.Int64(10), Name: github.String("Platform"), Slug: github.String("platform")},
+			{ID: github.Int64(20), Name: github.String("Release"), Slug: github.String("release")},
+		},
+	}
+	c := &RulesetClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain},
+		ref: gitprovider.OrgRepositoryRef{
+			OrganizationRef: gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"},
+			RepositoryName:  "bar",
+		},
+	}
+	ctx := context.Background()
+	teamIDs := func() []int64 {
+		ids := []int64{}
+		for _, actor := range *fake.rulesets[0].BypassActors {
+			if actor.ActorType == rulesetActorTypeTeam {
+				ids = append(ids, *actor.ActorID)
+			}
+		}
+		return ids
+	}
+	req := gitprovider.RulesetInfo{
+		Name:           "main",
+		Include:        []string{"refs/heads/main"},
+		RestrictMerges: true,
+		MergeTeams:     []string{"release", "platform", "release"},
+	}
+
+	// Restricting merges to teams adds the update rule and the teams as bypass actors, keeping the
+	// other bypass actors
+	rs, actionTaken, err := c.Reconcile(ctx, req)
+	if err != nil || !actionTaken {
+		t.Fatalf("Reconcile() = %v, %v, want true, nil", actionTaken, err)
+	}
+	if got := rs.Get().MergeTeams; !reflect.DeepEqual(got, []string{"platform", "release"}) {
+		t.Errorf("Reconcile() MergeTeams = %v", got)
+	}
+	if rulesetRuleOfType(fake.rulesets[0], rulesetRuleTypeUpdate) == nil {
+		t.Error("Reconcile() didn't add the update rule")
+	}
+	if got := teamIDs(); !reflect.DeepEqual(got, []int64{10, 20}) {
+		t.Errorf("Reconcile() team bypass actors = %v, want [10 20]", got)
+	}
+	if actors := *fake.rulesets[0].BypassActors; !reflect.DeepEqual(actors[0], admins) {
+		t.Errorf("Reconcile() bypass actors = %+v, want the organization admins kept", actors)
+	}
+
+	// Reconciling the same state again is a no-op
+	if _, actionTaken, err := c.Reconcile(ctx, req); err != nil || actionTaken {
+		t.Errorf("Reconcile() = %v, %v, want false, nil", actionTaken, err)
+	}
+
+	// Teams that don't exist are listed, and nothing is applied
+	missing := req
+	missing.MergeTeams = []string{"platform", "security", "audit"}
+	_, _, err = c.Reconcile(ctx, missing)
+	if !errors.Is(err, gitprovider.ErrNotFound) || !strings.Contains(err.Error(), "audit, security") {
+		t.Errorf("Reconcile() error = %v, want %v listing the missing teams", err, gitprovider.ErrNotFound)
+	}
+	if fake.updates != 1 {
+		t.Errorf("UpdateRepoRuleset() called %d times, want 1", fake.updates)
+	}
+
+	// Lifting the restriction removes the rule and the teams
+	if _, _, err := c.Reconcile(ctx, gitprovider.RulesetInfo{Name: "main", Include: []string{"refs/heads/main"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(fake.rulesets[0].Rules) != 0 || len(teamIDs()) != 0 || len(*fake.rulesets[0].BypassActors) != 1 {
+		t.Errorf("Reconcile() rules, bypass actors = %+v, %+v", fake.rulesets[0].Rules, *fake.rulesets[0].BypassActors)
+	}
+
+	// A ruleset restricting merges is created with its teams
+	created, _, err := c.Reconcile(ctx, gitprovider.RulesetInfo{
+		Name:           "releases",
+		Include:        []string{"refs/heads/release/*"},
+		RestrictMerges: true,
+		MergeTeams:     []string{"release"},
+	})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if info := created.Get(); !info.RestrictMerges || !reflect.DeepEqual(info.MergeTeams, []string{"release"}) {
+		t.Errorf("Reconcile() created %+v", info)
+	}
+}
+
+func TestRulesetClient_Reconcile_requiredDeployments(t *testing.T) {
+	fake := &fakeRulesetClient{environments: []string{"Production", "staging"}}
+	c := &RulesetClient{
+		clientContext: &clientContext{c: fake, domain: DefaultDomain},
+		ref: gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+			RepositoryName: "bar",
+		},
+	}
+	ctx := context.Background()
+	req := gitprovider.RulesetInfo{
+		Name:                           "main",
+		Include:                        []string{"refs/heads/main"},
+		RequiredDeploymentEnvironments: []string{"staging", "production"},
+	}
+
+	// Environments that don't exist are listed, and nothing is created
+	missing := req
+	missing.RequiredDeploymentEnvironments = []string{"staging", "qa", "dev"}
+	_, _, err := c.Reconcile(ctx, missing)
+	if !errors.Is(err, gitprovider.ErrNotFound) || !strings.Contains(err.Error(), "environments dev, qa") {
+		t.Errorf("Reconcile() error = %v, want %v listing the missing environments", err, gitprovider.ErrNotFound)
+	}
+	if len(fake.rulesets) != 0 {
+		t.Fatalf("Reconcile() created %d rulesets, want 0", len(fake.rulesets))
+	}
+
+	// The ruleset is created with the rule, as environment names aren't case sensitive
+	rs, actionTaken, err := c.Reconcile(ctx, req)
+	if err != nil || !actionTaken {
+		t.Fatalf("Reconcile() = %v, %v, want true, nil", actionTaken, err)
+	}
+	if got := rs.Get().RequiredDeploymentEnvironments; !reflect.DeepEqual(got, []string{"production", "staging"}) {
+		t.Errorf("Reconcile() RequiredDeploymentEnvironments = %v", got)
+	}
+	rule := rulesetRuleOfType(fake.rulesets[0], rulesetRuleTypeRequiredDeployments)
+	if rule == nil || string(rule.Parameters) != `{"required_deployment_environments":["production","staging"]}` {
+		t.Errorf("Reconcile() rule = %+v", rule)
+	}
+
+	// Reconciling the same state again is a no-op
+	if _, actionTaken, err := c.Reconcile(ctx, req); err != nil || actionTaken {
+		t.Errorf("Reconcile() = %v, %v, want false, nil", actionTaken, err)
+	}
+
+	// Changing the environments updates the rule, and not requiring any removes it
+	req.RequiredDeploymentEnvironments = []string{"production"}
+	if rs, _, err = c.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if got := rs.Get().RequiredDeploymentEnvironments; !reflect.DeepEqual(got, []string{"production"}) {
+		t.Errorf("Reconcile() RequiredDeploymentEnvironments = %v", got)
+	}
+	req.RequiredDeploymentEnvironments = nil
+	if _, _, err = c.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(fake.rulesets[0].Rules) != 0 || fake.updates != 2 {
+		t.Errorf("Reconcile() rules = %+v, updates = %d, want none, 2", fake.rulesets[0].Rules, fake.updates)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// TagClient implements the gitprovider.TagClient interface.
+var _ gitprovider.TagClient = &TagClient{}
+
+// TagClient operates on the tags of a specific repository.
+type TagClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// List lists all tags of the repository, along with the commits they point to.
+//
+// List returns all available tags, using multiple paginated requests if needed.
+func (c *TagClient) List(ctx context.Context) ([]gitprovider.Tag, error) {
+	// GET /repos/{owner}/{repo}/tags
+	apiObjs, err := c.c.ListTags(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]gitprovider.Tag, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListTags
+		tags = append(tags, newTag(c.clientContext, apiObj, c.ref))
+	}
+	return tags, nil
+}
+
+// Create creates a lightweight tag with the given name, pointing at the commit sha, by creating
+// the "refs/tags/{name}" ref.
+//
+// ErrAlreadyExists is returned if the tag already exists.
+// ErrNotFound is returned if sha isn't a commit of the repository.
+func (c *TagClient) Create(ctx context.Context, name, sha string) error {
+	if err := gitprovider.ValidateTagName(name); err != nil {
+		return validation.NewMultiError(err, gitprovider.ErrInvalidArgument)
+	}
+	owner, repo := c.ref.GetIdentity(), c.ref.GetRepository()
+
+	// GET /repos/{owner}/{repo}/git/ref/{ref}
+	_, err := c.c.GetRef(ctx, owner, repo, "tags/"+name)
+	if err == nil {
+		return gitprovider.ErrAlreadyExists
+	} else if !errors.Is(err, gitprovider.ErrNotFound) {
+		return err
+	}
+	// GitHub refuses refs to unknown objects with a generic validation error, hence resolve sha first
+	// GET /repos/{owner}/{repo}/git/commits/{commit_sha}
+	commit, err := c.c.GetCommit(ctx, owner, repo, sha)
+	if err != nil {
+		return err
+	}
+
+	// POST /repos/{owner}/{repo}/git/refs
+	_, err = c.c.CreateRef(ctx, owner, repo, &github.Reference{
+		Ref:    github.String("refs/tags/" + name),
+		Object: &github.GitObject{SHA: commit.SHA},
+	})
+	return err
+}
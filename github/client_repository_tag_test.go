@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// fakeTagClient is a githubClient with the given tags (name to commit SHA) and commits, which
+// creates tags through refs. Calling any other method than the overridden ones panics.
+type fakeTagClient struct {
+	githubClient
+
+	tags    map[string]string
+	commits map[string]bool
+}
+
+func (c *fakeTagClient) ListTags(_ context.Context, _, _ string) ([]*github.RepositoryTag, error) {
+	apiObjs := []*github.RepositoryTag{}
+	for name, sha := range c.tags {
+		apiObjs = append(apiObjs, &github.RepositoryTag{Name: github.String(name), Commit: &github.Commit{SHA: github.String(sha)}})
+	}
+	return apiObjs, nil
+}
+
+func (c *fakeTagClient) GetRef(_ context.Context, _, _, ref string) (*github.Reference, error) {
+	sha, ok := c.tags[strings.TrimPrefix(ref, "tags/")]
+	if !strings.HasPrefix(ref, "tags/") || !ok {
+		return nil, gitprovider.ErrNotFound
+	}
+	return &github.Reference{Ref: github.String("refs/" + ref), Object: &github.GitObject{SHA: github.String(sha)}}, nil
+}
+
+func (c *fakeTagClient) GetCommit(_ context.Context, _, _, sha string) (*github.Commit, error) {
+	if !c.commits[sha] {
+		return nil, gitprovider.ErrNotFound
+	}
+	return &github.Commit{SHA: github.String(sha)}, nil
+}
+
+func (c *fakeTagClient) CreateRef(_ context.Context, _, _ string, req *github.Reference) (*github.Reference, error) {
+	if !strings.HasPrefix(req.GetRef(), "refs/tags/") {
+		return nil, errors.New("unexpected ref " + req.GetRef())
+	}
+	c.tags[strings.TrimPrefix(req.GetRef(), "refs/tags/")] = req.GetObject().GetSHA()
+	return req, nil
+}
+
+func TestTagClient_Create(t *testing.T) {
+	tests := []struct {
+		name        string
+		tagName     string
+		sha         string
+		expectedErr error
+	}{
+		{
+			name:    "new tag",
+			tagName: "v1.1.0",
+			sha:     "new",
+		},
+		{
+			name:    "hierarchical tag",
+			tagName: "api/v1.1.0",
+			sha:     "new",
+		},
+		{
+			name:        "unknown commit",
+			tagName:     "v1.1.0",
+			sha:         "unknown",
+			expectedErr: gitprovider.ErrNotFound,
+		},
+		{
+			name:        "existing tag",
+			tagName:     "v1.0.0",
+			sha:         "new",
+			expectedErr: gitprovider.ErrAlreadyExists,
+		},
+		{
+			name:        "empty name",
+			tagName:     "",
+			sha:         "new",
+			expectedErr: gitprovider.ErrInvalidArgument,
+		},
+		{
+			name:        "invalid name",
+			tagName:     "v1..0",
+			sha:         "new",
+			expectedErr: gitprovider.ErrInvalidArgument,
+		},
+		{
+			name:        "lock suffix",
+			tagName:     "v1.lock",
+			sha:         "new",
+			expectedErr: gitprovider.ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeTagClient{
+				tags:    map[string]string{"v1.0.0": "old"},
+				commits: map[string]bool{"old": true, "new": true},
+			}
+			c := &TagClient{
+				clientContext: &clientContext{c: fake, domain: DefaultDomain},
+				ref: gitprovider.UserRepositoryRef{
+					UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+					RepositoryName: "bar",
+				},
+			}
+			err := c.Create(context.Background(), tt.tagName, tt.sha)
+			if !errors.Is(err, tt.expectedErr) || (err != nil && tt.expectedErr == nil) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr != nil {
+				// The existing tag is kept as-is
+				if len(fake.tags) != 1 || fake.tags["v1.0.0"] != "old" {
+					t.Errorf("Create() changed the tags to %v", fake.tags)
+				}
+				return
+			}
+			tags, err := c.List(context.Background())
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			found := false
+			for _, tag := range tags {
+				if got := tag.Get(); got.Name == tt.tagName {
+					found = got.Sha == tt.sha
+				}
+			}
+			if !found {
+				t.Errorf("List() doesn't contain tag %q at %q", tt.tagName, tt.sha)
+			}
+		})
+	}
+}
@@ -18,7 +18,11 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
 	"github.com/google/go-github/v32/github"
@@ -35,10 +39,16 @@ type githubClient interface {
 	// GetOrg is a wrapper for "GET /orgs/{org}".
 	// This function HTTP error wrapping, and validates the server result.
 	GetOrg(ctx context.Context, orgName string) (*github.Organization, error)
+	// UpdateOrg is a wrapper for "PATCH /orgs/{org}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateOrg(ctx context.Context, orgName string, req *github.Organization) (*github.Organization, error)
 	// ListOrgs is a wrapper for "GET /user/orgs".
 	// This function handles pagination, HTTP error wrapping, and validates the server result.
 	ListOrgs(ctx context.Context) ([]*github.Organization, error)
 
+	// GetOrgTeam is a wrapper for "GET /orgs/{org}/teams/{team_slug}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetOrgTeam(ctx context.Context, orgName, teamSlug string) (*github.Team, error)
 	// ListOrgTeamMembers is a wrapper for "GET /orgs/{org}/teams/{team_slug}/members".
 	// This function handles pagination, HTTP error wrapping, and validates the server result.
 	ListOrgTeamMembers(ctx context.Context, orgName, teamName string) ([]*github.User, error)
@@ -46,19 +56,46 @@ type githubClient interface {
 	// This function handles pagination, HTTP error wrapping, and validates the server result.
 	ListOrgTeams(ctx context.Context, orgName string) ([]*github.Team, error)
 
+	// GetAuthenticatedUser is a wrapper for "GET /user".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetAuthenticatedUser(ctx context.Context) (*github.User, error)
+
 	// GetRepo is a wrapper for "GET /repos/{owner}/{repo}".
 	// This function handles HTTP error wrapping, and validates the server result.
 	GetRepo(ctx context.Context, owner, repo string) (*github.Repository, error)
 	// ListOrgRepos is a wrapper for "GET /orgs/{org}/repos".
 	// This function handles pagination, HTTP error wrapping, and validates the server result.
 	ListOrgRepos(ctx context.Context, org string) ([]*github.Repository, error)
+	// ListOrgReposOfType is a wrapper for "GET /orgs/{org}/repos?type={repoType}".
+	// repoType may be an empty string, in which case all repositories are listed.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListOrgReposOfType(ctx context.Context, org, repoType string) ([]*github.Repository, error)
 	// ListUserRepos is a wrapper for "GET /users/{username}/repos".
 	// This function handles pagination, HTTP error wrapping, and validates the server result.
 	ListUserRepos(ctx context.Context, username string) ([]*github.Repository, error)
+	// ListOrgReposPage is a wrapper for one page of "GET /orgs/{org}/repos".
+	// This function handles HTTP error wrapping, and validates the server result.
+	// sort and direction are passed as-is to the server, and may be empty for the server defaults.
+	ListOrgReposPage(ctx context.Context, org string, opts gitprovider.PageOptions, sort, direction string) ([]*github.Repository, gitprovider.PageInfo, error)
+	// ListUserReposPage is a wrapper for one page of "GET /users/{username}/repos".
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListUserReposPage(ctx context.Context, username string, opts gitprovider.PageOptions) ([]*github.Repository, gitprovider.PageInfo, error)
 	// CreateRepo is a wrapper for "POST /user/repos" (if orgName == "")
 	// or "POST /orgs/{org}/repos" (if orgName != "").
 	// This function handles HTTP error wrapping, and validates the server result.
 	CreateRepo(ctx context.Context, orgName string, req *github.Repository) (*github.Repository, error)
+	// CreateRepoFromTemplate is a wrapper for "POST /repos/{template_owner}/{template_repo}/generate".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateRepoFromTemplate(ctx context.Context, templateOwner, templateRepo string, req *github.TemplateRepoRequest) (*github.Repository, error)
+	// CreateFork is a wrapper for "POST /repos/{owner}/{repo}/forks", forking into the user's account
+	// if orgName == "". GitHub creates the fork asynchronously, hence "202 Accepted" isn't an error.
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateFork(ctx context.Context, owner, repo, orgName string) (*github.Repository, error)
+	// TransferRepo is a wrapper for "POST /repos/{owner}/{repo}/transfer". GitHub transfers the
+	// repository asynchronously, hence "202 Accepted" isn't an error.
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	TransferRepo(ctx context.Context, owner, repo, newOwner string) error
 	// UpdateRepo is a wrapper for "PATCH /repos/{owner}/{repo}".
 	// This function handles HTTP error wrapping, and validates the server result.
 	UpdateRepo(ctx context.Context, owner, repo string, req *github.Repository) (*github.Repository, error)
@@ -66,10 +103,44 @@ type githubClient interface {
 	// This function handles HTTP error wrapping.
 	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
 	DeleteRepo(ctx context.Context, owner, repo string) error
+	// GetRepoForkingSettings is a wrapper for "GET /repos/{owner}/{repo}", which only
+	// decodes the allow_forking field.
+	// This function handles HTTP error wrapping.
+	GetRepoForkingSettings(ctx context.Context, owner, repo string) (*repositoryForkingSettings, error)
+	// UpdateRepoForkingSettings is a wrapper for "PATCH /repos/{owner}/{repo}", which only
+	// updates the allow_forking field.
+	// This function handles HTTP error wrapping.
+	UpdateRepoForkingSettings(ctx context.Context, owner, repo string, req *repositoryForkingSettings) (*repositoryForkingSettings, error)
+	// ReplaceRepoTopics is a wrapper for "PUT /repos/{owner}/{repo}/topics".
+	// The topics stored by the server are returned.
+	// This function handles HTTP error wrapping.
+	ReplaceRepoTopics(ctx context.Context, owner, repo string, topics []string) ([]string, error)
+	// GetRepoFileContent is a wrapper for "GET /repos/{owner}/{repo}/contents/{path}", returning the
+	// content of the file at path on the default branch. ErrNotFound is returned if it isn't a file.
+	// This function handles HTTP error wrapping.
+	GetRepoFileContent(ctx context.Context, owner, repo, path string) (string, error)
+	// GetCommunityHealthMetrics is a wrapper for "GET /repos/{owner}/{repo}/community/profile".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetCommunityHealthMetrics(ctx context.Context, owner, repo string) (*github.CommunityHealthMetrics, error)
+	// GetRepoSubscription is a wrapper for "GET /repos/{owner}/{repo}/subscription".
+	// nil is returned if the authenticated user isn't watching nor ignoring the repository.
+	// This function handles HTTP error wrapping.
+	GetRepoSubscription(ctx context.Context, owner, repo string) (*github.Subscription, error)
+	// SetRepoSubscription is a wrapper for "PUT /repos/{owner}/{repo}/subscription".
+	// This function handles HTTP error wrapping.
+	SetRepoSubscription(ctx context.Context, owner, repo string, req *github.Subscription) error
+	// DeleteRepoSubscription is a wrapper for "DELETE /repos/{owner}/{repo}/subscription".
+	// This function handles HTTP error wrapping.
+	DeleteRepoSubscription(ctx context.Context, owner, repo string) error
 
 	// ListKeys is a wrapper for "GET /repos/{owner}/{repo}/keys".
 	// This function handles pagination, HTTP error wrapping, and validates the server result.
 	ListKeys(ctx context.Context, owner, repo string) ([]*github.Key, error)
+	// GetKeyByTitle is a wrapper for "GET /repos/{owner}/{repo}/keys", returning the first key with
+	// the given title. GitHub has no endpoint to get a key by its title, hence the keys are listed
+	// page by page, until the key is found. ErrNotFound is returned if there is no such key.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	GetKeyByTitle(ctx context.Context, owner, repo, title string) (*github.Key, error)
 	// CreateKey is a wrapper for "POST /repos/{owner}/{repo}/keys".
 	// This function handles HTTP error wrapping, and validates the server result.
 	CreateKey(ctx context.Context, owner, repo string, req *github.Key) (*github.Key, error)
@@ -77,6 +148,20 @@ type githubClient interface {
 	// This function handles HTTP error wrapping.
 	DeleteKey(ctx context.Context, owner, repo string, id int64) error
 
+	// ListHooks is a wrapper for "GET /repos/{owner}/{repo}/hooks".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListHooks(ctx context.Context, owner, repo string) ([]*github.Hook, error)
+	// CreateHook is a wrapper for "POST /repos/{owner}/{repo}/hooks".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateHook(ctx context.Context, owner, repo string, req *github.Hook) (*github.Hook, error)
+	// EditHook is a wrapper for "PATCH /repos/{owner}/{repo}/hooks/{hook_id}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	EditHook(ctx context.Context, owner, repo string, id int64, req *github.Hook) (*github.Hook, error)
+	// DeleteHook is a wrapper for "DELETE /repos/{owner}/{repo}/hooks/{hook_id}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteHook(ctx context.Context, owner, repo string, id int64) error
+
 	// GetTeamPermissions is a wrapper for "GET /orgs/{org}/teams/{team_slug}/repos/{owner}/{repo}".
 	// This function handles HTTP error wrapping, and validates the server result.
 	GetTeamPermissions(ctx context.Context, orgName, repo, teamName string) (map[string]bool, error)
@@ -89,6 +174,163 @@ type githubClient interface {
 	// RemoveTeam is a wrapper for "DELETE /orgs/{org}/teams/{team_slug}/repos/{owner}/{repo}".
 	// This function handles HTTP error wrapping.
 	RemoveTeam(ctx context.Context, orgName, repo, teamName string) error
+
+	// ListRepoSecurityAdvisories is a wrapper for "GET /repos/{owner}/{repo}/security-advisories".
+	// state may be an empty string, in which case advisories in all states are listed.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListRepoSecurityAdvisories(ctx context.Context, owner, repo, state string) ([]*securityAdvisory, error)
+
+	// ListRepoInstallations is a wrapper for "GET /user/installations", and
+	// "GET /user/installations/{installation_id}/repositories" for installations that only
+	// have access to selected repositories. Only installations with access to the repository are returned.
+	// This requires a user-to-server token, a 403 Forbidden is returned wrapping ErrInsufficientScope.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListRepoInstallations(ctx context.Context, owner, repo string) ([]*installation, error)
+
+	// CountSearchIssues is a wrapper for "GET /search/issues?q={query}", which only returns the
+	// total amount of matching issues and pull requests, requesting a single item.
+	// This function handles HTTP error wrapping.
+	CountSearchIssues(ctx context.Context, query string) (int64, error)
+
+	// ListRepoLanguages is a wrapper for "GET /repos/{owner}/{repo}/languages".
+	// The returned map has the amount of bytes of code written in each language.
+	// This function handles HTTP error wrapping.
+	ListRepoLanguages(ctx context.Context, owner, repo string) (map[string]int, error)
+
+	// RepoHasBranches is a wrapper for "GET /repos/{owner}/{repo}/branches", which only
+	// requests a single branch, to check whether there are any branches at all.
+	// This function handles HTTP error wrapping.
+	RepoHasBranches(ctx context.Context, owner, repo string) (bool, error)
+
+	// Branch protection methods
+
+	// GetBranchProtection is a wrapper for "GET /repos/{owner}/{repo}/branches/{branch}/protection".
+	// This function handles HTTP error wrapping.
+	GetBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Protection, error)
+	// UpdateBranchProtection is a wrapper for "PUT /repos/{owner}/{repo}/branches/{branch}/protection".
+	// This function handles HTTP error wrapping.
+	UpdateBranchProtection(ctx context.Context, owner, repo, branch string, req *github.ProtectionRequest) (*github.Protection, error)
+	// RemoveBranchProtection is a wrapper for "DELETE /repos/{owner}/{repo}/branches/{branch}/protection".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	RemoveBranchProtection(ctx context.Context, owner, repo, branch string) error
+
+	// Ruleset methods
+
+	// GetRepoRulesetByName is a wrapper for "GET /repos/{owner}/{repo}/rulesets", and
+	// "GET /repos/{owner}/{repo}/rulesets/{ruleset_id}" for the ruleset with the given name,
+	// as only the latter includes the rules. ErrNotFound is returned if there is no such ruleset.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	GetRepoRulesetByName(ctx context.Context, owner, repo, name string) (*ruleset, error)
+	// CreateRepoRuleset is a wrapper for "POST /repos/{owner}/{repo}/rulesets".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateRepoRuleset(ctx context.Context, owner, repo string, req *ruleset) (*ruleset, error)
+	// UpdateRepoRuleset is a wrapper for "PUT /repos/{owner}/{repo}/rulesets/{ruleset_id}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateRepoRuleset(ctx context.Context, owner, repo string, req *ruleset) (*ruleset, error)
+	// ListRepoEnvironments is a wrapper for "GET /repos/{owner}/{repo}/environments", returning the
+	// names of the environments of the repository.
+	// This function handles pagination and HTTP error wrapping.
+	ListRepoEnvironments(ctx context.Context, owner, repo string) ([]string, error)
+
+	// Actions secrets methods
+
+	// GetOrgPublicKey is a wrapper for "GET /orgs/{org}/actions/secrets/public-key".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetOrgPublicKey(ctx context.Context, org string) (*github.PublicKey, error)
+	// ListOrgSecrets is a wrapper for "GET /orgs/{org}/actions/secrets".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListOrgSecrets(ctx context.Context, org string) ([]*github.Secret, error)
+	// CreateOrUpdateOrgSecret is a wrapper for "PUT /orgs/{org}/actions/secrets/{secret_name}".
+	// This function handles HTTP error wrapping.
+	CreateOrUpdateOrgSecret(ctx context.Context, org string, req *github.EncryptedSecret) error
+	// DeleteOrgSecret is a wrapper for "DELETE /orgs/{org}/actions/secrets/{secret_name}".
+	// This function handles HTTP error wrapping.
+	DeleteOrgSecret(ctx context.Context, org, name string) error
+	// ListSelectedReposForOrgSecret is a wrapper for "GET /orgs/{org}/actions/secrets/{secret_name}/repositories".
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListSelectedReposForOrgSecret(ctx context.Context, org, name string) ([]*github.Repository, error)
+	// SetSelectedReposForOrgSecret is a wrapper for "PUT /orgs/{org}/actions/secrets/{secret_name}/repositories".
+	// This function handles HTTP error wrapping.
+	SetSelectedReposForOrgSecret(ctx context.Context, org, name string, repoIDs []int64) error
+
+	// StreamOrgAuditLog is a wrapper for "GET /orgs/{org}/audit-log", calling fn with each event and
+	// the cursor of its page, in chronological order. phrase and after may be empty strings.
+	// The context is checked for cancellation between pages. Errors from fn are returned as-is.
+	// A 403 Forbidden is returned wrapping ErrInsufficientScope.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	StreamOrgAuditLog(ctx context.Context, org, phrase, after string, fn func(apiObj *auditEvent, cursor string) error) error
+
+	// GetLatestRelease is a wrapper for "GET /repos/{owner}/{repo}/releases/latest".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetLatestRelease(ctx context.Context, owner, repo string) (*github.RepositoryRelease, error)
+	// GetReleaseByTag is a wrapper for "GET /repos/{owner}/{repo}/releases/tags/{tag}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetReleaseByTag(ctx context.Context, owner, repo, tag string) (*github.RepositoryRelease, error)
+	// ListReleases is a wrapper for "GET /repos/{owner}/{repo}/releases".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListReleases(ctx context.Context, owner, repo string) ([]*github.RepositoryRelease, error)
+	// CreateRelease is a wrapper for "POST /repos/{owner}/{repo}/releases".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateRelease(ctx context.Context, owner, repo string, req *github.RepositoryRelease) (*github.RepositoryRelease, error)
+
+	// ListTags is a wrapper for "GET /repos/{owner}/{repo}/tags".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListTags(ctx context.Context, owner, repo string) ([]*github.RepositoryTag, error)
+
+	// ListBranches is a wrapper for "GET /repos/{owner}/{repo}/branches".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListBranches(ctx context.Context, owner, repo string) ([]*github.Branch, error)
+	// GetBranch is a wrapper for "GET /repos/{owner}/{repo}/branches/{branch}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetBranch(ctx context.Context, owner, repo, branch string) (*github.Branch, error)
+	// CompareCommits is a wrapper for "GET /repos/{owner}/{repo}/compare/{base}...{head}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CompareCommits(ctx context.Context, owner, repo, base, head string) (*github.CommitsComparison, error)
+	// CreateRef is a wrapper for "POST /repos/{owner}/{repo}/git/refs".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateRef(ctx context.Context, owner, repo string, req *github.Reference) (*github.Reference, error)
+	// DeleteRef is a wrapper for "DELETE /repos/{owner}/{repo}/git/refs/{ref}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteRef(ctx context.Context, owner, repo, ref string) error
+	// GetRef is a wrapper for "GET /repos/{owner}/{repo}/git/ref/{ref}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetRef(ctx context.Context, owner, repo, ref string) (*github.Reference, error)
+	// UpdateRef is a wrapper for "PATCH /repos/{owner}/{repo}/git/refs/{ref}", without forcing the update.
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateRef(ctx context.Context, owner, repo string, req *github.Reference) (*github.Reference, error)
+	// GetCommit is a wrapper for "GET /repos/{owner}/{repo}/git/commits/{commit_sha}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetCommit(ctx context.Context, owner, repo, sha string) (*github.Commit, error)
+	// CreateBlob is a wrapper for "POST /repos/{owner}/{repo}/git/blobs".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateBlob(ctx context.Context, owner, repo string, req *github.Blob) (*github.Blob, error)
+	// CreateTree is a wrapper for "POST /repos/{owner}/{repo}/git/trees".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateTree(ctx context.Context, owner, repo, baseTree string, entries []*github.TreeEntry) (*github.Tree, error)
+	// CreateCommit is a wrapper for "POST /repos/{owner}/{repo}/git/commits".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateCommit(ctx context.Context, owner, repo string, req *github.Commit) (*github.Commit, error)
+	// ListCommitsPage is a wrapper for "GET /repos/{owner}/{repo}/commits?sha={branch}", for the given page.
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListCommitsPage(ctx context.Context, owner, repo, branch string, perPage, page int) ([]*github.RepositoryCommit, gitprovider.PageInfo, error)
+
+	// CreateIssue is a wrapper for "POST /repos/{owner}/{repo}/issues".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateIssue(ctx context.Context, owner, repo string, req *github.IssueRequest) (*github.Issue, error)
+	// ListIssues is a wrapper for "GET /repos/{owner}/{repo}/issues". The result includes pull requests.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListIssues(ctx context.Context, owner, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, error)
+
+	// ListOrgPATRequests is a wrapper for "GET /orgs/{org}/personal-access-token-requests".
+	// A 403 Forbidden is returned wrapping ErrInsufficientScope.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListOrgPATRequests(ctx context.Context, org string) ([]*patRequest, error)
+	// ReviewOrgPATRequest is a wrapper for "POST /orgs/{org}/personal-access-token-requests/{pat_request_id}".
+	// Denying a request requires destructive actions to be enabled.
+	// This function handles HTTP error wrapping.
+	ReviewOrgPATRequest(ctx context.Context, org string, id int64, action string) error
 }
 
 // githubClientImpl is a wrapper around *github.Client, which implements higher-level methods,
@@ -120,6 +362,19 @@ func (c *githubClientImpl) GetOrg(ctx context.Context, orgName string) (*github.
 	return apiObj, nil
 }
 
+func (c *githubClientImpl) UpdateOrg(ctx context.Context, orgName string, req *github.Organization) (*github.Organization, error) {
+	// PATCH /orgs/{org}
+	apiObj, _, err := c.c.Organizations.Edit(ctx, orgName, req)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateOrganizationAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
 func (c *githubClientImpl) ListOrgs(ctx context.Context) ([]*github.Organization, error) {
 	apiObjs := []*github.Organization{}
 	opts := &github.ListOptions{}
@@ -142,6 +397,19 @@ func (c *githubClientImpl) ListOrgs(ctx context.Context) ([]*github.Organization
 	return apiObjs, nil
 }
 
+func (c *githubClientImpl) GetOrgTeam(ctx context.Context, orgName, teamSlug string) (*github.Team, error) {
+	// GET /orgs/{org}/teams/{team_slug}
+	apiObj, _, err := c.c.Teams.GetTeamBySlug(ctx, orgName, teamSlug)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure the Name and Slug fields are set.
+	if err := validateTeamAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
 func (c *githubClientImpl) ListOrgTeamMembers(ctx context.Context, orgName, teamName string) ([]*github.User, error) {
 	apiObjs := []*github.User{}
 	opts := &github.TeamListTeamMembersOptions{}
@@ -179,21 +447,117 @@ func (c *githubClientImpl) ListOrgTeams(ctx context.Context, orgName string) ([]
 		return nil, err
 	}
 
-	// Make sure the Slug field is set.
+	// Make sure the Name and Slug fields are set.
 	for _, apiObj := range apiObjs {
-		if apiObj.Slug == nil {
-			return nil, fmt.Errorf("didn't expect slug to be nil for team: %+v: %w", apiObj, gitprovider.ErrInvalidServerData)
+		if err := validateTeamAPI(apiObj); err != nil {
+			return nil, err
 		}
 	}
 	return apiObjs, nil
 }
 
+func (c *githubClientImpl) GetAuthenticatedUser(ctx context.Context) (*github.User, error) {
+	// GET /user
+	apiObj, _, err := c.c.Users.Get(ctx, "")
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateUserAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
 func (c *githubClientImpl) GetRepo(ctx context.Context, owner, repo string) (*github.Repository, error) {
 	// GET /repos/{owner}/{repo}
 	apiObj, _, err := c.c.Repositories.Get(ctx, owner, repo)
 	return validateRepositoryAPIResp(apiObj, err)
 }
 
+func (c *githubClientImpl) GetRepoForkingSettings(ctx context.Context, owner, repo string) (*repositoryForkingSettings, error) {
+	// go-github doesn't support this field yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s", owner, repo), nil)
+	if err != nil {
+		return nil, err
+	}
+	// GET /repos/{owner}/{repo}
+	apiObj := &repositoryForkingSettings{}
+	if _, err := c.c.Do(ctx, req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) UpdateRepoForkingSettings(ctx context.Context, owner, repo string, apiObj *repositoryForkingSettings) (*repositoryForkingSettings, error) {
+	// go-github doesn't support this field yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodPatch, fmt.Sprintf("repos/%s/%s", owner, repo), apiObj)
+	if err != nil {
+		return nil, err
+	}
+	// PATCH /repos/{owner}/{repo}
+	respObj := &repositoryForkingSettings{}
+	if _, err := c.c.Do(ctx, req, respObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return respObj, nil
+}
+
+func (c *githubClientImpl) ReplaceRepoTopics(ctx context.Context, owner, repo string, topics []string) ([]string, error) {
+	// PUT /repos/{owner}/{repo}/topics
+	apiObj, _, err := c.c.Repositories.ReplaceAllTopics(ctx, owner, repo, topics)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) GetRepoFileContent(ctx context.Context, owner, repo, path string) (string, error) {
+	// GET /repos/{owner}/{repo}/contents/{path}
+	apiObj, _, _, err := c.c.Repositories.GetContents(ctx, owner, repo, path, nil)
+	if err != nil {
+		return "", handleHTTPError(err)
+	}
+	// A directory has no file content
+	if apiObj == nil {
+		return "", fmt.Errorf("%q is not a file: %w", path, gitprovider.ErrNotFound)
+	}
+	return apiObj.GetContent()
+}
+
+func (c *githubClientImpl) GetCommunityHealthMetrics(ctx context.Context, owner, repo string) (*github.CommunityHealthMetrics, error) {
+	// GET /repos/{owner}/{repo}/community/profile
+	apiObj, _, err := c.c.Repositories.GetCommunityHealthMetrics(ctx, owner, repo)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateCommunityHealthMetricsAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) GetRepoSubscription(ctx context.Context, owner, repo string) (*github.Subscription, error) {
+	// GET /repos/{owner}/{repo}/subscription
+	apiObj, _, err := c.c.Activity.GetRepositorySubscription(ctx, owner, repo)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) SetRepoSubscription(ctx context.Context, owner, repo string, req *github.Subscription) error {
+	// PUT /repos/{owner}/{repo}/subscription
+	_, _, err := c.c.Activity.SetRepositorySubscription(ctx, owner, repo, req)
+	return handleHTTPError(err)
+}
+
+func (c *githubClientImpl) DeleteRepoSubscription(ctx context.Context, owner, repo string) error {
+	// DELETE /repos/{owner}/{repo}/subscription
+	_, err := c.c.Activity.DeleteRepositorySubscription(ctx, owner, repo)
+	return handleHTTPError(err)
+}
+
 func validateRepositoryAPIResp(apiObj *github.Repository, err error) (*github.Repository, error) {
 	// If the response contained an error, return
 	if err != nil {
@@ -207,11 +571,14 @@ func validateRepositoryAPIResp(apiObj *github.Repository, err error) (*github.Re
 }
 
 func (c *githubClientImpl) ListOrgRepos(ctx context.Context, org string) ([]*github.Repository, error) {
+	return c.ListOrgReposOfType(ctx, org, "")
+}
+
+func (c *githubClientImpl) ListOrgReposOfType(ctx context.Context, org, repoType string) ([]*github.Repository, error) {
 	var apiObjs []*github.Repository
-	opts := &github.RepositoryListByOrgOptions{}
+	opts := &github.RepositoryListByOrgOptions{Type: repoType}
 	err := allPages(&opts.ListOptions, func() (*github.Response, error) {
-		// GET /orgs/{org}/repos
-		pageObjs, resp, listErr := c.c.Repositories.ListByOrg(ctx, org, opts)
+		pageObjs, resp, listErr := c.listOrgReposPage(ctx, org, opts)
 		apiObjs = append(apiObjs, pageObjs...)
 		return resp, listErr
 	})
@@ -221,6 +588,28 @@ func (c *githubClientImpl) ListOrgRepos(ctx context.Context, org string) ([]*git
 	return validateRepositoryObjects(apiObjs)
 }
 
+func (c *githubClientImpl) ListOrgReposPage(ctx context.Context, org string, opts gitprovider.PageOptions, sort, direction string) ([]*github.Repository, gitprovider.PageInfo, error) {
+	listOpts := &github.RepositoryListByOrgOptions{
+		Sort:        sort,
+		Direction:   direction,
+		ListOptions: pageListOptions(opts),
+	}
+	apiObjs, resp, err := c.listOrgReposPage(ctx, org, listOpts)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, handleHTTPError(err)
+	}
+	apiObjs, err = validateRepositoryObjects(apiObjs)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	return apiObjs, pageInfoFromResponse(listOpts.Page, resp), nil
+}
+
+func (c *githubClientImpl) listOrgReposPage(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error) {
+	// GET /orgs/{org}/repos
+	return c.c.Repositories.ListByOrg(ctx, org, opts)
+}
+
 func validateRepositoryObjects(apiObjs []*github.Repository) ([]*github.Repository, error) {
 	for _, apiObj := range apiObjs {
 		// Make sure apiObj is valid
@@ -235,8 +624,7 @@ func (c *githubClientImpl) ListUserRepos(ctx context.Context, username string) (
 	var apiObjs []*github.Repository
 	opts := &github.RepositoryListOptions{}
 	err := allPages(&opts.ListOptions, func() (*github.Response, error) {
-		// GET /users/{username}/repos
-		pageObjs, resp, listErr := c.c.Repositories.List(ctx, username, opts)
+		pageObjs, resp, listErr := c.listUserReposPage(ctx, username, opts)
 		apiObjs = append(apiObjs, pageObjs...)
 		return resp, listErr
 	})
@@ -246,6 +634,24 @@ func (c *githubClientImpl) ListUserRepos(ctx context.Context, username string) (
 	return validateRepositoryObjects(apiObjs)
 }
 
+func (c *githubClientImpl) ListUserReposPage(ctx context.Context, username string, opts gitprovider.PageOptions) ([]*github.Repository, gitprovider.PageInfo, error) {
+	listOpts := &github.RepositoryListOptions{ListOptions: pageListOptions(opts)}
+	apiObjs, resp, err := c.listUserReposPage(ctx, username, listOpts)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, handleHTTPError(err)
+	}
+	apiObjs, err = validateRepositoryObjects(apiObjs)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	return apiObjs, pageInfoFromResponse(listOpts.Page, resp), nil
+}
+
+func (c *githubClientImpl) listUserReposPage(ctx context.Context, username string, opts *github.RepositoryListOptions) ([]*github.Repository, *github.Response, error) {
+	// GET /users/{username}/repos
+	return c.c.Repositories.List(ctx, username, opts)
+}
+
 func (c *githubClientImpl) CreateRepo(ctx context.Context, orgName string, req *github.Repository) (*github.Repository, error) {
 	// POST /user/repos (if orgName == "")
 	// POST /orgs/{org}/repos (if orgName != "")
@@ -253,6 +659,40 @@ func (c *githubClientImpl) CreateRepo(ctx context.Context, orgName string, req *
 	return validateRepositoryAPIResp(apiObj, err)
 }
 
+func (c *githubClientImpl) CreateRepoFromTemplate(ctx context.Context, templateOwner, templateRepo string, req *github.TemplateRepoRequest) (*github.Repository, error) {
+	// POST /repos/{template_owner}/{template_repo}/generate
+	apiObj, _, err := c.c.Repositories.CreateFromTemplate(ctx, templateOwner, templateRepo, req)
+	return validateRepositoryAPIResp(apiObj, err)
+}
+
+func (c *githubClientImpl) CreateFork(ctx context.Context, owner, repo, orgName string) (*github.Repository, error) {
+	// POST /repos/{owner}/{repo}/forks
+	apiObj, _, err := c.c.Repositories.CreateFork(ctx, owner, repo, &github.RepositoryCreateForkOptions{
+		Organization: orgName,
+	})
+	// The fork is created in the background, the pending fork is returned along with the error
+	var acceptedErr *github.AcceptedError
+	if errors.As(err, &acceptedErr) {
+		err = nil
+	}
+	return validateRepositoryAPIResp(apiObj, err)
+}
+
+func (c *githubClientImpl) TransferRepo(ctx context.Context, owner, repo, newOwner string) error {
+	// Don't allow transferring repositories if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot transfer repository: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// POST /repos/{owner}/{repo}/transfer
+	_, _, err := c.c.Repositories.Transfer(ctx, owner, repo, github.TransferRequest{NewOwner: newOwner})
+	// The repository is transferred in the background
+	var acceptedErr *github.AcceptedError
+	if errors.As(err, &acceptedErr) {
+		err = nil
+	}
+	return handleHTTPError(err)
+}
+
 func (c *githubClientImpl) UpdateRepo(ctx context.Context, owner, repo string, req *github.Repository) (*github.Repository, error) {
 	// PATCH /repos/{owner}/{repo}
 	apiObj, _, err := c.c.Repositories.Edit(ctx, owner, repo, req)
@@ -290,6 +730,30 @@ func (c *githubClientImpl) ListKeys(ctx context.Context, owner, repo string) ([]
 	return apiObjs, nil
 }
 
+func (c *githubClientImpl) GetKeyByTitle(ctx context.Context, owner, repo, title string) (*github.Key, error) {
+	opts := &github.ListOptions{}
+	for {
+		// GET /repos/{owner}/{repo}/keys
+		pageObjs, resp, err := c.c.Repositories.ListKeys(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, handleHTTPError(err)
+		}
+		for _, apiObj := range pageObjs {
+			if err := validateDeployKeyAPI(apiObj); err != nil {
+				return nil, err
+			}
+			// Don't request more pages once the key is found
+			if *apiObj.Title == title {
+				return apiObj, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, gitprovider.ErrNotFound
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
 func (c *githubClientImpl) CreateKey(ctx context.Context, owner, repo string, req *github.Key) (*github.Key, error) {
 	// POST /repos/{owner}/{repo}/keys
 	apiObj, _, err := c.c.Repositories.CreateKey(ctx, owner, repo, req)
@@ -308,6 +772,61 @@ func (c *githubClientImpl) DeleteKey(ctx context.Context, owner, repo string, id
 	return handleHTTPError(err)
 }
 
+func (c *githubClientImpl) ListHooks(ctx context.Context, owner, repo string) ([]*github.Hook, error) {
+	apiObjs := []*github.Hook{}
+	opts := &github.ListOptions{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/hooks
+		pageObjs, resp, listErr := c.c.Repositories.ListHooks(ctx, owner, repo, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateRepositoryHookAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) CreateHook(ctx context.Context, owner, repo string, req *github.Hook) (*github.Hook, error) {
+	// POST /repos/{owner}/{repo}/hooks
+	apiObj, _, err := c.c.Repositories.CreateHook(ctx, owner, repo, req)
+	return validateRepositoryHookAPIResp(apiObj, err)
+}
+
+func (c *githubClientImpl) EditHook(ctx context.Context, owner, repo string, id int64, req *github.Hook) (*github.Hook, error) {
+	// PATCH /repos/{owner}/{repo}/hooks/{hook_id}
+	apiObj, _, err := c.c.Repositories.EditHook(ctx, owner, repo, id, req)
+	return validateRepositoryHookAPIResp(apiObj, err)
+}
+
+func validateRepositoryHookAPIResp(apiObj *github.Hook, err error) (*github.Hook, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateRepositoryHookAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) DeleteHook(ctx context.Context, owner, repo string, id int64) error {
+	// Don't allow deleting webhooks if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete webhook: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /repos/{owner}/{repo}/hooks/{hook_id}
+	_, err := c.c.Repositories.DeleteHook(ctx, owner, repo, id)
+	return handleHTTPError(err)
+}
+
 func (c *githubClientImpl) GetTeamPermissions(ctx context.Context, orgName, repo, teamName string) (map[string]bool, error) {
 	// GET /orgs/{org}/teams/{team_slug}/repos/{owner}/{repo}
 	apiObj, _, err := c.c.Teams.IsTeamRepoBySlug(ctx, orgName, teamName, orgName, repo)
@@ -357,3 +876,674 @@ func (c *githubClientImpl) RemoveTeam(ctx context.Context, orgName, repo, teamNa
 	_, err := c.c.Teams.RemoveTeamRepoBySlug(ctx, orgName, teamName, orgName, repo)
 	return handleHTTPError(err)
 }
+
+func (c *githubClientImpl) ListRepoSecurityAdvisories(ctx context.Context, owner, repo, state string) ([]*securityAdvisory, error) {
+	apiObjs := []*securityAdvisory{}
+	err := allCursorPages("", func(after string) (*github.Response, error) {
+		// go-github doesn't support this endpoint yet, hence construct the request manually
+		query := url.Values{}
+		if state != "" {
+			query.Set("state", state)
+		}
+		if after != "" {
+			query.Set("after", after)
+		}
+		u := fmt.Sprintf("repos/%s/%s/security-advisories?%s", owner, repo, query.Encode())
+		req, err := c.c.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		// GET /repos/{owner}/{repo}/security-advisories
+		var pageObjs []*securityAdvisory
+		resp, listErr := c.c.Do(ctx, req, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateSecurityAdvisoryAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) CountSearchIssues(ctx context.Context, query string) (int64, error) {
+	// GET /search/issues
+	result, _, err := c.c.Search.Issues(ctx, query, &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 1}})
+	if err != nil {
+		return 0, handleHTTPError(err)
+	}
+	if result.Total == nil {
+		return 0, fmt.Errorf("search result is missing the total count: %w", gitprovider.ErrInvalidServerData)
+	}
+	return int64(*result.Total), nil
+}
+
+func (c *githubClientImpl) ListRepoInstallations(ctx context.Context, owner, repo string) ([]*installation, error) {
+	apiObjs := []*installation{}
+	opts := &github.ListOptions{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// go-github's Installation struct lacks the app slug, hence construct the request manually
+		u := "user/installations"
+		if opts.Page != 0 {
+			u = fmt.Sprintf("%s?page=%d", u, opts.Page)
+		}
+		req, err := c.c.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		// GET /user/installations
+		var page struct {
+			Installations []*installation `json:"installations"`
+		}
+		resp, listErr := c.c.Do(ctx, req, &page)
+		apiObjs = append(apiObjs, page.Installations...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, withInsufficientScope(err)
+	}
+
+	installations := make([]*installation, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		if err := validateInstallationAPI(apiObj); err != nil {
+			return nil, err
+		}
+		hasAccess, err := c.installationHasAccess(ctx, apiObj, owner, repo)
+		if err != nil {
+			return nil, err
+		}
+		if hasAccess {
+			installations = append(installations, apiObj)
+		}
+	}
+	return installations, nil
+}
+
+// installationHasAccess returns true if the (validated) installation has access to the given repository.
+func (c *githubClientImpl) installationHasAccess(ctx context.Context, apiObj *installation, owner, repo string) (bool, error) {
+	if !strings.EqualFold(*apiObj.Account.Login, owner) {
+		return false, nil
+	}
+	if *apiObj.RepositorySelection == repositorySelectionAll {
+		return true, nil
+	}
+	hasAccess := false
+	opts := &github.ListOptions{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// GET /user/installations/{installation_id}/repositories
+		pageObjs, resp, listErr := c.c.Apps.ListUserRepos(ctx, *apiObj.ID, opts)
+		for _, pageObj := range pageObjs {
+			if pageObj.Name != nil && strings.EqualFold(*pageObj.Name, repo) {
+				hasAccess = true
+			}
+		}
+		return resp, listErr
+	})
+	if err != nil {
+		return false, withInsufficientScope(err)
+	}
+	return hasAccess, nil
+}
+
+func (c *githubClientImpl) ListRepoLanguages(ctx context.Context, owner, repo string) (map[string]int, error) {
+	// GET /repos/{owner}/{repo}/languages
+	languages, _, err := c.c.Repositories.ListLanguages(ctx, owner, repo)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return languages, nil
+}
+
+func (c *githubClientImpl) RepoHasBranches(ctx context.Context, owner, repo string) (bool, error) {
+	opts := &github.BranchListOptions{ListOptions: github.ListOptions{PerPage: 1}}
+	// GET /repos/{owner}/{repo}/branches
+	branches, _, err := c.c.Repositories.ListBranches(ctx, owner, repo, opts)
+	if err != nil {
+		return false, handleHTTPError(err)
+	}
+	return len(branches) != 0, nil
+}
+
+func (c *githubClientImpl) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Protection, error) {
+	// GET /repos/{owner}/{repo}/branches/{branch}/protection
+	apiObj, _, err := c.c.Repositories.GetBranchProtection(ctx, owner, repo, branch)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) UpdateBranchProtection(ctx context.Context, owner, repo, branch string, req *github.ProtectionRequest) (*github.Protection, error) {
+	// PUT /repos/{owner}/{repo}/branches/{branch}/protection
+	apiObj, _, err := c.c.Repositories.UpdateBranchProtection(ctx, owner, repo, branch, req)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) RemoveBranchProtection(ctx context.Context, owner, repo, branch string) error {
+	// Don't allow removing branch protection if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot remove branch protection: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /repos/{owner}/{repo}/branches/{branch}/protection
+	_, err := c.c.Repositories.RemoveBranchProtection(ctx, owner, repo, branch)
+	return handleHTTPError(err)
+}
+
+func (c *githubClientImpl) GetRepoRulesetByName(ctx context.Context, owner, repo, name string) (*ruleset, error) {
+	var rulesetID *int64
+	opts := &github.ListOptions{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// go-github doesn't support this endpoint yet, hence construct the request manually
+		u := fmt.Sprintf("repos/%s/%s/rulesets", owner, repo)
+		if opts.Page != 0 {
+			u = fmt.Sprintf("%s?page=%d", u, opts.Page)
+		}
+		req, err := c.c.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		// GET /repos/{owner}/{repo}/rulesets
+		var pageObjs []*ruleset
+		resp, listErr := c.c.Do(ctx, req, &pageObjs)
+		for _, pageObj := range pageObjs {
+			if err := validateRulesetAPI(pageObj); err != nil {
+				return nil, err
+			}
+			if *pageObj.Name == name {
+				rulesetID = pageObj.ID
+			}
+		}
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rulesetID == nil {
+		return nil, gitprovider.ErrNotFound
+	}
+
+	req, err := c.c.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/rulesets/%d", owner, repo, *rulesetID), nil)
+	if err != nil {
+		return nil, err
+	}
+	// GET /repos/{owner}/{repo}/rulesets/{ruleset_id}
+	return c.doRuleset(ctx, req)
+}
+
+func (c *githubClientImpl) CreateRepoRuleset(ctx context.Context, owner, repo string, apiObj *ruleset) (*ruleset, error) {
+	req, err := c.c.NewRequest(http.MethodPost, fmt.Sprintf("repos/%s/%s/rulesets", owner, repo), apiObj)
+	if err != nil {
+		return nil, err
+	}
+	// POST /repos/{owner}/{repo}/rulesets
+	return c.doRuleset(ctx, req)
+}
+
+func (c *githubClientImpl) UpdateRepoRuleset(ctx context.Context, owner, repo string, apiObj *ruleset) (*ruleset, error) {
+	req, err := c.c.NewRequest(http.MethodPut, fmt.Sprintf("repos/%s/%s/rulesets/%d", owner, repo, *apiObj.ID), apiObj)
+	if err != nil {
+		return nil, err
+	}
+	// PUT /repos/{owner}/{repo}/rulesets/{ruleset_id}
+	return c.doRuleset(ctx, req)
+}
+
+func (c *githubClientImpl) ListRepoEnvironments(ctx context.Context, owner, repo string) ([]string, error) {
+	names := []string{}
+	opts := &github.ListOptions{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// go-github doesn't support this endpoint yet, hence construct the request manually
+		u := fmt.Sprintf("repos/%s/%s/environments", owner, repo)
+		if opts.Page != 0 {
+			u = fmt.Sprintf("%s?page=%d", u, opts.Page)
+		}
+		req, err := c.c.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		// GET /repos/{owner}/{repo}/environments
+		pageObj := &struct {
+			Environments []struct {
+				Name string `json:"name"`
+			} `json:"environments"`
+		}{}
+		resp, listErr := c.c.Do(ctx, req, pageObj)
+		for _, env := range pageObj.Environments {
+			names = append(names, env.Name)
+		}
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// doRuleset sends req, and decodes and validates the ruleset in the response.
+func (c *githubClientImpl) doRuleset(ctx context.Context, req *http.Request) (*ruleset, error) {
+	apiObj := &ruleset{}
+	if _, err := c.c.Do(ctx, req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateRulesetAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) GetOrgPublicKey(ctx context.Context, org string) (*github.PublicKey, error) {
+	// GET /orgs/{org}/actions/secrets/public-key
+	apiObj, _, err := c.c.Actions.GetOrgPublicKey(ctx, org)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validatePublicKeyAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) ListOrgSecrets(ctx context.Context, org string) ([]*github.Secret, error) {
+	apiObjs := []*github.Secret{}
+	opts := &github.ListOptions{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// GET /orgs/{org}/actions/secrets
+		pageObjs, resp, listErr := c.c.Actions.ListOrgSecrets(ctx, org, opts)
+		if pageObjs != nil {
+			apiObjs = append(apiObjs, pageObjs.Secrets...)
+		}
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateSecretAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) CreateOrUpdateOrgSecret(ctx context.Context, org string, req *github.EncryptedSecret) error {
+	// PUT /orgs/{org}/actions/secrets/{secret_name}
+	_, err := c.c.Actions.CreateOrUpdateOrgSecret(ctx, org, req)
+	return handleHTTPError(err)
+}
+
+func (c *githubClientImpl) DeleteOrgSecret(ctx context.Context, org, name string) error {
+	// DELETE /orgs/{org}/actions/secrets/{secret_name}
+	_, err := c.c.Actions.DeleteOrgSecret(ctx, org, name)
+	return handleHTTPError(err)
+}
+
+func (c *githubClientImpl) ListSelectedReposForOrgSecret(ctx context.Context, org, name string) ([]*github.Repository, error) {
+	// GET /orgs/{org}/actions/secrets/{secret_name}/repositories
+	apiObj, _, err := c.c.Actions.ListSelectedReposForOrgSecret(ctx, org, name)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return validateRepositoryObjects(apiObj.Repositories)
+}
+
+func (c *githubClientImpl) SetSelectedReposForOrgSecret(ctx context.Context, org, name string, repoIDs []int64) error {
+	// PUT /orgs/{org}/actions/secrets/{secret_name}/repositories
+	_, err := c.c.Actions.SetSelectedReposForOrgSecret(ctx, org, name, github.SelectedRepoIDs(repoIDs))
+	return handleHTTPError(err)
+}
+
+func (c *githubClientImpl) StreamOrgAuditLog(ctx context.Context, org, phrase, after string, fn func(apiObj *auditEvent, cursor string) error) error {
+	err := allCursorPages(after, func(after string) (*github.Response, error) {
+		// Stop between pages if the context was cancelled
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// go-github doesn't support this endpoint yet, hence construct the request manually
+		query := url.Values{}
+		query.Set("order", "asc")
+		if phrase != "" {
+			query.Set("phrase", phrase)
+		}
+		if after != "" {
+			query.Set("after", after)
+		}
+		u := fmt.Sprintf("orgs/%s/audit-log?%s", org, query.Encode())
+		req, err := c.c.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		// GET /orgs/{org}/audit-log
+		var pageObjs []*auditEvent
+		resp, err := c.c.Do(ctx, req, &pageObjs)
+		if err != nil {
+			return nil, err
+		}
+		for _, apiObj := range pageObjs {
+			if err := validateAuditEventAPI(apiObj); err != nil {
+				return nil, err
+			}
+			if err := fn(apiObj, after); err != nil {
+				return nil, err
+			}
+		}
+		return resp, nil
+	})
+	return withInsufficientScope(err)
+}
+
+func (c *githubClientImpl) GetLatestRelease(ctx context.Context, owner, repo string) (*github.RepositoryRelease, error) {
+	// GET /repos/{owner}/{repo}/releases/latest
+	apiObj, _, err := c.c.Repositories.GetLatestRelease(ctx, owner, repo)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateReleaseAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) GetReleaseByTag(ctx context.Context, owner, repo, tag string) (*github.RepositoryRelease, error) {
+	// GET /repos/{owner}/{repo}/releases/tags/{tag}
+	apiObj, _, err := c.c.Repositories.GetReleaseByTag(ctx, owner, repo, tag)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateReleaseAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) ListReleases(ctx context.Context, owner, repo string) ([]*github.RepositoryRelease, error) {
+	apiObjs := []*github.RepositoryRelease{}
+	opts := &github.ListOptions{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/releases
+		pageObjs, resp, listErr := c.c.Repositories.ListReleases(ctx, owner, repo, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, apiObj := range apiObjs {
+		if err := validateReleaseAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) CreateRelease(ctx context.Context, owner, repo string, req *github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	// POST /repos/{owner}/{repo}/releases
+	apiObj, _, err := c.c.Repositories.CreateRelease(ctx, owner, repo, req)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateReleaseAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) ListTags(ctx context.Context, owner, repo string) ([]*github.RepositoryTag, error) {
+	apiObjs := []*github.RepositoryTag{}
+	opts := &github.ListOptions{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/tags
+		pageObjs, resp, listErr := c.c.Repositories.ListTags(ctx, owner, repo, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, apiObj := range apiObjs {
+		if err := validateTagAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) ListBranches(ctx context.Context, owner, repo string) ([]*github.Branch, error) {
+	apiObjs := []*github.Branch{}
+	opts := &github.BranchListOptions{}
+	err := allPages(&opts.ListOptions, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/branches
+		pageObjs, resp, listErr := c.c.Repositories.ListBranches(ctx, owner, repo, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateBranchAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) GetBranch(ctx context.Context, owner, repo, branch string) (*github.Branch, error) {
+	// GET /repos/{owner}/{repo}/branches/{branch}
+	apiObj, _, err := c.c.Repositories.GetBranch(ctx, owner, repo, branch)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateBranchAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) CompareCommits(ctx context.Context, owner, repo, base, head string) (*github.CommitsComparison, error) {
+	// GET /repos/{owner}/{repo}/compare/{base}...{head}
+	apiObj, _, err := c.c.Repositories.CompareCommits(ctx, owner, repo, base, head)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateCommitsComparisonAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) CreateRef(ctx context.Context, owner, repo string, req *github.Reference) (*github.Reference, error) {
+	// POST /repos/{owner}/{repo}/git/refs
+	apiObj, _, err := c.c.Git.CreateRef(ctx, owner, repo, req)
+	return validateReferenceAPIResp(apiObj, err)
+}
+
+func (c *githubClientImpl) DeleteRef(ctx context.Context, owner, repo, ref string) error {
+	// Don't allow deleting refs if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete ref: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /repos/{owner}/{repo}/git/refs/{ref}
+	_, err := c.c.Git.DeleteRef(ctx, owner, repo, ref)
+	return handleHTTPError(err)
+}
+
+func (c *githubClientImpl) GetRef(ctx context.Context, owner, repo, ref string) (*github.Reference, error) {
+	// GET /repos/{owner}/{repo}/git/ref/{ref}
+	apiObj, _, err := c.c.Git.GetRef(ctx, owner, repo, ref)
+	return validateReferenceAPIResp(apiObj, err)
+}
+
+func (c *githubClientImpl) UpdateRef(ctx context.Context, owner, repo string, req *github.Reference) (*github.Reference, error) {
+	// PATCH /repos/{owner}/{repo}/git/refs/{ref}
+	apiObj, _, err := c.c.Git.UpdateRef(ctx, owner, repo, req, false)
+	return validateReferenceAPIResp(apiObj, err)
+}
+
+func validateReferenceAPIResp(apiObj *github.Reference, err error) (*github.Reference, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateGitObjectAPI("GitHub.Reference", apiObj.GetObject().GetSHA()); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) GetCommit(ctx context.Context, owner, repo, sha string) (*github.Commit, error) {
+	// GET /repos/{owner}/{repo}/git/commits/{commit_sha}
+	apiObj, _, err := c.c.Git.GetCommit(ctx, owner, repo, sha)
+	return validateCommitAPIResp(apiObj, err)
+}
+
+func (c *githubClientImpl) CreateCommit(ctx context.Context, owner, repo string, req *github.Commit) (*github.Commit, error) {
+	// POST /repos/{owner}/{repo}/git/commits
+	apiObj, _, err := c.c.Git.CreateCommit(ctx, owner, repo, req)
+	return validateCommitAPIResp(apiObj, err)
+}
+
+func validateCommitAPIResp(apiObj *github.Commit, err error) (*github.Commit, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateCommitAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) CreateBlob(ctx context.Context, owner, repo string, req *github.Blob) (*github.Blob, error) {
+	// POST /repos/{owner}/{repo}/git/blobs
+	apiObj, _, err := c.c.Git.CreateBlob(ctx, owner, repo, req)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateGitObjectAPI("GitHub.Blob", apiObj.GetSHA()); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) CreateTree(ctx context.Context, owner, repo, baseTree string, entries []*github.TreeEntry) (*github.Tree, error) {
+	// POST /repos/{owner}/{repo}/git/trees
+	apiObj, _, err := c.c.Git.CreateTree(ctx, owner, repo, baseTree, entries)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateGitObjectAPI("GitHub.Tree", apiObj.GetSHA()); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) ListCommitsPage(ctx context.Context, owner, repo, branch string, perPage, page int) ([]*github.RepositoryCommit, gitprovider.PageInfo, error) {
+	opts := &github.CommitsListOptions{
+		SHA:         branch,
+		ListOptions: github.ListOptions{PerPage: perPage, Page: page},
+	}
+	// GET /repos/{owner}/{repo}/commits
+	apiObjs, resp, err := c.c.Repositories.ListCommits(ctx, owner, repo, opts)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, handleHTTPError(err)
+	}
+	for _, apiObj := range apiObjs {
+		if err := validateRepositoryCommitAPI(apiObj); err != nil {
+			return nil, gitprovider.PageInfo{}, err
+		}
+	}
+	return apiObjs, pageInfoFromResponse(page, resp), nil
+}
+
+func (c *githubClientImpl) CreateIssue(ctx context.Context, owner, repo string, req *github.IssueRequest) (*github.Issue, error) {
+	// POST /repos/{owner}/{repo}/issues
+	apiObj, _, err := c.c.Issues.Create(ctx, owner, repo, req)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateIssueAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) ListIssues(ctx context.Context, owner, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, error) {
+	apiObjs := []*github.Issue{}
+	err := allPages(&opts.ListOptions, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/issues
+		pageObjs, resp, listErr := c.c.Issues.ListByRepo(ctx, owner, repo, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, apiObj := range apiObjs {
+		if err := validateIssueAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) ListOrgPATRequests(ctx context.Context, org string) ([]*patRequest, error) {
+	apiObjs := []*patRequest{}
+	opts := &github.ListOptions{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// go-github doesn't support this endpoint yet, hence construct the request manually
+		u := fmt.Sprintf("orgs/%s/personal-access-token-requests", org)
+		if opts.Page != 0 {
+			u = fmt.Sprintf("%s?page=%d", u, opts.Page)
+		}
+		req, err := c.c.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		// GET /orgs/{org}/personal-access-token-requests
+		var pageObjs []*patRequest
+		resp, listErr := c.c.Do(ctx, req, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, withInsufficientScope(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validatePATRequestAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) ReviewOrgPATRequest(ctx context.Context, org string, id int64, action string) error {
+	// Denying revokes the token's access, hence don't allow it if the user didn't explicitly allow
+	// dangerous API calls.
+	if action == patRequestActionDeny && !c.destructiveActions {
+		return fmt.Errorf("cannot deny personal access token request: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// go-github doesn't support this endpoint yet, hence construct the request manually
+	u := fmt.Sprintf("orgs/%s/personal-access-token-requests/%d", org, id)
+	req, err := c.c.NewRequest(http.MethodPost, u, &patRequestReview{Action: action})
+	if err != nil {
+		return err
+	}
+	// POST /orgs/{org}/personal-access-token-requests/{pat_request_id}
+	_, err = c.c.Do(ctx, req, nil)
+	return withInsufficientScope(handleHTTPError(err))
+}
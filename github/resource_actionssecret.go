@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/google/go-github/v32/github"
+	"golang.org/x/crypto/nacl/box"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// publicKeySize is the size of the Curve25519 public keys GitHub uses for sealing secrets.
+const publicKeySize = 32
+
+// validatePublicKeyAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validatePublicKeyAPI(apiObj *github.PublicKey) error {
+	return validateAPIObject("GitHub.PublicKey", func(validator validation.Validator) {
+		if apiObj.KeyID == nil {
+			validator.Required("KeyID")
+		}
+		if apiObj.Key == nil {
+			validator.Required("Key")
+		}
+	})
+}
+
+// validateSecretAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateSecretAPI(apiObj *github.Secret) error {
+	return validateAPIObject("GitHub.Secret", func(validator validation.Validator) {
+		if apiObj.Name == "" {
+			validator.Required("Name")
+		}
+		// Make sure visibility is valid if set
+		if apiObj.Visibility != "" {
+			v := gitprovider.ActionsSecretVisibility(apiObj.Visibility)
+			validator.Append(gitprovider.ValidateActionsSecretVisibility(v), v, "Visibility")
+		}
+	})
+}
+
+func actionsSecretFromAPI(apiObj *github.Secret) gitprovider.ActionsSecretInfo {
+	info := gitprovider.ActionsSecretInfo{
+		Name: apiObj.Name,
+	}
+	if apiObj.Visibility != "" {
+		info.Visibility = gitprovider.ActionsSecretVisibilityVar(gitprovider.ActionsSecretVisibility(apiObj.Visibility))
+	}
+	return info
+}
+
+// sealSecret encrypts value with the given (validated) public key, as required by GitHub.
+// The result is base64-encoded, ready to be sent to the server.
+func sealSecret(value string, apiObj *github.PublicKey) (string, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(*apiObj.Key)
+	if err != nil || len(keyBytes) != publicKeySize {
+		return "", fmt.Errorf("invalid public key %q: %w", *apiObj.KeyID, gitprovider.ErrInvalidServerData)
+	}
+	var publicKey [publicKeySize]byte
+	copy(publicKey[:], keyBytes)
+
+	sealed, err := box.SealAnonymous(nil, []byte(value), &publicKey, rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
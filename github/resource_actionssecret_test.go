@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+	"golang.org/x/crypto/nacl/box"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+func Test_sealSecret(t *testing.T) {
+	recipientPublic, recipientPrivate, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		key          string
+		value        string
+		expectedErrs []error
+	}{
+		{
+			name:  "valid key",
+			key:   base64.StdEncoding.EncodeToString(recipientPublic[:]),
+			value: "s3cr3t",
+		},
+		{
+			name:  "empty value",
+			key:   base64.StdEncoding.EncodeToString(recipientPublic[:]),
+			value: "",
+		},
+		{
+			name:         "key not base64",
+			key:          "not base64!",
+			expectedErrs: []error{gitprovider.ErrInvalidServerData},
+		},
+		{
+			name:         "key too short",
+			key:          base64.StdEncoding.EncodeToString(recipientPublic[:16]),
+			expectedErrs: []error{gitprovider.ErrInvalidServerData},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sealed, err := sealSecret(tt.value, &github.PublicKey{KeyID: github.String("1"), Key: &tt.key})
+			validation.TestExpectErrors(t, "sealSecret", err, tt.expectedErrs...)
+			if err != nil {
+				return
+			}
+			// Open the sealed box like libsodium's crypto_box_seal_open would
+			sealedBytes, err := base64.StdEncoding.DecodeString(sealed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opened, ok := box.OpenAnonymous(nil, sealedBytes, recipientPublic, recipientPrivate)
+			if !ok {
+				t.Fatal("sealSecret() produced a box that couldn't be opened")
+			}
+			if string(opened) != tt.value {
+				t.Errorf("sealSecret() opened = %q, want %q", opened, tt.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"time"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+const (
+	// auditLogTimeFormat is the time format used in the "created" qualifier of audit log search phrases.
+	auditLogTimeFormat = "2006-01-02T15:04:05Z"
+)
+
+// auditEvent is the subset of an audit log event, as returned from "GET /orgs/{org}/audit-log",
+// that we care about. go-github doesn't provide a struct for it (yet).
+type auditEvent struct {
+	DocumentID *string `json:"_document_id,omitempty"`
+	Action     *string `json:"action,omitempty"`
+	Actor      *string `json:"actor,omitempty"`
+	// Timestamp is the amount of milliseconds since the Unix epoch.
+	Timestamp *int64 `json:"@timestamp,omitempty"`
+}
+
+// validateAuditEventAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateAuditEventAPI(apiObj *auditEvent) error {
+	return validateAPIObject("GitHub.AuditEvent", func(validator validation.Validator) {
+		if apiObj.DocumentID == nil {
+			validator.Required("DocumentID")
+		}
+		if apiObj.Action == nil {
+			validator.Required("Action")
+		}
+		if apiObj.Timestamp == nil {
+			validator.Required("Timestamp")
+		}
+	})
+}
+
+func auditEventFromAPI(apiObj *auditEvent, cursor string) gitprovider.AuditEvent {
+	event := gitprovider.AuditEvent{
+		ID:        *apiObj.DocumentID,
+		Action:    *apiObj.Action,
+		CreatedAt: time.Unix(0, *apiObj.Timestamp*int64(time.Millisecond)).UTC(),
+		Cursor:    cursor,
+	}
+	if apiObj.Actor != nil {
+		event.Actor = *apiObj.Actor
+	}
+	return event
+}
+
+// auditLogPhrase returns the audit log search phrase for events created at or after since,
+// or an empty string if since is the zero time.
+func auditLogPhrase(since time.Time) string {
+	if since.IsZero() {
+		return ""
+	}
+	return "created:>=" + since.UTC().Format(auditLogTimeFormat)
+}
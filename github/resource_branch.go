@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+func newBranch(ctx *clientContext, apiObj *github.Branch, ref gitprovider.RepositoryRef) *branch {
+	return &branch{
+		clientContext: ctx,
+		b:             *apiObj,
+		ref:           ref,
+	}
+}
+
+var _ gitprovider.Branch = &branch{}
+
+type branch struct {
+	*clientContext
+
+	b   github.Branch
+	ref gitprovider.RepositoryRef
+}
+
+func (b *branch) Get() gitprovider.BranchInfo {
+	return branchFromAPI(&b.b)
+}
+
+func (b *branch) APIObject() interface{} {
+	return &b.b
+}
+
+func (b *branch) Repository() gitprovider.RepositoryRef {
+	return b.ref
+}
+
+func branchFromAPI(apiObj *github.Branch) gitprovider.BranchInfo {
+	return gitprovider.BranchInfo{
+		Name:      *apiObj.Name,
+		Sha:       *apiObj.Commit.SHA,
+		Protected: apiObj.GetProtected(),
+	}
+}
+
+// validateBranchAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateBranchAPI(apiObj *github.Branch) error {
+	return validateAPIObject("GitHub.Branch", func(validator validation.Validator) {
+		if apiObj.Name == nil {
+			validator.Required("Name")
+		}
+		if apiObj.Commit == nil || apiObj.Commit.SHA == nil {
+			validator.Required("Commit.SHA")
+		}
+	})
+}
+
+// validateCommitsComparisonAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateCommitsComparisonAPI(apiObj *github.CommitsComparison) error {
+	return validateAPIObject("GitHub.CommitsComparison", func(validator validation.Validator) {
+		if apiObj.AheadBy == nil {
+			validator.Required("AheadBy")
+		}
+	})
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// maxRequiredReviewCount is the maximum amount of approving reviews GitHub can require.
+const maxRequiredReviewCount = 6
+
+func newBranchProtection(c *BranchProtectionClient, branch string, apiObj *github.Protection) *branchProtection {
+	return &branchProtection{
+		branch: branch,
+		p:      *apiObj,
+		c:      c,
+	}
+}
+
+var _ gitprovider.BranchProtection = &branchProtection{}
+
+type branchProtection struct {
+	// branch is kept next to p, as GitHub doesn't return the name of the branch.
+	branch string
+	p      github.Protection
+	c      *BranchProtectionClient
+}
+
+func (bp *branchProtection) Get() gitprovider.BranchProtectionInfo {
+	return branchProtectionFromAPI(bp.branch, &bp.p)
+}
+
+func (bp *branchProtection) Set(info gitprovider.BranchProtectionInfo) error {
+	if err := validateBranchProtectionInfo(info); err != nil {
+		return err
+	}
+	bp.branch = info.Branch
+	branchProtectionInfoToAPIObj(&info, &bp.p)
+	return nil
+}
+
+func (bp *branchProtection) APIObject() interface{} {
+	return &bp.p
+}
+
+func (bp *branchProtection) Repository() gitprovider.RepositoryRef {
+	return bp.c.ref
+}
+
+// Update will apply the desired state in this object to the server.
+// As GitHub replaces the whole protection of the branch, the settings that aren't modelled in
+// gitprovider.BranchProtectionInfo, e.g. dismissing stale reviews, are sent as they are in the
+// API object.
+//
+// ErrNotFound is returned if the branch does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (bp *branchProtection) Update(ctx context.Context) error {
+	// PUT /repos/{owner}/{repo}/branches/{branch}/protection
+	apiObj, err := bp.c.c.UpdateBranchProtection(ctx, bp.c.ref.GetIdentity(), bp.c.ref.GetRepository(), bp.branch, branchProtectionToRequest(&bp.p))
+	if err != nil {
+		return err
+	}
+	bp.p = *apiObj
+	return nil
+}
+
+// Delete removes the protection of the branch. This requires destructive actions to be
+// enabled in the client.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (bp *branchProtection) Delete(ctx context.Context) error {
+	// DELETE /repos/{owner}/{repo}/branches/{branch}/protection
+	return bp.c.c.RemoveBranchProtection(ctx, bp.c.ref.GetIdentity(), bp.c.ref.GetRepository(), bp.branch)
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (bp *branchProtection) Reconcile(ctx context.Context) (bool, error) {
+	actual, err := bp.c.get(ctx, bp.branch)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return true, bp.Update(ctx)
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// If the desired matches the actual state, do nothing
+	if bp.Get().Equals(actual.Get()) {
+		return false, nil
+	}
+	return true, bp.Update(ctx)
+}
+
+// validateBranchProtectionInfo validates info, along with the limits GitHub puts on it.
+func validateBranchProtectionInfo(info gitprovider.BranchProtectionInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	validator := validation.New("BranchProtection")
+	if info.RequiredReviewCount > maxRequiredReviewCount {
+		validator.Invalid(info.RequiredReviewCount, "RequiredReviewCount")
+	}
+	return validator.Error()
+}
+
+func branchProtectionFromAPI(branch string, apiObj *github.Protection) gitprovider.BranchProtectionInfo {
+	info := gitprovider.BranchProtectionInfo{
+		Branch:               branch,
+		RequiredStatusChecks: []string{},
+		EnforceAdmins:        apiObj.EnforceAdmins != nil && apiObj.EnforceAdmins.Enabled,
+		PushUsers:            []string{},
+		PushTeams:            []string{},
+	}
+	if apiObj.RequiredPullRequestReviews != nil {
+		info.RequiredReviewCount = apiObj.RequiredPullRequestReviews.RequiredApprovingReviewCount
+	}
+	if apiObj.RequiredStatusChecks != nil {
+		info.RequiredStatusChecks = append(info.RequiredStatusChecks, apiObj.RequiredStatusChecks.Contexts...)
+	}
+	info.RequireLinearHistory = gitprovider.BoolVar(apiObj.RequireLinearHistory != nil && apiObj.RequireLinearHistory.Enabled)
+	if apiObj.Restrictions != nil {
+		info.RestrictPushes = true
+		for _, user := range apiObj.Restrictions.Users {
+			info.PushUsers = append(info.PushUsers, user.GetLogin())
+		}
+		for _, team := range apiObj.Restrictions.Teams {
+			info.PushTeams = append(info.PushTeams, team.GetSlug())
+		}
+	}
+	info.Default()
+	return info
+}
+
+func branchProtectionToAPI(info *gitprovider.BranchProtectionInfo) *github.Protection {
+	apiObj := &github.Protection{}
+	branchProtectionInfoToAPIObj(info, apiObj)
+	return apiObj
+}
+
+// branchProtectionInfoToAPIObj applies info to apiObj. The settings that aren't modelled in
+// BranchProtectionInfo, e.g. requiring branches to be up to date, are kept as-is.
+func branchProtectionInfoToAPIObj(info *gitprovider.BranchProtectionInfo, apiObj *github.Protection) {
+	// GitHub disables the required reviews and status checks by omitting them
+	if info.RequiredReviewCount > 0 {
+		if apiObj.RequiredPullRequestReviews == nil {
+			apiObj.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcement{}
+		}
+		apiObj.RequiredPullRequestReviews.RequiredApprovingReviewCount = info.RequiredReviewCount
+	} else {
+		apiObj.RequiredPullRequestReviews = nil
+	}
+	if len(info.RequiredStatusChecks) != 0 {
+		if apiObj.RequiredStatusChecks == nil {
+			apiObj.RequiredStatusChecks = &github.RequiredStatusChecks{}
+		}
+		apiObj.RequiredStatusChecks.Contexts = info.RequiredStatusChecks
+	} else {
+		apiObj.RequiredStatusChecks = nil
+	}
+	if apiObj.EnforceAdmins == nil {
+		apiObj.EnforceAdmins = &github.AdminEnforcement{}
+	}
+	apiObj.EnforceAdmins.Enabled = info.EnforceAdmins
+	if info.RequireLinearHistory != nil {
+		apiObj.RequireLinearHistory = &github.RequireLinearHistory{Enabled: *info.RequireLinearHistory}
+	}
+
+	if !info.RestrictPushes {
+		apiObj.Restrictions = nil
+		return
+	}
+	// Apps aren't modelled, keep them as-is
+	restrictions := &github.BranchRestrictions{Users: []*github.User{}, Teams: []*github.Team{}}
+	if apiObj.Restrictions != nil {
+		restrictions.Apps = apiObj.Restrictions.Apps
+	}
+	for _, login := range info.PushUsers {
+		restrictions.Users = append(restrictions.Users, &github.User{Login: gitprovider.StringVar(login)})
+	}
+	for _, slug := range info.PushTeams {
+		restrictions.Teams = append(restrictions.Teams, &github.Team{Slug: gitprovider.StringVar(slug)})
+	}
+	apiObj.Restrictions = restrictions
+}
+
+// branchProtectionToRequest converts the protection as returned from GitHub to the request
+// replacing it, including the settings that aren't modelled in BranchProtectionInfo.
+func branchProtectionToRequest(apiObj *github.Protection) *github.ProtectionRequest {
+	req := &github.ProtectionRequest{
+		RequiredStatusChecks: apiObj.RequiredStatusChecks,
+		EnforceAdmins:        apiObj.EnforceAdmins != nil && apiObj.EnforceAdmins.Enabled,
+	}
+	if reviews := apiObj.RequiredPullRequestReviews; reviews != nil {
+		req.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcementRequest{
+			DismissStaleReviews:          reviews.DismissStaleReviews,
+			RequireCodeOwnerReviews:      reviews.RequireCodeOwnerReviews,
+			RequiredApprovingReviewCount: reviews.RequiredApprovingReviewCount,
+		}
+		if dismissal := reviews.DismissalRestrictions; dismissal != nil {
+			users, teams := userLogins(dismissal.Users), teamSlugs(dismissal.Teams)
+			req.RequiredPullRequestReviews.DismissalRestrictionsRequest = &github.DismissalRestrictionsRequest{
+				Users: &users,
+				Teams: &teams,
+			}
+		}
+	}
+	if restrictions := apiObj.Restrictions; restrictions != nil {
+		req.Restrictions = &github.BranchRestrictionsRequest{
+			Users: userLogins(restrictions.Users),
+			Teams: teamSlugs(restrictions.Teams),
+		}
+		for _, app := range restrictions.Apps {
+			req.Restrictions.Apps = append(req.Restrictions.Apps, app.GetSlug())
+		}
+	}
+	if apiObj.RequireLinearHistory != nil {
+		req.RequireLinearHistory = gitprovider.BoolVar(apiObj.RequireLinearHistory.Enabled)
+	}
+	if apiObj.AllowForcePushes != nil {
+		req.AllowForcePushes = gitprovider.BoolVar(apiObj.AllowForcePushes.Enabled)
+	}
+	if apiObj.AllowDeletions != nil {
+		req.AllowDeletions = gitprovider.BoolVar(apiObj.AllowDeletions.Enabled)
+	}
+	return req
+}
+
+// userLogins returns the logins of the users, which is non-nil as GitHub requires.
+func userLogins(users []*github.User) []string {
+	logins := make([]string, 0, len(users))
+	for _, user := range users {
+		logins = append(logins, user.GetLogin())
+	}
+	return logins
+}
+
+// teamSlugs returns the slugs of the teams, which is non-nil as GitHub requires.
+func teamSlugs(teams []*github.Team) []string {
+	slugs := make([]string, 0, len(teams))
+	for _, team := range teams {
+		slugs = append(slugs, team.GetSlug())
+	}
+	return slugs
+}
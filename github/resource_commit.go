@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+const (
+	// blobModeFile is the tree entry mode of a regular (non-executable) file.
+	blobModeFile = "100644"
+	// treeEntryTypeBlob is the tree entry type of a file.
+	treeEntryTypeBlob = "blob"
+	// blobEncodingUTF8 is the encoding of blob content given as a string.
+	blobEncodingUTF8 = "utf-8"
+)
+
+func newCommit(ctx *clientContext, apiObj *github.Commit, ref gitprovider.RepositoryRef) *commit {
+	return &commit{
+		clientContext: ctx,
+		c:             *apiObj,
+		ref:           ref,
+	}
+}
+
+var _ gitprovider.Commit = &commit{}
+
+type commit struct {
+	*clientContext
+
+	c   github.Commit
+	ref gitprovider.RepositoryRef
+}
+
+func (c *commit) Get() gitprovider.CommitInfo {
+	return commitFromAPI(&c.c)
+}
+
+func (c *commit) APIObject() interface{} {
+	return &c.c
+}
+
+func (c *commit) Repository() gitprovider.RepositoryRef {
+	return c.ref
+}
+
+func commitFromAPI(apiObj *github.Commit) gitprovider.CommitInfo {
+	info := gitprovider.CommitInfo{
+		Sha:       apiObj.GetSHA(),
+		Author:    apiObj.GetAuthor().GetName(),
+		Message:   apiObj.GetMessage(),
+		CreatedAt: apiObj.GetAuthor().GetDate(),
+		URL:       apiObj.GetHTMLURL(),
+	}
+	if verification := apiObj.Verification; verification != nil {
+		info.Verified = gitprovider.BoolVar(verification.GetVerified())
+		info.VerificationReason = verification.GetReason()
+	}
+	return info
+}
+
+// commitFromRepositoryCommit returns the Git commit of apiObj, along with the SHA and web URL
+// that are only set on the outer object in "GET /repos/{owner}/{repo}/commits" responses.
+func commitFromRepositoryCommit(apiObj *github.RepositoryCommit) *github.Commit {
+	c := *apiObj.Commit
+	c.SHA = apiObj.SHA
+	c.HTMLURL = apiObj.HTMLURL
+	return &c
+}
+
+// commitTreeEntries returns the tree entries changing files, given the SHAs of the blobs created
+// for them. Files without a blob SHA (i.e. nil content) are deleted, as go-github sends a null SHA
+// for entries without SHA and content.
+func commitTreeEntries(files []gitprovider.CommitFile, blobSHAs map[string]string) []*github.TreeEntry {
+	entries := make([]*github.TreeEntry, 0, len(files))
+	for _, file := range files {
+		entry := &github.TreeEntry{
+			Path: github.String(file.Path),
+			Mode: github.String(blobModeFile),
+			Type: github.String(treeEntryTypeBlob),
+		}
+		if sha, ok := blobSHAs[file.Path]; ok {
+			entry.SHA = github.String(sha)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// validateCommitAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateCommitAPI(apiObj *github.Commit) error {
+	return validateAPIObject("GitHub.Commit", func(validator validation.Validator) {
+		if apiObj.SHA == nil {
+			validator.Required("SHA")
+		}
+	})
+}
+
+// validateRepositoryCommitAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateRepositoryCommitAPI(apiObj *github.RepositoryCommit) error {
+	return validateAPIObject("GitHub.RepositoryCommit", func(validator validation.Validator) {
+		if apiObj.SHA == nil {
+			validator.Required("SHA")
+		}
+		if apiObj.Commit == nil {
+			validator.Required("Commit")
+		}
+	})
+}
+
+// validateGitObjectAPI validates that the Git object, e.g. a blob or tree, received from the
+// server has a SHA.
+func validateGitObjectAPI(name, sha string) error {
+	return validateAPIObject(name, func(validator validation.Validator) {
+		if sha == "" {
+			validator.Required("SHA")
+		}
+	})
+}
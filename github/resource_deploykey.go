@@ -20,7 +20,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
 
 	"github.com/google/go-github/v32/github"
 
@@ -101,7 +100,7 @@ func (dk *deployKey) Delete(ctx context.Context) error {
 //
 // The internal API object will be overridden with the received server data if actionTaken == true.
 func (dk *deployKey) Reconcile(ctx context.Context) (bool, error) {
-	actual, err := dk.c.get(ctx, *dk.k.Key)
+	actual, err := dk.c.get(ctx, *dk.k.Title)
 	if err != nil {
 		// Create if not found
 		if errors.Is(err, gitprovider.ErrNotFound) {
@@ -112,15 +111,14 @@ func (dk *deployKey) Reconcile(ctx context.Context) (bool, error) {
 		return false, err
 	}
 
-	// Use wrappers here to extract the "spec" part of the object for comparison
-	desiredSpec := newGithubKeySpec(&dk.k)
-	actualSpec := newGithubKeySpec(&actual.k)
-
-	// If the desired matches the actual state, do nothing
-	if desiredSpec.Equals(actualSpec) {
+	// Compare the "spec" part of the objects, i.e. the name, key material and read-only flag
+	desired := deployKeyFromAPI(&dk.k)
+	desired.Default()
+	if desired.Equals(actual.Get()) {
 		return false, nil
 	}
-	// If desired and actual state mis-match, update
+	// Deploy keys are immutable, hence the key is rotated by deleting and recreating it
+	dk.k.ID = actual.k.ID
 	return true, dk.Update(ctx)
 }
 
@@ -177,25 +175,3 @@ func deployKeyInfoToAPIObj(info *gitprovider.DeployKeyInfo, apiObj *github.Key)
 		apiObj.ReadOnly = info.ReadOnly
 	}
 }
-
-// This function copies over the fields that are part of create request of a deploy
-// i.e. the desired spec of the deploy key. This allows us to separate "spec" from "status" fields.
-func newGithubKeySpec(key *github.Key) *githubKeySpec {
-	return &githubKeySpec{
-		&github.Key{
-			// Create-specific parameters
-			// See: https://docs.github.com/en/rest/reference/repos#create-a-deploy-key
-			Title:    key.Title,
-			Key:      key.Key,
-			ReadOnly: key.ReadOnly,
-		},
-	}
-}
-
-type githubKeySpec struct {
-	*github.Key
-}
-
-func (s *githubKeySpec) Equals(other *githubKeySpec) bool {
-	return reflect.DeepEqual(s, other)
-}
@@ -0,0 +1,251 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+const (
+	// Keys of the webhook configuration, see
+	// https://docs.github.com/en/rest/reference/repos#create-a-repository-webhook
+	hookConfigURL         = "url"
+	hookConfigContentType = "content_type"
+	hookConfigSecret      = "secret"
+)
+
+func newRepositoryHook(c *RepositoryHookClient, hook *github.Hook) *repositoryHook {
+	return &repositoryHook{
+		h: *hook,
+		c: c,
+	}
+}
+
+var _ gitprovider.RepositoryHook = &repositoryHook{}
+
+type repositoryHook struct {
+	h github.Hook
+	c *RepositoryHookClient
+}
+
+func (rh *repositoryHook) Get() gitprovider.RepositoryHookInfo {
+	return repositoryHookFromAPI(&rh.h)
+}
+
+func (rh *repositoryHook) Set(info gitprovider.RepositoryHookInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	repositoryHookInfoToAPIObj(&info, &rh.h)
+	return nil
+}
+
+func (rh *repositoryHook) APIObject() interface{} {
+	return &rh.h
+}
+
+func (rh *repositoryHook) Repository() gitprovider.RepositoryRef {
+	return rh.c.ref
+}
+
+// Update will apply the desired state in this object to the server.
+// Only set fields will be respected (i.e. PATCH behaviour).
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// As GitHub replaces the whole configuration of the webhook, the secret is removed
+// if it isn't set through .Set() again.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (rh *repositoryHook) Update(ctx context.Context) error {
+	// We can use the same webhook ID that we got from the GET calls. Make sure it's non-nil.
+	// This _should never_ happen, but just check for it anyways to avoid panicing.
+	if rh.h.ID == nil {
+		return fmt.Errorf("didn't expect ID to be nil: %w", gitprovider.ErrUnexpectedEvent)
+	}
+
+	// PATCH /repos/{owner}/{repo}/hooks/{hook_id}
+	apiObj, err := rh.c.c.EditHook(ctx, rh.c.ref.GetIdentity(), rh.c.ref.GetRepository(), *rh.h.ID, newGithubHookSpec(&rh.h))
+	if err != nil {
+		return err
+	}
+	rh.h = *apiObj
+	return nil
+}
+
+// Delete deletes the webhook from the repository. This requires destructive actions to be
+// enabled in the client.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (rh *repositoryHook) Delete(ctx context.Context) error {
+	// We can use the same webhook ID that we got from the GET calls. Make sure it's non-nil.
+	// This _should never_ happen, but just check for it anyways to avoid panicing.
+	if rh.h.ID == nil {
+		return fmt.Errorf("didn't expect ID to be nil: %w", gitprovider.ErrUnexpectedEvent)
+	}
+
+	// DELETE /repos/{owner}/{repo}/hooks/{hook_id}
+	return rh.c.c.DeleteHook(ctx, rh.c.ref.GetIdentity(), rh.c.ref.GetRepository(), *rh.h.ID)
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider. The secret isn't compared, as it can't be read.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (rh *repositoryHook) Reconcile(ctx context.Context) (bool, error) {
+	actual, err := rh.c.get(ctx, rh.Get().URL)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return true, rh.createIntoSelf(ctx)
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// If the desired matches the actual state, do nothing
+	if rh.Get().Equals(actual.Get()) {
+		return false, nil
+	}
+	// Update the actual webhook, as the ID of this object might not be set
+	rh.h.ID = actual.h.ID
+	return true, rh.Update(ctx)
+}
+
+func (rh *repositoryHook) createIntoSelf(ctx context.Context) error {
+	// POST /repos/{owner}/{repo}/hooks
+	apiObj, err := rh.c.c.CreateHook(ctx, rh.c.ref.GetIdentity(), rh.c.ref.GetRepository(), newGithubHookSpec(&rh.h))
+	if err != nil {
+		return err
+	}
+	rh.h = *apiObj
+	return nil
+}
+
+func validateRepositoryHookAPI(apiObj *github.Hook) error {
+	return validateAPIObject("GitHub.Hook", func(validator validation.Validator) {
+		// Make sure ID, active, events and the config URL are populated as per
+		// https://docs.github.com/en/rest/reference/repos#get-a-repository-webhook
+		if apiObj.ID == nil {
+			validator.Required("ID")
+		}
+		if apiObj.Active == nil {
+			validator.Required("Active")
+		}
+		if apiObj.Events == nil {
+			validator.Required("Events")
+		}
+		if _, ok := apiObj.Config[hookConfigURL].(string); !ok {
+			validator.Required("Config.URL")
+		}
+	})
+}
+
+func repositoryHookFromAPI(apiObj *github.Hook) gitprovider.RepositoryHookInfo {
+	url, _ := apiObj.Config[hookConfigURL].(string)
+	// GitHub delivers form-encoded payloads if no content type is configured
+	contentType := gitprovider.RepositoryHookContentTypeForm
+	if ct, ok := apiObj.Config[hookConfigContentType].(string); ok && len(ct) != 0 {
+		contentType = gitprovider.RepositoryHookContentType(ct)
+	}
+	return gitprovider.RepositoryHookInfo{
+		URL:         url,
+		ContentType: &contentType,
+		// The secret is write-only, GitHub only returns a masked value
+		Active: apiObj.Active,
+		Events: repositoryHookEventsFromAPI(apiObj.Events),
+	}
+}
+
+// repositoryHookEventsFromAPI maps the GitHub event names to the provider-neutral events.
+// Events without a provider-neutral equivalent, e.g. "deployment", are left out.
+func repositoryHookEventsFromAPI(apiEvents []string) []gitprovider.RepositoryHookEvent {
+	events := make([]gitprovider.RepositoryHookEvent, 0, len(apiEvents))
+	for _, apiEvent := range apiEvents {
+		event := gitprovider.RepositoryHookEvent(apiEvent)
+		if gitprovider.ValidateRepositoryHookEvent(event) == nil {
+			events = append(events, event)
+		}
+	}
+	return gitprovider.NormalizeRepositoryHookEvents(events)
+}
+
+func repositoryHookToAPI(info *gitprovider.RepositoryHookInfo) *github.Hook {
+	h := &github.Hook{}
+	repositoryHookInfoToAPIObj(info, h)
+	return h
+}
+
+func repositoryHookInfoToAPIObj(info *gitprovider.RepositoryHookInfo, apiObj *github.Hook) {
+	if apiObj.Config == nil {
+		apiObj.Config = map[string]interface{}{}
+	}
+	// Required fields, we assume info is validated, and hence these are set
+	apiObj.Config[hookConfigURL] = info.URL
+	// optional fields
+	if info.ContentType != nil {
+		apiObj.Config[hookConfigContentType] = string(*info.ContentType)
+	}
+	// Never send back the masked secret returned by GitHub
+	delete(apiObj.Config, hookConfigSecret)
+	if info.Secret != nil {
+		apiObj.Config[hookConfigSecret] = *info.Secret
+	}
+	if info.Active != nil {
+		apiObj.Active = info.Active
+	}
+	if info.Events != nil {
+		events := make([]string, 0, len(info.Events)+len(apiObj.Events))
+		for _, event := range info.Events {
+			events = append(events, string(event))
+		}
+		// Keep the subscribed events that don't have a provider-neutral equivalent
+		for _, apiEvent := range apiObj.Events {
+			if gitprovider.ValidateRepositoryHookEvent(gitprovider.RepositoryHookEvent(apiEvent)) != nil {
+				events = append(events, apiEvent)
+			}
+		}
+		apiObj.Events = events
+	}
+}
+
+// This function copies over the fields that are part of create request of a webhook
+// i.e. the desired spec of the webhook. This allows us to separate "spec" from "status" fields.
+func newGithubHookSpec(hook *github.Hook) *github.Hook {
+	return &github.Hook{
+		// Create-specific parameters
+		// See: https://docs.github.com/en/rest/reference/repos#create-a-repository-webhook
+		Config: hook.Config,
+		Events: hook.Events,
+		Active: hook.Active,
+	}
+}
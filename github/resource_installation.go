@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+const (
+	// repositorySelectionAll means that the app installation has access to all repositories of the account.
+	repositorySelectionAll = "all"
+)
+
+// permissionLevels are the access levels of app permissions, in increasing order.
+//nolint:gochecknoglobals
+var permissionLevels = []string{"read", "write", "admin"}
+
+// repoPermissionRanks maps the user's access to a repository, as returned in the permissions of
+// "GET /repos/{owner}/{repo}", to the highest access level of app permissions it allows,
+// as a rank, see permissionLevelRank.
+//nolint:gochecknoglobals,gomnd
+var repoPermissionRanks = map[string]int{
+	"pull":     1,
+	"triage":   1,
+	"push":     2,
+	"maintain": 2,
+	"admin":    3,
+}
+
+// installation is the subset of an app installation object, as returned from
+// "GET /user/installations", that we care about. go-github's Installation struct
+// lacks the app slug, and decodes permissions into a fixed set of fields.
+type installation struct {
+	ID                  *int64            `json:"id,omitempty"`
+	AppSlug             *string           `json:"app_slug,omitempty"`
+	Account             *github.User      `json:"account,omitempty"`
+	RepositorySelection *string           `json:"repository_selection,omitempty"`
+	Permissions         map[string]string `json:"permissions,omitempty"`
+}
+
+// validateInstallationAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateInstallationAPI(apiObj *installation) error {
+	return validateAPIObject("GitHub.Installation", func(validator validation.Validator) {
+		if apiObj.ID == nil {
+			validator.Required("ID")
+		}
+		if apiObj.AppSlug == nil {
+			validator.Required("AppSlug")
+		}
+		if apiObj.Account == nil || apiObj.Account.Login == nil {
+			validator.Required("Account.Login")
+		}
+		if apiObj.RepositorySelection == nil {
+			validator.Required("RepositorySelection")
+		}
+	})
+}
+
+func installedAppFromAPI(apiObj *installation) gitprovider.InstalledAppInfo {
+	permissions := make(map[string]string, len(apiObj.Permissions))
+	for name, level := range apiObj.Permissions {
+		permissions[name] = level
+	}
+	return gitprovider.InstalledAppInfo{
+		Slug:        *apiObj.AppSlug,
+		Permissions: permissions,
+	}
+}
+
+// permissionLevelRank returns the 1-based rank of level in permissionLevels, or 0 if it's unknown.
+func permissionLevelRank(level string) int {
+	for i, l := range permissionLevels {
+		if l == level {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// effectivePermissions merges the permissions of the (validated) installations, keeping the
+// highest access level of each permission, capped by the user's access to the repository.
+// Permissions with unknown access levels are kept as-is.
+func effectivePermissions(installations []*installation, repoPermissions map[string]bool) map[string]string {
+	maxRank := 0
+	for name, granted := range repoPermissions {
+		if granted && repoPermissionRanks[name] > maxRank {
+			maxRank = repoPermissionRanks[name]
+		}
+	}
+
+	permissions := map[string]string{}
+	for _, apiObj := range installations {
+		for name, level := range apiObj.Permissions {
+			rank := permissionLevelRank(level)
+			if rank == 0 {
+				permissions[name] = level
+				continue
+			}
+			if rank > maxRank {
+				rank = maxRank
+			}
+			if rank > permissionLevelRank(permissions[name]) {
+				permissions[name] = permissionLevels[rank-1]
+			}
+		}
+	}
+	return permissions
+}
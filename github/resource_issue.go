@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+const (
+	// issueStateAll is the state filter of the issue list, matching both open and closed issues.
+	issueStateAll = "all"
+)
+
+func newIssue(ctx *clientContext, apiObj *github.Issue, ref gitprovider.RepositoryRef) *issue {
+	return &issue{
+		clientContext: ctx,
+		i:             *apiObj,
+		ref:           ref,
+	}
+}
+
+var _ gitprovider.Issue = &issue{}
+
+type issue struct {
+	*clientContext
+
+	i   github.Issue
+	ref gitprovider.RepositoryRef
+}
+
+func (i *issue) Get() gitprovider.IssueInfo {
+	return issueFromAPI(&i.i)
+}
+
+func (i *issue) APIObject() interface{} {
+	return &i.i
+}
+
+func (i *issue) Repository() gitprovider.RepositoryRef {
+	return i.ref
+}
+
+func issueFromAPI(apiObj *github.Issue) gitprovider.IssueInfo {
+	return gitprovider.IssueInfo{
+		Number: apiObj.GetNumber(),
+		Title:  apiObj.GetTitle(),
+		State:  gitprovider.IssueState(apiObj.GetState()),
+		URL:    apiObj.GetHTMLURL(),
+	}
+}
+
+// issueListOptions converts the given IssueListOptions to the go-github options of the issue list.
+// GitHub only lists open issues by default, hence the state is always set.
+func issueListOptions(opts gitprovider.IssueListOptions) *github.IssueListByRepoOptions {
+	apiOpts := &github.IssueListByRepoOptions{
+		State: issueStateAll,
+	}
+	if opts.State != nil {
+		apiOpts.State = string(*opts.State)
+	}
+	if opts.Label != "" {
+		apiOpts.Labels = []string{opts.Label}
+	}
+	return apiOpts
+}
+
+// validateIssueAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateIssueAPI(apiObj *github.Issue) error {
+	return validateAPIObject("GitHub.Issue", func(validator validation.Validator) {
+		if apiObj.Number == nil {
+			validator.Required("Number")
+		}
+		if apiObj.State == nil {
+			validator.Required("State")
+		}
+	})
+}
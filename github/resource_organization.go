@@ -17,12 +17,22 @@ limitations under the License.
 package github
 
 import (
+	"context"
+
 	"github.com/google/go-github/v32/github"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
 	"github.com/dinosk/go-git-providers/validation"
 )
 
+const (
+	// maxOrgNameLength, maxOrgDescriptionLength and maxOrgBlogLength are the maximum lengths of
+	// the display name, description and website in the profile of an organization.
+	maxOrgNameLength        = 255
+	maxOrgDescriptionLength = 160
+	maxOrgBlogLength        = 255
+)
+
 func newOrganization(ctx *clientContext, apiObj *github.Organization, ref gitprovider.OrganizationRef) *organization {
 	return &organization{
 		clientContext: ctx,
@@ -32,6 +42,10 @@ func newOrganization(ctx *clientContext, apiObj *github.Organization, ref gitpro
 			clientContext: ctx,
 			ref:           ref,
 		},
+		actionsSecrets: &OrgActionsSecretsClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
 	}
 }
 
@@ -43,13 +57,59 @@ type organization struct {
 	o   github.Organization
 	ref gitprovider.OrganizationRef
 
-	teams *TeamsClient
+	teams          *TeamsClient
+	actionsSecrets *OrgActionsSecretsClient
 }
 
 func (o *organization) Get() gitprovider.OrganizationInfo {
 	return organizationFromAPI(&o.o)
 }
 
+// GetMetadata fetches the profile of the organization. The member count is the number of filled
+// seats of the plan of the organization, which is only visible to its owners, and nil otherwise.
+//
+// The internal API object will be overridden with the received server data.
+func (o *organization) GetMetadata(ctx context.Context) (gitprovider.OrganizationMetadata, error) {
+	// GET /orgs/{org}
+	apiObj, err := o.c.GetOrg(ctx, o.ref.Organization)
+	if err != nil {
+		return gitprovider.OrganizationMetadata{}, err
+	}
+	o.o = *apiObj
+	return organizationMetadataFromAPI(apiObj), nil
+}
+
+// SetMetadata updates the display name, description and website of the organization.
+// This is a no-op if the set fields already are the actual state.
+//
+// The internal API object will be overridden with the received server data.
+func (o *organization) SetMetadata(ctx context.Context, metadata gitprovider.OrganizationMetadata) error {
+	if err := validateOrganizationMetadata(metadata); err != nil {
+		return validation.NewMultiError(err, gitprovider.ErrInvalidArgument)
+	}
+	// GET /orgs/{org}
+	actual, err := o.GetMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	// If desired state already is the actual state, do nothing
+	changes, changed := metadata.Changes(actual)
+	if !changed {
+		return nil
+	}
+	// PATCH /orgs/{org}
+	apiObj, err := o.c.UpdateOrg(ctx, o.ref.Organization, &github.Organization{
+		Name:        changes.DisplayName,
+		Description: changes.Description,
+		Blog:        changes.Website,
+	})
+	if err != nil {
+		return err
+	}
+	o.o = *apiObj
+	return nil
+}
+
 func (o *organization) APIObject() interface{} {
 	return &o.o
 }
@@ -62,6 +122,75 @@ func (o *organization) Teams() gitprovider.TeamsClient {
 	return o.teams
 }
 
+func (o *organization) ActionsSecrets() gitprovider.OrgActionsSecretsClient {
+	return o.actionsSecrets
+}
+
+// StreamAuditLog calls fn for each event in the audit log of this organization, in chronological
+// order, fetching one page at a time. If fn returns an error, streaming stops and that error is
+// returned. This requires the "read:audit_log" scope and GitHub Enterprise Cloud,
+// otherwise ErrInsufficientScope is returned.
+func (o *organization) StreamAuditLog(ctx context.Context, opts gitprovider.AuditLogOptions, fn func(gitprovider.AuditEvent) error) error {
+	// GET /orgs/{org}/audit-log
+	return o.c.StreamOrgAuditLog(ctx, o.ref.Organization, auditLogPhrase(opts.Since), opts.After, func(apiObj *auditEvent, cursor string) error {
+		// apiObj is already validated at StreamOrgAuditLog
+		return fn(auditEventFromAPI(apiObj, cursor))
+	})
+}
+
+// GetDefaultBranchName returns the name of the initial branch of new repositories in this organization.
+//
+// This is not supported in GitHub, whose REST API doesn't expose the setting.
+func (o *organization) GetDefaultBranchName(_ context.Context) (string, error) {
+	return "", gitprovider.ErrNoProviderSupport
+}
+
+// SetDefaultBranchName sets the name of the initial branch of new repositories in this organization.
+//
+// This is not supported in GitHub, whose REST API doesn't expose the setting.
+func (o *organization) SetDefaultBranchName(_ context.Context, _ string) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// SetRepositoryCreationLevel sets who can create repositories in this organization.
+//
+// This is not supported in GitHub, whose member privileges don't map onto the levels.
+func (o *organization) SetRepositoryCreationLevel(_ context.Context, _ gitprovider.RepositoryCreationLevel) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// ListPATRequests lists the pending requests to access this organization with a fine-grained
+// personal access token. This requires the organization to require approval of such tokens, and
+// the "admin:org" scope, otherwise ErrInsufficientScope is returned.
+//
+// ListPATRequests returns all available requests, using multiple paginated requests if needed.
+func (o *organization) ListPATRequests(ctx context.Context) ([]gitprovider.PATRequest, error) {
+	// GET /orgs/{org}/personal-access-token-requests
+	apiObjs, err := o.c.ListOrgPATRequests(ctx, o.ref.Organization)
+	if err != nil {
+		return nil, err
+	}
+	requests := make([]gitprovider.PATRequest, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListOrgPATRequests
+		requests = append(requests, patRequestFromAPI(apiObj))
+	}
+	return requests, nil
+}
+
+// ApprovePATRequest approves the pending fine-grained personal access token request with the given ID.
+func (o *organization) ApprovePATRequest(ctx context.Context, id int64) error {
+	// POST /orgs/{org}/personal-access-token-requests/{pat_request_id}
+	return o.c.ReviewOrgPATRequest(ctx, o.ref.Organization, id, patRequestActionApprove)
+}
+
+// DenyPATRequest denies the pending fine-grained personal access token request with the given ID.
+// This requires destructive actions to be enabled in the client.
+func (o *organization) DenyPATRequest(ctx context.Context, id int64) error {
+	// POST /orgs/{org}/personal-access-token-requests/{pat_request_id}
+	return o.c.ReviewOrgPATRequest(ctx, o.ref.Organization, id, patRequestActionDeny)
+}
+
 func organizationFromAPI(apiObj *github.Organization) gitprovider.OrganizationInfo {
 	return gitprovider.OrganizationInfo{
 		Name:        apiObj.Name,
@@ -69,6 +198,34 @@ func organizationFromAPI(apiObj *github.Organization) gitprovider.OrganizationIn
 	}
 }
 
+func organizationMetadataFromAPI(apiObj *github.Organization) gitprovider.OrganizationMetadata {
+	metadata := gitprovider.OrganizationMetadata{
+		DisplayName: apiObj.Name,
+		Description: apiObj.Description,
+		AvatarURL:   apiObj.AvatarURL,
+		Website:     apiObj.Blog,
+	}
+	if apiObj.Plan != nil {
+		metadata.MemberCount = apiObj.Plan.FilledSeats
+	}
+	return metadata
+}
+
+// validateOrganizationMetadata validates that the set fields of metadata fit the profile of a GitHub organization.
+func validateOrganizationMetadata(metadata gitprovider.OrganizationMetadata) error {
+	validator := validation.New("OrganizationMetadata")
+	if metadata.DisplayName != nil {
+		validator.Append(gitprovider.ValidateMaxLength(*metadata.DisplayName, maxOrgNameLength), *metadata.DisplayName, "DisplayName")
+	}
+	if metadata.Description != nil {
+		validator.Append(gitprovider.ValidateMaxLength(*metadata.Description, maxOrgDescriptionLength), *metadata.Description, "Description")
+	}
+	if metadata.Website != nil {
+		validator.Append(gitprovider.ValidateMaxLength(*metadata.Website, maxOrgBlogLength), *metadata.Website, "Website")
+	}
+	return validator.Error()
+}
+
 // validateOrganizationAPI validates the apiObj received from the server, to make sure that it is
 // valid for our use.
 func validateOrganizationAPI(apiObj *github.Organization) error {
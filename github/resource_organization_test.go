@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+func Test_organizationMetadataFromAPI(t *testing.T) {
+	tests := []struct {
+		name   string
+		apiObj *github.Organization
+		want   gitprovider.OrganizationMetadata
+	}{
+		{
+			name: "owner, with plan",
+			apiObj: &github.Organization{
+				Login:       github.String("fluxcd"),
+				Name:        github.String("Flux"),
+				Description: github.String("Open and extensible continuous delivery"),
+				AvatarURL:   github.String("https://avatars.githubusercontent.com/u/52158677"),
+				Blog:        github.String("https://fluxcd.io"),
+				Plan:        &github.Plan{FilledSeats: github.Int(42)},
+			},
+			want: gitprovider.OrganizationMetadata{
+				DisplayName: gitprovider.StringVar("Flux"),
+				Description: gitprovider.StringVar("Open and extensible continuous delivery"),
+				AvatarURL:   gitprovider.StringVar("https://avatars.githubusercontent.com/u/52158677"),
+				Website:     gitprovider.StringVar("https://fluxcd.io"),
+				MemberCount: gitprovider.IntVar(42),
+			},
+		},
+		{
+			name:   "member, without plan",
+			apiObj: &github.Organization{Login: github.String("fluxcd"), Name: github.String("Flux")},
+			want:   gitprovider.OrganizationMetadata{DisplayName: gitprovider.StringVar("Flux")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := organizationMetadataFromAPI(tt.apiObj); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("organizationMetadataFromAPI() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeOrgClient is a githubClient that keeps a single organization in memory.
+// Calling any other method than the overridden ones panics.
+type fakeOrgClient struct {
+	githubClient
+
+	org     github.Organization
+	updates []*github.Organization
+}
+
+func (c *fakeOrgClient) GetOrg(_ context.Context, _ string) (*github.Organization, error) {
+	org := c.org
+	return &org, nil
+}
+
+func (c *fakeOrgClient) UpdateOrg(_ context.Context, _ string, req *github.Organization) (*github.Organization, error) {
+	c.updates = append(c.updates, req)
+	if req.Name != nil {
+		c.org.Name = req.Name
+	}
+	if req.Description != nil {
+		c.org.Description = req.Description
+	}
+	if req.Blog != nil {
+		c.org.Blog = req.Blog
+	}
+	return c.GetOrg(context.Background(), "")
+}
+
+func TestOrganization_SetMetadata(t *testing.T) {
+	fake := &fakeOrgClient{org: github.Organization{
+		Login:       github.String("fluxcd"),
+		Name:        github.String("Flux"),
+		Description: github.String("Continuous delivery"),
+		AvatarURL:   github.String("https://avatars.githubusercontent.com/u/52158677"),
+	}}
+	ref := gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "fluxcd"}
+	o := newOrganization(&clientContext{c: fake, domain: DefaultDomain}, &fake.org, ref)
+	ctx := context.Background()
+
+	// Only the set fields that differ are sent, and read-only fields are ignored
+	err := o.SetMetadata(ctx, gitprovider.OrganizationMetadata{
+		DisplayName: gitprovider.StringVar("Flux"),
+		Website:     gitprovider.StringVar("https://fluxcd.io"),
+		AvatarURL:   gitprovider.StringVar("https://example.com/avatar.png"),
+	})
+	if err != nil {
+		t.Fatalf("SetMetadata() error = %v", err)
+	}
+	want := []*github.Organization{{Blog: github.String("https://fluxcd.io")}}
+	if !reflect.DeepEqual(fake.updates, want) {
+		t.Errorf("SetMetadata() updates = %v, want %v", fake.updates, want)
+	}
+	if *o.Get().Name != "Flux" || *fake.org.AvatarURL != "https://avatars.githubusercontent.com/u/52158677" {
+		t.Errorf("SetMetadata() changed the name or avatar")
+	}
+
+	// Setting the actual state again is a no-op
+	if err := o.SetMetadata(ctx, gitprovider.OrganizationMetadata{Website: gitprovider.StringVar("https://fluxcd.io")}); err != nil {
+		t.Fatalf("SetMetadata() error = %v", err)
+	}
+	if len(fake.updates) != 1 {
+		t.Errorf("SetMetadata() made %d updates, want 1", len(fake.updates))
+	}
+
+	// Too long fields are rejected before calling the server
+	err = o.SetMetadata(ctx, gitprovider.OrganizationMetadata{Description: gitprovider.StringVar(strings.Repeat("ä", maxOrgDescriptionLength+1))})
+	if !errors.Is(err, gitprovider.ErrInvalidArgument) {
+		t.Errorf("SetMetadata() error = %v, want %v", err, gitprovider.ErrInvalidArgument)
+	}
+	if err := o.SetMetadata(ctx, gitprovider.OrganizationMetadata{Description: gitprovider.StringVar(strings.Repeat("ä", maxOrgDescriptionLength))}); err != nil {
+		t.Errorf("SetMetadata() error = %v for a description of maximum length", err)
+	}
+}
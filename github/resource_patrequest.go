@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"time"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+const (
+	// patRequestActionApprove and patRequestActionDeny are the values of the "action" field of
+	// "POST /orgs/{org}/personal-access-token-requests/{pat_request_id}".
+	patRequestActionApprove = "approve"
+	patRequestActionDeny    = "deny"
+)
+
+// patRequest is the subset of a fine-grained personal access token request, as returned from
+// "GET /orgs/{org}/personal-access-token-requests", that we care about. go-github doesn't
+// provide a struct for it (yet).
+type patRequest struct {
+	ID          *int64                       `json:"id,omitempty"`
+	Reason      *string                      `json:"reason,omitempty"`
+	Owner       *github.User                 `json:"owner,omitempty"`
+	Permissions map[string]map[string]string `json:"permissions,omitempty"`
+	CreatedAt   *time.Time                   `json:"created_at,omitempty"`
+}
+
+// patRequestReview is the request body of "POST /orgs/{org}/personal-access-token-requests/{pat_request_id}".
+type patRequestReview struct {
+	Action string `json:"action"`
+}
+
+// validatePATRequestAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validatePATRequestAPI(apiObj *patRequest) error {
+	return validateAPIObject("GitHub.PATRequest", func(validator validation.Validator) {
+		if apiObj.ID == nil {
+			validator.Required("ID")
+		}
+		if apiObj.Owner == nil || apiObj.Owner.Login == nil {
+			validator.Required("Owner.Login")
+		}
+	})
+}
+
+func patRequestFromAPI(apiObj *patRequest) gitprovider.PATRequest {
+	req := gitprovider.PATRequest{
+		ID:                      *apiObj.ID,
+		Requester:               *apiObj.Owner.Login,
+		OrganizationPermissions: copyPermissions(apiObj.Permissions["organization"]),
+		RepositoryPermissions:   copyPermissions(apiObj.Permissions["repository"]),
+	}
+	if apiObj.Reason != nil {
+		req.Reason = *apiObj.Reason
+	}
+	if apiObj.CreatedAt != nil {
+		req.CreatedAt = *apiObj.CreatedAt
+	}
+	return req
+}
+
+// copyPermissions returns a copy of the given permission-to-level map, which is never nil.
+func copyPermissions(permissions map[string]string) map[string]string {
+	result := make(map[string]string, len(permissions))
+	for name, level := range permissions {
+		result[name] = level
+	}
+	return result
+}
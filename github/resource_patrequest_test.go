@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+func Test_patRequestFromAPI(t *testing.T) {
+	apiObj := &patRequest{}
+	data := `{"id":25381,"reason":"Deploy with Flux","owner":{"login":"octocat"},
+		"permissions":{"organization":{"members":"read"},"repository":{"contents":"write"},"other":{"gpg_keys":"read"}},
+		"created_at":"2023-05-16T08:47:09Z"}`
+	if err := json.Unmarshal([]byte(data), apiObj); err != nil {
+		t.Fatal(err)
+	}
+	if err := validatePATRequestAPI(apiObj); err != nil {
+		t.Fatalf("validatePATRequestAPI() error = %v", err)
+	}
+	got := patRequestFromAPI(apiObj)
+	if got.ID != 25381 || got.Requester != "octocat" || got.Reason != "Deploy with Flux" || got.CreatedAt.IsZero() {
+		t.Errorf("patRequestFromAPI() = %+v", got)
+	}
+	if want := map[string]string{"members": "read"}; !reflect.DeepEqual(got.OrganizationPermissions, want) {
+		t.Errorf("patRequestFromAPI() organization permissions = %v, want %v", got.OrganizationPermissions, want)
+	}
+	if want := map[string]string{"contents": "write"}; !reflect.DeepEqual(got.RepositoryPermissions, want) {
+		t.Errorf("patRequestFromAPI() repository permissions = %v, want %v", got.RepositoryPermissions, want)
+	}
+
+	// The requester is required
+	if err := validatePATRequestAPI(&patRequest{ID: apiObj.ID}); !errors.Is(err, gitprovider.ErrInvalidServerData) {
+		t.Errorf("validatePATRequestAPI() error = %v, want %v", err, gitprovider.ErrInvalidServerData)
+	}
+}
+
+func Test_githubClientImpl_ReviewOrgPATRequest_deny(t *testing.T) {
+	// Denying is refused before any request is made, hence no underlying client is needed
+	c := &githubClientImpl{destructiveActions: false}
+	err := c.ReviewOrgPATRequest(context.Background(), "foo", 1, patRequestActionDeny)
+	if !errors.Is(err, gitprovider.ErrDestructiveCallDisallowed) {
+		t.Errorf("ReviewOrgPATRequest() error = %v, want %v", err, gitprovider.ErrDestructiveCallDisallowed)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+func newRelease(ctx *clientContext, apiObj *github.RepositoryRelease, ref gitprovider.RepositoryRef) *release {
+	return &release{
+		clientContext: ctx,
+		r:             *apiObj,
+		ref:           ref,
+	}
+}
+
+var _ gitprovider.Release = &release{}
+
+type release struct {
+	*clientContext
+
+	r   github.RepositoryRelease
+	ref gitprovider.RepositoryRef
+}
+
+func (r *release) Get() gitprovider.ReleaseInfo {
+	return releaseFromAPI(&r.r)
+}
+
+func (r *release) APIObject() interface{} {
+	return &r.r
+}
+
+func (r *release) Repository() gitprovider.RepositoryRef {
+	return r.ref
+}
+
+func releaseFromAPI(apiObj *github.RepositoryRelease) gitprovider.ReleaseInfo {
+	return gitprovider.ReleaseInfo{
+		TagName:         *apiObj.TagName,
+		TargetCommitish: apiObj.TargetCommitish,
+		Name:            apiObj.Name,
+		Body:            apiObj.Body,
+		Draft:           apiObj.Draft,
+		Prerelease:      apiObj.Prerelease,
+	}
+}
+
+func releaseToAPI(info *gitprovider.ReleaseInfo) *github.RepositoryRelease {
+	return &github.RepositoryRelease{
+		TagName:         &info.TagName,
+		TargetCommitish: info.TargetCommitish,
+		Name:            info.Name,
+		Body:            info.Body,
+		Draft:           info.Draft,
+		Prerelease:      info.Prerelease,
+	}
+}
+
+// validateReleaseAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateReleaseAPI(apiObj *github.RepositoryRelease) error {
+	return validateAPIObject("GitHub.RepositoryRelease", func(validator validation.Validator) {
+		if apiObj.TagName == nil {
+			validator.Required("TagName")
+		}
+	})
+}
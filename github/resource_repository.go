@@ -19,6 +19,7 @@ package github
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 
 	"github.com/google/go-github/v32/github"
@@ -36,6 +37,38 @@ func newUserRepository(ctx *clientContext, apiObj *github.Repository, ref gitpro
 			clientContext: ctx,
 			ref:           ref,
 		},
+		releases: &ReleaseClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		commits: &CommitClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branches: &BranchClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		tags: &TagClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		issues: &IssueClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		hooks: &RepositoryHookClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		rulesets: &RulesetClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branchProtection: &BranchProtectionClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
 	}
 }
 
@@ -46,19 +79,57 @@ type userRepository struct {
 
 	r   github.Repository // go-github
 	ref gitprovider.RepositoryRef
+	// allowForking is kept next to r, as go-github doesn't provide a field for it (yet).
+	// allowForkingChanged is true if it was changed by Set, and needs to be applied in Update.
+	allowForking        *bool
+	allowForkingChanged bool
+	// archivedChanged is true if Archived was changed by Set, or differs from the actual state at
+	// Reconcile time. Update then orders the archiving around the other changes.
+	archivedChanged bool
 
 	deployKeys *DeployKeyClient
+	releases   *ReleaseClient
+	commits    *CommitClient
+	branches   *BranchClient
+	tags       *TagClient
+	issues     *IssueClient
+	hooks      *RepositoryHookClient
+	rulesets   *RulesetClient
+
+	branchProtection *BranchProtectionClient
 }
 
 func (r *userRepository) Get() gitprovider.RepositoryInfo {
-	return repositoryFromAPI(&r.r)
+	info := repositoryFromAPI(&r.r)
+	info.AllowForking = r.allowForking
+	return info
 }
 
 func (r *userRepository) Set(info gitprovider.RepositoryInfo) error {
 	if err := info.ValidateInfo(); err != nil {
 		return err
 	}
+	visibility := info.Visibility
+	if visibility == nil {
+		visibility = r.Get().Visibility
+	}
+	if err := validateAllowForking(info.AllowForking, visibility); err != nil {
+		return err
+	}
+	if err := validateVisibility(info.Visibility, r.ref.GetDomain()); err != nil {
+		return err
+	}
+	if err := r.validateArchiving(info); err != nil {
+		return err
+	}
+	if info.Archived != nil && *info.Archived != r.r.GetArchived() {
+		r.archivedChanged = true
+	}
 	repositoryInfoToAPIObj(&info, &r.r)
+	if info.AllowForking != nil && !reflect.DeepEqual(info.AllowForking, r.allowForking) {
+		r.allowForking = info.AllowForking
+		r.allowForkingChanged = true
+	}
 	return nil
 }
 
@@ -74,6 +145,38 @@ func (r *userRepository) DeployKeys() gitprovider.DeployKeyClient {
 	return r.deployKeys
 }
 
+func (r *userRepository) Releases() gitprovider.ReleaseClient {
+	return r.releases
+}
+
+func (r *userRepository) Commits() gitprovider.CommitClient {
+	return r.commits
+}
+
+func (r *userRepository) Branches() gitprovider.BranchClient {
+	return r.branches
+}
+
+func (r *userRepository) Tags() gitprovider.TagClient {
+	return r.tags
+}
+
+func (r *userRepository) Issues() gitprovider.IssueClient {
+	return r.issues
+}
+
+func (r *userRepository) Hooks() gitprovider.RepositoryHookClient {
+	return r.hooks
+}
+
+func (r *userRepository) Rulesets() gitprovider.RulesetClient {
+	return r.rulesets
+}
+
+func (r *userRepository) BranchProtection() gitprovider.BranchProtectionClient {
+	return r.branchProtection
+}
+
 // Update will apply the desired state in this object to the server.
 // Only set fields will be respected (i.e. PATCH behaviour).
 // In order to apply changes to this object, use the .Set({Resource}Info) error
@@ -84,13 +187,56 @@ func (r *userRepository) DeployKeys() gitprovider.DeployKeyClient {
 //
 // The internal API object will be overridden with the received server data.
 func (r *userRepository) Update(ctx context.Context) error {
+	// GitHub refuses most changes to archived repositories, hence unarchive first, and
+	// archive last if the archived state changed
+	archive := r.archivedChanged && r.r.GetArchived()
+	if r.archivedChanged && !archive {
+		if err := r.updateArchived(ctx, false); err != nil {
+			return err
+		}
+	}
+	req := r.r
+	if archive {
+		req.Archived = nil
+	}
+	// Topics can't be updated through PATCH, hence apply them separately if they changed
+	topics := r.r.Topics
 	// PATCH /repos/{owner}/{repo}
-	apiObj, err := r.c.UpdateRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), &r.r)
+	apiObj, err := r.c.UpdateRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), &req)
 	if err != nil {
 		return err
 	}
 	r.r = *apiObj
-	return nil
+	if err := r.updateTopics(ctx, topics); err != nil {
+		return err
+	}
+	if r.allowForkingChanged {
+		if err := r.updateAllowForking(ctx); err != nil {
+			return err
+		}
+	}
+	if !archive {
+		return nil
+	}
+	return r.updateArchived(ctx, true)
+}
+
+// Refresh fetches the current state of this repository from the server, without
+// mutating anything. Any local changes that weren't applied are discarded.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (r *userRepository) Refresh(ctx context.Context) error {
+	// GET /repos/{owner}/{repo}
+	apiObj, err := r.c.GetRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return err
+	}
+	r.r = *apiObj
+	r.allowForkingChanged = false
+	r.archivedChanged = false
+	return r.getAllowForking(ctx)
 }
 
 // Reconcile makes sure the desired state in this object (called "req" here) becomes
@@ -110,12 +256,27 @@ func (r *userRepository) Reconcile(ctx context.Context) (bool, error) {
 			if orgRef, ok := r.ref.(gitprovider.OrgRepositoryRef); ok {
 				orgName = orgRef.Organization
 			}
+			topics := r.r.Topics
+			archived := r.r.GetArchived()
 			repo, err := r.c.CreateRepo(ctx, orgName, &r.r)
 			if err != nil {
 				return true, err
 			}
 			r.r = *repo
-			return true, nil
+			// Topics can't be set at creation time, hence apply them separately
+			if err := r.updateTopics(ctx, topics); err != nil {
+				return true, err
+			}
+			if r.allowForking != nil {
+				if err := r.updateAllowForking(ctx); err != nil {
+					return true, err
+				}
+			}
+			// Neither can the repository be archived, hence archive it last
+			if !archived {
+				return true, nil
+			}
+			return true, r.updateArchived(ctx, true)
 		}
 
 		return false, err
@@ -124,9 +285,30 @@ func (r *userRepository) Reconcile(ctx context.Context) (bool, error) {
 	// Use wrappers here to extract the "spec" part of the object for comparison
 	desiredSpec := newGithubRepositorySpec(&r.r)
 	actualSpec := newGithubRepositorySpec(apiObj)
+	// Topics have no default, leave them as-is if they aren't desired
+	if r.r.Topics == nil {
+		desiredSpec.Topics = actualSpec.Topics
+	}
+	// Neither has Archived, leave it as-is if it isn't desired
+	if r.r.Archived == nil {
+		desiredSpec.Archived = actualSpec.Archived
+	}
+	r.archivedChanged = desiredSpec.GetArchived() != actualSpec.GetArchived()
+
+	// AllowForking isn't part of apiObj, hence compare it separately if it's desired
+	allowForkingEquals := true
+	if r.allowForking != nil {
+		// GET /repos/{owner}/{repo}
+		actualForking, err := r.c.GetRepoForkingSettings(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+		if err != nil {
+			return false, err
+		}
+		allowForkingEquals = reflect.DeepEqual(r.allowForking, actualForking.AllowForking)
+		r.allowForkingChanged = !allowForkingEquals
+	}
 
 	// If desired state already is the actual state, do nothing
-	if desiredSpec.Equals(actualSpec) {
+	if desiredSpec.Equals(actualSpec) && allowForkingEquals {
 		return false, nil
 	}
 	// Otherwise, make the desired state the actual state
@@ -140,6 +322,394 @@ func (r *userRepository) Delete(ctx context.Context) error {
 	return r.c.DeleteRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
 }
 
+// ListSecurityAdvisories lists the security advisories filed for this repository, optionally
+// filtered by state.
+//
+// ListSecurityAdvisories returns all available advisories, using multiple paginated requests if needed.
+func (r *userRepository) ListSecurityAdvisories(ctx context.Context, opts gitprovider.SecurityAdvisoryListOptions) ([]gitprovider.SecurityAdvisoryInfo, error) {
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, err
+	}
+	state := ""
+	if opts.State != nil {
+		state = string(*opts.State)
+	}
+
+	// GET /repos/{owner}/{repo}/security-advisories
+	apiObjs, err := r.c.ListRepoSecurityAdvisories(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), state)
+	if err != nil {
+		return nil, err
+	}
+
+	advisories := make([]gitprovider.SecurityAdvisoryInfo, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListRepoSecurityAdvisories
+		advisories = append(advisories, securityAdvisoryFromAPI(apiObj))
+	}
+	return advisories, nil
+}
+
+// SetPipelineRequirements configures what CI results are required before changes can be merged.
+//
+// This is not supported in GitHub, where the requirements are set per branch.
+func (r *userRepository) SetPipelineRequirements(_ context.Context, _ gitprovider.PipelineRequirements) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// SetIssueCloseSettings configures how issues are closed automatically when referenced.
+//
+// This is not supported in GitHub, where referenced issues are always closed.
+func (r *userRepository) SetIssueCloseSettings(_ context.Context, _ gitprovider.IssueCloseSettings) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// ListInstalledApps lists the GitHub Apps that have been granted access to this repository,
+// along with their permissions. This requires a user-to-server token of a GitHub App,
+// otherwise ErrInsufficientScope is returned.
+//
+// ListInstalledApps returns all available apps, using multiple paginated requests if needed.
+func (r *userRepository) ListInstalledApps(ctx context.Context) ([]gitprovider.InstalledAppInfo, error) {
+	// GET /user/installations
+	apiObjs, err := r.c.ListRepoInstallations(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+
+	apps := make([]gitprovider.InstalledAppInfo, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListRepoInstallations
+		apps = append(apps, installedAppFromAPI(apiObj))
+	}
+	return apps, nil
+}
+
+// EffectivePermissions returns the permissions of the GitHub App installations the client's
+// user-to-server token is for, that have access to this repository, capped by the user's access
+// to the repository. E.g. "contents: write" becomes "contents: read" for a user with read access.
+// Other tokens lack granular permissions, hence ErrInsufficientScope is returned for them.
+// The result is cached per repository for the lifetime of the client.
+func (r *userRepository) EffectivePermissions(ctx context.Context) (map[string]string, error) {
+	if permissions, ok := r.permissions.get(r.ref); ok {
+		return permissions, nil
+	}
+
+	// GET /user/installations
+	installations, err := r.c.ListRepoInstallations(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+	// GET /repos/{owner}/{repo}
+	apiObj, err := r.c.GetRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+	var repoPermissions map[string]bool
+	if apiObj.Permissions != nil {
+		repoPermissions = *apiObj.Permissions
+	}
+
+	permissions := effectivePermissions(installations, repoPermissions)
+	r.permissions.set(r.ref, permissions)
+	return permissions, nil
+}
+
+// PrimaryLanguage returns the language with the largest amount of bytes of code in this repository.
+// An empty string is returned if the repository has no detectable language.
+func (r *userRepository) PrimaryLanguage(ctx context.Context) (string, error) {
+	// GET /repos/{owner}/{repo}/languages
+	languages, err := r.c.ListRepoLanguages(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return "", err
+	}
+	return primaryLanguage(languages), nil
+}
+
+// IsEmpty returns true if the repository has no branches, which is the case until
+// the first commit is pushed.
+//
+// ErrNotFound is returned if the repository does not exist.
+func (r *userRepository) IsEmpty(ctx context.Context) (bool, error) {
+	// GET /repos/{owner}/{repo}/branches
+	hasBranches, err := r.c.RepoHasBranches(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return false, err
+	}
+	return !hasBranches, nil
+}
+
+// GetMergeQueue returns the merge queue settings of the given branch.
+// If no merge queue is configured, MergeQueueInfo.Enabled is false.
+//
+// The merge queue is managed through a repository ruleset named "merge-queue/{branch}".
+// Merge queues configured through other rulesets are not detected.
+func (r *userRepository) GetMergeQueue(ctx context.Context, branch string) (gitprovider.MergeQueueInfo, error) {
+	// GET /repos/{owner}/{repo}/rulesets
+	apiObj, err := r.c.GetRepoRulesetByName(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), mergeQueueRulesetName(branch))
+	if err != nil && !errors.Is(err, gitprovider.ErrNotFound) {
+		return gitprovider.MergeQueueInfo{}, err
+	}
+	return mergeQueueFromAPI(apiObj)
+}
+
+// SetMergeQueue configures the merge queue of the given branch.
+// This is a no-op if the settings already are the actual state.
+//
+// The merge queue is managed through a repository ruleset named "merge-queue/{branch}", which
+// is created if needed. Disabling the merge queue disables the ruleset, rather than deleting it.
+func (r *userRepository) SetMergeQueue(ctx context.Context, branch string, req gitprovider.MergeQueueInfo) error {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return err
+	}
+
+	// GET /repos/{owner}/{repo}/rulesets
+	apiObj, err := r.c.GetRepoRulesetByName(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), mergeQueueRulesetName(branch))
+	if err != nil && !errors.Is(err, gitprovider.ErrNotFound) {
+		return err
+	}
+	actual, err := mergeQueueFromAPI(apiObj)
+	if err != nil {
+		return err
+	}
+	// If desired state already is the actual state, do nothing
+	if req.Equals(actual) {
+		return nil
+	}
+
+	created := apiObj == nil
+	apiObj, err = mergeQueueToAPI(req, branch, apiObj)
+	if err != nil {
+		return err
+	}
+	if created {
+		// POST /repos/{owner}/{repo}/rulesets
+		_, err = r.c.CreateRepoRuleset(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), apiObj)
+		return err
+	}
+	// PUT /repos/{owner}/{repo}/rulesets/{ruleset_id}
+	_, err = r.c.UpdateRepoRuleset(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), apiObj)
+	return err
+}
+
+// TriggerMirrorSync starts syncing this mirror repository with its upstream repository.
+//
+// This is not supported in GitHub.
+func (r *userRepository) TriggerMirrorSync(_ context.Context) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// GetMirrorStatus returns the state of the last sync of this mirror repository.
+//
+// This is not supported in GitHub.
+func (r *userRepository) GetMirrorStatus(_ context.Context) (gitprovider.MirrorStatusInfo, error) {
+	return gitprovider.MirrorStatusInfo{}, gitprovider.ErrNoProviderSupport
+}
+
+// SetMergeTrain enables or disables merge trains.
+//
+// This is not supported in GitHub, see SetMergeQueue instead.
+func (r *userRepository) SetMergeTrain(_ context.Context, _ bool) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// CountOpenIssues returns the amount of open issues in this repository, or 0 if the issue
+// tracker is disabled.
+//
+// GitHub's open issue count of the repository includes the open pull requests, hence the
+// pull requests are counted separately, and subtracted.
+func (r *userRepository) CountOpenIssues(ctx context.Context) (int64, error) {
+	// GET /repos/{owner}/{repo}
+	apiObj, err := r.c.GetRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return 0, err
+	}
+	if apiObj.HasIssues != nil && !*apiObj.HasIssues {
+		return 0, nil
+	}
+	pullRequests, err := r.CountOpenPullRequests(ctx)
+	if err != nil {
+		return 0, err
+	}
+	count := int64(apiObj.GetOpenIssuesCount()) - pullRequests
+	// The search index may lag behind the repository, never return a negative count
+	if count < 0 {
+		return 0, nil
+	}
+	return count, nil
+}
+
+// CountOpenPullRequests returns the amount of open pull requests in this repository.
+//
+// The pull requests are counted using the search API, which has a lower rate limit.
+func (r *userRepository) CountOpenPullRequests(ctx context.Context) (int64, error) {
+	// GET /search/issues
+	return r.c.CountSearchIssues(ctx, fmt.Sprintf("repo:%s/%s is:pr is:open", r.ref.GetIdentity(), r.ref.GetRepository()))
+}
+
+// ListProtectedEnvironments lists the protected environments of this repository.
+//
+// This is not supported in GitHub, which gates deployments through environment reviewers.
+func (r *userRepository) ListProtectedEnvironments(_ context.Context) ([]gitprovider.ProtectedEnvironmentInfo, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// ProtectEnvironment makes sure only the roles in req can deploy to the environment.
+//
+// This is not supported in GitHub, which gates deployments through environment reviewers.
+func (r *userRepository) ProtectEnvironment(_ context.Context, _ gitprovider.ProtectedEnvironmentInfo) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// UnprotectEnvironment removes the protection of the given environment.
+//
+// This is not supported in GitHub, which gates deployments through environment reviewers.
+func (r *userRepository) UnprotectEnvironment(_ context.Context, _ string) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// SetSubscription configures what notifications the authenticated user gets about this repository.
+// SubscriptionLevelAll watches the repository, SubscriptionLevelIgnore ignores it, and
+// SubscriptionLevelParticipating stops watching (or ignoring) it.
+func (r *userRepository) SetSubscription(ctx context.Context, req gitprovider.SubscriptionInfo) error {
+	if err := req.ValidateInfo(); err != nil {
+		return err
+	}
+	// GET /repos/{owner}/{repo}/subscription
+	apiObj, err := r.c.GetRepoSubscription(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return err
+	}
+	// If desired state already is the actual state, do nothing
+	if req.Equals(subscriptionFromAPI(apiObj)) {
+		return nil
+	}
+	// Not watching the repository is the default, which is restored by removing the subscription
+	if req.Level == gitprovider.SubscriptionLevelParticipating {
+		// DELETE /repos/{owner}/{repo}/subscription
+		return r.c.DeleteRepoSubscription(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	}
+	// PUT /repos/{owner}/{repo}/subscription
+	return r.c.SetRepoSubscription(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), &github.Subscription{
+		Subscribed: github.Bool(req.Level == gitprovider.SubscriptionLevelAll),
+		Ignored:    github.Bool(req.Level == gitprovider.SubscriptionLevelIgnore),
+	})
+}
+
+// GetCommunityHealthFile returns the content of the given community health file on the default branch.
+//
+// ErrNotFound is returned if the repository doesn't have the file.
+func (r *userRepository) GetCommunityHealthFile(ctx context.Context, file gitprovider.CommunityHealthFile) (string, error) {
+	_, content, err := r.findCommunityHealthFile(ctx, file)
+	return content, err
+}
+
+// SetCommunityHealthFile commits content as the given community health file to the default branch.
+func (r *userRepository) SetCommunityHealthFile(ctx context.Context, file gitprovider.CommunityHealthFile, content string) error {
+	path, actual, err := r.findCommunityHealthFile(ctx, file)
+	if errors.Is(err, gitprovider.ErrNotFound) {
+		path = gitprovider.CommunityHealthFilePaths(file)[0]
+	} else if err != nil {
+		return err
+	} else if actual == content {
+		// If desired state already is the actual state, do nothing
+		return nil
+	}
+	_, err = r.commits.Create(ctx, r.r.GetDefaultBranch(), fmt.Sprintf("Update %s", path), []gitprovider.CommitFile{
+		{Path: path, Content: &content},
+	})
+	return err
+}
+
+// findCommunityHealthFile returns the path and content of the given community health file, trying
+// the paths in the order of precedence GitHub uses.
+func (r *userRepository) findCommunityHealthFile(ctx context.Context, file gitprovider.CommunityHealthFile) (string, string, error) {
+	if err := gitprovider.ValidateCommunityHealthFile(file); err != nil {
+		return "", "", fmt.Errorf("community health file %q: %w", file, err)
+	}
+	for _, path := range gitprovider.CommunityHealthFilePaths(file) {
+		// GET /repos/{owner}/{repo}/contents/{path}
+		content, err := r.c.GetRepoFileContent(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), path)
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return "", "", err
+		}
+		return path, content, nil
+	}
+	return "", "", fmt.Errorf("community health file %q: %w", file, gitprovider.ErrNotFound)
+}
+
+// CommunityHealthScore returns the health percentage of the community profile of this repository.
+// The community profile doesn't report SECURITY.md, hence that file is looked up separately.
+func (r *userRepository) CommunityHealthScore(ctx context.Context) (gitprovider.CommunityHealthInfo, error) {
+	// GET /repos/{owner}/{repo}/community/profile
+	apiObj, err := r.c.GetCommunityHealthMetrics(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return gitprovider.CommunityHealthInfo{}, err
+	}
+	info := gitprovider.CommunityHealthInfo{
+		HealthPercentage: *apiObj.HealthPercentage,
+		Files:            []gitprovider.CommunityHealthFile{},
+	}
+	if apiObj.Files != nil && apiObj.Files.Contributing != nil {
+		info.Files = append(info.Files, gitprovider.CommunityHealthFileContributing)
+	}
+	if apiObj.Files != nil && apiObj.Files.CodeOfConduct != nil {
+		info.Files = append(info.Files, gitprovider.CommunityHealthFileCodeOfConduct)
+	}
+	_, _, err = r.findCommunityHealthFile(ctx, gitprovider.CommunityHealthFileSecurity)
+	if err == nil {
+		info.Files = append(info.Files, gitprovider.CommunityHealthFileSecurity)
+	} else if !errors.Is(err, gitprovider.ErrNotFound) {
+		return gitprovider.CommunityHealthInfo{}, err
+	}
+	return info, nil
+}
+
+// validateCommunityHealthMetricsAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateCommunityHealthMetricsAPI(apiObj *github.CommunityHealthMetrics) error {
+	return validateAPIObject("GitHub.CommunityHealthMetrics", func(validator validation.Validator) {
+		if apiObj.HealthPercentage == nil {
+			validator.Required("HealthPercentage")
+		}
+	})
+}
+
+// subscriptionFromAPI maps a repository subscription to the SubscriptionInfo. Ignored takes
+// precedence over Subscribed, as ignoring a repository mutes all its notifications.
+func subscriptionFromAPI(apiObj *github.Subscription) gitprovider.SubscriptionInfo {
+	switch {
+	case apiObj == nil:
+		return gitprovider.SubscriptionInfo{Level: gitprovider.SubscriptionLevelParticipating}
+	case apiObj.GetIgnored():
+		return gitprovider.SubscriptionInfo{Level: gitprovider.SubscriptionLevelIgnore}
+	case apiObj.GetSubscribed():
+		return gitprovider.SubscriptionInfo{Level: gitprovider.SubscriptionLevelAll}
+	}
+	return gitprovider.SubscriptionInfo{Level: gitprovider.SubscriptionLevelParticipating}
+}
+
+// SetWebCommitSigning configures whether unsigned commits are rejected for all branches.
+//
+// This is not supported in GitHub, where signed commits are required per branch.
+func (r *userRepository) SetWebCommitSigning(_ context.Context, _ bool) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// primaryLanguage returns the language with the largest amount of bytes, or an empty string
+// if languages is empty. Ties are broken alphabetically, to be deterministic.
+func primaryLanguage(languages map[string]int) string {
+	primary, maxBytes := "", 0
+	for language, bytes := range languages {
+		if primary == "" || bytes > maxBytes || (bytes == maxBytes && language < primary) {
+			primary, maxBytes = language, bytes
+		}
+	}
+	return primary
+}
+
 func newOrgRepository(ctx *clientContext, apiObj *github.Repository, ref gitprovider.RepositoryRef) *orgRepository {
 	return &orgRepository{
 		userRepository: *newUserRepository(ctx, apiObj, ref),
@@ -162,6 +732,106 @@ func (r *orgRepository) TeamAccess() gitprovider.TeamAccessClient {
 	return r.teamAccess
 }
 
+// getAllowForking fetches whether the repository can be forked from the server.
+func (r *userRepository) getAllowForking(ctx context.Context) error {
+	// GET /repos/{owner}/{repo}
+	apiObj, err := r.c.GetRepoForkingSettings(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		return err
+	}
+	r.allowForking = apiObj.AllowForking
+	return nil
+}
+
+// updateAllowForking applies the desired allowForking to the server.
+func (r *userRepository) updateAllowForking(ctx context.Context) error {
+	// PATCH /repos/{owner}/{repo}
+	apiObj, err := r.c.UpdateRepoForkingSettings(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), &repositoryForkingSettings{
+		AllowForking: r.allowForking,
+	})
+	if err != nil {
+		return err
+	}
+	r.allowForking = apiObj.AllowForking
+	r.allowForkingChanged = false
+	return nil
+}
+
+// updateArchived archives or unarchives the repository. If the repository is unarchived, the
+// internal API object is left as-is, as it may contain local changes that are yet to be applied.
+func (r *userRepository) updateArchived(ctx context.Context, archived bool) error {
+	// PATCH /repos/{owner}/{repo}
+	apiObj, err := r.c.UpdateRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), &github.Repository{
+		Archived: github.Bool(archived),
+	})
+	if err != nil {
+		return err
+	}
+	if archived {
+		r.r = *apiObj
+	}
+	r.archivedChanged = false
+	return nil
+}
+
+// updateTopics replaces the topics of the repository with the desired topics, lowercased like GitHub
+// stores them. This is a no-op if topics is nil, or equals the actual topics.
+func (r *userRepository) updateTopics(ctx context.Context, topics []string) error {
+	if topics == nil || reflect.DeepEqual(gitprovider.NormalizeTopics(topics), gitprovider.NormalizeTopics(r.r.Topics)) {
+		return nil
+	}
+	// PUT /repos/{owner}/{repo}/topics
+	apiObj, err := r.c.ReplaceRepoTopics(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), gitprovider.NormalizeTopics(topics))
+	if err != nil {
+		return err
+	}
+	r.r.Topics = apiObj
+	return nil
+}
+
+// validateAllowForking validates that forking is only disallowed for repositories that aren't
+// public, as public repositories can always be forked in GitHub.
+func validateAllowForking(allowForking *bool, visibility *gitprovider.RepositoryVisibility) error {
+	validator := validation.New("Repository")
+	if allowForking != nil && !*allowForking &&
+		visibility != nil && *visibility == gitprovider.RepositoryVisibilityPublic {
+		validator.Append(fmt.Errorf("%w: forking can't be disallowed for public repositories",
+			validation.ErrFieldInvalid), *allowForking, "AllowForking")
+	}
+	return validator.Error()
+}
+
+// validateVisibility makes sure the visibility, if set, exists in the given domain. Internal
+// repositories only exist in GitHub Enterprise, and not in github.com.
+func validateVisibility(visibility *gitprovider.RepositoryVisibility, domain string) error {
+	if visibility != nil && *visibility == gitprovider.RepositoryVisibilityInternal && domain == DefaultDomain {
+		return fmt.Errorf("%s doesn't support internal repositories, only GitHub Enterprise does: %w",
+			domain, gitprovider.ErrNoProviderSupport)
+	}
+	return nil
+}
+
+// validateArchiving validates that info doesn't archive the repository while changing any of its
+// other fields, as GitHub refuses changes to archived repositories.
+func (r *userRepository) validateArchiving(info gitprovider.RepositoryInfo) error {
+	if info.Archived == nil || !*info.Archived || r.r.GetArchived() {
+		return nil
+	}
+	desired := r.r
+	repositoryInfoToAPIObj(&info, &desired)
+	desiredSpec := newGithubRepositorySpec(&desired)
+	actualSpec := newGithubRepositorySpec(&r.r)
+	desiredSpec.Archived = actualSpec.Archived
+	forkingChanged := info.AllowForking != nil && !reflect.DeepEqual(info.AllowForking, r.allowForking)
+
+	validator := validation.New("Repository")
+	if !desiredSpec.Equals(actualSpec) || forkingChanged {
+		validator.Append(fmt.Errorf("%w: the repository can't be archived while changing other fields",
+			validation.ErrFieldInvalid), *info.Archived, "Archived")
+	}
+	return validator.Error()
+}
+
 // validateRepositoryAPI validates the apiObj received from the server, to make sure that it is
 // valid for our use.
 func validateRepositoryAPI(apiObj *github.Repository) error {
@@ -182,6 +852,12 @@ func repositoryFromAPI(apiObj *github.Repository) gitprovider.RepositoryInfo {
 	repo := gitprovider.RepositoryInfo{
 		Description:   apiObj.Description,
 		DefaultBranch: apiObj.DefaultBranch,
+		HasIssues:     apiObj.HasIssues,
+		HasWiki:       apiObj.HasWiki,
+		HasProjects:   apiObj.HasProjects,
+		Archived:      apiObj.Archived,
+		// GitHub omits the topics if there are none, which is different from not knowing them
+		Topics: append([]string{}, apiObj.Topics...),
 	}
 	if apiObj.Visibility != nil {
 		repo.Visibility = gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibility(*apiObj.Visibility))
@@ -207,6 +883,21 @@ func repositoryInfoToAPIObj(repo *gitprovider.RepositoryInfo, apiObj *github.Rep
 	if repo.Visibility != nil {
 		apiObj.Visibility = gitprovider.StringVar(string(*repo.Visibility))
 	}
+	if repo.HasIssues != nil {
+		apiObj.HasIssues = repo.HasIssues
+	}
+	if repo.HasWiki != nil {
+		apiObj.HasWiki = repo.HasWiki
+	}
+	if repo.HasProjects != nil {
+		apiObj.HasProjects = repo.HasProjects
+	}
+	if repo.Topics != nil {
+		apiObj.Topics = gitprovider.NormalizeTopics(repo.Topics)
+	}
+	if repo.Archived != nil {
+		apiObj.Archived = repo.Archived
+	}
 }
 
 func applyRepoCreateOptions(apiObj *github.Repository, opts gitprovider.RepositoryCreateOptions) {
@@ -218,13 +909,18 @@ func applyRepoCreateOptions(apiObj *github.Repository, opts gitprovider.Reposito
 
 // This function copies over the fields that are part of create/update requests of a repository
 // i.e. the desired spec of the repository. This allows us to separate "spec" from "status" fields.
+// Fields that GitHub canonicalizes server-side are normalized, see RepositoryInfo.Normalized().
 // See also: https://github.com/google/go-github/blob/master/github/repos.go#L340-L358
 func newGithubRepositorySpec(repo *github.Repository) *githubRepositorySpec {
+	normalized := gitprovider.RepositoryInfo{
+		Description:   repo.Description,
+		DefaultBranch: repo.DefaultBranch,
+	}.Normalized()
 	return &githubRepositorySpec{
 		&github.Repository{
 			// Generic
 			Name:        repo.Name,
-			Description: repo.Description,
+			Description: normalized.Description,
 			Homepage:    repo.Homepage,
 			Private:     repo.Private,
 			Visibility:  repo.Visibility,
@@ -232,10 +928,12 @@ func newGithubRepositorySpec(repo *github.Repository) *githubRepositorySpec {
 			HasProjects: repo.HasProjects,
 			HasWiki:     repo.HasWiki,
 			IsTemplate:  repo.IsTemplate,
+			Topics:      gitprovider.NormalizeTopics(repo.Topics),
 
 			// Update-specific parameters
 			// See: https://docs.github.com/en/rest/reference/repos#update-a-repository
-			DefaultBranch: repo.DefaultBranch,
+			DefaultBranch: normalized.DefaultBranch,
+			Archived:      repo.Archived,
 
 			// Create-specific parameters
 			// See: https://docs.github.com/en/rest/reference/repos#create-an-organization-repository
@@ -260,3 +958,9 @@ type githubRepositorySpec struct {
 func (s *githubRepositorySpec) Equals(other *githubRepositorySpec) bool {
 	return reflect.DeepEqual(s, other)
 }
+
+// repositoryForkingSettings is the subset of a repository object, as returned from
+// "GET /repos/{owner}/{repo}", that go-github doesn't provide a field for (yet).
+type repositoryForkingSettings struct {
+	AllowForking *bool `json:"allow_forking,omitempty"`
+}
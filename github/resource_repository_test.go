@@ -0,0 +1,1045 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// fakeRepoClient is a githubClient that keeps a single repository in memory. Like the
+// real server, it stores the repository JSON-encoded, and only applies set fields on update.
+// Calling any other method than the overridden ones panics.
+type fakeRepoClient struct {
+	githubClient
+
+	// stored is the JSON-encoded repository, or nil if it doesn't exist
+	stored []byte
+	// normalize is optionally applied to the repository before it's stored
+	normalize func(*github.Repository)
+	// updates counts the calls to CreateRepo and UpdateRepo
+	updates int
+	// allowForking is the stored allow_forking field, and forkingUpdates counts the calls
+	// to UpdateRepoForkingSettings
+	allowForking   *bool
+	forkingUpdates int
+	// searchCount is returned by CountSearchIssues
+	searchCount int64
+	// topicUpdates counts the calls to ReplaceRepoTopics
+	topicUpdates int
+	// templateTopics are the topics of the "template" repository
+	templateTopics []string
+}
+
+// errArchived is returned by fakeRepoClient when changing an archived repository.
+var errArchived = errors.New("repository was archived so is read-only")
+
+func (c *fakeRepoClient) store(apiObj *github.Repository) (*github.Repository, error) {
+	if c.normalize != nil {
+		c.normalize(apiObj)
+	}
+	data, err := json.Marshal(apiObj)
+	if err != nil {
+		return nil, err
+	}
+	c.stored = data
+	c.updates++
+	return c.load()
+}
+
+func (c *fakeRepoClient) load() (*github.Repository, error) {
+	apiObj := &github.Repository{}
+	if err := json.Unmarshal(c.stored, apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *fakeRepoClient) GetRepo(_ context.Context, _, repo string) (*github.Repository, error) {
+	if repo == "template" {
+		return &github.Repository{Name: github.String(repo), Topics: c.templateTopics}, nil
+	}
+	if c.stored == nil {
+		return nil, gitprovider.ErrNotFound
+	}
+	return c.load()
+}
+
+func (c *fakeRepoClient) CreateRepo(_ context.Context, _ string, req *github.Repository) (*github.Repository, error) {
+	apiObj := *req
+	apiObj.ID = github.Int64(1)
+	// Like the real server, topics can't be set, nor the repository archived, at creation time
+	apiObj.Topics = nil
+	apiObj.Archived = nil
+	return c.store(&apiObj)
+}
+
+// CreateRepoFromTemplate only knows the "template" repository, whose default branch is "master".
+func (c *fakeRepoClient) CreateRepoFromTemplate(_ context.Context, _, templateRepo string, req *github.TemplateRepoRequest) (*github.Repository, error) {
+	if templateRepo != "template" {
+		return nil, gitprovider.ErrNotFound
+	}
+	return c.store(&github.Repository{
+		ID:            github.Int64(1),
+		Name:          req.Name,
+		Description:   req.Description,
+		Private:       req.Private,
+		DefaultBranch: github.String("master"),
+	})
+}
+
+func (c *fakeRepoClient) UpdateRepo(_ context.Context, _, _ string, req *github.Repository) (*github.Repository, error) {
+	apiObj, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	// Like the real server, archived repositories refuse any change but unarchiving
+	if apiObj.GetArchived() && !reflect.DeepEqual(*req, github.Repository{Archived: github.Bool(false)}) {
+		return nil, errArchived
+	}
+	// PATCH behaviour: only apply the set fields. Like the real server, topics are ignored.
+	patch := *req
+	patch.Topics = nil
+	data, err := json.Marshal(&patch)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, apiObj); err != nil {
+		return nil, err
+	}
+	return c.store(apiObj)
+}
+
+func (c *fakeRepoClient) GetRepoForkingSettings(_ context.Context, _, _ string) (*repositoryForkingSettings, error) {
+	if c.stored == nil {
+		return nil, gitprovider.ErrNotFound
+	}
+	return &repositoryForkingSettings{AllowForking: c.allowForking}, nil
+}
+
+func (c *fakeRepoClient) UpdateRepoForkingSettings(_ context.Context, _, _ string, req *repositoryForkingSettings) (*repositoryForkingSettings, error) {
+	if c.stored == nil {
+		return nil, gitprovider.ErrNotFound
+	}
+	c.allowForking = req.AllowForking
+	c.forkingUpdates++
+	return &repositoryForkingSettings{AllowForking: c.allowForking}, nil
+}
+
+func (c *fakeRepoClient) ReplaceRepoTopics(_ context.Context, _, _ string, topics []string) ([]string, error) {
+	apiObj, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	if apiObj.GetArchived() {
+		return nil, errArchived
+	}
+	// Like the real server, topics are stored in lower case
+	apiObj.Topics = []string{}
+	for _, topic := range topics {
+		apiObj.Topics = append(apiObj.Topics, strings.ToLower(topic))
+	}
+	c.topicUpdates++
+	if _, err := c.store(apiObj); err != nil {
+		return nil, err
+	}
+	// Storing the topics isn't counted as a repository update
+	c.updates--
+	return apiObj.Topics, nil
+}
+
+func (c *fakeRepoClient) CountSearchIssues(_ context.Context, _ string) (int64, error) {
+	return c.searchCount, nil
+}
+
+func newFakeUserRepositoriesClient(c githubClient) *UserRepositoriesClient {
+	return &UserRepositoriesClient{
+		clientContext: &clientContext{c: c, domain: DefaultDomain},
+	}
+}
+
+func TestUserRepositoriesClient_Reconcile_roundtrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+	}{
+		{
+			name:        "ascii",
+			description: "a plain description",
+		},
+		{
+			name:        "emoji",
+			description: "Ships it 🚀 fast, with ünïcödé and 日本語",
+		},
+		{
+			name:        "emoji shortcodes and html-escaped characters",
+			description: ":tada: <b>bold</b> & \"quoted\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fake := &fakeRepoClient{}
+			c := newFakeUserRepositoriesClient(fake)
+			ref := gitprovider.UserRepositoryRef{
+				UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+				RepositoryName: "bar",
+			}
+			req := gitprovider.RepositoryInfo{Description: gitprovider.StringVar(tt.description)}
+
+			// The first pass creates the repository
+			repo, actionTaken, err := c.Reconcile(ctx, ref, req)
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if !actionTaken {
+				t.Fatal("Reconcile() actionTaken = false on create, want true")
+			}
+			if got := *repo.Get().Description; got != tt.description {
+				t.Errorf("Reconcile() description = %q, want %q", got, tt.description)
+			}
+
+			// The second pass must not detect any drift
+			_, actionTaken, err = c.Reconcile(ctx, ref, req)
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if actionTaken {
+				t.Error("Reconcile() actionTaken = true on second pass, want false")
+			}
+			// Neither must reconciling the resource itself
+			if actionTaken, err = repo.Reconcile(ctx); err != nil || actionTaken {
+				t.Errorf("UserRepository.Reconcile() = %v, %v, want false, nil", actionTaken, err)
+			}
+			if fake.updates != 1 {
+				t.Errorf("server got %d writes, want 1", fake.updates)
+			}
+		})
+	}
+}
+
+func TestUserRepositoriesClient_Create_fromTemplate(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeRepoClient{}
+	c := newFakeUserRepositoriesClient(fake)
+	ref := gitprovider.UserRepositoryRef{
+		UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+		RepositoryName: "bar",
+	}
+	templateRef := func(name string) *gitprovider.RepositoryCreateOptions {
+		return &gitprovider.RepositoryCreateOptions{TemplateRepositoryRef: gitprovider.OrgRepositoryRef{
+			OrganizationRef: gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "fluxcd"},
+			RepositoryName:  name,
+		}}
+	}
+	req := gitprovider.RepositoryInfo{
+		Description: gitprovider.StringVar("from a template"),
+		HasWiki:     gitprovider.BoolVar(false),
+	}
+
+	if _, err := c.Create(ctx, ref, req, templateRef("missing")); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Fatalf("Create() error = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+
+	repo, err := c.Create(ctx, ref, req, templateRef("template"))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	// The fields the template endpoint doesn't take are applied with an update, while the
+	// default branch stays the one of the template
+	info := repo.Get()
+	if *info.Description != "from a template" || *info.HasWiki || *info.DefaultBranch != "master" {
+		t.Errorf("Create() = %+v", info)
+	}
+	if fake.updates != 2 {
+		t.Errorf("server got %d writes, want 2", fake.updates)
+	}
+}
+
+func TestUserRepositoriesClient_Create_inheritTopics(t *testing.T) {
+	template := gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "fluxcd"},
+		RepositoryName:  "template",
+	}
+	tests := []struct {
+		name           string
+		templateTopics []string
+		topics         []string
+		opts           *gitprovider.RepositoryCreateOptions
+		want           []string
+		wantUpdates    int
+		expectedErr    error
+	}{
+		{
+			name:           "template topics aren't inherited by default",
+			templateTopics: []string{"flux"},
+			opts:           &gitprovider.RepositoryCreateOptions{TemplateRepositoryRef: template},
+			want:           []string{},
+		},
+		{
+			name:           "template topics inherited",
+			templateTopics: []string{"flux", "GitOps"},
+			opts:           &gitprovider.RepositoryCreateOptions{TemplateRepositoryRef: template, InheritTopics: true},
+			want:           []string{"flux", "gitops"},
+			wantUpdates:    1,
+		},
+		{
+			name:           "template topics merged with the desired ones",
+			templateTopics: []string{"flux", "gitops"},
+			topics:         []string{"demo", "flux"},
+			opts:           &gitprovider.RepositoryCreateOptions{TemplateRepositoryRef: template, InheritTopics: true},
+			want:           []string{"demo", "flux", "gitops"},
+			wantUpdates:    1,
+		},
+		{
+			name: "template without topics",
+			opts: &gitprovider.RepositoryCreateOptions{TemplateRepositoryRef: template, InheritTopics: true},
+			want: []string{},
+		},
+		{
+			name:        "no template to inherit from",
+			opts:        &gitprovider.RepositoryCreateOptions{InheritTopics: true},
+			expectedErr: validation.ErrFieldInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeRepoClient{templateTopics: tt.templateTopics}
+			c := newFakeUserRepositoriesClient(fake)
+			ref := gitprovider.UserRepositoryRef{
+				UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+				RepositoryName: "bar",
+			}
+			repo, err := c.Create(context.Background(), ref, gitprovider.RepositoryInfo{Topics: tt.topics}, tt.opts)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.expectedErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := repo.Get().Topics; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Create() topics = %v, want %v", got, tt.want)
+			}
+			if fake.topicUpdates != tt.wantUpdates {
+				t.Errorf("server got %d topic writes, want %d", fake.topicUpdates, tt.wantUpdates)
+			}
+		})
+	}
+}
+
+func TestUserRepositoriesClient_Reconcile_serverNormalized(t *testing.T) {
+	tests := []struct {
+		name      string
+		normalize func(*github.Repository)
+		req       gitprovider.RepositoryInfo
+	}{
+		{
+			name: "trailing whitespace trimmed from description",
+			normalize: func(r *github.Repository) {
+				if r.Description != nil {
+					r.Description = github.String(strings.TrimRight(*r.Description, " \t\n"))
+				}
+			},
+			req: gitprovider.RepositoryInfo{Description: gitprovider.StringVar("foo bar  \n")},
+		},
+		{
+			name: "default branch lowercased",
+			normalize: func(r *github.Repository) {
+				if r.DefaultBranch != nil {
+					r.DefaultBranch = github.String(strings.ToLower(*r.DefaultBranch))
+				}
+			},
+			req: gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("Main")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fake := &fakeRepoClient{normalize: tt.normalize}
+			c := newFakeUserRepositoriesClient(fake)
+			ref := gitprovider.UserRepositoryRef{
+				UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+				RepositoryName: "bar",
+			}
+
+			repo, _, err := c.Reconcile(ctx, ref, tt.req)
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			// Reconciling again must not loop updating the normalized field
+			for i := 0; i < 3; i++ {
+				_, actionTaken, err := c.Reconcile(ctx, ref, tt.req)
+				if err != nil || actionTaken {
+					t.Fatalf("Reconcile() pass %d = %v, %v, want false, nil", i+2, actionTaken, err)
+				}
+			}
+			// Neither must reconciling the resource with the un-normalized desired state
+			if err := repo.Set(tt.req); err != nil {
+				t.Fatalf("UserRepository.Set() error = %v", err)
+			}
+			if actionTaken, err := repo.Reconcile(ctx); err != nil || actionTaken {
+				t.Errorf("UserRepository.Reconcile() = %v, %v, want false, nil", actionTaken, err)
+			}
+			if fake.updates != 1 {
+				t.Errorf("server got %d writes, want 1", fake.updates)
+			}
+		})
+	}
+}
+
+func TestUserRepositoriesClient_Reconcile_topics(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeRepoClient{}
+	c := newFakeUserRepositoriesClient(fake)
+	ref := gitprovider.UserRepositoryRef{
+		UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+		RepositoryName: "bar",
+	}
+	req := gitprovider.RepositoryInfo{Topics: []string{"Flux", "CD"}}
+
+	// The first pass creates the repository, and sets the sorted, lowercased topics
+	repo, _, err := c.Reconcile(ctx, ref, req)
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if got, want := repo.Get().Topics, []string{"cd", "flux"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Reconcile() topics = %v, want %v", got, want)
+	}
+
+	// The second pass must not detect any drift, regardless of the order of the topics
+	_, actionTaken, err := c.Reconcile(ctx, ref, gitprovider.RepositoryInfo{Topics: []string{"cd", "FLUX"}})
+	if err != nil || actionTaken {
+		t.Fatalf("Reconcile() second pass = %v, %v, want false, nil", actionTaken, err)
+	}
+	// Neither must reconciling the resource with the mixed-case desired state
+	if err := repo.Set(req); err != nil {
+		t.Fatalf("UserRepository.Set() error = %v", err)
+	}
+	if actionTaken, err := repo.Reconcile(ctx); err != nil || actionTaken {
+		t.Errorf("UserRepository.Reconcile() = %v, %v, want false, nil", actionTaken, err)
+	}
+	// Not desiring topics leaves them as-is
+	if _, actionTaken, err := c.Reconcile(ctx, ref, gitprovider.RepositoryInfo{}); err != nil || actionTaken {
+		t.Errorf("Reconcile() without topics = %v, %v, want false, nil", actionTaken, err)
+	}
+	if fake.topicUpdates != 1 {
+		t.Errorf("server got %d topic writes, want 1", fake.topicUpdates)
+	}
+}
+
+func Test_primaryLanguage(t *testing.T) {
+	tests := []struct {
+		name      string
+		languages map[string]int
+		want      string
+	}{
+		{
+			name:      "no languages",
+			languages: map[string]int{},
+			want:      "",
+		},
+		{
+			name:      "largest byte count",
+			languages: map[string]int{"Go": 1200, "Shell": 300, "Makefile": 50},
+			want:      "Go",
+		},
+		{
+			name:      "tie is broken alphabetically",
+			languages: map[string]int{"Python": 100, "Go": 100},
+			want:      "Go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := primaryLanguage(tt.languages); got != tt.want {
+				t.Errorf("primaryLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserRepositoriesClient_Reconcile_featureToggles(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeRepoClient{}
+	c := newFakeUserRepositoriesClient(fake)
+	ref := gitprovider.UserRepositoryRef{
+		UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+		RepositoryName: "bar",
+	}
+
+	if _, _, err := c.Reconcile(ctx, ref, gitprovider.RepositoryInfo{}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	// Disabling the wiki and issues must be detected as drift
+	req := gitprovider.RepositoryInfo{
+		HasIssues: gitprovider.BoolVar(false),
+		HasWiki:   gitprovider.BoolVar(false),
+	}
+	repo, actionTaken, err := c.Reconcile(ctx, ref, req)
+	if err != nil || !actionTaken {
+		t.Fatalf("Reconcile() = %v, %v, want true, nil", actionTaken, err)
+	}
+	info := repo.Get()
+	if *info.HasIssues || *info.HasWiki || !*info.HasProjects {
+		t.Errorf("Reconcile() HasIssues, HasWiki, HasProjects = %v, %v, %v, want false, false, true",
+			*info.HasIssues, *info.HasWiki, *info.HasProjects)
+	}
+	// After which the state is stable
+	if _, actionTaken, err = c.Reconcile(ctx, ref, req); err != nil || actionTaken {
+		t.Errorf("Reconcile() = %v, %v, want false, nil", actionTaken, err)
+	}
+}
+
+func TestUserRepositoriesClient_Reconcile_allowForking(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeRepoClient{allowForking: gitprovider.BoolVar(true)}
+	c := newFakeUserRepositoriesClient(fake)
+	ref := gitprovider.UserRepositoryRef{
+		UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+		RepositoryName: "bar",
+	}
+
+	// Forking can't be disallowed for public repositories
+	publicReq := gitprovider.RepositoryInfo{
+		Visibility:   gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic),
+		AllowForking: gitprovider.BoolVar(false),
+	}
+	if _, _, err := c.Reconcile(ctx, ref, publicReq); !errors.Is(err, validation.ErrFieldInvalid) {
+		t.Fatalf("Reconcile() error = %v, want %v", err, validation.ErrFieldInvalid)
+	}
+
+	// The first pass creates the repository, and disallows forking
+	req := gitprovider.RepositoryInfo{AllowForking: gitprovider.BoolVar(false)}
+	repo, _, err := c.Reconcile(ctx, ref, req)
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if got := repo.Get().AllowForking; got == nil || *got {
+		t.Errorf("Reconcile() AllowForking = %v, want false", got)
+	}
+
+	// Leaving AllowForking unset must not detect any drift
+	if _, actionTaken, err := c.Reconcile(ctx, ref, gitprovider.RepositoryInfo{}); err != nil || actionTaken {
+		t.Errorf("Reconcile() = %v, %v, want false, nil", actionTaken, err)
+	}
+
+	// Drift is detected and fixed, also when no other field changed
+	fake.allowForking = gitprovider.BoolVar(true)
+	if _, actionTaken, err := c.Reconcile(ctx, ref, req); err != nil || !actionTaken {
+		t.Errorf("Reconcile() = %v, %v, want true, nil", actionTaken, err)
+	}
+	if fake.allowForking == nil || *fake.allowForking {
+		t.Errorf("server AllowForking = %v, want false", fake.allowForking)
+	}
+	if actionTaken, err := repo.Reconcile(ctx); err != nil || actionTaken {
+		t.Errorf("UserRepository.Reconcile() = %v, %v, want false, nil", actionTaken, err)
+	}
+	if fake.forkingUpdates != 2 {
+		t.Errorf("server got %d forking updates, want 2", fake.forkingUpdates)
+	}
+}
+
+func TestUserRepositoriesClient_Reconcile_internal(t *testing.T) {
+	ctx := context.Background()
+	req := gitprovider.RepositoryInfo{
+		Visibility: gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityInternal),
+	}
+
+	// Internal repositories don't exist in github.com
+	fake := &fakeRepoClient{}
+	c := newFakeUserRepositoriesClient(fake)
+	ref := gitprovider.UserRepositoryRef{
+		UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+		RepositoryName: "bar",
+	}
+	if _, _, err := c.Reconcile(ctx, ref, req); !errors.Is(err, gitprovider.ErrNoProviderSupport) {
+		t.Fatalf("Reconcile() error = %v, want %v", err, gitprovider.ErrNoProviderSupport)
+	}
+	if fake.stored != nil {
+		t.Fatal("Reconcile() created the repository, want it to be refused")
+	}
+	repo, _, err := c.Reconcile(ctx, ref, gitprovider.RepositoryInfo{})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := repo.Set(req); !errors.Is(err, gitprovider.ErrNoProviderSupport) {
+		t.Errorf("Set() error = %v, want %v", err, gitprovider.ErrNoProviderSupport)
+	}
+
+	// But they do in GitHub Enterprise
+	const enterpriseDomain = "github.example.com"
+	c = &UserRepositoriesClient{
+		clientContext: &clientContext{c: &fakeRepoClient{}, domain: enterpriseDomain},
+	}
+	ref.Domain = enterpriseDomain
+	repo, _, err = c.Reconcile(ctx, ref, req)
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if got := *repo.Get().Visibility; got != gitprovider.RepositoryVisibilityInternal {
+		t.Errorf("Reconcile() visibility = %q, want %q", got, gitprovider.RepositoryVisibilityInternal)
+	}
+	if _, actionTaken, err := c.Reconcile(ctx, ref, req); err != nil || actionTaken {
+		t.Errorf("Reconcile() = %v, %v, want false, nil", actionTaken, err)
+	}
+}
+
+func TestUserRepositoriesClient_Reconcile_archived(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeRepoClient{}
+	c := newFakeUserRepositoriesClient(fake)
+	ref := gitprovider.UserRepositoryRef{
+		UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+		RepositoryName: "bar",
+	}
+
+	// The first pass creates the repository, sets the topics, and archives it last
+	req := gitprovider.RepositoryInfo{Topics: []string{"flux"}, Archived: gitprovider.BoolVar(true)}
+	repo, _, err := c.Reconcile(ctx, ref, req)
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if got := repo.Get(); got.Archived == nil || !*got.Archived || !reflect.DeepEqual(got.Topics, []string{"flux"}) {
+		t.Errorf("Reconcile() Archived, Topics = %v, %v, want true, [flux]", got.Archived, got.Topics)
+	}
+	// Not desiring Archived leaves it as-is
+	if _, actionTaken, err := c.Reconcile(ctx, ref, gitprovider.RepositoryInfo{}); err != nil || actionTaken {
+		t.Errorf("Reconcile() without Archived = %v, %v, want false, nil", actionTaken, err)
+	}
+
+	// Unarchiving happens before the other changes, which the archived repository would refuse
+	req = gitprovider.RepositoryInfo{
+		Description: gitprovider.StringVar("unarchived"),
+		Topics:      []string{"flux", "cd"},
+		Archived:    gitprovider.BoolVar(false),
+	}
+	repo, actionTaken, err := c.Reconcile(ctx, ref, req)
+	if err != nil || !actionTaken {
+		t.Fatalf("Reconcile() = %v, %v, want true, nil", actionTaken, err)
+	}
+	got := repo.Get()
+	if *got.Archived || *got.Description != "unarchived" || !reflect.DeepEqual(got.Topics, []string{"cd", "flux"}) {
+		t.Errorf("Reconcile() Archived, Description, Topics = %v, %q, %v, want false, \"unarchived\", [cd flux]",
+			*got.Archived, *got.Description, got.Topics)
+	}
+	if actionTaken, err := repo.Reconcile(ctx); err != nil || actionTaken {
+		t.Errorf("UserRepository.Reconcile() = %v, %v, want false, nil", actionTaken, err)
+	}
+}
+
+func TestUserRepository_Set_archived(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeRepoClient{}
+	c := newFakeUserRepositoriesClient(fake)
+	ref := gitprovider.UserRepositoryRef{
+		UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+		RepositoryName: "bar",
+	}
+	repo, _, err := c.Reconcile(ctx, ref, gitprovider.RepositoryInfo{Description: gitprovider.StringVar("foo")})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	// Archiving while changing other fields is invalid, and leaves the object as-is
+	info := repo.Get()
+	info.Description = gitprovider.StringVar("bar")
+	info.Archived = gitprovider.BoolVar(true)
+	if err := repo.Set(info); !errors.Is(err, validation.ErrFieldInvalid) {
+		t.Fatalf("Set() error = %v, want %v", err, validation.ErrFieldInvalid)
+	}
+	if got := repo.Get(); *got.Description != "foo" || got.Archived != nil {
+		t.Errorf("Set() Description, Archived = %q, %v, want \"foo\", nil", *got.Description, got.Archived)
+	}
+
+	// Archiving alone is valid
+	info = repo.Get()
+	info.Archived = gitprovider.BoolVar(true)
+	if err := repo.Set(info); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := repo.Update(ctx); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got := repo.Get().Archived; got == nil || !*got {
+		t.Errorf("Update() Archived = %v, want true", got)
+	}
+
+	// Unarchiving while changing other fields is valid, as the repository is unarchived first
+	info = repo.Get()
+	info.Description = gitprovider.StringVar("bar")
+	info.Archived = gitprovider.BoolVar(false)
+	if err := repo.Set(info); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := repo.Update(ctx); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got := repo.Get(); *got.Archived || *got.Description != "bar" {
+		t.Errorf("Update() Archived, Description = %v, %q, want false, \"bar\"", *got.Archived, *got.Description)
+	}
+}
+
+func TestUserRepository_CountOpenIssues(t *testing.T) {
+	tests := []struct {
+		name         string
+		repo         github.Repository
+		pullRequests int64
+		want         int64
+	}{
+		{
+			name:         "pull requests are subtracted",
+			repo:         github.Repository{OpenIssuesCount: github.Int(5)},
+			pullRequests: 2,
+			want:         3,
+		},
+		{
+			name:         "lagging search index",
+			repo:         github.Repository{OpenIssuesCount: github.Int(1)},
+			pullRequests: 2,
+			want:         0,
+		},
+		{
+			name:         "issue tracker disabled",
+			repo:         github.Repository{OpenIssuesCount: github.Int(2), HasIssues: github.Bool(false)},
+			pullRequests: 2,
+			want:         0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeRepoClient{searchCount: tt.pullRequests}
+			if _, err := fake.store(&tt.repo); err != nil {
+				t.Fatal(err)
+			}
+			ref := gitprovider.UserRepositoryRef{
+				UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+				RepositoryName: "bar",
+			}
+			repo := newUserRepository(&clientContext{c: fake, domain: DefaultDomain}, &tt.repo, ref)
+			got, err := repo.CountOpenIssues(context.Background())
+			if err != nil {
+				t.Fatalf("CountOpenIssues() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CountOpenIssues() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakePermissionsClient is a githubClient returning fixed installations and repository
+// permissions, counting the requests.
+type fakePermissionsClient struct {
+	githubClient
+
+	installations   []*installation
+	repoPermissions map[string]bool
+	requests        int
+}
+
+func (c *fakePermissionsClient) ListRepoInstallations(_ context.Context, _, _ string) ([]*installation, error) {
+	c.requests++
+	return c.installations, nil
+}
+
+func (c *fakePermissionsClient) GetRepo(_ context.Context, _, _ string) (*github.Repository, error) {
+	c.requests++
+	return &github.Repository{Permissions: &c.repoPermissions}, nil
+}
+
+func TestUserRepository_EffectivePermissions(t *testing.T) {
+	tests := []struct {
+		name            string
+		installations   []*installation
+		repoPermissions map[string]bool
+		want            map[string]string
+	}{
+		{
+			name: "installation permissions within the user's access",
+			installations: []*installation{
+				{Permissions: map[string]string{"contents": "write", "metadata": "read"}},
+			},
+			repoPermissions: map[string]bool{"admin": false, "push": true, "pull": true},
+			want:            map[string]string{"contents": "write", "metadata": "read"},
+		},
+		{
+			name: "capped by the user's access",
+			installations: []*installation{
+				{Permissions: map[string]string{"contents": "write", "administration": "admin", "metadata": "read"}},
+			},
+			repoPermissions: map[string]bool{"admin": false, "push": false, "pull": true},
+			want:            map[string]string{"contents": "read", "administration": "read", "metadata": "read"},
+		},
+		{
+			name: "highest level of multiple installations",
+			installations: []*installation{
+				{Permissions: map[string]string{"contents": "read", "issues": "write"}},
+				{Permissions: map[string]string{"contents": "write", "issues": "read"}},
+			},
+			repoPermissions: map[string]bool{"admin": true, "push": true, "pull": true},
+			want:            map[string]string{"contents": "write", "issues": "write"},
+		},
+		{
+			name:            "no installations",
+			repoPermissions: map[string]bool{"admin": true, "push": true, "pull": true},
+			want:            map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fake := &fakePermissionsClient{installations: tt.installations, repoPermissions: tt.repoPermissions}
+			ref := gitprovider.UserRepositoryRef{
+				UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+				RepositoryName: "bar",
+			}
+			repo := newUserRepository(&clientContext{c: fake, domain: DefaultDomain}, &github.Repository{}, ref)
+
+			got, err := repo.EffectivePermissions(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EffectivePermissions() = %v, want %v", got, tt.want)
+			}
+
+			// The second call must be served from the cache, unaffected by changes to the first result
+			got["contents"] = "admin"
+			requests := fake.requests
+			got, err = repo.EffectivePermissions(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cached EffectivePermissions() = %v, want %v", got, tt.want)
+			}
+			if fake.requests != requests {
+				t.Errorf("got %d requests for the cached permissions, want none", fake.requests-requests)
+			}
+		})
+	}
+}
+
+// fakeSubscriptionClient is a githubClient that keeps the repository subscription of the
+// authenticated user in memory, recording the requests that change it.
+type fakeSubscriptionClient struct {
+	githubClient
+
+	subscription *github.Subscription
+	changes      []string
+}
+
+func (c *fakeSubscriptionClient) GetRepoSubscription(_ context.Context, _, _ string) (*github.Subscription, error) {
+	return c.subscription, nil
+}
+
+func (c *fakeSubscriptionClient) SetRepoSubscription(_ context.Context, _, _ string, req *github.Subscription) error {
+	c.changes = append(c.changes, "set")
+	c.subscription = req
+	return nil
+}
+
+func (c *fakeSubscriptionClient) DeleteRepoSubscription(_ context.Context, _, _ string) error {
+	c.changes = append(c.changes, "delete")
+	c.subscription = nil
+	return nil
+}
+
+func TestUserRepository_SetSubscription(t *testing.T) {
+	watching := &github.Subscription{Subscribed: github.Bool(true), Ignored: github.Bool(false)}
+	ignoring := &github.Subscription{Subscribed: github.Bool(false), Ignored: github.Bool(true)}
+	tests := []struct {
+		name        string
+		actual      *github.Subscription
+		level       gitprovider.SubscriptionLevel
+		wantChanges []string
+		wantErr     error
+		want        gitprovider.SubscriptionLevel
+	}{
+		{
+			name:        "watch",
+			level:       gitprovider.SubscriptionLevelAll,
+			wantChanges: []string{"set"},
+			want:        gitprovider.SubscriptionLevelAll,
+		},
+		{
+			name:        "ignore a watched repository",
+			actual:      watching,
+			level:       gitprovider.SubscriptionLevelIgnore,
+			wantChanges: []string{"set"},
+			want:        gitprovider.SubscriptionLevelIgnore,
+		},
+		{
+			name:        "stop ignoring",
+			actual:      ignoring,
+			level:       gitprovider.SubscriptionLevelParticipating,
+			wantChanges: []string{"delete"},
+			want:        gitprovider.SubscriptionLevelParticipating,
+		},
+		{
+			name:   "already ignored",
+			actual: ignoring,
+			level:  gitprovider.SubscriptionLevelIgnore,
+			want:   gitprovider.SubscriptionLevelIgnore,
+		},
+		{
+			name:  "already not watching",
+			level: gitprovider.SubscriptionLevelParticipating,
+			want:  gitprovider.SubscriptionLevelParticipating,
+		},
+		{
+			name:    "invalid level",
+			actual:  watching,
+			level:   gitprovider.SubscriptionLevel("mention"),
+			wantErr: validation.ErrFieldEnumInvalid,
+			want:    gitprovider.SubscriptionLevelAll,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeSubscriptionClient{subscription: tt.actual}
+			ref := gitprovider.UserRepositoryRef{
+				UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+				RepositoryName: "bar",
+			}
+			repo := newUserRepository(&clientContext{c: fake, domain: DefaultDomain}, &github.Repository{}, ref)
+
+			err := repo.SetSubscription(context.Background(), gitprovider.SubscriptionInfo{Level: tt.level})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetSubscription() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(fake.changes, tt.wantChanges) {
+				t.Errorf("changes = %v, want %v", fake.changes, tt.wantChanges)
+			}
+			if got := subscriptionFromAPI(fake.subscription).Level; got != tt.want {
+				t.Errorf("subscription level = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeHealthFilesClient is a githubClient serving the files of the default branch ("main") from
+// memory, and recording the commits made on top of it.
+type fakeHealthFilesClient struct {
+	*fakeGitDataClient
+
+	files   map[string]string
+	metrics *github.CommunityHealthMetrics
+}
+
+func (c *fakeHealthFilesClient) GetRepoFileContent(_ context.Context, _, _, path string) (string, error) {
+	content, ok := c.files[path]
+	if !ok {
+		return "", gitprovider.ErrNotFound
+	}
+	return content, nil
+}
+
+func (c *fakeHealthFilesClient) GetCommunityHealthMetrics(_ context.Context, _, _ string) (*github.CommunityHealthMetrics, error) {
+	return c.metrics, nil
+}
+
+func newFakeHealthFilesRepository(fake *fakeHealthFilesClient) gitprovider.UserRepository {
+	ref := gitprovider.UserRepositoryRef{
+		UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+		RepositoryName: "bar",
+	}
+	return newUserRepository(&clientContext{c: fake, domain: DefaultDomain}, &github.Repository{DefaultBranch: github.String("main")}, ref)
+}
+
+func TestUserRepository_CommunityHealthFiles(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeHealthFilesClient{
+		fakeGitDataClient: &fakeGitDataClient{},
+		files:             map[string]string{"CONTRIBUTING.md": "Send patches."},
+	}
+	repo := newFakeHealthFilesRepository(fake)
+
+	content, err := repo.GetCommunityHealthFile(ctx, gitprovider.CommunityHealthFileContributing)
+	if err != nil || content != "Send patches." {
+		t.Errorf("GetCommunityHealthFile() = %q, %v, want %q", content, err, "Send patches.")
+	}
+	if _, err := repo.GetCommunityHealthFile(ctx, gitprovider.CommunityHealthFileSecurity); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("GetCommunityHealthFile() error = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+	if _, err := repo.GetCommunityHealthFile(ctx, gitprovider.CommunityHealthFile("README.md")); !errors.Is(err, validation.ErrFieldEnumInvalid) {
+		t.Errorf("GetCommunityHealthFile() error = %v, want %v", err, validation.ErrFieldEnumInvalid)
+	}
+
+	// Setting the actual content doesn't commit anything
+	if err := repo.SetCommunityHealthFile(ctx, gitprovider.CommunityHealthFileContributing, "Send patches."); err != nil {
+		t.Fatalf("SetCommunityHealthFile() error = %v", err)
+	}
+	if fake.commit != nil {
+		t.Fatalf("SetCommunityHealthFile() committed %v, want no commit", fake.commit)
+	}
+
+	tests := []struct {
+		name     string
+		file     gitprovider.CommunityHealthFile
+		wantPath string
+	}{
+		{name: "update in place", file: gitprovider.CommunityHealthFileContributing, wantPath: "CONTRIBUTING.md"},
+		{name: "create in .github", file: gitprovider.CommunityHealthFileSecurity, wantPath: ".github/SECURITY.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.SetCommunityHealthFile(ctx, tt.file, "New content."); err != nil {
+				t.Fatalf("SetCommunityHealthFile() error = %v", err)
+			}
+			// Only the health file is changed, on top of the default branch
+			if len(fake.entries) != 1 || fake.entries[0].GetPath() != tt.wantPath {
+				t.Errorf("SetCommunityHealthFile() tree entries = %v, want only %q", fake.entries, tt.wantPath)
+			}
+			if fake.ref.GetRef() != "refs/heads/main" {
+				t.Errorf("SetCommunityHealthFile() ref = %v, want the default branch", fake.ref)
+			}
+		})
+	}
+}
+
+func TestUserRepository_CommunityHealthScore(t *testing.T) {
+	fake := &fakeHealthFilesClient{
+		files: map[string]string{"docs/SECURITY.md": "Report to security@example.com."},
+		metrics: &github.CommunityHealthMetrics{
+			HealthPercentage: github.Int(71),
+			Files:            &github.CommunityHealthFiles{Contributing: &github.Metric{}},
+		},
+	}
+	repo := newFakeHealthFilesRepository(fake)
+
+	got, err := repo.CommunityHealthScore(context.Background())
+	if err != nil {
+		t.Fatalf("CommunityHealthScore() error = %v", err)
+	}
+	want := gitprovider.CommunityHealthInfo{
+		HealthPercentage: 71,
+		Files:            []gitprovider.CommunityHealthFile{gitprovider.CommunityHealthFileContributing, gitprovider.CommunityHealthFileSecurity},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CommunityHealthScore() = %+v, want %+v", got, want)
+	}
+}
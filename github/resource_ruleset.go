@@ -0,0 +1,495 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+const (
+	rulesetTargetBranch       = "branch"
+	rulesetEnforcementActive  = "active"
+	rulesetEnforcementOff     = "disabled"
+	rulesetRuleTypeMergeQueue = "merge_queue"
+	// rulesetRuleTypeRequiredSignatures requires commits to have verified signatures. It has no parameters.
+	rulesetRuleTypeRequiredSignatures = "required_signatures"
+	// rulesetRuleTypeRequiredDeployments requires deployments to environments to succeed before updating
+	// the matching refs. Its parameters are requiredDeploymentsParameters.
+	rulesetRuleTypeRequiredDeployments = "required_deployments"
+	// rulesetRuleTypeUpdate only allows the bypass actors to update the matching refs. Its parameters are optional.
+	rulesetRuleTypeUpdate = "update"
+	// rulesetActorTypeTeam is the type of the bypass actors that are teams, referred to by their ID.
+	rulesetActorTypeTeam = "Team"
+	// rulesetBypassModeAlways allows the bypass actor to bypass the rules both when pushing and merging.
+	rulesetBypassModeAlways = "always"
+)
+
+// ruleset is a repository ruleset object, as returned from "GET /repos/{owner}/{repo}/rulesets/{ruleset_id}".
+// go-github doesn't support rulesets yet. Rules are only included when getting a single ruleset.
+type ruleset struct {
+	ID          *int64             `json:"id,omitempty"`
+	Name        *string            `json:"name,omitempty"`
+	Target      *string            `json:"target,omitempty"`
+	Enforcement *string            `json:"enforcement,omitempty"`
+	Conditions  *rulesetConditions `json:"conditions,omitempty"`
+	// Rules isn't omitted if empty, as GitHub keeps the rules of a ruleset if they're not given
+	Rules []*rulesetRule `json:"rules"`
+	// BypassActors is a pointer, as GitHub keeps the bypass actors of a ruleset if they're not given,
+	// while an empty list removes them
+	BypassActors *[]*rulesetBypassActor `json:"bypass_actors,omitempty"`
+}
+
+// rulesetBypassActor is an actor that can bypass the rules of a ruleset.
+type rulesetBypassActor struct {
+	ActorID    *int64 `json:"actor_id,omitempty"`
+	ActorType  string `json:"actor_type"`
+	BypassMode string `json:"bypass_mode,omitempty"`
+}
+
+// rulesetTeams maps the slugs of the teams of an organization to their IDs, as rulesets refer to
+// teams by their IDs.
+type rulesetTeams map[string]int64
+
+// slug returns the slug of the team with the given ID. Unknown teams, e.g. deleted ones, are named
+// by their ID.
+func (t rulesetTeams) slug(id int64) string {
+	for slug, teamID := range t {
+		if teamID == id {
+			return slug
+		}
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+// ids returns the IDs of the teams with the given slugs. ErrNotFound is returned, listing the
+// missing teams, if any of them doesn't exist.
+func (t rulesetTeams) ids(slugs []string) ([]int64, error) {
+	ids := make([]int64, 0, len(slugs))
+	missing := []string{}
+	for _, slug := range slugs {
+		id, ok := t[slug]
+		if !ok {
+			missing = append(missing, slug)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(missing) != 0 {
+		return nil, fmt.Errorf("%w: teams %s", gitprovider.ErrNotFound, strings.Join(missing, ", "))
+	}
+	return ids, nil
+}
+
+// hasTeamBypassActors returns whether any team can bypass the rules of the ruleset.
+func hasTeamBypassActors(apiObj *ruleset) bool {
+	if apiObj == nil || apiObj.BypassActors == nil {
+		return false
+	}
+	for _, actor := range *apiObj.BypassActors {
+		if actor.ActorType == rulesetActorTypeTeam {
+			return true
+		}
+	}
+	return false
+}
+
+type rulesetConditions struct {
+	RefName *rulesetRefNameCondition `json:"ref_name,omitempty"`
+}
+
+type rulesetRefNameCondition struct {
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+}
+
+// rulesetRule is a rule of a ruleset, whose parameters depend on the type of the rule.
+type rulesetRule struct {
+	Type       string          `json:"type"`
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+}
+
+// mergeQueueParameters are the parameters of a "merge_queue" rule. All fields are required by GitHub.
+type mergeQueueParameters struct {
+	CheckResponseTimeoutMinutes  int    `json:"check_response_timeout_minutes"`
+	GroupingStrategy             string `json:"grouping_strategy"`
+	MaxEntriesToBuild            int    `json:"max_entries_to_build"`
+	MaxEntriesToMerge            int    `json:"max_entries_to_merge"`
+	MergeMethod                  string `json:"merge_method"`
+	MinEntriesToMerge            int    `json:"min_entries_to_merge"`
+	MinEntriesToMergeWaitMinutes int    `json:"min_entries_to_merge_wait_minutes"`
+}
+
+// requiredDeploymentsParameters are the parameters of a "required_deployments" rule.
+type requiredDeploymentsParameters struct {
+	RequiredDeploymentEnvironments []string `json:"required_deployment_environments"`
+}
+
+// defaultMergeQueueParameters returns the parameters GitHub uses by default for new merge queues.
+func defaultMergeQueueParameters() mergeQueueParameters {
+	return mergeQueueParameters{
+		CheckResponseTimeoutMinutes:  60,
+		GroupingStrategy:             "ALLGREEN",
+		MaxEntriesToBuild:            5,
+		MaxEntriesToMerge:            5,
+		MergeMethod:                  "MERGE",
+		MinEntriesToMerge:            1,
+		MinEntriesToMergeWaitMinutes: 5,
+	}
+}
+
+// validateRulesetAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateRulesetAPI(apiObj *ruleset) error {
+	return validateAPIObject("GitHub.Ruleset", func(validator validation.Validator) {
+		if apiObj.ID == nil {
+			validator.Required("ID")
+		}
+		if apiObj.Name == nil {
+			validator.Required("Name")
+		}
+		// Make sure the parameters of the modelled rules can be decoded
+		if rule := rulesetRuleOfType(apiObj, rulesetRuleTypeRequiredDeployments); rule != nil {
+			if err := json.Unmarshal(rule.Parameters, &requiredDeploymentsParameters{}); err != nil {
+				validator.Invalid(string(rule.Parameters), "Rules")
+			}
+		}
+	})
+}
+
+// newRepositoryRuleset returns the ruleset of apiObj. teams must contain the teams the ruleset
+// refers to, it may be nil if it doesn't refer to any.
+func newRepositoryRuleset(c *RulesetClient, apiObj *ruleset, teams rulesetTeams) *repositoryRuleset {
+	return &repositoryRuleset{
+		rs:    *apiObj,
+		c:     c,
+		teams: teams,
+	}
+}
+
+var _ gitprovider.Ruleset = &repositoryRuleset{}
+
+type repositoryRuleset struct {
+	rs    ruleset
+	c     *RulesetClient
+	teams rulesetTeams
+}
+
+func (r *repositoryRuleset) Get() gitprovider.RulesetInfo {
+	return rulesetFromAPI(&r.rs, r.teams)
+}
+
+func (r *repositoryRuleset) Set(info gitprovider.RulesetInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	return rulesetInfoToAPIObj(&info, &r.rs, r.teams)
+}
+
+func (r *repositoryRuleset) APIObject() interface{} {
+	return &r.rs
+}
+
+func (r *repositoryRuleset) Repository() gitprovider.RepositoryRef {
+	return r.c.ref
+}
+
+// Update will apply the desired state in this object to the server.
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (r *repositoryRuleset) Update(ctx context.Context) error {
+	// We can use the same ruleset ID that we got from the GET calls. Make sure it's non-nil.
+	// This _should never_ happen, but just check for it anyways to avoid panicing.
+	if r.rs.ID == nil {
+		return fmt.Errorf("didn't expect ID to be nil: %w", gitprovider.ErrUnexpectedEvent)
+	}
+
+	// PUT /repos/{owner}/{repo}/rulesets/{ruleset_id}
+	apiObj, err := r.c.c.UpdateRepoRuleset(ctx, r.c.ref.GetIdentity(), r.c.ref.GetRepository(), &r.rs)
+	if err != nil {
+		return err
+	}
+	r.rs = *apiObj
+	return nil
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (r *repositoryRuleset) Reconcile(ctx context.Context) (bool, error) {
+	actual, err := r.c.get(ctx, r.Get().Name)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return true, r.createIntoSelf(ctx)
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// If the desired matches the actual state, do nothing
+	if r.Get().Equals(actual.Get()) {
+		return false, nil
+	}
+	// Update the actual ruleset, as the ID of this object might not be set
+	r.rs.ID = actual.rs.ID
+	return true, r.Update(ctx)
+}
+
+func (r *repositoryRuleset) createIntoSelf(ctx context.Context) error {
+	// POST /repos/{owner}/{repo}/rulesets
+	apiObj, err := r.c.c.CreateRepoRuleset(ctx, r.c.ref.GetIdentity(), r.c.ref.GetRepository(), &r.rs)
+	if err != nil {
+		return err
+	}
+	r.rs = *apiObj
+	return nil
+}
+
+func rulesetFromAPI(apiObj *ruleset, teams rulesetTeams) gitprovider.RulesetInfo {
+	info := gitprovider.RulesetInfo{
+		Name:               *apiObj.Name,
+		Include:            []string{},
+		Exclude:            []string{},
+		RequiredSignatures: rulesetRuleOfType(apiObj, rulesetRuleTypeRequiredSignatures) != nil,
+		RestrictMerges:     rulesetRuleOfType(apiObj, rulesetRuleTypeUpdate) != nil,
+		MergeTeams:         []string{},
+	}
+	info.RequiredDeploymentEnvironments = []string{}
+	if rule := rulesetRuleOfType(apiObj, rulesetRuleTypeRequiredDeployments); rule != nil {
+		// The parameters are validated at validateRulesetAPI
+		params := requiredDeploymentsParameters{}
+		_ = json.Unmarshal(rule.Parameters, &params)
+		info.RequiredDeploymentEnvironments = append(info.RequiredDeploymentEnvironments, params.RequiredDeploymentEnvironments...)
+		sort.Strings(info.RequiredDeploymentEnvironments)
+	}
+	if apiObj.Target != nil {
+		info.Target = gitprovider.RulesetTargetVar(gitprovider.RulesetTarget(*apiObj.Target))
+	}
+	if apiObj.Enforcement != nil {
+		info.Enforcement = gitprovider.RulesetEnforcementVar(gitprovider.RulesetEnforcement(*apiObj.Enforcement))
+	}
+	if apiObj.Conditions != nil && apiObj.Conditions.RefName != nil {
+		info.Include = append(info.Include, apiObj.Conditions.RefName.Include...)
+		info.Exclude = append(info.Exclude, apiObj.Conditions.RefName.Exclude...)
+	}
+	if apiObj.BypassActors != nil {
+		for _, actor := range *apiObj.BypassActors {
+			if actor.ActorType == rulesetActorTypeTeam && actor.ActorID != nil {
+				info.MergeTeams = append(info.MergeTeams, teams.slug(*actor.ActorID))
+			}
+		}
+		sort.Strings(info.MergeTeams)
+	}
+	return info
+}
+
+func rulesetToAPI(info *gitprovider.RulesetInfo, teams rulesetTeams) (*ruleset, error) {
+	apiObj := &ruleset{}
+	if err := rulesetInfoToAPIObj(info, apiObj, teams); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+// rulesetInfoToAPIObj applies info to apiObj. Rules not modelled in RulesetInfo, e.g. the
+// merge queue, and bypass actors that aren't teams are kept as-is. ErrNotFound is returned if
+// any of the merge teams isn't in teams.
+func rulesetInfoToAPIObj(info *gitprovider.RulesetInfo, apiObj *ruleset, teams rulesetTeams) error {
+	// Resolve the teams first, so that apiObj is left as-is if any of them is missing
+	teamIDs, err := teams.ids(info.MergeTeams)
+	if err != nil {
+		return err
+	}
+
+	// Required fields, we assume info is validated, and hence these are set
+	apiObj.Name = gitprovider.StringVar(info.Name)
+	exclude := info.Exclude
+	if exclude == nil {
+		exclude = []string{}
+	}
+	apiObj.Conditions = &rulesetConditions{
+		RefName: &rulesetRefNameCondition{Include: info.Include, Exclude: exclude},
+	}
+	// optional fields
+	if info.Target != nil {
+		apiObj.Target = gitprovider.StringVar(string(*info.Target))
+	}
+	if info.Enforcement != nil {
+		apiObj.Enforcement = gitprovider.StringVar(string(*info.Enforcement))
+	}
+
+	// Add or remove the required signatures, required deployments and update rules. The parameters
+	// of an existing update rule are kept.
+	updateRule := rulesetRuleOfType(apiObj, rulesetRuleTypeUpdate)
+	rules := make([]*rulesetRule, 0, len(apiObj.Rules)+3)
+	for _, rule := range apiObj.Rules {
+		switch rule.Type {
+		case rulesetRuleTypeRequiredSignatures, rulesetRuleTypeRequiredDeployments, rulesetRuleTypeUpdate:
+		default:
+			rules = append(rules, rule)
+		}
+	}
+	if info.RequiredSignatures {
+		rules = append(rules, &rulesetRule{Type: rulesetRuleTypeRequiredSignatures})
+	}
+	if len(info.RequiredDeploymentEnvironments) != 0 {
+		data, err := json.Marshal(requiredDeploymentsParameters{RequiredDeploymentEnvironments: info.RequiredDeploymentEnvironments})
+		if err != nil {
+			return err
+		}
+		rules = append(rules, &rulesetRule{Type: rulesetRuleTypeRequiredDeployments, Parameters: data})
+	}
+	if info.RestrictMerges {
+		if updateRule == nil {
+			updateRule = &rulesetRule{Type: rulesetRuleTypeUpdate}
+		}
+		rules = append(rules, updateRule)
+	}
+	apiObj.Rules = rules
+
+	// Replace the teams that can bypass the rules, leaving the bypass actors as-is if there are
+	// neither teams to add nor to remove
+	if apiObj.BypassActors == nil && len(teamIDs) == 0 {
+		return nil
+	}
+	actors := []*rulesetBypassActor{}
+	if apiObj.BypassActors != nil {
+		for _, actor := range *apiObj.BypassActors {
+			if actor.ActorType != rulesetActorTypeTeam {
+				actors = append(actors, actor)
+			}
+		}
+	}
+	for _, id := range teamIDs {
+		actors = append(actors, &rulesetBypassActor{
+			ActorID:    github.Int64(id),
+			ActorType:  rulesetActorTypeTeam,
+			BypassMode: rulesetBypassModeAlways,
+		})
+	}
+	apiObj.BypassActors = &actors
+	return nil
+}
+
+// mergeQueueRulesetName returns the name of the ruleset that manages the merge queue of branch.
+func mergeQueueRulesetName(branch string) string {
+	return fmt.Sprintf("merge-queue/%s", branch)
+}
+
+// mergeQueueRule returns the merge queue rule of the ruleset, or nil if there is none.
+func mergeQueueRule(apiObj *ruleset) *rulesetRule {
+	return rulesetRuleOfType(apiObj, rulesetRuleTypeMergeQueue)
+}
+
+// rulesetRuleOfType returns the rule of the given type of the ruleset, or nil if there is none.
+func rulesetRuleOfType(apiObj *ruleset, ruleType string) *rulesetRule {
+	for _, rule := range apiObj.Rules {
+		if rule.Type == ruleType {
+			return rule
+		}
+	}
+	return nil
+}
+
+// mergeQueueFromAPI returns the merge queue settings of the ruleset managing the merge queue,
+// which is nil if there is no such ruleset.
+func mergeQueueFromAPI(apiObj *ruleset) (gitprovider.MergeQueueInfo, error) {
+	if apiObj == nil || apiObj.Enforcement == nil || *apiObj.Enforcement != rulesetEnforcementActive {
+		return gitprovider.MergeQueueInfo{}, nil
+	}
+	rule := mergeQueueRule(apiObj)
+	if rule == nil {
+		return gitprovider.MergeQueueInfo{}, nil
+	}
+	params := mergeQueueParameters{}
+	if err := json.Unmarshal(rule.Parameters, &params); err != nil {
+		return gitprovider.MergeQueueInfo{}, validation.NewMultiError(err, gitprovider.ErrInvalidServerData)
+	}
+	return gitprovider.MergeQueueInfo{
+		Enabled:     true,
+		BatchSize:   &params.MaxEntriesToMerge,
+		MergeMethod: gitprovider.MergeQueueMergeMethodVar(gitprovider.MergeQueueMergeMethod(strings.ToLower(params.MergeMethod))),
+	}, nil
+}
+
+// mergeQueueToAPI applies the merge queue settings to the ruleset managing the merge queue of
+// branch, which is created if apiObj is nil. Parameters not modelled in MergeQueueInfo are kept.
+func mergeQueueToAPI(req gitprovider.MergeQueueInfo, branch string, apiObj *ruleset) (*ruleset, error) {
+	if apiObj == nil {
+		apiObj = &ruleset{
+			Name:   gitprovider.StringVar(mergeQueueRulesetName(branch)),
+			Target: gitprovider.StringVar(rulesetTargetBranch),
+			Conditions: &rulesetConditions{
+				RefName: &rulesetRefNameCondition{
+					Include: []string{"refs/heads/" + branch},
+					Exclude: []string{},
+				},
+			},
+		}
+	}
+	enforcement := rulesetEnforcementOff
+	if req.Enabled {
+		enforcement = rulesetEnforcementActive
+	}
+	apiObj.Enforcement = &enforcement
+
+	rule := mergeQueueRule(apiObj)
+	if rule == nil {
+		rule = &rulesetRule{Type: rulesetRuleTypeMergeQueue}
+		apiObj.Rules = append(apiObj.Rules, rule)
+	}
+	params := defaultMergeQueueParameters()
+	if len(rule.Parameters) != 0 {
+		if err := json.Unmarshal(rule.Parameters, &params); err != nil {
+			return nil, validation.NewMultiError(err, gitprovider.ErrInvalidServerData)
+		}
+	}
+	if req.BatchSize != nil {
+		params.MaxEntriesToMerge = *req.BatchSize
+	}
+	if req.MergeMethod != nil {
+		params.MergeMethod = strings.ToUpper(string(*req.MergeMethod))
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	rule.Parameters = data
+	return apiObj, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+func Test_mergeQueueToAPI(t *testing.T) {
+	req := gitprovider.MergeQueueInfo{
+		Enabled:     true,
+		BatchSize:   gitprovider.IntVar(10),
+		MergeMethod: gitprovider.MergeQueueMergeMethodVar(gitprovider.MergeQueueMergeMethodSquash),
+	}
+
+	// Creating a ruleset sets all parameters GitHub requires
+	apiObj, err := mergeQueueToAPI(req, "main", nil)
+	if err != nil {
+		t.Fatalf("mergeQueueToAPI() error = %v", err)
+	}
+	if *apiObj.Name != "merge-queue/main" || apiObj.Conditions.RefName.Include[0] != "refs/heads/main" {
+		t.Errorf("mergeQueueToAPI() name, include = %q, %v", *apiObj.Name, apiObj.Conditions.RefName.Include)
+	}
+	params := mergeQueueParameters{}
+	if err := json.Unmarshal(mergeQueueRule(apiObj).Parameters, &params); err != nil {
+		t.Fatal(err)
+	}
+	want := defaultMergeQueueParameters()
+	want.MaxEntriesToMerge = 10
+	want.MergeMethod = "SQUASH"
+	if params != want {
+		t.Errorf("mergeQueueToAPI() parameters = %+v, want %+v", params, want)
+	}
+
+	// Reading it back gives the desired state
+	id := int64(1)
+	apiObj.ID = &id
+	actual, err := mergeQueueFromAPI(apiObj)
+	if err != nil {
+		t.Fatalf("mergeQueueFromAPI() error = %v", err)
+	}
+	if !req.Equals(actual) {
+		t.Errorf("mergeQueueFromAPI() = %+v, want %+v", actual, req)
+	}
+
+	// Updating keeps the parameters that aren't modelled, and disabling disables the ruleset
+	want.CheckResponseTimeoutMinutes = 30
+	data, _ := json.Marshal(want)
+	mergeQueueRule(apiObj).Parameters = data
+	apiObj, err = mergeQueueToAPI(gitprovider.MergeQueueInfo{Enabled: false}, "main", apiObj)
+	if err != nil {
+		t.Fatalf("mergeQueueToAPI() error = %v", err)
+	}
+	if err := json.Unmarshal(mergeQueueRule(apiObj).Parameters, &params); err != nil {
+		t.Fatal(err)
+	}
+	if params != want || *apiObj.Enforcement != rulesetEnforcementOff {
+		t.Errorf("mergeQueueToAPI() parameters, enforcement = %+v, %q", params, *apiObj.Enforcement)
+	}
+	if actual, _ := mergeQueueFromAPI(apiObj); actual.Enabled {
+		t.Error("mergeQueueFromAPI() Enabled = true for disabled ruleset")
+	}
+}
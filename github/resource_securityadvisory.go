@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// securityAdvisory is the subset of a repository security advisory object, as returned from
+// "GET /repos/{owner}/{repo}/security-advisories", that we care about. go-github doesn't
+// provide a struct for it (yet).
+type securityAdvisory struct {
+	GHSAID   *string `json:"ghsa_id,omitempty"`
+	Severity *string `json:"severity,omitempty"`
+	State    *string `json:"state,omitempty"`
+}
+
+// validateSecurityAdvisoryAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateSecurityAdvisoryAPI(apiObj *securityAdvisory) error {
+	return validateAPIObject("GitHub.SecurityAdvisory", func(validator validation.Validator) {
+		if apiObj.GHSAID == nil {
+			validator.Required("GHSAID")
+		}
+		if apiObj.State == nil {
+			validator.Required("State")
+		}
+	})
+}
+
+func securityAdvisoryFromAPI(apiObj *securityAdvisory) gitprovider.SecurityAdvisoryInfo {
+	info := gitprovider.SecurityAdvisoryInfo{
+		ID:    *apiObj.GHSAID,
+		State: gitprovider.SecurityAdvisoryState(*apiObj.State),
+	}
+	if apiObj.Severity != nil {
+		info.Severity = *apiObj.Severity
+	}
+	return info
+}
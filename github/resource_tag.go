@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+func newTag(ctx *clientContext, apiObj *github.RepositoryTag, ref gitprovider.RepositoryRef) *tag {
+	return &tag{
+		clientContext: ctx,
+		t:             *apiObj,
+		ref:           ref,
+	}
+}
+
+var _ gitprovider.Tag = &tag{}
+
+type tag struct {
+	*clientContext
+
+	t   github.RepositoryTag
+	ref gitprovider.RepositoryRef
+}
+
+func (t *tag) Get() gitprovider.TagInfo {
+	return tagFromAPI(&t.t)
+}
+
+func (t *tag) APIObject() interface{} {
+	return &t.t
+}
+
+func (t *tag) Repository() gitprovider.RepositoryRef {
+	return t.ref
+}
+
+func tagFromAPI(apiObj *github.RepositoryTag) gitprovider.TagInfo {
+	return gitprovider.TagInfo{
+		Name: *apiObj.Name,
+		Sha:  *apiObj.Commit.SHA,
+	}
+}
+
+// validateTagAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateTagAPI(apiObj *github.RepositoryTag) error {
+	return validateAPIObject("GitHub.RepositoryTag", func(validator validation.Validator) {
+		if apiObj.Name == nil {
+			validator.Required("Name")
+		}
+		if apiObj.Commit == nil || apiObj.Commit.SHA == nil {
+			validator.Required("Commit.SHA")
+		}
+	})
+}
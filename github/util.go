@@ -20,6 +20,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 
 	"github.com/google/go-github/v32/github"
 
@@ -55,6 +58,16 @@ func validateOrgRepositoryRef(ref gitprovider.OrgRepositoryRef, expectedDomain s
 	return validateIdentityFields(ref, expectedDomain)
 }
 
+// validateRepositoryRef makes sure the RepositoryRef is valid for GitHub's usage.
+func validateRepositoryRef(ref gitprovider.RepositoryRef, expectedDomain string) error {
+	// Make sure the RepositoryRef fields are valid
+	if err := validation.ValidateTargets("RepositoryRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
 // validateOrganizationRef makes sure the OrganizationRef is valid for GitHub's usage.
 func validateOrganizationRef(ref gitprovider.OrganizationRef, expectedDomain string) error {
 	// Make sure the OrganizationRef fields are valid
@@ -100,6 +113,7 @@ func handleHTTPError(err error) error {
 		return nil
 	}
 	ghRateLimitError := &github.RateLimitError{}
+	ghAbuseRateLimitError := &github.AbuseRateLimitError{}
 	ghErrorResponse := &github.ErrorResponse{}
 	if errors.As(err, &ghRateLimitError) {
 		// Convert go-github's RateLimitError to our similar error type
@@ -114,6 +128,14 @@ func handleHTTPError(err error) error {
 			Remaining: ghRateLimitError.Rate.Remaining,
 			Reset:     ghRateLimitError.Rate.Reset.Time,
 		})
+	} else if errors.As(err, &ghAbuseRateLimitError) {
+		// Secondary rate limits only report when to retry, through the Retry-After header
+		return validation.NewMultiError(err, gitprovider.NewRateLimitError(gitprovider.HTTPError{
+			Response:         ghAbuseRateLimitError.Response,
+			ErrorMessage:     ghAbuseRateLimitError.Error(),
+			Message:          ghAbuseRateLimitError.Message,
+			DocumentationURL: rateLimitDocURL,
+		}, rateLimitHeaders))
 	} else if errors.As(err, &ghErrorResponse) {
 		httpErr := gitprovider.HTTPError{
 			Response:         ghErrorResponse.Response,
@@ -145,6 +167,17 @@ func handleHTTPError(err error) error {
 	return err
 }
 
+// withInsufficientScope marks an error already returned from handleHTTPError with
+// gitprovider.ErrInsufficientScope if the server responded with 403 Forbidden. This should
+// only be used for endpoints where that means the token lacks a scope, not invalid credentials.
+func withInsufficientScope(err error) error {
+	ghErrorResponse := &github.ErrorResponse{}
+	if errors.As(err, &ghErrorResponse) && ghErrorResponse.Response.StatusCode == http.StatusForbidden {
+		return validation.NewMultiError(err, gitprovider.ErrInsufficientScope)
+	}
+	return err
+}
+
 // allPages runs fn for each page, expecting a HTTP request to be made and returned during that call.
 // allPages expects that the data is saved in fn to an outer variable.
 // allPages calls fn as many times as needed to get all pages, and modifies opts for each call.
@@ -162,6 +195,76 @@ func allPages(opts *github.ListOptions, fn func() (*github.Response, error)) err
 	}
 }
 
+// pageListOptions converts the given PageOptions to go-github ListOptions.
+// As page indexes are 1-based, Page is always set to at least 1.
+func pageListOptions(opts gitprovider.PageOptions) github.ListOptions {
+	page := opts.Page
+	if page == 0 {
+		page = 1
+	}
+	return github.ListOptions{Page: page, PerPage: opts.PerPage}
+}
+
+// pageInfoFromResponse normalizes the pagination metadata GitHub gives in the Link header.
+// GitHub doesn't supply the total amount of items, so that is always unknown. The total amount
+// of pages is known from the "last" link, or from the current page if it is the last one.
+func pageInfoFromResponse(page int, resp *github.Response) gitprovider.PageInfo {
+	info := gitprovider.PageInfo{
+		NextPage:   resp.NextPage,
+		TotalCount: gitprovider.UnknownCount,
+		TotalPages: gitprovider.UnknownCount,
+	}
+	if resp.LastPage != 0 {
+		info.TotalPages = resp.LastPage
+	} else if resp.NextPage == 0 {
+		info.TotalPages = page
+	}
+	return info
+}
+
+// allCursorPages runs fn for each page, like allPages, but for endpoints that use cursor-based
+// pagination through an "after" query parameter, which go-github doesn't parse from the Link header.
+// fn is given the cursor to request, starting at after. An empty cursor means the first page.
+// There is no need to wrap the resulting error in handleHTTPError(err), as that's already done.
+func allCursorPages(after string, fn func(after string) (*github.Response, error)) error {
+	for {
+		resp, err := fn(after)
+		if err != nil {
+			return handleHTTPError(err)
+		}
+		if after = nextPageCursor(resp); after == "" {
+			return nil
+		}
+	}
+}
+
+// nextPageCursor returns the "after" cursor of the rel="next" link in the Link header of resp,
+// or an empty string if there is no next page.
+func nextPageCursor(resp *github.Response) string {
+	if resp == nil || resp.Response == nil {
+		return ""
+	}
+	for _, link := range strings.Split(resp.Header.Get("Link"), ",") {
+		segments := strings.Split(strings.TrimSpace(link), ";")
+		// A link must at least have href and rel
+		if len(segments) < 2 {
+			continue
+		}
+		for _, segment := range segments[1:] {
+			if strings.TrimSpace(segment) != `rel="next"` {
+				continue
+			}
+			href := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(segments[0]), "<"), ">")
+			u, err := url.Parse(href)
+			if err != nil {
+				return ""
+			}
+			return u.Query().Get("after")
+		}
+	}
+	return ""
+}
+
 // validateAPIObject creates a Validatior with the specified name, gives it to fn, and
 // depending on if any error was registered with it; either returns nil, or a MultiError
 // with both the validation error and ErrInvalidServerData, to mark that the server data
@@ -175,3 +278,22 @@ func validateAPIObject(name string, fn func(validation.Validator)) error {
 	}
 	return nil
 }
+
+// forEachBounded calls fn for each index in [0, n), running at most concurrency calls at once,
+// and returns when all calls have returned.
+func forEachBounded(n, concurrency int, fn func(i int)) {
+	sem := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -17,9 +17,14 @@ limitations under the License.
 package github
 
 import (
+	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
 	"github.com/dinosk/go-git-providers/validation"
@@ -133,3 +138,200 @@ func Test_allPages(t *testing.T) {
 		})
 	}
 }
+
+func newLinkResponse(link string) *github.Response {
+	header := http.Header{}
+	if link != "" {
+		header.Set("Link", link)
+	}
+	return &github.Response{Response: &http.Response{Header: header}}
+}
+
+func Test_allCursorPages(t *testing.T) {
+	tests := []struct {
+		name           string
+		fn             func(int) (*github.Response, error)
+		expectedErrs   []error
+		expectedCursor []string
+	}{
+		{
+			name: "one page only, no error",
+			fn: func(_ int) (*github.Response, error) {
+				return newLinkResponse(""), nil
+			},
+			expectedCursor: []string{""},
+		},
+		{
+			name: "three pages, no error",
+			fn: func(i int) (*github.Response, error) {
+				switch i {
+				case 1:
+					return newLinkResponse(`<https://api.github.com/repos/o/r/security-advisories?after=Y3Vy>; rel="next"`), nil
+				case 2:
+					return newLinkResponse(`<https://api.github.com/repos/o/r/security-advisories?before=YmVm>; rel="prev", ` +
+						`<https://api.github.com/repos/o/r/security-advisories?after=bmV4dA%3D%3D&state=draft>; rel="next"`), nil
+				}
+				return newLinkResponse(`<https://api.github.com/repos/o/r/security-advisories?before=cHJldg>; rel="prev"`), nil
+			},
+			expectedCursor: []string{"", "Y3Vy", "bmV4dA=="},
+		},
+		{
+			name: "error at second page",
+			fn: func(i int) (*github.Response, error) {
+				if i == 2 {
+					return nil, newGHError()
+				}
+				return newLinkResponse(`<https://api.github.com/repos/o/r/security-advisories?after=Y3Vy>; rel="next"`), nil
+			},
+			expectedCursor: []string{"", "Y3Vy"},
+			expectedErrs:   []error{&validation.MultiError{}, gitprovider.ErrNotFound, newGHError()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursors := []string{}
+			err := allCursorPages("", func(after string) (*github.Response, error) {
+				cursors = append(cursors, after)
+				return tt.fn(len(cursors))
+			})
+			validation.TestExpectErrors(t, "allCursorPages", err, tt.expectedErrs...)
+			if !reflect.DeepEqual(cursors, tt.expectedCursor) {
+				t.Errorf("allCursorPages() cursors = %v, want %v", cursors, tt.expectedCursor)
+			}
+		})
+	}
+}
+
+func Test_pageInfoFromResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		page int
+		resp *github.Response
+		want gitprovider.PageInfo
+	}{
+		{
+			name: "first of several pages",
+			page: 1,
+			resp: &github.Response{NextPage: 2, LastPage: 5},
+			want: gitprovider.PageInfo{NextPage: 2, TotalCount: gitprovider.UnknownCount, TotalPages: 5},
+		},
+		{
+			name: "last page",
+			page: 5,
+			resp: &github.Response{PrevPage: 4, FirstPage: 1},
+			want: gitprovider.PageInfo{NextPage: 0, TotalCount: gitprovider.UnknownCount, TotalPages: 5},
+		},
+		{
+			name: "only page",
+			page: 1,
+			resp: &github.Response{},
+			want: gitprovider.PageInfo{NextPage: 0, TotalCount: gitprovider.UnknownCount, TotalPages: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pageInfoFromResponse(tt.page, tt.resp); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pageInfoFromResponse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_withInsufficientScope(t *testing.T) {
+	newStatusError := func(status int) error {
+		err := newGHError()
+		err.Response.StatusCode = status
+		return handleHTTPError(err)
+	}
+	tests := []struct {
+		name         string
+		err          error
+		expectedErrs []error
+	}{
+		{
+			name: "nil => nil",
+		},
+		{
+			name:         "403 => InsufficientScope",
+			err:          newStatusError(http.StatusForbidden),
+			expectedErrs: []error{gitprovider.ErrInsufficientScope, &gitprovider.InvalidCredentialsError{}},
+		},
+		{
+			name:         "404 => NotFound only",
+			err:          newStatusError(http.StatusNotFound),
+			expectedErrs: []error{gitprovider.ErrNotFound},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := withInsufficientScope(tt.err)
+			validation.TestExpectErrors(t, "withInsufficientScope", err, tt.expectedErrs...)
+			if tt.err != nil && len(tt.expectedErrs) == 1 && errors.Is(err, gitprovider.ErrInsufficientScope) {
+				t.Errorf("withInsufficientScope() unexpectedly wrapped ErrInsufficientScope")
+			}
+		})
+	}
+}
+
+// newGHResponseError feeds a synthetic response to go-github, and returns the error it makes of it.
+func newGHResponseError(status int, header http.Header, body string) error {
+	return github.CheckResponse(&http.Response{
+		Request:    &http.Request{Method: "GET", URL: &url.URL{}},
+		StatusCode: status,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	})
+}
+
+func Test_handleHTTPError_rateLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantLimited bool
+		wantReset   time.Time
+	}{
+		{
+			name: "rate limit exhausted",
+			err: newGHResponseError(http.StatusForbidden, http.Header{
+				"X-Ratelimit-Limit":     []string{"5000"},
+				"X-Ratelimit-Remaining": []string{"0"},
+				"X-Ratelimit-Reset":     []string{"1600000000"},
+			}, `{"message": "API rate limit exceeded"}`),
+			wantLimited: true,
+			wantReset:   time.Unix(1600000000, 0),
+		},
+		{
+			name: "secondary rate limit",
+			err: newGHResponseError(http.StatusForbidden, http.Header{
+				"Retry-After": []string{"60"},
+			}, `{"message": "You have triggered an abuse detection mechanism", "documentation_url": "https://developer.github.com/v3/#abuse-rate-limits"}`),
+			wantLimited: true,
+			wantReset:   time.Now().Add(time.Minute),
+		},
+		{
+			name: "other 403",
+			err:  newGHResponseError(http.StatusForbidden, http.Header{}, `{"message": "Must have admin rights to Repository."}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := handleHTTPError(tt.err)
+			if got := errors.Is(err, gitprovider.ErrRateLimited); got != tt.wantLimited {
+				t.Fatalf("errors.Is(%v, ErrRateLimited) = %v, want %v", err, got, tt.wantLimited)
+			}
+			if !tt.wantLimited {
+				return
+			}
+			rateLimitErr := &gitprovider.RateLimitError{}
+			if !errors.As(err, &rateLimitErr) {
+				t.Fatalf("errors.As(%v, *RateLimitError) = false", err)
+			}
+			if diff := rateLimitErr.Reset.Sub(tt.wantReset); diff < -time.Second || diff > time.Second {
+				t.Errorf("Reset = %v, want %v", rateLimitErr.Reset, tt.wantReset)
+			}
+			if errors.As(err, new(*gitprovider.InvalidCredentialsError)) {
+				t.Error("rate limit error is unexpectedly an InvalidCredentialsError")
+			}
+		})
+	}
+}
@@ -17,11 +17,14 @@ limitations under the License.
 package gitlab
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
 	"github.com/dinosk/go-git-providers/gitprovider/cache"
+	"github.com/hashicorp/go-retryablehttp"
 	gogitlab "github.com/xanzy/go-gitlab"
 	"golang.org/x/oauth2"
 )
@@ -29,8 +32,20 @@ import (
 const (
 	// DefaultDomain specifies the default domain used as the backend.
 	DefaultDomain = "gitlab.com"
+
+	// apiPath is the path of the REST API on a GitLab instance.
+	apiPath = "/api/v4"
 )
 
+// rateLimitHeaders are the headers GitLab reports the rate limit of the client in.
+// See: https://docs.gitlab.com/ee/user/admin_area/settings/user_and_ip_rate_limits.html#response-headers
+//nolint:gochecknoglobals
+var rateLimitHeaders = gitprovider.RateLimitHeaders{
+	Limit:     "RateLimit-Limit",
+	Remaining: "RateLimit-Remaining",
+	Reset:     "RateLimit-Reset",
+}
+
 // ClientOption is the interface to implement for passing options to NewClient.
 // The clientOptions struct is private to force usage of the With... functions.
 type ClientOption interface {
@@ -82,6 +97,15 @@ func (opts *clientOptions) getTransportChain() (chain []gitprovider.ChainableRou
 	if opts.PostChainTransportHook != nil {
 		chain = append(chain, opts.PostChainTransportHook)
 	}
+	if userAgentTransport := opts.UserAgentTransport(); userAgentTransport != nil {
+		chain = append(chain, userAgentTransport)
+	}
+	if requestTimeoutTransport := opts.RequestTimeoutTransport(); requestTimeoutTransport != nil {
+		chain = append(chain, requestTimeoutTransport)
+	}
+	if rateLimitTransport := opts.RateLimitTransport(rateLimitHeaders); rateLimitTransport != nil {
+		chain = append(chain, rateLimitTransport)
+	}
 	if opts.AuthTransport != nil {
 		chain = append(chain, opts.AuthTransport)
 	}
@@ -121,11 +145,45 @@ func optionError(err error) ClientOption {
 //
 
 // WithDomain initializes a Client for a custom GitLab instance of the given domain.
-// Only host and port information should be present in domain. domain must not be an empty string.
+// domain consists of the host, an optional port, and the path the instance is installed under, if
+// any, e.g. "gitlab.example.com/gitlab". The base URL of the API, e.g. "https://gitlab.example.com/api/v4",
+// is accepted too. domain must not be an empty string. See gitprovider.NormalizeBaseURL.
 func WithDomain(domain string) ClientOption {
 	return buildCommonOption(gitprovider.CommonClientOptions{Domain: &domain})
 }
 
+// WithDefaultOrganization makes repository operations given a ref without an organization, i.e.
+// only a repository name, use the given organization. The domain of org must match the client's.
+func WithDefaultOrganization(org gitprovider.OrganizationRef) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{DefaultOrganization: &org})
+}
+
+// WithRetryBudget limits the amount of retries of failed requests (429 Too Many Requests and
+// 5xx responses) across all requests of the client. The budget allows size retries in a burst,
+// and is refilled with refillPerSecond retries per second. When the budget is exhausted, the error
+// of a failed request is returned immediately. size must be positive, and refillPerSecond must not
+// be negative.
+func WithRetryBudget(size int, refillPerSecond float64) ClientOption {
+	budget, err := gitprovider.NewRetryBudget(size, refillPerSecond)
+	if err != nil {
+		return optionError(fmt.Errorf("%v: %w", err, gitprovider.ErrInvalidClientOptions))
+	}
+	return buildCommonOption(gitprovider.CommonClientOptions{RetryBudget: budget})
+}
+
+// WithRetryableStatusCodes makes the client retry responses with the given status codes, instead of
+// 429 Too Many Requests and 5xx. This is useful e.g. behind a proxy signaling throttling with a
+// non-standard status code. The codes must be valid HTTP status codes. The retries are limited by
+// WithRetryBudget, if set.
+func WithRetryableStatusCodes(codes ...int) ClientOption {
+	// Don't allow invalid values
+	if err := gitprovider.ValidateRetryableStatusCodes(codes); err != nil {
+		return optionError(fmt.Errorf("%v: %w", err, gitprovider.ErrInvalidClientOptions))
+	}
+
+	return buildCommonOption(gitprovider.CommonClientOptions{RetryableStatusCodes: codes})
+}
+
 // WithDestructiveAPICalls tells the client whether it's allowed to do dangerous and possibly destructive
 // actions, like e.g. deleting a repository.
 func WithDestructiveAPICalls(destructiveActions bool) ClientOption {
@@ -154,6 +212,38 @@ func WithPostChainTransportHook(postRoundTripperFunc gitprovider.ChainableRoundT
 	return buildCommonOption(gitprovider.CommonClientOptions{PostChainTransportHook: postRoundTripperFunc})
 }
 
+// WithRequestTimeout limits every single request to GitLab to the given duration, including reading
+// the response body, e.g. so that a hung connection can't block a reconcile forever. A shorter
+// deadline of the caller's context is kept. d must be positive. See gitprovider.RequestTimeoutTransport.
+func WithRequestTimeout(d time.Duration) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{RequestTimeout: &d})
+}
+
+// WithUserAgent sets the User-Agent header of every request to GitLab to userAgent, e.g. so that
+// the administrators of a self-hosted instance can identify the client's traffic in their logs.
+// userAgent must not be an empty string. See gitprovider.UserAgentTransport.
+func WithUserAgent(userAgent string) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{UserAgent: &userAgent})
+}
+
+// WithRateLimitHandler calls handler with the rate limit GitLab reports in the RateLimit-* headers
+// of every response, e.g. to throttle requests before the limit is exhausted. handler must not be nil.
+// See gitprovider.RateLimitTransport for more information.
+func WithRateLimitHandler(handler func(gitprovider.RateLimitInfo)) ClientOption {
+	// Don't allow an empty value
+	if handler == nil {
+		return optionError(fmt.Errorf("handler cannot be nil: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return buildCommonOption(gitprovider.CommonClientOptions{RateLimitHandler: handler})
+}
+
+// WithRateLimitBlocking makes requests wait until the rate limit resets, once GitLab reported
+// that no requests remain, instead of being rejected with 429 Too Many Requests.
+func WithRateLimitBlocking(block bool) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{BlockOnRateLimit: &block})
+}
+
 // WithOAuth2Token initializes a Client which authenticates with GitLab through an OAuth2 token.
 // oauth2Token must not be an empty string.
 func WithOAuth2Token(oauth2Token string) ClientOption {
@@ -212,36 +302,32 @@ func NewClient(token string, tokenType string, optFns ...ClientOption) (gitprovi
 		return nil, err
 	}
 
-	if tokenType == "oauth2" {
-		if opts.Domain == nil || *opts.Domain == DefaultDomain {
-			// No domain set or the default gitlab.com used
-			domain = DefaultDomain
-			gl, err = gogitlab.NewOAuthClient(token, gogitlab.WithHTTPClient(httpClient))
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			domain = *opts.Domain
-			gl, err = gogitlab.NewOAuthClient(token, gogitlab.WithHTTPClient(httpClient), gogitlab.WithBaseURL(domain))
-			if err != nil {
-				return nil, err
-			}
+	glOpts := []gogitlab.ClientOptionFunc{gogitlab.WithHTTPClient(httpClient)}
+	var baseURL gitprovider.BaseURL
+	if opts.Domain != nil {
+		if baseURL, err = gitprovider.NormalizeBaseURL(*opts.Domain, apiPath); err != nil {
+			return nil, err
 		}
+	}
+	if opts.Domain == nil || baseURL.Domain() == DefaultDomain {
+		// No domain set or the default gitlab.com used
+		domain = DefaultDomain
 	} else {
-		if opts.Domain == nil || *opts.Domain == DefaultDomain {
-			// No domain set or the default gitlab.com used
-			domain = DefaultDomain
-			gl, err = gogitlab.NewClient(token, gogitlab.WithHTTPClient(httpClient))
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			domain = *opts.Domain
-			gl, err = gogitlab.NewClient(token, gogitlab.WithHTTPClient(httpClient), gogitlab.WithBaseURL(domain))
-			if err != nil {
-				return nil, err
-			}
-		}
+		// A self-hosted instance is used, possibly installed under a path
+		domain = baseURL.Domain()
+		glOpts = append(glOpts, gogitlab.WithBaseURL(baseURL.URL(apiPath+"/")))
+	}
+	if opts.RetryBudget != nil || opts.RetryableStatusCodes != nil {
+		glOpts = append(glOpts, gogitlab.WithCustomRetry(retryCheck(opts.RetryBudget, opts.RetryableStatusCodes)))
+	}
+
+	if tokenType == "oauth2" {
+		gl, err = gogitlab.NewOAuthClient(token, glOpts...)
+	} else {
+		gl, err = gogitlab.NewClient(token, glOpts...)
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	// By default, turn destructive actions off. But allow overrides.
@@ -250,5 +336,40 @@ func NewClient(token string, tokenType string, optFns ...ClientOption) (gitprovi
 		destructiveActions = *opts.EnableDestructiveAPICalls
 	}
 
-	return newClient(gl, domain, sshDomain, destructiveActions), nil
+	// Make sure the default organization, if set, is in the domain of the client
+	if opts.DefaultOrganization != nil && opts.DefaultOrganization.Domain != domain {
+		return nil, fmt.Errorf("default organization domain %q doesn't match the client domain %q: %w",
+			opts.DefaultOrganization.Domain, domain, gitprovider.ErrInvalidClientOptions)
+	}
+
+	return newClient(gl, domain, sshDomain, destructiveActions, opts.DefaultOrganization), nil
+}
+
+// retryCheck returns a retryablehttp.CheckRetry that retries responses with one of codes, or
+// the same responses as go-gitlab does by default (429 Too Many Requests and 5xx) if codes is nil,
+// as long as budget allows it. If budget is nil, retries are only limited per request.
+func retryCheck(budget *gitprovider.RetryBudget, codes []int) retryablehttp.CheckRetry {
+	isRetryable := func(statusCode int) bool {
+		return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+	}
+	if codes != nil {
+		retryable := make(map[int]bool, len(codes))
+		for _, code := range codes {
+			retryable[code] = true
+		}
+		isRetryable = func(statusCode int) bool { return retryable[statusCode] }
+	}
+
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if err != nil {
+			return false, err
+		}
+		if isRetryable(resp.StatusCode) {
+			return budget == nil || budget.TryAcquire(), nil
+		}
+		return false, nil
+	}
 }
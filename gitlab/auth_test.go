@@ -17,6 +17,7 @@ limitations under the License.
 package gitlab
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -166,11 +167,59 @@ func Test_makeOptions(t *testing.T) {
 			opts: []ClientOption{WithConditionalRequests(true)},
 			want: &clientOptions{EnableConditionalRequests: gitprovider.BoolVar(true)},
 		},
+		{
+			name:         "WithRetryBudget, invalid size",
+			opts:         []ClientOption{WithRetryBudget(0, 1)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name: "WithRetryableStatusCodes",
+			opts: []ClientOption{WithRetryableStatusCodes(420, 503)},
+			want: buildCommonOption(gitprovider.CommonClientOptions{RetryableStatusCodes: []int{420, 503}}),
+		},
+		{
+			name:         "WithRetryableStatusCodes, invalid code",
+			opts:         []ClientOption{WithRetryableStatusCodes(99)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRetryableStatusCodes, exclusive",
+			opts:         []ClientOption{WithRetryableStatusCodes(420), WithRetryableStatusCodes(503)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRetryBudget, exclusive",
+			opts:         []ClientOption{WithRetryBudget(10, 1), WithRetryBudget(5, 1)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
 		{
 			name:         "WithConditionalRequests, exclusive",
 			opts:         []ClientOption{WithConditionalRequests(true), WithConditionalRequests(false)},
 			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
 		},
+		{
+			name: "WithRateLimitBlocking",
+			opts: []ClientOption{WithRateLimitBlocking(true)},
+			want: buildCommonOption(gitprovider.CommonClientOptions{BlockOnRateLimit: gitprovider.BoolVar(true)}),
+		},
+		{
+			name:         "WithRateLimitBlocking, exclusive",
+			opts:         []ClientOption{WithRateLimitBlocking(true), WithRateLimitBlocking(false)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRateLimitHandler, nil",
+			opts:         []ClientOption{WithRateLimitHandler(nil)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name: "WithRateLimitHandler, exclusive",
+			opts: []ClientOption{
+				WithRateLimitHandler(func(gitprovider.RateLimitInfo) {}),
+				WithRateLimitHandler(func(gitprovider.RateLimitInfo) {}),
+			},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -196,3 +245,30 @@ func Test_makeOptions(t *testing.T) {
 		})
 	}
 }
+
+func Test_retryCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		codes  []int
+		status int
+		want   bool
+	}{
+		{name: "default, 5xx", status: http.StatusBadGateway, want: true},
+		{name: "default, 429", status: http.StatusTooManyRequests, want: true},
+		{name: "default, 404", status: http.StatusNotFound, want: false},
+		{name: "custom code", codes: []int{420}, status: 420, want: true},
+		{name: "custom codes replace the default", codes: []int{420}, status: http.StatusBadGateway, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status}
+			got, err := retryCheck(nil, tt.codes)(context.Background(), resp, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("retryCheck() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -24,9 +24,9 @@ import (
 // ProviderID is the provider ID for GitLab.
 const ProviderID = gitprovider.ProviderID("gitlab")
 
-func newClient(c *gitlab.Client, domain string, sshDomain string, destructiveActions bool) *Client {
+func newClient(c *gitlab.Client, domain string, sshDomain string, destructiveActions bool, defaultOrg *gitprovider.OrganizationRef) *Client {
 	glClient := &gitlabClientImpl{c, destructiveActions}
-	ctx := &clientContext{glClient, domain, sshDomain, destructiveActions}
+	ctx := &clientContext{glClient, domain, sshDomain, destructiveActions, defaultOrg}
 	return &Client{
 		clientContext: ctx,
 		orgs: &OrganizationsClient{
@@ -46,6 +46,15 @@ type clientContext struct {
 	domain             string
 	sshDomain          string
 	destructiveActions bool
+	// defaultOrg is used for repository operations given a ref without an organization, if set
+	defaultOrg *gitprovider.OrganizationRef
+}
+
+// resolveOrgRepositoryRef fills in the default organization if ref doesn't specify one.
+func (c *clientContext) resolveOrgRepositoryRef(ref gitprovider.OrgRepositoryRef) (gitprovider.OrgRepositoryRef, error) {
+	orgRef, err := gitprovider.ResolveOrganizationRef(ref.OrganizationRef, c.defaultOrg)
+	ref.OrganizationRef = orgRef
+	return ref, err
 }
 
 // Client implements the gitprovider.Client interface.
@@ -18,6 +18,7 @@ package gitlab
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
 	"github.com/xanzy/go-gitlab"
@@ -34,27 +35,39 @@ type TeamsClient struct {
 
 // Get a team within the specific organization.
 //
-// teamName may include slashes, to point to e.g. subgroups in GitLab.
+// teamName is the path of the subgroup relative to the organization, and may include slashes.
 // teamName must not be an empty string.
 //
 // ErrNotFound is returned if the resource does not exist.
 func (c *TeamsClient) Get(ctx context.Context, teamName string) (gitprovider.Team, error) {
-	apiObjs, err := c.c.ListGroupMembers(ctx, c.ref.Organization)
+	if err := validateOrganizationRef(c.ref, c.domain); err != nil {
+		return nil, err
+	}
+
+	// GET /groups/{group}
+	groupPath := fmt.Sprintf("%s/%s", gitlabGroupPath(c.ref), teamName)
+	apiObj, err := c.c.GetGroup(ctx, groupPath)
 	if err != nil {
 		return nil, err
 	}
 
-	// Collect a list of the members' names. Login is validated to be non-nil in ListOrgTeamMembers.
-	logins := make([]string, 0, len(apiObjs))
-	for _, apiObj := range apiObjs {
-		// Login is validated to be non-nil in ListOrgTeamMembers
-		logins = append(logins, apiObj.Username)
+	// GET /groups/{group}/members
+	members, err := c.c.ListGroupMembers(ctx, groupPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members of team %q: %w", teamName, err)
+	}
+
+	// Collect a list of the members' names
+	logins := make([]string, 0, len(members))
+	for _, member := range members {
+		logins = append(logins, member.Username)
 	}
 
 	return &team{
-		users: apiObjs,
+		users: members,
 		info: gitprovider.TeamInfo{
-			Name:    teamName,
+			Name:    apiObj.Name,
+			Slug:    teamName,
 			Members: logins,
 		},
 		ref: c.ref,
@@ -65,14 +78,20 @@ func (c *TeamsClient) Get(ctx context.Context, teamName string) (gitprovider.Tea
 //
 // List returns all available organizations, using multiple paginated requests if needed.
 func (c *TeamsClient) List(ctx context.Context) ([]gitprovider.Team, error) {
-	subgroups, err := c.c.ListSubgroups(ctx, c.ref.Organization)
+	if err := validateOrganizationRef(c.ref, c.domain); err != nil {
+		return nil, err
+	}
+
+	// GET /groups/{group}/subgroups
+	subgroups, err := c.c.ListSubgroups(ctx, gitlabGroupPath(c.ref))
 	if err != nil {
 		return nil, err
 	}
 
 	teams := make([]gitprovider.Team, 0, len(subgroups))
 	for _, subgroup := range subgroups {
-		team, err := c.Get(ctx, subgroup.Name)
+		// Path is validated to be non-empty in ListSubgroups
+		team, err := c.Get(ctx, subgroup.Path)
 		if err != nil {
 			return nil, err
 		}
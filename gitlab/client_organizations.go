@@ -36,7 +36,7 @@ type OrganizationsClient struct {
 // ErrNotFound is returned if the resource does not exist.
 func (c *OrganizationsClient) Get(ctx context.Context, ref gitprovider.OrganizationRef) (gitprovider.Organization, error) {
 	// GET /groups/{group}
-	apiObj, err := c.c.GetGroup(ctx, ref.Organization)
+	apiObj, err := c.c.GetGroup(ctx, gitlabGroupPath(ref))
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +71,7 @@ func (c *OrganizationsClient) List(ctx context.Context) ([]gitprovider.Organizat
 //
 // Children returns all available organizations, using multiple paginated requests if needed.
 func (c *OrganizationsClient) Children(ctx context.Context, ref gitprovider.OrganizationRef) ([]gitprovider.Organization, error) {
-	apiObjs, err := c.c.ListSubgroups(ctx, ref.Organization)
+	apiObjs, err := c.c.ListSubgroups(ctx, gitlabGroupPath(ref))
 	if err != nil {
 		return nil, err
 	}
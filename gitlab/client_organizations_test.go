@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// newTestGroupClient returns a clientContext talking to a test server, which records the escaped
+// paths of the group requests and responds with a group or a list of projects.
+func newTestGroupClient(t *testing.T, paths *[]string) *clientContext {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/v4/groups/") {
+			return
+		}
+		*paths = append(*paths, r.URL.EscapedPath())
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/projects") {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 42, "path": "frontend", "full_path": "fluxcd/engineering/frontend"}`))
+	}))
+	t.Cleanup(srv.Close)
+	gl, err := gitlab.NewClient("", gitlab.WithBaseURL(srv.URL+"/api/v4/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &clientContext{c: &gitlabClientImpl{gl, false}, domain: DefaultDomain}
+}
+
+func TestOrganizationsClient_Get_subgroups(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		wantGroupPath string
+	}{
+		{
+			name:          "two levels",
+			url:           "https://gitlab.com/fluxcd/engineering",
+			wantGroupPath: "/api/v4/groups/fluxcd%2Fengineering",
+		},
+		{
+			name:          "three levels",
+			url:           "https://gitlab.com/fluxcd/engineering/frontend",
+			wantGroupPath: "/api/v4/groups/fluxcd%2Fengineering%2Ffrontend",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := gitprovider.ParseOrganizationURL(tt.url)
+			if err != nil {
+				t.Fatalf("ParseOrganizationURL() error = %v", err)
+			}
+			var paths []string
+			ctx := newTestGroupClient(t, &paths)
+
+			c := &OrganizationsClient{clientContext: ctx}
+			if _, err := c.Get(context.Background(), *ref); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			repos := &OrgRepositoriesClient{clientContext: ctx}
+			if _, err := repos.List(context.Background(), *ref); err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+
+			want := []string{tt.wantGroupPath, tt.wantGroupPath + "/projects"}
+			if len(paths) != len(want) || paths[0] != want[0] || paths[1] != want[1] {
+				t.Errorf("request paths = %v, want %v", paths, want)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@ package gitlab
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
 	"github.com/xanzy/go-gitlab"
@@ -36,59 +38,242 @@ type OrgRepositoriesClient struct {
 //
 // ErrNotFound is returned if the resource does not exist.
 func (c *OrgRepositoriesClient) Get(ctx context.Context, ref gitprovider.OrgRepositoryRef) (gitprovider.OrgRepository, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := c.resolveOrgRepositoryRef(ref)
+	if err != nil {
+		return nil, err
+	}
 	// Make sure the OrgRepositoryRef is valid
 	if err := validateOrgRepositoryRef(ref, c.domain); err != nil {
 		return nil, err
 	}
 	// GET /groups/{group}/projects
-	apiObj, err := c.c.GetGroupProject(ctx, ref.OrganizationRef.Organization, ref.RepositoryName)
+	apiObj, err := c.c.GetGroupProject(ctx, gitlabGroupPath(ref.OrganizationRef), ref.RepositoryName)
 	if err != nil {
 		return nil, err
 	}
-	return newGroupProject(c.clientContext, apiObj, ref), nil
+	repo := newGroupProject(c.clientContext, apiObj, ref)
+	// go-gitlab doesn't provide a field for AllowForking (yet), hence request it separately
+	if err := repo.getAllowForking(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
 }
 
 // List all repositories in the given organization.
 //
 // List returns all available repositories, using multiple paginated requests if needed.
 func (c *OrgRepositoriesClient) List(ctx context.Context, ref gitprovider.OrganizationRef) ([]gitprovider.OrgRepository, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, err
+	}
 	// Make sure the OrganizationRef is valid
 	if err := validateOrganizationRef(ref, c.domain); err != nil {
 		return nil, err
 	}
 
 	// GET /orgs/{org}/repos
-	apiObjs, err := c.c.ListGroupProjects(ctx, ref.Organization)
+	apiObjs, err := c.c.ListGroupProjects(ctx, gitlabGroupPath(ref))
 	if err != nil {
 		return nil, err
 	}
 
-	// Traverse the list, and return a list of OrgRepository objects
+	return c.orgRepositoriesFromAPI(ref, apiObjs), nil
+}
+
+// ListPage lists one page of repositories, along with the pagination metadata supplied by the provider.
+func (c *OrgRepositoriesClient) ListPage(ctx context.Context, ref gitprovider.OrganizationRef, opts gitprovider.PageOptions) ([]gitprovider.OrgRepository, gitprovider.PageInfo, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	// Make sure the OrganizationRef and options are valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+
+	// GET /groups/{group}/projects
+	apiObjs, pageInfo, err := c.c.ListGroupProjectsPage(ctx, gitlabGroupPath(ref), opts, "", "")
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	return c.orgRepositoriesFromAPI(ref, apiObjs), pageInfo, nil
+}
+
+// ListSorted lists one page of repositories, sorted server-side according to sortOpts.
+func (c *OrgRepositoriesClient) ListSorted(ctx context.Context, ref gitprovider.OrganizationRef, sortOpts gitprovider.RepositorySortOptions, opts gitprovider.PageOptions) ([]gitprovider.OrgRepository, gitprovider.PageInfo, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	// Make sure the OrganizationRef and options are valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	if err := sortOpts.ValidateOptions(); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	sortKey, ok := gitlabRepositorySortKeys[sortOpts.Sort]
+	if !ok {
+		return nil, gitprovider.PageInfo{}, fmt.Errorf("sorting repositories by %q: %w", sortOpts.Sort, gitprovider.ErrNoProviderSupport)
+	}
+
+	// GET /groups/{group}/projects
+	apiObjs, pageInfo, err := c.c.ListGroupProjectsPage(ctx, gitlabGroupPath(ref), opts, sortKey, string(sortOpts.GetDirection()))
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	return c.orgRepositoriesFromAPI(ref, apiObjs), pageInfo, nil
+}
+
+// gitlabRepositorySortKeys maps the known sort keys to the values of the "order_by" parameter of
+// "GET /groups/{group}/projects". GitLab doesn't track pushes separately, so the last activity
+// time is the closest match for RepositorySortKeyPushed.
+//nolint:gochecknoglobals
+var gitlabRepositorySortKeys = map[gitprovider.RepositorySortKey]string{
+	gitprovider.RepositorySortKeyCreated: "created_at",
+	gitprovider.RepositorySortKeyUpdated: "updated_at",
+	gitprovider.RepositorySortKeyPushed:  "last_activity_at",
+	gitprovider.RepositorySortKeyName:    "name",
+}
+
+// ListRepositoryRefs lists references to the repositories in the given organization that
+// match the filter, without returning the full repository resources.
+//
+// The Visibility, Archived and Language filters are applied server-side. The NamePrefix filter
+// narrows the search server-side, but is applied client-side.
+//
+// ListRepositoryRefs returns all matching references, using multiple paginated requests if needed.
+func (c *OrgRepositoriesClient) ListRepositoryRefs(ctx context.Context, ref gitprovider.OrganizationRef, filter gitprovider.RefListFilter) ([]gitprovider.RepositoryRef, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the OrganizationRef and filter are valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+	if err := filter.ValidateOptions(); err != nil {
+		return nil, err
+	}
+
+	var visibility *gitlab.VisibilityValue
+	if filter.Visibility != nil {
+		v := gitlabVisibilityMap[*filter.Visibility]
+		visibility = &v
+	}
+	// GET /groups/{group}/projects
+	apiObjs, err := c.c.ListGroupProjectsMatching(ctx, gitlabGroupPath(ref), visibility, filter.Archived, filter.NamePrefix, filter.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]gitprovider.RepositoryRef, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListGroupProjectsMatching
+		// The search is a substring match, only keep the names with the prefix
+		if !strings.HasPrefix(apiObj.Name, filter.NamePrefix) {
+			continue
+		}
+		refs = append(refs, gitprovider.OrgRepositoryRef{
+			OrganizationRef: ref,
+			RepositoryName:  apiObj.Name,
+		})
+	}
+	return refs, nil
+}
+
+// ListRepositories lists the repositories in the given organization that match opts.
+//
+// The Visibility filter is applied server-side, the Topic filter client-side. GitLab lists the
+// topics along with the projects, hence filtering by topic doesn't need any extra requests.
+//
+// ListRepositories returns all matching repositories, using multiple paginated requests if needed.
+func (c *OrgRepositoriesClient) ListRepositories(ctx context.Context, ref gitprovider.OrganizationRef, opts gitprovider.RepositoryListOptions) ([]gitprovider.OrgRepository, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := gitprovider.ResolveOrganizationRef(ref, c.defaultOrg)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the OrganizationRef and options are valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, err
+	}
+
+	var visibility *gitlab.VisibilityValue
+	if opts.Visibility != nil {
+		v := gitlabVisibilityMap[*opts.Visibility]
+		visibility = &v
+	}
+	// GET /groups/{group}/projects
+	apiObjs, err := c.c.ListGroupProjectsMatching(ctx, gitlabGroupPath(ref), visibility, nil, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]*gitlab.Project, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		if opts.MatchesTopics(apiObj.TagList) {
+			matching = append(matching, apiObj)
+		}
+	}
+	return c.orgRepositoriesFromAPI(ref, matching), nil
+}
+
+// orgRepositoriesFromAPI traverses the list, and returns a list of OrgRepository objects.
+func (c *OrgRepositoriesClient) orgRepositoriesFromAPI(ref gitprovider.OrganizationRef, apiObjs []*gitlab.Project) []gitprovider.OrgRepository {
 	repos := make([]gitprovider.OrgRepository, 0, len(apiObjs))
 	for _, apiObj := range apiObjs {
-		// apiObj is already validated at ListOrgRepos
+		// apiObj is already validated at ListGroupProjects or ListGroupProjectsPage
 		repos = append(repos, newGroupProject(c.clientContext, apiObj, gitprovider.OrgRepositoryRef{
 			OrganizationRef: ref,
 			RepositoryName:  apiObj.Name,
 		}))
 	}
-	return repos, nil
+	return repos
 }
 
 // Create creates a repository for the given organization, with the data and options.
 //
 // ErrAlreadyExists will be returned if the resource already exists.
 func (c *OrgRepositoriesClient) Create(ctx context.Context, ref gitprovider.OrgRepositoryRef, req gitprovider.RepositoryInfo, opts ...gitprovider.RepositoryCreateOption) (gitprovider.OrgRepository, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := c.resolveOrgRepositoryRef(ref)
+	if err != nil {
+		return nil, err
+	}
 	// Make sure the RepositoryRef is valid
 	if err := validateOrgRepositoryRef(ref, c.domain); err != nil {
 		return nil, err
 	}
 
-	apiObj, err := createProject(ctx, c.c, ref, ref.Organization, req, opts...)
+	apiObj, err := createProject(ctx, c.c, ref, gitlabGroupPath(ref.OrganizationRef), req, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return newGroupProject(c.clientContext, apiObj, ref), nil
+	repo := newGroupProject(c.clientContext, apiObj, ref)
+	// The project object can't carry AllowForking, hence apply it separately
+	if req.AllowForking != nil {
+		repo.allowForking = req.AllowForking
+		if err := repo.updateAllowForking(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return repo, nil
 }
 
 // Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
@@ -97,6 +282,11 @@ func (c *OrgRepositoriesClient) Create(ctx context.Context, ref gitprovider.OrgR
 // If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
 // If req is already the actual state, this is a no-op (actionTaken == false).
 func (c *OrgRepositoriesClient) Reconcile(ctx context.Context, ref gitprovider.OrgRepositoryRef, req gitprovider.RepositoryInfo, opts ...gitprovider.RepositoryReconcileOption) (gitprovider.OrgRepository, bool, error) {
+	// Fill in the default organization if ref doesn't specify one
+	ref, err := c.resolveOrgRepositoryRef(ref)
+	if err != nil {
+		return nil, false, err
+	}
 	// First thing, validate and default the request to ensure a valid and fully-populated object
 	// (to minimize any possible diffs between desired and actual state)
 	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
@@ -126,6 +316,15 @@ func createProject(ctx context.Context, c gitlabClient, ref gitprovider.Reposito
 		return nil, err
 	}
 
+	// Assemble the options struct based on the given options
+	o, err := gitprovider.MakeRepositoryCreateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if o.TemplateRepositoryRef != nil {
+		return nil, fmt.Errorf("creating repositories from a template: %w", gitprovider.ErrNoProviderSupport)
+	}
+
 	// Convert to the API object and apply the options
 	data := repositoryToAPI(&req, ref)
 	if len(groupName) > 0 {
@@ -136,9 +335,122 @@ func createProject(ctx context.Context, c gitlabClient, ref gitprovider.Reposito
 	return c.CreateProject(ctx, &data)
 }
 
+// ReconcileList makes sure the repositories of the given organization are the desired ones, matched
+// by name. Missing repositories are created, and drifted ones updated, see Reconcile. If opts.Prune is
+// set, the repositories that aren't desired, and whose name starts with opts.NamePrefix, are deleted.
+// Deleting repositories requires destructive actions to be enabled in the client, otherwise
+// ErrDestructiveCallDisallowed is returned before any change. If opts.DryRun is set, nothing is changed,
+// hence the deletions are reported even if destructive actions are disabled.
+//
+// The action taken (or, in a dry run, that would be taken) for each repository is returned, also when
+// an error occurred midway.
+func (c *OrgRepositoriesClient) ReconcileList(ctx context.Context, ref gitprovider.OrganizationRef, desired []gitprovider.RepositoryReconcileRequest, opts gitprovider.RepositoryReconcileListOptions) ([]gitprovider.RepositoryReconcileResult, error) {
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, err
+	}
+	// First thing, validate and default the requests to ensure valid and fully-populated objects
+	reqs, err := gitprovider.ValidateAndDefaultRepositoryReconcileRequests(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := c.List(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	// Repository names are case-insensitive in GitLab
+	actualByName := make(map[string]gitprovider.OrgRepository, len(actual))
+	for _, repo := range actual {
+		actualByName[strings.ToLower(repo.Repository().GetRepository())] = repo
+	}
+	desiredNames := make(map[string]struct{}, len(reqs))
+	for _, req := range reqs {
+		desiredNames[strings.ToLower(req.Name)] = struct{}{}
+	}
+
+	// Find the repositories that aren't desired, and make sure they may be deleted before changing anything
+	toRemove := make([]gitprovider.OrgRepository, 0, len(actual))
+	for _, repo := range actual {
+		name := repo.Repository().GetRepository()
+		if _, ok := desiredNames[strings.ToLower(name)]; !ok && opts.Prunable(name) {
+			toRemove = append(toRemove, repo)
+		}
+	}
+	if len(toRemove) != 0 && !opts.DryRun && !c.destructiveActions {
+		return nil, fmt.Errorf("cannot delete %d repositories: %w", len(toRemove), gitprovider.ErrDestructiveCallDisallowed)
+	}
+
+	results := make([]gitprovider.RepositoryReconcileResult, 0, len(reqs)+len(toRemove))
+	for _, req := range reqs {
+		action, err := c.reconcileListItem(ctx, ref, req, actualByName, opts.DryRun)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, gitprovider.RepositoryReconcileResult{Name: req.Name, Action: action})
+	}
+	for _, repo := range toRemove {
+		if !opts.DryRun {
+			if err := repo.Delete(ctx); err != nil {
+				return results, err
+			}
+		}
+		results = append(results, gitprovider.RepositoryReconcileResult{
+			Name:   repo.Repository().GetRepository(),
+			Action: gitprovider.RepositoryReconcileActionDelete,
+		})
+	}
+	return results, nil
+}
+
+// ForkRepository forks the upstream repository into the organization given in opts.
+//
+// This is not supported (yet) in GitLab.
+func (c *OrgRepositoriesClient) ForkRepository(_ context.Context, _ gitprovider.RepositoryRef, _ gitprovider.ForkOptions) (gitprovider.OrgRepository, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// TransferRepository transfers the repository to newOwner, a user or organization.
+//
+// This is not supported (yet) in GitLab.
+func (c *OrgRepositoriesClient) TransferRepository(_ context.Context, _ gitprovider.RepositoryRef, _ gitprovider.IdentityRef, _ gitprovider.TransferOptions) (gitprovider.UserRepository, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// reconcileListItem reconciles a repository desired by ReconcileList, and returns the action taken. The
+// repository is only fetched again if it exists, as the listed objects may lack fields.
+func (c *OrgRepositoriesClient) reconcileListItem(ctx context.Context, ref gitprovider.OrganizationRef, req gitprovider.RepositoryReconcileRequest, actualByName map[string]gitprovider.OrgRepository, dryRun bool) (gitprovider.RepositoryReconcileAction, error) {
+	repoRef := gitprovider.OrgRepositoryRef{OrganizationRef: ref, RepositoryName: req.Name}
+	listed, ok := actualByName[strings.ToLower(req.Name)]
+	if !ok {
+		if dryRun {
+			return gitprovider.RepositoryReconcileActionCreate, nil
+		}
+		_, err := c.Create(ctx, repoRef, req.Info)
+		return gitprovider.RepositoryReconcileActionCreate, err
+	}
+
+	// Use the actual name, as the desired one may differ in case
+	repoRef.RepositoryName = listed.Repository().GetRepository()
+	actual, err := c.Get(ctx, repoRef)
+	if err != nil {
+		return gitprovider.RepositoryReconcileActionNone, err
+	}
+	var drifted bool
+	if dryRun {
+		_, drifted = repositoryDrift(actual, req.Info)
+	} else if drifted, err = reconcileRepository(ctx, actual, req.Info); err != nil {
+		return gitprovider.RepositoryReconcileActionUpdate, err
+	}
+	if !drifted {
+		return gitprovider.RepositoryReconcileActionNone, nil
+	}
+	return gitprovider.RepositoryReconcileActionUpdate, nil
+}
+
 func reconcileRepository(ctx context.Context, actual gitprovider.UserRepository, req gitprovider.RepositoryInfo) (bool, error) {
+	req, drifted := repositoryDrift(actual, req)
 	// If the desired matches the actual state, just return the actual state
-	if req.Equals(actual.Get()) {
+	if !drifted {
 		return false, nil
 	}
 	// Populate the desired state to the current-actual object
@@ -149,6 +461,24 @@ func reconcileRepository(ctx context.Context, actual gitprovider.UserRepository,
 	return true, actual.Update(ctx)
 }
 
+// repositoryDrift returns the desired state to Set on actual, and whether it differs from the actual state.
+func repositoryDrift(actual gitprovider.UserRepository, req gitprovider.RepositoryInfo) (gitprovider.RepositoryInfo, bool) {
+	// Archived isn't supported (yet) in GitLab, hence don't detect drift for it
+	req.Archived = nil
+	// HasProjects has no GitLab equivalent, hence don't detect drift for it
+	req.HasProjects = nil
+	actualInfo := actual.Get()
+	// Topics have no default, leave them as-is if they aren't desired
+	if req.Topics == nil {
+		req.Topics = actualInfo.Topics
+	}
+	// AllowForking has no default, leave it as-is if it isn't desired
+	if req.AllowForking == nil {
+		req.AllowForking = actualInfo.AllowForking
+	}
+	return req, !req.Equals(actualInfo)
+}
+
 func toCreateOpts(opts ...gitprovider.RepositoryReconcileOption) []gitprovider.RepositoryCreateOption {
 	// Convert RepositoryReconcileOption => RepositoryCreateOption
 	createOpts := make([]gitprovider.RepositoryCreateOption, 0, len(opts))
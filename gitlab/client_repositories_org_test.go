@@ -0,0 +1,413 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// fakeProjectPagesClient is a gitlabClient serving the projects of a group in pages, like the
+// real server does. Calling any other method than the overridden ones panics.
+type fakeProjectPagesClient struct {
+	gitlabClient
+
+	projects []*gitlab.Project
+	// omitTotals makes the server omit the X-Total* headers, like it does for large collections
+	omitTotals bool
+}
+
+func (c *fakeProjectPagesClient) ListGroupProjectsPage(_ context.Context, _ string, opts gitprovider.PageOptions, _, _ string) ([]*gitlab.Project, gitprovider.PageInfo, error) {
+	page, perPage := opts.Page, opts.PerPage
+	if page == 0 {
+		page = 1
+	}
+	if perPage == 0 {
+		perPage = 20
+	}
+	totalPages := (len(c.projects) + perPage - 1) / perPage
+	start, end := (page-1)*perPage, page*perPage
+	if start > len(c.projects) {
+		start = len(c.projects)
+	}
+	if end > len(c.projects) {
+		end = len(c.projects)
+	}
+
+	resp := &gitlab.Response{Response: &http.Response{Header: http.Header{}}}
+	if page < totalPages {
+		resp.NextPage = page + 1
+	}
+	if !c.omitTotals {
+		resp.TotalItems, resp.TotalPages = len(c.projects), totalPages
+		resp.Header.Set("X-Total", strconv.Itoa(resp.TotalItems))
+		resp.Header.Set("X-Total-Pages", strconv.Itoa(resp.TotalPages))
+	}
+	return c.projects[start:end], pageInfoFromResponse(resp), nil
+}
+
+func newFakeProjects(n int) []*gitlab.Project {
+	projects := make([]*gitlab.Project, 0, n)
+	for i := 0; i < n; i++ {
+		projects = append(projects, &gitlab.Project{Name: fmt.Sprintf("project-%d", i)})
+	}
+	return projects
+}
+
+func TestOrgRepositoriesClient_ListPage(t *testing.T) {
+	tests := []struct {
+		name          string
+		projects      int
+		perPage       int
+		omitTotals    bool
+		wantPageSizes []int
+		wantLastInfo  gitprovider.PageInfo
+	}{
+		{
+			name:          "multiple pages",
+			projects:      5,
+			perPage:       2,
+			wantPageSizes: []int{2, 2, 1},
+			wantLastInfo:  gitprovider.PageInfo{NextPage: 0, TotalCount: 5, TotalPages: 3},
+		},
+		{
+			name:          "full last page",
+			projects:      4,
+			perPage:       2,
+			wantPageSizes: []int{2, 2},
+			wantLastInfo:  gitprovider.PageInfo{NextPage: 0, TotalCount: 4, TotalPages: 2},
+		},
+		{
+			name:          "single page with the default size",
+			projects:      3,
+			wantPageSizes: []int{3},
+			wantLastInfo:  gitprovider.PageInfo{NextPage: 0, TotalCount: 3, TotalPages: 1},
+		},
+		{
+			name:          "no projects",
+			perPage:       2,
+			wantPageSizes: []int{0},
+			wantLastInfo:  gitprovider.PageInfo{NextPage: 0, TotalCount: 0, TotalPages: 0},
+		},
+		{
+			name:          "totals omitted for large collections",
+			projects:      3,
+			perPage:       2,
+			omitTotals:    true,
+			wantPageSizes: []int{2, 1},
+			wantLastInfo:  gitprovider.PageInfo{NextPage: 0, TotalCount: gitprovider.UnknownCount, TotalPages: gitprovider.UnknownCount},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fake := &fakeProjectPagesClient{projects: newFakeProjects(tt.projects), omitTotals: tt.omitTotals}
+			c := &OrgRepositoriesClient{
+				clientContext: &clientContext{c: fake, domain: DefaultDomain},
+			}
+			ref := gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"}
+
+			// Follow NextPage until the last page, like a caller would
+			pageSizes := []int{}
+			total := 0
+			opts := gitprovider.PageOptions{PerPage: tt.perPage}
+			for {
+				repos, pageInfo, err := c.ListPage(ctx, ref, opts)
+				if err != nil {
+					t.Fatalf("ListPage() error = %v", err)
+				}
+				pageSizes = append(pageSizes, len(repos))
+				total += len(repos)
+				if pageInfo.IsLastPage() {
+					if pageInfo != tt.wantLastInfo {
+						t.Errorf("ListPage() last page info = %+v, want %+v", pageInfo, tt.wantLastInfo)
+					}
+					break
+				}
+				if len(pageSizes) > tt.projects+1 {
+					t.Fatalf("ListPage() never reported the last page, got NextPage %d", pageInfo.NextPage)
+				}
+				opts.Page = pageInfo.NextPage
+			}
+
+			if !reflect.DeepEqual(pageSizes, tt.wantPageSizes) {
+				t.Errorf("ListPage() page sizes = %v, want %v", pageSizes, tt.wantPageSizes)
+			}
+			if total != tt.projects {
+				t.Errorf("ListPage() returned %d repositories in total, want %d", total, tt.projects)
+			}
+		})
+	}
+}
+
+// fakeProjectsClient is a gitlabClient that keeps the projects of a single group in memory, and
+// populates the status fields like the real server does. Calling any other method than the
+// overridden ones panics.
+type fakeProjectsClient struct {
+	gitlabClient
+
+	projects map[string]*gitlab.Project
+	nextID   int
+	creates  int
+	updates  int
+}
+
+func (c *fakeProjectsClient) GetGroupProject(_ context.Context, _ string, projectName string) (*gitlab.Project, error) {
+	apiObj, ok := c.projects[projectName]
+	if !ok {
+		return nil, gitprovider.ErrNotFound
+	}
+	project := *apiObj
+	return &project, nil
+}
+
+func (c *fakeProjectsClient) CreateProject(_ context.Context, req *gitlab.Project) (*gitlab.Project, error) {
+	c.creates++
+	c.nextID++
+	apiObj := *req
+	apiObj.ID = c.nextID
+	apiObj.WebURL = fmt.Sprintf("https://gitlab.com/foo/%s", req.Name)
+	if c.projects == nil {
+		c.projects = map[string]*gitlab.Project{}
+	}
+	c.projects[req.Name] = &apiObj
+	project := apiObj
+	return &project, nil
+}
+
+func (c *fakeProjectsClient) UpdateProject(_ context.Context, req *gitlab.Project) (*gitlab.Project, error) {
+	c.updates++
+	for _, apiObj := range c.projects {
+		if apiObj.ID != req.ID {
+			continue
+		}
+		apiObj.Description = req.Description
+		apiObj.DefaultBranch = req.DefaultBranch
+		apiObj.Visibility = req.Visibility
+		apiObj.IssuesEnabled = req.IssuesEnabled
+		apiObj.WikiEnabled = req.WikiEnabled
+		if req.TagList != nil {
+			apiObj.TagList = req.TagList
+		}
+		project := *apiObj
+		return &project, nil
+	}
+	return nil, gitprovider.ErrNotFound
+}
+
+func (c *fakeProjectsClient) GetProjectForkingSettings(_ context.Context, _ int) (*projectForkingSettings, error) {
+	return &projectForkingSettings{ForkingAccessLevel: gitlab.AccessControl(gitlab.EnabledAccessControl)}, nil
+}
+
+func TestOrgRepositoriesClient_Reconcile(t *testing.T) {
+	existing := func() *gitlab.Project {
+		return &gitlab.Project{
+			ID:            1,
+			Name:          "bar",
+			Description:   "The bar project",
+			DefaultBranch: "master",
+			Visibility:    gitlab.PrivateVisibility,
+			IssuesEnabled: true,
+			WikiEnabled:   true,
+			// Status fields populated by the server shouldn't be detected as drift
+			WebURL:    "https://gitlab.com/foo/bar",
+			Namespace: &gitlab.ProjectNamespace{ID: 42, Name: "foo", Kind: "group"},
+		}
+	}
+	tests := []struct {
+		name           string
+		projects       map[string]*gitlab.Project
+		req            gitprovider.RepositoryInfo
+		wantAction     bool
+		wantCreates    int
+		wantUpdates    int
+		wantVisibility gitprovider.RepositoryVisibility
+	}{
+		{
+			name: "create when not found",
+			req: gitprovider.RepositoryInfo{
+				Description: gitprovider.StringVar("The bar project"),
+				Visibility:  gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityInternal),
+			},
+			wantAction:     true,
+			wantCreates:    1,
+			wantVisibility: gitprovider.RepositoryVisibilityInternal,
+		},
+		{
+			name:     "no-op when in sync",
+			projects: map[string]*gitlab.Project{"bar": existing()},
+			req: gitprovider.RepositoryInfo{
+				Description: gitprovider.StringVar("The bar project "),
+			},
+			wantVisibility: gitprovider.RepositoryVisibilityPrivate,
+		},
+		{
+			name:     "update the drifted visibility",
+			projects: map[string]*gitlab.Project{"bar": existing()},
+			req: gitprovider.RepositoryInfo{
+				Description: gitprovider.StringVar("The bar project"),
+				Visibility:  gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic),
+			},
+			wantAction:     true,
+			wantUpdates:    1,
+			wantVisibility: gitprovider.RepositoryVisibilityPublic,
+		},
+		{
+			name:     "update the drifted default branch",
+			projects: map[string]*gitlab.Project{"bar": existing()},
+			req: gitprovider.RepositoryInfo{
+				Description:   gitprovider.StringVar("The bar project"),
+				DefaultBranch: gitprovider.StringVar("develop"),
+			},
+			wantAction:     true,
+			wantUpdates:    1,
+			wantVisibility: gitprovider.RepositoryVisibilityPrivate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			fake := &fakeProjectsClient{projects: tt.projects, nextID: 1}
+			c := &OrgRepositoriesClient{
+				clientContext: &clientContext{c: fake, domain: DefaultDomain},
+			}
+			ref := gitprovider.OrgRepositoryRef{
+				OrganizationRef: gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"},
+				RepositoryName:  "bar",
+			}
+
+			repo, actionTaken, err := c.Reconcile(ctx, ref, tt.req)
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if actionTaken != tt.wantAction {
+				t.Errorf("Reconcile() actionTaken = %v, want %v", actionTaken, tt.wantAction)
+			}
+			if fake.creates != tt.wantCreates || fake.updates != tt.wantUpdates {
+				t.Errorf("Reconcile() made %d creates and %d updates, want %d and %d", fake.creates, fake.updates, tt.wantCreates, tt.wantUpdates)
+			}
+			if got := *repo.Get().Visibility; got != tt.wantVisibility {
+				t.Errorf("Reconcile() visibility = %q, want %q", got, tt.wantVisibility)
+			}
+
+			// Reconciling the same request again must be a no-op, on the client and the resource
+			if _, actionTaken, err := c.Reconcile(ctx, ref, tt.req); err != nil || actionTaken {
+				t.Errorf("second Reconcile() = %v, %v, want false, nil", actionTaken, err)
+			}
+			actual, err := c.Get(ctx, ref)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if actionTaken, err := actual.Reconcile(ctx); err != nil || actionTaken {
+				t.Errorf("resource Reconcile() = %v, %v, want false, nil", actionTaken, err)
+			}
+		})
+	}
+}
+
+// fakeGroupProjectsClient is a gitlabClient listing the projects of a group, filtered by visibility
+// like the real server does. Calling any other method than the overridden ones panics.
+type fakeGroupProjectsClient struct {
+	gitlabClient
+
+	projects []*gitlab.Project
+}
+
+func (c *fakeGroupProjectsClient) ListGroupProjectsMatching(_ context.Context, _ string, visibility *gitlab.VisibilityValue, _ *bool, _, _ string) ([]*gitlab.Project, error) {
+	apiObjs := make([]*gitlab.Project, 0, len(c.projects))
+	for _, apiObj := range c.projects {
+		if visibility == nil || apiObj.Visibility == *visibility {
+			apiObjs = append(apiObjs, apiObj)
+		}
+	}
+	return apiObjs, nil
+}
+
+func TestOrgRepositoriesClient_ListRepositories(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      gitprovider.RepositoryListOptions
+		wantNames []string
+		wantErr   error
+	}{
+		{
+			name:      "no filters",
+			wantNames: []string{"api", "docs", "web"},
+		},
+		{
+			name:      "internal visibility",
+			opts:      gitprovider.RepositoryListOptions{Visibility: gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityInternal)},
+			wantNames: []string{"docs"},
+		},
+		{
+			name:      "topic, compared case-insensitively",
+			opts:      gitprovider.RepositoryListOptions{Topic: "GO"},
+			wantNames: []string{"api", "web"},
+		},
+		{
+			name: "visibility and topic",
+			opts: gitprovider.RepositoryListOptions{
+				Visibility: gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic),
+				Topic:      "go",
+			},
+			wantNames: []string{"web"},
+		},
+		{
+			name:    "unknown visibility",
+			opts:    gitprovider.RepositoryListOptions{Visibility: gitprovider.RepositoryVisibilityVar("secret")},
+			wantErr: validation.ErrFieldEnumInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeGroupProjectsClient{projects: []*gitlab.Project{
+				{Name: "api", Visibility: gitlab.PrivateVisibility, TagList: []string{"go"}},
+				{Name: "docs", Visibility: gitlab.InternalVisibility, TagList: []string{"frontend"}},
+				{Name: "web", Visibility: gitlab.PublicVisibility, TagList: []string{"frontend", "go"}},
+			}}
+			c := &OrgRepositoriesClient{
+				clientContext: &clientContext{c: fake, domain: DefaultDomain},
+			}
+			ref := gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"}
+
+			repos, err := c.ListRepositories(context.Background(), ref, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ListRepositories() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			names := make([]string, 0, len(repos))
+			for _, repo := range repos {
+				names = append(names, repo.Repository().GetRepository())
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("ListRepositories() = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
@@ -19,8 +19,11 @@ package gitlab
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+	"github.com/xanzy/go-gitlab"
 )
 
 // UserRepositoriesClient implements the gitprovider.UserRepositoriesClient interface.
@@ -44,7 +47,12 @@ func (c *UserRepositoriesClient) Get(ctx context.Context, ref gitprovider.UserRe
 	if err != nil {
 		return nil, err
 	}
-	return newUserProject(c.clientContext, apiObj, ref), nil
+	repo := newUserProject(c.clientContext, apiObj, ref)
+	// go-gitlab doesn't provide a field for AllowForking (yet), hence request it separately
+	if err := repo.getAllowForking(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
 }
 
 // List all repositories in the given organization.
@@ -62,16 +70,38 @@ func (c *UserRepositoriesClient) List(ctx context.Context, ref gitprovider.UserR
 		return nil, err
 	}
 
-	// Traverse the list, and return a list of UserRepository objects
+	return c.userRepositoriesFromAPI(ref, apiObjs), nil
+}
+
+// ListPage lists one page of repositories, along with the pagination metadata supplied by the provider.
+func (c *UserRepositoriesClient) ListPage(ctx context.Context, ref gitprovider.UserRef, opts gitprovider.PageOptions) ([]gitprovider.UserRepository, gitprovider.PageInfo, error) {
+	// Make sure the UserRef and options are valid
+	if err := validateUserRef(ref, c.domain); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+
+	// GET /users/{username}/projects
+	apiObjs, pageInfo, err := c.c.ListUserProjectsPage(ctx, ref.UserLogin, opts)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	return c.userRepositoriesFromAPI(ref, apiObjs), pageInfo, nil
+}
+
+// userRepositoriesFromAPI traverses the list, and returns a list of UserRepository objects.
+func (c *UserRepositoriesClient) userRepositoriesFromAPI(ref gitprovider.UserRef, apiObjs []*gitlab.Project) []gitprovider.UserRepository {
 	repos := make([]gitprovider.UserRepository, 0, len(apiObjs))
 	for _, apiObj := range apiObjs {
-		// apiObj is already validated at ListUserRepos
+		// apiObj is already validated at ListUserProjects or ListUserProjectsPage
 		repos = append(repos, newUserProject(c.clientContext, apiObj, gitprovider.UserRepositoryRef{
 			UserRef:        ref,
 			RepositoryName: apiObj.Name,
 		}))
 	}
-	return repos, nil
+	return repos
 }
 
 // Create creates a repository for the given organization, with the data and options
@@ -91,7 +121,15 @@ func (c *UserRepositoriesClient) Create(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	return newUserProject(c.clientContext, apiObj, ref), nil
+	repo := newUserProject(c.clientContext, apiObj, ref)
+	// The project object can't carry AllowForking, hence apply it separately
+	if req.AllowForking != nil {
+		repo.allowForking = req.AllowForking
+		if err := repo.updateAllowForking(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return repo, nil
 }
 
 // Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
@@ -121,3 +159,23 @@ func (c *UserRepositoriesClient) Reconcile(ctx context.Context, ref gitprovider.
 	actionTaken, err := reconcileRepository(ctx, actual, req)
 	return actual, actionTaken, err
 }
+
+// GetAuthenticatedUser returns the login and domain of the user the client is authenticated as.
+//
+// An *InvalidCredentialsError is returned if the client isn't authenticated, or the token is invalid.
+func (c *UserRepositoriesClient) GetAuthenticatedUser(ctx context.Context) (gitprovider.UserInfo, error) {
+	// GET /user
+	apiObj, err := c.c.GetCurrentUser(ctx)
+	if err != nil {
+		return gitprovider.UserInfo{}, fmt.Errorf("failed to get the authenticated user: %w", err)
+	}
+	return gitprovider.UserInfo{Domain: c.domain, Login: apiObj.Username}, nil
+}
+
+func validateUserAPI(apiObj *gitlab.User) error {
+	return validateAPIObject("GitLab.User", func(validator validation.Validator) {
+		if apiObj.Username == "" {
+			validator.Required("Username")
+		}
+	})
+}
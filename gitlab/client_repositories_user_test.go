@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+func TestUserRepositoriesClient_GetAuthenticatedUser(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/user" {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "401 Unauthorized"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 1, "username": "fluxcd-bot", "name": "Flux"}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		token   string
+		want    gitprovider.UserInfo
+		wantErr bool
+	}{
+		{
+			name:  "authenticated",
+			token: "secret",
+			want:  gitprovider.UserInfo{Domain: DefaultDomain, Login: "fluxcd-bot"},
+		},
+		{
+			name:    "unauthenticated",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gl, err := gitlab.NewClient(tt.token, gitlab.WithBaseURL(srv.URL+"/api/v4/"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := &UserRepositoriesClient{
+				clientContext: &clientContext{c: &gitlabClientImpl{gl, false}, domain: DefaultDomain},
+			}
+			got, err := c.GetAuthenticatedUser(context.Background())
+			if tt.wantErr {
+				var credsErr *gitprovider.InvalidCredentialsError
+				if !errors.As(err, &credsErr) {
+					t.Fatalf("GetAuthenticatedUser() error = %v, want %T", err, credsErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetAuthenticatedUser() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetAuthenticatedUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
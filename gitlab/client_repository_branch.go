@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// BranchClient implements the gitprovider.BranchClient interface.
+var _ gitprovider.BranchClient = &BranchClient{}
+
+// BranchClient operates on the branches of a specific project.
+type BranchClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get a branch by its name.
+//
+// ErrNotFound is returned if the branch does not exist.
+func (c *BranchClient) Get(ctx context.Context, name string) (gitprovider.Branch, error) {
+	// GET /projects/{project}/repository/branches/{branch}
+	apiObj, err := c.c.GetBranch(ctx, getRepoPath(c.ref), name)
+	if err != nil {
+		return nil, err
+	}
+	return newBranch(c.clientContext, apiObj, c.ref), nil
+}
+
+// List lists all branches of the project, along with whether they are protected.
+//
+// List returns all available branches, using multiple paginated requests if needed.
+func (c *BranchClient) List(ctx context.Context) ([]gitprovider.Branch, error) {
+	if err := validateRepositoryRef(c.ref, c.domain); err != nil {
+		return nil, err
+	}
+	// GET /projects/{project}/repository/branches
+	apiObjs, err := c.c.ListBranches(ctx, getRepoPath(c.ref))
+	if err != nil {
+		return nil, err
+	}
+	// Map the api object to our Branch type
+	branches := make([]gitprovider.Branch, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListBranches
+		branches = append(branches, newBranch(c.clientContext, apiObj, c.ref))
+	}
+	return branches, nil
+}
+
+// Create creates a branch with the given name, pointing at the commit fromSHA. If fromSHA is
+// empty, the branch points at the head of the default branch of the project.
+//
+// ErrAlreadyExists is returned if the branch already exists.
+// ErrNotFound is returned if fromSHA isn't a commit of the project.
+func (c *BranchClient) Create(ctx context.Context, branchName, fromSHA string) error {
+	if err := gitprovider.ValidateBranchName(branchName); err != nil {
+		return validation.NewMultiError(err, gitprovider.ErrInvalidArgument)
+	}
+	projectName := getRepoPath(c.ref)
+
+	// GET /projects/{project}/repository/branches/{branch}
+	_, err := c.c.GetBranch(ctx, projectName, branchName)
+	if err == nil {
+		return gitprovider.ErrAlreadyExists
+	} else if !errors.Is(err, gitprovider.ErrNotFound) {
+		return err
+	}
+
+	ref := fromSHA
+	if ref == "" {
+		// GitLab accepts a branch name as ref, hence there's no need to resolve the default branch head
+		// GET /projects/{project}
+		apiObj, err := c.c.GetUserProject(ctx, projectName)
+		if err != nil {
+			return err
+		}
+		ref = apiObj.DefaultBranch
+	} else {
+		// GitLab rejects unknown refs with a generic 400 Bad Request, hence look up the commit first
+		// GET /projects/{project}/repository/commits/{sha}
+		if _, err := c.c.GetCommit(ctx, projectName, fromSHA); err != nil {
+			return err
+		}
+	}
+	// POST /projects/{project}/repository/branches
+	_, err = c.c.CreateBranch(ctx, projectName, branchName, ref)
+	return err
+}
+
+// IsBranchMerged returns true if all commits of the given branch are part of the default branch
+// of the project, as determined by GitLab.
+//
+// ErrNotFound is returned if the branch does not exist.
+func (c *BranchClient) IsBranchMerged(ctx context.Context, branch string) (bool, error) {
+	// GET /projects/{project}/repository/branches/{branch}
+	apiObj, err := c.c.GetBranch(ctx, getRepoPath(c.ref), branch)
+	if err != nil {
+		return false, err
+	}
+	return apiObj.Merged, nil
+}
+
+// DeleteMergedBranches deletes the merged branches whose last commit is older than opts.MinAge.
+// The default branch and protected branches are never deleted. This requires destructive actions
+// to be enabled in the client.
+//
+// The names of the deleted branches are returned, along with the reason why each other branch was
+// skipped. If some branches failed to be deleted, a *validation.MultiError with an error per branch
+// is returned too.
+func (c *BranchClient) DeleteMergedBranches(ctx context.Context, opts gitprovider.DeleteMergedBranchesOptions) ([]string, map[string]gitprovider.BranchSkipReason, error) {
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, nil, err
+	}
+	if !c.destructiveActions {
+		return nil, nil, fmt.Errorf("cannot delete merged branches: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	projectName := getRepoPath(c.ref)
+
+	// GET /projects/{project}/repository/branches
+	apiObjs, err := c.c.ListBranches(ctx, projectName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The branch objects tell everything needed to decide, hence only deleting needs requests
+	skipped := map[string]gitprovider.BranchSkipReason{}
+	candidates := []string{}
+	for _, apiObj := range apiObjs {
+		if reason := branchSkipReason(apiObj, opts.MinAge); reason != "" {
+			skipped[apiObj.Name] = reason
+		} else {
+			candidates = append(candidates, apiObj.Name)
+		}
+	}
+
+	errs := make([]error, len(candidates))
+	forEachBounded(len(candidates), opts.GetConcurrency(), func(i int) {
+		// DELETE /projects/{project}/repository/branches/{branch}
+		if err := c.c.DeleteBranch(ctx, projectName, candidates[i]); err != nil {
+			errs[i] = fmt.Errorf("failed to delete branch %q: %w", candidates[i], err)
+		}
+	})
+
+	deleted := []string{}
+	failed := []error{}
+	for i, name := range candidates {
+		if errs[i] != nil {
+			failed = append(failed, errs[i])
+		} else {
+			deleted = append(deleted, name)
+		}
+	}
+	if len(failed) != 0 {
+		return deleted, skipped, validation.NewMultiError(failed...)
+	}
+	return deleted, skipped, nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/xanzy/go-gitlab"
+)
+
+// CommitClient implements the gitprovider.CommitClient interface.
+var _ gitprovider.CommitClient = &CommitClient{}
+
+// CommitClient operates on the commits of a specific project.
+type CommitClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// ListPage lists the commits of the given branch, newest first, in pages of perPage commits.
+// GitLab returns the signature of a commit separately, hence the verification status is only
+// populated if opts.IncludeVerification is set, making one more request per commit.
+func (c *CommitClient) ListPage(ctx context.Context, branch string, perPage, page int, opts gitprovider.CommitListOptions) ([]gitprovider.Commit, error) {
+	// GET /projects/{project}/repository/commits
+	apiObjs, err := c.c.ListCommitsPage(ctx, getRepoPath(c.ref), branch, perPage, page)
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]gitprovider.Commit, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListCommitsPage
+		commit := newCommit(c.clientContext, apiObj, c.ref)
+		if opts.IncludeVerification {
+			// GET /projects/{project}/repository/commits/{sha}/signature
+			signature, err := c.c.GetCommitSignature(ctx, getRepoPath(c.ref), apiObj.ID)
+			if err != nil {
+				return nil, err
+			}
+			commit.verification = verificationFromSignature(signature)
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// Create creates a commit on top of the given branch, changing the given files, and moves the
+// branch to it. Files with nil content are deleted.
+func (c *CommitClient) Create(ctx context.Context, branch string, message string, files []gitprovider.CommitFile) (gitprovider.Commit, error) {
+	if err := gitprovider.ValidateCommitRequest(branch, message, files); err != nil {
+		return nil, err
+	}
+	projectName := getRepoPath(c.ref)
+
+	// GitLab requires to tell apart creating and updating files, hence look up the existing files
+	actions := make([]*gitlab.CommitAction, 0, len(files))
+	for _, file := range files {
+		action := &gitlab.CommitAction{FilePath: file.Path, Action: gitlab.FileDelete}
+		if file.Content != nil {
+			action.Content = *file.Content
+			// HEAD /projects/{project}/repository/files/{file_path}
+			_, err := c.c.GetFileMetaData(ctx, projectName, file.Path, branch)
+			switch {
+			case errors.Is(err, gitprovider.ErrNotFound):
+				action.Action = gitlab.FileCreate
+			case err != nil:
+				return nil, err
+			default:
+				action.Action = gitlab.FileUpdate
+			}
+		}
+		actions = append(actions, action)
+	}
+
+	// POST /projects/{project}/repository/commits
+	apiObj, err := c.c.CreateCommit(ctx, projectName, &gitlab.CreateCommitOptions{
+		Branch:        &branch,
+		CommitMessage: &message,
+		Actions:       actions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newCommit(c.clientContext, apiObj, c.ref), nil
+}
+
+// CommitCount returns the amount of commits of the default branch, as counted in the project
+// statistics. An empty branch means the default branch.
+//
+// UnknownCount (-1) is returned for other branches, which GitLab doesn't count, or if the user
+// isn't allowed to read the project statistics.
+func (c *CommitClient) CommitCount(ctx context.Context, branch string) (int64, error) {
+	// GET /projects/{project}?statistics=true
+	apiObj, err := c.c.GetProjectWithStatistics(ctx, getRepoPath(c.ref))
+	if err != nil {
+		return 0, err
+	}
+	if (branch != "" && branch != apiObj.DefaultBranch) || apiObj.Statistics == nil {
+		return gitprovider.UnknownCount, nil
+	}
+	return int64(apiObj.Statistics.CommitCount), nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// fakeCommitsClient is a gitlabClient listing a fixed page of commits, along with the signatures of
+// the signed ones. Calling any other method than the overridden ones panics.
+type fakeCommitsClient struct {
+	gitlabClient
+
+	commits    []*gitlab.Commit
+	signatures map[string]*gitlab.GPGSignature
+	// signatureRequests is the amount of signatures requested.
+	signatureRequests int
+}
+
+func (c *fakeCommitsClient) ListCommitsPage(_ context.Context, _, _ string, _, _ int) ([]*gitlab.Commit, error) {
+	return c.commits, nil
+}
+
+func (c *fakeCommitsClient) GetCommitSignature(_ context.Context, _, sha string) (*gitlab.GPGSignature, error) {
+	c.signatureRequests++
+	return c.signatures[sha], nil
+}
+
+func TestCommitClient_ListPage_verification(t *testing.T) {
+	type verification struct {
+		verified *bool
+		reason   string
+	}
+	tests := []struct {
+		name                  string
+		opts                  gitprovider.CommitListOptions
+		want                  []verification
+		wantSignatureRequests int
+	}{
+		{
+			name:                  "not requested",
+			want:                  []verification{{nil, ""}, {nil, ""}, {nil, ""}},
+			wantSignatureRequests: 0,
+		},
+		{
+			name: "requested",
+			opts: gitprovider.CommitListOptions{IncludeVerification: true},
+			want: []verification{
+				{gitprovider.BoolVar(true), "verified"},
+				{gitprovider.BoolVar(false), "unverified"},
+				{gitprovider.BoolVar(false), "unsigned"},
+			},
+			wantSignatureRequests: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeCommitsClient{
+				commits: []*gitlab.Commit{{ID: "signed"}, {ID: "bad-signature"}, {ID: "unsigned"}},
+				signatures: map[string]*gitlab.GPGSignature{
+					"signed":        {VerificationStatus: "verified"},
+					"bad-signature": {VerificationStatus: "unverified"},
+				},
+			}
+			c := &CommitClient{
+				clientContext: &clientContext{c: fake, domain: DefaultDomain},
+				ref: gitprovider.UserRepositoryRef{
+					UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+					RepositoryName: "bar",
+				},
+			}
+
+			commits, err := c.ListPage(context.Background(), "main", 10, 1, tt.opts)
+			if err != nil {
+				t.Fatalf("ListPage() error = %v", err)
+			}
+			if len(commits) != len(tt.want) {
+				t.Fatalf("ListPage() returned %d commits, want %d", len(commits), len(tt.want))
+			}
+			for i, commit := range commits {
+				info := commit.Get()
+				if !reflect.DeepEqual(info.Verified, tt.want[i].verified) || info.VerificationReason != tt.want[i].reason {
+					t.Errorf("commit %s verification = %v, %q, want %v, %q",
+						info.Sha, info.Verified, info.VerificationReason, tt.want[i].verified, tt.want[i].reason)
+				}
+			}
+			if fake.signatureRequests != tt.wantSignatureRequests {
+				t.Errorf("got %d signature requests, want %d", fake.signatureRequests, tt.wantSignatureRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// RepositoryHookClient implements the gitprovider.RepositoryHookClient interface.
+var _ gitprovider.RepositoryHookClient = &RepositoryHookClient{}
+
+// RepositoryHookClient operates on the webhooks of a specific repository.
+//
+// This is not supported (yet) in GitLab.
+// All methods return gitprovider.ErrNoProviderSupport.
+type RepositoryHookClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the webhook delivering to the given URL.
+func (c *RepositoryHookClient) Get(_ context.Context, _ string) (gitprovider.RepositoryHook, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List lists all webhooks of the repository.
+func (c *RepositoryHookClient) List(_ context.Context) ([]gitprovider.RepositoryHook, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create creates a webhook with the given specifications.
+func (c *RepositoryHookClient) Create(_ context.Context, _ gitprovider.RepositoryHookInfo, _ gitprovider.RepositoryHookCreateOptions) (gitprovider.RepositoryHook, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+func (c *RepositoryHookClient) Reconcile(_ context.Context, _ gitprovider.RepositoryHookInfo) (gitprovider.RepositoryHook, bool, error) {
+	return nil, false, gitprovider.ErrNoProviderSupport
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/xanzy/go-gitlab"
+)
+
+// IssueClient implements the gitprovider.IssueClient interface.
+var _ gitprovider.IssueClient = &IssueClient{}
+
+// IssueClient operates on the issues of a specific project.
+type IssueClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Create creates an open issue with the given title, body and labels. GitLab creates the labels
+// that don't exist in the project yet.
+// The title must not be empty.
+func (c *IssueClient) Create(ctx context.Context, title, body string, labels []string) (gitprovider.Issue, error) {
+	if err := gitprovider.ValidateIssueRequest(title, labels); err != nil {
+		return nil, err
+	}
+	req := &gitlab.CreateIssueOptions{
+		Title:       &title,
+		Description: &body,
+	}
+	if len(labels) != 0 {
+		apiLabels := gitlab.Labels(labels)
+		req.Labels = &apiLabels
+	}
+	// POST /projects/{project}/issues
+	apiObj, err := c.c.CreateIssue(ctx, getRepoPath(c.ref), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue %q: %w", title, err)
+	}
+	return newIssue(c.clientContext, apiObj, c.ref), nil
+}
+
+// List lists the issues of the project, newest first, optionally filtered by state and label.
+//
+// List returns all available issues, using multiple paginated requests if needed.
+func (c *IssueClient) List(ctx context.Context, opts gitprovider.IssueListOptions) ([]gitprovider.Issue, error) {
+	if err := opts.ValidateOptions(); err != nil {
+		return nil, err
+	}
+	// GET /projects/{project}/issues
+	apiObjs, err := c.c.ListIssues(ctx, getRepoPath(c.ref), issueListOptions(opts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	issues := make([]gitprovider.Issue, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListIssues
+		issues = append(issues, newIssue(c.clientContext, apiObj, c.ref))
+	}
+	return issues, nil
+}
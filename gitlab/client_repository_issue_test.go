@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// fakeIssuesClient is a gitlabClient serving a fixed set of issues, filtered by state and label like
+// GitLab does. Calling any other method than the overridden ones panics.
+type fakeIssuesClient struct {
+	gitlabClient
+
+	issues []*gitlab.Issue
+}
+
+func (c *fakeIssuesClient) ListIssues(_ context.Context, _ string, opts *gitlab.ListProjectIssuesOptions) ([]*gitlab.Issue, error) {
+	apiObjs := []*gitlab.Issue{}
+	for _, apiObj := range c.issues {
+		if opts.State != nil && apiObj.State != *opts.State {
+			continue
+		}
+		if len(opts.Labels) != 0 && !issueHasLabel(apiObj, opts.Labels[0]) {
+			continue
+		}
+		apiObjs = append(apiObjs, apiObj)
+	}
+	return apiObjs, nil
+}
+
+func issueHasLabel(apiObj *gitlab.Issue, label string) bool {
+	for _, l := range apiObj.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func TestIssueClient_List(t *testing.T) {
+	c := &IssueClient{
+		clientContext: &clientContext{c: &fakeIssuesClient{issues: []*gitlab.Issue{
+			{IID: 1, Title: "Crash", State: "opened", Labels: gitlab.Labels{"bug"}},
+			{IID: 2, Title: "Typo", State: "closed"},
+			{IID: 3, Title: "Old crash", State: "closed", Labels: gitlab.Labels{"bug"}},
+		}}, domain: DefaultDomain},
+		ref: gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+			RepositoryName: "bar",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		opts      gitprovider.IssueListOptions
+		want      []int
+		wantState gitprovider.IssueState
+	}{
+		{
+			name: "all states",
+			want: []int{1, 2, 3},
+		},
+		{
+			name:      "open",
+			opts:      gitprovider.IssueListOptions{State: gitprovider.IssueStateVar(gitprovider.IssueStateOpen)},
+			want:      []int{1},
+			wantState: gitprovider.IssueStateOpen,
+		},
+		{
+			name:      "closed",
+			opts:      gitprovider.IssueListOptions{State: gitprovider.IssueStateVar(gitprovider.IssueStateClosed)},
+			want:      []int{2, 3},
+			wantState: gitprovider.IssueStateClosed,
+		},
+		{
+			name:      "open with label",
+			opts:      gitprovider.IssueListOptions{State: gitprovider.IssueStateVar(gitprovider.IssueStateOpen), Label: "bug"},
+			want:      []int{1},
+			wantState: gitprovider.IssueStateOpen,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := c.List(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			got := []int{}
+			for _, issue := range issues {
+				info := issue.Get()
+				got = append(got, info.Number)
+				// GitLab's "opened" state is mapped to IssueStateOpen
+				if tt.wantState != "" && info.State != tt.wantState {
+					t.Errorf("List() state of #%d = %q, want %q", info.Number, info.State, tt.wantState)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// ReleaseClient implements the gitprovider.ReleaseClient interface.
+var _ gitprovider.ReleaseClient = &ReleaseClient{}
+
+// ReleaseClient operates on the releases of a specific project.
+type ReleaseClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// LatestRelease returns the release with the most recent release date. GitLab doesn't have draft
+// releases, and upcoming releases, i.e. with a release date in the future, are considered
+// prereleases, hence they are never the latest release. This matches GitHub's behavior.
+//
+// ErrNotFound is returned if the project has no (released) releases.
+func (c *ReleaseClient) LatestRelease(ctx context.Context) (gitprovider.ReleaseInfo, error) {
+	// GET /projects/{project}/releases
+	apiObjs, err := c.c.ListProjectReleases(ctx, getRepoPath(c.ref))
+	if err != nil {
+		return gitprovider.ReleaseInfo{}, err
+	}
+	latest := latestRelease(apiObjs)
+	if latest == nil {
+		return gitprovider.ReleaseInfo{}, gitprovider.ErrNotFound
+	}
+	return releaseFromAPI(latest), nil
+}
+
+// Get returns the release made from the given tag.
+//
+// ErrNotFound is returned if the tag has no release, or doesn't exist.
+func (c *ReleaseClient) Get(ctx context.Context, tag string) (gitprovider.Release, error) {
+	// GET /projects/{project}/releases/{tag_name}
+	apiObj, err := c.c.GetProjectRelease(ctx, getRepoPath(c.ref), tag)
+	if err != nil {
+		return nil, err
+	}
+	return newRelease(c.clientContext, apiObj, c.ref), nil
+}
+
+// List lists all releases of the project, newest (by release date) first, which is GitLab's
+// default order.
+//
+// List returns all available releases, using multiple paginated requests if needed.
+func (c *ReleaseClient) List(ctx context.Context) ([]gitprovider.Release, error) {
+	// GET /projects/{project}/releases
+	apiObjs, err := c.c.ListProjectReleases(ctx, getRepoPath(c.ref))
+	if err != nil {
+		return nil, err
+	}
+	releases := make([]gitprovider.Release, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListProjectReleases
+		releases = append(releases, newRelease(c.clientContext, apiObj, c.ref))
+	}
+	return releases, nil
+}
+
+// Create creates a release with the given specifications. The tag is created from
+// req.TargetCommitish if it doesn't exist yet.
+//
+// Draft releases and prereleases can't be created in GitLab, ErrNoProviderSupport is returned
+// for them.
+//
+// ErrAlreadyExists will be returned if the tag already has a release.
+func (c *ReleaseClient) Create(ctx context.Context, req gitprovider.ReleaseInfo) (gitprovider.Release, error) {
+	if err := req.ValidateInfo(); err != nil {
+		return nil, err
+	}
+	opts, err := releaseToAPI(&req)
+	if err != nil {
+		return nil, err
+	}
+	// GitLab's error for a duplicate release isn't recognizable, hence check for it first
+	if _, err := c.Get(ctx, req.TagName); err == nil {
+		return nil, fmt.Errorf("release for tag %q: %w", req.TagName, gitprovider.ErrAlreadyExists)
+	} else if !errors.Is(err, gitprovider.ErrNotFound) {
+		return nil, err
+	}
+
+	// POST /projects/{project}/releases
+	apiObj, err := c.c.CreateProjectRelease(ctx, getRepoPath(c.ref), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release for tag %q: %w", req.TagName, err)
+	}
+	return newRelease(c.clientContext, apiObj, c.ref), nil
+}
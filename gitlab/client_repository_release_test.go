@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// newTestReleaseClient returns a ReleaseClient talking to a test server, which only has a release
+// for the "v1.0.0" tag, and records the escaped paths of the requests.
+func newTestReleaseClient(t *testing.T, paths *[]string) *ReleaseClient {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/v4/projects/") {
+			return
+		}
+		*paths = append(*paths, r.Method+" "+r.URL.EscapedPath())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/foo%2Fbar/releases/v1.0.0":
+			_, _ = w.Write([]byte(`{"tag_name": "v1.0.0", "name": "First", "commit": {"id": "abc"}}`))
+		case "/api/v4/projects/foo%2Fbar/releases":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"tag_name": "v1.1.0", "name": "v1.1.0"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "404 Not Found"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	gl, err := gitlab.NewClient("", gitlab.WithBaseURL(srv.URL+"/api/v4/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ReleaseClient{
+		clientContext: &clientContext{c: &gitlabClientImpl{gl, false}, domain: DefaultDomain},
+		ref: gitprovider.UserRepositoryRef{
+			UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+			RepositoryName: "bar",
+		},
+	}
+}
+
+func TestReleaseClient_Get(t *testing.T) {
+	paths := []string{}
+	c := newTestReleaseClient(t, &paths)
+
+	release, err := c.Get(context.Background(), "v1.0.0")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := release.Get(); got.TagName != "v1.0.0" || *got.Name != "First" || *got.TargetCommitish != "abc" {
+		t.Errorf("Get() = %v", got)
+	}
+	// A tag without release is reported as not found
+	if _, err := c.Get(context.Background(), "v2.0.0"); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+}
+
+func TestReleaseClient_Create(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       gitprovider.ReleaseInfo
+		wantErr   error
+		wantPaths []string
+	}{
+		{
+			name: "new tag",
+			req:  gitprovider.ReleaseInfo{TagName: "v1.1.0"},
+			wantPaths: []string{
+				"GET /api/v4/projects/foo%2Fbar/releases/v1.1.0",
+				"POST /api/v4/projects/foo%2Fbar/releases",
+			},
+		},
+		{
+			name:      "tag with release",
+			req:       gitprovider.ReleaseInfo{TagName: "v1.0.0"},
+			wantErr:   gitprovider.ErrAlreadyExists,
+			wantPaths: []string{"GET /api/v4/projects/foo%2Fbar/releases/v1.0.0"},
+		},
+		{
+			name:      "draft",
+			req:       gitprovider.ReleaseInfo{TagName: "v1.1.0", Draft: gitprovider.BoolVar(true)},
+			wantErr:   gitprovider.ErrNoProviderSupport,
+			wantPaths: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := []string{}
+			c := newTestReleaseClient(t, &paths)
+
+			_, err := c.Create(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("Create() requests = %v, want %v", paths, tt.wantPaths)
+			}
+		})
+	}
+}
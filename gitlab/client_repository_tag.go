@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// TagClient implements the gitprovider.TagClient interface.
+var _ gitprovider.TagClient = &TagClient{}
+
+// TagClient operates on the tags of a specific project.
+type TagClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// List lists all tags of the project, along with the commits they point to.
+//
+// List returns all available tags, using multiple paginated requests if needed.
+func (c *TagClient) List(ctx context.Context) ([]gitprovider.Tag, error) {
+	// GET /projects/{project}/repository/tags
+	apiObjs, err := c.c.ListTags(ctx, getRepoPath(c.ref))
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]gitprovider.Tag, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListTags
+		tags = append(tags, newTag(c.clientContext, apiObj, c.ref))
+	}
+	return tags, nil
+}
+
+// Create creates a lightweight tag with the given name, pointing at the commit sha.
+//
+// ErrAlreadyExists is returned if the tag already exists.
+// ErrNotFound is returned if sha isn't a commit of the project.
+func (c *TagClient) Create(ctx context.Context, name, sha string) error {
+	if err := gitprovider.ValidateTagName(name); err != nil {
+		return validation.NewMultiError(err, gitprovider.ErrInvalidArgument)
+	}
+	projectName := getRepoPath(c.ref)
+
+	// GET /projects/{project}/repository/tags/{tag}
+	_, err := c.c.GetTag(ctx, projectName, name)
+	if err == nil {
+		return gitprovider.ErrAlreadyExists
+	} else if !errors.Is(err, gitprovider.ErrNotFound) {
+		return err
+	}
+	// GitLab refuses unknown refs with a generic 400 Bad Request, hence resolve sha first. This also
+	// makes sure sha is a commit, as GitLab would accept a branch name as ref too.
+	// GET /projects/{project}/repository/commits/{sha}
+	commit, err := c.c.GetCommit(ctx, projectName, sha)
+	if err != nil {
+		return err
+	}
+
+	// POST /projects/{project}/repository/tags
+	_, err = c.c.CreateTag(ctx, projectName, name, commit.ID)
+	return err
+}
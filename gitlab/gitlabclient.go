@@ -18,10 +18,14 @@ package gitlab
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/hashicorp/go-retryablehttp"
 	"github.com/xanzy/go-gitlab"
 )
 
@@ -38,6 +42,22 @@ type gitlabClient interface {
 	// GetGroup is a wrapper for "GET /groups/{group}".
 	// This function HTTP error wrapping, and validates the server result.
 	GetGroup(ctx context.Context, groupID interface{}) (*gitlab.Group, error)
+	// GetGroupDefaultBranch is a wrapper for "GET /groups/{group}", which only
+	// decodes the default_branch field.
+	// This function handles HTTP error wrapping.
+	GetGroupDefaultBranch(ctx context.Context, groupID int) (*groupDefaultBranch, error)
+	// UpdateGroupDefaultBranch is a wrapper for "PUT /groups/{group}", which only
+	// updates the default_branch field.
+	// This function handles HTTP error wrapping.
+	UpdateGroupDefaultBranch(ctx context.Context, groupID int, req *groupDefaultBranch) error
+	// UpdateGroupProjectCreationLevel is a wrapper for "PUT /groups/{group}", which only
+	// updates the project_creation_level field.
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateGroupProjectCreationLevel(ctx context.Context, groupID int, level gitlab.ProjectCreationLevelValue) (*gitlab.Group, error)
+	// UpdateGroupMetadata is a wrapper for "PUT /groups/{group}", which only
+	// updates the name and description fields that are set.
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateGroupMetadata(ctx context.Context, groupID int, name, description *string) (*gitlab.Group, error)
 	// ListGroups is a wrapper for "GET /groups".
 	// This function handles pagination, HTTP error wrapping, and validates the server result.
 	ListGroups(ctx context.Context) ([]*gitlab.Group, error)
@@ -48,6 +68,12 @@ type gitlabClient interface {
 	// This function handles pagination, HTTP error wrapping, and validates the server result.
 	ListGroupMembers(ctx context.Context, groupName string) ([]*gitlab.GroupMember, error)
 
+	// User methods
+
+	// GetCurrentUser is a wrapper for "GET /user".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetCurrentUser(ctx context.Context) (*gitlab.User, error)
+
 	// Project methods
 
 	// GetProject is a wrapper for "GET /projects/{project}".
@@ -56,12 +82,27 @@ type gitlabClient interface {
 	// ListGroupProjects is a wrapper for "GET /groups/{group}/projects".
 	// This function handles pagination, HTTP error wrapping, and validates the server result.
 	ListGroupProjects(ctx context.Context, groupName string) ([]*gitlab.Project, error)
+	// ListGroupProjectsMatching is a wrapper for "GET /groups/{group}/projects", with the given filters.
+	// visibility and archived may be nil, search and language may be empty strings, which means no filtering.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListGroupProjectsMatching(ctx context.Context, groupName string, visibility *gitlab.VisibilityValue, archived *bool, search, language string) ([]*gitlab.Project, error)
 	// GetProject is a wrapper for "GET /projects/{project}".
 	// This function handles HTTP error wrapping, and validates the server result.
 	GetUserProject(ctx context.Context, projectName string) (*gitlab.Project, error)
+	// GetProjectWithStatistics is a wrapper for "GET /projects/{project}?statistics=true".
+	// Statistics is nil if the user isn't allowed to read them.
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetProjectWithStatistics(ctx context.Context, projectName string) (*gitlab.Project, error)
 	// ListUserProjects is a wrapper for "GET /users/{username}/projects".
 	// This function handles pagination, HTTP error wrapping, and validates the server result.
 	ListUserProjects(ctx context.Context, username string) ([]*gitlab.Project, error)
+	// ListGroupProjectsPage is a wrapper for one page of "GET /groups/{group}/projects".
+	// This function handles HTTP error wrapping, and validates the server result.
+	// orderBy and sort are passed as-is to the server, and may be empty for the server defaults.
+	ListGroupProjectsPage(ctx context.Context, groupName string, opts gitprovider.PageOptions, orderBy, sort string) ([]*gitlab.Project, gitprovider.PageInfo, error)
+	// ListUserProjectsPage is a wrapper for one page of "GET /users/{username}/projects".
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListUserProjectsPage(ctx context.Context, username string, opts gitprovider.PageOptions) ([]*gitlab.Project, gitprovider.PageInfo, error)
 	// ListProjectUsers is a wrapper for "GET /projects/{project}/users".
 	// This function handles pagination, HTTP error wrapping, and validates the server result.
 	ListProjectUsers(ctx context.Context, projectName string) ([]*gitlab.ProjectUser, error)
@@ -71,6 +112,84 @@ type gitlabClient interface {
 	// UpdateProject is a wrapper for "PUT /projects/{project}".
 	// This function handles HTTP error wrapping, and validates the server result.
 	UpdateProject(ctx context.Context, req *gitlab.Project) (*gitlab.Project, error)
+	// UpdateProjectMergeChecks is a wrapper for "PUT /projects/{project}", which only
+	// updates the only_allow_merge_if_* fields.
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateProjectMergeChecks(ctx context.Context, projectID int, pipelineSucceeds, discussionsResolved bool) (*gitlab.Project, error)
+	// GetProjectIssueCloseSettings is a wrapper for "GET /projects/{project}", which only
+	// decodes the autoclose_referenced_issues field.
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetProjectIssueCloseSettings(ctx context.Context, projectID int) (*projectIssueCloseSettings, error)
+	// UpdateProjectIssueCloseSettings is a wrapper for "PUT /projects/{project}", which only
+	// updates the autoclose_referenced_issues field.
+	// This function handles HTTP error wrapping.
+	UpdateProjectIssueCloseSettings(ctx context.Context, projectID int, req *projectIssueCloseSettings) error
+	// GetProjectRawFile is a wrapper for "GET /projects/{project}/repository/files/{file_path}/raw",
+	// returning the content of the file at path on the given ref.
+	// This function handles HTTP error wrapping.
+	GetProjectRawFile(ctx context.Context, projectID int, path, ref string) (string, error)
+	// GetProjectNotificationSettings is a wrapper for "GET /projects/{project}/notification_settings".
+	// This function handles HTTP error wrapping.
+	GetProjectNotificationSettings(ctx context.Context, projectID int) (*gitlab.NotificationSettings, error)
+	// UpdateProjectNotificationLevel is a wrapper for "PUT /projects/{project}/notification_settings",
+	// which only updates the level field.
+	// This function handles HTTP error wrapping.
+	UpdateProjectNotificationLevel(ctx context.Context, projectID int, level gitlab.NotificationLevelValue) error
+	// GetProjectForkingSettings is a wrapper for "GET /projects/{project}", which only
+	// decodes the forking_access_level field.
+	// This function handles HTTP error wrapping.
+	GetProjectForkingSettings(ctx context.Context, projectID int) (*projectForkingSettings, error)
+	// UpdateProjectForkingSettings is a wrapper for "PUT /projects/{project}", which only
+	// updates the forking_access_level field.
+	// This function handles HTTP error wrapping.
+	UpdateProjectForkingSettings(ctx context.Context, projectID int, req *projectForkingSettings) error
+	// GetProjectLanguages is a wrapper for "GET /projects/{project}/languages".
+	// The returned map has the percentage of the code written in each language.
+	// This function handles HTTP error wrapping.
+	GetProjectLanguages(ctx context.Context, projectName string) (map[string]float32, error)
+	// GetProjectEmptyStatus is a wrapper for "GET /projects/{project}", which only
+	// decodes the empty_repo field.
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetProjectEmptyStatus(ctx context.Context, projectName string) (*projectEmptyStatus, error)
+	// GetProjectMergeTrainSettings is a wrapper for "GET /projects/{project}", which only
+	// decodes the merge_pipelines_enabled and merge_trains_enabled fields. These are nil if
+	// the features aren't available in the tier of the project.
+	// This function handles HTTP error wrapping.
+	GetProjectMergeTrainSettings(ctx context.Context, projectID int) (*projectMergeTrainSettings, error)
+	// UpdateProjectMergeTrainSettings is a wrapper for "PUT /projects/{project}", which only
+	// updates the merge_pipelines_enabled and merge_trains_enabled fields.
+	// This function handles HTTP error wrapping.
+	UpdateProjectMergeTrainSettings(ctx context.Context, projectID int, req *projectMergeTrainSettings) error
+	// StartPullMirror is a wrapper for "POST /projects/{project}/mirror/pull".
+	// This function handles HTTP error wrapping.
+	StartPullMirror(ctx context.Context, projectID int) error
+	// GetProjectPushRule is a wrapper for "GET /projects/{project}/push_rule".
+	// nil is returned if the project has no push rule.
+	// This function handles HTTP error wrapping.
+	GetProjectPushRule(ctx context.Context, projectID int) (*gitlab.ProjectPushRules, error)
+	// AddProjectPushRule is a wrapper for "POST /projects/{project}/push_rule".
+	// This function handles HTTP error wrapping.
+	AddProjectPushRule(ctx context.Context, projectID int, req *gitlab.EditProjectPushRuleOptions) (*gitlab.ProjectPushRules, error)
+	// EditProjectPushRule is a wrapper for "PUT /projects/{project}/push_rule".
+	// This function handles HTTP error wrapping.
+	EditProjectPushRule(ctx context.Context, projectID int, req *gitlab.EditProjectPushRuleOptions) (*gitlab.ProjectPushRules, error)
+	// ListProjectProtectedEnvironments is a wrapper for "GET /projects/{project}/protected_environments".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListProjectProtectedEnvironments(ctx context.Context, projectID int) ([]*protectedEnvironment, error)
+	// ProtectProjectEnvironment is a wrapper for "POST /projects/{project}/protected_environments".
+	// This function handles HTTP error wrapping, and validates the server result.
+	ProtectProjectEnvironment(ctx context.Context, projectID int, req *protectedEnvironment) (*protectedEnvironment, error)
+	// UpdateProjectProtectedEnvironment is a wrapper for "PUT /projects/{project}/protected_environments/{name}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateProjectProtectedEnvironment(ctx context.Context, projectID int, req *protectedEnvironment) (*protectedEnvironment, error)
+	// UnprotectProjectEnvironment is a wrapper for "DELETE /projects/{project}/protected_environments/{name}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	UnprotectProjectEnvironment(ctx context.Context, projectID int, name string) error
+	// CountProjectMergeRequests is a wrapper for "GET /projects/{project}/merge_requests?state={state}",
+	// which only returns the total amount of matching merge requests, requesting a single item.
+	// This function handles HTTP error wrapping.
+	CountProjectMergeRequests(ctx context.Context, projectName, state string) (int64, error)
 	// DeleteProject is a wrapper for "DELETE /projects/{project}".
 	// This function handles HTTP error wrapping.
 	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
@@ -87,6 +206,79 @@ type gitlabClient interface {
 	// DeleteKey is a wrapper for "DELETE /projects/{project}/deploy_keys/{key_id}".
 	// This function handles HTTP error wrapping.
 	DeleteKey(ctx context.Context, projectName string, keyID int) error
+	// EnableKey is a wrapper for "POST /projects/{project}/deploy_keys/{key_id}/enable".
+	// This function handles HTTP error wrapping, and validates the server result.
+	EnableKey(ctx context.Context, projectName string, keyID int) (*gitlab.DeployKey, error)
+
+	// Release methods
+
+	// ListProjectReleases is a wrapper for "GET /projects/{project}/releases".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListProjectReleases(ctx context.Context, projectName string) ([]*release, error)
+	// GetProjectRelease is a wrapper for "GET /projects/{project}/releases/{tag_name}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetProjectRelease(ctx context.Context, projectName, tag string) (*release, error)
+	// CreateProjectRelease is a wrapper for "POST /projects/{project}/releases".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateProjectRelease(ctx context.Context, projectName string, req *gitlab.CreateReleaseOptions) (*release, error)
+
+	// Branch methods
+
+	// ListBranches is a wrapper for "GET /projects/{project}/repository/branches".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListBranches(ctx context.Context, projectName string) ([]*gitlab.Branch, error)
+	// GetBranch is a wrapper for "GET /projects/{project}/repository/branches/{branch}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetBranch(ctx context.Context, projectName, branch string) (*gitlab.Branch, error)
+	// CreateBranch is a wrapper for "POST /projects/{project}/repository/branches".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateBranch(ctx context.Context, projectName, branch, ref string) (*gitlab.Branch, error)
+	// DeleteBranch is a wrapper for "DELETE /projects/{project}/repository/branches/{branch}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteBranch(ctx context.Context, projectName, branch string) error
+
+	// Tag methods
+
+	// ListTags is a wrapper for "GET /projects/{project}/repository/tags".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListTags(ctx context.Context, projectName string) ([]*gitlab.Tag, error)
+	// GetTag is a wrapper for "GET /projects/{project}/repository/tags/{tag}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetTag(ctx context.Context, projectName, tag string) (*gitlab.Tag, error)
+	// CreateTag is a wrapper for "POST /projects/{project}/repository/tags", creating a lightweight tag.
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateTag(ctx context.Context, projectName, tag, ref string) (*gitlab.Tag, error)
+
+	// Commit methods
+
+	// GetCommit is a wrapper for "GET /projects/{project}/repository/commits/{sha}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetCommit(ctx context.Context, projectName, sha string) (*gitlab.Commit, error)
+
+	// ListCommitsPage is a wrapper for "GET /projects/{project}/repository/commits?ref_name={branch}",
+	// for the given page.
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListCommitsPage(ctx context.Context, projectName, branch string, perPage, page int) ([]*gitlab.Commit, error)
+	// GetCommitSignature is a wrapper for "GET /projects/{project}/repository/commits/{sha}/signature".
+	// nil is returned if the commit isn't signed.
+	// This function handles HTTP error wrapping.
+	GetCommitSignature(ctx context.Context, projectName, sha string) (*gitlab.GPGSignature, error)
+	// CreateCommit is a wrapper for "POST /projects/{project}/repository/commits".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateCommit(ctx context.Context, projectName string, req *gitlab.CreateCommitOptions) (*gitlab.Commit, error)
+	// GetFileMetaData is a wrapper for "HEAD /projects/{project}/repository/files/{file_path}?ref={ref}".
+	// This function handles HTTP error wrapping.
+	GetFileMetaData(ctx context.Context, projectName, path, ref string) (*gitlab.File, error)
+
+	// Issue methods
+
+	// CreateIssue is a wrapper for "POST /projects/{project}/issues".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateIssue(ctx context.Context, projectName string, req *gitlab.CreateIssueOptions) (*gitlab.Issue, error)
+	// ListIssues is a wrapper for "GET /projects/{project}/issues".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListIssues(ctx context.Context, projectName string, opts *gitlab.ListProjectIssuesOptions) ([]*gitlab.Issue, error)
 
 	// Team related methods
 
@@ -126,6 +318,59 @@ func (c *gitlabClientImpl) GetGroup(ctx context.Context, groupID interface{}) (*
 	return apiObj, nil
 }
 
+func (c *gitlabClientImpl) GetGroupDefaultBranch(ctx context.Context, groupID int) (*groupDefaultBranch, error) {
+	// go-gitlab doesn't support this field yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodGet, fmt.Sprintf("groups/%d", groupID), nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	// GET /groups/{group}
+	apiObj := &groupDefaultBranch{}
+	if _, err := c.c.Do(req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) UpdateGroupDefaultBranch(ctx context.Context, groupID int, apiObj *groupDefaultBranch) error {
+	// go-gitlab doesn't support this field yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodPut, fmt.Sprintf("groups/%d", groupID), apiObj, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return err
+	}
+	// PUT /groups/{group}
+	_, err = c.c.Do(req, nil)
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) UpdateGroupProjectCreationLevel(ctx context.Context, groupID int, level gitlab.ProjectCreationLevelValue) (*gitlab.Group, error) {
+	opts := &gitlab.UpdateGroupOptions{ProjectCreationLevel: &level}
+	// PUT /groups/{group}
+	apiObj, _, err := c.c.Groups.UpdateGroup(groupID, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateGroupAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) UpdateGroupMetadata(ctx context.Context, groupID int, name, description *string) (*gitlab.Group, error) {
+	opts := &gitlab.UpdateGroupOptions{Name: name, Description: description}
+	// PUT /groups/{group}
+	apiObj, _, err := c.c.Groups.UpdateGroup(groupID, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateGroupAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
 func (c *gitlabClientImpl) ListGroups(ctx context.Context) ([]*gitlab.Group, error) {
 	apiObjs := []*gitlab.Group{}
 	opts := &gitlab.ListGroupsOptions{}
@@ -175,10 +420,25 @@ func (c *gitlabClientImpl) GetGroupProject(ctx context.Context, groupName string
 }
 
 func (c *gitlabClientImpl) ListGroupProjects(ctx context.Context, groupName string) ([]*gitlab.Project, error) {
+	return c.ListGroupProjectsMatching(ctx, groupName, nil, nil, "", "")
+}
+
+func (c *gitlabClientImpl) ListGroupProjectsMatching(ctx context.Context, groupName string, visibility *gitlab.VisibilityValue, archived *bool, search, language string) ([]*gitlab.Project, error) {
 	var apiObjs []*gitlab.Project
-	opts := &gitlab.ListGroupProjectsOptions{}
+	opts := &gitlab.ListGroupProjectsOptions{
+		Visibility: visibility,
+		Archived:   archived,
+	}
+	if search != "" {
+		opts.Search = &search
+	}
+	var reqOpts []gitlab.RequestOptionFunc
+	if language != "" {
+		// go-gitlab doesn't support this filter for group projects yet
+		reqOpts = append(reqOpts, withQueryParam("with_programming_language", language))
+	}
 	err := allGroupProjectPages(opts, func() (*gitlab.Response, error) {
-		pageObjs, resp, listErr := c.c.Groups.ListGroupProjects(groupName, opts, gitlab.WithContext(ctx))
+		pageObjs, resp, listErr := c.listGroupProjectsPage(ctx, groupName, opts, reqOpts...)
 		apiObjs = append(apiObjs, pageObjs...)
 		return resp, listErr
 	})
@@ -188,6 +448,30 @@ func (c *gitlabClientImpl) ListGroupProjects(ctx context.Context, groupName stri
 	return validateProjectObjects(apiObjs)
 }
 
+func (c *gitlabClientImpl) ListGroupProjectsPage(ctx context.Context, groupName string, opts gitprovider.PageOptions, orderBy, sort string) ([]*gitlab.Project, gitprovider.PageInfo, error) {
+	listOpts := &gitlab.ListGroupProjectsOptions{ListOptions: pageListOptions(opts)}
+	if orderBy != "" {
+		listOpts.OrderBy = gitlab.String(orderBy)
+	}
+	if sort != "" {
+		listOpts.Sort = gitlab.String(sort)
+	}
+	apiObjs, resp, err := c.listGroupProjectsPage(ctx, groupName, listOpts)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, handleHTTPError(err)
+	}
+	apiObjs, err = validateProjectObjects(apiObjs)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	return apiObjs, pageInfoFromResponse(resp), nil
+}
+
+func (c *gitlabClientImpl) listGroupProjectsPage(ctx context.Context, groupName string, opts *gitlab.ListGroupProjectsOptions, reqOpts ...gitlab.RequestOptionFunc) ([]*gitlab.Project, *gitlab.Response, error) {
+	// GET /groups/{group}/projects
+	return c.c.Groups.ListGroupProjects(groupName, opts, append(reqOpts, gitlab.WithContext(ctx))...)
+}
+
 func validateProjectObjects(apiObjs []*gitlab.Project) ([]*gitlab.Project, error) {
 	for _, apiObj := range apiObjs {
 		// Make sure apiObj is valid
@@ -213,12 +497,31 @@ func (c *gitlabClientImpl) ListGroupMembers(ctx context.Context, groupName strin
 	return apiObjs, nil
 }
 
+func (c *gitlabClientImpl) GetCurrentUser(ctx context.Context) (*gitlab.User, error) {
+	// GET /user
+	apiObj, _, err := c.c.Users.CurrentUser(gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateUserAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
 func (c *gitlabClientImpl) GetUserProject(ctx context.Context, projectName string) (*gitlab.Project, error) {
 	opts := &gitlab.GetProjectOptions{}
 	apiObj, _, err := c.c.Projects.GetProject(projectName, opts, gitlab.WithContext(ctx))
 	return validateProjectAPIResp(apiObj, err)
 }
 
+func (c *gitlabClientImpl) GetProjectWithStatistics(ctx context.Context, projectName string) (*gitlab.Project, error) {
+	opts := &gitlab.GetProjectOptions{Statistics: gitlab.Bool(true)}
+	apiObj, _, err := c.c.Projects.GetProject(projectName, opts, gitlab.WithContext(ctx))
+	return validateProjectAPIResp(apiObj, err)
+}
+
 func validateProjectAPIResp(apiObj *gitlab.Project, err error) (*gitlab.Project, error) {
 	// If the response contained an error, return
 	if err != nil {
@@ -265,8 +568,7 @@ func (c *gitlabClientImpl) ListUserProjects(ctx context.Context, username string
 	var apiObjs []*gitlab.Project
 	opts := &gitlab.ListProjectsOptions{}
 	err := allProjectPages(opts, func() (*gitlab.Response, error) {
-		// GET /projects/{project}/users
-		pageObjs, resp, listErr := c.c.Projects.ListUserProjects(username, opts, gitlab.WithContext(ctx))
+		pageObjs, resp, listErr := c.listUserProjectsPage(ctx, username, opts)
 		apiObjs = append(apiObjs, pageObjs...)
 		return resp, listErr
 	})
@@ -276,6 +578,24 @@ func (c *gitlabClientImpl) ListUserProjects(ctx context.Context, username string
 	return apiObjs, nil
 }
 
+func (c *gitlabClientImpl) ListUserProjectsPage(ctx context.Context, username string, opts gitprovider.PageOptions) ([]*gitlab.Project, gitprovider.PageInfo, error) {
+	listOpts := &gitlab.ListProjectsOptions{ListOptions: pageListOptions(opts)}
+	apiObjs, resp, err := c.listUserProjectsPage(ctx, username, listOpts)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, handleHTTPError(err)
+	}
+	apiObjs, err = validateProjectObjects(apiObjs)
+	if err != nil {
+		return nil, gitprovider.PageInfo{}, err
+	}
+	return apiObjs, pageInfoFromResponse(resp), nil
+}
+
+func (c *gitlabClientImpl) listUserProjectsPage(ctx context.Context, username string, opts *gitlab.ListProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error) {
+	// GET /users/{username}/projects
+	return c.c.Projects.ListUserProjects(username, opts, gitlab.WithContext(ctx))
+}
+
 func (c *gitlabClientImpl) CreateProject(ctx context.Context, req *gitlab.Project) (*gitlab.Project, error) {
 	var namespaceID int
 	// If the project doesn't belong to a user set its namespace ID
@@ -291,6 +611,11 @@ func (c *gitlabClientImpl) CreateProject(ctx context.Context, req *gitlab.Projec
 		DefaultBranch: &req.DefaultBranch,
 		Description:   &req.Description,
 		Visibility:    &req.Visibility,
+		IssuesEnabled: &req.IssuesEnabled,
+		WikiEnabled:   &req.WikiEnabled,
+	}
+	if req.TagList != nil {
+		opts.TagList = &req.TagList
 	}
 	if namespaceID != 0 {
 		opts.NamespaceID = &namespaceID
@@ -302,14 +627,294 @@ func (c *gitlabClientImpl) CreateProject(ctx context.Context, req *gitlab.Projec
 
 func (c *gitlabClientImpl) UpdateProject(ctx context.Context, req *gitlab.Project) (*gitlab.Project, error) {
 	opts := &gitlab.EditProjectOptions{
-		Name:        &req.Name,
-		Description: &req.Description,
-		Visibility:  &req.Visibility,
+		Name:          &req.Name,
+		Description:   &req.Description,
+		Visibility:    &req.Visibility,
+		IssuesEnabled: &req.IssuesEnabled,
+		WikiEnabled:   &req.WikiEnabled,
+	}
+	if req.DefaultBranch != "" {
+		opts.DefaultBranch = &req.DefaultBranch
+	}
+	if req.TagList != nil {
+		opts.TagList = &req.TagList
 	}
 	apiObj, _, err := c.c.Projects.EditProject(req.ID, opts, gitlab.WithContext(ctx))
 	return validateProjectAPIResp(apiObj, err)
 }
 
+func (c *gitlabClientImpl) UpdateProjectMergeChecks(ctx context.Context, projectID int, pipelineSucceeds, discussionsResolved bool) (*gitlab.Project, error) {
+	opts := &gitlab.EditProjectOptions{
+		OnlyAllowMergeIfPipelineSucceeds:          &pipelineSucceeds,
+		OnlyAllowMergeIfAllDiscussionsAreResolved: &discussionsResolved,
+	}
+	// PUT /projects/{project}
+	apiObj, _, err := c.c.Projects.EditProject(projectID, opts, gitlab.WithContext(ctx))
+	return validateProjectAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) GetProjectIssueCloseSettings(ctx context.Context, projectID int) (*projectIssueCloseSettings, error) {
+	// go-gitlab doesn't support this field yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodGet, fmt.Sprintf("projects/%d", projectID), nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	// GET /projects/{project}
+	apiObj := &projectIssueCloseSettings{}
+	if _, err := c.c.Do(req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateProjectIssueCloseSettingsAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) UpdateProjectIssueCloseSettings(ctx context.Context, projectID int, apiObj *projectIssueCloseSettings) error {
+	// go-gitlab doesn't support this field yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodPut, fmt.Sprintf("projects/%d", projectID), apiObj, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return err
+	}
+	// PUT /projects/{project}
+	_, err = c.c.Do(req, nil)
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) GetProjectRawFile(ctx context.Context, projectID int, path, ref string) (string, error) {
+	opts := &gitlab.GetRawFileOptions{Ref: &ref}
+	// GET /projects/{project}/repository/files/{file_path}/raw
+	content, _, err := c.c.RepositoryFiles.GetRawFile(projectID, path, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return "", handleHTTPError(err)
+	}
+	return string(content), nil
+}
+
+func (c *gitlabClientImpl) GetProjectNotificationSettings(ctx context.Context, projectID int) (*gitlab.NotificationSettings, error) {
+	// GET /projects/{project}/notification_settings
+	apiObj, _, err := c.c.NotificationSettings.GetSettingsForProject(projectID, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) UpdateProjectNotificationLevel(ctx context.Context, projectID int, level gitlab.NotificationLevelValue) error {
+	opts := &gitlab.NotificationSettingsOptions{Level: &level}
+	// PUT /projects/{project}/notification_settings
+	_, _, err := c.c.NotificationSettings.UpdateSettingsForProject(projectID, opts, gitlab.WithContext(ctx))
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) GetProjectForkingSettings(ctx context.Context, projectID int) (*projectForkingSettings, error) {
+	// go-gitlab doesn't support this field yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodGet, fmt.Sprintf("projects/%d", projectID), nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	// GET /projects/{project}
+	apiObj := &projectForkingSettings{}
+	if _, err := c.c.Do(req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) UpdateProjectForkingSettings(ctx context.Context, projectID int, apiObj *projectForkingSettings) error {
+	// go-gitlab doesn't support this field in the project object yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodPut, fmt.Sprintf("projects/%d", projectID), apiObj, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return err
+	}
+	// PUT /projects/{project}
+	_, err = c.c.Do(req, nil)
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) GetProjectLanguages(ctx context.Context, projectName string) (map[string]float32, error) {
+	// GET /projects/{project}/languages
+	languages, _, err := c.c.Projects.GetProjectLanguages(projectName, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return *languages, nil
+}
+
+func (c *gitlabClientImpl) GetProjectMergeTrainSettings(ctx context.Context, projectID int) (*projectMergeTrainSettings, error) {
+	// go-gitlab doesn't support these fields yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodGet, fmt.Sprintf("projects/%d", projectID), nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	// GET /projects/{project}
+	apiObj := &projectMergeTrainSettings{}
+	if _, err := c.c.Do(req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) UpdateProjectMergeTrainSettings(ctx context.Context, projectID int, apiObj *projectMergeTrainSettings) error {
+	// go-gitlab doesn't support these fields yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodPut, fmt.Sprintf("projects/%d", projectID), apiObj, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return err
+	}
+	// PUT /projects/{project}
+	_, err = c.c.Do(req, nil)
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) StartPullMirror(ctx context.Context, projectID int) error {
+	// POST /projects/{project}/mirror/pull
+	_, err := c.c.Projects.StartMirroringProject(projectID, gitlab.WithContext(ctx))
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) GetProjectPushRule(ctx context.Context, projectID int) (*gitlab.ProjectPushRules, error) {
+	// GET /projects/{project}/push_rule
+	apiObj, _, err := c.c.Projects.GetProjectPushRules(projectID, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// GitLab returns null if there is no push rule, which is decoded into an empty object
+	if apiObj.ID == 0 {
+		return nil, nil
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) AddProjectPushRule(ctx context.Context, projectID int, opts *gitlab.EditProjectPushRuleOptions) (*gitlab.ProjectPushRules, error) {
+	// go-gitlab's AddProjectPushRuleOptions lacks some fields the endpoint supports, hence construct
+	// the request manually, with the (equivalent) options of the PUT endpoint
+	req, err := c.c.NewRequest(http.MethodPost, fmt.Sprintf("projects/%d/push_rule", projectID), opts, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	// POST /projects/{project}/push_rule
+	apiObj := &gitlab.ProjectPushRules{}
+	if _, err := c.c.Do(req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) EditProjectPushRule(ctx context.Context, projectID int, opts *gitlab.EditProjectPushRuleOptions) (*gitlab.ProjectPushRules, error) {
+	// PUT /projects/{project}/push_rule
+	apiObj, _, err := c.c.Projects.EditProjectPushRule(projectID, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) ListProjectProtectedEnvironments(ctx context.Context, projectID int) ([]*protectedEnvironment, error) {
+	apiObjs := []*protectedEnvironment{}
+	opts := &gitlab.ListOptions{}
+	err := allProtectedEnvironmentPages(opts, func() (*gitlab.Response, error) {
+		// go-gitlab doesn't support protected environments yet, hence construct the request manually
+		req, err := c.c.NewRequest(http.MethodGet, fmt.Sprintf("projects/%d/protected_environments", projectID), opts, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+		if err != nil {
+			return nil, err
+		}
+		// GET /projects/{project}/protected_environments
+		var pageObjs []*protectedEnvironment
+		resp, listErr := c.c.Do(req, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateProtectedEnvironmentAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) ProtectProjectEnvironment(ctx context.Context, projectID int, apiObj *protectedEnvironment) (*protectedEnvironment, error) {
+	// go-gitlab doesn't support protected environments yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodPost, fmt.Sprintf("projects/%d/protected_environments", projectID), apiObj, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	// POST /projects/{project}/protected_environments
+	return c.doProtectedEnvironmentRequest(req)
+}
+
+func (c *gitlabClientImpl) UpdateProjectProtectedEnvironment(ctx context.Context, projectID int, apiObj *protectedEnvironment) (*protectedEnvironment, error) {
+	// go-gitlab doesn't support protected environments yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodPut, fmt.Sprintf("projects/%d/protected_environments/%s", projectID, url.PathEscape(apiObj.Name)), apiObj, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	// PUT /projects/{project}/protected_environments/{name}
+	return c.doProtectedEnvironmentRequest(req)
+}
+
+func (c *gitlabClientImpl) doProtectedEnvironmentRequest(req *retryablehttp.Request) (*protectedEnvironment, error) {
+	apiObj := &protectedEnvironment{}
+	if _, err := c.c.Do(req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateProtectedEnvironmentAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) UnprotectProjectEnvironment(ctx context.Context, projectID int, name string) error {
+	// Don't allow unprotecting environments if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot unprotect environment: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// go-gitlab doesn't support protected environments yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodDelete, fmt.Sprintf("projects/%d/protected_environments/%s", projectID, url.PathEscape(name)), nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return err
+	}
+	// DELETE /projects/{project}/protected_environments/{name}
+	_, err = c.c.Do(req, nil)
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) CountProjectMergeRequests(ctx context.Context, projectName, state string) (int64, error) {
+	opts := &gitlab.ListProjectMergeRequestsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 1},
+		State:       &state,
+	}
+	// GET /projects/{project}/merge_requests
+	_, resp, err := c.c.MergeRequests.ListProjectMergeRequests(projectName, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return 0, handleHTTPError(err)
+	}
+	return int64(resp.TotalItems), nil
+}
+
+func (c *gitlabClientImpl) GetProjectEmptyStatus(ctx context.Context, projectName string) (*projectEmptyStatus, error) {
+	// go-gitlab doesn't support this field yet, hence construct the request manually
+	req, err := c.c.NewRequest(http.MethodGet, fmt.Sprintf("projects/%s", url.PathEscape(projectName)), nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	// GET /projects/{project}
+	apiObj := &projectEmptyStatus{}
+	if _, err := c.c.Do(req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateProjectEmptyStatusAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
 func (c *gitlabClientImpl) DeleteProject(ctx context.Context, projectName string) error {
 	// Don't allow deleting repositories if the user didn't explicitly allow dangerous API calls.
 	if !c.destructiveActions {
@@ -364,6 +969,282 @@ func (c *gitlabClientImpl) DeleteKey(ctx context.Context, projectName string, ke
 	return handleHTTPError(err)
 }
 
+func (c *gitlabClientImpl) EnableKey(ctx context.Context, projectName string, keyID int) (*gitlab.DeployKey, error) {
+	// POST /projects/{project}/deploy_keys/{key_id}/enable
+	apiObj, _, err := c.c.DeployKeys.EnableDeployKey(projectName, keyID, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateDeployKeyAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) ListProjectReleases(ctx context.Context, projectName string) ([]*release, error) {
+	apiObjs := []*release{}
+	opts := &gitlab.ListReleasesOptions{}
+	err := allReleasePages(opts, func() (*gitlab.Response, error) {
+		// go-gitlab's Release struct lacks the release date, hence construct the request manually
+		req, err := c.c.NewRequest(http.MethodGet, fmt.Sprintf("projects/%s/releases", url.PathEscape(projectName)), opts, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+		if err != nil {
+			return nil, err
+		}
+		// GET /projects/{project}/releases
+		var pageObjs []*release
+		resp, listErr := c.c.Do(req, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateReleaseAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) GetProjectRelease(ctx context.Context, projectName, tag string) (*release, error) {
+	// go-gitlab's Release struct lacks the release date, hence construct the request manually
+	u := fmt.Sprintf("projects/%s/releases/%s", url.PathEscape(projectName), url.PathEscape(tag))
+	req, err := c.c.NewRequest(http.MethodGet, u, nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	// GET /projects/{project}/releases/{tag_name}
+	apiObj := &release{}
+	if _, err := c.c.Do(req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateReleaseAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) CreateProjectRelease(ctx context.Context, projectName string, opts *gitlab.CreateReleaseOptions) (*release, error) {
+	// go-gitlab's Release struct lacks the release date, hence construct the request manually
+	u := fmt.Sprintf("projects/%s/releases", url.PathEscape(projectName))
+	req, err := c.c.NewRequest(http.MethodPost, u, opts, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	// POST /projects/{project}/releases
+	apiObj := &release{}
+	if _, err := c.c.Do(req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateReleaseAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) ListBranches(ctx context.Context, projectName string) ([]*gitlab.Branch, error) {
+	apiObjs := []*gitlab.Branch{}
+	opts := &gitlab.ListBranchesOptions{}
+	err := allBranchPages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/repository/branches
+		pageObjs, resp, listErr := c.c.Branches.ListBranches(projectName, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateBranchAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) GetBranch(ctx context.Context, projectName, branch string) (*gitlab.Branch, error) {
+	// GET /projects/{project}/repository/branches/{branch}
+	apiObj, _, err := c.c.Branches.GetBranch(projectName, branch, gitlab.WithContext(ctx))
+	return validateBranchAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) CreateBranch(ctx context.Context, projectName, branch, ref string) (*gitlab.Branch, error) {
+	opts := &gitlab.CreateBranchOptions{Branch: &branch, Ref: &ref}
+	// POST /projects/{project}/repository/branches
+	apiObj, _, err := c.c.Branches.CreateBranch(projectName, opts, gitlab.WithContext(ctx))
+	return validateBranchAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) DeleteBranch(ctx context.Context, projectName, branch string) error {
+	// Don't allow deleting branches if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete branch: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /projects/{project}/repository/branches/{branch}
+	_, err := c.c.Branches.DeleteBranch(projectName, branch, gitlab.WithContext(ctx))
+	return handleHTTPError(err)
+}
+
+func validateBranchAPIResp(apiObj *gitlab.Branch, err error) (*gitlab.Branch, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateBranchAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) ListTags(ctx context.Context, projectName string) ([]*gitlab.Tag, error) {
+	apiObjs := []*gitlab.Tag{}
+	opts := &gitlab.ListTagsOptions{}
+	err := allTagPages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/repository/tags
+		pageObjs, resp, listErr := c.c.Tags.ListTags(projectName, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, apiObj := range apiObjs {
+		if err := validateTagAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) GetTag(ctx context.Context, projectName, tag string) (*gitlab.Tag, error) {
+	// GET /projects/{project}/repository/tags/{tag}
+	apiObj, _, err := c.c.Tags.GetTag(projectName, tag, gitlab.WithContext(ctx))
+	return validateTagAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) CreateTag(ctx context.Context, projectName, tag, ref string) (*gitlab.Tag, error) {
+	// Without a message, GitLab creates a lightweight tag
+	opts := &gitlab.CreateTagOptions{
+		TagName: &tag,
+		Ref:     &ref,
+	}
+	// POST /projects/{project}/repository/tags
+	apiObj, _, err := c.c.Tags.CreateTag(projectName, opts, gitlab.WithContext(ctx))
+	return validateTagAPIResp(apiObj, err)
+}
+
+func validateTagAPIResp(apiObj *gitlab.Tag, err error) (*gitlab.Tag, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateTagAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) GetCommit(ctx context.Context, projectName, sha string) (*gitlab.Commit, error) {
+	// GET /projects/{project}/repository/commits/{sha}
+	apiObj, _, err := c.c.Commits.GetCommit(projectName, sha, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateCommitAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) ListCommitsPage(ctx context.Context, projectName, branch string, perPage, page int) ([]*gitlab.Commit, error) {
+	opts := &gitlab.ListCommitsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: perPage, Page: page},
+		RefName:     &branch,
+	}
+	// GET /projects/{project}/repository/commits
+	apiObjs, _, err := c.c.Commits.ListCommits(projectName, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	for _, apiObj := range apiObjs {
+		if err := validateCommitAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) GetCommitSignature(ctx context.Context, projectName, sha string) (*gitlab.GPGSignature, error) {
+	// GET /projects/{project}/repository/commits/{sha}/signature
+	apiObj, _, err := c.c.Commits.GetGPGSiganature(projectName, sha, gitlab.WithContext(ctx))
+	if err != nil {
+		err = handleHTTPError(err)
+		// GitLab responds with 404 Not Found if the commit isn't signed
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) CreateCommit(ctx context.Context, projectName string, req *gitlab.CreateCommitOptions) (*gitlab.Commit, error) {
+	// POST /projects/{project}/repository/commits
+	apiObj, _, err := c.c.Commits.CreateCommit(projectName, req, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateCommitAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) GetFileMetaData(ctx context.Context, projectName, path, ref string) (*gitlab.File, error) {
+	// HEAD /projects/{project}/repository/files/{file_path}
+	apiObj, _, err := c.c.RepositoryFiles.GetFileMetaData(projectName, path, &gitlab.GetFileMetaDataOptions{Ref: &ref}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) CreateIssue(ctx context.Context, projectName string, req *gitlab.CreateIssueOptions) (*gitlab.Issue, error) {
+	// POST /projects/{project}/issues
+	apiObj, _, err := c.c.Issues.CreateIssue(projectName, req, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateIssueAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) ListIssues(ctx context.Context, projectName string, opts *gitlab.ListProjectIssuesOptions) ([]*gitlab.Issue, error) {
+	apiObjs := []*gitlab.Issue{}
+	err := allIssuePages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/issues
+		pageObjs, resp, listErr := c.c.Issues.ListProjectIssues(projectName, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, apiObj := range apiObjs {
+		if err := validateIssueAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
 func (c *gitlabClientImpl) ShareProject(ctx context.Context, projectName string, groupIDObj, groupAccessObj int) error {
 	groupAccess := gitlab.AccessLevel(gitlab.AccessLevelValue(groupAccessObj))
 	groupID := &groupIDObj
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"time"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+	"github.com/xanzy/go-gitlab"
+)
+
+func newBranch(ctx *clientContext, apiObj *gitlab.Branch, ref gitprovider.RepositoryRef) *branch {
+	return &branch{
+		clientContext: ctx,
+		b:             *apiObj,
+		ref:           ref,
+	}
+}
+
+var _ gitprovider.Branch = &branch{}
+
+type branch struct {
+	*clientContext
+
+	b   gitlab.Branch
+	ref gitprovider.RepositoryRef
+}
+
+func (b *branch) Get() gitprovider.BranchInfo {
+	return branchFromAPI(&b.b)
+}
+
+func (b *branch) APIObject() interface{} {
+	return &b.b
+}
+
+func (b *branch) Repository() gitprovider.RepositoryRef {
+	return b.ref
+}
+
+func branchFromAPI(apiObj *gitlab.Branch) gitprovider.BranchInfo {
+	return gitprovider.BranchInfo{
+		Name:      apiObj.Name,
+		Sha:       apiObj.Commit.ID,
+		Protected: apiObj.Protected,
+	}
+}
+
+// branchSkipReason returns why DeleteMergedBranches must not delete the (validated) branch, or an
+// empty string if it can be deleted.
+func branchSkipReason(apiObj *gitlab.Branch, minAge time.Duration) gitprovider.BranchSkipReason {
+	switch {
+	case apiObj.Default:
+		return gitprovider.BranchSkipReasonDefault
+	case apiObj.Protected:
+		return gitprovider.BranchSkipReasonProtected
+	case !apiObj.Merged:
+		return gitprovider.BranchSkipReasonNotMerged
+	case minAge != 0 && (apiObj.Commit.CommittedDate == nil || time.Since(*apiObj.Commit.CommittedDate) < minAge):
+		return gitprovider.BranchSkipReasonTooRecent
+	}
+	return ""
+}
+
+// validateBranchAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateBranchAPI(apiObj *gitlab.Branch) error {
+	return validateAPIObject("GitLab.Branch", func(validator validation.Validator) {
+		if apiObj.Name == "" {
+			validator.Required("Name")
+		}
+		if apiObj.Commit == nil || apiObj.Commit.ID == "" {
+			validator.Required("Commit.ID")
+		}
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+func Test_branchSkipReason(t *testing.T) {
+	recent, old := time.Now().Add(-time.Hour), time.Now().Add(-30*24*time.Hour)
+	tests := []struct {
+		name   string
+		branch gitlab.Branch
+		minAge time.Duration
+		want   gitprovider.BranchSkipReason
+	}{
+		{
+			name:   "default branch",
+			branch: gitlab.Branch{Default: true, Merged: true},
+			want:   gitprovider.BranchSkipReasonDefault,
+		},
+		{
+			name:   "protected",
+			branch: gitlab.Branch{Protected: true, Merged: true},
+			want:   gitprovider.BranchSkipReasonProtected,
+		},
+		{
+			name:   "not merged",
+			branch: gitlab.Branch{Commit: &gitlab.Commit{CommittedDate: &old}},
+			want:   gitprovider.BranchSkipReasonNotMerged,
+		},
+		{
+			name:   "too recent",
+			branch: gitlab.Branch{Merged: true, Commit: &gitlab.Commit{CommittedDate: &recent}},
+			minAge: 24 * time.Hour,
+			want:   gitprovider.BranchSkipReasonTooRecent,
+		},
+		{
+			name:   "merged and old enough",
+			branch: gitlab.Branch{Merged: true, Commit: &gitlab.Commit{CommittedDate: &old}},
+			minAge: 24 * time.Hour,
+		},
+		{
+			name:   "merged, regardless of age",
+			branch: gitlab.Branch{Merged: true, Commit: &gitlab.Commit{CommittedDate: &recent}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := branchSkipReason(&tt.branch, tt.minAge); got != tt.want {
+				t.Errorf("branchSkipReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+	"github.com/xanzy/go-gitlab"
+)
+
+func newCommit(ctx *clientContext, apiObj *gitlab.Commit, ref gitprovider.RepositoryRef) *commit {
+	return &commit{
+		clientContext: ctx,
+		c:             *apiObj,
+		ref:           ref,
+	}
+}
+
+var _ gitprovider.Commit = &commit{}
+
+type commit struct {
+	*clientContext
+
+	c   gitlab.Commit
+	ref gitprovider.RepositoryRef
+	// verification is the verification status of the commit, which GitLab returns separately.
+	// It is nil if it wasn't requested.
+	verification *commitVerification
+}
+
+// commitVerification is the verification status of a commit, see verificationFromSignature.
+type commitVerification struct {
+	verified bool
+	reason   string
+}
+
+func (c *commit) Get() gitprovider.CommitInfo {
+	info := commitFromAPI(&c.c)
+	if c.verification != nil {
+		info.Verified = gitprovider.BoolVar(c.verification.verified)
+		info.VerificationReason = c.verification.reason
+	}
+	return info
+}
+
+func (c *commit) APIObject() interface{} {
+	return &c.c
+}
+
+func (c *commit) Repository() gitprovider.RepositoryRef {
+	return c.ref
+}
+
+func commitFromAPI(apiObj *gitlab.Commit) gitprovider.CommitInfo {
+	info := gitprovider.CommitInfo{
+		Sha:     apiObj.ID,
+		Author:  apiObj.AuthorName,
+		Message: apiObj.Message,
+		URL:     apiObj.WebURL,
+	}
+	if apiObj.AuthoredDate != nil {
+		info.CreatedAt = *apiObj.AuthoredDate
+	}
+	return info
+}
+
+// commitVerificationUnsigned is the verification reason of commits without a signature, named
+// like GitHub does, as GitLab doesn't return a signature object for them.
+const commitVerificationUnsigned = "unsigned"
+
+// verificationFromSignature returns the verification status of a commit with the given signature,
+// which is nil if the commit isn't signed.
+func verificationFromSignature(signature *gitlab.GPGSignature) *commitVerification {
+	if signature == nil {
+		return &commitVerification{verified: false, reason: commitVerificationUnsigned}
+	}
+	return &commitVerification{
+		verified: signature.VerificationStatus == "verified",
+		reason:   signature.VerificationStatus,
+	}
+}
+
+// validateCommitAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateCommitAPI(apiObj *gitlab.Commit) error {
+	return validateAPIObject("GitLab.Commit", func(validator validation.Validator) {
+		if apiObj.ID == "" {
+			validator.Required("ID")
+		}
+	})
+}
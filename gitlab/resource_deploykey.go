@@ -148,10 +148,15 @@ func validateDeployKeyAPI(apiObj *gitlab.DeployKey) error {
 }
 
 func deployKeyFromAPI(apiObj *gitlab.DeployKey) gitprovider.DeployKeyInfo {
-	return gitprovider.DeployKeyInfo{
+	info := gitprovider.DeployKeyInfo{
 		Name: apiObj.Title,
 		Key:  []byte(apiObj.Key),
 	}
+	// GitLab exposes write access as can_push, which is the inverse of ReadOnly
+	if apiObj.CanPush != nil {
+		info.ReadOnly = gitprovider.BoolVar(!*apiObj.CanPush)
+	}
+	return info
 }
 
 func deployKeyToAPI(info *gitprovider.DeployKeyInfo) *gitlab.DeployKey {
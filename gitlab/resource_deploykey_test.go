@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+func Test_deployKeyReadOnlyRoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		readOnly    *bool
+		wantCanPush *bool
+	}{
+		{
+			name:        "read-only",
+			readOnly:    gitprovider.BoolVar(true),
+			wantCanPush: gitlab.Bool(false),
+		},
+		{
+			name:        "read-write",
+			readOnly:    gitprovider.BoolVar(false),
+			wantCanPush: gitlab.Bool(true),
+		},
+		{
+			name:        "unset defaults to read-only",
+			wantCanPush: gitlab.Bool(false),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := gitprovider.DeployKeyInfo{Name: "flux", Key: []byte("ssh-ed25519 AAAAflux"), ReadOnly: tt.readOnly}
+			if err := gitprovider.ValidateAndDefaultInfo(&info); err != nil {
+				t.Fatalf("ValidateAndDefaultInfo() error = %v", err)
+			}
+			apiObj := deployKeyToAPI(&info)
+			if !reflect.DeepEqual(apiObj.CanPush, tt.wantCanPush) {
+				t.Fatalf("CanPush = %v, want %v", *apiObj.CanPush, *tt.wantCanPush)
+			}
+			if got := deployKeyFromAPI(apiObj); !reflect.DeepEqual(got, info) {
+				t.Errorf("deployKeyFromAPI() = %+v, want %+v", got, info)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+	"github.com/xanzy/go-gitlab"
+)
+
+const (
+	// issueStateOpened is what GitLab calls an open issue, both in the issue and the list filter.
+	issueStateOpened = "opened"
+)
+
+func newIssue(ctx *clientContext, apiObj *gitlab.Issue, ref gitprovider.RepositoryRef) *issue {
+	return &issue{
+		clientContext: ctx,
+		i:             *apiObj,
+		ref:           ref,
+	}
+}
+
+var _ gitprovider.Issue = &issue{}
+
+type issue struct {
+	*clientContext
+
+	i   gitlab.Issue
+	ref gitprovider.RepositoryRef
+}
+
+func (i *issue) Get() gitprovider.IssueInfo {
+	return issueFromAPI(&i.i)
+}
+
+func (i *issue) APIObject() interface{} {
+	return &i.i
+}
+
+func (i *issue) Repository() gitprovider.RepositoryRef {
+	return i.ref
+}
+
+func issueFromAPI(apiObj *gitlab.Issue) gitprovider.IssueInfo {
+	return gitprovider.IssueInfo{
+		// The IID is the number shown in the web UI, the ID is unique across the instance
+		Number: apiObj.IID,
+		Title:  apiObj.Title,
+		State:  issueStateFromAPI(apiObj.State),
+		URL:    apiObj.WebURL,
+	}
+}
+
+// issueStateFromAPI maps the state of a GitLab issue to an IssueState.
+func issueStateFromAPI(state string) gitprovider.IssueState {
+	if state == issueStateOpened {
+		return gitprovider.IssueStateOpen
+	}
+	return gitprovider.IssueState(state)
+}
+
+// issueStateToAPI maps an IssueState to the state of a GitLab issue.
+func issueStateToAPI(state gitprovider.IssueState) string {
+	if state == gitprovider.IssueStateOpen {
+		return issueStateOpened
+	}
+	return string(state)
+}
+
+// issueListOptions converts the given IssueListOptions to the go-gitlab options of the issue list.
+func issueListOptions(opts gitprovider.IssueListOptions) *gitlab.ListProjectIssuesOptions {
+	apiOpts := &gitlab.ListProjectIssuesOptions{}
+	if opts.State != nil {
+		apiOpts.State = gitlab.String(issueStateToAPI(*opts.State))
+	}
+	if opts.Label != "" {
+		apiOpts.Labels = gitlab.Labels{opts.Label}
+	}
+	return apiOpts
+}
+
+// validateIssueAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateIssueAPI(apiObj *gitlab.Issue) error {
+	return validateAPIObject("GitLab.Issue", func(validator validation.Validator) {
+		if apiObj.IID == 0 {
+			validator.Required("IID")
+		}
+		if apiObj.State == "" {
+			validator.Required("State")
+		}
+	})
+}
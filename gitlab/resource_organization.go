@@ -17,12 +17,21 @@ limitations under the License.
 package gitlab
 
 import (
+	"context"
+
 	"github.com/xanzy/go-gitlab"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
 	"github.com/dinosk/go-git-providers/validation"
 )
 
+const (
+	// maxGroupNameLength and maxGroupDescriptionLength are the maximum lengths of the name and
+	// description of a group.
+	maxGroupNameLength        = 255
+	maxGroupDescriptionLength = 255
+)
+
 func newOrganization(ctx *clientContext, apiObj *gitlab.Group, ref gitprovider.OrganizationRef) *organization {
 	return &organization{
 		clientContext: ctx,
@@ -32,6 +41,10 @@ func newOrganization(ctx *clientContext, apiObj *gitlab.Group, ref gitprovider.O
 			clientContext: ctx,
 			ref:           ref,
 		},
+		actionsSecrets: &OrgActionsSecretsClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
 	}
 }
 
@@ -43,13 +56,56 @@ type organization struct {
 	g   gitlab.Group
 	ref gitprovider.OrganizationRef
 
-	teams *TeamsClient
+	teams          *TeamsClient
+	actionsSecrets *OrgActionsSecretsClient
 }
 
 func (o *organization) Get() gitprovider.OrganizationInfo {
 	return organizationFromAPI(&o.g)
 }
 
+// GetMetadata fetches the profile of the group. GitLab doesn't supply the website and
+// member count of groups, hence they are nil.
+//
+// The internal API object will be overridden with the received server data.
+func (o *organization) GetMetadata(ctx context.Context) (gitprovider.OrganizationMetadata, error) {
+	// GET /groups/{group}
+	apiObj, err := o.c.GetGroup(ctx, o.g.ID)
+	if err != nil {
+		return gitprovider.OrganizationMetadata{}, err
+	}
+	o.g = *apiObj
+	return organizationMetadataFromAPI(apiObj), nil
+}
+
+// SetMetadata updates the name and description of the group. GitLab groups have no website,
+// hence Website is ignored. This is a no-op if the set fields already are the actual state.
+//
+// The internal API object will be overridden with the received server data.
+func (o *organization) SetMetadata(ctx context.Context, metadata gitprovider.OrganizationMetadata) error {
+	metadata.Website = nil
+	if err := validateGroupMetadata(metadata); err != nil {
+		return validation.NewMultiError(err, gitprovider.ErrInvalidArgument)
+	}
+	// GET /groups/{group}
+	actual, err := o.GetMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	// If desired state already is the actual state, do nothing
+	changes, changed := metadata.Changes(actual)
+	if !changed {
+		return nil
+	}
+	// PUT /groups/{group}
+	apiObj, err := o.c.UpdateGroupMetadata(ctx, o.g.ID, changes.DisplayName, changes.Description)
+	if err != nil {
+		return err
+	}
+	o.g = *apiObj
+	return nil
+}
+
 func (o *organization) APIObject() interface{} {
 	return &o.g
 }
@@ -62,6 +118,102 @@ func (o *organization) Teams() gitprovider.TeamsClient {
 	return o.teams
 }
 
+func (o *organization) ActionsSecrets() gitprovider.OrgActionsSecretsClient {
+	return o.actionsSecrets
+}
+
+// StreamAuditLog calls fn for each event in the audit log of this organization.
+//
+// This is not supported in GitLab, whose audit events have a different model.
+func (o *organization) StreamAuditLog(_ context.Context, _ gitprovider.AuditLogOptions, _ func(gitprovider.AuditEvent) error) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// GetDefaultBranchName returns the name of the initial branch of new projects in this group.
+// An empty string is returned if the group inherits the instance default.
+func (o *organization) GetDefaultBranchName(ctx context.Context) (string, error) {
+	// GET /groups/{group}
+	apiObj, err := o.c.GetGroupDefaultBranch(ctx, o.g.ID)
+	if err != nil {
+		return "", err
+	}
+	return apiObj.DefaultBranch, nil
+}
+
+// SetDefaultBranchName sets the name of the initial branch of new projects in this group.
+// This is a no-op if the name already is the actual state.
+func (o *organization) SetDefaultBranchName(ctx context.Context, name string) error {
+	if err := gitprovider.ValidateBranchName(name); err != nil {
+		return validation.NewMultiError(err, gitprovider.ErrInvalidArgument)
+	}
+	// GET /groups/{group}
+	apiObj, err := o.c.GetGroupDefaultBranch(ctx, o.g.ID)
+	if err != nil {
+		return err
+	}
+	// If desired state already is the actual state, do nothing
+	if apiObj.DefaultBranch == name {
+		return nil
+	}
+	// PUT /groups/{group}
+	return o.c.UpdateGroupDefaultBranch(ctx, o.g.ID, &groupDefaultBranch{DefaultBranch: name})
+}
+
+// SetRepositoryCreationLevel sets who can create projects in this group.
+// This is a no-op if the level already is the actual state.
+//
+// The internal API object will be overridden with the received server data.
+func (o *organization) SetRepositoryCreationLevel(ctx context.Context, level gitprovider.RepositoryCreationLevel) error {
+	if err := gitprovider.ValidateRepositoryCreationLevel(level); err != nil {
+		return validation.NewMultiError(err, gitprovider.ErrInvalidArgument)
+	}
+	// GET /groups/{group}
+	apiObj, err := o.c.GetGroup(ctx, o.g.ID)
+	if err != nil {
+		return err
+	}
+	// If desired state already is the actual state, do nothing
+	if apiObj.ProjectCreationLevel == gitlab.ProjectCreationLevelValue(level) {
+		o.g = *apiObj
+		return nil
+	}
+	// PUT /groups/{group}
+	apiObj, err = o.c.UpdateGroupProjectCreationLevel(ctx, o.g.ID, gitlab.ProjectCreationLevelValue(level))
+	if err != nil {
+		return err
+	}
+	o.g = *apiObj
+	return nil
+}
+
+// groupDefaultBranch is the subset of a group object, as returned from
+// "GET /groups/{group}", that go-gitlab doesn't provide a field for (yet).
+type groupDefaultBranch struct {
+	DefaultBranch string `json:"default_branch"`
+}
+
+// ListPATRequests lists the pending requests to access this organization with a fine-grained
+// personal access token.
+//
+// This is not supported in GitLab, which has no approval flow for personal access tokens.
+func (o *organization) ListPATRequests(_ context.Context) ([]gitprovider.PATRequest, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// ApprovePATRequest approves the pending fine-grained personal access token request with the given ID.
+//
+// This is not supported in GitLab, which has no approval flow for personal access tokens.
+func (o *organization) ApprovePATRequest(_ context.Context, _ int64) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// DenyPATRequest denies the pending fine-grained personal access token request with the given ID.
+//
+// This is not supported in GitLab, which has no approval flow for personal access tokens.
+func (o *organization) DenyPATRequest(_ context.Context, _ int64) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
 func organizationFromAPI(apiObj *gitlab.Group) gitprovider.OrganizationInfo {
 	return gitprovider.OrganizationInfo{
 		Name:        &apiObj.Name,
@@ -69,6 +221,26 @@ func organizationFromAPI(apiObj *gitlab.Group) gitprovider.OrganizationInfo {
 	}
 }
 
+func organizationMetadataFromAPI(apiObj *gitlab.Group) gitprovider.OrganizationMetadata {
+	return gitprovider.OrganizationMetadata{
+		DisplayName: &apiObj.Name,
+		Description: &apiObj.Description,
+		AvatarURL:   &apiObj.AvatarURL,
+	}
+}
+
+// validateGroupMetadata validates that the set fields of metadata fit a GitLab group.
+func validateGroupMetadata(metadata gitprovider.OrganizationMetadata) error {
+	validator := validation.New("OrganizationMetadata")
+	if metadata.DisplayName != nil {
+		validator.Append(gitprovider.ValidateMaxLength(*metadata.DisplayName, maxGroupNameLength), *metadata.DisplayName, "DisplayName")
+	}
+	if metadata.Description != nil {
+		validator.Append(gitprovider.ValidateMaxLength(*metadata.Description, maxGroupDescriptionLength), *metadata.Description, "Description")
+	}
+	return validator.Error()
+}
+
 // validateOrganizationAPI validates the apiObj received from the server, to make sure that it is
 // valid for our use.
 func validateGroupAPI(apiObj *gitlab.Group) error {
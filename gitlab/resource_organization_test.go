@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// fakeGroupsClient is a gitlabClient serving a single group, and counting the updates of its
+// project creation level. Calling any other method than the overridden ones panics.
+type fakeGroupsClient struct {
+	gitlabClient
+
+	group   gitlab.Group
+	updates int
+}
+
+func (c *fakeGroupsClient) GetGroup(_ context.Context, _ interface{}) (*gitlab.Group, error) {
+	apiObj := c.group
+	return &apiObj, nil
+}
+
+func (c *fakeGroupsClient) UpdateGroupProjectCreationLevel(_ context.Context, _ int, level gitlab.ProjectCreationLevelValue) (*gitlab.Group, error) {
+	c.updates++
+	c.group.ProjectCreationLevel = level
+	apiObj := c.group
+	return &apiObj, nil
+}
+
+func TestOrganization_SetRepositoryCreationLevel(t *testing.T) {
+	tests := []struct {
+		name        string
+		level       gitprovider.RepositoryCreationLevel
+		wantLevel   gitlab.ProjectCreationLevelValue
+		wantUpdates int
+		expectedErr error
+	}{
+		{
+			name:        "invalid level",
+			level:       gitprovider.RepositoryCreationLevel("everyone"),
+			wantLevel:   gitlab.DeveloperProjectCreation,
+			expectedErr: gitprovider.ErrInvalidArgument,
+		},
+		{
+			name:      "no-op when the level is already set",
+			level:     gitprovider.RepositoryCreationLevelDevelopers,
+			wantLevel: gitlab.DeveloperProjectCreation,
+		},
+		{
+			name:        "update the level",
+			level:       gitprovider.RepositoryCreationLevelMaintainers,
+			wantLevel:   gitlab.MaintainerProjectCreation,
+			wantUpdates: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeGroupsClient{group: gitlab.Group{ID: 42, ProjectCreationLevel: gitlab.DeveloperProjectCreation}}
+			ref := gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"}
+			org := newOrganization(&clientContext{c: fake, domain: DefaultDomain}, &fake.group, ref)
+
+			err := org.SetRepositoryCreationLevel(context.Background(), tt.level)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("SetRepositoryCreationLevel() error = %v, want %v", err, tt.expectedErr)
+			}
+			if fake.updates != tt.wantUpdates {
+				t.Errorf("got %d updates, want %d", fake.updates, tt.wantUpdates)
+			}
+			if got := org.APIObject().(*gitlab.Group).ProjectCreationLevel; got != tt.wantLevel {
+				t.Errorf("ProjectCreationLevel = %q, want %q", got, tt.wantLevel)
+			}
+		})
+	}
+}
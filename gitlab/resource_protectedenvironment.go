@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"fmt"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// protectedEnvironment is a protected environment object, as returned from
+// "GET /projects/{project}/protected_environments", which go-gitlab doesn't support (yet).
+type protectedEnvironment struct {
+	Name               string                    `json:"name"`
+	DeployAccessLevels []*environmentAccessLevel `json:"deploy_access_levels"`
+}
+
+// environmentAccessLevel grants a role, user or group access to deploy to a protected environment.
+// Destroy removes an existing entry, identified by ID, when updating the protected environment.
+type environmentAccessLevel struct {
+	ID          int  `json:"id,omitempty"`
+	AccessLevel int  `json:"access_level,omitempty"`
+	UserID      int  `json:"user_id,omitempty"`
+	GroupID     int  `json:"group_id,omitempty"`
+	Destroy     bool `json:"_destroy,omitempty"`
+}
+
+// isRole returns true if the entry grants access to a role, instead of to a specific user or group.
+func (l *environmentAccessLevel) isRole() bool {
+	return l.UserID == 0 && l.GroupID == 0
+}
+
+// environmentAccessLevels maps the known EnvironmentAccessLevel values to GitLab's access levels.
+//nolint:gochecknoglobals
+var environmentAccessLevels = map[gitprovider.EnvironmentAccessLevel]int{
+	gitprovider.EnvironmentAccessLevelDeveloper:  30,
+	gitprovider.EnvironmentAccessLevelMaintainer: 40,
+	gitprovider.EnvironmentAccessLevelAdmin:      60,
+}
+
+// validateProtectedEnvironmentAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateProtectedEnvironmentAPI(apiObj *protectedEnvironment) error {
+	return validateAPIObject("GitLab.ProtectedEnvironment", func(validator validation.Validator) {
+		if len(apiObj.Name) == 0 {
+			validator.Required("Name")
+		}
+	})
+}
+
+// protectedEnvironmentFromAPI returns the roles that can deploy to the environment. Access granted
+// to specific users or groups isn't included.
+func protectedEnvironmentFromAPI(apiObj *protectedEnvironment) (gitprovider.ProtectedEnvironmentInfo, error) {
+	info := gitprovider.ProtectedEnvironmentInfo{
+		Name:               apiObj.Name,
+		DeployAccessLevels: []gitprovider.EnvironmentAccessLevel{},
+	}
+	for _, level := range apiObj.DeployAccessLevels {
+		if !level.isRole() {
+			continue
+		}
+		accessLevel, err := getEnvironmentAccessLevel(level.AccessLevel)
+		if err != nil {
+			return gitprovider.ProtectedEnvironmentInfo{}, err
+		}
+		info.DeployAccessLevels = append(info.DeployAccessLevels, accessLevel)
+	}
+	return info, nil
+}
+
+// protectedEnvironmentToAPI returns the request to protect a new environment as described by req.
+func protectedEnvironmentToAPI(req gitprovider.ProtectedEnvironmentInfo) *protectedEnvironment {
+	apiObj := &protectedEnvironment{Name: req.Name}
+	for _, level := range req.DeployAccessLevels {
+		apiObj.DeployAccessLevels = append(apiObj.DeployAccessLevels, &environmentAccessLevel{
+			AccessLevel: environmentAccessLevels[level],
+		})
+	}
+	return apiObj
+}
+
+// protectedEnvironmentUpdate returns the request to make the roles in req the only ones that can deploy
+// to the actual protected environment. Roles that are no longer desired are destroyed, and missing
+// roles are added. Access granted to specific users or groups is left as-is.
+func protectedEnvironmentUpdate(req gitprovider.ProtectedEnvironmentInfo, actual *protectedEnvironment) *protectedEnvironment {
+	desired := make(map[int]bool, len(req.DeployAccessLevels))
+	for _, level := range req.DeployAccessLevels {
+		desired[environmentAccessLevels[level]] = true
+	}
+
+	apiObj := &protectedEnvironment{Name: actual.Name}
+	for _, level := range actual.DeployAccessLevels {
+		if !level.isRole() {
+			continue
+		}
+		if desired[level.AccessLevel] {
+			// Already granted, hence don't add it again
+			delete(desired, level.AccessLevel)
+			continue
+		}
+		apiObj.DeployAccessLevels = append(apiObj.DeployAccessLevels, &environmentAccessLevel{
+			ID:      level.ID,
+			Destroy: true,
+		})
+	}
+	// Add the missing roles in the order they were given, to be deterministic
+	for _, level := range req.DeployAccessLevels {
+		accessLevel := environmentAccessLevels[level]
+		if !desired[accessLevel] {
+			continue
+		}
+		delete(desired, accessLevel)
+		apiObj.DeployAccessLevels = append(apiObj.DeployAccessLevels, &environmentAccessLevel{
+			AccessLevel: accessLevel,
+		})
+	}
+	return apiObj
+}
+
+// getEnvironmentAccessLevel maps GitLab's access level to an EnvironmentAccessLevel.
+func getEnvironmentAccessLevel(accessLevel int) (gitprovider.EnvironmentAccessLevel, error) {
+	for level, value := range environmentAccessLevels {
+		if value == accessLevel {
+			return level, nil
+		}
+	}
+	return "", fmt.Errorf("unknown deploy access level %d: %w", accessLevel, gitprovider.ErrInvalidServerData)
+}
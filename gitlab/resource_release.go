@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+	"github.com/xanzy/go-gitlab"
+)
+
+// release extends go-gitlab's Release with the fields that go-gitlab doesn't provide (yet).
+type release struct {
+	gitlab.Release
+	// ReleasedAt is the (possibly future) date the release is made available.
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	// UpcomingRelease is true if ReleasedAt is in the future.
+	UpcomingRelease bool `json:"upcoming_release"`
+}
+
+func newRelease(ctx *clientContext, apiObj *release, ref gitprovider.RepositoryRef) *projectRelease {
+	return &projectRelease{
+		clientContext: ctx,
+		r:             *apiObj,
+		ref:           ref,
+	}
+}
+
+var _ gitprovider.Release = &projectRelease{}
+
+// projectRelease implements gitprovider.Release, named to not collide with the release API type.
+type projectRelease struct {
+	*clientContext
+
+	r   release
+	ref gitprovider.RepositoryRef
+}
+
+func (r *projectRelease) Get() gitprovider.ReleaseInfo {
+	return releaseFromAPI(&r.r)
+}
+
+func (r *projectRelease) APIObject() interface{} {
+	return &r.r
+}
+
+func (r *projectRelease) Repository() gitprovider.RepositoryRef {
+	return r.ref
+}
+
+// releasedAt returns the date apiObj is made available, falling back to its creation date for
+// servers that don't return the release date.
+func (r *release) releasedAt() time.Time {
+	if r.ReleasedAt != nil {
+		return *r.ReleasedAt
+	}
+	if r.CreatedAt != nil {
+		return *r.CreatedAt
+	}
+	return time.Time{}
+}
+
+// latestRelease returns the release with the most recent release date, or nil if there is none.
+// Upcoming releases are ignored, in line with GitHub not considering prereleases the latest release.
+func latestRelease(apiObjs []*release) *release {
+	var latest *release
+	for _, apiObj := range apiObjs {
+		if apiObj.UpcomingRelease {
+			continue
+		}
+		if latest == nil || apiObj.releasedAt().After(latest.releasedAt()) {
+			latest = apiObj
+		}
+	}
+	return latest
+}
+
+func releaseFromAPI(apiObj *release) gitprovider.ReleaseInfo {
+	info := gitprovider.ReleaseInfo{
+		TagName:    apiObj.TagName,
+		Name:       gitprovider.StringVar(apiObj.Name),
+		Body:       gitprovider.StringVar(apiObj.Description),
+		Draft:      gitprovider.BoolVar(false),
+		Prerelease: gitprovider.BoolVar(apiObj.UpcomingRelease),
+	}
+	if apiObj.Commit.ID != "" {
+		info.TargetCommitish = gitprovider.StringVar(apiObj.Commit.ID)
+	}
+	return info
+}
+
+// releaseToAPI converts info to the options of a new release. GitLab doesn't have draft releases, and
+// prereleases are only expressed through a future release date, hence neither can be requested.
+func releaseToAPI(info *gitprovider.ReleaseInfo) (*gitlab.CreateReleaseOptions, error) {
+	if info.Draft != nil && *info.Draft {
+		return nil, fmt.Errorf("creating draft releases: %w", gitprovider.ErrNoProviderSupport)
+	}
+	if info.Prerelease != nil && *info.Prerelease {
+		return nil, fmt.Errorf("creating prereleases: %w", gitprovider.ErrNoProviderSupport)
+	}
+	opts := &gitlab.CreateReleaseOptions{
+		TagName:     &info.TagName,
+		Name:        info.Name,
+		Description: info.Body,
+		Ref:         info.TargetCommitish,
+	}
+	// GitLab requires a name, where GitHub defaults to the tag name
+	if opts.Name == nil {
+		opts.Name = &info.TagName
+	}
+	return opts, nil
+}
+
+// validateReleaseAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateReleaseAPI(apiObj *release) error {
+	return validateAPIObject("GitLab.Release", func(validator validation.Validator) {
+		if apiObj.TagName == "" {
+			validator.Required("TagName")
+		}
+	})
+}
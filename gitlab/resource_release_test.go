@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_latestRelease(t *testing.T) {
+	day := func(d int) *time.Time {
+		ts := time.Date(2024, time.January, d, 0, 0, 0, 0, time.UTC)
+		return &ts
+	}
+	newRelease := func(tag string, releasedAt *time.Time, upcoming bool) *release {
+		apiObj := &release{ReleasedAt: releasedAt, UpcomingRelease: upcoming}
+		apiObj.TagName = tag
+		apiObj.CreatedAt = day(1)
+		return apiObj
+	}
+	tests := []struct {
+		name    string
+		apiObjs []*release
+		want    string
+	}{
+		{
+			name: "no releases",
+		},
+		{
+			name: "most recent release date, not creation order",
+			apiObjs: []*release{
+				newRelease("v1.1.0", day(3), false),
+				newRelease("v1.2.0", day(5), false),
+				newRelease("v1.0.1", day(4), false),
+			},
+			want: "v1.2.0",
+		},
+		{
+			name: "upcoming releases are ignored",
+			apiObjs: []*release{
+				newRelease("v1.1.0", day(3), false),
+				newRelease("v2.0.0", day(20), true),
+			},
+			want: "v1.1.0",
+		},
+		{
+			name:    "only upcoming releases",
+			apiObjs: []*release{newRelease("v2.0.0", day(20), true)},
+		},
+		{
+			name: "falls back to the creation date",
+			apiObjs: []*release{
+				newRelease("v1.0.0", nil, false),
+				newRelease("v1.1.0", day(2), false),
+			},
+			want: "v1.1.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if latest := latestRelease(tt.apiObjs); latest != nil {
+				got = latest.TagName
+			}
+			if got != tt.want {
+				t.Errorf("latestRelease() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@ package gitlab
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
 
 	"github.com/google/go-cmp/cmp"
 	gogitlab "github.com/xanzy/go-gitlab"
@@ -35,6 +37,38 @@ func newUserProject(ctx *clientContext, apiObj *gogitlab.Project, ref gitprovide
 			clientContext: ctx,
 			ref:           ref,
 		},
+		releases: &ReleaseClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		commits: &CommitClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branches: &BranchClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		tags: &TagClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		issues: &IssueClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		hooks: &RepositoryHookClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		rulesets: &RulesetClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branchProtection: &BranchProtectionClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
 	}
 }
 
@@ -45,12 +79,27 @@ type userProject struct {
 
 	p   gogitlab.Project
 	ref gitprovider.RepositoryRef
+	// allowForking is kept next to p, as go-gitlab doesn't provide a field for it (yet).
+	// allowForkingChanged is true if it was changed by Set, and needs to be applied in Update.
+	allowForking        *bool
+	allowForkingChanged bool
 
 	deployKeys *DeployKeyClient
+	releases   *ReleaseClient
+	commits    *CommitClient
+	branches   *BranchClient
+	tags       *TagClient
+	issues     *IssueClient
+	hooks      *RepositoryHookClient
+	rulesets   *RulesetClient
+
+	branchProtection *BranchProtectionClient
 }
 
 func (p *userProject) Get() gitprovider.RepositoryInfo {
-	return repositoryFromAPI(&p.p)
+	info := repositoryFromAPI(&p.p)
+	info.AllowForking = p.allowForking
+	return info
 }
 
 func (p *userProject) Set(info gitprovider.RepositoryInfo) error {
@@ -58,6 +107,10 @@ func (p *userProject) Set(info gitprovider.RepositoryInfo) error {
 		return err
 	}
 	repositoryInfoToAPIObj(&info, &p.p)
+	if info.AllowForking != nil && !reflect.DeepEqual(info.AllowForking, p.allowForking) {
+		p.allowForking = info.AllowForking
+		p.allowForkingChanged = true
+	}
 	return nil
 }
 
@@ -73,6 +126,38 @@ func (p *userProject) DeployKeys() gitprovider.DeployKeyClient {
 	return p.deployKeys
 }
 
+func (p *userProject) Releases() gitprovider.ReleaseClient {
+	return p.releases
+}
+
+func (p *userProject) Commits() gitprovider.CommitClient {
+	return p.commits
+}
+
+func (p *userProject) Branches() gitprovider.BranchClient {
+	return p.branches
+}
+
+func (p *userProject) Tags() gitprovider.TagClient {
+	return p.tags
+}
+
+func (p *userProject) Issues() gitprovider.IssueClient {
+	return p.issues
+}
+
+func (p *userProject) Hooks() gitprovider.RepositoryHookClient {
+	return p.hooks
+}
+
+func (p *userProject) Rulesets() gitprovider.RulesetClient {
+	return p.rulesets
+}
+
+func (p *userProject) BranchProtection() gitprovider.BranchProtectionClient {
+	return p.branchProtection
+}
+
 // The internal API object will be overridden with the received server data.
 func (p *userProject) Update(ctx context.Context) error {
 	// PATCH /repos/{owner}/{repo}
@@ -81,7 +166,27 @@ func (p *userProject) Update(ctx context.Context) error {
 		return err
 	}
 	p.p = *apiObj
-	return nil
+	if !p.allowForkingChanged {
+		return nil
+	}
+	return p.updateAllowForking(ctx)
+}
+
+// Refresh fetches the current state of this repository from the server, without
+// mutating anything. Any local changes that weren't applied are discarded.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (p *userProject) Refresh(ctx context.Context) error {
+	// GET /projects/{project}
+	apiObj, err := p.c.GetUserProject(ctx, getRepoPath(p.ref))
+	if err != nil {
+		return err
+	}
+	p.p = *apiObj
+	p.allowForkingChanged = false
+	return p.getAllowForking(ctx)
 }
 
 // Reconcile makes sure the desired state in this object (called "req" here) becomes
@@ -106,18 +211,22 @@ func (p *userProject) Reconcile(ctx context.Context) (bool, error) {
 				return true, err
 			}
 			p.p = *project
-			return true, nil
+			if p.allowForking == nil {
+				return true, nil
+			}
+			return true, p.updateAllowForking(ctx)
 		}
 
 		return false, err
 	}
 
-	// Use wrappers here to extract the "spec" part of the object for comparison
-	desiredSpec := newGitlabProjectSpec(&p.p)
-	actualSpec := newGitlabProjectSpec(apiObj)
+	allowForkingEquals, err := p.allowForkingEquals(ctx)
+	if err != nil {
+		return false, err
+	}
 
 	// If desired state already is the actual state, do nothing
-	if desiredSpec.Equals(actualSpec) {
+	if projectInfoEquals(&p.p, apiObj) && allowForkingEquals {
 		return false, nil
 	}
 	// Otherwise, make the desired state the actual state
@@ -131,6 +240,435 @@ func (p *userProject) Delete(ctx context.Context) error {
 	return p.c.DeleteProject(ctx, getRepoPath(p.ref))
 }
 
+// ListSecurityAdvisories lists the security advisories filed for this repository.
+//
+// This is not supported in GitLab.
+func (p *userProject) ListSecurityAdvisories(_ context.Context, _ gitprovider.SecurityAdvisoryListOptions) ([]gitprovider.SecurityAdvisoryInfo, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// ListInstalledApps lists the apps that have been granted access to this repository.
+//
+// This is not supported in GitLab, whose integrations are configured per project instead.
+func (p *userProject) ListInstalledApps(_ context.Context) ([]gitprovider.InstalledAppInfo, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// EffectivePermissions returns the permissions the credentials of the client have on this project.
+//
+// This is not supported in GitLab, which only exposes the access level of the user, not granular permissions.
+func (p *userProject) EffectivePermissions(_ context.Context) (map[string]string, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// SetPipelineRequirements configures what CI results are required before changes can be merged.
+// This is a no-op if the requirements already are the actual state.
+//
+// The internal API object will be overridden with the received server data.
+func (p *userProject) SetPipelineRequirements(ctx context.Context, req gitprovider.PipelineRequirements) error {
+	// GET /projects/{project}
+	apiObj, err := p.c.GetUserProject(ctx, getRepoPath(p.ref))
+	if err != nil {
+		return err
+	}
+	// If desired state already is the actual state, do nothing
+	if apiObj.OnlyAllowMergeIfPipelineSucceeds == req.RequireSuccessfulPipeline &&
+		apiObj.OnlyAllowMergeIfAllDiscussionsAreResolved == req.RequireResolvedDiscussions {
+		p.p = *apiObj
+		return nil
+	}
+	// PUT /projects/{project}
+	apiObj, err = p.c.UpdateProjectMergeChecks(ctx, apiObj.ID, req.RequireSuccessfulPipeline, req.RequireResolvedDiscussions)
+	if err != nil {
+		return err
+	}
+	p.p = *apiObj
+	return nil
+}
+
+// SetIssueCloseSettings configures how issues are closed automatically when referenced.
+// This is a no-op if the settings already are the actual state.
+func (p *userProject) SetIssueCloseSettings(ctx context.Context, req gitprovider.IssueCloseSettings) error {
+	// GET /projects/{project}
+	apiObj, err := p.c.GetProjectIssueCloseSettings(ctx, p.p.ID)
+	if err != nil {
+		return err
+	}
+	// If desired state already is the actual state, do nothing
+	if *apiObj.AutocloseReferencedIssues == req.AutocloseReferencedIssues {
+		return nil
+	}
+	// PUT /projects/{project}
+	return p.c.UpdateProjectIssueCloseSettings(ctx, p.p.ID, &projectIssueCloseSettings{
+		AutocloseReferencedIssues: &req.AutocloseReferencedIssues,
+	})
+}
+
+// PrimaryLanguage returns the language with the largest share of the code in this project.
+// An empty string is returned if the project has no detectable language.
+//
+// GitLab's project object has no language field, neither in the REST API nor in go-gitlab, hence
+// the languages of the project are requested, which GitLab returns as percentages.
+func (p *userProject) PrimaryLanguage(ctx context.Context) (string, error) {
+	// GET /projects/{project}/languages
+	languages, err := p.c.GetProjectLanguages(ctx, getRepoPath(p.ref))
+	if err != nil {
+		return "", err
+	}
+	return primaryLanguage(languages), nil
+}
+
+// IsEmpty returns true if the project's repository has no commits yet.
+//
+// ErrNotFound is returned if the project does not exist.
+func (p *userProject) IsEmpty(ctx context.Context) (bool, error) {
+	// GET /projects/{project}
+	apiObj, err := p.c.GetProjectEmptyStatus(ctx, getRepoPath(p.ref))
+	if err != nil {
+		return false, err
+	}
+	return *apiObj.EmptyRepo, nil
+}
+
+// GetMergeQueue returns the merge queue settings of the given branch.
+//
+// This is not supported in GitLab, which has merge trains instead.
+func (p *userProject) GetMergeQueue(_ context.Context, _ string) (gitprovider.MergeQueueInfo, error) {
+	return gitprovider.MergeQueueInfo{}, gitprovider.ErrNoProviderSupport
+}
+
+// SetMergeQueue configures the merge queue of the given branch.
+//
+// This is not supported in GitLab, which has merge trains instead.
+func (p *userProject) SetMergeQueue(_ context.Context, _ string, _ gitprovider.MergeQueueInfo) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// SetMergeTrain enables or disables merge trains. Enabling merge trains also enables merged results
+// pipelines, which they require. This is a no-op if enabled is the actual state.
+//
+// Merge trains require a premium tier, otherwise ErrFeatureNotAvailable is returned.
+func (p *userProject) SetMergeTrain(ctx context.Context, enabled bool) error {
+	// GET /projects/{project}
+	apiObj, err := p.c.GetProjectMergeTrainSettings(ctx, p.p.ID)
+	if err != nil {
+		return err
+	}
+	// GitLab omits the field from the project if merge trains aren't available in its tier
+	if apiObj.MergeTrainsEnabled == nil {
+		return fmt.Errorf("merge trains require a premium tier: %w", gitprovider.ErrFeatureNotAvailable)
+	}
+	// If desired state already is the actual state, do nothing
+	if *apiObj.MergeTrainsEnabled == enabled {
+		return nil
+	}
+	req := &projectMergeTrainSettings{MergeTrainsEnabled: &enabled}
+	if enabled {
+		req.MergePipelinesEnabled = &enabled
+	}
+	// PUT /projects/{project}
+	return p.c.UpdateProjectMergeTrainSettings(ctx, p.p.ID, req)
+}
+
+// TriggerMirrorSync starts syncing this pull mirror with its upstream repository right away.
+// The sync runs in the background, use GetMirrorStatus to follow it.
+//
+// ErrNotMirror is returned if this project isn't a pull mirror, which requires a premium tier.
+func (p *userProject) TriggerMirrorSync(ctx context.Context) error {
+	// GET /projects/{project}
+	apiObj, err := p.getMirror(ctx)
+	if err != nil {
+		return err
+	}
+	// POST /projects/{project}/mirror/pull
+	return p.c.StartPullMirror(ctx, apiObj.ID)
+}
+
+// GetMirrorStatus returns the state of the last sync of this pull mirror, including the error
+// GitLab got from the upstream repository, if the sync failed.
+//
+// ErrNotMirror is returned if this project isn't a pull mirror, which requires a premium tier.
+func (p *userProject) GetMirrorStatus(ctx context.Context) (gitprovider.MirrorStatusInfo, error) {
+	// GET /projects/{project}
+	apiObj, err := p.getMirror(ctx)
+	if err != nil {
+		return gitprovider.MirrorStatusInfo{}, err
+	}
+	return mirrorStatusFromAPI(apiObj), nil
+}
+
+// getMirror gets the project, and makes sure it's a pull mirror.
+func (p *userProject) getMirror(ctx context.Context) (*gogitlab.Project, error) {
+	// GET /projects/{project}
+	apiObj, err := p.c.GetUserProject(ctx, getRepoPath(p.ref))
+	if err != nil {
+		return nil, err
+	}
+	// Mirror is false too if pull mirroring isn't available in the tier of the project
+	if !apiObj.Mirror {
+		return nil, fmt.Errorf("project %q isn't a pull mirror: %w", getRepoPath(p.ref), gitprovider.ErrNotMirror)
+	}
+	return apiObj, nil
+}
+
+// CountOpenIssues returns the amount of open issues in this project, or 0 if the issue
+// tracker is disabled.
+func (p *userProject) CountOpenIssues(ctx context.Context) (int64, error) {
+	// GET /projects/{project}
+	apiObj, err := p.c.GetUserProject(ctx, getRepoPath(p.ref))
+	if err != nil {
+		return 0, err
+	}
+	// GitLab omits the count if the issue tracker is disabled
+	if !apiObj.IssuesEnabled {
+		return 0, nil
+	}
+	return int64(apiObj.OpenIssuesCount), nil
+}
+
+// CountOpenPullRequests returns the amount of open merge requests in this project, or 0 if
+// merge requests are disabled.
+func (p *userProject) CountOpenPullRequests(ctx context.Context) (int64, error) {
+	// GET /projects/{project}
+	apiObj, err := p.c.GetUserProject(ctx, getRepoPath(p.ref))
+	if err != nil {
+		return 0, err
+	}
+	if !apiObj.MergeRequestsEnabled {
+		return 0, nil
+	}
+	// GET /projects/{project}/merge_requests
+	return p.c.CountProjectMergeRequests(ctx, getRepoPath(p.ref), "opened")
+}
+
+// SetWebCommitSigning configures whether pushes of unsigned commits are rejected, using the
+// "reject unsigned commits" push rule of the project. This is a no-op if enabled is the actual state.
+// Push rules require a premium tier, otherwise ErrFeatureNotAvailable is returned.
+func (p *userProject) SetWebCommitSigning(ctx context.Context, enabled bool) error {
+	// GET /projects/{project}/push_rule
+	apiObj, err := p.c.GetProjectPushRule(ctx, p.p.ID)
+	if err != nil {
+		// GitLab hides the endpoint if push rules aren't available in its tier
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return fmt.Errorf("push rules require a premium tier: %w", gitprovider.ErrFeatureNotAvailable)
+		}
+		return err
+	}
+	req := &gogitlab.EditProjectPushRuleOptions{RejectUnsignedCommits: &enabled}
+	// Without a push rule, unsigned commits are accepted
+	if apiObj == nil {
+		if !enabled {
+			return nil
+		}
+		// POST /projects/{project}/push_rule
+		_, err = p.c.AddProjectPushRule(ctx, p.p.ID, req)
+		return err
+	}
+	// If desired state already is the actual state, do nothing
+	if apiObj.RejectUnsignedCommits == enabled {
+		return nil
+	}
+	// PUT /projects/{project}/push_rule
+	_, err = p.c.EditProjectPushRule(ctx, p.p.ID, req)
+	return err
+}
+
+// ListProtectedEnvironments lists the protected environments of this project, along with the roles
+// that can deploy to them. Access granted to specific users or groups isn't included.
+//
+// Protected environments require a premium tier, otherwise ErrFeatureNotAvailable is returned.
+func (p *userProject) ListProtectedEnvironments(ctx context.Context) ([]gitprovider.ProtectedEnvironmentInfo, error) {
+	apiObjs, err := p.listProtectedEnvironments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]gitprovider.ProtectedEnvironmentInfo, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		info, err := protectedEnvironmentFromAPI(apiObj)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// ProtectEnvironment makes sure the environment with the name of req is protected, and that only
+// the roles in req can deploy to it. Access granted to specific users or groups is left as-is.
+// This is a no-op if req already is the actual state.
+//
+// Protected environments require a premium tier, otherwise ErrFeatureNotAvailable is returned.
+func (p *userProject) ProtectEnvironment(ctx context.Context, req gitprovider.ProtectedEnvironmentInfo) error {
+	if err := req.ValidateInfo(); err != nil {
+		return err
+	}
+	apiObjs, err := p.listProtectedEnvironments(ctx)
+	if err != nil {
+		return err
+	}
+	// Protected environments are identified by their name
+	for _, apiObj := range apiObjs {
+		if apiObj.Name != req.Name {
+			continue
+		}
+		actual, err := protectedEnvironmentFromAPI(apiObj)
+		if err != nil {
+			return err
+		}
+		// If desired state already is the actual state, do nothing
+		if req.Equals(actual) {
+			return nil
+		}
+		// PUT /projects/{project}/protected_environments/{name}
+		_, err = p.c.UpdateProjectProtectedEnvironment(ctx, p.p.ID, protectedEnvironmentUpdate(req, apiObj))
+		return err
+	}
+	// POST /projects/{project}/protected_environments
+	_, err = p.c.ProtectProjectEnvironment(ctx, p.p.ID, protectedEnvironmentToAPI(req))
+	return err
+}
+
+// UnprotectEnvironment removes the protection of the environment with the given name. This requires
+// destructive actions to be enabled in the client.
+//
+// ErrNotFound is returned if the environment isn't protected.
+func (p *userProject) UnprotectEnvironment(ctx context.Context, name string) error {
+	// DELETE /projects/{project}/protected_environments/{name}
+	return p.c.UnprotectProjectEnvironment(ctx, p.p.ID, name)
+}
+
+// SetSubscription configures the notification level of the authenticated user for this project.
+// SubscriptionLevelAll maps to "watch", SubscriptionLevelParticipating to "participating", and
+// SubscriptionLevelIgnore to "disabled". The other levels, e.g. "global", are always changed.
+func (p *userProject) SetSubscription(ctx context.Context, req gitprovider.SubscriptionInfo) error {
+	if err := req.ValidateInfo(); err != nil {
+		return err
+	}
+	level, ok := gitlabNotificationLevelMap[req.Level]
+	if !ok {
+		return fmt.Errorf("subscription level %q: %w", req.Level, gitprovider.ErrInvalidArgument)
+	}
+	// GET /projects/{project}/notification_settings
+	apiObj, err := p.c.GetProjectNotificationSettings(ctx, p.p.ID)
+	if err != nil {
+		return err
+	}
+	// If desired state already is the actual state, do nothing
+	if apiObj.Level == level {
+		return nil
+	}
+	// PUT /projects/{project}/notification_settings
+	return p.c.UpdateProjectNotificationLevel(ctx, p.p.ID, level)
+}
+
+// listProtectedEnvironments lists the protected environments of this project, returning
+// ErrFeatureNotAvailable if they aren't available in the tier of the project.
+func (p *userProject) listProtectedEnvironments(ctx context.Context) ([]*protectedEnvironment, error) {
+	// GET /projects/{project}/protected_environments
+	apiObjs, err := p.c.ListProjectProtectedEnvironments(ctx, p.p.ID)
+	if err != nil {
+		// GitLab hides the endpoint if protected environments aren't available in its tier
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return nil, fmt.Errorf("protected environments require a premium tier: %w", gitprovider.ErrFeatureNotAvailable)
+		}
+		return nil, err
+	}
+	return apiObjs, nil
+}
+
+// projectMergeTrainSettings is the subset of a project object, as returned from
+// "GET /projects/{project}", that go-gitlab doesn't provide a field for (yet).
+type projectMergeTrainSettings struct {
+	MergePipelinesEnabled *bool `json:"merge_pipelines_enabled,omitempty"`
+	MergeTrainsEnabled    *bool `json:"merge_trains_enabled,omitempty"`
+}
+
+// projectEmptyStatus is the subset of a project object, as returned from
+// "GET /projects/{project}", that go-gitlab doesn't provide a field for (yet).
+type projectEmptyStatus struct {
+	EmptyRepo *bool `json:"empty_repo"`
+}
+
+// primaryLanguage returns the language with the largest percentage, or an empty string
+// if languages is empty. Ties are broken alphabetically, to be deterministic.
+func primaryLanguage(languages map[string]float32) string {
+	primary, maxShare := "", float32(0)
+	for language, share := range languages {
+		if primary == "" || share > maxShare || (share == maxShare && language < primary) {
+			primary, maxShare = language, share
+		}
+	}
+	return primary
+}
+
+// getAllowForking fetches whether the project can be forked from the server.
+func (p *userProject) getAllowForking(ctx context.Context) error {
+	// GET /projects/{project}
+	apiObj, err := p.c.GetProjectForkingSettings(ctx, p.p.ID)
+	if err != nil {
+		return err
+	}
+	p.allowForking = allowForkingFromAPI(apiObj.ForkingAccessLevel)
+	return nil
+}
+
+// updateAllowForking applies the desired allowForking to the server.
+func (p *userProject) updateAllowForking(ctx context.Context) error {
+	// PUT /projects/{project}
+	if err := p.c.UpdateProjectForkingSettings(ctx, p.p.ID, &projectForkingSettings{
+		ForkingAccessLevel: allowForkingToAPI(*p.allowForking),
+	}); err != nil {
+		return err
+	}
+	p.allowForkingChanged = false
+	return nil
+}
+
+// allowForkingEquals compares allowForking with the actual state, if it's desired.
+// AllowForking isn't part of the project object, hence it's compared separately.
+func (p *userProject) allowForkingEquals(ctx context.Context) (bool, error) {
+	if p.allowForking == nil {
+		return true, nil
+	}
+	// GET /projects/{project}
+	apiObj, err := p.c.GetProjectForkingSettings(ctx, p.p.ID)
+	if err != nil {
+		return false, err
+	}
+	equals := reflect.DeepEqual(p.allowForking, allowForkingFromAPI(apiObj.ForkingAccessLevel))
+	p.allowForkingChanged = !equals
+	return equals, nil
+}
+
+// allowForkingFromAPI maps the forking access level to whether the project can be forked.
+// The "private" level only allows members to fork, which still counts as allowed.
+func allowForkingFromAPI(level *gogitlab.AccessControlValue) *bool {
+	if level == nil {
+		return nil
+	}
+	return gitprovider.BoolVar(*level != gogitlab.DisabledAccessControl)
+}
+
+// allowForkingToAPI maps whether the project can be forked to the forking access level.
+func allowForkingToAPI(allowForking bool) *gogitlab.AccessControlValue {
+	if allowForking {
+		return gogitlab.AccessControl(gogitlab.EnabledAccessControl)
+	}
+	return gogitlab.AccessControl(gogitlab.DisabledAccessControl)
+}
+
+// projectForkingSettings is the subset of a project object, as returned from
+// "GET /projects/{project}", that go-gitlab doesn't provide a field for (yet).
+type projectForkingSettings struct {
+	ForkingAccessLevel *gogitlab.AccessControlValue `json:"forking_access_level,omitempty"`
+}
+
+// projectIssueCloseSettings is the subset of a project object, as returned from
+// "GET /projects/{project}", that go-gitlab doesn't provide a field for (yet).
+type projectIssueCloseSettings struct {
+	AutocloseReferencedIssues *bool `json:"autoclose_referenced_issues,omitempty"`
+}
+
 func newGroupProject(ctx *clientContext, apiObj *gogitlab.Project, ref gitprovider.RepositoryRef) *orgRepository {
 	return &orgRepository{
 		userProject: *newUserProject(ctx, apiObj, ref),
@@ -171,18 +709,22 @@ func (r *orgRepository) Reconcile(ctx context.Context) (bool, error) {
 				return true, err
 			}
 			r.p = *project
-			return true, nil
+			if r.allowForking == nil {
+				return true, nil
+			}
+			return true, r.updateAllowForking(ctx)
 		}
 
 		return false, err
 	}
 
-	// Use wrappers here to extract the "spec" part of the object for comparison
-	desiredSpec := newGitlabProjectSpec(&r.p)
-	actualSpec := newGitlabProjectSpec(apiObj)
+	allowForkingEquals, err := r.allowForkingEquals(ctx)
+	if err != nil {
+		return false, err
+	}
 
 	// If desired state already is the actual state, do nothing
-	if desiredSpec.Equals(actualSpec) {
+	if projectInfoEquals(&r.p, apiObj) && allowForkingEquals {
 		return false, nil
 	}
 	// Otherwise, make the desired state the actual state
@@ -193,11 +735,50 @@ func repositoryFromAPI(apiObj *gogitlab.Project) gitprovider.RepositoryInfo {
 	repo := gitprovider.RepositoryInfo{
 		Description:   &apiObj.Description,
 		DefaultBranch: &apiObj.DefaultBranch,
+		HasIssues:     &apiObj.IssuesEnabled,
+		HasWiki:       &apiObj.WikiEnabled,
+		Topics:        append([]string{}, apiObj.TagList...),
 	}
-	repo.Visibility = gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibility(apiObj.Visibility))
+	repo.Visibility = gitprovider.RepositoryVisibilityVar(repositoryVisibilityFromAPI(apiObj.Visibility))
 	return repo
 }
 
+// repositoryVisibilityFromAPI maps the visibility level of a project to the repository visibility.
+// Unknown levels are passed through, and hence fail validation instead of silently becoming private.
+func repositoryVisibilityFromAPI(visibility gogitlab.VisibilityValue) gitprovider.RepositoryVisibility {
+	for v, apiVisibility := range gitlabVisibilityMap {
+		if apiVisibility == visibility {
+			return v
+		}
+	}
+	return gitprovider.RepositoryVisibility(visibility)
+}
+
+// projectInfoEquals compares the settable RepositoryInfo fields of the desired and actual project,
+// rather than the whole API objects, which also carry fields populated by the server.
+func projectInfoEquals(desired, actual *gogitlab.Project) bool {
+	desiredInfo, actualInfo := repositoryFromAPI(desired), repositoryFromAPI(actual)
+	// Topics have no default, leave them as-is if they aren't desired
+	if desired.TagList == nil {
+		desiredInfo.Topics = actualInfo.Topics
+	}
+	return desiredInfo.Equals(actualInfo)
+}
+
+// mirrorStatusFromAPI maps the import fields of a pull mirror project, which GitLab uses to
+// track its syncs, to the sync status.
+func mirrorStatusFromAPI(apiObj *gogitlab.Project) gitprovider.MirrorStatusInfo {
+	status := gitprovider.MirrorStatusInfo{Status: gitprovider.MirrorSyncStatus(apiObj.ImportStatus)}
+	// Treat a missing status as not synced yet
+	if len(status.Status) == 0 {
+		status.Status = gitprovider.MirrorSyncStatusNone
+	}
+	if status.Status == gitprovider.MirrorSyncStatusFailed {
+		status.LastError = apiObj.ImportError
+	}
+	return status
+}
+
 func repositoryToAPI(repo *gitprovider.RepositoryInfo, ref gitprovider.RepositoryRef) gogitlab.Project {
 	apiObj := gogitlab.Project{
 		Name: *gitprovider.StringVar(ref.GetRepository()),
@@ -216,21 +797,36 @@ func repositoryInfoToAPIObj(repo *gitprovider.RepositoryInfo, apiObj *gogitlab.P
 	if repo.Visibility != nil {
 		apiObj.Visibility = gitlabVisibilityMap[*repo.Visibility]
 	}
+	if repo.HasIssues != nil {
+		apiObj.IssuesEnabled = *repo.HasIssues
+	}
+	if repo.HasWiki != nil {
+		apiObj.WikiEnabled = *repo.HasWiki
+	}
+	if repo.Topics != nil {
+		apiObj.TagList = gitprovider.NormalizeTopics(repo.Topics)
+	}
+	// HasProjects has no GitLab equivalent, and is ignored
 }
 
 // This function copies over the fields that are part of create/update requests of a project
 // i.e. the desired spec of the repository. This allows us to separate "spec" from "status" fields.
+// Fields that GitLab canonicalizes server-side are normalized, see RepositoryInfo.Normalized().
 func newGitlabProjectSpec(project *gogitlab.Project) *gitlabProjectSpec {
 	return &gitlabProjectSpec{
 		&gogitlab.Project{
 			// Generic
 			Name:        project.Name,
 			Namespace:   project.Namespace,
-			Description: project.Description,
+			Description: gitprovider.NormalizeDescription(project.Description),
 			Visibility:  project.Visibility,
 
+			IssuesEnabled: project.IssuesEnabled,
+			WikiEnabled:   project.WikiEnabled,
+			TagList:       gitprovider.NormalizeTopics(project.TagList),
+
 			// Update-specific parameters
-			DefaultBranch: project.DefaultBranch,
+			DefaultBranch: gitprovider.NormalizeBranchName(project.DefaultBranch),
 		},
 	}
 }
@@ -243,6 +839,75 @@ func (s *gitlabProjectSpec) Equals(other *gitlabProjectSpec) bool {
 	return cmp.Equal(s, other)
 }
 
+// GetCommunityHealthFile returns the content of the given community health file on the default branch.
+//
+// ErrNotFound is returned if the project doesn't have the file.
+func (p *userProject) GetCommunityHealthFile(ctx context.Context, file gitprovider.CommunityHealthFile) (string, error) {
+	_, content, err := p.findCommunityHealthFile(ctx, file)
+	return content, err
+}
+
+// SetCommunityHealthFile commits content as the given community health file to the default branch.
+// New files are created in the root of the repository, as GitLab only recognizes the files there.
+func (p *userProject) SetCommunityHealthFile(ctx context.Context, file gitprovider.CommunityHealthFile, content string) error {
+	path, actual, err := p.findCommunityHealthFile(ctx, file)
+	if errors.Is(err, gitprovider.ErrNotFound) {
+		path = string(file)
+	} else if err != nil {
+		return err
+	} else if actual == content {
+		// If desired state already is the actual state, do nothing
+		return nil
+	}
+	_, err = p.commits.Create(ctx, p.p.DefaultBranch, fmt.Sprintf("Update %s", path), []gitprovider.CommitFile{
+		{Path: path, Content: &content},
+	})
+	return err
+}
+
+// findCommunityHealthFile returns the path and content of the given community health file.
+func (p *userProject) findCommunityHealthFile(ctx context.Context, file gitprovider.CommunityHealthFile) (string, string, error) {
+	if err := gitprovider.ValidateCommunityHealthFile(file); err != nil {
+		return "", "", fmt.Errorf("community health file %q: %w", file, err)
+	}
+	for _, path := range gitprovider.CommunityHealthFilePaths(file) {
+		// GET /projects/{project}/repository/files/{file_path}/raw
+		content, err := p.c.GetProjectRawFile(ctx, p.p.ID, path, p.p.DefaultBranch)
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return "", "", err
+		}
+		return path, content, nil
+	}
+	return "", "", fmt.Errorf("community health file %q: %w", file, gitprovider.ErrNotFound)
+}
+
+// CommunityHealthScore returns the share of the community health files the project has, as GitLab
+// has no community profile to get a score from.
+func (p *userProject) CommunityHealthScore(ctx context.Context) (gitprovider.CommunityHealthInfo, error) {
+	files := gitprovider.CommunityHealthFiles()
+	info := gitprovider.CommunityHealthInfo{Files: []gitprovider.CommunityHealthFile{}}
+	for _, file := range files {
+		_, _, err := p.findCommunityHealthFile(ctx, file)
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return gitprovider.CommunityHealthInfo{}, err
+		}
+		info.Files = append(info.Files, file)
+	}
+	info.HealthPercentage = len(info.Files) * 100 / len(files)
+	return info, nil
+}
+
+//nolint
+var gitlabNotificationLevelMap = map[gitprovider.SubscriptionLevel]gogitlab.NotificationLevelValue{
+	gitprovider.SubscriptionLevelAll:           gogitlab.WatchNotificationLevel,
+	gitprovider.SubscriptionLevelParticipating: gogitlab.ParticipatingNotificationLevel,
+	gitprovider.SubscriptionLevelIgnore:        gogitlab.DisabledNotificationLevel,
+}
+
 //nolint
 var gitlabVisibilityMap = map[gitprovider.RepositoryVisibility]gogitlab.VisibilityValue{
 	gitprovider.RepositoryVisibilityInternal: gogitlab.InternalVisibility,
@@ -0,0 +1,333 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	gogitlab "github.com/xanzy/go-gitlab"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+func Test_allowForkingFromAPI(t *testing.T) {
+	tests := []struct {
+		name  string
+		level *gogitlab.AccessControlValue
+		want  *bool
+	}{
+		{
+			name:  "enabled",
+			level: gogitlab.AccessControl(gogitlab.EnabledAccessControl),
+			want:  gitprovider.BoolVar(true),
+		},
+		{
+			name:  "private",
+			level: gogitlab.AccessControl(gogitlab.PrivateAccessControl),
+			want:  gitprovider.BoolVar(true),
+		},
+		{
+			name:  "disabled",
+			level: gogitlab.AccessControl(gogitlab.DisabledAccessControl),
+			want:  gitprovider.BoolVar(false),
+		},
+		{
+			name:  "not returned",
+			level: nil,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allowForkingFromAPI(tt.level); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("allowForkingFromAPI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_protectedEnvironmentUpdate(t *testing.T) {
+	actual := &protectedEnvironment{
+		Name: "production",
+		DeployAccessLevels: []*environmentAccessLevel{
+			{ID: 1, AccessLevel: 30},
+			{ID: 2, AccessLevel: 40},
+			{ID: 3, AccessLevel: 40, UserID: 7},
+		},
+	}
+	req := gitprovider.ProtectedEnvironmentInfo{
+		Name:               "production",
+		DeployAccessLevels: []gitprovider.EnvironmentAccessLevel{gitprovider.EnvironmentAccessLevelMaintainer, gitprovider.EnvironmentAccessLevelAdmin},
+	}
+	want := &protectedEnvironment{
+		Name: "production",
+		DeployAccessLevels: []*environmentAccessLevel{
+			{ID: 1, Destroy: true},
+			{AccessLevel: 60},
+		},
+	}
+	if got := protectedEnvironmentUpdate(req, actual); !reflect.DeepEqual(got, want) {
+		t.Errorf("protectedEnvironmentUpdate() = %v, want %v", got, want)
+	}
+
+	info, err := protectedEnvironmentFromAPI(actual)
+	if err != nil {
+		t.Fatalf("protectedEnvironmentFromAPI() error = %v", err)
+	}
+	wantInfo := gitprovider.ProtectedEnvironmentInfo{
+		Name:               "production",
+		DeployAccessLevels: []gitprovider.EnvironmentAccessLevel{gitprovider.EnvironmentAccessLevelDeveloper, gitprovider.EnvironmentAccessLevelMaintainer},
+	}
+	if !reflect.DeepEqual(info, wantInfo) {
+		t.Errorf("protectedEnvironmentFromAPI() = %v, want %v", info, wantInfo)
+	}
+}
+
+func Test_mirrorStatusFromAPI(t *testing.T) {
+	tests := []struct {
+		name   string
+		apiObj *gogitlab.Project
+		want   gitprovider.MirrorStatusInfo
+	}{
+		{
+			name:   "finished, stale error isn't returned",
+			apiObj: &gogitlab.Project{Mirror: true, ImportStatus: "finished", ImportError: "timed out"},
+			want:   gitprovider.MirrorStatusInfo{Status: gitprovider.MirrorSyncStatusFinished},
+		},
+		{
+			name:   "failed authentication against upstream",
+			apiObj: &gogitlab.Project{Mirror: true, ImportStatus: "failed", ImportError: "HTTP Basic: Access denied"},
+			want: gitprovider.MirrorStatusInfo{
+				Status:    gitprovider.MirrorSyncStatusFailed,
+				LastError: "HTTP Basic: Access denied",
+			},
+		},
+		{
+			name:   "no status yet",
+			apiObj: &gogitlab.Project{Mirror: true},
+			want:   gitprovider.MirrorStatusInfo{Status: gitprovider.MirrorSyncStatusNone},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mirrorStatusFromAPI(tt.apiObj); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mirrorStatusFromAPI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_repositoryVisibilityMapping(t *testing.T) {
+	tests := []struct {
+		visibility gitprovider.RepositoryVisibility
+		apiObj     gogitlab.VisibilityValue
+	}{
+		{visibility: gitprovider.RepositoryVisibilityPublic, apiObj: gogitlab.PublicVisibility},
+		{visibility: gitprovider.RepositoryVisibilityInternal, apiObj: gogitlab.InternalVisibility},
+		{visibility: gitprovider.RepositoryVisibilityPrivate, apiObj: gogitlab.PrivateVisibility},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.visibility), func(t *testing.T) {
+			apiObj := &gogitlab.Project{}
+			repositoryInfoToAPIObj(&gitprovider.RepositoryInfo{Visibility: &tt.visibility}, apiObj)
+			if apiObj.Visibility != tt.apiObj {
+				t.Errorf("repositoryInfoToAPIObj() visibility = %q, want %q", apiObj.Visibility, tt.apiObj)
+			}
+			if got := *repositoryFromAPI(apiObj).Visibility; got != tt.visibility {
+				t.Errorf("repositoryFromAPI() visibility = %q, want %q", got, tt.visibility)
+			}
+		})
+	}
+}
+
+// fakePushRuleClient is a gitlabClient serving the push rule of a project, and recording the methods
+// of the requests changing it. A nil rule means the project has no push rule. Calling any other
+// method than the overridden ones panics.
+type fakePushRuleClient struct {
+	gitlabClient
+
+	rule     *gogitlab.ProjectPushRules
+	getErr   error
+	requests []string
+}
+
+func (c *fakePushRuleClient) GetProjectPushRule(_ context.Context, _ int) (*gogitlab.ProjectPushRules, error) {
+	if c.getErr != nil {
+		return nil, c.getErr
+	}
+	return c.rule, nil
+}
+
+func (c *fakePushRuleClient) AddProjectPushRule(_ context.Context, _ int, req *gogitlab.EditProjectPushRuleOptions) (*gogitlab.ProjectPushRules, error) {
+	c.requests = append(c.requests, http.MethodPost)
+	c.rule = &gogitlab.ProjectPushRules{RejectUnsignedCommits: *req.RejectUnsignedCommits}
+	return c.rule, nil
+}
+
+func (c *fakePushRuleClient) EditProjectPushRule(_ context.Context, _ int, req *gogitlab.EditProjectPushRuleOptions) (*gogitlab.ProjectPushRules, error) {
+	c.requests = append(c.requests, http.MethodPut)
+	c.rule.RejectUnsignedCommits = *req.RejectUnsignedCommits
+	return c.rule, nil
+}
+
+func TestUserProject_SetWebCommitSigning(t *testing.T) {
+	tests := []struct {
+		name         string
+		rule         *gogitlab.ProjectPushRules
+		getErr       error
+		enabled      bool
+		wantRequests []string
+		expectedErr  error
+	}{
+		{
+			name:    "no push rule, disabled",
+			enabled: false,
+		},
+		{
+			name:         "no push rule, enabled",
+			enabled:      true,
+			wantRequests: []string{http.MethodPost},
+		},
+		{
+			name:    "no-op when the state is equal",
+			rule:    &gogitlab.ProjectPushRules{RejectUnsignedCommits: true},
+			enabled: true,
+		},
+		{
+			name:         "update a differing state",
+			rule:         &gogitlab.ProjectPushRules{RejectUnsignedCommits: true},
+			enabled:      false,
+			wantRequests: []string{http.MethodPut},
+		},
+		{
+			name:        "push rules not available",
+			getErr:      gitprovider.ErrNotFound,
+			enabled:     true,
+			expectedErr: gitprovider.ErrFeatureNotAvailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakePushRuleClient{rule: tt.rule, getErr: tt.getErr}
+			ref := gitprovider.UserRepositoryRef{
+				UserRef:        gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "foo"},
+				RepositoryName: "bar",
+			}
+			p := newUserProject(&clientContext{c: fake, domain: DefaultDomain}, &gogitlab.Project{ID: 42}, ref)
+
+			err := p.SetWebCommitSigning(context.Background(), tt.enabled)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("SetWebCommitSigning() error = %v, want %v", err, tt.expectedErr)
+			}
+			if !reflect.DeepEqual(fake.requests, tt.wantRequests) {
+				t.Errorf("requests = %v, want %v", fake.requests, tt.wantRequests)
+			}
+			if tt.expectedErr == nil && fake.rule != nil && fake.rule.RejectUnsignedCommits != tt.enabled {
+				t.Errorf("RejectUnsignedCommits = %v, want %v", fake.rule.RejectUnsignedCommits, tt.enabled)
+			}
+		})
+	}
+}
+
+func Test_primaryLanguage(t *testing.T) {
+	tests := []struct {
+		name      string
+		languages map[string]float32
+		want      string
+	}{
+		{
+			name:      "no languages",
+			languages: map[string]float32{},
+			want:      "",
+		},
+		{
+			name:      "largest percentage",
+			languages: map[string]float32{"Go": 80.5, "Shell": 15, "Makefile": 4.5},
+			want:      "Go",
+		},
+		{
+			name:      "tie is broken alphabetically",
+			languages: map[string]float32{"Python": 50, "Go": 50},
+			want:      "Go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := primaryLanguage(tt.languages); got != tt.want {
+				t.Errorf("primaryLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeLanguagesClient is a gitlabClient serving the languages of a project. Calling any other
+// method than the overridden ones panics.
+type fakeLanguagesClient struct {
+	gitlabClient
+
+	languages map[string]float32
+	projects  []string
+}
+
+func (c *fakeLanguagesClient) GetProjectLanguages(_ context.Context, projectName string) (map[string]float32, error) {
+	c.projects = append(c.projects, projectName)
+	return c.languages, nil
+}
+
+func TestUserProject_PrimaryLanguage(t *testing.T) {
+	tests := []struct {
+		name      string
+		languages map[string]float32
+		want      string
+	}{
+		{
+			name:      "no detectable language",
+			languages: map[string]float32{},
+			want:      "",
+		},
+		{
+			name:      "tie is broken alphabetically",
+			languages: map[string]float32{"Ruby": 40, "Python": 40, "Shell": 20},
+			want:      "Python",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeLanguagesClient{languages: tt.languages}
+			ref := gitprovider.OrgRepositoryRef{
+				OrganizationRef: gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "foo"},
+				RepositoryName:  "bar",
+			}
+			p := newUserProject(&clientContext{c: fake, domain: DefaultDomain}, &gogitlab.Project{ID: 42}, ref)
+
+			got, err := p.PrimaryLanguage(context.Background())
+			if err != nil {
+				t.Fatalf("PrimaryLanguage() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("PrimaryLanguage() = %q, want %q", got, tt.want)
+			}
+			if want := []string{"foo/bar"}; !reflect.DeepEqual(fake.projects, want) {
+				t.Errorf("requested projects = %v, want %v", fake.projects, want)
+			}
+		})
+	}
+}
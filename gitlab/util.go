@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
 	"github.com/dinosk/go-git-providers/validation"
+	"github.com/hashicorp/go-retryablehttp"
 	"github.com/xanzy/go-gitlab"
 )
 
@@ -17,10 +19,50 @@ const (
 	masterBranchName         = "master"
 )
 
+// gitlabGroupPath returns the full path of the group ref points to, including its subgroups, e.g.
+// "fluxcd/engineering/frontend". It's the ID to give go-gitlab for "/groups/{id}" paths, which
+// URL-encodes the slashes (as %2F) itself. Hence the path mustn't be encoded here, too.
+func gitlabGroupPath(ref gitprovider.OrganizationRef) string {
+	return ref.GetIdentity()
+}
+
 func getRepoPath(ref gitprovider.RepositoryRef) string {
 	return fmt.Sprintf("%s/%s", ref.GetIdentity(), ref.GetRepository())
 }
 
+// withQueryParam returns a request option that sets the given query parameter, for
+// parameters that go-gitlab doesn't support (yet).
+func withQueryParam(key, value string) gitlab.RequestOptionFunc {
+	return func(req *retryablehttp.Request) error {
+		query := req.URL.Query()
+		query.Set(key, value)
+		req.URL.RawQuery = query.Encode()
+		return nil
+	}
+}
+
+// pageListOptions converts the given PageOptions to go-gitlab ListOptions.
+func pageListOptions(opts gitprovider.PageOptions) gitlab.ListOptions {
+	return gitlab.ListOptions{Page: opts.Page, PerPage: opts.PerPage}
+}
+
+// pageInfoFromResponse normalizes the pagination metadata GitLab gives in the X-Total* headers.
+// GitLab omits the total headers for large collections, in which case the counts are unknown.
+func pageInfoFromResponse(resp *gitlab.Response) gitprovider.PageInfo {
+	info := gitprovider.PageInfo{
+		NextPage:   resp.NextPage,
+		TotalCount: gitprovider.UnknownCount,
+		TotalPages: gitprovider.UnknownCount,
+	}
+	if resp.Response != nil && resp.Header.Get("X-Total") != "" {
+		info.TotalCount = resp.TotalItems
+	}
+	if resp.Response != nil && resp.Header.Get("X-Total-Pages") != "" {
+		info.TotalPages = resp.TotalPages
+	}
+	return info
+}
+
 // allPages runs fn for each page, expecting a HTTP request to be made and returned during that call.
 // allPages expects that the data is saved in fn to an outer variable.
 // allPages calls fn as many times as needed to get all pages, and modifies opts for each call.
@@ -116,6 +158,71 @@ func allDeployKeyPages(opts *gitlab.ListProjectDeployKeysOptions, fn func() (*gi
 	}
 }
 
+func allProtectedEnvironmentPages(opts *gitlab.ListOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return handleHTTPError(err)
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allBranchPages(opts *gitlab.ListBranchesOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return handleHTTPError(err)
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allReleasePages(opts *gitlab.ListReleasesOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return handleHTTPError(err)
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allTagPages(opts *gitlab.ListTagsOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return handleHTTPError(err)
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allIssuePages(opts *gitlab.ListProjectIssuesOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return handleHTTPError(err)
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
 // validateUserRepositoryRef makes sure the UserRepositoryRef is valid for GitHub's usage.
 func validateUserRepositoryRef(ref gitprovider.UserRepositoryRef, expectedDomain string) error {
 	// Make sure the RepositoryRef fields are valid
@@ -136,6 +243,16 @@ func validateOrgRepositoryRef(ref gitprovider.OrgRepositoryRef, expectedDomain s
 	return validateIdentityFields(ref, expectedDomain)
 }
 
+// validateRepositoryRef makes sure the RepositoryRef is valid for GitLab's usage.
+func validateRepositoryRef(ref gitprovider.RepositoryRef, expectedDomain string) error {
+	// Make sure the RepositoryRef fields are valid
+	if err := validation.ValidateTargets("RepositoryRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
 // validateUserRef makes sure the UserRef is valid for GitHub's usage.
 func validateUserRef(ref gitprovider.UserRef, expectedDomain string) error {
 	// Make sure the OrganizationRef fields are valid
@@ -178,6 +295,26 @@ func validateProjectAPI(apiObj *gitlab.Project) error {
 	})
 }
 
+// validateProjectIssueCloseSettingsAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use. GitLab versions before 13.1 don't return the field.
+func validateProjectIssueCloseSettingsAPI(apiObj *projectIssueCloseSettings) error {
+	return validateAPIObject("GitLab.Project", func(validator validation.Validator) {
+		if apiObj.AutocloseReferencedIssues == nil {
+			validator.Required("AutocloseReferencedIssues")
+		}
+	})
+}
+
+// validateProjectEmptyStatusAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateProjectEmptyStatusAPI(apiObj *projectEmptyStatus) error {
+	return validateAPIObject("GitLab.Project", func(validator validation.Validator) {
+		if apiObj.EmptyRepo == nil {
+			validator.Required("EmptyRepo")
+		}
+	})
+}
+
 // validateOrganizationRef makes sure the OrganizationRef is valid for GitHub's usage.
 func validateOrganizationRef(ref gitprovider.OrganizationRef, expectedDomain string) error {
 	// Make sure the OrganizationRef fields are valid
@@ -196,10 +333,9 @@ func validateIdentityFields(ref gitprovider.IdentityRef, expectedDomain string)
 	}
 	// Make sure the right type of identityref is used
 	switch ref.GetType() {
-	case gitprovider.IdentityTypeOrganization, gitprovider.IdentityTypeUser:
+	case gitprovider.IdentityTypeOrganization, gitprovider.IdentityTypeSuborganization, gitprovider.IdentityTypeUser:
+		// Sub-organizations are subgroups in GitLab, see gitlabGroupPath
 		return nil
-	case gitprovider.IdentityTypeSuborganization:
-		return fmt.Errorf("github doesn't support sub-organizations: %w", gitprovider.ErrNoProviderSupport)
 	}
 	return fmt.Errorf("invalid identity type: %v: %w", ref.GetType(), gitprovider.ErrInvalidArgument)
 }
@@ -226,6 +362,10 @@ func handleHTTPError(err error) error {
 				&gitprovider.InvalidCredentialsError{HTTPError: httpErr},
 			)
 		}
+		// Check for rate limit exhaustion
+		if glErrorResponse.Response.StatusCode == http.StatusTooManyRequests {
+			return validation.NewMultiError(err, gitprovider.NewRateLimitError(httpErr, rateLimitHeaders))
+		}
 		// Check for 404 Not Found
 		if glErrorResponse.Response.StatusCode == http.StatusNotFound {
 			return validation.NewMultiError(err, gitprovider.ErrNotFound)
@@ -240,3 +380,22 @@ func handleHTTPError(err error) error {
 	// Do nothing, just pipe through the unknown err
 	return err
 }
+
+// forEachBounded calls fn for each index in [0, n), running at most concurrency calls at once,
+// and returns when all calls have returned.
+func forEachBounded(n, concurrency int, fn func(i int)) {
+	sem := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
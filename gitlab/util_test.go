@@ -17,9 +17,13 @@ limitations under the License.
 package gitlab
 
 import (
+	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/dinosk/go-git-providers/gitprovider"
 	"github.com/dinosk/go-git-providers/validation"
@@ -133,3 +137,27 @@ func Test_allGroupPages(t *testing.T) {
 		})
 	}
 }
+
+func Test_handleHTTPError_rateLimit(t *testing.T) {
+	// Feed a synthetic 429 response to go-gitlab, and check the error it makes of it
+	err := handleHTTPError(gitlab.CheckResponse(&http.Response{
+		Request:    &http.Request{Method: "GET", URL: &url.URL{}},
+		StatusCode: http.StatusTooManyRequests,
+		Header: http.Header{
+			"Ratelimit-Limit":     []string{"600"},
+			"Ratelimit-Remaining": []string{"0"},
+			"Ratelimit-Reset":     []string{"1600000000"},
+		},
+		Body: ioutil.NopCloser(strings.NewReader(`{"message": "Retry later"}`)),
+	}))
+	if !errors.Is(err, gitprovider.ErrRateLimited) {
+		t.Fatalf("errors.Is(%v, ErrRateLimited) = false", err)
+	}
+	rateLimitErr := &gitprovider.RateLimitError{}
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("errors.As(%v, *RateLimitError) = false", err)
+	}
+	if want := time.Unix(1600000000, 0); !rateLimitErr.Reset.Equal(want) || rateLimitErr.Limit != 600 {
+		t.Errorf("RateLimitError = %+v, want Limit 600 and Reset %v", rateLimitErr, want)
+	}
+}
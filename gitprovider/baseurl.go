@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// BaseURL is the normalized location of a self-hosted Git provider instance, see NormalizeBaseURL.
+type BaseURL struct {
+	// Host is the host, and optionally the port, of the instance, e.g. "ghe.example.com:8443".
+	Host string
+	// PathPrefix is the path the instance is installed under, e.g. "/github", or an empty string.
+	PathPrefix string
+}
+
+// Domain returns the host and path prefix of the instance, e.g. "ghe.example.com/github". This is
+// the domain clients for the instance support.
+func (u BaseURL) Domain() string {
+	return u.Host + u.PathPrefix
+}
+
+// URL returns the HTTPS URL of the given absolute path on the instance, e.g. URL("/api/v3/").
+func (u BaseURL) URL(path string) string {
+	return "https://" + u.Host + u.PathPrefix + path
+}
+
+// NormalizeBaseURL parses the domain of a self-hosted Git provider instance, which may also be
+// given as a base URL, e.g. "https://ghe.example.com/github/api/v3/". The first of apiPaths that
+// the path ends with, e.g. "/api/v3", is stripped, and the remaining path is kept as the path
+// prefix the instance is installed under. Only the HTTPS scheme is supported, and may be left out.
+//
+// An error wrapping ErrInvalidClientOptions is returned if str has other parts than a host, port
+// and path, or if the path contains another API path than apiPaths, e.g. of another API version.
+func NormalizeBaseURL(str string, apiPaths ...string) (BaseURL, error) {
+	// Fail-fast if the URL is empty
+	if len(str) == 0 {
+		return BaseURL{}, fmt.Errorf("base URL cannot be empty: %w", ErrInvalidClientOptions)
+	}
+	// A bare domain is the common case, default to HTTPS for it
+	rawURL := str
+	if !strings.Contains(str, "://") {
+		rawURL = "https://" + str
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return BaseURL{}, fmt.Errorf("invalid base URL %q: %v: %w", str, err, ErrInvalidClientOptions)
+	}
+	if u.Scheme != "https" {
+		return BaseURL{}, fmt.Errorf("base URL %q: %v: %w", str, ErrURLUnsupportedScheme, ErrInvalidClientOptions)
+	}
+	if len(u.Hostname()) == 0 || u.User != nil || len(u.RawQuery) != 0 || len(u.Fragment) != 0 {
+		return BaseURL{}, fmt.Errorf("base URL %q: %v: %w", str, ErrURLUnsupportedParts, ErrInvalidClientOptions)
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	for _, apiPath := range apiPaths {
+		if strings.HasSuffix(path, apiPath) {
+			path = strings.TrimSuffix(path, apiPath)
+			break
+		}
+	}
+	// Make sure the path prefix is clean, and doesn't contain an unknown API path
+	if len(path) != 0 {
+		for _, segment := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+			if len(segment) == 0 {
+				return BaseURL{}, fmt.Errorf("base URL %q has an empty path segment: %w", str, ErrInvalidClientOptions)
+			}
+			if segment == "api" {
+				return BaseURL{}, fmt.Errorf("base URL %q has an unsupported API path, expected one of %v: %w", str, apiPaths, ErrInvalidClientOptions)
+			}
+		}
+	}
+	return BaseURL{Host: u.Host, PathPrefix: path}, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"testing"
+
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+func TestNormalizeBaseURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		str          string
+		apiPaths     []string
+		wantDomain   string
+		wantURL      string
+		expectedErrs []error
+	}{
+		{
+			name:       "host",
+			str:        "ghe.example.com",
+			apiPaths:   []string{"/api/v3"},
+			wantDomain: "ghe.example.com",
+			wantURL:    "https://ghe.example.com/api/v3/",
+		},
+		{
+			name:       "host and port",
+			str:        "ghe.example.com:8443",
+			apiPaths:   []string{"/api/v3"},
+			wantDomain: "ghe.example.com:8443",
+			wantURL:    "https://ghe.example.com:8443/api/v3/",
+		},
+		{
+			name:       "API path",
+			str:        "ghe.example.com/api/v3",
+			apiPaths:   []string{"/api/v3"},
+			wantDomain: "ghe.example.com",
+			wantURL:    "https://ghe.example.com/api/v3/",
+		},
+		{
+			name:       "path prefix",
+			str:        "ghe.example.com/github",
+			apiPaths:   []string{"/api/v3"},
+			wantDomain: "ghe.example.com/github",
+			wantURL:    "https://ghe.example.com/github/api/v3/",
+		},
+		{
+			name:       "path prefix and API path",
+			str:        "ghe.example.com/github/api/v3",
+			apiPaths:   []string{"/api/v3"},
+			wantDomain: "ghe.example.com/github",
+			wantURL:    "https://ghe.example.com/github/api/v3/",
+		},
+		{
+			name:       "scheme, path prefix and API path with trailing slash",
+			str:        "https://ghe.example.com/github/api/v3/",
+			apiPaths:   []string{"/api/v3"},
+			wantDomain: "ghe.example.com/github",
+			wantURL:    "https://ghe.example.com/github/api/v3/",
+		},
+		{
+			name:       "second API path",
+			str:        "ghe.example.com/api/uploads",
+			apiPaths:   []string{"/api/v3", "/api/uploads"},
+			wantDomain: "ghe.example.com",
+			wantURL:    "https://ghe.example.com/api/v3/",
+		},
+		{
+			name:         "other API version",
+			str:          "ghe.example.com/github/api/v4",
+			apiPaths:     []string{"/api/v3"},
+			expectedErrs: []error{ErrInvalidClientOptions},
+		},
+		{
+			name:         "empty path segment",
+			str:          "ghe.example.com//github",
+			apiPaths:     []string{"/api/v3"},
+			expectedErrs: []error{ErrInvalidClientOptions},
+		},
+		{
+			name:         "http scheme",
+			str:          "http://ghe.example.com",
+			apiPaths:     []string{"/api/v3"},
+			expectedErrs: []error{ErrInvalidClientOptions},
+		},
+		{
+			name:         "user info",
+			str:          "https://user@ghe.example.com",
+			apiPaths:     []string{"/api/v3"},
+			expectedErrs: []error{ErrInvalidClientOptions},
+		},
+		{
+			name:         "query",
+			str:          "ghe.example.com/api/v3?foo=bar",
+			apiPaths:     []string{"/api/v3"},
+			expectedErrs: []error{ErrInvalidClientOptions},
+		},
+		{
+			name:         "no host",
+			str:          "https:///api/v3",
+			apiPaths:     []string{"/api/v3"},
+			expectedErrs: []error{ErrInvalidClientOptions},
+		},
+		{
+			name:         "empty",
+			str:          "",
+			expectedErrs: []error{ErrInvalidClientOptions},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeBaseURL(tt.str, tt.apiPaths...)
+			validation.TestExpectErrors(t, "NormalizeBaseURL", err, tt.expectedErrs...)
+			if err != nil {
+				return
+			}
+			if got.Domain() != tt.wantDomain {
+				t.Errorf("NormalizeBaseURL().Domain() = %v, want %v", got.Domain(), tt.wantDomain)
+			}
+			if gotURL := got.URL(tt.apiPaths[0] + "/"); gotURL != tt.wantURL {
+				t.Errorf("NormalizeBaseURL().URL() = %v, want %v", gotURL, tt.wantURL)
+			}
+		})
+	}
+}
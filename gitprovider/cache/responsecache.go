@@ -0,0 +1,228 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gregjones/httpcache"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+// WithResponseCache returns a gitprovider.ChainableRoundTripperFunc caching the responses of GET
+// requests for ttl, in order to not hit the API for data that rarely changes, e.g. when
+// reconciling repeatedly. Responses are cached per method, URL and credentials, hence the
+// transport should be registered after the authentication in the chain, i.e. as the
+// PostChainTransportHook. Only "200 OK" responses are cached.
+//
+// Once the ttl expired, the cached response is revalidated using its ETag, if any, through the
+// If-None-Match header. A "304 Not Modified" response serves the cached response again, and
+// refreshes its ttl. Requests with the "Cache-Control: no-cache" header are always revalidated
+// this way, or sent as-is if the response has no ETag. Any other request than GET invalidates
+// the cached responses of its URL regardless of their query, as it might modify the resource, e.g.
+// creating a repository with a POST invalidates the cached pages of the listed repositories.
+//
+// At most maxEntries responses are cached, evicting the least recently used one if needed. If
+// maxEntries is zero or negative, the amount of cached responses is not limited. Responses
+// served from the cache have the httpcache.XFromCache header set.
+//
+// The returned transport is safe for concurrent use.
+func WithResponseCache(ttl time.Duration, maxEntries int) gitprovider.ChainableRoundTripperFunc {
+	return func(in http.RoundTripper) http.RoundTripper {
+		if in == nil {
+			in = http.DefaultTransport
+		}
+		return &responseCache{
+			next:       in,
+			ttl:        ttl,
+			maxEntries: maxEntries,
+			entries:    map[string]*list.Element{},
+			lru:        list.New(),
+			now:        time.Now,
+		}
+	}
+}
+
+type responseCache struct {
+	next       http.RoundTripper
+	ttl        time.Duration
+	maxEntries int
+
+	// mu guards entries and lru. The elements of lru are *cachedResponse, ordered from the most to
+	// the least recently used one, and entries maps their keys to them.
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	// now returns the current time. It can be replaced in unit tests.
+	now func() time.Time
+}
+
+// cachedResponse is a "200 OK" response, with its body read into memory.
+type cachedResponse struct {
+	key string
+	// resource is the URL of the response without its query, see resourceURL.
+	resource string
+	header   http.Header
+	body     []byte
+	etag     string
+	expires  time.Time
+}
+
+// response returns a new *http.Response for req, serving the cached response.
+func (e *cachedResponse) response(req *http.Request) *http.Response {
+	header := e.header.Clone()
+	header.Set(httpcache.XFromCache, "1")
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// responseCacheKey returns the key of the cached response of req. The credentials are hashed, in
+// order to not keep them in memory.
+func responseCacheKey(req *http.Request) string {
+	// GitLab's personal access tokens are sent in the Private-Token header
+	credentials := req.Header.Get("Authorization") + "\n" + req.Header.Get("Private-Token")
+	hash := sha256.Sum256([]byte(credentials))
+	return req.Method + " " + req.URL.String() + " " + hex.EncodeToString(hash[:])
+}
+
+// resourceURL returns the scheme, host and path of u, i.e. u without its query and fragment.
+// The responses of a resource are invalidated through it, regardless of e.g. the requested page.
+func resourceURL(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + u.EscapedPath()
+}
+
+// RoundTrip serves GET requests from the cache if possible, or revalidates the cached response.
+// Any other request invalidates the cached responses of its URL, ignoring the query.
+func (c *responseCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		c.invalidate(resourceURL(req.URL))
+		return c.next.RoundTrip(req)
+	}
+
+	key := responseCacheKey(req)
+	noCache := strings.Contains(strings.ToLower(req.Header.Get("Cache-Control")), "no-cache")
+	c.mu.Lock()
+	var entry *cachedResponse
+	if elem, ok := c.entries[key]; ok {
+		entry = elem.Value.(*cachedResponse)
+		if !noCache && c.now().Before(entry.expires) {
+			c.lru.MoveToFront(elem)
+			c.mu.Unlock()
+			return entry.response(req), nil
+		}
+	}
+	c.mu.Unlock()
+
+	// Revalidate the cached response, unless the caller sends a conditional request itself
+	revalidate := entry != nil && entry.etag != "" && req.Header.Get("If-None-Match") == ""
+	outReq := req
+	if revalidate {
+		outReq = req.Clone(req.Context())
+		outReq.Header.Set("If-None-Match", entry.etag)
+	}
+	resp, err := c.next.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case revalidate && resp.StatusCode == http.StatusNotModified:
+		resp.Body.Close()
+		c.mu.Lock()
+		entry.expires = c.now().Add(c.ttl)
+		c.store(entry)
+		c.mu.Unlock()
+		return entry.response(req), nil
+	case resp.StatusCode == http.StatusOK:
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		c.mu.Lock()
+		c.store(&cachedResponse{
+			key:      key,
+			resource: resourceURL(req.URL),
+			header:   resp.Header.Clone(),
+			body:     body,
+			etag:     resp.Header.Get("ETag"),
+			expires:  c.now().Add(c.ttl),
+		})
+		c.mu.Unlock()
+		return resp, nil
+	default:
+		// Don't keep serving a response the server doesn't return anymore
+		c.mu.Lock()
+		if elem, ok := c.entries[key]; ok {
+			c.remove(elem)
+		}
+		c.mu.Unlock()
+		return resp, nil
+	}
+}
+
+// store adds or replaces the entry as the most recently used one, evicting the least recently used
+// entries if there are more than maxEntries. c.mu must be held.
+func (c *responseCache) store(entry *cachedResponse) {
+	if elem, ok := c.entries[entry.key]; ok {
+		c.remove(elem)
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove removes elem from the cache. c.mu must be held.
+func (c *responseCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cachedResponse).key)
+}
+
+// invalidate removes the cached responses of resource, see resourceURL, regardless of the
+// credentials.
+func (c *responseCache) invalidate(resource string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if elem.Value.(*cachedResponse).resource == resource {
+			c.remove(elem)
+		}
+		elem = next
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gregjones/httpcache"
+)
+
+// fakeServer is a http.RoundTripper serving body with the given ETag, and "304 Not Modified"
+// if the request's If-None-Match header matches it.
+type fakeServer struct {
+	body     string
+	etag     string
+	requests []*http.Request
+}
+
+func (s *fakeServer) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.requests = append(s.requests, req)
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader(s.body))}
+	if s.etag != "" {
+		resp.Header.Set("ETag", s.etag)
+		if req.Header.Get("If-None-Match") == s.etag {
+			resp.StatusCode = http.StatusNotModified
+			resp.Body = ioutil.NopCloser(strings.NewReader(""))
+		}
+	}
+	return resp, nil
+}
+
+func newTestCache(server *fakeServer, ttl time.Duration, maxEntries int) (*responseCache, *time.Time) {
+	c := WithResponseCache(ttl, maxEntries)(server).(*responseCache)
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func doRequest(t *testing.T, rt http.RoundTripper, method, url, token string, header http.Header) (string, bool) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("RoundTrip() status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	return string(body), resp.Header.Get(httpcache.XFromCache) != ""
+}
+
+const testURL = "https://api.github.com/repos/foo/bar"
+
+func TestWithResponseCache_hits(t *testing.T) {
+	server := &fakeServer{body: "v1"}
+	c, _ := newTestCache(server, time.Minute, 0)
+
+	if body, cached := doRequest(t, c, http.MethodGet, testURL, "a", nil); body != "v1" || cached {
+		t.Errorf("first GET = %q, %v, want \"v1\", false", body, cached)
+	}
+	server.body = "v2"
+	if body, cached := doRequest(t, c, http.MethodGet, testURL, "a", nil); body != "v1" || !cached {
+		t.Errorf("second GET = %q, %v, want \"v1\", true", body, cached)
+	}
+	// Responses aren't shared between credentials
+	if body, cached := doRequest(t, c, http.MethodGet, testURL, "b", nil); body != "v2" || cached {
+		t.Errorf("GET with other credentials = %q, %v, want \"v2\", false", body, cached)
+	}
+	// no-cache bypasses the cache
+	noCache := http.Header{"Cache-Control": []string{"no-cache"}}
+	if body, cached := doRequest(t, c, http.MethodGet, testURL, "a", noCache); body != "v2" || cached {
+		t.Errorf("GET with no-cache = %q, %v, want \"v2\", false", body, cached)
+	}
+	// Other requests invalidate the cached responses of the URL
+	server.body = "v3"
+	doRequest(t, c, http.MethodPatch, testURL, "a", nil)
+	if body, cached := doRequest(t, c, http.MethodGet, testURL, "b", nil); body != "v3" || cached {
+		t.Errorf("GET after PATCH = %q, %v, want \"v3\", false", body, cached)
+	}
+	if len(server.requests) != 5 {
+		t.Errorf("server got %d requests, want 5", len(server.requests))
+	}
+}
+
+func TestWithResponseCache_invalidateQuery(t *testing.T) {
+	const listURL = "https://api.github.com/orgs/foo/repos"
+	server := &fakeServer{body: "[bar]"}
+	c, _ := newTestCache(server, time.Minute, 0)
+
+	doRequest(t, c, http.MethodGet, listURL+"?per_page=100&page=1", "a", nil)
+	if _, cached := doRequest(t, c, http.MethodGet, listURL+"?per_page=100&page=1", "a", nil); !cached {
+		t.Error("second list wasn't served from the cache")
+	}
+	// Creating a repository invalidates the cached list, regardless of its query
+	server.body = "[bar baz]"
+	doRequest(t, c, http.MethodPost, listURL, "a", nil)
+	if body, cached := doRequest(t, c, http.MethodGet, listURL+"?per_page=100&page=1", "a", nil); body != "[bar baz]" || cached {
+		t.Errorf("list after create = %q, %v, want \"[bar baz]\", false", body, cached)
+	}
+}
+
+func TestWithResponseCache_expiry(t *testing.T) {
+	server := &fakeServer{body: "v1"}
+	c, now := newTestCache(server, time.Minute, 0)
+
+	doRequest(t, c, http.MethodGet, testURL, "a", nil)
+	*now = now.Add(59 * time.Second)
+	if _, cached := doRequest(t, c, http.MethodGet, testURL, "a", nil); !cached {
+		t.Error("GET before expiry wasn't served from the cache")
+	}
+	server.body = "v2"
+	*now = now.Add(time.Second)
+	if body, cached := doRequest(t, c, http.MethodGet, testURL, "a", nil); body != "v2" || cached {
+		t.Errorf("GET after expiry = %q, %v, want \"v2\", false", body, cached)
+	}
+	// Without an ETag, the expired response isn't revalidated
+	if got := server.requests[1].Header.Get("If-None-Match"); got != "" {
+		t.Errorf("If-None-Match = %q, want none", got)
+	}
+}
+
+func TestWithResponseCache_notModified(t *testing.T) {
+	server := &fakeServer{body: "v1", etag: `"abc"`}
+	c, now := newTestCache(server, time.Minute, 0)
+
+	doRequest(t, c, http.MethodGet, testURL, "a", nil)
+	// The expired response is revalidated, and served again on "304 Not Modified"
+	*now = now.Add(time.Minute)
+	if body, cached := doRequest(t, c, http.MethodGet, testURL, "a", nil); body != "v1" || !cached {
+		t.Errorf("revalidated GET = %q, %v, want \"v1\", true", body, cached)
+	}
+	if got := server.requests[1].Header.Get("If-None-Match"); got != `"abc"` {
+		t.Errorf("If-None-Match = %q, want %q", got, `"abc"`)
+	}
+	// Which refreshes the ttl
+	*now = now.Add(59 * time.Second)
+	if _, cached := doRequest(t, c, http.MethodGet, testURL, "a", nil); !cached {
+		t.Error("GET after revalidation wasn't served from the cache")
+	}
+	// Once the resource changed, the new response is served
+	server.body, server.etag = "v2", `"def"`
+	*now = now.Add(time.Minute)
+	if body, cached := doRequest(t, c, http.MethodGet, testURL, "a", nil); body != "v2" || cached {
+		t.Errorf("GET after change = %q, %v, want \"v2\", false", body, cached)
+	}
+	if len(server.requests) != 3 {
+		t.Errorf("server got %d requests, want 3", len(server.requests))
+	}
+}
+
+func TestWithResponseCache_eviction(t *testing.T) {
+	server := &fakeServer{body: "v1"}
+	c, _ := newTestCache(server, time.Minute, 2)
+
+	doRequest(t, c, http.MethodGet, testURL+"/1", "a", nil)
+	doRequest(t, c, http.MethodGet, testURL+"/2", "a", nil)
+	// Using the first response makes the second one the least recently used
+	doRequest(t, c, http.MethodGet, testURL+"/1", "a", nil)
+	doRequest(t, c, http.MethodGet, testURL+"/3", "a", nil)
+
+	for _, tt := range []struct {
+		url    string
+		cached bool
+	}{
+		{url: testURL + "/1", cached: true},
+		{url: testURL + "/3", cached: true},
+		{url: testURL + "/2", cached: false},
+	} {
+		if _, cached := doRequest(t, c, http.MethodGet, tt.url, "a", nil); cached != tt.cached {
+			t.Errorf("GET %s cached = %v, want %v", tt.url, cached, tt.cached)
+		}
+	}
+}
@@ -89,6 +89,28 @@ type OrgRepositoriesClient interface {
 	// List returns all available repositories, using multiple paginated requests if needed.
 	List(ctx context.Context, o OrganizationRef) ([]OrgRepository, error)
 
+	// ListPage lists one page of repositories in the given organization.
+	//
+	// ListPage returns the pagination metadata supplied by the provider along with the page.
+	ListPage(ctx context.Context, o OrganizationRef, opts PageOptions) ([]OrgRepository, PageInfo, error)
+
+	// ListSorted lists one page of repositories in the given organization, sorted server-side
+	// according to sortOpts.
+	//
+	// An error wrapping ErrNoProviderSupport is returned if the provider can't sort by the given key.
+	ListSorted(ctx context.Context, o OrganizationRef, sortOpts RepositorySortOptions, opts PageOptions) ([]OrgRepository, PageInfo, error)
+
+	// ListRepositoryRefs lists references to the repositories in the given organization that
+	// match the filter, without returning the full repository resources.
+	//
+	// ListRepositoryRefs returns all matching references, using multiple paginated requests if needed.
+	ListRepositoryRefs(ctx context.Context, o OrganizationRef, filter RefListFilter) ([]RepositoryRef, error)
+
+	// ListRepositories lists the repositories in the given organization that match opts.
+	//
+	// ListRepositories returns all matching repositories, using multiple paginated requests if needed.
+	ListRepositories(ctx context.Context, o OrganizationRef, opts RepositoryListOptions) ([]OrgRepository, error)
+
 	// Create creates a repository for the given organization, with the data and options.
 	//
 	// ErrAlreadyExists will be returned if the resource already exists.
@@ -100,6 +122,31 @@ type OrgRepositoriesClient interface {
 	// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
 	// If req is already the actual state, this is a no-op (actionTaken == false).
 	Reconcile(ctx context.Context, r OrgRepositoryRef, req RepositoryInfo, opts ...RepositoryReconcileOption) (resp OrgRepository, actionTaken bool, err error)
+
+	// ReconcileList makes sure the repositories of the given organization are the desired ones, matched
+	// by name. Missing repositories are created, and drifted ones updated, see Reconcile. If opts.Prune is
+	// set, the repositories that aren't desired, and whose name starts with opts.NamePrefix, are deleted.
+	// Deleting repositories requires destructive actions to be enabled in the client, otherwise
+	// ErrDestructiveCallDisallowed is returned before any change. If opts.DryRun is set, nothing is changed,
+	// hence the deletions are reported even if destructive actions are disabled.
+	//
+	// The action taken (or, in a dry run, that would be taken) for each repository is returned, also when
+	// an error occurred midway.
+	ReconcileList(ctx context.Context, o OrganizationRef, desired []RepositoryReconcileRequest, opts RepositoryReconcileListOptions) ([]RepositoryReconcileResult, error)
+
+	// ForkRepository forks the upstream repository into the organization given in opts, and waits until
+	// the fork is created, at most opts.Timeout.
+	//
+	// ErrAlreadyExists is returned if a repository with the name of the fork already exists.
+	ForkRepository(ctx context.Context, upstream RepositoryRef, opts ForkOptions) (OrgRepository, error)
+
+	// TransferRepository transfers the repository to newOwner, a user or organization, and waits until
+	// it's available there, at most opts.Timeout. The repository keeps its name. If newOwner is an
+	// organization, the returned repository is an OrgRepository. This requires destructive actions to
+	// be enabled in the client, otherwise ErrDestructiveCallDisallowed is returned before any request.
+	//
+	// ErrNotFound is returned if the repository does not exist.
+	TransferRepository(ctx context.Context, repo RepositoryRef, newOwner IdentityRef, opts TransferOptions) (UserRepository, error)
 }
 
 // UserRepositoriesClient operates on repositories for users.
@@ -114,6 +161,11 @@ type UserRepositoriesClient interface {
 	// List returns all available repositories, using multiple paginated requests if needed.
 	List(ctx context.Context, o UserRef) ([]UserRepository, error)
 
+	// ListPage lists one page of repositories for the given user.
+	//
+	// ListPage returns the pagination metadata supplied by the provider along with the page.
+	ListPage(ctx context.Context, o UserRef, opts PageOptions) ([]UserRepository, PageInfo, error)
+
 	// Create creates a repository for the given user, with the data and options
 	//
 	// ErrAlreadyExists will be returned if the resource already exists.
@@ -125,6 +177,12 @@ type UserRepositoriesClient interface {
 	// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
 	// If req is already the actual state, this is a no-op (actionTaken == false).
 	Reconcile(ctx context.Context, r UserRepositoryRef, req RepositoryInfo, opts ...RepositoryReconcileOption) (resp UserRepository, actionTaken bool, err error)
+
+	// GetAuthenticatedUser returns the login and domain of the user the client is authenticated as,
+	// e.g. to pick a default owner for repositories.
+	//
+	// An *InvalidCredentialsError is returned if the client isn't authenticated, or the token is invalid.
+	GetAuthenticatedUser(ctx context.Context) (UserInfo, error)
 }
 
 //
@@ -150,6 +208,34 @@ type TeamsClient interface {
 	// Possibly add Create/Update/Delete methods later
 }
 
+// OrgActionsSecretsClient operates on the organization-wide CI secrets of a specific organization.
+// This client can be accessed through Organization.ActionsSecrets().
+//
+// This is not supported in GitLab, use group CI variables there.
+type OrgActionsSecretsClient interface {
+	// List lists all secrets in the organization. The secret values are never returned.
+	//
+	// List returns all available secrets, using multiple paginated requests if needed.
+	List(ctx context.Context) ([]ActionsSecretInfo, error)
+
+	// Set creates or updates the given secret. The value is sealed with the organization's
+	// public key before it is sent to the server.
+	Set(ctx context.Context, req ActionsSecretInfo) error
+
+	// Delete deletes the secret with the given name.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Delete(ctx context.Context, name string) error
+
+	// ListSelectedRepositories lists the names of the repositories that can access a secret
+	// with the "selected" visibility.
+	ListSelectedRepositories(ctx context.Context, name string) ([]string, error)
+
+	// SetSelectedRepositories replaces the list of repositories that can access a secret
+	// with the "selected" visibility.
+	SetSelectedRepositories(ctx context.Context, name string, repoNames []string) error
+}
+
 // TeamAccessClient operates on the teams list for a specific repository.
 // This client can be accessed through Repository.TeamAccess().
 type TeamAccessClient interface {
@@ -190,6 +276,11 @@ type DeployKeyClient interface {
 	// using multiple paginated requests if needed.
 	List(ctx context.Context) ([]DeployKey, error)
 
+	// ListReadWriteKeys lists the deploy keys of the repository that can write to it, i.e. the
+	// ones whose ReadOnly field is false. The Git providers can't filter deploy keys server-side,
+	// hence this is a client-side filter over the full list returned by List.
+	ListReadWriteKeys(ctx context.Context) ([]DeployKey, error)
+
 	// Create a deploy key with the given specifications.
 	//
 	// ErrAlreadyExists will be returned if the resource already exists.
@@ -201,4 +292,225 @@ type DeployKeyClient interface {
 	// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
 	// If req is already the actual state, this is a no-op (actionTaken == false).
 	Reconcile(ctx context.Context, req DeployKeyInfo) (resp DeployKey, actionTaken bool, err error)
+
+	// ReconcileList makes sure the deploy keys of the repository are exactly the desired ones, matched
+	// by their public key material (see DeployKeyInfo.HasSameKey). Missing keys are created, matching
+	// keys are left as-is, and the other keys are deleted. Deleting keys requires destructive actions to
+	// be enabled in the client, otherwise ErrDestructiveCallDisallowed is returned before any change.
+	//
+	// The names of the added and removed keys are returned, also when an error occurred midway.
+	ReconcileList(ctx context.Context, desired []DeployKeyInfo) (added, removed []string, err error)
+
+	// ReconcileAll makes sure the deploy keys of the repository are exactly the desired ones, matched
+	// by name. Missing keys are created, keys whose public key material or access level changed are
+	// rotated, i.e. deleted and recreated, and the keys that aren't desired are deleted. Rotating and
+	// deleting keys requires destructive actions to be enabled in the client, otherwise
+	// ErrDestructiveCallDisallowed is returned before any change.
+	//
+	// changed is true if any key was created, rotated or deleted, also when an error occurred midway.
+	ReconcileAll(ctx context.Context, desired []DeployKeyInfo) (changed bool, err error)
+
+	// RotateAll replaces each deploy key of the repository with a new key pair from generate, keeping its
+	// name and access level. Each key is rotated on its own, and the new key is created before the old one
+	// is deleted, so a failure never leaves a key without either the old or the new key pair working.
+	// Deleting keys requires destructive actions to be enabled in the client, otherwise
+	// ErrDestructiveCallDisallowed is returned before any change.
+	//
+	// The private keys of the new key pairs are returned by key name, hence key names should be unique.
+	// If some keys failed to rotate, a *validation.MultiError with an error per key is returned too.
+	RotateAll(ctx context.Context, generate func() (pub, priv []byte, err error)) (map[string][]byte, error)
+
+	// EnableDeployKeyForProject enables an existing deploy key of this repository, identified by its
+	// ID, for the repository referenced by ref. This allows one key to access several repositories.
+	// If the key is already enabled for the given repository, this is a no-op.
+	//
+	// ErrNotFound is returned if this repository has no deploy key with the given ID.
+	//
+	// This is not supported in GitHub.
+	EnableDeployKeyForProject(ctx context.Context, keyID int, ref RepositoryRef) error
+}
+
+// RepositoryHookClient operates on the webhooks of a specific repository.
+// This client can be accessed through Repository.Hooks().
+type RepositoryHookClient interface {
+	// Get a webhook by the URL it delivers to.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Get(ctx context.Context, url string) (RepositoryHook, error)
+
+	// List all webhooks of the repository.
+	//
+	// List returns all available webhooks, using multiple paginated requests if needed.
+	List(ctx context.Context) ([]RepositoryHook, error)
+
+	// Create a webhook with the given specifications. If opts.VerifyURL is set, the webhook is only
+	// created if its URL is reachable, see VerifyHookURL.
+	//
+	// ErrAlreadyExists will be returned if the resource already exists.
+	Create(ctx context.Context, req RepositoryHookInfo, opts RepositoryHookCreateOptions) (RepositoryHook, error)
+
+	// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+	// The secret isn't compared, as it can't be read; it is only set when the webhook is created or updated.
+	//
+	// If req doesn't exist under the hood, it is created (actionTaken == true).
+	// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+	// If req is already the actual state, this is a no-op (actionTaken == false).
+	Reconcile(ctx context.Context, req RepositoryHookInfo) (resp RepositoryHook, actionTaken bool, err error)
+}
+
+// BranchProtectionClient operates on the branch protection rules of a specific repository.
+// This client can be accessed through Repository.BranchProtection().
+type BranchProtectionClient interface {
+	// Get the protection rules of the given branch.
+	//
+	// ErrNotFound is returned if the branch isn't protected, or doesn't exist.
+	Get(ctx context.Context, branch string) (BranchProtection, error)
+
+	// Create protects a branch with the given rules.
+	//
+	// ErrAlreadyExists will be returned if the branch already is protected.
+	Create(ctx context.Context, req BranchProtectionInfo) (BranchProtection, error)
+
+	// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+	// The protection is looked up by the name of its branch, and the rules are compared field by field.
+	//
+	// If req doesn't exist under the hood, it is created (actionTaken == true).
+	// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+	// If req is already the actual state, this is a no-op (actionTaken == false).
+	Reconcile(ctx context.Context, req BranchProtectionInfo) (resp BranchProtection, actionTaken bool, err error)
+}
+
+// RulesetClient operates on the rulesets of a specific repository.
+// This client can be accessed through Repository.Rulesets().
+type RulesetClient interface {
+	// Get a ruleset by its name.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Get(ctx context.Context, name string) (Ruleset, error)
+
+	// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+	// The ruleset is looked up by its name. Rules of the ruleset that aren't modelled in RulesetInfo are kept.
+	//
+	// If req doesn't exist under the hood, it is created (actionTaken == true).
+	// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+	// If req is already the actual state, this is a no-op (actionTaken == false).
+	Reconcile(ctx context.Context, req RulesetInfo) (resp Ruleset, actionTaken bool, err error)
+}
+
+// ReleaseClient operates on the releases of a specific repository.
+// This client can be accessed through Repository.Releases().
+type ReleaseClient interface {
+	// LatestRelease returns the most recently released release of the repository. Drafts and
+	// prereleases are never considered the latest release.
+	//
+	// ErrNotFound is returned if the repository has no (published) releases.
+	LatestRelease(ctx context.Context) (ReleaseInfo, error)
+
+	// Get returns the release made from the given tag.
+	//
+	// ErrNotFound is returned if the tag has no release, or doesn't exist.
+	Get(ctx context.Context, tag string) (Release, error)
+
+	// List lists all releases of the repository, newest first.
+	//
+	// List returns all available releases, using multiple paginated requests if needed.
+	List(ctx context.Context) ([]Release, error)
+
+	// Create creates a release with the given specifications. The tag is created from
+	// req.TargetCommitish if it doesn't exist yet.
+	//
+	// ErrAlreadyExists will be returned if the tag already has a release.
+	Create(ctx context.Context, req ReleaseInfo) (Release, error)
+}
+
+// CommitClient operates on the commits of a specific repository.
+// This client can be accessed through Repository.Commits().
+type CommitClient interface {
+	// ListPage lists the commits of the given branch, newest first, in pages of perPage commits.
+	// Page indexes are 1-based. The verification status of the commits is populated if
+	// opts.IncludeVerification is set, or if the provider returns it along with the commits anyway.
+	//
+	// ErrNotFound is returned if the branch does not exist.
+	ListPage(ctx context.Context, branch string, perPage, page int, opts CommitListOptions) ([]Commit, error)
+
+	// Create creates a commit on top of the given branch, changing the given files, and moves the
+	// branch to it. Files with nil content are deleted.
+	//
+	// ErrNotFound is returned if the branch does not exist.
+	Create(ctx context.Context, branch string, message string, files []CommitFile) (Commit, error)
+
+	// CommitCount returns the amount of commits reachable from the given branch, without listing
+	// the commits. An empty branch means the default branch of the repository.
+	// UnknownCount (-1) is returned if the count can't be determined without listing all commits.
+	//
+	// In GitHub, the count is approximate, as it's derived from the pagination metadata of the
+	// commit list. In GitLab, the count is only known for the default branch.
+	CommitCount(ctx context.Context, branch string) (int64, error)
+}
+
+// TagClient operates on the tags of a specific repository.
+// This client can be accessed through Repository.Tags().
+type TagClient interface {
+	// List lists all tags of the repository, along with the commits they point to.
+	//
+	// List returns all available tags, using multiple paginated requests if needed.
+	List(ctx context.Context) ([]Tag, error)
+
+	// Create creates a lightweight tag with the given name, pointing at the commit sha.
+	//
+	// ErrAlreadyExists is returned if the tag already exists.
+	// ErrNotFound is returned if sha isn't a commit of the repository.
+	Create(ctx context.Context, name, sha string) error
+}
+
+// IssueClient operates on the issues of a specific repository.
+// This client can be accessed through Repository.Issues().
+type IssueClient interface {
+	// Create creates an open issue with the given title, body and labels.
+	// The title must not be empty.
+	Create(ctx context.Context, title, body string, labels []string) (Issue, error)
+
+	// List lists the issues of the repository, newest first, optionally filtered by state and label.
+	// Pull requests (or merge requests) are never included.
+	//
+	// List returns all available issues, using multiple paginated requests if needed.
+	List(ctx context.Context, opts IssueListOptions) ([]Issue, error)
+}
+
+// BranchClient operates on the branches of a specific repository.
+// This client can be accessed through Repository.Branches().
+type BranchClient interface {
+	// Get a branch by its name.
+	//
+	// ErrNotFound is returned if the branch does not exist.
+	Get(ctx context.Context, name string) (Branch, error)
+
+	// List lists all branches of the repository, along with whether they are protected.
+	//
+	// List returns all available branches, using multiple paginated requests if needed.
+	List(ctx context.Context) ([]Branch, error)
+
+	// Create creates a branch with the given name, pointing at the commit fromSHA. If fromSHA is
+	// empty, the branch points at the head of the default branch of the repository.
+	//
+	// ErrAlreadyExists is returned if the branch already exists.
+	// ErrNotFound is returned if fromSHA isn't a commit of the repository.
+	Create(ctx context.Context, branchName, fromSHA string) error
+
+	// IsBranchMerged returns true if all commits of the given branch are part of the default branch
+	// of the repository, i.e. the branch can be deleted without losing work. A branch without own
+	// commits, e.g. one that was just created, is merged.
+	//
+	// ErrNotFound is returned if the branch does not exist.
+	IsBranchMerged(ctx context.Context, branch string) (bool, error)
+
+	// DeleteMergedBranches deletes the merged branches (see IsBranchMerged) whose last commit is older
+	// than opts.MinAge. The default branch and protected branches are never deleted. Up to
+	// opts.Concurrency branches are checked and deleted at once. This requires destructive actions to
+	// be enabled in the client, otherwise ErrDestructiveCallDisallowed is returned before any change.
+	//
+	// The names of the deleted branches are returned, along with the reason why each other branch was
+	// skipped. A branch that failed to be checked or deleted doesn't stop the others; if any failed,
+	// a *validation.MultiError with an error per branch is returned too.
+	DeleteMergedBranches(ctx context.Context, opts DeleteMergedBranchesOptions) (deleted []string, skipped map[string]BranchSkipReason, err error)
 }
@@ -19,6 +19,7 @@ package gitprovider
 import (
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // ChainableRoundTripperFunc is a function that returns a higher-level "out" RoundTripper,
@@ -52,6 +53,37 @@ type CommonClientOptions struct {
 	// The "chain" looks like follows:
 	// Git provider API (in==nil) <-> "Post Chain" (out) <-> Provider Specific (e.g. auth, caching) <-> "Pre Chain" <-> *http.Client
 	PostChainTransportHook ChainableRoundTripperFunc
+
+	// DefaultOrganization specifies the organization to use for repository operations given a ref
+	// without an organization, i.e. only a repository name. The domain of the organization must match
+	// the domain of the client. Default: nil (which means the organization is always required)
+	DefaultOrganization *OrganizationRef
+
+	// RetryBudget limits the amount of retries across all requests of the client, see RetryBudget.
+	// Default: nil (which means retries are only limited per request)
+	RetryBudget *RetryBudget
+
+	// RetryableStatusCodes specifies the status codes of responses that are retried, instead of the
+	// provider's default set (e.g. 429 Too Many Requests and 5xx). The codes must be in the range 100-599.
+	// Default: nil (which means the provider's default set is used)
+	RetryableStatusCodes []int
+
+	// RateLimitHandler is called with the rate limit reported in every response of the provider,
+	// see RateLimitTransport. Default: nil (which means the rate limit isn't reported)
+	RateLimitHandler func(RateLimitInfo)
+
+	// BlockOnRateLimit specifies whether requests wait until the rate limit resets, once the provider
+	// reported that no requests remain, see RateLimitTransport. Default: false
+	BlockOnRateLimit *bool
+
+	// RequestTimeout limits the duration of every single request, including reading the response
+	// body, see RequestTimeoutTransport. A shorter deadline of the caller's context is kept. The
+	// duration must be positive. Default: nil (which means only the caller's context applies)
+	RequestTimeout *time.Duration
+
+	// UserAgent is set as the User-Agent header of every request, see UserAgentTransport. It must not
+	// be an empty string. Default: nil (which means the provider's Go client sets its own user agent)
+	UserAgent *string
 }
 
 // ApplyToCommonClientOptions applies the currently set fields in opts to target. If both opts and
@@ -92,9 +124,119 @@ func (opts *CommonClientOptions) ApplyToCommonClientOptions(target *CommonClient
 		}
 		target.PostChainTransportHook = opts.PostChainTransportHook
 	}
+
+	if opts.DefaultOrganization != nil {
+		// Make sure the user didn't specify the DefaultOrganization twice
+		if target.DefaultOrganization != nil {
+			return fmt.Errorf("option DefaultOrganization already configured: %w", ErrInvalidClientOptions)
+		}
+		// Don't allow an empty organization
+		if len(opts.DefaultOrganization.Organization) == 0 {
+			return fmt.Errorf("option DefaultOrganization must specify an organization: %w", ErrInvalidClientOptions)
+		}
+		target.DefaultOrganization = opts.DefaultOrganization
+	}
+
+	if opts.RetryBudget != nil {
+		// Make sure the user didn't specify the RetryBudget twice
+		if target.RetryBudget != nil {
+			return fmt.Errorf("option RetryBudget already configured: %w", ErrInvalidClientOptions)
+		}
+		target.RetryBudget = opts.RetryBudget
+	}
+
+	if opts.RetryableStatusCodes != nil {
+		// Make sure the user didn't specify the RetryableStatusCodes twice
+		if target.RetryableStatusCodes != nil {
+			return fmt.Errorf("option RetryableStatusCodes already configured: %w", ErrInvalidClientOptions)
+		}
+		target.RetryableStatusCodes = opts.RetryableStatusCodes
+	}
+
+	if opts.RateLimitHandler != nil {
+		// Make sure the user didn't specify the RateLimitHandler twice
+		if target.RateLimitHandler != nil {
+			return fmt.Errorf("option RateLimitHandler already configured: %w", ErrInvalidClientOptions)
+		}
+		target.RateLimitHandler = opts.RateLimitHandler
+	}
+
+	if opts.BlockOnRateLimit != nil {
+		// Make sure the user didn't specify the BlockOnRateLimit twice
+		if target.BlockOnRateLimit != nil {
+			return fmt.Errorf("option BlockOnRateLimit already configured: %w", ErrInvalidClientOptions)
+		}
+		target.BlockOnRateLimit = opts.BlockOnRateLimit
+	}
+
+	if opts.RequestTimeout != nil {
+		// Make sure the user didn't specify the RequestTimeout twice
+		if target.RequestTimeout != nil {
+			return fmt.Errorf("option RequestTimeout already configured: %w", ErrInvalidClientOptions)
+		}
+		// Don't allow a timeout that expires immediately
+		if *opts.RequestTimeout <= 0 {
+			return fmt.Errorf("option RequestTimeout must be positive: %w", ErrInvalidClientOptions)
+		}
+		target.RequestTimeout = opts.RequestTimeout
+	}
+
+	if opts.UserAgent != nil {
+		// Make sure the user didn't specify the UserAgent twice
+		if target.UserAgent != nil {
+			return fmt.Errorf("option UserAgent already configured: %w", ErrInvalidClientOptions)
+		}
+		// Don't allow an empty string
+		if len(*opts.UserAgent) == 0 {
+			return fmt.Errorf("option UserAgent cannot be an empty string: %w", ErrInvalidClientOptions)
+		}
+		target.UserAgent = opts.UserAgent
+	}
 	return nil
 }
 
+// RateLimitTransport returns a RateLimitTransport parsing the given headers, if RateLimitHandler
+// or BlockOnRateLimit is set. Otherwise, nil is returned, as the rate limit doesn't need to be tracked.
+func (opts *CommonClientOptions) RateLimitTransport(headers RateLimitHeaders) ChainableRoundTripperFunc {
+	block := opts.BlockOnRateLimit != nil && *opts.BlockOnRateLimit
+	if opts.RateLimitHandler == nil && !block {
+		return nil
+	}
+	return RateLimitTransport(headers, opts.RateLimitHandler, block)
+}
+
+// RequestTimeoutTransport returns a RequestTimeoutTransport with the RequestTimeout, if it is set.
+// Otherwise, nil is returned, as only the caller's context applies.
+func (opts *CommonClientOptions) RequestTimeoutTransport() ChainableRoundTripperFunc {
+	if opts.RequestTimeout == nil {
+		return nil
+	}
+	return RequestTimeoutTransport(*opts.RequestTimeout)
+}
+
+// UserAgentTransport returns a UserAgentTransport with the UserAgent, if it is set. Otherwise, nil
+// is returned, as the provider's Go client sets its own user agent.
+func (opts *CommonClientOptions) UserAgentTransport() ChainableRoundTripperFunc {
+	if opts.UserAgent == nil {
+		return nil
+	}
+	return UserAgentTransport(*opts.UserAgent)
+}
+
+// ResolveOrganizationRef returns the default organization if ref doesn't specify an organization,
+// and defaultOrg is set. Otherwise, ref is returned as-is. The domain of ref may be left empty in
+// that case, but if set, ErrDomainUnsupported is returned if it differs from the default organization's.
+func ResolveOrganizationRef(ref OrganizationRef, defaultOrg *OrganizationRef) (OrganizationRef, error) {
+	if defaultOrg == nil || len(ref.Organization) != 0 {
+		return ref, nil
+	}
+	if len(ref.Domain) != 0 && ref.Domain != defaultOrg.Domain {
+		return ref, fmt.Errorf("domain %q differs from the default organization's domain %q: %w",
+			ref.Domain, defaultOrg.Domain, ErrDomainUnsupported)
+	}
+	return *defaultOrg, nil
+}
+
 // BuildClientFromTransportChain builds a *http.Client from a chain of ChainableRoundTripperFuncs.
 // The first function in the chain is called with "in" == nil. "out" of the first function in the chain,
 // is passed as "in" to the second function, and so on. "out" of the last function in the chain is used
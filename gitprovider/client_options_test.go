@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/dinosk/go-git-providers/validation"
 )
@@ -65,6 +66,22 @@ func withPostChainTransportHook(postRoundTripperFunc ChainableRoundTripperFunc)
 	return &CommonClientOptions{PostChainTransportHook: postRoundTripperFunc}
 }
 
+func withDefaultOrganization(org OrganizationRef) commonClientOption {
+	return &CommonClientOptions{DefaultOrganization: &org}
+}
+
+func withRequestTimeout(d time.Duration) commonClientOption {
+	return &CommonClientOptions{RequestTimeout: &d}
+}
+
+func withUserAgent(userAgent string) commonClientOption {
+	return &CommonClientOptions{UserAgent: &userAgent}
+}
+
+func durationVar(d time.Duration) *time.Duration {
+	return &d
+}
+
 func dummyRoundTripper1(http.RoundTripper) http.RoundTripper { return nil }
 
 func Test_makeOptions(t *testing.T) {
@@ -123,6 +140,54 @@ func Test_makeOptions(t *testing.T) {
 			opts:         []commonClientOption{withPostChainTransportHook(dummyRoundTripper1), withPostChainTransportHook(dummyRoundTripper1)},
 			expectedErrs: []error{ErrInvalidClientOptions},
 		},
+		{
+			name: "withDefaultOrganization",
+			opts: []commonClientOption{withDefaultOrganization(OrganizationRef{Domain: "foo", Organization: "bar"})},
+			want: &CommonClientOptions{DefaultOrganization: &OrganizationRef{Domain: "foo", Organization: "bar"}},
+		},
+		{
+			name:         "withDefaultOrganization, empty organization",
+			opts:         []commonClientOption{withDefaultOrganization(OrganizationRef{Domain: "foo"})},
+			expectedErrs: []error{ErrInvalidClientOptions},
+		},
+		{
+			name: "withDefaultOrganization, duplicate",
+			opts: []commonClientOption{
+				withDefaultOrganization(OrganizationRef{Domain: "foo", Organization: "bar"}),
+				withDefaultOrganization(OrganizationRef{Domain: "foo", Organization: "baz"}),
+			},
+			expectedErrs: []error{ErrInvalidClientOptions},
+		},
+		{
+			name: "withRequestTimeout",
+			opts: []commonClientOption{withRequestTimeout(time.Minute)},
+			want: &CommonClientOptions{RequestTimeout: durationVar(time.Minute)},
+		},
+		{
+			name:         "withRequestTimeout, not positive",
+			opts:         []commonClientOption{withRequestTimeout(0)},
+			expectedErrs: []error{ErrInvalidClientOptions},
+		},
+		{
+			name:         "withRequestTimeout, duplicate",
+			opts:         []commonClientOption{withRequestTimeout(time.Minute), withRequestTimeout(time.Second)},
+			expectedErrs: []error{ErrInvalidClientOptions},
+		},
+		{
+			name: "withUserAgent",
+			opts: []commonClientOption{withUserAgent("flux/1.0")},
+			want: &CommonClientOptions{UserAgent: StringVar("flux/1.0")},
+		},
+		{
+			name:         "withUserAgent, empty",
+			opts:         []commonClientOption{withUserAgent("")},
+			expectedErrs: []error{ErrInvalidClientOptions},
+		},
+		{
+			name:         "withUserAgent, duplicate",
+			opts:         []commonClientOption{withUserAgent("flux/1.0"), withUserAgent("flux/2.0")},
+			expectedErrs: []error{ErrInvalidClientOptions},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -145,3 +210,54 @@ func Test_makeOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveOrganizationRef(t *testing.T) {
+	defaultOrg := &OrganizationRef{Domain: "foo.com", Organization: "bar"}
+	tests := []struct {
+		name         string
+		ref          OrganizationRef
+		defaultOrg   *OrganizationRef
+		want         OrganizationRef
+		expectedErrs []error
+	}{
+		{
+			name: "no default",
+			ref:  OrganizationRef{},
+			want: OrganizationRef{},
+		},
+		{
+			name:       "full ref",
+			ref:        OrganizationRef{Domain: "other.com", Organization: "baz"},
+			defaultOrg: defaultOrg,
+			want:       OrganizationRef{Domain: "other.com", Organization: "baz"},
+		},
+		{
+			name:       "bare ref",
+			ref:        OrganizationRef{},
+			defaultOrg: defaultOrg,
+			want:       *defaultOrg,
+		},
+		{
+			name:       "bare ref, same domain",
+			ref:        OrganizationRef{Domain: "foo.com"},
+			defaultOrg: defaultOrg,
+			want:       *defaultOrg,
+		},
+		{
+			name:         "bare ref, different domain",
+			ref:          OrganizationRef{Domain: "other.com"},
+			defaultOrg:   defaultOrg,
+			want:         OrganizationRef{Domain: "other.com"},
+			expectedErrs: []error{ErrDomainUnsupported},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveOrganizationRef(tt.ref, tt.defaultOrg)
+			validation.TestExpectErrors(t, "ResolveOrganizationRef", err, tt.expectedErrs...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveOrganizationRef() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -161,3 +161,517 @@ func ValidateLicenseTemplate(t LicenseTemplate) error {
 func LicenseTemplateVar(t LicenseTemplate) *LicenseTemplate {
 	return &t
 }
+
+// SecurityAdvisoryState is an enum specifying the state of a repository security advisory.
+type SecurityAdvisoryState string
+
+const (
+	// SecurityAdvisoryStateTriage specifies that the advisory has been reported, but not yet triaged.
+	SecurityAdvisoryStateTriage = SecurityAdvisoryState("triage")
+	// SecurityAdvisoryStateDraft specifies that the advisory has been accepted, but not yet published.
+	SecurityAdvisoryStateDraft = SecurityAdvisoryState("draft")
+	// SecurityAdvisoryStatePublished specifies that the advisory has been published.
+	SecurityAdvisoryStatePublished = SecurityAdvisoryState("published")
+	// SecurityAdvisoryStateClosed specifies that the advisory has been closed without being published.
+	SecurityAdvisoryStateClosed = SecurityAdvisoryState("closed")
+)
+
+// knownSecurityAdvisoryStateValues is a map of known SecurityAdvisoryState values, used for validation.
+//nolint:gochecknoglobals
+var knownSecurityAdvisoryStateValues = map[SecurityAdvisoryState]struct{}{
+	SecurityAdvisoryStateTriage:    {},
+	SecurityAdvisoryStateDraft:     {},
+	SecurityAdvisoryStatePublished: {},
+	SecurityAdvisoryStateClosed:    {},
+}
+
+// ValidateSecurityAdvisoryState validates a given SecurityAdvisoryState.
+// Use as errs.Append(ValidateSecurityAdvisoryState(state), state, "FieldName").
+func ValidateSecurityAdvisoryState(s SecurityAdvisoryState) error {
+	_, ok := knownSecurityAdvisoryStateValues[s]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// SecurityAdvisoryStateVar returns a pointer to a SecurityAdvisoryState.
+func SecurityAdvisoryStateVar(s SecurityAdvisoryState) *SecurityAdvisoryState {
+	return &s
+}
+
+// IssueState is an enum specifying the state of an issue.
+type IssueState string
+
+const (
+	// IssueStateOpen specifies that the issue is open.
+	IssueStateOpen = IssueState("open")
+	// IssueStateClosed specifies that the issue is closed.
+	IssueStateClosed = IssueState("closed")
+)
+
+// knownIssueStateValues is a map of known IssueState values, used for validation.
+//nolint:gochecknoglobals
+var knownIssueStateValues = map[IssueState]struct{}{
+	IssueStateOpen:   {},
+	IssueStateClosed: {},
+}
+
+// ValidateIssueState validates a given IssueState.
+// Use as errs.Append(ValidateIssueState(state), state, "FieldName").
+func ValidateIssueState(s IssueState) error {
+	_, ok := knownIssueStateValues[s]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// IssueStateVar returns a pointer to an IssueState.
+func IssueStateVar(s IssueState) *IssueState {
+	return &s
+}
+
+// ActionsSecretVisibility is an enum specifying what repositories in an organization
+// can access an organization-wide CI secret.
+type ActionsSecretVisibility string
+
+const (
+	// ActionsSecretVisibilityAll ("all") means all repositories in the organization can access the secret.
+	ActionsSecretVisibilityAll = ActionsSecretVisibility("all")
+	// ActionsSecretVisibilityPrivate ("private") means only private repositories in the organization can
+	// access the secret.
+	ActionsSecretVisibilityPrivate = ActionsSecretVisibility("private")
+	// ActionsSecretVisibilitySelected ("selected") means only an explicit list of repositories can
+	// access the secret.
+	ActionsSecretVisibilitySelected = ActionsSecretVisibility("selected")
+)
+
+// knownActionsSecretVisibilityValues is a map of known ActionsSecretVisibility values, used for validation.
+//nolint:gochecknoglobals
+var knownActionsSecretVisibilityValues = map[ActionsSecretVisibility]struct{}{
+	ActionsSecretVisibilityAll:      {},
+	ActionsSecretVisibilityPrivate:  {},
+	ActionsSecretVisibilitySelected: {},
+}
+
+// ValidateActionsSecretVisibility validates a given ActionsSecretVisibility.
+// Use as errs.Append(ValidateActionsSecretVisibility(visibility), visibility, "FieldName").
+func ValidateActionsSecretVisibility(v ActionsSecretVisibility) error {
+	_, ok := knownActionsSecretVisibilityValues[v]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// ActionsSecretVisibilityVar returns a pointer to an ActionsSecretVisibility.
+func ActionsSecretVisibilityVar(v ActionsSecretVisibility) *ActionsSecretVisibility {
+	return &v
+}
+
+// MergeQueueMergeMethod is an enum specifying how a merge queue merges the queued changes.
+type MergeQueueMergeMethod string
+
+const (
+	// MergeQueueMergeMethodMerge ("merge") means a merge commit is created for each change.
+	MergeQueueMergeMethodMerge = MergeQueueMergeMethod("merge")
+	// MergeQueueMergeMethodSquash ("squash") means the commits of each change are squashed into one.
+	MergeQueueMergeMethodSquash = MergeQueueMergeMethod("squash")
+	// MergeQueueMergeMethodRebase ("rebase") means the commits of each change are rebased onto the branch.
+	MergeQueueMergeMethodRebase = MergeQueueMergeMethod("rebase")
+)
+
+// knownMergeQueueMergeMethodValues is a map of known MergeQueueMergeMethod values, used for validation.
+//nolint:gochecknoglobals
+var knownMergeQueueMergeMethodValues = map[MergeQueueMergeMethod]struct{}{
+	MergeQueueMergeMethodMerge:  {},
+	MergeQueueMergeMethodSquash: {},
+	MergeQueueMergeMethodRebase: {},
+}
+
+// ValidateMergeQueueMergeMethod validates a given MergeQueueMergeMethod.
+// Use as errs.Append(ValidateMergeQueueMergeMethod(method), method, "FieldName").
+func ValidateMergeQueueMergeMethod(m MergeQueueMergeMethod) error {
+	_, ok := knownMergeQueueMergeMethodValues[m]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// MergeQueueMergeMethodVar returns a pointer to a MergeQueueMergeMethod.
+func MergeQueueMergeMethodVar(m MergeQueueMergeMethod) *MergeQueueMergeMethod {
+	return &m
+}
+
+// RepositorySortKey is an enum specifying the field to sort a repository list by.
+type RepositorySortKey string
+
+const (
+	// RepositorySortKeyCreated ("created") sorts by the creation time of the repository.
+	RepositorySortKeyCreated = RepositorySortKey("created")
+	// RepositorySortKeyUpdated ("updated") sorts by the last time the repository was updated.
+	RepositorySortKeyUpdated = RepositorySortKey("updated")
+	// RepositorySortKeyPushed ("pushed") sorts by the last time something was pushed to the repository.
+	RepositorySortKeyPushed = RepositorySortKey("pushed")
+	// RepositorySortKeyName ("name") sorts by the name of the repository.
+	RepositorySortKeyName = RepositorySortKey("name")
+)
+
+// knownRepositorySortKeyValues is a map of known RepositorySortKey values, used for validation.
+//nolint:gochecknoglobals
+var knownRepositorySortKeyValues = map[RepositorySortKey]struct{}{
+	RepositorySortKeyCreated: {},
+	RepositorySortKeyUpdated: {},
+	RepositorySortKeyPushed:  {},
+	RepositorySortKeyName:    {},
+}
+
+// ValidateRepositorySortKey validates a given RepositorySortKey.
+// Use as errs.Append(ValidateRepositorySortKey(key), key, "FieldName").
+func ValidateRepositorySortKey(k RepositorySortKey) error {
+	_, ok := knownRepositorySortKeyValues[k]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// RepositorySortKeyVar returns a pointer to a RepositorySortKey.
+func RepositorySortKeyVar(k RepositorySortKey) *RepositorySortKey {
+	return &k
+}
+
+// SortDirection is an enum specifying the order of a sorted list.
+type SortDirection string
+
+const (
+	// SortDirectionAscending ("asc") sorts the list in ascending order.
+	SortDirectionAscending = SortDirection("asc")
+	// SortDirectionDescending ("desc") sorts the list in descending order.
+	SortDirectionDescending = SortDirection("desc")
+)
+
+// knownSortDirectionValues is a map of known SortDirection values, used for validation.
+//nolint:gochecknoglobals
+var knownSortDirectionValues = map[SortDirection]struct{}{
+	SortDirectionAscending:  {},
+	SortDirectionDescending: {},
+}
+
+// ValidateSortDirection validates a given SortDirection.
+// Use as errs.Append(ValidateSortDirection(direction), direction, "FieldName").
+func ValidateSortDirection(d SortDirection) error {
+	_, ok := knownSortDirectionValues[d]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// SortDirectionVar returns a pointer to a SortDirection.
+func SortDirectionVar(d SortDirection) *SortDirection {
+	return &d
+}
+
+// RepositoryCreationLevel is an enum specifying who can create repositories in an organization.
+type RepositoryCreationLevel string
+
+const (
+	// RepositoryCreationLevelNoOne ("noone") means no one but administrators can create repositories.
+	RepositoryCreationLevelNoOne = RepositoryCreationLevel("noone")
+	// RepositoryCreationLevelMaintainers ("maintainer") means members with at least maintainer
+	// permissions can create repositories.
+	RepositoryCreationLevelMaintainers = RepositoryCreationLevel("maintainer")
+	// RepositoryCreationLevelDevelopers ("developer") means members with at least developer
+	// permissions can create repositories.
+	RepositoryCreationLevelDevelopers = RepositoryCreationLevel("developer")
+)
+
+// knownRepositoryCreationLevelValues is a map of known RepositoryCreationLevel values, used for validation.
+//nolint:gochecknoglobals
+var knownRepositoryCreationLevelValues = map[RepositoryCreationLevel]struct{}{
+	RepositoryCreationLevelNoOne:       {},
+	RepositoryCreationLevelMaintainers: {},
+	RepositoryCreationLevelDevelopers:  {},
+}
+
+// ValidateRepositoryCreationLevel validates a given RepositoryCreationLevel.
+// Use as errs.Append(ValidateRepositoryCreationLevel(level), level, "FieldName").
+func ValidateRepositoryCreationLevel(l RepositoryCreationLevel) error {
+	_, ok := knownRepositoryCreationLevelValues[l]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// RepositoryCreationLevelVar returns a pointer to a RepositoryCreationLevel.
+func RepositoryCreationLevelVar(l RepositoryCreationLevel) *RepositoryCreationLevel {
+	return &l
+}
+
+// EnvironmentAccessLevel is an enum specifying the minimum role required to deploy to a protected environment.
+type EnvironmentAccessLevel string
+
+const (
+	// EnvironmentAccessLevelDeveloper ("developer") means members with at least developer
+	// permissions can deploy.
+	EnvironmentAccessLevelDeveloper = EnvironmentAccessLevel("developer")
+	// EnvironmentAccessLevelMaintainer ("maintainer") means members with at least maintainer
+	// permissions can deploy.
+	EnvironmentAccessLevelMaintainer = EnvironmentAccessLevel("maintainer")
+	// EnvironmentAccessLevelAdmin ("admin") means only instance administrators can deploy.
+	EnvironmentAccessLevelAdmin = EnvironmentAccessLevel("admin")
+)
+
+// knownEnvironmentAccessLevelValues is a map of known EnvironmentAccessLevel values, used for validation.
+//nolint:gochecknoglobals
+var knownEnvironmentAccessLevelValues = map[EnvironmentAccessLevel]struct{}{
+	EnvironmentAccessLevelDeveloper:  {},
+	EnvironmentAccessLevelMaintainer: {},
+	EnvironmentAccessLevelAdmin:      {},
+}
+
+// ValidateEnvironmentAccessLevel validates a given EnvironmentAccessLevel.
+// Use as errs.Append(ValidateEnvironmentAccessLevel(level), level, "FieldName").
+func ValidateEnvironmentAccessLevel(l EnvironmentAccessLevel) error {
+	_, ok := knownEnvironmentAccessLevelValues[l]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// EnvironmentAccessLevelVar returns a pointer to a EnvironmentAccessLevel.
+func EnvironmentAccessLevelVar(l EnvironmentAccessLevel) *EnvironmentAccessLevel {
+	return &l
+}
+
+// RepositoryHookEvent is an enum specifying an event a repository webhook can be subscribed to.
+type RepositoryHookEvent string
+
+const (
+	// RepositoryHookEventPush ("push") is triggered when commits or tags are pushed to the repository.
+	RepositoryHookEventPush = RepositoryHookEvent("push")
+	// RepositoryHookEventPullRequest ("pull_request") is triggered when a pull request (merge request
+	// in GitLab) is opened, changed, merged or closed.
+	RepositoryHookEventPullRequest = RepositoryHookEvent("pull_request")
+	// RepositoryHookEventIssues ("issues") is triggered when an issue is opened, changed or closed.
+	RepositoryHookEventIssues = RepositoryHookEvent("issues")
+	// RepositoryHookEventRelease ("release") is triggered when a release is published, changed or deleted.
+	RepositoryHookEventRelease = RepositoryHookEvent("release")
+)
+
+// knownRepositoryHookEventValues is a map of known RepositoryHookEvent values, used for validation.
+//nolint:gochecknoglobals
+var knownRepositoryHookEventValues = map[RepositoryHookEvent]struct{}{
+	RepositoryHookEventPush:        {},
+	RepositoryHookEventPullRequest: {},
+	RepositoryHookEventIssues:      {},
+	RepositoryHookEventRelease:     {},
+}
+
+// ValidateRepositoryHookEvent validates a given RepositoryHookEvent.
+// Use as errs.Append(ValidateRepositoryHookEvent(event), event, "FieldName").
+func ValidateRepositoryHookEvent(e RepositoryHookEvent) error {
+	_, ok := knownRepositoryHookEventValues[e]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// RepositoryHookContentType is an enum specifying the media type of the payloads a repository
+// webhook delivers.
+type RepositoryHookContentType string
+
+const (
+	// RepositoryHookContentTypeJSON ("json") delivers the payload as the JSON request body.
+	RepositoryHookContentTypeJSON = RepositoryHookContentType("json")
+	// RepositoryHookContentTypeForm ("form") delivers the payload as the "payload" parameter of a
+	// form-encoded request body.
+	RepositoryHookContentTypeForm = RepositoryHookContentType("form")
+)
+
+// knownRepositoryHookContentTypeValues is a map of known RepositoryHookContentType values, used for validation.
+//nolint:gochecknoglobals
+var knownRepositoryHookContentTypeValues = map[RepositoryHookContentType]struct{}{
+	RepositoryHookContentTypeJSON: {},
+	RepositoryHookContentTypeForm: {},
+}
+
+// ValidateRepositoryHookContentType validates a given RepositoryHookContentType.
+// Use as errs.Append(ValidateRepositoryHookContentType(contentType), contentType, "FieldName").
+func ValidateRepositoryHookContentType(t RepositoryHookContentType) error {
+	_, ok := knownRepositoryHookContentTypeValues[t]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// RepositoryHookContentTypeVar returns a pointer to a RepositoryHookContentType.
+func RepositoryHookContentTypeVar(t RepositoryHookContentType) *RepositoryHookContentType {
+	return &t
+}
+
+// MirrorSyncStatus is an enum specifying the state of the last sync of a mirror repository.
+type MirrorSyncStatus string
+
+const (
+	// MirrorSyncStatusNone ("none") means the mirror hasn't been synced yet.
+	MirrorSyncStatusNone = MirrorSyncStatus("none")
+	// MirrorSyncStatusScheduled ("scheduled") means a sync is queued.
+	MirrorSyncStatusScheduled = MirrorSyncStatus("scheduled")
+	// MirrorSyncStatusStarted ("started") means a sync is running.
+	MirrorSyncStatusStarted = MirrorSyncStatus("started")
+	// MirrorSyncStatusFinished ("finished") means the last sync succeeded.
+	MirrorSyncStatusFinished = MirrorSyncStatus("finished")
+	// MirrorSyncStatusFailed ("failed") means the last sync failed, e.g. because the credentials
+	// for the upstream repository were rejected.
+	MirrorSyncStatusFailed = MirrorSyncStatus("failed")
+)
+
+// RulesetTarget is an enum specifying the kind of refs a repository ruleset applies to.
+type RulesetTarget string
+
+const (
+	// RulesetTargetBranch ("branch") makes the ruleset apply to branches.
+	RulesetTargetBranch = RulesetTarget("branch")
+	// RulesetTargetTag ("tag") makes the ruleset apply to tags.
+	RulesetTargetTag = RulesetTarget("tag")
+)
+
+// knownRulesetTargetValues is a map of known RulesetTarget values, used for validation.
+//nolint:gochecknoglobals
+var knownRulesetTargetValues = map[RulesetTarget]struct{}{
+	RulesetTargetBranch: {},
+	RulesetTargetTag:    {},
+}
+
+// ValidateRulesetTarget validates a given RulesetTarget.
+// Use as errs.Append(ValidateRulesetTarget(target), target, "FieldName").
+func ValidateRulesetTarget(t RulesetTarget) error {
+	_, ok := knownRulesetTargetValues[t]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// RulesetTargetVar returns a pointer to a RulesetTarget.
+func RulesetTargetVar(t RulesetTarget) *RulesetTarget {
+	return &t
+}
+
+// RulesetEnforcement is an enum specifying whether the rules of a repository ruleset are enforced.
+type RulesetEnforcement string
+
+const (
+	// RulesetEnforcementActive ("active") enforces the rules.
+	RulesetEnforcementActive = RulesetEnforcement("active")
+	// RulesetEnforcementDisabled ("disabled") doesn't enforce the rules.
+	RulesetEnforcementDisabled = RulesetEnforcement("disabled")
+	// RulesetEnforcementEvaluate ("evaluate") reports the refs violating the rules, without
+	// enforcing them. This is only available in GitHub Enterprise.
+	RulesetEnforcementEvaluate = RulesetEnforcement("evaluate")
+)
+
+// knownRulesetEnforcementValues is a map of known RulesetEnforcement values, used for validation.
+//nolint:gochecknoglobals
+var knownRulesetEnforcementValues = map[RulesetEnforcement]struct{}{
+	RulesetEnforcementActive:   {},
+	RulesetEnforcementDisabled: {},
+	RulesetEnforcementEvaluate: {},
+}
+
+// ValidateRulesetEnforcement validates a given RulesetEnforcement.
+// Use as errs.Append(ValidateRulesetEnforcement(enforcement), enforcement, "FieldName").
+func ValidateRulesetEnforcement(e RulesetEnforcement) error {
+	_, ok := knownRulesetEnforcementValues[e]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// RulesetEnforcementVar returns a pointer to a RulesetEnforcement.
+func RulesetEnforcementVar(e RulesetEnforcement) *RulesetEnforcement {
+	return &e
+}
+
+// SubscriptionLevel is an enum specifying what notifications a user gets about a repository.
+type SubscriptionLevel string
+
+const (
+	// SubscriptionLevelAll ("all") notifies about all activity in the repository.
+	// This is called "watching" in GitHub, and "watch" in GitLab.
+	SubscriptionLevelAll = SubscriptionLevel("all")
+	// SubscriptionLevelParticipating ("participating") only notifies about the conversations the
+	// user participates in, or is mentioned in. This is "not watching" in GitHub.
+	SubscriptionLevelParticipating = SubscriptionLevel("participating")
+	// SubscriptionLevelIgnore ("ignore") doesn't notify about the repository at all, not even
+	// when the user is mentioned. This is called "ignoring" in GitHub, and "disabled" in GitLab.
+	SubscriptionLevelIgnore = SubscriptionLevel("ignore")
+)
+
+// knownSubscriptionLevelValues is a map of known SubscriptionLevel values, used for validation.
+//nolint:gochecknoglobals
+var knownSubscriptionLevelValues = map[SubscriptionLevel]struct{}{
+	SubscriptionLevelAll:           {},
+	SubscriptionLevelParticipating: {},
+	SubscriptionLevelIgnore:        {},
+}
+
+// ValidateSubscriptionLevel validates a given SubscriptionLevel.
+// Use as errs.Append(ValidateSubscriptionLevel(level), level, "FieldName").
+func ValidateSubscriptionLevel(l SubscriptionLevel) error {
+	_, ok := knownSubscriptionLevelValues[l]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// SubscriptionLevelVar returns a pointer to a SubscriptionLevel.
+func SubscriptionLevelVar(l SubscriptionLevel) *SubscriptionLevel {
+	return &l
+}
+
+// CommunityHealthFile is an enum specifying a community health file of a repository, i.e. a file
+// describing how to take part in the project. The value is the name of the file.
+type CommunityHealthFile string
+
+const (
+	// CommunityHealthFileContributing ("CONTRIBUTING.md") describes how to contribute to the project.
+	CommunityHealthFileContributing = CommunityHealthFile("CONTRIBUTING.md")
+	// CommunityHealthFileCodeOfConduct ("CODE_OF_CONDUCT.md") describes how to engage in the community.
+	CommunityHealthFileCodeOfConduct = CommunityHealthFile("CODE_OF_CONDUCT.md")
+	// CommunityHealthFileSecurity ("SECURITY.md") describes how to report security vulnerabilities.
+	CommunityHealthFileSecurity = CommunityHealthFile("SECURITY.md")
+)
+
+// knownCommunityHealthFileValues is a map of known CommunityHealthFile values, used for validation.
+//nolint:gochecknoglobals
+var knownCommunityHealthFileValues = map[CommunityHealthFile]struct{}{
+	CommunityHealthFileContributing:  {},
+	CommunityHealthFileCodeOfConduct: {},
+	CommunityHealthFileSecurity:      {},
+}
+
+// ValidateCommunityHealthFile validates a given CommunityHealthFile.
+// Use as errs.Append(ValidateCommunityHealthFile(file), file, "FieldName").
+func ValidateCommunityHealthFile(f CommunityHealthFile) error {
+	_, ok := knownCommunityHealthFileValues[f]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// CommunityHealthFileVar returns a pointer to a CommunityHealthFile.
+func CommunityHealthFileVar(f CommunityHealthFile) *CommunityHealthFile {
+	return &f
+}
@@ -19,12 +19,16 @@ package gitprovider
 import (
 	"errors"
 	"net/http"
+	"strconv"
 	"time"
 )
 
 var (
 	// ErrNoProviderSupport describes that the provider doesn't support the requested feature.
 	ErrNoProviderSupport = errors.New("no provider support for this feature")
+	// ErrFeatureNotAvailable describes that the provider supports the requested feature, but not in
+	// the tier (license or subscription plan) of the instance, organization or repository.
+	ErrFeatureNotAvailable = errors.New("the feature is not available in the current tier")
 	// ErrDomainUnsupported describes the case where e.g. a GitHub provider used for trying to get
 	// information from e.g. "gitlab.com".
 	ErrDomainUnsupported = errors.New("the client doesn't support handling requests for this domain")
@@ -66,6 +70,29 @@ var (
 	// ErrInvalidPermissionLevel is the error returned when there is no mapping
 	// from the given level to the gitprovider levels.
 	ErrInvalidPermissionLevel = errors.New("invalid permission level")
+
+	// ErrInsufficientScope is returned when the credentials are valid, but lack the scope
+	// (or type of token) required for the specific request.
+	ErrInsufficientScope = errors.New("the credentials lack the scope required for this request")
+
+	// ErrRateLimited is returned when the provider rejected a request because the rate limit of the
+	// client is exhausted. Use errors.As with a *RateLimitError to get the time the limit resets.
+	ErrRateLimited = errors.New("the rate limit of the client is exhausted")
+
+	// ErrNotMirror is returned when a mirror operation is called for a repository that
+	// isn't configured to mirror another repository.
+	ErrNotMirror = errors.New("the repository isn't configured as a mirror")
+
+	// ErrDefaultBranchNotFound is returned for the repositories that are skipped when applying a
+	// BranchProtectionTemplate, as their default branch doesn't exist, e.g. as they are empty.
+	ErrDefaultBranchNotFound = errors.New("the default branch of the repository doesn't exist")
+
+	// ErrHookURLUnresolvable is returned by VerifyHookURL if the host of the webhook URL can't be resolved.
+	ErrHookURLUnresolvable = errors.New("the host of the webhook URL can't be resolved")
+	// ErrHookURLUnreachable is returned by VerifyHookURL if no connection can be made to the webhook URL.
+	ErrHookURLUnreachable = errors.New("the webhook URL is unreachable")
+	// ErrHookURLTimeout is returned by VerifyHookURL if the webhook URL didn't respond in time.
+	ErrHookURLTimeout = errors.New("the webhook URL didn't respond in time")
 )
 
 // HTTPError is an error that contains context about the HTTP request/response that failed.
@@ -98,6 +125,29 @@ type RateLimitError struct {
 	Reset time.Time `json:"reset"`
 }
 
+// Is makes errors.Is(err, ErrRateLimited) return true for a RateLimitError.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// NewRateLimitError returns a RateLimitError for httpErr, with the rate limit parsed from the given
+// headers of its response. If the response doesn't have them, as for secondary rate limits, Reset is
+// derived from the Retry-After header (in seconds) instead, if set.
+func NewRateLimitError(httpErr HTTPError, headers RateLimitHeaders) *RateLimitError {
+	rateLimitErr := &RateLimitError{HTTPError: httpErr}
+	if httpErr.Response == nil {
+		return rateLimitErr
+	}
+	if info, ok := parseRateLimitInfo(httpErr.Response.Header, headers); ok {
+		rateLimitErr.Limit = info.Limit
+		rateLimitErr.Remaining = info.Remaining
+		rateLimitErr.Reset = info.Reset
+	} else if retryAfter, err := strconv.Atoi(httpErr.Response.Header.Get("Retry-After")); err == nil {
+		rateLimitErr.Reset = time.Now().Add(time.Duration(retryAfter) * time.Second)
+	}
+	return rateLimitErr
+}
+
 // ValidationError is an error, extending HTTPError, that contains context about failed server-side validation.
 type ValidationError struct {
 	// RateLimitError extends HTTPError.
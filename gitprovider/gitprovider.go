@@ -63,6 +63,18 @@ type Updatable interface {
 	Update(ctx context.Context) error
 }
 
+// Refreshable is an interface which all objects that can be re-fetched
+// using the Client implement.
+type Refreshable interface {
+	// Refresh fetches the current state of this object from the server, without
+	// mutating anything. Any local changes that weren't applied are discarded.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	//
+	// The internal API object will be overridden with the received server data.
+	Refresh(ctx context.Context) error
+}
+
 // Deletable is an interface which all objects that can be deleted
 // using the Client implement.
 type Deletable interface {
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultHookURLTimeout is how long VerifyHookURL waits for a response if the context has no
+// earlier deadline.
+const defaultHookURLTimeout = 10 * time.Second
+
+// VerifyHookURL checks that the given webhook URL is reachable, by sending a HEAD request to it.
+// The request is sent without the credentials of the client, and redirects aren't followed. Any
+// HTTP response counts as reachable, as the endpoints of webhooks often only accept POST requests.
+//
+// The deadline of ctx applies to the request. ErrHookURLUnresolvable is returned if the host can't
+// be resolved, ErrHookURLTimeout if the URL didn't respond in time, and ErrHookURLUnreachable if
+// no connection could be made otherwise.
+func VerifyHookURL(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+	}
+	client := &http.Client{
+		Timeout: defaultHookURLTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return hookURLError(ctx, err)
+	}
+	return resp.Body.Close()
+}
+
+// hookURLError maps an error of the request sent by VerifyHookURL to the kind of failure.
+func hookURLError(ctx context.Context, err error) error {
+	// The caller giving up isn't a failure of the URL
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return ctx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %v", ErrHookURLTimeout, err)
+	}
+	// Check for DNS errors before other timeouts, as the lookup failing is the more precise cause
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return fmt.Errorf("%w: %v", ErrHookURLUnresolvable, err)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%w: %v", ErrHookURLTimeout, err)
+	}
+	return fmt.Errorf("%w: %v", ErrHookURLUnreachable, err)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestVerifyHookURL(t *testing.T) {
+	// hung only responds once the request is given up
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer hung.Close()
+	// postOnly rejects the HEAD request, which still makes it reachable
+	postOnly := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer postOnly.Close()
+	// closed is an address nothing listens on anymore
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := "http://" + l.Addr().String()
+	l.Close()
+
+	tests := []struct {
+		name        string
+		url         string
+		timeout     time.Duration
+		expectedErr error
+	}{
+		{
+			name: "reachable",
+			url:  postOnly.URL,
+		},
+		{
+			name:        "connection refused",
+			url:         closed,
+			expectedErr: ErrHookURLUnreachable,
+		},
+		{
+			name:        "context deadline",
+			url:         hung.URL,
+			timeout:     50 * time.Millisecond,
+			expectedErr: ErrHookURLTimeout,
+		},
+		{
+			name:        "invalid URL",
+			url:         "://ci.example.com",
+			expectedErr: ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			if err := VerifyHookURL(ctx, tt.url); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("VerifyHookURL() error = %v, want %v", err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func Test_hookURLError(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		err         error
+		expectedErr error
+	}{
+		{
+			name:        "DNS",
+			ctx:         context.Background(),
+			err:         &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "ci.example.invalid", IsNotFound: true}},
+			expectedErr: ErrHookURLUnresolvable,
+		},
+		{
+			name:        "DNS timeout",
+			ctx:         context.Background(),
+			err:         &net.OpError{Op: "dial", Err: &net.DNSError{Err: "i/o timeout", Name: "ci.example.com", IsTimeout: true}},
+			expectedErr: ErrHookURLUnresolvable,
+		},
+		{
+			name:        "dial timeout",
+			ctx:         context.Background(),
+			err:         &net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded},
+			expectedErr: ErrHookURLTimeout,
+		},
+		{
+			name:        "context deadline",
+			ctx:         context.Background(),
+			err:         context.DeadlineExceeded,
+			expectedErr: ErrHookURLTimeout,
+		},
+		{
+			name:        "connection reset",
+			ctx:         context.Background(),
+			err:         &net.OpError{Op: "read", Err: errors.New("connection reset by peer")},
+			expectedErr: ErrHookURLUnreachable,
+		},
+		{
+			name:        "context canceled",
+			ctx:         canceled,
+			err:         context.Canceled,
+			expectedErr: context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := hookURLError(tt.ctx, tt.err); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("hookURLError() = %v, want %v", err, tt.expectedErr)
+			}
+		})
+	}
+}
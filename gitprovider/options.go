@@ -17,6 +17,10 @@ limitations under the License.
 package gitprovider
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/dinosk/go-git-providers/validation"
 )
 
@@ -55,6 +59,20 @@ type RepositoryCreateOptions struct {
 	// Default: nil.
 	// Available options: See the LicenseTemplate enum.
 	LicenseTemplate *LicenseTemplate
+
+	// TemplateRepositoryRef lets the user create the repository from a template repository, which
+	// gives the repository the files and directory structure of the template. It can't be combined
+	// with AutoInit. ErrNotFound is returned if the template repository doesn't exist.
+	// Default: nil (which means "don't use a template").
+	// This is only supported in GitHub.
+	TemplateRepositoryRef RepositoryRef
+
+	// InheritTopics can be set to true in order to apply the topics of the template repository to
+	// the created repository, in addition to the desired topics. It can only be set together with
+	// TemplateRepositoryRef.
+	// Default: false.
+	// This is only supported in GitHub.
+	InheritTopics bool
 }
 
 // ApplyToRepositoryCreateOptions applies the options defined in the options struct to the
@@ -67,6 +85,12 @@ func (opts *RepositoryCreateOptions) ApplyToRepositoryCreateOptions(target *Repo
 	if opts.LicenseTemplate != nil {
 		target.LicenseTemplate = opts.LicenseTemplate
 	}
+	if opts.TemplateRepositoryRef != nil {
+		target.TemplateRepositoryRef = opts.TemplateRepositoryRef
+	}
+	if opts.InheritTopics {
+		target.InheritTopics = opts.InheritTopics
+	}
 }
 
 // ValidateInfo validates that the options are valid.
@@ -75,5 +99,439 @@ func (opts *RepositoryCreateOptions) ValidateOptions() error {
 	if opts.LicenseTemplate != nil {
 		errs.Append(ValidateLicenseTemplate(*opts.LicenseTemplate), *opts.LicenseTemplate, "LicenseTemplate")
 	}
+	// A repository created from a template can't also be initialized with a commit
+	if opts.TemplateRepositoryRef != nil && opts.AutoInit != nil {
+		errs.Invalid(*opts.AutoInit, "AutoInit")
+	}
+	// Topics can only be inherited from a template
+	if opts.InheritTopics && opts.TemplateRepositoryRef == nil {
+		errs.Invalid(opts.InheritTopics, "InheritTopics")
+	}
+	return errs.Error()
+}
+
+// SecurityAdvisoryListOptions specifies optional options when listing security advisories.
+type SecurityAdvisoryListOptions struct {
+	// State filters the returned advisories by the given state.
+	// Default: nil (which means "all states").
+	// Available options: See the SecurityAdvisoryState enum.
+	State *SecurityAdvisoryState
+}
+
+// ValidateOptions validates that the options are valid.
+func (opts *SecurityAdvisoryListOptions) ValidateOptions() error {
+	errs := validation.New("SecurityAdvisoryListOptions")
+	if opts.State != nil {
+		errs.Append(ValidateSecurityAdvisoryState(*opts.State), *opts.State, "State")
+	}
+	return errs.Error()
+}
+
+// CommitListOptions specifies optional options when listing commits through CommitClient.ListPage.
+type CommitListOptions struct {
+	// IncludeVerification populates the verification status of the commits, see CommitInfo.Verified.
+	// Providers that return the status separately from the commit, e.g. GitLab, need one more request
+	// per commit for this, hence it is opt-in.
+	// Default: false
+	IncludeVerification bool
+}
+
+// IssueListOptions specifies optional options when listing issues.
+type IssueListOptions struct {
+	// State filters the returned issues by the given state.
+	// Default: nil (which means "all states").
+	// Available options: See the IssueState enum.
+	State *IssueState
+
+	// Label only includes issues that have the given label.
+	// Default: "" (which means "any labels").
+	Label string
+}
+
+// ValidateOptions validates that the options are valid.
+func (opts *IssueListOptions) ValidateOptions() error {
+	errs := validation.New("IssueListOptions")
+	if opts.State != nil {
+		errs.Append(ValidateIssueState(*opts.State), *opts.State, "State")
+	}
+	return errs.Error()
+}
+
+// AuditLogOptions specifies optional options when streaming an audit log.
+type AuditLogOptions struct {
+	// Since only includes events that happened at or after the given time.
+	// Default: zero time (which means "all retained events").
+	Since time.Time
+
+	// After resumes streaming from the page with the given cursor, as returned in AuditEvent.Cursor.
+	// Default: "" (which means "from the first page").
+	After string
+}
+
+// RefListFilter specifies optional filters when listing repository references.
+// Providers apply the filters server-side where supported, and client-side otherwise.
+type RefListFilter struct {
+	// Visibility only includes repositories with the given visibility.
+	// Default: nil (which means "any visibility").
+	// Available options: See the RepositoryVisibility enum.
+	Visibility *RepositoryVisibility
+
+	// Archived only includes archived repositories if true, or non-archived repositories if false.
+	// Default: nil (which means "both archived and non-archived").
+	Archived *bool
+
+	// Language only includes repositories with the given primary programming language,
+	// compared case-insensitively, e.g. "go".
+	// Default: "" (which means "any language").
+	Language string
+
+	// NamePrefix only includes repositories whose name starts with the given prefix.
+	// Default: "" (which means "any name").
+	NamePrefix string
+}
+
+// ValidateOptions validates that the options are valid.
+func (opts *RefListFilter) ValidateOptions() error {
+	errs := validation.New("RefListFilter")
+	if opts.Visibility != nil {
+		errs.Append(ValidateRepositoryVisibility(*opts.Visibility), *opts.Visibility, "Visibility")
+	}
+	return errs.Error()
+}
+
+// RepositoryListOptions specifies optional filters when listing repositories.
+type RepositoryListOptions struct {
+	// Visibility only includes repositories with the given visibility.
+	// Default: nil (which means "any visibility").
+	// Available options: See the RepositoryVisibility enum.
+	Visibility *RepositoryVisibility
+
+	// Topic only includes repositories labeled with the given topic, compared case-insensitively.
+	// Topics are filtered client-side, from the topics listed along with each repository. Providers
+	// that don't list them return ErrNoProviderSupport instead of fetching them one by one.
+	// Default: "" (which means "any topics").
+	Topic string
+}
+
+// ValidateOptions validates that the options are valid.
+func (opts *RepositoryListOptions) ValidateOptions() error {
+	errs := validation.New("RepositoryListOptions")
+	if opts.Visibility != nil {
+		errs.Append(ValidateRepositoryVisibility(*opts.Visibility), *opts.Visibility, "Visibility")
+	}
+	if opts.Topic != "" {
+		errs.Append(ValidateTopic(opts.Topic), opts.Topic, "Topic")
+	}
+	return errs.Error()
+}
+
+// MatchesTopics returns whether a repository with the given topics matches the Topic filter.
+func (opts *RepositoryListOptions) MatchesTopics(topics []string) bool {
+	if opts.Topic == "" {
+		return true
+	}
+	for _, topic := range topics {
+		if strings.EqualFold(topic, opts.Topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// RepositorySortOptions specifies how to sort a list of repositories server-side.
+type RepositorySortOptions struct {
+	// Sort is the field to sort the repositories by.
+	// +required
+	// Available options: See the RepositorySortKey enum.
+	Sort RepositorySortKey
+
+	// Direction is the order to sort the repositories in.
+	// Default: nil (which means "desc" for the time-based keys, and "asc" for RepositorySortKeyName).
+	// Available options: See the SortDirection enum.
+	Direction *SortDirection
+}
+
+// ValidateOptions validates that the options are valid.
+func (opts *RepositorySortOptions) ValidateOptions() error {
+	errs := validation.New("RepositorySortOptions")
+	if opts.Sort == "" {
+		errs.Required("Sort")
+	} else {
+		errs.Append(ValidateRepositorySortKey(opts.Sort), opts.Sort, "Sort")
+	}
+	if opts.Direction != nil {
+		errs.Append(ValidateSortDirection(*opts.Direction), *opts.Direction, "Direction")
+	}
+	return errs.Error()
+}
+
+// GetDirection returns the configured direction, or the default one for the sort key if unset.
+func (opts *RepositorySortOptions) GetDirection() SortDirection {
+	if opts.Direction != nil {
+		return *opts.Direction
+	}
+	if opts.Sort == RepositorySortKeyName {
+		return SortDirectionAscending
+	}
+	return SortDirectionDescending
+}
+
+const (
+	// the default amount of branches that DeleteMergedBranches processes at once.
+	defaultDeleteMergedBranchesConcurrency = 4
+)
+
+// DeleteMergedBranchesOptions specifies which merged branches to delete in bulk.
+type DeleteMergedBranchesOptions struct {
+	// MinAge only deletes branches whose last commit is at least this old.
+	// Default: 0 (which means "regardless of age").
+	MinAge time.Duration
+
+	// Concurrency is the maximum amount of branches that are checked and deleted at once.
+	// Default: 0 (which means 4).
+	Concurrency int
+}
+
+// ValidateOptions validates that the options are valid.
+func (opts *DeleteMergedBranchesOptions) ValidateOptions() error {
+	errs := validation.New("DeleteMergedBranchesOptions")
+	if opts.MinAge < 0 {
+		errs.Invalid(opts.MinAge, "MinAge")
+	}
+	if opts.Concurrency < 0 {
+		errs.Invalid(opts.Concurrency, "Concurrency")
+	}
+	return errs.Error()
+}
+
+// GetConcurrency returns the configured concurrency, or the default one if unset.
+func (opts *DeleteMergedBranchesOptions) GetConcurrency() int {
+	if opts.Concurrency != 0 {
+		return opts.Concurrency
+	}
+	return defaultDeleteMergedBranchesConcurrency
+}
+
+// BranchSkipReason describes why a branch wasn't deleted by BranchClient.DeleteMergedBranches.
+type BranchSkipReason string
+
+const (
+	// BranchSkipReasonDefault means that the branch is the default branch of the repository.
+	BranchSkipReasonDefault = BranchSkipReason("default")
+	// BranchSkipReasonProtected means that the branch is protected.
+	BranchSkipReasonProtected = BranchSkipReason("protected")
+	// BranchSkipReasonNotMerged means that the branch has commits that aren't in the default branch.
+	BranchSkipReasonNotMerged = BranchSkipReason("not merged")
+	// BranchSkipReasonTooRecent means that the last commit of the branch is more recent than MinAge.
+	BranchSkipReasonTooRecent = BranchSkipReason("too recent")
+)
+
+// RepositoryReconcileRequest is a repository desired by OrgRepositoriesClient.ReconcileList.
+type RepositoryReconcileRequest struct {
+	// Name is the name of the repository in the organization.
+	// +required
+	Name string
+
+	// Info is the desired state of the repository, see OrgRepositoriesClient.Reconcile.
+	// +optional
+	Info RepositoryInfo
+}
+
+// ValidateAndDefaultRepositoryReconcileRequests validates that the names of the requests are set,
+// and unique compared case-insensitively like Git providers do, and returns a copy of the requests
+// with their Info validated and defaulted, see ValidateAndDefaultInfo.
+func ValidateAndDefaultRepositoryReconcileRequests(desired []RepositoryReconcileRequest) ([]RepositoryReconcileRequest, error) {
+	errs := validation.New("RepositoryReconcileRequest")
+	reqs := make([]RepositoryReconcileRequest, len(desired))
+	seen := make(map[string]struct{}, len(desired))
+	for i := range desired {
+		reqs[i] = desired[i]
+		name := strings.ToLower(reqs[i].Name)
+		if name == "" {
+			errs.Required("Name")
+		} else if _, ok := seen[name]; ok {
+			errs.Append(fmt.Errorf("%w: duplicate repository name", validation.ErrFieldInvalid), reqs[i].Name, "Name")
+		}
+		seen[name] = struct{}{}
+		if err := ValidateAndDefaultInfo(&reqs[i].Info); err != nil {
+			return nil, err
+		}
+	}
+	if err := errs.Error(); err != nil {
+		return nil, err
+	}
+	return reqs, nil
+}
+
+// RepositoryReconcileListOptions specifies optional options when reconciling the repositories of an
+// organization through OrgRepositoriesClient.ReconcileList.
+type RepositoryReconcileListOptions struct {
+	// Prune deletes the repositories of the organization that aren't desired, but only those whose
+	// name starts with NamePrefix. Deleting repositories requires destructive actions to be enabled
+	// in the client.
+	// Default: false
+	Prune bool
+
+	// NamePrefix guards the repositories of the organization that aren't managed, by only pruning
+	// the repositories whose name starts with it. It must be set if Prune is true, in order to never
+	// delete all repositories of an organization by mistake.
+	// Default: "" (which means "no repositories are pruned")
+	NamePrefix string
+
+	// DryRun only reports the actions that would be taken, without changing anything.
+	// Default: false
+	DryRun bool
+}
+
+// ValidateOptions validates that the options are valid.
+func (opts *RepositoryReconcileListOptions) ValidateOptions() error {
+	errs := validation.New("RepositoryReconcileListOptions")
+	if opts.Prune && opts.NamePrefix == "" {
+		errs.Required("NamePrefix")
+	}
 	return errs.Error()
 }
+
+// Prunable returns whether a repository with the given name may be deleted if it isn't desired.
+func (opts *RepositoryReconcileListOptions) Prunable(name string) bool {
+	return opts.Prune && opts.NamePrefix != "" && strings.HasPrefix(name, opts.NamePrefix)
+}
+
+// RepositoryReconcileAction describes what OrgRepositoriesClient.ReconcileList did to a repository.
+type RepositoryReconcileAction string
+
+const (
+	// RepositoryReconcileActionNone means that the repository already was in the desired state.
+	RepositoryReconcileActionNone = RepositoryReconcileAction("none")
+	// RepositoryReconcileActionCreate means that the repository was missing, and created.
+	RepositoryReconcileActionCreate = RepositoryReconcileAction("create")
+	// RepositoryReconcileActionUpdate means that the repository drifted from the desired state, and was updated.
+	RepositoryReconcileActionUpdate = RepositoryReconcileAction("update")
+	// RepositoryReconcileActionDelete means that the repository wasn't desired, and was pruned.
+	RepositoryReconcileActionDelete = RepositoryReconcileAction("delete")
+)
+
+// RepositoryReconcileResult is the action OrgRepositoriesClient.ReconcileList took, or would take in
+// a dry run, for a repository.
+type RepositoryReconcileResult struct {
+	// Name is the name of the repository in the organization.
+	Name string
+
+	// Action is what was done to the repository.
+	Action RepositoryReconcileAction
+}
+
+const (
+	// by default, ForkRepository waits at most a minute for the fork to be created.
+	defaultForkTimeout = time.Minute
+	// by default, ForkRepository checks every second whether the fork was created.
+	defaultForkPollInterval = time.Second
+)
+
+// ForkOptions specifies optional options when forking a repository through
+// OrgRepositoriesClient.ForkRepository.
+type ForkOptions struct {
+	// Organization is the organization to fork the repository into.
+	// Default: empty (which means the client's default organization, see WithDefaultOrganization).
+	Organization OrganizationRef
+
+	// Name is the name of the fork.
+	// Default: nil (which means the name of the upstream repository).
+	Name *string
+
+	// Timeout is how long to wait for the fork to be created, as Git providers may create it asynchronously.
+	// Default: 0 (which means one minute).
+	Timeout time.Duration
+
+	// PollInterval is how often to check whether the fork was created.
+	// Default: 0 (which means one second).
+	PollInterval time.Duration
+}
+
+// ValidateOptions validates that the options are valid.
+func (opts *ForkOptions) ValidateOptions() error {
+	errs := validation.New("ForkOptions")
+	if opts.Name != nil && *opts.Name == "" {
+		errs.Invalid(*opts.Name, "Name")
+	}
+	if opts.Timeout < 0 {
+		errs.Invalid(opts.Timeout, "Timeout")
+	}
+	if opts.PollInterval < 0 {
+		errs.Invalid(opts.PollInterval, "PollInterval")
+	}
+	return errs.Error()
+}
+
+// GetTimeout returns the configured timeout, or the default one if unset.
+func (opts *ForkOptions) GetTimeout() time.Duration {
+	if opts.Timeout != 0 {
+		return opts.Timeout
+	}
+	return defaultForkTimeout
+}
+
+// GetPollInterval returns the configured poll interval, or the default one if unset.
+func (opts *ForkOptions) GetPollInterval() time.Duration {
+	if opts.PollInterval != 0 {
+		return opts.PollInterval
+	}
+	return defaultForkPollInterval
+}
+
+const (
+	// by default, TransferRepository waits at most a minute for the repository to be transferred.
+	defaultTransferTimeout = time.Minute
+	// by default, TransferRepository checks every second whether the repository was transferred.
+	defaultTransferPollInterval = time.Second
+)
+
+// TransferOptions specifies optional options when transferring a repository through
+// OrgRepositoriesClient.TransferRepository.
+type TransferOptions struct {
+	// Timeout is how long to wait for the repository to appear under the new owner, as Git providers
+	// may transfer it asynchronously.
+	// Default: 0 (which means one minute).
+	Timeout time.Duration
+
+	// PollInterval is how often to check whether the repository was transferred.
+	// Default: 0 (which means one second).
+	PollInterval time.Duration
+}
+
+// ValidateOptions validates that the options are valid.
+func (opts *TransferOptions) ValidateOptions() error {
+	errs := validation.New("TransferOptions")
+	if opts.Timeout < 0 {
+		errs.Invalid(opts.Timeout, "Timeout")
+	}
+	if opts.PollInterval < 0 {
+		errs.Invalid(opts.PollInterval, "PollInterval")
+	}
+	return errs.Error()
+}
+
+// GetTimeout returns the configured timeout, or the default one if unset.
+func (opts *TransferOptions) GetTimeout() time.Duration {
+	if opts.Timeout != 0 {
+		return opts.Timeout
+	}
+	return defaultTransferTimeout
+}
+
+// GetPollInterval returns the configured poll interval, or the default one if unset.
+func (opts *TransferOptions) GetPollInterval() time.Duration {
+	if opts.PollInterval != 0 {
+		return opts.PollInterval
+	}
+	return defaultTransferPollInterval
+}
+
+// RepositoryHookCreateOptions specifies optional options when creating a webhook through
+// RepositoryHookClient.Create.
+type RepositoryHookCreateOptions struct {
+	// VerifyURL can be set to true in order to check that the URL of the webhook is reachable
+	// before creating it, see VerifyHookURL.
+	// Default: false (which means the webhook is created without sending any request to its URL).
+	VerifyURL bool
+}
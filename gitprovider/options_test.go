@@ -31,6 +31,10 @@ var (
 	partialCreateOpts1     = &RepositoryCreateOptions{AutoInit: BoolVar(false)}
 	partialCreateOpts2     = &RepositoryCreateOptions{LicenseTemplate: LicenseTemplateVar(LicenseTemplateApache2)}
 	invalidRepoCreateOpts  = &RepositoryCreateOptions{LicenseTemplate: &unknownLicenseTemplate}
+	templateCreateOpts     = &RepositoryCreateOptions{TemplateRepositoryRef: OrgRepositoryRef{
+		OrganizationRef: OrganizationRef{Domain: "github.com", Organization: "fluxcd"},
+		RepositoryName:  "template",
+	}}
 )
 
 func TestMakeRepositoryCreateOptions(t *testing.T) {
@@ -72,6 +76,37 @@ func TestMakeRepositoryCreateOptions(t *testing.T) {
 			},
 			want: *repoCreateOpts2,
 		},
+		{
+			name: "template without auto init",
+			opts: []RepositoryCreateOption{templateCreateOpts, partialCreateOpts2},
+			want: RepositoryCreateOptions{
+				LicenseTemplate:       partialCreateOpts2.LicenseTemplate,
+				TemplateRepositoryRef: templateCreateOpts.TemplateRepositoryRef,
+			},
+		},
+		{
+			name: "template can't be combined with auto init",
+			opts: []RepositoryCreateOption{templateCreateOpts, partialCreateOpts1},
+			want: RepositoryCreateOptions{
+				AutoInit:              partialCreateOpts1.AutoInit,
+				TemplateRepositoryRef: templateCreateOpts.TemplateRepositoryRef,
+			},
+			expectedErr: validation.ErrFieldInvalid,
+		},
+		{
+			name: "inherit topics from a template",
+			opts: []RepositoryCreateOption{templateCreateOpts, &RepositoryCreateOptions{InheritTopics: true}},
+			want: RepositoryCreateOptions{
+				TemplateRepositoryRef: templateCreateOpts.TemplateRepositoryRef,
+				InheritTopics:         true,
+			},
+		},
+		{
+			name:        "topics can't be inherited without a template",
+			opts:        []RepositoryCreateOption{&RepositoryCreateOptions{InheritTopics: true}},
+			want:        RepositoryCreateOptions{InheritTopics: true},
+			expectedErr: validation.ErrFieldInvalid,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -93,3 +128,53 @@ func TestMakeRepositoryCreateOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestRepositorySortOptions(t *testing.T) {
+	unknownSortKey := RepositorySortKey("stars")
+	tests := []struct {
+		name          string
+		opts          RepositorySortOptions
+		wantDirection SortDirection
+		expectedErr   error
+	}{
+		{
+			name:          "time key defaults to descending",
+			opts:          RepositorySortOptions{Sort: RepositorySortKeyPushed},
+			wantDirection: SortDirectionDescending,
+		},
+		{
+			name:          "name key defaults to ascending",
+			opts:          RepositorySortOptions{Sort: RepositorySortKeyName},
+			wantDirection: SortDirectionAscending,
+		},
+		{
+			name:          "explicit direction",
+			opts:          RepositorySortOptions{Sort: RepositorySortKeyName, Direction: SortDirectionVar(SortDirectionDescending)},
+			wantDirection: SortDirectionDescending,
+		},
+		{
+			name:        "missing sort key",
+			opts:        RepositorySortOptions{},
+			expectedErr: validation.ErrFieldRequired,
+		},
+		{
+			name:        "unknown sort key",
+			opts:        RepositorySortOptions{Sort: unknownSortKey},
+			expectedErr: validation.ErrFieldEnumInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.ValidateOptions()
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("RepositorySortOptions.ValidateOptions() error = %v, wanted %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+			if got := tt.opts.GetDirection(); got != tt.wantDirection {
+				t.Errorf("RepositorySortOptions.GetDirection() = %v, want %v", got, tt.wantDirection)
+			}
+		})
+	}
+}
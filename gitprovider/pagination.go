@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// UnknownCount is used in PageInfo for counts the Git provider doesn't supply.
+const UnknownCount = -1
+
+// PageOptions specifies what page of a paginated list to request from the Git provider.
+type PageOptions struct {
+	// Page is the 1-based index of the page to request.
+	// Default: 0 (which means the first page).
+	Page int
+
+	// PerPage is the maximum amount of items to return in the page.
+	// Default: 0 (which means the provider-specific default).
+	PerPage int
+}
+
+// ValidateOptions validates that the options are valid.
+func (opts *PageOptions) ValidateOptions() error {
+	errs := validation.New("PageOptions")
+	if opts.Page < 0 {
+		errs.Invalid(opts.Page, "Page")
+	}
+	if opts.PerPage < 0 {
+		errs.Invalid(opts.PerPage, "PerPage")
+	}
+	return errs.Error()
+}
+
+// PageInfo contains the pagination metadata returned from the Git provider along with a page of items.
+type PageInfo struct {
+	// NextPage is the index of the next page, which can be given as PageOptions.Page to fetch it.
+	// NextPage is 0 if this was the last page.
+	NextPage int `json:"nextPage"`
+
+	// TotalCount is the total amount of items in all pages, or UnknownCount if not supplied by the provider.
+	TotalCount int `json:"totalCount"`
+
+	// TotalPages is the total amount of pages, or UnknownCount if not supplied by the provider.
+	TotalPages int `json:"totalPages"`
+}
+
+// IsLastPage returns true if there are no more pages after this one.
+func (p PageInfo) IsLastPage() bool {
+	return p.NextPage == 0
+}
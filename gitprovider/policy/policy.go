@@ -0,0 +1,184 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy applies repository metadata stored as code, e.g. in a central repository, to
+// the repositories in a Git provider.
+//
+// A policy file is a YAML document like the following:
+//
+//	repository: https://github.com/fluxcd/flux2
+//	description: Open and extensible continuous delivery solution for Kubernetes.
+//	topics:
+//	- gitops
+//	- kubernetes
+//	visibility: public
+//
+// Only the fields set in the policy are applied; the other fields of the repository are left as-is.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// Policy describes the desired metadata of a single repository.
+type Policy struct {
+	// Repository is the HTTPS URL of the repository the policy applies to.
+	// +required
+	Repository string `yaml:"repository"`
+
+	// OwnerType specifies whether the repository is owned by a user or an organization.
+	// Default: organization
+	// +optional
+	OwnerType gitprovider.IdentityType `yaml:"ownerType,omitempty"`
+
+	// Description is the desired description of the repository.
+	// +optional
+	Description *string `yaml:"description,omitempty"`
+
+	// Topics lists the desired topics of the repository. An empty list removes all topics.
+	// +optional
+	Topics []string `yaml:"topics,omitempty"`
+
+	// Visibility is the desired visibility of the repository.
+	// +optional
+	Visibility *gitprovider.RepositoryVisibility `yaml:"visibility,omitempty"`
+}
+
+// Parse parses and validates a policy from the YAML document in data, e.g. the contents of a
+// policy file fetched from a repository. Unknown fields are reported as errors.
+func Parse(data []byte) (*Policy, error) {
+	p := &Policy{}
+	if err := yaml.UnmarshalStrict(data, p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParseFile parses and validates a policy from the YAML file at the given local path.
+func ParseFile(path string) (*Policy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Validate validates that the policy is well-formed. Errors wrap gitprovider.ErrInvalidArgument.
+func (p *Policy) Validate() error {
+	validator := validation.New("Policy")
+	if p.Repository == "" {
+		validator.Required("Repository")
+	} else if _, err := gitprovider.ParseOrgRepositoryURL(p.Repository); err != nil {
+		validator.Invalid(p.Repository, "Repository")
+	}
+	switch p.OwnerType {
+	case "", gitprovider.IdentityTypeOrganization, gitprovider.IdentityTypeUser:
+	default:
+		validator.Invalid(p.OwnerType, "OwnerType")
+	}
+	for _, topic := range p.Topics {
+		validator.Append(gitprovider.ValidateTopic(topic), topic, "Topics")
+	}
+	if p.Visibility != nil {
+		validator.Append(gitprovider.ValidateRepositoryVisibility(*p.Visibility), *p.Visibility, "Visibility")
+	}
+	if err := validator.Error(); err != nil {
+		return validation.NewMultiError(err, gitprovider.ErrInvalidArgument)
+	}
+	return nil
+}
+
+// Apply applies the policy to its repository through Reconcile, and returns the names of the
+// RepositoryInfo fields that were changed, e.g. "Topics". If the repository already complies with
+// the policy, no changes are made and an empty list is returned.
+//
+// If the repository doesn't exist, an error wrapping gitprovider.ErrNotFound is returned; the
+// repository is not created.
+func (p *Policy) Apply(ctx context.Context, c gitprovider.ResourceClient) ([]string, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	repo, err := p.getRepository(ctx, c)
+	if errors.Is(err, gitprovider.ErrNotFound) {
+		return nil, fmt.Errorf("repository %q referenced by the policy doesn't exist: %w", p.Repository, err)
+	} else if err != nil {
+		return nil, err
+	}
+	return p.applyTo(ctx, repo)
+}
+
+func (p *Policy) getRepository(ctx context.Context, c gitprovider.ResourceClient) (gitprovider.UserRepository, error) {
+	if p.OwnerType == gitprovider.IdentityTypeUser {
+		ref, err := gitprovider.ParseUserRepositoryURL(p.Repository)
+		if err != nil {
+			return nil, err
+		}
+		return c.UserRepositories().Get(ctx, *ref)
+	}
+	ref, err := gitprovider.ParseOrgRepositoryURL(p.Repository)
+	if err != nil {
+		return nil, err
+	}
+	return c.OrgRepositories().Get(ctx, *ref)
+}
+
+// applyTo applies the policy to repo, and returns the names of the changed fields.
+func (p *Policy) applyTo(ctx context.Context, repo gitprovider.UserRepository) ([]string, error) {
+	info := repo.Get()
+	changed := []string{}
+	if p.Description != nil && (info.Description == nil || *info.Description != *p.Description) {
+		info.Description = p.Description
+		changed = append(changed, "Description")
+	}
+	if p.Topics != nil {
+		topics := gitprovider.NormalizeTopics(p.Topics)
+		if !reflect.DeepEqual(gitprovider.NormalizeTopics(info.Topics), topics) {
+			info.Topics = topics
+			changed = append(changed, "Topics")
+		}
+	}
+	if p.Visibility != nil && (info.Visibility == nil || *info.Visibility != *p.Visibility) {
+		info.Visibility = p.Visibility
+		changed = append(changed, "Visibility")
+	}
+	if len(changed) == 0 {
+		return changed, nil
+	}
+
+	if err := repo.Set(info); err != nil {
+		return nil, err
+	}
+	if _, err := repo.Reconcile(ctx); err != nil {
+		return nil, fmt.Errorf("failed to apply policy to repository %q: %w", p.Repository, err)
+	}
+	return changed, nil
+}
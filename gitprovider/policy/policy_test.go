@@ -0,0 +1,222 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dinosk/go-git-providers/gitprovider"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *Policy
+		wantErr error
+	}{
+		{
+			name: "all fields",
+			data: `
+repository: https://github.com/foo/bar
+ownerType: user
+description: A repository.
+topics: [gitops, Kubernetes]
+visibility: private
+`,
+			want: &Policy{
+				Repository:  "https://github.com/foo/bar",
+				OwnerType:   gitprovider.IdentityTypeUser,
+				Description: gitprovider.StringVar("A repository."),
+				Topics:      []string{"gitops", "Kubernetes"},
+				Visibility:  gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPrivate),
+			},
+		},
+		{
+			name: "only repository",
+			data: "repository: https://github.com/foo/bar",
+			want: &Policy{Repository: "https://github.com/foo/bar"},
+		},
+		{
+			name:    "missing repository",
+			data:    "description: A repository.",
+			wantErr: gitprovider.ErrInvalidArgument,
+		},
+		{
+			name:    "repository without name",
+			data:    "repository: https://github.com/foo",
+			wantErr: gitprovider.ErrInvalidArgument,
+		},
+		{
+			name:    "invalid owner type",
+			data:    "repository: https://github.com/foo/bar\nownerType: team",
+			wantErr: gitprovider.ErrInvalidArgument,
+		},
+		{
+			name:    "invalid topic",
+			data:    "repository: https://github.com/foo/bar\ntopics: [-foo]",
+			wantErr: gitprovider.ErrInvalidArgument,
+		},
+		{
+			name:    "invalid visibility",
+			data:    "repository: https://github.com/foo/bar\nvisibility: secret",
+			wantErr: gitprovider.ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_unknownField(t *testing.T) {
+	if _, err := Parse([]byte("repository: https://github.com/foo/bar\ntopic: [foo]")); err == nil {
+		t.Error("Parse() error = nil, want an error for the unknown field")
+	}
+}
+
+// fakeRepository is a gitprovider.OrgRepository that records the reconciled info.
+// Calling any other method than the overridden ones panics.
+type fakeRepository struct {
+	gitprovider.OrgRepository
+
+	info       gitprovider.RepositoryInfo
+	reconciled *gitprovider.RepositoryInfo
+}
+
+func (r *fakeRepository) Get() gitprovider.RepositoryInfo { return r.info }
+
+func (r *fakeRepository) Set(info gitprovider.RepositoryInfo) error {
+	r.info = info
+	return nil
+}
+
+func (r *fakeRepository) Reconcile(_ context.Context) (bool, error) {
+	info := r.info
+	r.reconciled = &info
+	return true, nil
+}
+
+// fakeOrgRepositoriesClient serves a single repository by its name.
+type fakeOrgRepositoriesClient struct {
+	gitprovider.OrgRepositoriesClient
+
+	name string
+	repo *fakeRepository
+}
+
+func (c *fakeOrgRepositoriesClient) Get(_ context.Context, ref gitprovider.OrgRepositoryRef) (gitprovider.OrgRepository, error) {
+	if ref.RepositoryName != c.name {
+		return nil, gitprovider.ErrNotFound
+	}
+	return c.repo, nil
+}
+
+type fakeResourceClient struct {
+	gitprovider.ResourceClient
+
+	orgRepos *fakeOrgRepositoriesClient
+}
+
+func (c *fakeResourceClient) OrgRepositories() gitprovider.OrgRepositoriesClient { return c.orgRepos }
+
+func TestPolicy_Apply(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      Policy
+		wantChanged []string
+		wantInfo    *gitprovider.RepositoryInfo
+	}{
+		{
+			name: "changed fields",
+			policy: Policy{
+				Repository:  "https://github.com/foo/bar",
+				Description: gitprovider.StringVar("new"),
+				Topics:      []string{"Kubernetes", "gitops"},
+				Visibility:  gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic),
+			},
+			wantChanged: []string{"Description", "Topics"},
+			wantInfo: &gitprovider.RepositoryInfo{
+				Description: gitprovider.StringVar("new"),
+				Topics:      []string{"gitops", "kubernetes"},
+				Visibility:  gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic),
+			},
+		},
+		{
+			name: "remove topics",
+			policy: Policy{
+				Repository: "https://github.com/foo/bar",
+				Topics:     []string{},
+			},
+			wantChanged: []string{"Topics"},
+			wantInfo: &gitprovider.RepositoryInfo{
+				Description: gitprovider.StringVar("old"),
+				Topics:      []string{},
+				Visibility:  gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic),
+			},
+		},
+		{
+			name: "no changes",
+			policy: Policy{
+				Repository:  "https://github.com/foo/bar",
+				Description: gitprovider.StringVar("old"),
+				Topics:      []string{"GitOps"},
+			},
+			wantChanged: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{info: gitprovider.RepositoryInfo{
+				Description: gitprovider.StringVar("old"),
+				Topics:      []string{"gitops"},
+				Visibility:  gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic),
+			}}
+			c := &fakeResourceClient{orgRepos: &fakeOrgRepositoriesClient{name: "bar", repo: repo}}
+
+			changed, err := tt.policy.Apply(context.Background(), c)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if !reflect.DeepEqual(changed, tt.wantChanged) {
+				t.Errorf("Apply() = %v, want %v", changed, tt.wantChanged)
+			}
+			if !reflect.DeepEqual(repo.reconciled, tt.wantInfo) {
+				t.Errorf("reconciled info = %+v, want %+v", repo.reconciled, tt.wantInfo)
+			}
+		})
+	}
+}
+
+func TestPolicy_Apply_notFound(t *testing.T) {
+	c := &fakeResourceClient{orgRepos: &fakeOrgRepositoriesClient{name: "bar"}}
+	p := Policy{Repository: "https://github.com/foo/baz", Description: gitprovider.StringVar("new")}
+
+	if _, err := p.Apply(context.Background(), c); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Apply() error = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitInfo describes the rate limit of a client, as reported by the provider in the headers
+// of a response.
+type RateLimitInfo struct {
+	// The number of requests per period the client is currently limited to.
+	Limit int
+	// The number of remaining requests the client can make in the current period.
+	Remaining int
+	// The timestamp at which point the current rate limit will reset.
+	Reset time.Time
+}
+
+// RateLimitHeaders specifies the names of the response headers a provider reports its rate limit in,
+// e.g. "X-RateLimit-Remaining" for GitHub, and "RateLimit-Remaining" for GitLab. The reset header
+// must contain a Unix timestamp in seconds.
+type RateLimitHeaders struct {
+	Limit     string
+	Remaining string
+	Reset     string
+}
+
+// parseRateLimitInfo parses the rate limit headers of a response. false is returned if the
+// response doesn't have them, or they are malformed.
+func parseRateLimitInfo(header http.Header, names RateLimitHeaders) (RateLimitInfo, bool) {
+	limit, err := strconv.Atoi(header.Get(names.Limit))
+	if err != nil {
+		return RateLimitInfo{}, false
+	}
+	remaining, err := strconv.Atoi(header.Get(names.Remaining))
+	if err != nil {
+		return RateLimitInfo{}, false
+	}
+	reset, err := strconv.ParseInt(header.Get(names.Reset), 10, 64)
+	if err != nil {
+		return RateLimitInfo{}, false
+	}
+	return RateLimitInfo{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}, true
+}
+
+// RateLimitTransport returns a ChainableRoundTripperFunc parsing the rate limit headers of every
+// response, and calling handler with the result, if handler is non-nil. If block is true, requests
+// made after a response reported that no requests remain wait until the rate limit resets. Waiting
+// stops when the context of the request is done, returning its error.
+func RateLimitTransport(headers RateLimitHeaders, handler func(RateLimitInfo), block bool) ChainableRoundTripperFunc {
+	return func(in http.RoundTripper) http.RoundTripper {
+		if in == nil {
+			in = http.DefaultTransport
+		}
+		return &rateLimitTransport{
+			next:    in,
+			headers: headers,
+			handler: handler,
+			block:   block,
+			now:     time.Now,
+			sleep:   sleepContext,
+		}
+	}
+}
+
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	headers RateLimitHeaders
+	handler func(RateLimitInfo)
+	block   bool
+
+	mu sync.Mutex
+	// blockedUntil is the reset time of an exhausted rate limit, requests wait until then.
+	blockedUntil time.Time
+	// now returns the current time, it can be replaced in unit tests.
+	now func() time.Time
+	// sleep waits for d, or until ctx is done. It can be replaced in unit tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.block {
+		if err := t.waitForReset(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	info, ok := parseRateLimitInfo(resp.Header, t.headers)
+	if !ok {
+		return resp, nil
+	}
+	if t.block && info.Remaining == 0 {
+		t.mu.Lock()
+		if info.Reset.After(t.blockedUntil) {
+			t.blockedUntil = info.Reset
+		}
+		t.mu.Unlock()
+	}
+	if t.handler != nil {
+		t.handler(info)
+	}
+	return resp, nil
+}
+
+// waitForReset waits until the exhausted rate limit resets, if there is one.
+func (t *rateLimitTransport) waitForReset(ctx context.Context) error {
+	t.mu.Lock()
+	delay := t.blockedUntil.Sub(t.now())
+	t.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	return t.sleep(ctx, delay)
+}
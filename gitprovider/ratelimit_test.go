@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testRateLimitHeaders = RateLimitHeaders{
+	Limit:     "X-RateLimit-Limit",
+	Remaining: "X-RateLimit-Remaining",
+	Reset:     "X-RateLimit-Reset",
+}
+
+// rateLimitedRoundTripper returns the scripted remaining request counts in order, in the rate limit headers.
+type rateLimitedRoundTripper struct {
+	remaining []string
+	requests  int
+}
+
+func (rt *rateLimitedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader(""))}
+	if remaining := rt.remaining[rt.requests]; remaining != "" {
+		resp.Header.Set("X-RateLimit-Limit", "5000")
+		resp.Header.Set("X-RateLimit-Remaining", remaining)
+		resp.Header.Set("X-RateLimit-Reset", "1600000060")
+	}
+	rt.requests++
+	return resp, nil
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	reset := time.Unix(1600000060, 0)
+	tests := []struct {
+		name       string
+		remaining  []string
+		block      bool
+		wantInfos  []RateLimitInfo
+		wantDelays []time.Duration
+	}{
+		{
+			name:      "report the rate limit",
+			remaining: []string{"2", "1"},
+			wantInfos: []RateLimitInfo{
+				{Limit: 5000, Remaining: 2, Reset: reset},
+				{Limit: 5000, Remaining: 1, Reset: reset},
+			},
+		},
+		{
+			name:      "skip responses without rate limit",
+			remaining: []string{"", "1"},
+			wantInfos: []RateLimitInfo{
+				{Limit: 5000, Remaining: 1, Reset: reset},
+			},
+		},
+		{
+			name:      "don't block by default",
+			remaining: []string{"0", "4999"},
+			wantInfos: []RateLimitInfo{
+				{Limit: 5000, Remaining: 0, Reset: reset},
+				{Limit: 5000, Remaining: 4999, Reset: reset},
+			},
+		},
+		{
+			name:      "block until reset when exhausted",
+			remaining: []string{"1", "0", "4999"},
+			block:     true,
+			wantInfos: []RateLimitInfo{
+				{Limit: 5000, Remaining: 1, Reset: reset},
+				{Limit: 5000, Remaining: 0, Reset: reset},
+				{Limit: 5000, Remaining: 4999, Reset: reset},
+			},
+			wantDelays: []time.Duration{time.Minute},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			infos := []RateLimitInfo{}
+			handler := func(info RateLimitInfo) { infos = append(infos, info) }
+			fake := &rateLimitedRoundTripper{remaining: tt.remaining}
+			rt := RateLimitTransport(testRateLimitHeaders, handler, tt.block)(fake).(*rateLimitTransport)
+			rt.now = func() time.Time { return time.Unix(1600000000, 0) }
+			delays := []time.Duration{}
+			rt.sleep = func(_ context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return nil
+			}
+
+			for range tt.remaining {
+				req, err := http.NewRequest(http.MethodGet, "https://api.github.com/repos/foo/bar", nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := rt.RoundTrip(req); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !reflect.DeepEqual(infos, tt.wantInfos) {
+				t.Errorf("handler called with %v, want %v", infos, tt.wantInfos)
+			}
+			if len(delays) != len(tt.wantDelays) || (len(delays) != 0 && !reflect.DeepEqual(delays, tt.wantDelays)) {
+				t.Errorf("delays = %v, want %v", delays, tt.wantDelays)
+			}
+		})
+	}
+}
+
+func TestRateLimitTransport_contextCanceled(t *testing.T) {
+	fake := &rateLimitedRoundTripper{remaining: []string{"0", "4999"}}
+	rt := RateLimitTransport(testRateLimitHeaders, nil, true)(fake).(*rateLimitTransport)
+	rt.now = func() time.Time { return time.Unix(1600000000, 0) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/repos/foo/bar", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	if _, err := rt.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("RoundTrip() error = %v, want %v", err, context.Canceled)
+	}
+	if fake.requests != 1 {
+		t.Errorf("got %d requests, want 1", fake.requests)
+	}
+}
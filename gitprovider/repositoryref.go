@@ -17,6 +17,8 @@ limitations under the License.
 package gitprovider
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -103,7 +105,7 @@ func (u UserRef) GetType() IdentityType {
 
 // String returns the HTTPS URL to access the User.
 func (u UserRef) String() string {
-	return fmt.Sprintf("https://%s/%s", u.GetDomain(), u.GetIdentity())
+	return identityURL(u.GetDomain(), u.UserLogin)
 }
 
 // ValidateFields validates its own fields for a given validator.
@@ -159,7 +161,17 @@ func (o OrganizationRef) GetType() IdentityType {
 
 // String returns the HTTPS URL to access the Organization.
 func (o OrganizationRef) String() string {
-	return fmt.Sprintf("https://%s/%s", o.GetDomain(), o.GetIdentity())
+	return identityURL(o.GetDomain(), append([]string{o.Organization}, o.SubOrganizations...)...)
+}
+
+// identityURL returns the HTTPS URL of the given path on domain. The path segments are escaped, so
+// that parsing the URL again, e.g. through ParseOrganizationURL, yields the same segments.
+func identityURL(domain string, segments ...string) string {
+	escaped := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+	return fmt.Sprintf("https://%s/%s", domain, strings.Join(escaped, "/"))
 }
 
 // ValidateFields validates its own fields for a given validator.
@@ -186,7 +198,7 @@ type OrgRepositoryRef struct {
 
 // String returns the HTTPS URL to access the repository.
 func (r OrgRepositoryRef) String() string {
-	return fmt.Sprintf("%s/%s", r.OrganizationRef.String(), r.RepositoryName)
+	return fmt.Sprintf("%s/%s", r.OrganizationRef.String(), url.PathEscape(r.RepositoryName))
 }
 
 // GetRepository returns the repository name for this repo.
@@ -222,7 +234,7 @@ type UserRepositoryRef struct {
 
 // String returns the HTTPS URL to access the repository.
 func (r UserRepositoryRef) String() string {
-	return fmt.Sprintf("%s/%s", r.UserRef.String(), r.RepositoryName)
+	return fmt.Sprintf("%s/%s", r.UserRef.String(), url.PathEscape(r.RepositoryName))
 }
 
 // GetRepository returns the repository name for this repo.
@@ -262,6 +274,26 @@ func GetCloneURL(rs RepositoryRef, transport TransportType) string {
 	return ""
 }
 
+// GetAuthenticatedCloneURL returns the HTTPS URL to clone a repository with the given credentials
+// embedded, e.g. "https://oauth2:<token>@gitlab.com/org/repo.git". The credentials are URL-escaped.
+// ErrInvalidArgument is returned if the transport isn't TransportTypeHTTPS, or a credential is empty.
+//
+// The returned URL contains a secret, and hence shouldn't be logged or persisted.
+func GetAuthenticatedCloneURL(rs RepositoryRef, transport TransportType, username, token string) (string, error) {
+	if transport != TransportTypeHTTPS {
+		return "", fmt.Errorf("credentials can't be embedded in clone URLs of transport %q: %w", transport, ErrInvalidArgument)
+	}
+	if len(username) == 0 || len(token) == 0 {
+		return "", fmt.Errorf("username and token are required: %w", ErrInvalidArgument)
+	}
+	u, err := url.Parse(GetCloneURL(rs, transport))
+	if err != nil {
+		return "", fmt.Errorf("%v: %w", err, ErrInvalidArgument)
+	}
+	u.User = url.UserPassword(username, token)
+	return u.String(), nil
+}
+
 // ParseOrganizationURL parses an URL to an organization into a OrganizationRef object.
 func ParseOrganizationURL(o string) (*OrganizationRef, error) {
 	u, parts, err := parseURL(o)
@@ -326,6 +358,41 @@ func ParseOrgRepositoryURL(r string) (*OrgRepositoryRef, error) {
 	}, nil
 }
 
+// ParseRepositoryURLAuto parses a HTTPS clone URL into a RepositoryRef object, consulting the Git
+// provider through c to find out whether the owner of the repository is an organization or a user.
+// An OrgRepositoryRef is returned for organizations, and a UserRepositoryRef for users. Owners with
+// sub-organizations are always organizations. Use ParseOrgRepositoryURL or ParseUserRepositoryURL
+// to parse the URL offline, if the type of the owner is known.
+//
+// ErrNotFound is returned if the owner doesn't exist.
+func ParseRepositoryURLAuto(ctx context.Context, c ResourceClient, r string) (RepositoryRef, error) {
+	orgRef, err := ParseOrgRepositoryURL(r)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.Organizations().Get(ctx, orgRef.OrganizationRef)
+	if err == nil {
+		return *orgRef, nil
+	}
+	if !errors.Is(err, ErrNotFound) || len(orgRef.SubOrganizations) != 0 {
+		return nil, err
+	}
+
+	// The owner isn't an organization, hence check that it's an existing user
+	userRef, err := ParseUserRepositoryURL(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := c.UserRepositories().ListPage(ctx, userRef.UserRef, PageOptions{PerPage: 1}); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("owner %q is neither an organization nor a user: %w", userRef.UserLogin, err)
+		}
+		return nil, err
+	}
+	return *userRef, nil
+}
+
 func parseRepositoryURL(r string) (orgInfoPtr *OrganizationRef, repoName string, err error) {
 	// First, parse the URL as an organization
 	orgInfoPtr, err = ParseOrganizationURL(r)
@@ -368,17 +435,21 @@ func parseURL(str string) (*url.URL, []string, error) {
 	}
 
 	// Strip any leading and trailing slash to be able to split the string cleanly
-	path := strings.TrimSuffix(strings.TrimPrefix(u.Path, "/"), "/")
-	// Split the path by slash
+	path := strings.TrimSuffix(strings.TrimPrefix(u.EscapedPath(), "/"), "/")
+	// Split the escaped path by slash, so that escaped slashes stay part of their segment
 	parts := strings.Split(path, "/")
 	// Make sure there aren't any "empty" string splits
 	// This has the consequence that it's guaranteed that there is at least one
 	// part returned, so there's no need to check for len(parts) < 1
-	for _, p := range parts {
+	for i, p := range parts {
 		// Make sure any path part is not empty
 		if len(p) == 0 {
 			return nil, nil, fmt.Errorf("%w: %s", ErrURLInvalid, str)
 		}
+		// Unescape the part, it's escaped again by e.g. OrganizationRef.String()
+		if parts[i], err = url.PathUnescape(p); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrURLInvalid, str)
+		}
 	}
 	return u, parts, nil
 }
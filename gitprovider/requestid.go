@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+const (
+	// RequestIDHeader is the HTTP header the request ID from the context is sent in.
+	RequestIDHeader = "X-Request-Id"
+	// gitHubRequestIDHeader is the HTTP header GitHub returns its own request ID in.
+	gitHubRequestIDHeader = "X-GitHub-Request-Id"
+)
+
+// requestIDContextKey is the context key for the request ID set by WithRequestID.
+type requestIDContextKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the given request (or trace) ID. Requests made with
+// the returned context through RequestIDTransport have the ID set in the RequestIDHeader header.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID set in ctx by WithRequestID, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDContextKey{}).(string)
+	return requestID, ok && len(requestID) != 0
+}
+
+// RequestIDTransport is a ChainableRoundTripperFunc which sets the request ID of the request context
+// (see WithRequestID) in the RequestIDHeader header of outgoing requests. Register it with the
+// provider's WithPreChainTransportHook or WithPostChainTransportHook option. To also have the request
+// ID in e.g. logs, chain a logging RoundTripper after it:
+//
+//	func(in http.RoundTripper) http.RoundTripper { return RequestIDTransport(newLoggingTransport(in)) }
+func RequestIDTransport(in http.RoundTripper) http.RoundTripper {
+	if in == nil {
+		in = http.DefaultTransport
+	}
+	return &requestIDRoundTripper{in}
+}
+
+// requestIDRoundTripper is the RoundTripper returned by RequestIDTransport.
+type requestIDRoundTripper struct {
+	transport http.RoundTripper
+}
+
+// RoundTrip sets the request ID header, if the request context carries a request ID.
+func (r *requestIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestID, ok := RequestIDFromContext(req.Context())
+	if !ok {
+		return r.transport.RoundTrip(req)
+	}
+	// RoundTrippers must not modify the given request, hence set the header on a clone
+	req = req.Clone(req.Context())
+	req.Header.Set(RequestIDHeader, requestID)
+	return r.transport.RoundTrip(req)
+}
+
+// RequestID returns the ID the provider assigned to the failed request, if the provider returned
+// one. Give this ID to the provider's support to have them look up the request.
+func (e *HTTPError) RequestID() string {
+	if e.Response == nil {
+		return ""
+	}
+	// GitHub sets its own header, GitLab sets the conventional one
+	if requestID := e.Response.Header.Get(gitHubRequestIDHeader); len(requestID) != 0 {
+		return requestID
+	}
+	return e.Response.Header.Get(RequestIDHeader)
+}
+
+// RequestIDFromError returns the ID the provider assigned to the failed request, if err contains
+// an HTTP error for which the provider returned one. Otherwise, an empty string is returned.
+func RequestIDFromError(err error) string {
+	var httpErr *HTTPError
+	var rateLimitErr *RateLimitError
+	var validationErr *ValidationError
+	var credentialsErr *InvalidCredentialsError
+	switch {
+	case errors.As(err, &httpErr):
+		return httpErr.RequestID()
+	case errors.As(err, &rateLimitErr):
+		return rateLimitErr.RequestID()
+	case errors.As(err, &validationErr):
+		return validationErr.RequestID()
+	case errors.As(err, &credentialsErr):
+		return credentialsErr.RequestID()
+	}
+	return ""
+}
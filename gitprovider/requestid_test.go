@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+type headerRecorder struct {
+	header http.Header
+}
+
+func (r *headerRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.header = req.Header
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func TestRequestIDTransport(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{
+			name: "request ID in context",
+			ctx:  WithRequestID(context.Background(), "trace-1"),
+			want: "trace-1",
+		},
+		{
+			name: "no request ID in context",
+			ctx:  context.Background(),
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &headerRecorder{}
+			req, err := http.NewRequestWithContext(tt.ctx, http.MethodGet, "https://example.com", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := RequestIDTransport(recorder).RoundTrip(req); err != nil {
+				t.Fatal(err)
+			}
+			if got := recorder.header.Get(RequestIDHeader); got != tt.want {
+				t.Errorf("RequestIDTransport() header = %q, want %q", got, tt.want)
+			}
+			if len(req.Header) != 0 {
+				t.Errorf("RequestIDTransport() modified the given request")
+			}
+		})
+	}
+}
+
+func TestRequestIDFromError(t *testing.T) {
+	responseWithHeader := func(key, value string) *http.Response {
+		return &http.Response{Header: http.Header{key: []string{value}}}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "GitHub request ID",
+			err: validation.NewMultiError(errors.New("foo"), &HTTPError{
+				Response: responseWithHeader("X-Github-Request-Id", "gh-1"),
+			}),
+			want: "gh-1",
+		},
+		{
+			name: "GitLab request ID in extended error",
+			err: validation.NewMultiError(errors.New("foo"), &RateLimitError{HTTPError: HTTPError{
+				Response: responseWithHeader("X-Request-Id", "gl-1"),
+			}}),
+			want: "gl-1",
+		},
+		{
+			name: "no HTTP error",
+			err:  ErrNotFound,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RequestIDFromError(tt.err); got != tt.want {
+				t.Errorf("RequestIDFromError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package gitprovider
 
+import "context"
+
 // Organization represents an organization in a Git provider.
 // For now, the organization is read-only, i.e. there aren't set/update methods.
 type Organization interface {
@@ -28,8 +30,73 @@ type Organization interface {
 	// Get returns high-level information about the organization.
 	Get() OrganizationInfo
 
+	// GetMetadata fetches the profile of the organization, i.e. its display name, description,
+	// avatar, website and member count. Fields that the provider doesn't supply are nil.
+	//
+	// The internal API object will be overridden with the received server data.
+	GetMetadata(ctx context.Context) (OrganizationMetadata, error)
+
+	// SetMetadata updates the profile of the organization to the set fields of metadata, and leaves
+	// the other fields as-is. Only the display name, description and website can be set, fields that
+	// the provider doesn't support setting are ignored. The lengths of the fields are validated
+	// against the limits of the provider. This is a no-op if the set fields already are the actual state.
+	//
+	// This is not supported in Bitbucket and Gitea.
+	SetMetadata(ctx context.Context, metadata OrganizationMetadata) error
+
 	// Teams gives access to the TeamsClient for this specific organization
 	Teams() TeamsClient
+
+	// ActionsSecrets gives access to the organization-wide CI secrets of this specific organization.
+	ActionsSecrets() OrgActionsSecretsClient
+
+	// StreamAuditLog calls fn for each event in the audit log of this organization, in chronological
+	// order, fetching one page at a time. If fn returns an error, streaming stops and that error is
+	// returned. This requires admin access and a suitable plan, otherwise ErrInsufficientScope is returned.
+	//
+	// This is not supported in GitLab.
+	StreamAuditLog(ctx context.Context, opts AuditLogOptions, fn func(AuditEvent) error) error
+
+	// GetDefaultBranchName returns the name of the initial branch of new repositories in this
+	// organization. An empty string is returned if the organization inherits the instance default.
+	//
+	// This is not supported in GitHub.
+	GetDefaultBranchName(ctx context.Context) (string, error)
+
+	// SetDefaultBranchName sets the name of the initial branch of new repositories in this
+	// organization. The name must be a valid Git branch name.
+	// This is a no-op if the name already is the actual state.
+	//
+	// This is not supported in GitHub.
+	SetDefaultBranchName(ctx context.Context, name string) error
+
+	// SetRepositoryCreationLevel sets who can create repositories in this organization.
+	// This is a no-op if the level already is the actual state.
+	//
+	// This is not supported in GitHub.
+	SetRepositoryCreationLevel(ctx context.Context, level RepositoryCreationLevel) error
+
+	// ListPATRequests lists the pending requests to access this organization with a fine-grained
+	// personal access token, along with the requested permissions. This requires the organization to
+	// require approval of such tokens, and admin access, otherwise ErrInsufficientScope is returned.
+	//
+	// This is not supported in GitLab.
+	//
+	// ListPATRequests returns all available requests, using multiple paginated requests if needed.
+	ListPATRequests(ctx context.Context) ([]PATRequest, error)
+
+	// ApprovePATRequest approves the pending fine-grained personal access token request with the
+	// given ID, granting the token the requested access.
+	//
+	// This is not supported in GitLab.
+	ApprovePATRequest(ctx context.Context, id int64) error
+
+	// DenyPATRequest denies the pending fine-grained personal access token request with the given ID,
+	// revoking the token's access to this organization. This requires destructive actions to be
+	// enabled in the client.
+	//
+	// This is not supported in GitLab.
+	DenyPATRequest(ctx context.Context, id int64) error
 }
 
 // Team represents a team in an organization in a Git provider.
@@ -52,6 +119,8 @@ type UserRepository interface {
 	Object
 	// The repository can be updated.
 	Updatable
+	// The repository can be refreshed.
+	Refreshable
 	// The repository can be reconciled.
 	Reconcilable
 	// The repository can be deleted.
@@ -67,6 +136,173 @@ type UserRepository interface {
 
 	// DeployKeys gives access to manipulating deploy keys to access this specific repository.
 	DeployKeys() DeployKeyClient
+
+	// Releases gives access to the releases of this specific repository.
+	Releases() ReleaseClient
+
+	// Commits gives access to the commits of this specific repository.
+	Commits() CommitClient
+
+	// Branches gives access to the branches of this specific repository.
+	Branches() BranchClient
+
+	// Tags gives access to the tags of this specific repository.
+	Tags() TagClient
+
+	// Issues gives access to the issues of this specific repository.
+	Issues() IssueClient
+
+	// Hooks gives access to the webhooks of this specific repository.
+	Hooks() RepositoryHookClient
+
+	// Rulesets gives access to the rulesets of this specific repository.
+	Rulesets() RulesetClient
+
+	// BranchProtection gives access to the branch protection rules of this specific repository.
+	BranchProtection() BranchProtectionClient
+
+	// ListSecurityAdvisories lists the security advisories filed for this repository, optionally
+	// filtered by state. This is not part of Get(), as it requires (possibly many) extra requests.
+	//
+	// This is not supported in GitLab.
+	//
+	// ListSecurityAdvisories returns all available advisories, using multiple paginated requests if needed.
+	ListSecurityAdvisories(ctx context.Context, opts SecurityAdvisoryListOptions) ([]SecurityAdvisoryInfo, error)
+
+	// SetPipelineRequirements configures what CI results are required before changes can be merged.
+	// This is a no-op if the requirements already are the actual state.
+	//
+	// This is not supported in GitHub, where the requirements are set per branch.
+	SetPipelineRequirements(ctx context.Context, req PipelineRequirements) error
+
+	// SetIssueCloseSettings configures how issues are closed automatically when referenced.
+	// This is a no-op if the settings already are the actual state.
+	//
+	// This is not supported in GitHub, where referenced issues are always closed.
+	SetIssueCloseSettings(ctx context.Context, req IssueCloseSettings) error
+
+	// ListInstalledApps lists the apps that have been granted access to this repository, along
+	// with their permissions. Depending on the provider, this requires a specific scope or type of
+	// token, otherwise ErrInsufficientScope is returned.
+	//
+	// This is not supported in GitLab.
+	//
+	// ListInstalledApps returns all available apps, using multiple paginated requests if needed.
+	ListInstalledApps(ctx context.Context) ([]InstalledAppInfo, error)
+
+	// EffectivePermissions returns the permissions the credentials of the client actually have on
+	// this repository, mapping each permission, e.g. "contents", to its access level, e.g. "read" or
+	// "write". This allows checking for a missing permission before attempting an operation.
+	// The result is cached per repository for the lifetime of the client. Depending on the provider,
+	// this requires a specific type of token, otherwise ErrInsufficientScope is returned.
+	//
+	// This is not supported in GitLab.
+	EffectivePermissions(ctx context.Context) (map[string]string, error)
+
+	// PrimaryLanguage returns the dominant programming language of this repository, i.e. the
+	// one with the largest share of the code, as detected by the provider.
+	// An empty string is returned if the repository has no detectable language.
+	PrimaryLanguage(ctx context.Context) (string, error)
+
+	// IsEmpty returns true if the repository has no commits (and hence no branches) yet,
+	// e.g. to decide whether to push an initial commit.
+	//
+	// ErrNotFound is returned if the repository does not exist.
+	IsEmpty(ctx context.Context) (bool, error)
+
+	// GetMergeQueue returns the merge queue settings of the given branch.
+	// If no merge queue is configured, MergeQueueInfo.Enabled is false.
+	//
+	// This is not supported in GitLab, which has merge trains instead.
+	GetMergeQueue(ctx context.Context, branch string) (MergeQueueInfo, error)
+
+	// SetMergeQueue configures the merge queue of the given branch.
+	// This is a no-op if the settings already are the actual state.
+	//
+	// This is not supported in GitLab, which has merge trains instead.
+	SetMergeQueue(ctx context.Context, branch string, req MergeQueueInfo) error
+
+	// SetMergeTrain enables or disables merge trains, which merge the queued merge requests in order
+	// after running pipelines on their combined result. This is a no-op if enabled is the actual state.
+	// Merge trains require a premium tier, otherwise ErrFeatureNotAvailable is returned.
+	//
+	// This is not supported in GitHub, see SetMergeQueue instead.
+	SetMergeTrain(ctx context.Context, enabled bool) error
+
+	// SetWebCommitSigning configures whether pushes of commits without a verified signature are
+	// rejected, for all branches of the repository. This is a no-op if enabled is the actual state.
+	// Push rules require a premium tier, otherwise ErrFeatureNotAvailable is returned.
+	//
+	// This is not supported in GitHub, where signed commits are required per branch, through
+	// its branch protection rules.
+	SetWebCommitSigning(ctx context.Context, enabled bool) error
+
+	// TriggerMirrorSync starts syncing this mirror repository with its upstream repository right
+	// away, instead of waiting for the next scheduled sync. The sync runs in the background, use
+	// GetMirrorStatus to follow it. ErrNotMirror is returned if this repository isn't a pull mirror.
+	//
+	// This is not supported in GitHub.
+	TriggerMirrorSync(ctx context.Context) error
+
+	// GetMirrorStatus returns the state of the last sync of this mirror repository, including why it
+	// failed, if so. ErrNotMirror is returned if this repository isn't a pull mirror.
+	//
+	// This is not supported in GitHub.
+	GetMirrorStatus(ctx context.Context) (MirrorStatusInfo, error)
+
+	// CountOpenIssues returns the amount of open issues in this repository, without fetching the
+	// issues themselves. Pull requests are not counted. 0 is returned if the issue tracker is disabled.
+	CountOpenIssues(ctx context.Context) (int64, error)
+
+	// CountOpenPullRequests returns the amount of open pull requests (merge requests in GitLab) in
+	// this repository, without fetching the pull requests themselves. 0 is returned if pull requests
+	// are disabled.
+	CountOpenPullRequests(ctx context.Context) (int64, error)
+
+	// ListProtectedEnvironments lists the protected environments of this repository, along with
+	// the roles that are allowed to deploy to them.
+	//
+	// This is not supported in GitHub, which gates deployments through environment reviewers.
+	//
+	// ListProtectedEnvironments returns all available environments, using multiple paginated requests if needed.
+	ListProtectedEnvironments(ctx context.Context) ([]ProtectedEnvironmentInfo, error)
+
+	// ProtectEnvironment makes sure the environment with the name of req is protected, and that
+	// only the roles in req can deploy to it. This is a no-op if req already is the actual state.
+	//
+	// This is not supported in GitHub, which gates deployments through environment reviewers.
+	ProtectEnvironment(ctx context.Context, req ProtectedEnvironmentInfo) error
+
+	// UnprotectEnvironment removes the protection of the environment with the given name, allowing
+	// everyone with write access to deploy to it. This requires destructive actions to be enabled
+	// in the client.
+	//
+	// ErrNotFound is returned if the environment isn't protected.
+	//
+	// This is not supported in GitHub, which gates deployments through environment reviewers.
+	UnprotectEnvironment(ctx context.Context, name string) error
+
+	// SetSubscription configures what notifications the authenticated user gets about this
+	// repository. This is a no-op if req already is the actual state.
+	SetSubscription(ctx context.Context, req SubscriptionInfo) error
+
+	// GetCommunityHealthFile returns the content of the given community health file on the default
+	// branch, looked up at the paths of CommunityHealthFilePaths, in order.
+	//
+	// ErrNotFound is returned if the repository doesn't have the file.
+	GetCommunityHealthFile(ctx context.Context, file CommunityHealthFile) (string, error)
+
+	// SetCommunityHealthFile commits content as the given community health file to the default branch.
+	// An existing file is updated where it is. Otherwise the file is created in the .github directory
+	// in GitHub, and in the root in GitLab, which only recognizes the files there. The other files are
+	// left as-is. This is a no-op if content already is the content of the file.
+	SetCommunityHealthFile(ctx context.Context, file CommunityHealthFile, content string) error
+
+	// CommunityHealthScore returns the community health score of this repository, and the community
+	// health files it has. In GitHub, the score is the one of the community profile of the repository.
+	// GitLab has no such score, hence it's computed from the share of the community health files the
+	// repository has.
+	CommunityHealthScore(ctx context.Context) (CommunityHealthInfo, error)
 }
 
 // OrgRepository describes a repository owned by an organization.
@@ -99,6 +335,132 @@ type DeployKey interface {
 	Set(DeployKeyInfo) error
 }
 
+// RepositoryHook represents a webhook delivering the events of a repository to an URL.
+type RepositoryHook interface {
+	// RepositoryHook implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+	// The webhook can be updated.
+	Updatable
+	// The webhook can be reconciled.
+	Reconcilable
+	// The webhook can be deleted.
+	Deletable
+	// RepositoryBound returns repository reference details.
+	RepositoryBound
+
+	// Get returns high-level information about this webhook.
+	Get() RepositoryHookInfo
+	// Set sets high-level desired state for this webhook. In order to apply these changes in
+	// the Git provider, run .Update() or .Reconcile().
+	Set(RepositoryHookInfo) error
+}
+
+// BranchProtection represents the protection rules of a branch.
+type BranchProtection interface {
+	// BranchProtection implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+	// The branch protection can be updated.
+	Updatable
+	// The branch protection can be reconciled.
+	Reconcilable
+	// The branch protection can be deleted.
+	Deletable
+	// RepositoryBound returns repository reference details.
+	RepositoryBound
+
+	// Get returns high-level information about this branch protection.
+	Get() BranchProtectionInfo
+	// Set sets high-level desired state for this branch protection. In order to apply these changes in
+	// the Git provider, run .Update() or .Reconcile().
+	Set(BranchProtectionInfo) error
+}
+
+// Ruleset represents a named set of rules applying to some refs of a repository.
+type Ruleset interface {
+	// Ruleset implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+	// The ruleset can be updated.
+	Updatable
+	// The ruleset can be reconciled.
+	Reconcilable
+	// RepositoryBound returns repository reference details.
+	RepositoryBound
+
+	// Get returns high-level information about this ruleset.
+	Get() RulesetInfo
+	// Set sets high-level desired state for this ruleset. In order to apply these changes in
+	// the Git provider, run .Update() or .Reconcile().
+	Set(RulesetInfo) error
+}
+
+// Branch represents a branch in a repository.
+// The branch is read-only, i.e. there aren't set/update methods.
+type Branch interface {
+	// Branch implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+	// RepositoryBound returns repository reference details.
+	RepositoryBound
+
+	// Get returns high-level information about this branch.
+	Get() BranchInfo
+}
+
+// Tag represents a tag in a repository.
+// The tag is read-only, i.e. there aren't set/update methods.
+type Tag interface {
+	// Tag implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+	// RepositoryBound returns repository reference details.
+	RepositoryBound
+
+	// Get returns high-level information about this tag.
+	Get() TagInfo
+}
+
+// Commit represents a commit in a repository.
+// The commit is read-only, i.e. there aren't set/update methods.
+type Commit interface {
+	// Commit implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+	// RepositoryBound returns repository reference details.
+	RepositoryBound
+
+	// Get returns high-level information about this commit.
+	Get() CommitInfo
+}
+
+// Release represents a release of a repository.
+// For now, the release is read-only, i.e. there aren't set/update methods.
+type Release interface {
+	// Release implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+	// RepositoryBound returns repository reference details.
+	RepositoryBound
+
+	// Get returns high-level information about this release.
+	Get() ReleaseInfo
+}
+
+// Issue represents an issue in a repository.
+// The issue is read-only, i.e. there aren't set/update methods.
+type Issue interface {
+	// Issue implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+	// RepositoryBound returns repository reference details.
+	RepositoryBound
+
+	// Get returns high-level information about this issue.
+	Get() IssueInfo
+}
+
 // TeamAccess describes a binding between a repository and a team.
 type TeamAccess interface {
 	// TeamAccess implements the Object interface,
@@ -0,0 +1,277 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryBudget is a token bucket limiting the amount of retries across all requests of a client.
+// Each retry takes one token from the bucket, and the bucket is refilled at a constant rate up to
+// its size. When the bucket is empty, requests are not retried, and their error is returned
+// immediately. This keeps the client from causing retry storms when a provider is struggling,
+// while still retrying occasional failures.
+//
+// A RetryBudget is safe for concurrent use.
+type RetryBudget struct {
+	size            float64
+	refillPerSecond float64
+
+	mu         sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+	// now returns the current time, it can be replaced in unit tests.
+	now func() time.Time
+}
+
+// NewRetryBudget creates a full RetryBudget allowing size retries in a burst, refilled with
+// refillPerSecond retries per second. size must be positive, and refillPerSecond must not be
+// negative, otherwise an error wrapping ErrInvalidArgument is returned.
+func NewRetryBudget(size int, refillPerSecond float64) (*RetryBudget, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("retry budget size must be positive, got %d: %w", size, ErrInvalidArgument)
+	}
+	if refillPerSecond < 0 {
+		return nil, fmt.Errorf("retry budget refill rate must not be negative, got %v: %w", refillPerSecond, ErrInvalidArgument)
+	}
+	return &RetryBudget{
+		size:            float64(size),
+		refillPerSecond: refillPerSecond,
+		tokens:          float64(size),
+		lastRefill:      time.Now(),
+		now:             time.Now,
+	}, nil
+}
+
+// TryAcquire takes one retry from the budget, and returns true if there was one left.
+// If false is returned, the request must not be retried.
+func (b *RetryBudget) TryAcquire() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// refill adds the tokens accumulated since the last refill, up to the size of the bucket.
+// The caller must hold the lock.
+func (b *RetryBudget) refill() {
+	now := b.now()
+	b.tokens += now.Sub(b.lastRefill).Seconds() * b.refillPerSecond
+	if b.tokens > b.size {
+		b.tokens = b.size
+	}
+	b.lastRefill = now
+}
+
+// RetryPredicate decides whether a request is retried, given its response, or the error
+// returned by the transport if the request failed without a response (resp is nil then).
+type RetryPredicate func(req *http.Request, resp *http.Response, err error) bool
+
+// DefaultRetryPredicate retries idempotent requests (GET, HEAD and OPTIONS) that failed with a
+// retryable response, see IsRetryableResponse. Other requests, e.g. POST, are never retried, as
+// they might have been applied by the provider already.
+func DefaultRetryPredicate(req *http.Request, resp *http.Response, err error) bool {
+	return isIdempotent(req) && IsRetryableResponse(resp, err)
+}
+
+// RetryOnStatusCodes returns a RetryPredicate retrying idempotent requests (GET, HEAD and OPTIONS)
+// that failed with a transport error, or with one of the given status codes. It can be used instead
+// of DefaultRetryPredicate, e.g. for proxies signaling throttling with a non-standard status code.
+func RetryOnStatusCodes(codes []int) RetryPredicate {
+	retryable := make(map[int]bool, len(codes))
+	for _, code := range codes {
+		retryable[code] = true
+	}
+	return func(req *http.Request, resp *http.Response, err error) bool {
+		if !isIdempotent(req) {
+			return false
+		}
+		return err != nil || retryable[resp.StatusCode]
+	}
+}
+
+// ValidateRetryableStatusCodes makes sure codes is non-empty, and only contains valid HTTP
+// status codes, i.e. in the range 100-599. Otherwise, an error wrapping ErrInvalidArgument is returned.
+func ValidateRetryableStatusCodes(codes []int) error {
+	if len(codes) == 0 {
+		return fmt.Errorf("retryable status codes cannot be empty: %w", ErrInvalidArgument)
+	}
+	for _, code := range codes {
+		if code < minStatusCode || code > maxStatusCode {
+			return fmt.Errorf("retryable status code %d is not in the range %d-%d: %w",
+				code, minStatusCode, maxStatusCode, ErrInvalidArgument)
+		}
+	}
+	return nil
+}
+
+const (
+	// minStatusCode and maxStatusCode are the bounds of the valid HTTP status codes.
+	minStatusCode = 100
+	maxStatusCode = 599
+)
+
+// isIdempotent returns true if the method of req is GET, HEAD or OPTIONS.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryableResponse returns true if the request failed transiently, i.e. with a transport error,
+// a 5xx status, 429 Too Many Requests, or 403 Forbidden with a Retry-After header, which is how
+// GitHub signals secondary rate limits. It can be used to build a RetryPredicate for other methods.
+func IsRetryableResponse(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return true
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode == http.StatusForbidden:
+		return resp.Header.Get("Retry-After") != ""
+	default:
+		return false
+	}
+}
+
+// RetryTransport returns a ChainableRoundTripperFunc retrying the requests shouldRetry allows up to
+// maxRetries times. The delay before the n-th retry is baseDelay * 2^(n-1), unless the response
+// specifies a Retry-After header, which is honored instead. Waiting stops when the context of the
+// request is done, returning its error. If shouldRetry is nil, DefaultRetryPredicate is used.
+//
+// If budget is non-nil, every retry takes a token from it, and once it is exhausted the last response
+// (or error) is returned without retrying. The budget can be shared between transports, to limit the
+// retries across all requests of a client.
+//
+// Requests with a body are only retried if the body can be recreated, i.e. http.Request.GetBody is set.
+func RetryTransport(maxRetries int, baseDelay time.Duration, shouldRetry RetryPredicate, budget *RetryBudget) ChainableRoundTripperFunc {
+	if shouldRetry == nil {
+		shouldRetry = DefaultRetryPredicate
+	}
+	return func(in http.RoundTripper) http.RoundTripper {
+		if in == nil {
+			in = http.DefaultTransport
+		}
+		return &retryTransport{
+			next:        in,
+			maxRetries:  maxRetries,
+			baseDelay:   baseDelay,
+			shouldRetry: shouldRetry,
+			budget:      budget,
+			sleep:       sleepContext,
+		}
+	}
+}
+
+type retryTransport struct {
+	next        http.RoundTripper
+	maxRetries  int
+	baseDelay   time.Duration
+	shouldRetry RetryPredicate
+	// budget limits the retries, if set.
+	budget *RetryBudget
+	// sleep waits for d, or until ctx is done. It can be replaced in unit tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attemptReq := req
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(attemptReq)
+		// A request with a body can only be retried if the body can be read again
+		canRetry := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+		if attempt == t.maxRetries || !canRetry || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		// Only take from the budget when the request would be retried otherwise
+		if t.budget != nil && !t.budget.TryAcquire() {
+			return resp, err
+		}
+
+		delay := t.retryDelay(resp, attempt)
+		if resp != nil {
+			// Drain the body, so the connection can be reused
+			_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxDrainedBodySize))
+			resp.Body.Close()
+		}
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		attemptReq = req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq.Body = body
+		}
+	}
+}
+
+// maxDrainedBodySize is the maximum amount of bytes read from the body of a response
+// that is retried. Larger bodies are closed without reading them to the end.
+const maxDrainedBodySize = 4096
+
+// retryDelay returns how long to wait before retrying after the given attempt (0-based).
+// The Retry-After header is honored if resp has one, either in seconds or as a HTTP date.
+func (t *retryTransport) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if resp != nil {
+		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+			if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+				return time.Duration(seconds) * time.Second
+			}
+			if date, err := http.ParseTime(retryAfter); err == nil {
+				if delay := time.Until(date); delay > 0 {
+					return delay
+				}
+				return 0
+			}
+		}
+	}
+	return t.baseDelay << uint(attempt)
+}
+
+// sleepContext waits for d, or until ctx is done, returning its error.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryBudget(t *testing.T) {
+	budget, err := NewRetryBudget(2, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(0, 0)
+	budget.lastRefill = now
+	budget.now = func() time.Time { return now }
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		want    bool
+	}{
+		{name: "full budget, first retry", want: true},
+		{name: "full budget, second retry", want: true},
+		{name: "exhausted", want: false},
+		{name: "partially refilled", advance: time.Second, want: false},
+		{name: "refilled one token", advance: time.Second, want: true},
+		{name: "exhausted again", want: false},
+		{name: "refill is capped at the size", advance: time.Hour, want: true},
+		{name: "second token after cap", want: true},
+		{name: "no third token after cap", want: false},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		if got := budget.TryAcquire(); got != step.want {
+			t.Errorf("%s: TryAcquire() = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestNewRetryBudget(t *testing.T) {
+	tests := []struct {
+		name            string
+		size            int
+		refillPerSecond float64
+		expectedErr     error
+	}{
+		{
+			name:            "valid",
+			size:            10,
+			refillPerSecond: 1,
+		},
+		{
+			name:            "valid, never refilled",
+			size:            10,
+			refillPerSecond: 0,
+		},
+		{
+			name:            "invalid size",
+			size:            0,
+			refillPerSecond: 1,
+			expectedErr:     ErrInvalidArgument,
+		},
+		{
+			name:            "invalid refill rate",
+			size:            10,
+			refillPerSecond: -1,
+			expectedErr:     ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRetryBudget(tt.size, tt.refillPerSecond); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("NewRetryBudget() error = %v, want %v", err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+// scriptedRoundTripper returns the scripted status codes in order, recording the request bodies.
+type scriptedRoundTripper struct {
+	statuses   []int
+	retryAfter string
+	bodies     []string
+}
+
+func (rt *scriptedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = string(b)
+	}
+	rt.bodies = append(rt.bodies, body)
+	status := rt.statuses[len(rt.bodies)-1]
+	resp := &http.Response{StatusCode: status, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader(""))}
+	if status != http.StatusOK && rt.retryAfter != "" {
+		resp.Header.Set("Retry-After", rt.retryAfter)
+	}
+	return resp, nil
+}
+
+func TestRetryTransport(t *testing.T) {
+	retryPOST := func(_ *http.Request, resp *http.Response, err error) bool {
+		return IsRetryableResponse(resp, err)
+	}
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		statuses    []int
+		retryAfter  string
+		shouldRetry RetryPredicate
+		wantStatus  int
+		wantDelays  []time.Duration
+	}{
+		{
+			name:       "retry 5xx with exponential backoff",
+			method:     http.MethodGet,
+			statuses:   []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantStatus: http.StatusOK,
+			wantDelays: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:       "give up after the max retries",
+			method:     http.MethodGet,
+			statuses:   []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			wantStatus: http.StatusBadGateway,
+			wantDelays: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name:       "honor Retry-After of a secondary rate limit",
+			method:     http.MethodGet,
+			statuses:   []int{http.StatusForbidden, http.StatusOK},
+			retryAfter: "7",
+			wantStatus: http.StatusOK,
+			wantDelays: []time.Duration{7 * time.Second},
+		},
+		{
+			name:       "don't retry 403 without Retry-After",
+			method:     http.MethodGet,
+			statuses:   []int{http.StatusForbidden},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:        "retry custom status codes",
+			method:      http.MethodGet,
+			statuses:    []int{420, http.StatusOK},
+			shouldRetry: RetryOnStatusCodes([]int{420}),
+			wantStatus:  http.StatusOK,
+			wantDelays:  []time.Duration{time.Second},
+		},
+		{
+			name:        "don't retry status codes other than the custom ones",
+			method:      http.MethodGet,
+			statuses:    []int{http.StatusBadGateway},
+			shouldRetry: RetryOnStatusCodes([]int{420}),
+			wantStatus:  http.StatusBadGateway,
+		},
+		{
+			name:       "don't retry POST by default",
+			method:     http.MethodPost,
+			body:       "{}",
+			statuses:   []int{http.StatusBadGateway},
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:        "retry POST if opted in, resending the body",
+			method:      http.MethodPost,
+			body:        "{}",
+			statuses:    []int{http.StatusTooManyRequests, http.StatusOK},
+			shouldRetry: retryPOST,
+			wantStatus:  http.StatusOK,
+			wantDelays:  []time.Duration{time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &scriptedRoundTripper{statuses: tt.statuses, retryAfter: tt.retryAfter}
+			rt := RetryTransport(3, time.Second, tt.shouldRetry, nil)(fake).(*retryTransport)
+			delays := []time.Duration{}
+			rt.sleep = func(_ context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return nil
+			}
+
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req, err := http.NewRequest(tt.method, "https://api.github.com/repos/foo/bar", body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := rt.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("RoundTrip() status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if len(tt.wantDelays) == 0 {
+				tt.wantDelays = []time.Duration{}
+			}
+			if !reflect.DeepEqual(delays, tt.wantDelays) {
+				t.Errorf("delays = %v, want %v", delays, tt.wantDelays)
+			}
+			for i, got := range fake.bodies {
+				if got != tt.body {
+					t.Errorf("body of attempt %d = %q, want %q", i, got, tt.body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransport_contextCanceled(t *testing.T) {
+	fake := &scriptedRoundTripper{statuses: []int{http.StatusBadGateway, http.StatusOK}}
+	rt := RetryTransport(3, time.Hour, nil, nil)(fake)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/repos/foo/bar", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rt.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("RoundTrip() error = %v, want %v", err, context.Canceled)
+	}
+	if len(fake.bodies) != 1 {
+		t.Errorf("got %d attempts, want 1", len(fake.bodies))
+	}
+}
+
+func TestRetryTransport_budget(t *testing.T) {
+	budget, err := NewRetryBudget(2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &scriptedRoundTripper{statuses: []int{
+		http.StatusBadGateway, http.StatusBadGateway, http.StatusOK,
+		http.StatusServiceUnavailable,
+	}}
+	rt := RetryTransport(3, time.Second, nil, budget)(fake).(*retryTransport)
+	rt.sleep = func(context.Context, time.Duration) error { return nil }
+
+	roundTrip := func() int {
+		req, err := http.NewRequest(http.MethodGet, "https://api.github.com/repos/foo/bar", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	// The first request takes the whole budget
+	if got := roundTrip(); got != http.StatusOK {
+		t.Errorf("RoundTrip() status = %d, want %d", got, http.StatusOK)
+	}
+	// The second one isn't retried, and returns its failed response
+	if got := roundTrip(); got != http.StatusServiceUnavailable {
+		t.Errorf("RoundTrip() status = %d, want %d", got, http.StatusServiceUnavailable)
+	}
+	if len(fake.bodies) != 4 {
+		t.Errorf("got %d attempts, want 4", len(fake.bodies))
+	}
+}
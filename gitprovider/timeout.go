@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// RequestTimeoutTransport returns a ChainableRoundTripperFunc giving each request that passes it a
+// context with the given timeout, derived from the request's context. A shorter deadline of the
+// request's context is kept. The timeout covers reading the response body too, as the derived
+// context is only released once the body is closed. Chained "after" RetryTransport, every retry
+// gets its own timeout.
+func RequestTimeoutTransport(timeout time.Duration) ChainableRoundTripperFunc {
+	return func(in http.RoundTripper) http.RoundTripper {
+		if in == nil {
+			in = http.DefaultTransport
+		}
+		return &requestTimeoutRoundTripper{transport: in, timeout: timeout}
+	}
+}
+
+// requestTimeoutRoundTripper is the RoundTripper returned by RequestTimeoutTransport.
+type requestTimeoutRoundTripper struct {
+	transport http.RoundTripper
+	timeout   time.Duration
+}
+
+// RoundTrip sends the request with a context with the timeout.
+func (r *requestTimeoutRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// context.WithTimeout keeps the deadline of the parent context, if that's earlier
+	ctx, cancel := context.WithTimeout(req.Context(), r.timeout)
+	resp, err := r.transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnCloseBody releases the context of a request once its response body is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body, and releases the context.
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// slowRoundTripper responds after delay, like a slow or hung server, unless the request context is
+// done first. The response body fails to be read once the request context is done.
+type slowRoundTripper struct {
+	delay time.Duration
+}
+
+func (rt *slowRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case <-time.After(rt.delay):
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: &contextBody{req.Context()}}, nil
+}
+
+// contextBody is a response body that reads "ok", unless ctx is done.
+type contextBody struct {
+	ctx context.Context
+}
+
+func (b *contextBody) Read(p []byte) (int, error) {
+	if err := b.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return copy(p, "ok"), io.EOF
+}
+
+func (b *contextBody) Close() error { return nil }
+
+func TestRequestTimeoutTransport(t *testing.T) {
+	tests := []struct {
+		name          string
+		delay         time.Duration
+		timeout       time.Duration
+		callerTimeout time.Duration
+		wantErr       error
+	}{
+		{
+			name:    "responds in time",
+			delay:   time.Millisecond,
+			timeout: time.Minute,
+		},
+		{
+			name:    "hung server times out",
+			delay:   time.Hour,
+			timeout: 10 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name:          "shorter caller deadline is kept",
+			delay:         time.Hour,
+			timeout:       time.Hour,
+			callerTimeout: 10 * time.Millisecond,
+			wantErr:       context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.callerTimeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.callerTimeout)
+				defer cancel()
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/repos/foo/bar", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rt := RequestTimeoutTransport(tt.timeout)(&slowRoundTripper{delay: tt.delay})
+
+			start := time.Now()
+			resp, err := rt.RoundTrip(req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RoundTrip() error = %v, want %v", err, tt.wantErr)
+			}
+			if elapsed := time.Since(start); elapsed > time.Minute {
+				t.Errorf("RoundTrip() took %v, want it to be cut short", elapsed)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			// The body must still be readable after RoundTrip returned
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("reading the body: %v", err)
+			}
+			if string(body) != "ok" {
+				t.Errorf("body = %q, want %q", body, "ok")
+			}
+			if err := resp.Body.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+		})
+	}
+}
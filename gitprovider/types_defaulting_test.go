@@ -63,6 +63,9 @@ func TestDefaulting(t *testing.T) {
 			expected: &RepositoryInfo{
 				Visibility:    RepositoryVisibilityVar(RepositoryVisibilityPrivate),
 				DefaultBranch: StringVar("master"),
+				HasIssues:     BoolVar(true),
+				HasWiki:       BoolVar(true),
+				HasProjects:   BoolVar(true),
 			},
 		},
 		{
@@ -71,10 +74,16 @@ func TestDefaulting(t *testing.T) {
 			object: &RepositoryInfo{
 				Visibility:    RepositoryVisibilityVar(RepositoryVisibilityPrivate),
 				DefaultBranch: StringVar("master"),
+				HasIssues:     BoolVar(true),
+				HasWiki:       BoolVar(true),
+				HasProjects:   BoolVar(true),
 			},
 			expected: &RepositoryInfo{
 				Visibility:    RepositoryVisibilityVar(RepositoryVisibilityPrivate),
 				DefaultBranch: StringVar("master"),
+				HasIssues:     BoolVar(true),
+				HasWiki:       BoolVar(true),
+				HasProjects:   BoolVar(true),
 			},
 		},
 		{
@@ -83,10 +92,16 @@ func TestDefaulting(t *testing.T) {
 			object: &RepositoryInfo{
 				Visibility:    RepositoryVisibilityVar(RepositoryVisibilityInternal),
 				DefaultBranch: StringVar("main"),
+				HasIssues:     BoolVar(false),
+				HasWiki:       BoolVar(false),
+				HasProjects:   BoolVar(false),
 			},
 			expected: &RepositoryInfo{
 				Visibility:    RepositoryVisibilityVar(RepositoryVisibilityInternal),
 				DefaultBranch: StringVar("main"),
+				HasIssues:     BoolVar(false),
+				HasWiki:       BoolVar(false),
+				HasProjects:   BoolVar(false),
 			},
 		},
 		{
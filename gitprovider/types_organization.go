@@ -16,6 +16,19 @@ limitations under the License.
 
 package gitprovider
 
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+const (
+	// the default visibility for organization CI secrets is to only expose them to private repositories.
+	defaultActionsSecretVisibility = ActionsSecretVisibilityPrivate
+)
+
 // OrganizationInfo represents an (top-level- or sub-) organization.
 type OrganizationInfo struct {
 	// Name is the human-friendly name of this organization, e.g. "Flux" or "Kubernetes SIGs".
@@ -25,11 +38,166 @@ type OrganizationInfo struct {
 	Description *string `json:"description"`
 }
 
+// OrganizationMetadata is the read-only profile of an organization, e.g. for displaying it in a catalog.
+// The field names are the same for all providers, fields that a provider doesn't supply are nil.
+type OrganizationMetadata struct {
+	// DisplayName is the human-friendly name of the organization.
+	DisplayName *string `json:"displayName"`
+
+	// Description is the description of the organization.
+	Description *string `json:"description"`
+
+	// AvatarURL is the URL of the avatar image of the organization.
+	AvatarURL *string `json:"avatarURL"`
+
+	// Website is the URL of the website of the organization.
+	Website *string `json:"website"`
+
+	// MemberCount is the number of members of the organization.
+	MemberCount *int `json:"memberCount"`
+}
+
+// Changes returns the fields of the desired metadata (m) that are set, and differ from the actual
+// metadata, along with whether there are any such fields. Only the display name, description and
+// website can be set; the avatar URL and member count are ignored.
+func (m OrganizationMetadata) Changes(actual OrganizationMetadata) (OrganizationMetadata, bool) {
+	changes := OrganizationMetadata{}
+	if m.DisplayName != nil && (actual.DisplayName == nil || *m.DisplayName != *actual.DisplayName) {
+		changes.DisplayName = m.DisplayName
+	}
+	if m.Description != nil && (actual.Description == nil || *m.Description != *actual.Description) {
+		changes.Description = m.Description
+	}
+	if m.Website != nil && (actual.Website == nil || *m.Website != *actual.Website) {
+		changes.Website = m.Website
+	}
+	return changes, changes.DisplayName != nil || changes.Description != nil || changes.Website != nil
+}
+
 // TeamInfo is a representation for a team of users inside of an organization.
 type TeamInfo struct {
 	// Name describes the name of the team. The team name may contain slashes.
 	Name string `json:"name"`
 
+	// Slug is the identifier of the team that is passed to TeamsClient.Get. In GitHub this is
+	// the team slug, and in GitLab the path of the subgroup relative to the organization.
+	Slug string `json:"slug"`
+
 	// Members points to a set of user names (logins) of the members of this team.
 	Members []string `json:"members"`
 }
+
+// ActionsSecretInfo describes an organization-wide CI secret.
+//
+// The secret value is write-only: it is only sent (sealed) to the server when setting the secret,
+// and is never returned from it. Value is excluded from JSON and from formatting with the fmt package.
+type ActionsSecretInfo struct {
+	// Name is the name of the secret, as it's referred to in CI configuration.
+	// +required
+	Name string `json:"name"`
+
+	// Value is the plaintext value of the secret. It is only used when setting the secret,
+	// and is always empty for secrets returned from the server.
+	Value string `json:"-"`
+
+	// Visibility specifies what repositories in the organization can access the secret.
+	// Default: private
+	Visibility *ActionsSecretVisibility `json:"visibility"`
+
+	// SelectedRepositories is the list of repository names in the organization that can access the secret.
+	// This can only be set when Visibility is "selected". It is not populated for secrets returned from
+	// the server, use ListSelectedRepositories() for that.
+	SelectedRepositories []string `json:"selectedRepositories,omitempty"`
+}
+
+// String implements fmt.Stringer, making sure the secret value is never printed.
+func (s ActionsSecretInfo) String() string {
+	visibility := ""
+	if s.Visibility != nil {
+		visibility = string(*s.Visibility)
+	}
+	return fmt.Sprintf("{Name:%s Value:<redacted> Visibility:%s SelectedRepositories:%v}", s.Name, visibility, s.SelectedRepositories)
+}
+
+// GoString implements fmt.GoStringer, making sure the secret value is never printed using %#v.
+func (s ActionsSecretInfo) GoString() string {
+	return s.String()
+}
+
+// Default defaults the ActionsSecretInfo, implementing the DefaultedInfoRequest interface.
+func (s *ActionsSecretInfo) Default() {
+	if s.Visibility == nil {
+		s.Visibility = ActionsSecretVisibilityVar(defaultActionsSecretVisibility)
+	}
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (s ActionsSecretInfo) ValidateInfo() error {
+	validator := validation.New("ActionsSecret")
+	// Make sure we've set the name of the secret
+	if len(s.Name) == 0 {
+		validator.Required("Name")
+	}
+	// Validate the Visibility enum
+	if s.Visibility != nil {
+		validator.Append(ValidateActionsSecretVisibility(*s.Visibility), *s.Visibility, "Visibility")
+	}
+	// Selected repositories only make sense with the "selected" visibility
+	if len(s.SelectedRepositories) != 0 && (s.Visibility == nil || *s.Visibility != ActionsSecretVisibilitySelected) {
+		validator.Invalid(s.SelectedRepositories, "SelectedRepositories")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument.
+func (s ActionsSecretInfo) Equals(actual InfoRequest) bool {
+	return reflect.DeepEqual(s, actual)
+}
+
+// AuditEvent describes an event in the audit log of an organization.
+// This is a read-only type, audit events are recorded by the Git provider.
+type AuditEvent struct {
+	// ID is the provider-specific unique identifier of the event.
+	ID string `json:"id"`
+
+	// Action is the kind of event, e.g. "repo.create" or "team.add_member".
+	Action string `json:"action"`
+
+	// Actor is the login of the user that caused the event. Actor might be empty
+	// for events that aren't caused by a user.
+	Actor string `json:"actor"`
+
+	// CreatedAt is the time the event happened.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Cursor is the pagination cursor of the page this event was on. Give it as
+	// AuditLogOptions.After to resume streaming from that page. As the whole page is
+	// delivered again, events might be received more than once when resuming.
+	Cursor string `json:"cursor"`
+}
+
+// PATRequest describes a pending request of an organization member to access the organization's
+// resources with a fine-grained personal access token.
+// This is a read-only type, requests are created by the Git provider when a member creates a token.
+type PATRequest struct {
+	// ID is the provider-specific unique identifier of the request.
+	ID int64 `json:"id"`
+
+	// Requester is the login of the user that created the token.
+	Requester string `json:"requester"`
+
+	// Reason is the justification given by the requester. Reason might be empty.
+	Reason string `json:"reason"`
+
+	// OrganizationPermissions maps each requested organization permission, e.g. "members",
+	// to its access level, e.g. "read" or "write".
+	OrganizationPermissions map[string]string `json:"organizationPermissions"`
+
+	// RepositoryPermissions maps each requested repository permission, e.g. "contents",
+	// to its access level, e.g. "read" or "write".
+	RepositoryPermissions map[string]string `json:"repositoryPermissions"`
+
+	// CreatedAt is the time the request was created.
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -17,7 +17,11 @@ limitations under the License.
 package gitprovider
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/dinosk/go-git-providers/validation"
 )
@@ -33,6 +37,26 @@ const (
 	defaultBranchName = "master"
 	// by default, deploy keys are read-only.
 	defaultDeployKeyReadOnly = true
+	// by default, repositories have issues, wikis and projects enabled.
+	defaultRepositoryFeatureEnabled = true
+	// by default, a merge queue merges at most 5 changes at once.
+	defaultMergeQueueBatchSize = 5
+	// the maximum batch size a merge queue accepts.
+	maxMergeQueueBatchSize = 100
+	// by default, a merge queue creates merge commits.
+	defaultMergeQueueMergeMethod = MergeQueueMergeMethodMerge
+	// by default, webhooks deliver JSON payloads.
+	defaultRepositoryHookContentType = RepositoryHookContentTypeJSON
+	// by default, webhooks are active.
+	defaultRepositoryHookActive = true
+	// by default, webhooks are subscribed to pushes.
+	defaultRepositoryHookEvent = RepositoryHookEventPush
+	// by default, rulesets apply to branches.
+	defaultRulesetTarget = RulesetTargetBranch
+	// by default, rulesets are enforced.
+	defaultRulesetEnforcement = RulesetEnforcementActive
+	// by default, a branch protection template is applied to 4 repositories at once.
+	defaultBranchProtectionTemplateConcurrency = 4
 )
 
 // RepositoryInfo implements InfoRequest and DefaultedInfoRequest (with a pointer receiver).
@@ -57,6 +81,52 @@ type RepositoryInfo struct {
 	// Default value at POST-time: RepositoryVisibilityPrivate.
 	// +optional
 	Visibility *RepositoryVisibility `json:"visibility"`
+
+	// HasIssues describes whether the issue tracker is enabled for the repository.
+	// In GitLab, this maps to "issues_enabled".
+	// Default value at POST-time: true.
+	// +optional
+	HasIssues *bool `json:"hasIssues"`
+
+	// HasWiki describes whether the wiki is enabled for the repository.
+	// In GitLab, this maps to "wiki_enabled".
+	// Default value at POST-time: true.
+	// +optional
+	HasWiki *bool `json:"hasWiki"`
+
+	// HasProjects describes whether project boards are enabled for the repository.
+	// GitLab has no equivalent, hence this field is ignored there.
+	// Default value at POST-time: true.
+	// +optional
+	HasProjects *bool `json:"hasProjects"`
+
+	// AllowForking describes whether the repository can be forked.
+	// In GitHub, forking can only be disallowed for private and internal repositories.
+	// In GitLab, this maps to "forking_access_level", where true means "enabled" and false means
+	// "disabled". The "private" level (only members can fork) is read as true, and is left as-is
+	// when true is desired.
+	// Default value at POST-time: nil (which means the provider's default is used, and
+	// that the field is not reconciled).
+	// +optional
+	AllowForking *bool `json:"allowForking"`
+
+	// Topics lists the topics the repository is labeled with. The topics are a set, hence they
+	// are sorted and lowercased like GitHub does before being sent, see NormalizeTopics(). Each
+	// topic must be valid in GitHub, see ValidateTopic(). In GitLab, topics are called tags.
+	// This field is only supported in GitHub and GitLab (yet), and is ignored by the other providers.
+	// Default value at POST-time: nil (which means the repository has no topics, and that the
+	// field is not reconciled).
+	// +optional
+	Topics []string `json:"topics"`
+
+	// Archived describes whether the repository is archived, i.e. read-only.
+	// In GitHub, archived repositories refuse most other changes, hence a repository is unarchived
+	// before, and archived after, its other fields are updated. Archiving a repository while
+	// changing other fields in the same Set() is invalid.
+	// This field is only supported in GitHub (yet), and is ignored by the other providers.
+	// Default value at POST-time: nil (which means the field is not reconciled).
+	// +optional
+	Archived *bool `json:"archived"`
 }
 
 // Default defaults the Repository, implementing the InfoRequest interface.
@@ -67,6 +137,15 @@ func (r *RepositoryInfo) Default() {
 	if r.DefaultBranch == nil {
 		r.DefaultBranch = StringVar(defaultBranchName)
 	}
+	if r.HasIssues == nil {
+		r.HasIssues = BoolVar(defaultRepositoryFeatureEnabled)
+	}
+	if r.HasWiki == nil {
+		r.HasWiki = BoolVar(defaultRepositoryFeatureEnabled)
+	}
+	if r.HasProjects == nil {
+		r.HasProjects = BoolVar(defaultRepositoryFeatureEnabled)
+	}
 }
 
 // ValidateInfo validates the object at {Object}.Set() and POST-time.
@@ -76,13 +155,71 @@ func (r RepositoryInfo) ValidateInfo() error {
 	if r.Visibility != nil {
 		validator.Append(ValidateRepositoryVisibility(*r.Visibility), *r.Visibility, "Visibility")
 	}
+	for _, topic := range r.Topics {
+		validator.Append(ValidateTopic(topic), topic, "Topics")
+	}
 	return validator.Error()
 }
 
 // Equals can be used to check if this *Info request (the desired state) matches the actual
-// passed in as the argument.
+// passed in as the argument. Both sides are normalized first, see Normalized().
 func (r RepositoryInfo) Equals(actual InfoRequest) bool {
-	return reflect.DeepEqual(r, actual)
+	actualInfo, ok := actual.(RepositoryInfo)
+	if !ok {
+		return false
+	}
+	return reflect.DeepEqual(r.Normalized(), actualInfo.Normalized())
+}
+
+// Normalized returns a copy of the RepositoryInfo, where the fields Git providers are known to
+// canonicalize server-side are normalized, in order to avoid detecting drift (and updating the
+// repository) on every reconcile. The following fields are normalized:
+//
+// - Description: see NormalizeDescription().
+// - DefaultBranch: see NormalizeBranchName().
+// - Topics: see NormalizeTopics().
+func (r RepositoryInfo) Normalized() RepositoryInfo {
+	if r.Description != nil {
+		r.Description = StringVar(NormalizeDescription(*r.Description))
+	}
+	if r.DefaultBranch != nil {
+		r.DefaultBranch = StringVar(NormalizeBranchName(*r.DefaultBranch))
+	}
+	if r.Topics != nil {
+		r.Topics = NormalizeTopics(r.Topics)
+	}
+	return r
+}
+
+// NormalizeDescription returns the description with leading and trailing whitespace
+// trimmed, as Git providers do when storing it. Use for comparisons only.
+func NormalizeDescription(description string) string {
+	return strings.TrimSpace(description)
+}
+
+// NormalizeBranchName returns the branch name in lower case, as Git providers may change
+// the casing of the default branch. Use for comparisons only.
+func NormalizeBranchName(branch string) string {
+	return strings.ToLower(branch)
+}
+
+// NormalizeTopics returns a sorted copy of the topics in lower case, without duplicates, as GitHub
+// lowercases topics when storing them, and their order doesn't matter. Unlike the other Normalize
+// functions, the result is also what is sent to the server, so that the desired topics are stored
+// as-is, and never differ from the actual ones.
+func NormalizeTopics(topics []string) []string {
+	normalized := make([]string, 0, len(topics))
+	seen := make(map[string]struct{}, len(topics))
+	for _, topic := range topics {
+		topic = strings.ToLower(topic)
+		if _, ok := seen[topic]; ok {
+			continue
+		}
+		seen[topic] = struct{}{}
+		normalized = append(normalized, topic)
+	}
+	sort.Strings(normalized)
+	return normalized
 }
 
 // TeamAccessInfo implements InfoRequest and DefaultedInfoRequest (with a pointer receiver).
@@ -174,6 +311,788 @@ func (dk DeployKeyInfo) ValidateInfo() error {
 
 // Equals can be used to check if this *Info request (the desired state) matches the actual
 // passed in as the argument.
+// The public key material is compared using HasSameKey, as the Git provider might not store the
+// comment of the key.
 func (dk DeployKeyInfo) Equals(actual InfoRequest) bool {
-	return reflect.DeepEqual(dk, actual)
+	other, ok := actual.(DeployKeyInfo)
+	if !ok {
+		return false
+	}
+	return dk.Name == other.Name && reflect.DeepEqual(dk.ReadOnly, other.ReadOnly) && dk.HasSameKey(other)
+}
+
+// HasSameKey returns true if dk and other have the same public key material. Only the key type
+// and the encoded key are compared, as the Git provider might not store the comment of the key.
+func (dk DeployKeyInfo) HasSameKey(other DeployKeyInfo) bool {
+	fields, otherFields := strings.Fields(string(dk.Key)), strings.Fields(string(other.Key))
+	if len(fields) > 2 {
+		fields = fields[:2]
+	}
+	if len(otherFields) > 2 {
+		otherFields = otherFields[:2]
+	}
+	return reflect.DeepEqual(fields, otherFields)
+}
+
+// ValidateAndDefaultDeployKeyInfos validates that the names of the desired deploy keys are unique, and
+// returns a copy of the requests validated and defaulted, see ValidateAndDefaultInfo.
+func ValidateAndDefaultDeployKeyInfos(desired []DeployKeyInfo) ([]DeployKeyInfo, error) {
+	errs := validation.New("DeployKeyInfo")
+	reqs := make([]DeployKeyInfo, len(desired))
+	seen := make(map[string]struct{}, len(desired))
+	for i := range desired {
+		reqs[i] = desired[i]
+		if err := ValidateAndDefaultInfo(&reqs[i]); err != nil {
+			return nil, err
+		}
+		if _, ok := seen[reqs[i].Name]; ok {
+			errs.Append(fmt.Errorf("%w: duplicate deploy key name", validation.ErrFieldInvalid), reqs[i].Name, "Name")
+		}
+		seen[reqs[i].Name] = struct{}{}
+	}
+	if err := errs.Error(); err != nil {
+		return nil, err
+	}
+	return reqs, nil
+}
+
+// ReleaseInfo implements InfoRequest.
+var _ InfoRequest = ReleaseInfo{}
+
+// ReleaseInfo contains high-level information about a release of a repository.
+type ReleaseInfo struct {
+	// TagName is the name of the tag the release is made from, e.g. "v1.2.0".
+	// +required
+	TagName string `json:"tagName"`
+
+	// TargetCommitish is the branch name or commit SHA the tag points to, or is created from if the
+	// tag doesn't exist yet. Default value at POST-time: the default branch of the repository.
+	// +optional
+	TargetCommitish *string `json:"targetCommitish,omitempty"`
+
+	// Name is the human-friendly title of the release. Default value at POST-time: the tag name.
+	// +optional
+	Name *string `json:"name,omitempty"`
+
+	// Body is the release notes, in Markdown.
+	// +optional
+	Body *string `json:"body,omitempty"`
+
+	// Draft specifies whether the release is unpublished. GitLab doesn't have draft releases.
+	// Default value at POST-time: false.
+	// +optional
+	Draft *bool `json:"draft,omitempty"`
+
+	// Prerelease specifies whether the release is marked as not ready for production. In GitLab,
+	// releases with a release date in the future ("upcoming releases") are prereleases.
+	// Default value at POST-time: false.
+	// +optional
+	Prerelease *bool `json:"prerelease,omitempty"`
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (r ReleaseInfo) ValidateInfo() error {
+	validator := validation.New("Release")
+	// TagName is a required field
+	if len(r.TagName) == 0 {
+		validator.Required("TagName")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument.
+func (r ReleaseInfo) Equals(actual InfoRequest) bool {
+	return reflect.DeepEqual(r, actual)
+}
+
+// RepositoryHookInfo implements InfoRequest and DefaultedInfoRequest (with a pointer receiver).
+var _ InfoRequest = RepositoryHookInfo{}
+var _ DefaultedInfoRequest = &RepositoryHookInfo{}
+
+// RepositoryHookInfo contains high-level information about a webhook of a repository.
+type RepositoryHookInfo struct {
+	// URL is the address the event payloads are delivered to. It identifies the webhook in the
+	// repository.
+	// +required
+	URL string `json:"url"`
+
+	// ContentType is the media type of the delivered payloads.
+	// Default value at POST-time: RepositoryHookContentTypeJSON.
+	// +optional
+	ContentType *RepositoryHookContentType `json:"contentType,omitempty"`
+
+	// Secret is used to sign the delivered payloads, allowing the receiver to verify them.
+	// The secret is write-only: it is never populated when read from the Git provider, and hence
+	// it isn't considered when comparing the desired and actual state.
+	// +optional
+	Secret *string `json:"secret,omitempty"`
+
+	// Active specifies whether the event payloads are delivered.
+	// Default value at POST-time: true.
+	// +optional
+	Active *bool `json:"active,omitempty"`
+
+	// Events is the set of events the webhook is subscribed to.
+	// Default value at POST-time: []RepositoryHookEvent{RepositoryHookEventPush}.
+	// +optional
+	Events []RepositoryHookEvent `json:"events,omitempty"`
+}
+
+// Default defaults the RepositoryHook fields. Events are sorted, and duplicates are removed.
+func (h *RepositoryHookInfo) Default() {
+	if h.ContentType == nil {
+		h.ContentType = RepositoryHookContentTypeVar(defaultRepositoryHookContentType)
+	}
+	if h.Active == nil {
+		h.Active = BoolVar(defaultRepositoryHookActive)
+	}
+	if len(h.Events) == 0 {
+		h.Events = []RepositoryHookEvent{defaultRepositoryHookEvent}
+	}
+	h.Events = NormalizeRepositoryHookEvents(h.Events)
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (h RepositoryHookInfo) ValidateInfo() error {
+	validator := validation.New("RepositoryHook")
+	// URL is a required field
+	if len(h.URL) == 0 {
+		validator.Required("URL")
+	}
+	// Validate the enums, if set
+	if h.ContentType != nil {
+		validator.Append(ValidateRepositoryHookContentType(*h.ContentType), *h.ContentType, "ContentType")
+	}
+	for _, event := range h.Events {
+		validator.Append(ValidateRepositoryHookEvent(event), event, "Events")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument. The secret is ignored, as it can't be read from the Git provider,
+// and the order of the events doesn't matter.
+func (h RepositoryHookInfo) Equals(actual InfoRequest) bool {
+	other, ok := actual.(RepositoryHookInfo)
+	if !ok {
+		return false
+	}
+	h.Secret, other.Secret = nil, nil
+	h.Events, other.Events = NormalizeRepositoryHookEvents(h.Events), NormalizeRepositoryHookEvents(other.Events)
+	return reflect.DeepEqual(h, other)
+}
+
+// NormalizeRepositoryHookEvents returns a sorted copy of events, without duplicates.
+func NormalizeRepositoryHookEvents(events []RepositoryHookEvent) []RepositoryHookEvent {
+	normalized := make([]RepositoryHookEvent, 0, len(events))
+	seen := make(map[RepositoryHookEvent]struct{}, len(events))
+	for _, event := range events {
+		if _, ok := seen[event]; ok {
+			continue
+		}
+		seen[event] = struct{}{}
+		normalized = append(normalized, event)
+	}
+	sort.Slice(normalized, func(i, j int) bool { return normalized[i] < normalized[j] })
+	return normalized
+}
+
+// RulesetInfo implements InfoRequest and DefaultedInfoRequest (with a pointer receiver).
+var _ InfoRequest = RulesetInfo{}
+var _ DefaultedInfoRequest = &RulesetInfo{}
+
+// RulesetInfo contains high-level information about a ruleset of a repository, i.e. a named set
+// of rules applying to the refs matching its conditions. Only the rules modelled here are managed,
+// other rules of the ruleset are kept as-is.
+type RulesetInfo struct {
+	// Name identifies the ruleset in the repository.
+	// +required
+	Name string `json:"name"`
+
+	// Target specifies the kind of refs the ruleset applies to.
+	// Default value at POST-time: RulesetTargetBranch.
+	// +optional
+	Target *RulesetTarget `json:"target,omitempty"`
+
+	// Enforcement specifies whether the rules are enforced.
+	// Default value at POST-time: RulesetEnforcementActive.
+	// +optional
+	Enforcement *RulesetEnforcement `json:"enforcement,omitempty"`
+
+	// Include is the list of patterns of the refs the ruleset applies to, e.g. "refs/heads/main",
+	// "refs/heads/release/*", or "~DEFAULT_BRANCH" for the default branch.
+	// +required
+	Include []string `json:"include"`
+
+	// Exclude is the list of patterns of the refs the ruleset doesn't apply to, even if they are included.
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+
+	// RequiredSignatures requires the commits pushed to the matching branches to have verified
+	// signatures. It can only be set for rulesets targeting branches.
+	// +optional
+	RequiredSignatures bool `json:"requiredSignatures,omitempty"`
+
+	// RestrictMerges only allows the MergeTeams (and the other bypass actors of the ruleset) to
+	// update the matching refs, be it by merging pull requests or by pushing.
+	// +optional
+	RestrictMerges bool `json:"restrictMerges,omitempty"`
+
+	// MergeTeams is the set of names (slugs) of the teams that are allowed to update the matching
+	// refs if RestrictMerges is true. They must exist in the organization owning the repository.
+	//
+	// GitHub models them as the teams that can bypass the ruleset, hence they bypass its other rules
+	// too. Individual users can't bypass rulesets in GitHub.
+	// +optional
+	MergeTeams []string `json:"mergeTeams,omitempty"`
+
+	// RequiredDeploymentEnvironments is the set of names of the environments that must be deployed to
+	// successfully before the matching branches can be updated. The environments must exist in the
+	// repository. It can only be set for rulesets targeting branches.
+	// +optional
+	RequiredDeploymentEnvironments []string `json:"requiredDeploymentEnvironments,omitempty"`
+}
+
+// Default defaults the Ruleset fields.
+func (r *RulesetInfo) Default() {
+	if r.Target == nil {
+		r.Target = RulesetTargetVar(defaultRulesetTarget)
+	}
+	if r.Enforcement == nil {
+		r.Enforcement = RulesetEnforcementVar(defaultRulesetEnforcement)
+	}
+	if r.Exclude == nil {
+		r.Exclude = []string{}
+	}
+	r.MergeTeams = normalizeStringSet(r.MergeTeams)
+	r.RequiredDeploymentEnvironments = normalizeStringSet(r.RequiredDeploymentEnvironments)
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (r RulesetInfo) ValidateInfo() error {
+	validator := validation.New("Ruleset")
+	// Name and Include are required fields
+	if len(r.Name) == 0 {
+		validator.Required("Name")
+	}
+	if len(r.Include) == 0 {
+		validator.Required("Include")
+	}
+	// Validate the enums, if set
+	if r.Target != nil {
+		validator.Append(ValidateRulesetTarget(*r.Target), *r.Target, "Target")
+	}
+	if r.Enforcement != nil {
+		validator.Append(ValidateRulesetEnforcement(*r.Enforcement), *r.Enforcement, "Enforcement")
+	}
+	// Signatures are required for commits, hence only on branches
+	if r.RequiredSignatures && r.Target != nil && *r.Target != RulesetTargetBranch {
+		validator.Invalid(r.RequiredSignatures, "RequiredSignatures")
+	}
+	// The teams that are allowed to merge are only meaningful if merges are restricted
+	if !r.RestrictMerges && len(r.MergeTeams) != 0 {
+		validator.Invalid(r.MergeTeams, "MergeTeams")
+	}
+	// Deployments are made from branches, hence they can only be required on branches
+	if len(r.RequiredDeploymentEnvironments) != 0 && r.Target != nil && *r.Target != RulesetTargetBranch {
+		validator.Invalid(r.RequiredDeploymentEnvironments, "RequiredDeploymentEnvironments")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument.
+func (r RulesetInfo) Equals(actual InfoRequest) bool {
+	return reflect.DeepEqual(r, actual)
+}
+
+// BranchProtectionInfo implements InfoRequest and DefaultedInfoRequest (with a pointer receiver).
+var _ InfoRequest = BranchProtectionInfo{}
+var _ DefaultedInfoRequest = &BranchProtectionInfo{}
+
+// BranchProtectionInfo contains high-level information about the protection rules of a branch.
+// Only the rules modelled here are managed, other settings of the protection are kept as-is.
+type BranchProtectionInfo struct {
+	// Branch is the name of the protected branch. It identifies the protection in the repository.
+	// +required
+	Branch string `json:"branch"`
+
+	// RequiredReviewCount is the number of approving reviews a pull request needs before it can
+	// be merged. Zero means that pull request reviews aren't required.
+	// +optional
+	RequiredReviewCount int `json:"requiredReviewCount,omitempty"`
+
+	// RequiredStatusChecks is the set of status checks (contexts) that must pass before merging.
+	// The order of the checks is insignificant.
+	// +optional
+	RequiredStatusChecks []string `json:"requiredStatusChecks,omitempty"`
+
+	// EnforceAdmins applies the protection rules to administrators too.
+	// +optional
+	EnforceAdmins bool `json:"enforceAdmins,omitempty"`
+
+	// RestrictPushes only allows PushUsers and PushTeams (and administrators) to push to the branch.
+	// If false, everyone with write access can push.
+	// +optional
+	RestrictPushes bool `json:"restrictPushes,omitempty"`
+
+	// PushUsers is the set of logins of the users that are allowed to push, if RestrictPushes is true.
+	// +optional
+	PushUsers []string `json:"pushUsers,omitempty"`
+
+	// PushTeams is the set of names (slugs) of the teams that are allowed to push, if RestrictPushes is true.
+	// +optional
+	PushTeams []string `json:"pushTeams,omitempty"`
+
+	// RequireLinearHistory prevents merge commits from being pushed to the branch. If nil, the
+	// setting is kept as-is, and not compared in Equals.
+	//
+	// GitHub maps this to the required linear history of the protection. GitLab has no such branch
+	// rule, it approximates linear history with the fast-forward merge method of the project, which
+	// is managed through the merge settings of the repository instead.
+	// +optional
+	RequireLinearHistory *bool `json:"requireLinearHistory,omitempty"`
+}
+
+// Default defaults the BranchProtection fields. The sets are sorted, and duplicates are removed.
+func (bp *BranchProtectionInfo) Default() {
+	bp.RequiredStatusChecks = normalizeStringSet(bp.RequiredStatusChecks)
+	bp.PushUsers = normalizeStringSet(bp.PushUsers)
+	bp.PushTeams = normalizeStringSet(bp.PushTeams)
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (bp BranchProtectionInfo) ValidateInfo() error {
+	validator := validation.New("BranchProtection")
+	// Make sure we've set the name of the branch
+	if len(bp.Branch) == 0 {
+		validator.Required("Branch")
+	}
+	if bp.RequiredReviewCount < 0 {
+		validator.Invalid(bp.RequiredReviewCount, "RequiredReviewCount")
+	}
+	// The actors that are allowed to push are only meaningful if pushes are restricted
+	if !bp.RestrictPushes && len(bp.PushUsers) != 0 {
+		validator.Invalid(bp.PushUsers, "PushUsers")
+	}
+	if !bp.RestrictPushes && len(bp.PushTeams) != 0 {
+		validator.Invalid(bp.PushTeams, "PushTeams")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument. The rules are compared field by field, and the order of the
+// status checks, users and teams doesn't matter.
+func (bp BranchProtectionInfo) Equals(actual InfoRequest) bool {
+	other, ok := actual.(BranchProtectionInfo)
+	if !ok {
+		return false
+	}
+	bp.Default()
+	other.Default()
+	// Don't compare the settings that aren't desired
+	if bp.RequireLinearHistory == nil {
+		other.RequireLinearHistory = nil
+	}
+	return reflect.DeepEqual(bp, other)
+}
+
+// BranchProtectionTemplate is a branch protection to apply to the default branch of many
+// repositories at once, e.g. through ApplyProtectionTemplate of the GitHub client.
+type BranchProtectionTemplate struct {
+	// Protection is the protection of the default branch of each repository. Its Branch must be
+	// empty, as it is the default branch of each repository.
+	// +required
+	Protection BranchProtectionInfo `json:"protection"`
+
+	// Concurrency is the maximum amount of repositories that are protected at once.
+	// Default: 0 (which means 4).
+	// +optional
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+// ValidateTemplate validates that the template is valid, before it's applied to any repository.
+func (t BranchProtectionTemplate) ValidateTemplate() error {
+	validator := validation.New("BranchProtectionTemplate")
+	if len(t.Protection.Branch) != 0 {
+		validator.Invalid(t.Protection.Branch, "Protection", "Branch")
+	}
+	// Validate the rules as if they protected a branch, as they would for every repository otherwise
+	protection := t.Protection
+	protection.Branch = "default"
+	validator.Append(protection.ValidateInfo(), t.Protection, "Protection")
+	if t.Concurrency < 0 {
+		validator.Invalid(t.Concurrency, "Concurrency")
+	}
+	return validator.Error()
+}
+
+// GetConcurrency returns the configured concurrency, or the default one if unset.
+func (t BranchProtectionTemplate) GetConcurrency() int {
+	if t.Concurrency != 0 {
+		return t.Concurrency
+	}
+	return defaultBranchProtectionTemplateConcurrency
+}
+
+// normalizeStringSet returns a sorted, non-nil copy of list, without duplicates.
+func normalizeStringSet(list []string) []string {
+	normalized := make([]string, 0, len(list))
+	seen := make(map[string]struct{}, len(list))
+	for _, item := range list {
+		if _, ok := seen[item]; ok {
+			continue
+		}
+		seen[item] = struct{}{}
+		normalized = append(normalized, item)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// BranchInfo contains high-level information about a branch.
+// This is a read-only type, branches are created through BranchClient.Create.
+type BranchInfo struct {
+	// Name is the name of the branch, e.g. "main".
+	Name string `json:"name"`
+
+	// Sha is the full SHA of the commit the branch points to.
+	Sha string `json:"sha"`
+
+	// Protected is true if the provider reports the branch as protected.
+	Protected bool `json:"protected"`
+}
+
+// TagInfo contains high-level information about a tag.
+// This is a read-only type, tags are created through TagClient.Create.
+type TagInfo struct {
+	// Name is the name of the tag, e.g. "v1.2.0".
+	Name string `json:"name"`
+
+	// Sha is the full SHA of the commit the tag points to.
+	Sha string `json:"sha"`
+}
+
+// CommitInfo contains high-level information about a commit.
+// This is a read-only type, commits are created through CommitClient.Create.
+type CommitInfo struct {
+	// Sha is the full SHA of the commit.
+	Sha string `json:"sha"`
+
+	// Author is the name of the author of the commit.
+	Author string `json:"author"`
+
+	// Message is the full commit message.
+	Message string `json:"message"`
+
+	// CreatedAt is the time the commit was authored.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// URL is the web URL of the commit.
+	URL string `json:"url"`
+
+	// Verified is whether the provider verified the signature of the commit. It is nil if the
+	// verification status is unknown, e.g. because it wasn't requested through
+	// CommitListOptions.IncludeVerification in a provider that returns it separately.
+	Verified *bool `json:"verified,omitempty"`
+
+	// VerificationReason is the verification status as reported by the provider, e.g. "valid" or
+	// "unsigned" in GitHub, and "verified" or "unverified" in GitLab. It is empty if Verified is nil.
+	VerificationReason string `json:"verificationReason,omitempty"`
+}
+
+// CommitFile describes a change to a single file in a commit, see CommitClient.Create.
+type CommitFile struct {
+	// Path is the path of the file, relative to the root of the repository.
+	// +required
+	Path string `json:"path"`
+
+	// Content is the new content of the file. The file is created if it doesn't exist yet.
+	// If nil, the file is deleted.
+	// +optional
+	Content *string `json:"content"`
+}
+
+// ValidateCommitRequest validates the arguments given to CommitClient.Create, i.e. that the
+// branch name is valid, the message isn't empty, and that each file is changed once.
+func ValidateCommitRequest(branch, message string, files []CommitFile) error {
+	validator := validation.New("Commit")
+	validator.Append(ValidateBranchName(branch), branch, "Branch")
+	if len(message) == 0 {
+		validator.Required("Message")
+	}
+	if len(files) == 0 {
+		validator.Required("Files")
+	}
+	paths := make(map[string]struct{}, len(files))
+	for _, file := range files {
+		if len(file.Path) == 0 {
+			validator.Required("Files.Path")
+			continue
+		}
+		if _, ok := paths[file.Path]; ok {
+			validator.Invalid(file.Path, "Files.Path")
+		}
+		paths[file.Path] = struct{}{}
+	}
+	return validator.Error()
+}
+
+// IssueInfo contains high-level information about an issue.
+// This is a read-only type, issues are created through IssueClient.Create.
+type IssueInfo struct {
+	// Number is the number of the issue, unique within the repository.
+	Number int `json:"number"`
+
+	// Title is the title of the issue.
+	Title string `json:"title"`
+
+	// State is the state of the issue.
+	State IssueState `json:"state"`
+
+	// URL is the web URL of the issue.
+	URL string `json:"url"`
+}
+
+// ValidateIssueRequest validates the arguments given to IssueClient.Create, i.e. that the
+// title isn't empty, and that no label is empty.
+func ValidateIssueRequest(title string, labels []string) error {
+	validator := validation.New("Issue")
+	if len(strings.TrimSpace(title)) == 0 {
+		validator.Required("Title")
+	}
+	for _, label := range labels {
+		if len(label) == 0 {
+			validator.Required("Labels")
+			break
+		}
+	}
+	return validator.Error()
+}
+
+// MirrorStatusInfo contains high-level information about the last sync of a mirror repository.
+// This is a read-only type, syncs are triggered through UserRepository.TriggerMirrorSync.
+type MirrorStatusInfo struct {
+	// Status is the state of the last sync.
+	Status MirrorSyncStatus `json:"status"`
+
+	// LastError is the error message of the last sync, as given by the provider, if Status is
+	// MirrorSyncStatusFailed. For example, it tells whether the upstream repository rejected
+	// the credentials of the mirror, or couldn't be reached at all.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// SecurityAdvisoryInfo contains high-level information about a security advisory filed for a repository.
+// This is a read-only type, advisories are managed through the Git provider's UI.
+type SecurityAdvisoryInfo struct {
+	// ID is the provider-specific identifier of the advisory, e.g. "GHSA-abcd-1234-efgh".
+	ID string `json:"id"`
+
+	// Severity describes the severity of the advisory, e.g. "low", "medium", "high" or "critical".
+	// Severity might be empty if the advisory hasn't been triaged yet.
+	Severity string `json:"severity"`
+
+	// State describes in what stage of the advisory lifecycle the advisory is.
+	State SecurityAdvisoryState `json:"state"`
+}
+
+// PipelineRequirements specifies what CI results are required before changes can be merged into a repository.
+// GitHub models this per-branch through required status checks, whereas GitLab configures it project-wide.
+type PipelineRequirements struct {
+	// RequireSuccessfulPipeline makes merging only possible if the CI pipeline (GitLab) or
+	// the required status checks (GitHub) succeeded.
+	RequireSuccessfulPipeline bool `json:"requireSuccessfulPipeline"`
+
+	// RequireResolvedDiscussions makes merging only possible if all discussion threads are resolved.
+	// This field is GitLab-specific.
+	RequireResolvedDiscussions bool `json:"requireResolvedDiscussions"`
+}
+
+// InstalledAppInfo contains high-level information about an app (integration) that has been
+// granted access to a repository.
+// This is a read-only type, app installations are managed through the Git provider's UI.
+type InstalledAppInfo struct {
+	// Slug is the URL-friendly name of the app, e.g. "dependabot".
+	Slug string `json:"slug"`
+
+	// Permissions maps each permission the app has been granted, e.g. "contents",
+	// to its access level, e.g. "read" or "write".
+	Permissions map[string]string `json:"permissions"`
+}
+
+// IssueCloseSettings specifies how issues are closed automatically when referenced from commits
+// and merge requests. This is a GitLab-specific type; GitHub always closes referenced issues using
+// fixed keywords. The closing keywords themselves are configured instance-wide in GitLab.
+type IssueCloseSettings struct {
+	// AutocloseReferencedIssues makes issues referenced with a closing keyword, e.g. "Closes #1",
+	// close automatically when the commit or merge request lands on the default branch.
+	AutocloseReferencedIssues bool `json:"autocloseReferencedIssues"`
+}
+
+// MergeQueueInfo implements InfoRequest and DefaultedInfoRequest (with a pointer receiver).
+var _ InfoRequest = MergeQueueInfo{}
+var _ DefaultedInfoRequest = &MergeQueueInfo{}
+
+// MergeQueueInfo specifies the merge queue settings of a branch. This is a GitHub-specific type;
+// GitLab has merge trains instead, which are configured separately.
+type MergeQueueInfo struct {
+	// Enabled makes changes targeting the branch go through the merge queue.
+	// When false, the other fields are ignored.
+	Enabled bool `json:"enabled"`
+
+	// BatchSize is the maximum amount of queued changes that are merged at once.
+	// Default value at POST-time: 5. Accepted values: 1-100.
+	// +optional
+	BatchSize *int `json:"batchSize"`
+
+	// MergeMethod describes how the queued changes are merged.
+	// Default value at POST-time: MergeQueueMergeMethodMerge.
+	// +optional
+	MergeMethod *MergeQueueMergeMethod `json:"mergeMethod"`
+}
+
+// Default defaults the MergeQueue, implementing the InfoRequest interface.
+func (mq *MergeQueueInfo) Default() {
+	if mq.BatchSize == nil {
+		mq.BatchSize = IntVar(defaultMergeQueueBatchSize)
+	}
+	if mq.MergeMethod == nil {
+		mq.MergeMethod = MergeQueueMergeMethodVar(defaultMergeQueueMergeMethod)
+	}
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (mq MergeQueueInfo) ValidateInfo() error {
+	validator := validation.New("MergeQueue")
+	if mq.BatchSize != nil && (*mq.BatchSize < 1 || *mq.BatchSize > maxMergeQueueBatchSize) {
+		validator.Invalid(*mq.BatchSize, "BatchSize")
+	}
+	// Validate the MergeMethod enum
+	if mq.MergeMethod != nil {
+		validator.Append(ValidateMergeQueueMergeMethod(*mq.MergeMethod), *mq.MergeMethod, "MergeMethod")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument. Two disabled merge queues are equal regardless of their settings.
+func (mq MergeQueueInfo) Equals(actual InfoRequest) bool {
+	actualInfo, ok := actual.(MergeQueueInfo)
+	if !ok {
+		return false
+	}
+	if !mq.Enabled && !actualInfo.Enabled {
+		return true
+	}
+	return reflect.DeepEqual(mq, actualInfo)
+}
+
+// ProtectedEnvironmentInfo implements InfoRequest.
+var _ InfoRequest = ProtectedEnvironmentInfo{}
+
+// ProtectedEnvironmentInfo specifies who can deploy to a protected environment of a repository.
+// This is a GitLab-specific type; GitHub gates deployments through environment reviewers instead.
+type ProtectedEnvironmentInfo struct {
+	// Name is the name of the environment, e.g. "production". Protected environments are
+	// identified by their name.
+	// +required
+	Name string `json:"name"`
+
+	// DeployAccessLevels lists the roles that are allowed to deploy to the environment.
+	// The order of the levels is insignificant.
+	// +required
+	DeployAccessLevels []EnvironmentAccessLevel `json:"deployAccessLevels"`
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (pe ProtectedEnvironmentInfo) ValidateInfo() error {
+	validator := validation.New("ProtectedEnvironment")
+	// Make sure we've set the name of the environment
+	if len(pe.Name) == 0 {
+		validator.Required("Name")
+	}
+	// An environment no one can deploy to must be expressed by the admin level
+	if len(pe.DeployAccessLevels) == 0 {
+		validator.Required("DeployAccessLevels")
+	}
+	// Validate the DeployAccessLevels enums
+	for _, level := range pe.DeployAccessLevels {
+		validator.Append(ValidateEnvironmentAccessLevel(level), level, "DeployAccessLevels")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument. The deploy access levels are compared as a set.
+func (pe ProtectedEnvironmentInfo) Equals(actual InfoRequest) bool {
+	actualInfo, ok := actual.(ProtectedEnvironmentInfo)
+	if !ok || pe.Name != actualInfo.Name {
+		return false
+	}
+	return reflect.DeepEqual(environmentAccessLevelSet(pe.DeployAccessLevels), environmentAccessLevelSet(actualInfo.DeployAccessLevels))
+}
+
+// environmentAccessLevelSet returns the distinct levels of the list.
+func environmentAccessLevelSet(levels []EnvironmentAccessLevel) map[EnvironmentAccessLevel]struct{} {
+	set := make(map[EnvironmentAccessLevel]struct{}, len(levels))
+	for _, level := range levels {
+		set[level] = struct{}{}
+	}
+	return set
+}
+
+// SubscriptionInfo implements InfoRequest.
+var _ InfoRequest = SubscriptionInfo{}
+
+// SubscriptionInfo specifies what notifications the authenticated user gets about a repository,
+// e.g. to keep a bot account managing many repositories from being flooded with notifications.
+type SubscriptionInfo struct {
+	// Level specifies what notifications the user gets.
+	// +required
+	Level SubscriptionLevel `json:"level"`
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (s SubscriptionInfo) ValidateInfo() error {
+	validator := validation.New("Subscription")
+	// Validate the Level enum
+	if len(s.Level) == 0 {
+		validator.Required("Level")
+	} else {
+		validator.Append(ValidateSubscriptionLevel(s.Level), s.Level, "Level")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument.
+func (s SubscriptionInfo) Equals(actual InfoRequest) bool {
+	return reflect.DeepEqual(s, actual)
+}
+
+// CommunityHealthFiles lists all known community health files, in the order they're reported in.
+func CommunityHealthFiles() []CommunityHealthFile {
+	return []CommunityHealthFile{
+		CommunityHealthFileContributing,
+		CommunityHealthFileCodeOfConduct,
+		CommunityHealthFileSecurity,
+	}
+}
+
+// CommunityHealthFilePaths returns the paths the given community health file is looked up at, in the
+// order of precedence GitHub uses: the .github directory, the root and the docs directory.
+func CommunityHealthFilePaths(file CommunityHealthFile) []string {
+	return []string{".github/" + string(file), string(file), "docs/" + string(file)}
+}
+
+// CommunityHealthInfo describes how well a repository supports its community, e.g. for an open
+// source program office to track the health of its repositories.
+type CommunityHealthInfo struct {
+	// HealthPercentage is the health score of the repository, from 0 to 100.
+	HealthPercentage int `json:"healthPercentage"`
+
+	// Files lists the community health files the repository has.
+	Files []CommunityHealthFile `json:"files"`
 }
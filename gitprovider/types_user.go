@@ -0,0 +1,32 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+// UserInfo contains high-level information about a user, e.g. the user the client is authenticated as.
+// This is a read-only type.
+type UserInfo struct {
+	// Domain is the domain of the Git provider the user belongs to, e.g. "github.com".
+	Domain string `json:"domain"`
+
+	// Login is the login name of the user, e.g. "fluxcd-bot".
+	Login string `json:"login"`
+}
+
+// UserRef returns a reference to the user, e.g. to use the user as the owner of repositories.
+func (u UserInfo) UserRef() UserRef {
+	return UserRef{Domain: u.Domain, UserLogin: u.Login}
+}
@@ -18,6 +18,7 @@ package gitprovider
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/dinosk/go-git-providers/validation"
@@ -100,6 +101,13 @@ func TestRepository_Validate(t *testing.T) {
 			},
 			expectedErrs: []error{validation.ErrFieldEnumInvalid},
 		},
+		{
+			name: "invalid create and update, invalid topic",
+			repo: RepositoryInfo{
+				Topics: []string{"GitOps", "flux_cd"},
+			},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -108,6 +116,442 @@ func TestRepository_Validate(t *testing.T) {
 	}
 }
 
+func TestRepository_Equals(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired RepositoryInfo
+		actual  InfoRequest
+		want    bool
+	}{
+		{
+			name:    "equal",
+			desired: RepositoryInfo{Description: StringVar("foo"), DefaultBranch: StringVar("main")},
+			actual:  RepositoryInfo{Description: StringVar("foo"), DefaultBranch: StringVar("main")},
+			want:    true,
+		},
+		{
+			name:    "description whitespace is normalized",
+			desired: RepositoryInfo{Description: StringVar(" foo \n")},
+			actual:  RepositoryInfo{Description: StringVar("foo")},
+			want:    true,
+		},
+		{
+			name:    "default branch casing is normalized",
+			desired: RepositoryInfo{DefaultBranch: StringVar("Main")},
+			actual:  RepositoryInfo{DefaultBranch: StringVar("main")},
+			want:    true,
+		},
+		{
+			name:    "topic order and casing is normalized",
+			desired: RepositoryInfo{Topics: []string{"GitOps", "flux"}},
+			actual:  RepositoryInfo{Topics: []string{"flux", "gitops"}},
+			want:    true,
+		},
+		{
+			name:    "different description",
+			desired: RepositoryInfo{Description: StringVar("foo")},
+			actual:  RepositoryInfo{Description: StringVar("bar")},
+			want:    false,
+		},
+		{
+			name:    "different type",
+			desired: RepositoryInfo{},
+			actual:  TeamAccessInfo{},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.desired.Equals(tt.actual); got != tt.want {
+				t.Errorf("RepositoryInfo.Equals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeQueue_Validate(t *testing.T) {
+	unknownMergeMethod := MergeQueueMergeMethod("unknown")
+	tests := []struct {
+		name         string
+		mergeQueue   MergeQueueInfo
+		expectedErrs []error
+	}{
+		{
+			name: "valid, with valid enum and batch size",
+			mergeQueue: MergeQueueInfo{
+				Enabled:     true,
+				BatchSize:   IntVar(100),
+				MergeMethod: MergeQueueMergeMethodVar(MergeQueueMergeMethodSquash),
+			},
+		},
+		{
+			name:         "invalid, batch size too small",
+			mergeQueue:   MergeQueueInfo{Enabled: true, BatchSize: IntVar(0)},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+		{
+			name:         "invalid, batch size too large",
+			mergeQueue:   MergeQueueInfo{Enabled: true, BatchSize: IntVar(101)},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+		{
+			name:         "invalid, invalid enum",
+			mergeQueue:   MergeQueueInfo{Enabled: true, MergeMethod: &unknownMergeMethod},
+			expectedErrs: []error{validation.ErrFieldEnumInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "MergeQueue", tt.mergeQueue.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestRuleset_Validate(t *testing.T) {
+	unknownEnforcement := RulesetEnforcement("unknown")
+	tests := []struct {
+		name         string
+		ruleset      RulesetInfo
+		expectedErrs []error
+	}{
+		{
+			name: "valid, requiring signatures on branches",
+			ruleset: RulesetInfo{
+				Name:               "main",
+				Include:            []string{"refs/heads/main"},
+				RequiredSignatures: true,
+			},
+		},
+		{
+			name:         "invalid, missing name and include",
+			ruleset:      RulesetInfo{},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name:         "invalid, invalid enum",
+			ruleset:      RulesetInfo{Name: "main", Include: []string{"~DEFAULT_BRANCH"}, Enforcement: &unknownEnforcement},
+			expectedErrs: []error{validation.ErrFieldEnumInvalid},
+		},
+		{
+			name: "invalid, requiring signatures on tags",
+			ruleset: RulesetInfo{
+				Name:               "tags",
+				Target:             RulesetTargetVar(RulesetTargetTag),
+				Include:            []string{"refs/tags/*"},
+				RequiredSignatures: true,
+			},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+		{
+			name: "valid, with restricted merges",
+			ruleset: RulesetInfo{
+				Name:           "main",
+				Include:        []string{"refs/heads/main"},
+				RestrictMerges: true,
+				MergeTeams:     []string{"platform"},
+			},
+		},
+		{
+			name: "invalid, requiring deployments on tags",
+			ruleset: RulesetInfo{
+				Name:                           "tags",
+				Target:                         RulesetTargetVar(RulesetTargetTag),
+				Include:                        []string{"refs/tags/*"},
+				RequiredDeploymentEnvironments: []string{"staging"},
+			},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+		{
+			name: "invalid, merge teams without restricted merges",
+			ruleset: RulesetInfo{
+				Name:       "main",
+				Include:    []string{"refs/heads/main"},
+				MergeTeams: []string{"platform"},
+			},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "Ruleset", tt.ruleset.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestBranchProtection_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		protection   BranchProtectionInfo
+		expectedErrs []error
+	}{
+		{
+			name: "valid, with restricted pushes",
+			protection: BranchProtectionInfo{
+				Branch:              "main",
+				RequiredReviewCount: 1,
+				RestrictPushes:      true,
+				PushUsers:           []string{"luxas"},
+			},
+		},
+		{
+			name:         "invalid, missing branch",
+			protection:   BranchProtectionInfo{},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name:         "invalid, negative review count",
+			protection:   BranchProtectionInfo{Branch: "main", RequiredReviewCount: -1},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+		{
+			name:         "invalid, push actors without restricting pushes",
+			protection:   BranchProtectionInfo{Branch: "main", PushTeams: []string{"maintainers"}},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "BranchProtection", tt.protection.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestProtectedEnvironment_Validate(t *testing.T) {
+	unknownLevel := EnvironmentAccessLevel("unknown")
+	tests := []struct {
+		name         string
+		environment  ProtectedEnvironmentInfo
+		expectedErrs []error
+	}{
+		{
+			name: "valid",
+			environment: ProtectedEnvironmentInfo{
+				Name:               "production",
+				DeployAccessLevels: []EnvironmentAccessLevel{EnvironmentAccessLevelMaintainer},
+			},
+		},
+		{
+			name:         "invalid, missing name",
+			environment:  ProtectedEnvironmentInfo{DeployAccessLevels: []EnvironmentAccessLevel{EnvironmentAccessLevelMaintainer}},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name:         "invalid, no access levels",
+			environment:  ProtectedEnvironmentInfo{Name: "production"},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name: "invalid, invalid enum",
+			environment: ProtectedEnvironmentInfo{
+				Name:               "production",
+				DeployAccessLevels: []EnvironmentAccessLevel{EnvironmentAccessLevelDeveloper, unknownLevel},
+			},
+			expectedErrs: []error{validation.ErrFieldEnumInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "ProtectedEnvironment", tt.environment.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestRelease_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		release      ReleaseInfo
+		expectedErrs []error
+	}{
+		{
+			name:    "valid",
+			release: ReleaseInfo{TagName: "v1.0.0", Prerelease: BoolVar(true)},
+		},
+		{
+			name:         "invalid, missing tag name",
+			release:      ReleaseInfo{Name: StringVar("First release")},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "Release", tt.release.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestValidateCommitRequest(t *testing.T) {
+	file := CommitFile{Path: "README.md", Content: StringVar("# Flux")}
+	tests := []struct {
+		name         string
+		branch       string
+		message      string
+		files        []CommitFile
+		expectedErrs []error
+	}{
+		{
+			name:    "valid, with a deletion",
+			branch:  "main",
+			message: "Bootstrap",
+			files:   []CommitFile{file, {Path: "old.md"}},
+		},
+		{
+			name:         "invalid, missing message and files",
+			branch:       "main",
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name:         "invalid, branch name",
+			branch:       "feature..x",
+			message:      "Bootstrap",
+			files:        []CommitFile{file},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+		{
+			name:         "invalid, file changed twice",
+			branch:       "main",
+			message:      "Bootstrap",
+			files:        []CommitFile{file, {Path: "README.md"}},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "Commit", func() error {
+				return ValidateCommitRequest(tt.branch, tt.message, tt.files)
+			}, tt.expectedErrs)
+		})
+	}
+}
+
+func TestProtectedEnvironment_Equals(t *testing.T) {
+	desired := ProtectedEnvironmentInfo{
+		Name:               "production",
+		DeployAccessLevels: []EnvironmentAccessLevel{EnvironmentAccessLevelDeveloper, EnvironmentAccessLevelMaintainer},
+	}
+	tests := []struct {
+		name   string
+		actual ProtectedEnvironmentInfo
+		want   bool
+	}{
+		{
+			name: "same levels in another order",
+			actual: ProtectedEnvironmentInfo{
+				Name:               "production",
+				DeployAccessLevels: []EnvironmentAccessLevel{EnvironmentAccessLevelMaintainer, EnvironmentAccessLevelDeveloper},
+			},
+			want: true,
+		},
+		{
+			name: "other levels",
+			actual: ProtectedEnvironmentInfo{
+				Name:               "production",
+				DeployAccessLevels: []EnvironmentAccessLevel{EnvironmentAccessLevelMaintainer},
+			},
+			want: false,
+		},
+		{
+			name: "other environment",
+			actual: ProtectedEnvironmentInfo{
+				Name:               "staging",
+				DeployAccessLevels: desired.DeployAccessLevels,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := desired.Equals(tt.actual); got != tt.want {
+				t.Errorf("ProtectedEnvironmentInfo.Equals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepositoryHook_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		hook         RepositoryHookInfo
+		expectedErrs []error
+	}{
+		{
+			name: "valid",
+			hook: RepositoryHookInfo{
+				URL:         "https://ci.example.com/hook",
+				ContentType: RepositoryHookContentTypeVar(RepositoryHookContentTypeForm),
+				Events:      []RepositoryHookEvent{RepositoryHookEventPush, RepositoryHookEventRelease},
+			},
+		},
+		{
+			name:         "invalid, missing URL",
+			hook:         RepositoryHookInfo{Secret: StringVar("s3cr3t")},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name: "invalid, unknown content type and event",
+			hook: RepositoryHookInfo{
+				URL:         "https://ci.example.com/hook",
+				ContentType: RepositoryHookContentTypeVar("xml"),
+				Events:      []RepositoryHookEvent{"deployment"},
+			},
+			expectedErrs: []error{validation.ErrFieldEnumInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "RepositoryHook", tt.hook.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestRepositoryHook_Equals(t *testing.T) {
+	desired := RepositoryHookInfo{
+		URL:    "https://ci.example.com/hook",
+		Secret: StringVar("s3cr3t"),
+		Active: BoolVar(true),
+		Events: []RepositoryHookEvent{RepositoryHookEventPush, RepositoryHookEventPullRequest},
+	}
+	tests := []struct {
+		name   string
+		actual RepositoryHookInfo
+		want   bool
+	}{
+		{
+			name: "secret isn't read, events in another order",
+			actual: RepositoryHookInfo{
+				URL:    "https://ci.example.com/hook",
+				Active: BoolVar(true),
+				Events: []RepositoryHookEvent{RepositoryHookEventPullRequest, RepositoryHookEventPush},
+			},
+			want: true,
+		},
+		{
+			name: "other events",
+			actual: RepositoryHookInfo{
+				URL:    "https://ci.example.com/hook",
+				Active: BoolVar(true),
+				Events: []RepositoryHookEvent{RepositoryHookEventPush},
+			},
+			want: false,
+		},
+		{
+			name: "inactive",
+			actual: RepositoryHookInfo{
+				URL:    "https://ci.example.com/hook",
+				Active: BoolVar(false),
+				Events: desired.Events,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := desired.Equals(tt.actual); got != tt.want {
+				t.Errorf("RepositoryHookInfo.Equals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTeamAccess_Validate(t *testing.T) {
 	invalidPermission := RepositoryPermission("unknown")
 	tests := []struct {
@@ -154,3 +598,63 @@ func TestTeamAccess_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestActionsSecret_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		secret       ActionsSecretInfo
+		expectedErrs []error
+	}{
+		{
+			name: "valid create",
+			secret: ActionsSecretInfo{
+				Name:  "FOO_TOKEN",
+				Value: "s3cr3t",
+			},
+		},
+		{
+			name: "valid create, with selected repositories",
+			secret: ActionsSecretInfo{
+				Name:                 "FOO_TOKEN",
+				Value:                "s3cr3t",
+				Visibility:           ActionsSecretVisibilityVar(ActionsSecretVisibilitySelected),
+				SelectedRepositories: []string{"foo", "bar"},
+			},
+		},
+		{
+			name: "invalid create, missing name",
+			secret: ActionsSecretInfo{
+				Value: "s3cr3t",
+			},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name: "invalid create, invalid visibility",
+			secret: ActionsSecretInfo{
+				Name:       "FOO_TOKEN",
+				Visibility: ActionsSecretVisibilityVar("internal"),
+			},
+			expectedErrs: []error{validation.ErrFieldEnumInvalid},
+		},
+		{
+			name: "invalid create, selected repositories without selected visibility",
+			secret: ActionsSecretInfo{
+				Name:                 "FOO_TOKEN",
+				Visibility:           ActionsSecretVisibilityVar(ActionsSecretVisibilityAll),
+				SelectedRepositories: []string{"foo"},
+			},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "ActionsSecret", tt.secret.ValidateInfo, tt.expectedErrs)
+			// The secret value must never be printed
+			for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+				if tt.secret.Value != "" && strings.Contains(fmt.Sprintf(format, tt.secret), tt.secret.Value) {
+					t.Errorf("ActionsSecretInfo formatted with %s contains the secret value", format)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"net/http"
+)
+
+// userAgentHeader is the HTTP header the user agent is sent in.
+const userAgentHeader = "User-Agent"
+
+// UserAgentTransport returns a ChainableRoundTripperFunc setting the User-Agent header of every
+// outgoing request to userAgent, e.g. so that the administrators of a self-hosted Git provider can
+// identify the client's traffic in their logs. The user agent set by the provider's Go client is
+// replaced.
+func UserAgentTransport(userAgent string) ChainableRoundTripperFunc {
+	return func(in http.RoundTripper) http.RoundTripper {
+		if in == nil {
+			in = http.DefaultTransport
+		}
+		return &userAgentRoundTripper{transport: in, userAgent: userAgent}
+	}
+}
+
+// userAgentRoundTripper is the RoundTripper returned by UserAgentTransport.
+type userAgentRoundTripper struct {
+	transport http.RoundTripper
+	userAgent string
+}
+
+// RoundTrip sends the request with the User-Agent header set.
+func (r *userAgentRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the given request, hence set the header on a clone
+	req = req.Clone(req.Context())
+	req.Header.Set(userAgentHeader, r.userAgent)
+	return r.transport.RoundTrip(req)
+}
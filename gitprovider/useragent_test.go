@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestUserAgentTransport(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+	}{
+		{
+			name:   "no user agent set",
+			header: http.Header{},
+		},
+		{
+			name:   "replaces the user agent of the Go client",
+			header: http.Header{"User-Agent": []string{"go-github"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &headerRecorder{}
+			req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header = tt.header.Clone()
+			if _, err := UserAgentTransport("flux/1.0")(recorder).RoundTrip(req); err != nil {
+				t.Fatal(err)
+			}
+			if got := recorder.header.Get("User-Agent"); got != "flux/1.0" {
+				t.Errorf("UserAgentTransport() header = %q, want %q", got, "flux/1.0")
+			}
+			if got, want := req.Header.Get("User-Agent"), tt.header.Get("User-Agent"); got != want {
+				t.Errorf("UserAgentTransport() modified the given request")
+			}
+		})
+	}
+}
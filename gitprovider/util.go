@@ -16,6 +16,16 @@ limitations under the License.
 
 package gitprovider
 
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+// maxTopicLength is the maximum length of a repository topic in GitHub.
+const maxTopicLength = 50
+
 // BoolVar returns a pointer to the given bool.
 func BoolVar(b bool) *bool {
 	return &b
@@ -25,3 +35,62 @@ func BoolVar(b bool) *bool {
 func StringVar(s string) *string {
 	return &s
 }
+
+// IntVar returns a pointer to the given int.
+func IntVar(i int) *int {
+	return &i
+}
+
+// ValidateTopic validates that topic is a valid repository topic according to the rules of GitHub,
+// i.e. that it consists of at most 50 letters, numbers and hyphens, and doesn't start with a hyphen.
+// Upper case letters are allowed, as topics are lowercased before being sent, see NormalizeTopics().
+// Use as errs.Append(ValidateTopic(topic), topic, "FieldName").
+func ValidateTopic(topic string) error {
+	topic = strings.ToLower(topic)
+	if topic == "" || len(topic) > maxTopicLength || strings.HasPrefix(topic, "-") {
+		return validation.ErrFieldInvalid
+	}
+	for _, r := range topic {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return validation.ErrFieldInvalid
+		}
+	}
+	return nil
+}
+
+// ValidateMaxLength validates that s is at most max characters (not bytes) long, e.g. for the limits
+// a provider puts on free-form text. Use as errs.Append(ValidateMaxLength(s, max), s, "FieldName").
+func ValidateMaxLength(s string, max int) error {
+	if utf8.RuneCountInString(s) > max {
+		return validation.ErrFieldInvalid
+	}
+	return nil
+}
+
+// ValidateBranchName validates that name is a valid branch name according to the rules of
+// "git check-ref-format --branch". Use as errs.Append(ValidateBranchName(name), name, "FieldName").
+func ValidateBranchName(name string) error {
+	if name == "" || name == "@" || strings.HasPrefix(name, "-") ||
+		strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") || strings.HasSuffix(name, ".") ||
+		strings.Contains(name, "..") || strings.Contains(name, "//") || strings.Contains(name, "@{") ||
+		strings.ContainsAny(name, " ~^:?*[\\") {
+		return validation.ErrFieldInvalid
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			return validation.ErrFieldInvalid
+		}
+	}
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") || strings.HasSuffix(component, ".lock") {
+			return validation.ErrFieldInvalid
+		}
+	}
+	return nil
+}
+
+// ValidateTagName validates that name is a valid tag name. Git applies the same ref naming rules to
+// tags as to branches, see ValidateBranchName. Use as errs.Append(ValidateTagName(name), name, "FieldName").
+func ValidateTagName(name string) error {
+	return ValidateBranchName(name)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dinosk/go-git-providers/validation"
+)
+
+func TestValidateBranchName(t *testing.T) {
+	tests := []struct {
+		name         string
+		branch       string
+		expectedErrs []error
+	}{
+		{name: "simple", branch: "main"},
+		{name: "hierarchical", branch: "release/v1.0"},
+		{name: "empty", branch: "", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "at sign", branch: "@", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "leading dash", branch: "-main", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "double dot", branch: "main..dev", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "trailing slash", branch: "main/", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "double slash", branch: "release//v1", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "trailing dot", branch: "main.", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "component starting with dot", branch: "release/.v1", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "lock suffix", branch: "main.lock", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "reflog syntax", branch: "main@{1}", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "space", branch: "my branch", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "special character", branch: "main:dev", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "backslash", branch: "main\\dev", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "control character", branch: "main\tdev", expectedErrs: []error{validation.ErrFieldInvalid}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validation.TestExpectErrors(t, "ValidateBranchName", ValidateBranchName(tt.branch), tt.expectedErrs...)
+		})
+	}
+}
+
+func TestValidateTopic(t *testing.T) {
+	tests := []struct {
+		name         string
+		topic        string
+		expectedErrs []error
+	}{
+		{name: "simple", topic: "gitops"},
+		{name: "hyphens and numbers", topic: "k8s-operator"},
+		{name: "upper case is lowercased", topic: "Flux"},
+		{name: "empty", topic: "", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "leading hyphen", topic: "-flux", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "underscore", topic: "flux_cd", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "space", topic: "flux cd", expectedErrs: []error{validation.ErrFieldInvalid}},
+		{name: "too long", topic: strings.Repeat("a", 51), expectedErrs: []error{validation.ErrFieldInvalid}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validation.TestExpectErrors(t, "ValidateTopic", ValidateTopic(tt.topic), tt.expectedErrs...)
+		})
+	}
+}
@@ -1,14 +1,36 @@
 module github.com/dinosk/go-git-providers
 
-go 1.14
+go 1.18
 
 require (
+	code.gitea.io/sdk/gitea v0.18.0
 	github.com/google/go-cmp v0.4.0
 	github.com/google/go-github/v32 v32.1.0
 	github.com/gregjones/httpcache v0.0.0-20190611155906-901d90724c79
-	github.com/ktrysmt/go-bitbucket v0.6.2
+	github.com/hashicorp/go-retryablehttp v0.6.4
 	github.com/onsi/ginkgo v1.14.0
 	github.com/onsi/gomega v1.10.1
 	github.com/xanzy/go-gitlab v0.33.0
+	golang.org/x/crypto v0.22.0
 	golang.org/x/oauth2 v0.0.0-20181106182150-f42d05182288
+	gopkg.in/yaml.v2 v2.3.0
+)
+
+require (
+	github.com/davidmz/go-pageant v1.0.2 // indirect
+	github.com/fsnotify/fsnotify v1.4.9 // indirect
+	github.com/go-fed/httpsig v1.1.0 // indirect
+	github.com/golang/protobuf v1.4.2 // indirect
+	github.com/google/go-querystring v1.0.0 // indirect
+	github.com/hashicorp/go-cleanhttp v0.5.1 // indirect
+	github.com/hashicorp/go-version v1.6.0 // indirect
+	github.com/nxadm/tail v1.4.4 // indirect
+	golang.org/x/net v0.21.0 // indirect
+	golang.org/x/sys v0.19.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	golang.org/x/time v0.0.0-20191024005414-555d28b269f0 // indirect
+	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
+	google.golang.org/appengine v1.3.0 // indirect
+	google.golang.org/protobuf v1.23.0 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
 )